@@ -25,12 +25,17 @@ type answerData struct {
 // SetRoutes defines the routes for this package in a route answers.
 func (srv *Service) SetRoutes(router chi.Router) {
 	router.Use(render.SetContentType(render.ContentTypeJSON))
-	router.Post("/answers", service.AppHandler(srv.submit).ServeHTTP)
+	router.With(srv.TaskTokenRateLimitMiddleware("answers")).
+		Post("/answers", service.AppHandler(srv.submit).ServeHTTP)
 
 	routerWithAuth := router.With(auth.UserMiddleware(srv.Base))
 	routerWithAuth.Get("/items/{item_id}/answers", service.AppHandler(srv.listAnswers).ServeHTTP)
 	routerWithAuth.Get("/items/{item_id}/best-answer", service.AppHandler(srv.getBestAnswer).ServeHTTP)
+	routerWithAuth.Get("/items/{item_id}/ungraded-answers", service.AppHandler(srv.listUngradedAnswers).ServeHTTP)
+	routerWithAuth.Get("/items/{item_id}/similarity", service.AppHandler(srv.listSimilarAnswers).ServeHTTP)
+	routerWithAuth.Get("/items/{item_id}/attempts/{attempt_id}/answers/timeline", service.AppHandler(srv.getAnswersTimeline).ServeHTTP)
 	routerWithAuth.Get("/answers/{answer_id}", service.AppHandler(srv.getAnswer).ServeHTTP)
+	routerWithAuth.Post("/answers/{answer_id}/grade", service.AppHandler(srv.gradeAnswer).ServeHTTP)
 	routerWithAuth.Post("/answers/{answer_id}/generate-task-token", service.AppHandler(srv.generateTaskToken).ServeHTTP)
 
 	routerWithParticipant := routerWithAuth.With(service.ParticipantMiddleware(srv.Base))
@@ -84,6 +84,7 @@ Feature: Create a 'saved' answer - robustness
           "answer": ["missing field"]
         },
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "success": false
       }
       """
@@ -106,6 +107,7 @@ Feature: Create a 'saved' answer - robustness
           "state": ["missing field"]
         },
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "success": false
       }
       """
@@ -30,6 +30,36 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item1",
       "author_id": "@Author",
+      "feedback": null,
+      "graded_at": "2017-05-29T06:38:40Z"
+    }
+    """
+
+  Scenario: The participant reads the feedback of a manual grading
+    Given I am @User
+    And I can view content of the item @Item1
+    And the database has the following table "answers":
+      | id  | author_id | participant_id | attempt_id | item_id | type       | state  | answer   | created_at          |
+      | 104 | @Author   | @User          | 2          | @Item1  | Submission | State1 | print(3) | 2017-05-29 06:38:39 |
+    And the database has the following table "gradings":
+      | answer_id | score | graded_at           | feedback          |
+      | 104       | 60    | 2017-05-29 06:38:40 | Almost, try again |
+    When I send a GET request to "/answers/104"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "id": "104",
+      "attempt_id": "2",
+      "participant_id": "@User",
+      "score": 60,
+      "answer": "print(3)",
+      "state": "State1",
+      "created_at": "2017-05-29T06:38:39Z",
+      "type": "Submission",
+      "item_id": "@Item1",
+      "author_id": "@Author",
+      "feedback": "Almost, try again",
       "graded_at": "2017-05-29T06:38:40Z"
     }
     """
@@ -56,6 +86,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item1",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -83,6 +114,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -112,6 +144,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -142,6 +175,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -168,6 +202,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -198,6 +233,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -225,6 +261,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -253,6 +290,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -284,6 +322,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -311,6 +350,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -339,6 +379,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -367,6 +408,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -401,6 +443,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
@@ -438,6 +481,7 @@ Feature: Get an answer by id
       "type": "Submission",
       "item_id": "@Item2",
       "author_id": "@Author",
+      "feedback": null,
       "graded_at": null
     }
     """
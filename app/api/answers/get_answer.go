@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-chi/render"
 
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 )
 
@@ -32,6 +33,8 @@ import (
 //				  while the thread should be active or closed less than 2 weeks ago.
 //
 //			If any of the preconditions fails, the 'forbidden' error is returned.
+//
+//			The `feedback` field contains the comment of the user who graded the answer manually (if any).
 //		parameters:
 //			- name: answer_id
 //				in: path
@@ -62,6 +65,21 @@ func (srv *Service) getAnswer(rw http.ResponseWriter, httpReq *http.Request) ser
 
 	store := srv.GetStore(httpReq)
 
+	err = whereUserCanViewAnswers(store, store.Answers().WithGradingsAndFeedbacks().ByID(answerID), user).
+		ScanIntoSliceOfMaps(&result).Error()
+	service.MustNotBeError(err)
+	if len(result) == 0 {
+		return service.InsufficientAccessRightsError
+	}
+	convertedResult := service.ConvertSliceOfMapsFromDBToJSON(result)[0]
+
+	render.Respond(rw, httpReq, convertedResult)
+	return service.NoError
+}
+
+// whereUserCanViewAnswers filters the given query on `answers` (or on another table aliased as `answers`
+// having `participant_id` & `item_id` columns) keeping only rows the user can view as described in getAnswer.
+func whereUserCanViewAnswers(store *database.DataStore, query *database.DB, user *database.User) *database.DB {
 	userAndHisTeamsQuery := store.Raw("SELECT id FROM ? `teams` UNION ALL SELECT ?",
 		store.ActiveGroupGroups().
 			WhereUserIsMember(user).
@@ -124,9 +142,7 @@ func (srv *Service) getAnswer(rw http.ResponseWriter, httpReq *http.Request) ser
 			user.GroupID).
 		Select("1").Limit(1).SubQuery()
 
-	err = store.Answers().
-		WithGradings().
-		ByID(answerID).
+	return query.
 		With("user_and_his_teams", userAndHisTeamsQuery).
 		// 1) the user is the participant or a member of the participant team able to view the item,
 		// 2) or an observer with required permissions
@@ -157,15 +173,5 @@ func (srv *Service) getAnswer(rw http.ResponseWriter, httpReq *http.Request) ser
 			/*   AND */
 			/*   ( */
 			userIsAManagerThatCanWatchMembersSubQuery /* OR */, userIsAHelperAndTheThreadHasNotBeenExpiredSubQuery,
-			/* ) */).
-		ScanIntoSliceOfMaps(&result).Error()
-
-	service.MustNotBeError(err)
-	if len(result) == 0 {
-		return service.InsufficientAccessRightsError
-	}
-	convertedResult := service.ConvertSliceOfMapsFromDBToJSON(result)[0]
-
-	render.Respond(rw, httpReq, convertedResult)
-	return service.NoError
+			/* ) */)
 }
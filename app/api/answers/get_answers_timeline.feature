@@ -0,0 +1,123 @@
+Feature: Get the timeline of answers
+  Background:
+    Given the database has the following users:
+      | group_id | login |
+      | 11       | jdoe  |
+      | 16       | jeff  |
+    And the database has the following table "groups":
+      | id | name  | type  |
+      | 13 | Class | Class |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 11             |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id  | type | default_language_tag |
+      | 200 | Task | fr                   |
+    And the database has the following table "permissions_generated":
+      | item_id | group_id | can_view_generated | can_watch_generated |
+      | 200     | 11       | content            | none                |
+      | 200     | 16       | content            | answer              |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 16         | true              |
+    And the database has the following table "attempts":
+      | id | participant_id |
+      | 1  | 11             |
+      | 2  | 11             |
+    And the database has the following table "results":
+      | attempt_id | participant_id | item_id | hints_requested      | latest_hint_at      |
+      | 1          | 11             | 200     | [0,{"rotorIndex":1}] | 2017-05-29 07:30:00 |
+      | 2          | 11             | 200     | null                 | null                |
+    And the database has the following table "answers":
+      | id  | author_id | participant_id | attempt_id | item_id | type       | state    | answer    | created_at          |
+      | 101 | 11        | 11             | 1          | 200     | Saved      | State101 | a\nb      | 2017-05-29 06:00:00 |
+      | 102 | 11        | 11             | 1          | 200     | Submission | State102 | a\nc\nd\n | 2017-05-29 07:00:00 |
+      | 103 | 11        | 11             | 1          | 200     | Current    | State103 | a\nc      | 2017-05-29 08:30:00 |
+      | 104 | 11        | 11             | 1          | 200     | Saved      | State104 | a\nc\nd   | 2017-05-29 09:00:00 |
+      | 105 | 11        | 11             | 2          | 200     | Submission | State105 | b         | 2017-05-29 06:30:00 |
+    And the database has the following table "gradings":
+      | answer_id | score | graded_at           | feedback  |
+      | 102       | 50    | 2017-05-29 08:00:00 | Try again |
+      | 105       | 100   | 2017-05-29 06:30:01 | null      |
+
+  Scenario Outline: Get the timeline of answers of an attempt
+    Given I am the user with id "<user_id>"
+    When I send a GET request to "/items/200/attempts/1/answers/timeline<query>"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "type": "answer",
+        "at": "2017-05-29T06:00:00Z",
+        "answer_id": "101",
+        "answer_type": "Saved",
+        "author_id": "11",
+        "changes": [
+          {"old_start": 1, "old_lines": 0, "new_start": 1, "new_lines": 2, "removed": [], "added": ["a", "b"]}
+        ]
+      },
+      {
+        "type": "answer",
+        "at": "2017-05-29T07:00:00Z",
+        "answer_id": "102",
+        "answer_type": "Submission",
+        "author_id": "11",
+        "changes": [
+          {"old_start": 2, "old_lines": 1, "new_start": 2, "new_lines": 2, "removed": ["b"], "added": ["c", "d"]}
+        ]
+      },
+      {
+        "type": "hints_requested",
+        "at": "2017-05-29T07:30:00Z",
+        "hints": [0, {"rotorIndex": 1}]
+      },
+      {
+        "type": "grading",
+        "at": "2017-05-29T08:00:00Z",
+        "answer_id": "102",
+        "score": 50,
+        "feedback": "Try again"
+      },
+      {
+        "type": "answer",
+        "at": "2017-05-29T09:00:00Z",
+        "answer_id": "104",
+        "answer_type": "Saved",
+        "author_id": "11",
+        "changes": []
+      }
+    ]
+    """
+  Examples:
+    | user_id | query              |
+    | 11      |                    |
+    | 11      | ?participant_id=11 |
+    | 16      | ?participant_id=11 |
+
+  Scenario: Get the timeline of another attempt
+    Given I am the user with id "11"
+    When I send a GET request to "/items/200/attempts/2/answers/timeline"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "type": "answer",
+        "at": "2017-05-29T06:30:00Z",
+        "answer_id": "105",
+        "answer_type": "Submission",
+        "author_id": "11",
+        "changes": [
+          {"old_start": 1, "old_lines": 0, "new_start": 1, "new_lines": 1, "removed": [], "added": ["b"]}
+        ]
+      },
+      {
+        "type": "grading",
+        "at": "2017-05-29T06:30:01Z",
+        "answer_id": "105",
+        "score": 100
+      }
+    ]
+    """
@@ -0,0 +1,266 @@
+package answers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/jinzhu/gorm"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+const (
+	answersTimelineEventTypeAnswer         = "answer"
+	answersTimelineEventTypeGrading        = "grading"
+	answersTimelineEventTypeHintsRequested = "hints_requested"
+)
+
+// A change of consecutive lines between two versions of an answer.
+type answerLinesChange struct {
+	// The number of the first changed line in the previous version (starting from 1)
+	// required: true
+	OldStart int `json:"old_start"`
+	// The number of lines of the previous version replaced by the change
+	// required: true
+	OldLines int `json:"old_lines"`
+	// The number of the first changed line in the new version (starting from 1)
+	// required: true
+	NewStart int `json:"new_start"`
+	// The number of lines of the new version inserted by the change
+	// required: true
+	NewLines int `json:"new_lines"`
+	// required: true
+	Removed []string `json:"removed"`
+	// required: true
+	Added []string `json:"added"`
+}
+
+// swagger:model answersTimelineEvent
+type answersTimelineEvent struct {
+	// required: true
+	// enum: answer,grading,hints_requested
+	Type string `json:"type"`
+	// `answers.created_at` for answers, `gradings.graded_at` for gradings, `results.latest_hint_at` for hint requests
+	// required: true
+	At database.Time `json:"at"`
+	// Only for answers and gradings
+	AnswerID *int64 `json:"answer_id,string,omitempty"`
+	// Only for answers
+	// enum: Saved,Submission
+	AnswerType *string `json:"answer_type,omitempty"`
+	// Only for answers
+	AuthorID *int64 `json:"author_id,string,omitempty"`
+	// Line-level changes compared to the previous answer of the timeline (to an empty answer for the first one),
+	// only for answers
+	Changes *[]answerLinesChange `json:"changes,omitempty"`
+	// Only for gradings
+	Score *float64 `json:"score,omitempty"`
+	// The comment of the user who graded the answer manually (only for gradings)
+	Feedback *string `json:"feedback,omitempty"`
+	// All the hints requested in the attempt (`results.hints_requested`), only for hint requests
+	Hints []json.RawMessage `json:"hints,omitempty"`
+
+	timelineOrder int
+}
+
+// swagger:operation GET /items/{item_id}/attempts/{attempt_id}/answers/timeline answers answersTimelineGet
+//
+//	---
+//	summary: Get the timeline of answers
+//	description: >
+//		Returns the history of saved and submitted answers (`answers.type` = 'Saved' or 'Submission')
+//		of the participant for the item within the attempt, the oldest first, with line-level changes
+//		between consecutive answers, interleaved with gradings of the submissions and hint requests.
+//
+//
+//		As only the time of the latest hint request is stored, all the requested hints
+//		(`results.hints_requested`) are returned as one 'hints_requested' event at `results.latest_hint_at`.
+//
+//
+//		The current user should be able to view answers of the participant for the item
+//		(see the restrictions of `GET /answers/{answer_id}`), otherwise the 'forbidden' error is returned.
+//		The result of the participant for the item within the attempt should exist, otherwise
+//		the 'forbidden' error is returned as well.
+//	parameters:
+//		- name: item_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: attempt_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: participant_id
+//			description: The participant (a user or a team), the current user's self group by default
+//			in: query
+//			type: integer
+//			format: int64
+//	responses:
+//		"200":
+//			description: OK. Success response with the timeline events
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/answersTimelineEvent"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getAnswersTimeline(rw http.ResponseWriter, httpReq *http.Request) service.APIError {
+	itemID, err := service.ResolveURLQueryPathInt64Field(httpReq, "item_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	attemptID, err := service.ResolveURLQueryPathInt64Field(httpReq, "attempt_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	user := srv.GetUser(httpReq)
+	participantID := user.GroupID
+	if len(httpReq.URL.Query()["participant_id"]) > 0 {
+		participantID, err = service.ResolveURLQueryGetInt64Field(httpReq, "participant_id")
+		if err != nil {
+			return service.ErrInvalidRequest(err)
+		}
+	}
+
+	store := srv.GetStore(httpReq)
+
+	var result struct {
+		HintsRequested *string
+		LatestHintAt   *database.Time
+	}
+	// the result is aliased as `answers` as it has the `participant_id` & `item_id` columns checked for answers
+	err = whereUserCanViewAnswers(store,
+		store.Table("results AS answers").
+			Where("answers.participant_id = ? AND answers.attempt_id = ? AND answers.item_id = ?", participantID, attemptID, itemID),
+		user).
+		Select("answers.hints_requested, answers.latest_hint_at").
+		Take(&result).Error()
+	if gorm.IsRecordNotFoundError(err) {
+		return service.InsufficientAccessRightsError
+	}
+	service.MustNotBeError(err)
+
+	var answers []struct {
+		ID        int64
+		AuthorID  int64
+		Type      string
+		Answer    *string
+		CreatedAt database.Time
+		Score     *float64
+		GradedAt  *database.Time
+		Feedback  *string
+	}
+	service.MustNotBeError(store.Answers().
+		Joins("LEFT JOIN gradings ON gradings.answer_id = answers.id").
+		Where("answers.participant_id = ? AND answers.attempt_id = ? AND answers.item_id = ?", participantID, attemptID, itemID).
+		Where("answers.type IN ('Saved', 'Submission')").
+		Order("answers.created_at, answers.id").
+		Select(`
+			answers.id, answers.author_id, answers.type, answers.answer, answers.created_at,
+			gradings.score, gradings.graded_at, gradings.feedback`).
+		Scan(&answers).Error())
+
+	events := make([]answersTimelineEvent, 0, len(answers))
+	var previousAnswer string
+	for index := range answers {
+		answer := &answers[index]
+		var answerText string
+		if answer.Answer != nil {
+			answerText = *answer.Answer
+		}
+		changes := diffAnswerLines(previousAnswer, answerText)
+		events = append(events, answersTimelineEvent{
+			Type:          answersTimelineEventTypeAnswer,
+			At:            answer.CreatedAt,
+			AnswerID:      &answer.ID,
+			AnswerType:    &answer.Type,
+			AuthorID:      &answer.AuthorID,
+			Changes:       &changes,
+			timelineOrder: len(events),
+		})
+		previousAnswer = answerText
+
+		if answer.GradedAt != nil {
+			events = append(events, answersTimelineEvent{
+				Type:          answersTimelineEventTypeGrading,
+				At:            *answer.GradedAt,
+				AnswerID:      &answer.ID,
+				Score:         answer.Score,
+				Feedback:      answer.Feedback,
+				timelineOrder: len(events),
+			})
+		}
+	}
+
+	if result.LatestHintAt != nil && result.HintsRequested != nil {
+		var hints []json.RawMessage
+		if err = json.Unmarshal([]byte(*result.HintsRequested), &hints); err != nil {
+			logging.GetLogEntry(httpReq).Warnf("Unable to parse hints_requested having value %q: %s", *result.HintsRequested, err.Error())
+		} else if len(hints) > 0 {
+			events = append(events, answersTimelineEvent{
+				Type:          answersTimelineEventTypeHintsRequested,
+				At:            *result.LatestHintAt,
+				Hints:         hints,
+				timelineOrder: len(events),
+			})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !time.Time(events[i].At).Equal(time.Time(events[j].At)) {
+			return time.Time(events[i].At).Before(time.Time(events[j].At))
+		}
+		return events[i].timelineOrder < events[j].timelineOrder
+	})
+
+	render.Respond(rw, httpReq, events)
+	return service.NoError
+}
+
+// diffAnswerLines returns the line-level changes transforming the old text into the new one.
+func diffAnswerLines(oldText, newText string) []answerLinesChange {
+	oldLines := splitAnswerLines(oldText)
+	newLines := splitAnswerLines(newText)
+
+	changes := make([]answerLinesChange, 0)
+	for _, opCode := range difflib.NewMatcher(oldLines, newLines).GetOpCodes() {
+		if opCode.Tag == 'e' {
+			continue
+		}
+		changes = append(changes, answerLinesChange{
+			OldStart: opCode.I1 + 1,
+			OldLines: opCode.I2 - opCode.I1,
+			NewStart: opCode.J1 + 1,
+			NewLines: opCode.J2 - opCode.J1,
+			Removed:  append(make([]string, 0, opCode.I2-opCode.I1), oldLines[opCode.I1:opCode.I2]...),
+			Added:    append(make([]string, 0, opCode.J2-opCode.J1), newLines[opCode.J1:opCode.J2]...),
+		})
+	}
+	return changes
+}
+
+func splitAnswerLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+}
@@ -0,0 +1,75 @@
+Feature: Get the timeline of answers - robustness
+  Background:
+    Given the database has the following users:
+      | group_id | login |
+      | 11       | jdoe  |
+      | 14       | jane  |
+      | 15       | bill  |
+    And the database has the following table "groups":
+      | id | name  | type  |
+      | 13 | Class | Class |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 11             |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id  | default_language_tag |
+      | 200 | fr                   |
+    And the database has the following table "permissions_generated":
+      | item_id | group_id | can_view_generated | can_watch_generated |
+      | 200     | 11       | content            | none                |
+      | 200     | 14       | info               | none                |
+      | 200     | 15       | content            | result              |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 15         | true              |
+    And the database has the following table "attempts":
+      | id | participant_id |
+      | 1  | 11             |
+      | 1  | 14             |
+    And the database has the following table "results":
+      | attempt_id | participant_id | item_id |
+      | 1          | 11             | 200     |
+      | 1          | 14             | 200     |
+
+  Scenario: Wrong item_id
+    Given I am the user with id "11"
+    When I send a GET request to "/items/abc/attempts/1/answers/timeline"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for item_id (should be int64)"
+
+  Scenario: Wrong attempt_id
+    Given I am the user with id "11"
+    When I send a GET request to "/items/200/attempts/abc/answers/timeline"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for attempt_id (should be int64)"
+
+  Scenario: Wrong participant_id
+    Given I am the user with id "11"
+    When I send a GET request to "/items/200/attempts/1/answers/timeline?participant_id=abc"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for participant_id (should be int64)"
+
+  Scenario: The user cannot view the content of the item
+    Given I am the user with id "14"
+    When I send a GET request to "/items/200/attempts/1/answers/timeline"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The result does not exist
+    Given I am the user with id "11"
+    When I send a GET request to "/items/200/attempts/2/answers/timeline"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The user cannot view answers of another participant
+    Given I am the user with id "11"
+    When I send a GET request to "/items/200/attempts/1/answers/timeline?participant_id=14"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The user has can_watch < answer on the item and no validated result
+    Given I am the user with id "15"
+    When I send a GET request to "/items/200/attempts/1/answers/timeline?participant_id=11"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
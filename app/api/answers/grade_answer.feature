@@ -0,0 +1,153 @@
+Feature: Grade an answer manually
+  Background:
+    Given the database has the following users:
+      | group_id | login   | first_name | last_name |
+      | 11       | jdoe    | John       | Doe       |
+      | 21       | trainer | Train      | Er        |
+    And the database has the following table "groups":
+      | id | name    | type  |
+      | 13 | Class A | Class |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 11             |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 21         | true              |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | type    | validation_type | default_language_tag |
+      | 10 | Chapter | All             | fr                   |
+      | 50 | Task    | Manual          | fr                   |
+    And the database has the following table "items_items":
+      | parent_item_id | child_item_id | child_order |
+      | 10             | 50            | 0           |
+    And the database has the following table "items_ancestors":
+      | ancestor_item_id | child_item_id |
+      | 10               | 50            |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_watch_generated |
+      | 11       | 10      | content            | none                |
+      | 11       | 50      | content            | none                |
+      | 21       | 50      | content            | answer              |
+    And the database has the following table "attempts":
+      | id | participant_id |
+      | 0  | 11             |
+    And the database has the following table "results":
+      | attempt_id | participant_id | item_id | latest_activity_at  |
+      | 0          | 11             | 10      | 2019-05-30 11:00:00 |
+      | 0          | 11             | 50      | 2019-05-30 11:00:00 |
+    And the database has the following table "answers":
+      | id  | author_id | participant_id | attempt_id | item_id | type       | created_at          |
+      | 123 | 11        | 11             | 0          | 50      | Submission | 2017-05-29 06:38:38 |
+      | 124 | 11        | 11             | 0          | 50      | Submission | 2017-05-29 06:39:38 |
+    And the server time is frozen
+
+  Scenario: Grade an ungraded submission with a full score
+    Given I am the user with id "21"
+    When I send a POST request to "/answers/123/grade" with the following body:
+      """
+      {
+        "score": 100,
+        "feedback": "Well done!"
+      }
+      """
+    Then the response should be "updated"
+    And the table "answers" should stay unchanged
+    And the table "gradings" should be:
+      | answer_id | score | grader_id | feedback   | ABS(TIMESTAMPDIFF(SECOND, graded_at, NOW())) < 3 |
+      | 123       | 100   | 21        | Well done! | 1                                                |
+    And the table "results" should be:
+      | attempt_id | participant_id | item_id | score_computed | tasks_tried | validated | score_obtained_at   | validated_at        |
+      | 0          | 11             | 10      | 100            | 1           | 1         | null                | 2017-05-29 06:38:38 |
+      | 0          | 11             | 50      | 100            | 1           | 1         | 2017-05-29 06:38:38 | 2017-05-29 06:38:38 |
+    And the table "results_propagate" should be empty
+    And the table "notifications" should be:
+      | user_id | type          | CAST(payload AS CHAR)                                         | deduplication_key | read_at | ABS(TIMESTAMPDIFF(SECOND, created_at, NOW())) < 3 |
+      | 11      | answer_graded | {"item_id": "50", "answer_id": "123", "participant_id": "11"} | answer_graded:123 | null    | 1                                                 |
+
+  Scenario: Regrade a submission without feedback
+    Given I am the user with id "21"
+    And the database has the following table "gradings":
+      | answer_id | score | graded_at           | grader_id | feedback  |
+      | 124       | 40    | 2017-05-29 07:00:00 | 21        | Try again |
+    And the database has the following table "notifications":
+      | id | user_id | type          | payload                                                       | deduplication_key | created_at          | read_at             |
+      | 1  | 11      | answer_graded | {"item_id": "50", "answer_id": "124", "participant_id": "11"} | answer_graded:124 | 2017-05-29 07:00:00 | 2017-05-29 08:00:00 |
+    When I send a POST request to "/answers/124/grade" with the following body:
+      """
+      {
+        "score": 60
+      }
+      """
+    Then the response should be "updated"
+    And the table "gradings" should be:
+      | answer_id | score | grader_id | feedback | ABS(TIMESTAMPDIFF(SECOND, graded_at, NOW())) < 3 |
+      | 124       | 60    | 21        | null     | 1                                                |
+    And the table "notifications" should be:
+      | id | user_id | type          | deduplication_key | read_at | ABS(TIMESTAMPDIFF(SECOND, created_at, NOW())) < 3 |
+      | 1  | 11      | answer_graded | answer_graded:124 | null    | 1                                                 |
+    And the table "results" should be:
+      | attempt_id | participant_id | item_id | score_computed | tasks_tried | validated | score_obtained_at   | validated_at |
+      | 0          | 11             | 10      | 60             | 1           | 0         | null                | null         |
+      | 0          | 11             | 50      | 60             | 1           | 0         | 2017-05-29 06:39:38 | null         |
+
+  Scenario: Regrade the best submission with a lower score (the result stays validated)
+    Given I am the user with id "21"
+    And the database has the following table "gradings":
+      | answer_id | score | graded_at           | grader_id |
+      | 123       | 50    | 2017-05-29 07:00:00 | 21        |
+    When I send a POST request to "/answers/124/grade" with the following body:
+      """
+      {
+        "score": 100
+      }
+      """
+    Then the response should be "updated"
+    And the table "results" should be:
+      | attempt_id | participant_id | item_id | score_computed | tasks_tried | validated | score_obtained_at   | validated_at        |
+      | 0          | 11             | 10      | 100            | 1           | 1         | null                | 2017-05-29 06:39:38 |
+      | 0          | 11             | 50      | 100            | 1           | 1         | 2017-05-29 06:39:38 | 2017-05-29 06:39:38 |
+    When I send a POST request to "/answers/124/grade" with the following body:
+      """
+      {
+        "score": 30
+      }
+      """
+    Then the response should be "updated"
+    And the table "gradings" should be:
+      | answer_id | score | grader_id |
+      | 123       | 50    | 21        |
+      | 124       | 30    | 21        |
+    And the table "results" should be:
+      | attempt_id | participant_id | item_id | score_computed | tasks_tried | validated | score_obtained_at   | validated_at        |
+      | 0          | 11             | 10      | 50             | 1           | 1         | null                | 2017-05-29 06:39:38 |
+      | 0          | 11             | 50      | 50             | 1           | 1         | 2017-05-29 06:38:38 | 2017-05-29 06:39:38 |
+
+  Scenario: Regrade a validated submission (the earliest validation time is kept)
+    Given I am the user with id "21"
+    And the database table "attempts" also has the following row:
+      | id | participant_id |
+      | 1  | 11             |
+    And the database table "results" also has the following rows:
+      | attempt_id | participant_id | item_id | latest_activity_at  | score_computed | tasks_tried | score_obtained_at   | validated_at        |
+      | 1          | 11             | 10      | 2019-05-30 11:00:00 | 100            | 1           | null                | 2017-05-29 06:00:00 |
+      | 1          | 11             | 50      | 2019-05-30 11:00:00 | 100            | 1           | 2017-05-29 06:00:00 | 2017-05-29 06:00:00 |
+    And the database table "answers" also has the following row:
+      | id  | author_id | participant_id | attempt_id | item_id | type       | created_at          |
+      | 125 | 11        | 11             | 1          | 50      | Submission | 2017-05-29 06:40:38 |
+    And the database has the following table "gradings":
+      | answer_id | score | graded_at           | grader_id |
+      | 125       | 90    | 2017-05-29 07:00:00 | 21        |
+    When I send a POST request to "/answers/125/grade" with the following body:
+      """
+      {
+        "score": 100
+      }
+      """
+    Then the response should be "updated"
+    And the table "results" should be:
+      | attempt_id | participant_id | item_id | score_computed | tasks_tried | validated | score_obtained_at   | validated_at        |
+      | 0          | 11             | 10      | 0              | 0           | 0         | null                | null                |
+      | 0          | 11             | 50      | 0              | 0           | 0         | null                | null                |
+      | 1          | 11             | 10      | 100            | 1           | 1         | null                | 2017-05-29 06:00:00 |
+      | 1          | 11             | 50      | 100            | 1           | 1         | 2017-05-29 06:40:38 | 2017-05-29 06:00:00 |
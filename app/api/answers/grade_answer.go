@@ -0,0 +1,148 @@
+package answers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:model
+type gradeAnswerRequest struct {
+	// required: true
+	// minimum: 0
+	// maximum: 100
+	Score float64 `json:"score" validate:"set,min=0,max=100"`
+	// A comment for the participant
+	Feedback *string `json:"feedback"`
+}
+
+// swagger:operation POST /answers/{answer_id}/grade answers answerGrade
+//
+//	---
+//	summary: Grade an answer manually
+//	description: >
+//		Lets a trainer grade a submission for an item with `validation_type` = 'Manual'.
+//		The service saves the score and the feedback into the `gradings` table (replacing the previous grading if any),
+//		recomputes the result of the participant on the item from all the gradings of the participant's answers
+//		within the attempt (so the score decreases if the best answer is re-graded with a lower score),
+//		and triggers the results propagation.
+//
+//
+//		The participant can read the feedback through `GET /answers/{answer_id}`.
+//		An in-app notification (see `GET /current-user/notifications`) is created for the participant
+//		(or for the members of the participant team) except for the current user.
+//
+//
+//		Restrictions:
+//
+//		* the answer should be a submission (`answers.type` = 'Submission');
+//
+//		* the item of the answer should have `validation_type` = 'Manual';
+//
+//		* the current user should have `can_watch` >= 'answer' on the item of the answer;
+//
+//		* the current user should be a manager with `can_watch_members` of an ancestor of the participant of the answer.
+//
+//		If any of the preconditions fails, the 'forbidden' error is returned.
+//	parameters:
+//		- name: answer_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: data
+//			in: body
+//			required: true
+//			schema:
+//				"$ref": "#/definitions/gradeAnswerRequest"
+//	responses:
+//		"200":
+//			"$ref": "#/responses/updatedResponse"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) gradeAnswer(rw http.ResponseWriter, httpReq *http.Request) service.APIError {
+	answerID, err := service.ResolveURLQueryPathInt64Field(httpReq, "answer_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	var requestData gradeAnswerRequest
+	formData := formdata.NewFormData(&requestData)
+	if err = formData.ParseJSONRequestData(httpReq); err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	user := srv.GetUser(httpReq)
+	apiError := service.NoError
+
+	err = srv.GetStore(httpReq).InTransaction(func(store *database.DataStore) error {
+		var answer struct {
+			ParticipantID  int64
+			AttemptID      int64
+			ItemID         int64
+			Type           string
+			ValidationType string
+		}
+		err = store.Answers().WithItems().
+			Where("answers.id = ?", answerID).
+			WithExclusiveWriteLock().
+			Select("answers.participant_id, answers.attempt_id, answers.item_id, answers.type, items.validation_type").
+			Take(&answer).Error()
+		if gorm.IsRecordNotFoundError(err) {
+			apiError = service.InsufficientAccessRightsError
+			return apiError.Error // rollback
+		}
+		service.MustNotBeError(err)
+
+		if !user.CanWatchItemAnswer(store, answer.ItemID) || !user.CanWatchGroupMembers(store, answer.ParticipantID) {
+			apiError = service.InsufficientAccessRightsError
+			return apiError.Error // rollback
+		}
+		if answer.Type != "Submission" {
+			apiError = service.ErrForbidden(errors.New("the answer is not a submission"))
+			return apiError.Error // rollback
+		}
+		if answer.ValidationType != "Manual" {
+			apiError = service.ErrForbidden(errors.New("the item is not graded manually"))
+			return apiError.Error // rollback
+		}
+
+		service.MustNotBeError(store.Gradings().InsertOrUpdateMap(map[string]interface{}{
+			"answer_id": answerID,
+			"score":     requestData.Score,
+			"graded_at": database.Now(),
+			"grader_id": user.GroupID,
+			"feedback":  requestData.Feedback,
+		}, []string{"score", "graded_at", "grader_id", "feedback"}))
+
+		service.MustNotBeError(store.Notifications().InsertForAnswerGrading(answerID, user.GroupID))
+
+		_, err = store.Results().RecomputeWithGradings(answer.ParticipantID, answer.AttemptID, answer.ItemID)
+		service.MustNotBeError(err)
+		store.ScheduleResultsPropagation()
+
+		return nil
+	})
+
+	if apiError != service.NoError {
+		return apiError
+	}
+	service.MustNotBeError(err)
+
+	service.MustNotBeError(render.Render(rw, httpReq, service.UpdateSuccess[*struct{}](nil)))
+	return service.NoError
+}
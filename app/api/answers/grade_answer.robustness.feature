@@ -0,0 +1,137 @@
+Feature: Grade an answer manually - robustness
+  Background:
+    Given the database has the following users:
+      | group_id | login    |
+      | 11       | jdoe     |
+      | 21       | trainer  |
+      | 22       | observer |
+    And the database has the following table "groups":
+      | id | name    | type  |
+      | 13 | Class A | Class |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 11             |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 21         | true              |
+      | 13       | 22         | false             |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | type | validation_type | default_language_tag |
+      | 50 | Task | Manual          | fr                   |
+      | 60 | Task | All             | fr                   |
+      | 70 | Task | Manual          | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_watch_generated |
+      | 21       | 50      | content            | answer              |
+      | 21       | 60      | content            | answer              |
+      | 21       | 70      | content            | result              |
+      | 22       | 50      | content            | answer              |
+    And the database has the following table "attempts":
+      | id | participant_id |
+      | 0  | 11             |
+    And the database has the following table "results":
+      | attempt_id | participant_id | item_id |
+      | 0          | 11             | 50      |
+      | 0          | 11             | 60      |
+      | 0          | 11             | 70      |
+    And the database has the following table "answers":
+      | id  | author_id | participant_id | attempt_id | item_id | type       | created_at          |
+      | 123 | 11        | 11             | 0          | 50      | Submission | 2017-05-29 06:38:38 |
+      | 124 | 11        | 11             | 0          | 50      | Saved      | 2017-05-29 06:38:38 |
+      | 125 | 11        | 11             | 0          | 60      | Submission | 2017-05-29 06:38:38 |
+      | 126 | 11        | 11             | 0          | 70      | Submission | 2017-05-29 06:38:38 |
+
+  Scenario: Invalid answer_id
+    Given I am the user with id "21"
+    When I send a POST request to "/answers/abc/grade" with the following body:
+      """
+      {"score": 100}
+      """
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for answer_id (should be int64)"
+    And the table "gradings" should be empty
+    And the table "results" should stay unchanged
+
+  Scenario Outline: Invalid score
+    Given I am the user with id "21"
+    When I send a POST request to "/answers/123/grade" with the following body:
+      """
+      <json>
+      """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "Bad Request",
+        "error_code": "invalid_input_data",
+        "error_text": "Invalid input data",
+        "errors": {
+          "score": ["<error>"]
+        }
+      }
+      """
+    And the table "gradings" should be empty
+    And the table "results" should stay unchanged
+  Examples:
+    | json             | error                                                    |
+    | {}               | missing field                                            |
+    | {"score": -1}    | score must be 0 or greater                               |
+    | {"score": 100.5} | score must be 100 or less                                |
+    | {"score": "100"} | expected type 'float64', got unconvertible type 'string' |
+
+  Scenario: The answer doesn't exist
+    Given I am the user with id "21"
+    When I send a POST request to "/answers/404/grade" with the following body:
+      """
+      {"score": 100}
+      """
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "gradings" should be empty
+    And the table "results" should stay unchanged
+
+  Scenario: The user cannot watch answers of the item
+    Given I am the user with id "21"
+    When I send a POST request to "/answers/126/grade" with the following body:
+      """
+      {"score": 100}
+      """
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "gradings" should be empty
+    And the table "results" should stay unchanged
+
+  Scenario: The user cannot watch the participant
+    Given I am the user with id "22"
+    When I send a POST request to "/answers/123/grade" with the following body:
+      """
+      {"score": 100}
+      """
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "gradings" should be empty
+    And the table "results" should stay unchanged
+
+  Scenario: The answer is not a submission
+    Given I am the user with id "21"
+    When I send a POST request to "/answers/124/grade" with the following body:
+      """
+      {"score": 100}
+      """
+    Then the response code should be 403
+    And the response error message should contain "The answer is not a submission"
+    And the table "gradings" should be empty
+    And the table "results" should stay unchanged
+
+  Scenario: The item is not graded manually
+    Given I am the user with id "21"
+    When I send a POST request to "/answers/125/grade" with the following body:
+      """
+      {"score": 100}
+      """
+    Then the response code should be 403
+    And the response error message should contain "The item is not graded manually"
+    And the table "gradings" should be empty
+    And the table "results" should stay unchanged
@@ -0,0 +1,71 @@
+Feature: List similar submissions
+  Background:
+    Given the database has the following users:
+      | group_id | login   |
+      | 11       | jdoe    |
+      | 12       | jane    |
+      | 14       | other   |
+      | 15       | joe     |
+      | 21       | trainer |
+    And the database has the following table "groups":
+      | id | name    | type  |
+      | 13 | Class A | Class |
+      | 16 | Class B | Class |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 11             |
+      | 13              | 12             |
+      | 13              | 15             |
+      | 16              | 14             |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 21         | true              |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | type | default_language_tag |
+      | 50 | Task | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_watch_generated |
+      | 21       | 50      | content            | answer              |
+    And the database has the following table "attempts":
+      | id | participant_id |
+      | 0  | 11             |
+      | 0  | 12             |
+      | 0  | 14             |
+      | 0  | 15             |
+    And the database has the following table "results":
+      | attempt_id | participant_id | item_id |
+      | 0          | 11             | 50      |
+      | 0          | 12             | 50      |
+      | 0          | 14             | 50      |
+      | 0          | 15             | 50      |
+    And the database has the following table "answers":
+      | id  | author_id | participant_id | attempt_id | item_id | type       | answer                                                                   | created_at          |
+      | 101 | 11        | 11             | 0          | 50      | Submission | print(1)                                                                 | 2020-01-01 06:00:00 |
+      | 102 | 11        | 11             | 0          | 50      | Submission | n = int(input())\nprint(n * (n + 1) // 2 + 0)                            | 2020-01-01 07:00:00 |
+      | 103 | 12        | 12             | 0          | 50      | Submission | n = int(input())\nprint(n * (n + 1) // 2)                                | 2020-01-01 08:00:00 |
+      | 104 | 12        | 12             | 0          | 50      | Saved      | print(2)                                                                 | 2020-01-01 09:00:00 |
+      | 105 | 15        | 15             | 0          | 50      | Submission | def f(k):\n  return 1 if k < 2 else k * f(k - 1)\nprint(f(int(input()))) | 2020-01-01 06:00:00 |
+      | 106 | 15        | 15             | 0          | 50      | Submission | N = int(input())\nprint(N * (N + 1) // 2)                                | 2020-01-01 10:00:00 |
+      | 107 | 14        | 14             | 0          | 50      | Submission | n = int(input())\nprint(n * (n + 1) // 2)                                | 2020-01-01 11:00:00 |
+    And the database has the following table "answer_similarities":
+      | answer1_id | answer2_id | item_id | similarity | computed_at         |
+      | 101        | 103        | 50      | 0.75       | 2020-01-02 00:00:00 |
+      | 103        | 107        | 50      | 1          | 2020-01-02 00:00:00 |
+
+  Scenario: List stored pairs of similar submissions of participants of the watched group
+    Given I am the user with id "21"
+    When I send a GET request to "/items/50/similarity?watched_group_id=13"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "similarity": 0.75,
+        "computed_at": "2020-01-02T00:00:00Z",
+        "answer1": {"id": "101", "participant_id": "11", "participant_name": "jdoe", "created_at": "2020-01-01T06:00:00Z"},
+        "answer2": {"id": "103", "participant_id": "12", "participant_name": "jane", "created_at": "2020-01-01T08:00:00Z"}
+      }
+    ]
+    """
+    And the table "answer_similarities" should stay unchanged
@@ -0,0 +1,201 @@
+package answers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+const defaultMinSimilarity = 0.5
+
+type similarAnswersResponseAnswer struct {
+	// required: true
+	ID int64 `json:"id,string"`
+	// required: true
+	ParticipantID int64 `json:"participant_id,string"`
+	// `groups.name` of the participant
+	// required: true
+	ParticipantName string `json:"participant_name"`
+	// required: true
+	CreatedAt database.Time `json:"created_at"`
+}
+
+// swagger:model
+type similarAnswersResponsePair struct {
+	// Similarity of the answers (from 0 to 1)
+	// required: true
+	Similarity float64 `json:"similarity"`
+	// required: true
+	ComputedAt database.Time `json:"computed_at"`
+	// The answer with the smaller id
+	// required: true
+	Answer1 similarAnswersResponseAnswer `json:"answer1"`
+	// The answer with the greater id
+	// required: true
+	Answer2 similarAnswersResponseAnswer `json:"answer2"`
+}
+
+// swagger:ignore
+type rawSimilarAnswersPair struct {
+	Similarity             float64
+	ComputedAt             database.Time
+	Answer1ID              int64
+	Answer1ParticipantID   int64
+	Answer1ParticipantName string
+	Answer1CreatedAt       database.Time
+	Answer2ID              int64
+	Answer2ParticipantID   int64
+	Answer2ParticipantName string
+	Answer2CreatedAt       database.Time
+}
+
+// swagger:operation GET /items/{item_id}/similarity answers similarAnswersList
+//
+//	---
+//	summary: List similar submissions
+//	description: >
+//		Lists pairs of submissions for the task whose participants are descendants of `{watched_group_id}`
+//		(or the group itself) with the similarity >= `{min_similarity}`, the most similar pairs first.
+//
+//
+//		The similarity is computed by the similarity analysis of the latest submissions of the participants
+//		(token-based fingerprinting with winnowing) which is run offline by the `compute-answers-similarity` command.
+//		The analysis replaces the pairs of submissions of participants of the group stored by a previous analysis
+//		and only stores pairs with the similarity >= the `--min-similarity` of the command (0.5 by default).
+//
+//
+//		* The item should be a task.
+//
+//		* The current user should have `can_watch` >= 'answer' on the item.
+//
+//		* The current user should be a manager with `can_watch_members` of `{watched_group_id}` or of its ancestor.
+//	parameters:
+//		- name: item_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: watched_group_id
+//			in: query
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: min_similarity
+//			in: query
+//			type: number
+//			minimum: 0
+//			maximum: 1
+//			default: 0.5
+//		- name: limit
+//			description: Display the first N pairs
+//			in: query
+//			type: integer
+//			maximum: 1000
+//			default: 500
+//	responses:
+//		"200":
+//			description: OK. Success response with an array of pairs of similar submissions
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/similarAnswersResponsePair"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) listSimilarAnswers(rw http.ResponseWriter, httpReq *http.Request) service.APIError {
+	itemID, err := service.ResolveURLQueryPathInt64Field(httpReq, "item_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	watchedGroupID, watchedGroupIDIsSet, apiError := srv.ResolveWatchedGroupID(httpReq)
+	if apiError != service.NoError {
+		return apiError
+	}
+	if !watchedGroupIDIsSet {
+		return service.ErrInvalidRequest(i18n.NewError("missing_parameter", "watched_group_id"))
+	}
+
+	minSimilarity := defaultMinSimilarity
+	if len(httpReq.URL.Query()["min_similarity"]) > 0 {
+		minSimilarity, err = strconv.ParseFloat(httpReq.URL.Query().Get("min_similarity"), 64)
+		if err != nil || minSimilarity < 0 || minSimilarity > 1 {
+			return service.ErrInvalidRequest(errors.New("wrong value for min_similarity (should be a number between 0 and 1)"))
+		}
+	}
+
+	user := srv.GetUser(httpReq)
+	store := srv.GetStore(httpReq)
+
+	if !user.CanWatchItemAnswer(store, itemID) {
+		return service.InsufficientAccessRightsError
+	}
+
+	found, err := store.Items().ByID(itemID).Where("type = 'Task'").HasRows()
+	service.MustNotBeError(err)
+	if !found {
+		return service.ErrForbidden(errors.New("the item is not a task"))
+	}
+
+	participantsQuery := store.ActiveGroupAncestors().
+		Where("groups_ancestors_active.ancestor_group_id = ?", watchedGroupID).
+		Select("groups_ancestors_active.child_group_id")
+
+	query := store.AnswerSimilarities().
+		Joins("JOIN answers AS answers1 ON answers1.id = answer_similarities.answer1_id").
+		Joins("JOIN answers AS answers2 ON answers2.id = answer_similarities.answer2_id").
+		Joins("JOIN `groups` AS participants1 ON participants1.id = answers1.participant_id").
+		Joins("JOIN `groups` AS participants2 ON participants2.id = answers2.participant_id").
+		Where("answer_similarities.item_id = ?", itemID).
+		Where("answer_similarities.similarity >= ?", minSimilarity).
+		Where("answers1.participant_id IN ?", participantsQuery.SubQuery()).
+		Where("answers2.participant_id IN ?", participantsQuery.SubQuery()).
+		Select(`
+			answer_similarities.similarity, answer_similarities.computed_at,
+			answers1.id AS answer1_id, answers1.participant_id AS answer1_participant_id,
+			participants1.name AS answer1_participant_name, answers1.created_at AS answer1_created_at,
+			answers2.id AS answer2_id, answers2.participant_id AS answer2_participant_id,
+			participants2.name AS answer2_participant_name, answers2.created_at AS answer2_created_at`).
+		Order("answer_similarities.similarity DESC, answer_similarities.answer1_id, answer_similarities.answer2_id")
+	query = service.NewQueryLimiter().Apply(httpReq, query)
+
+	var result []rawSimilarAnswersPair
+	service.MustNotBeError(query.Scan(&result).Error())
+
+	responseData := make([]similarAnswersResponsePair, 0, len(result))
+	for index := range result {
+		row := &result[index]
+		responseData = append(responseData, similarAnswersResponsePair{
+			Similarity: row.Similarity,
+			ComputedAt: row.ComputedAt,
+			Answer1: similarAnswersResponseAnswer{
+				ID:              row.Answer1ID,
+				ParticipantID:   row.Answer1ParticipantID,
+				ParticipantName: row.Answer1ParticipantName,
+				CreatedAt:       row.Answer1CreatedAt,
+			},
+			Answer2: similarAnswersResponseAnswer{
+				ID:              row.Answer2ID,
+				ParticipantID:   row.Answer2ParticipantID,
+				ParticipantName: row.Answer2ParticipantName,
+				CreatedAt:       row.Answer2CreatedAt,
+			},
+		})
+	}
+
+	render.Respond(rw, httpReq, responseData)
+	return service.NoError
+}
@@ -0,0 +1,76 @@
+Feature: List similar submissions - robustness
+  Background:
+    Given the database has the following users:
+      | group_id | login    |
+      | 11       | jdoe     |
+      | 21       | trainer  |
+      | 22       | observer |
+    And the database has the following table "groups":
+      | id | name    | type  |
+      | 13 | Class A | Class |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 11             |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 21         | true              |
+      | 13       | 22         | false             |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | type    | default_language_tag |
+      | 50 | Task    | fr                   |
+      | 60 | Chapter | fr                   |
+      | 70 | Task    | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_watch_generated |
+      | 21       | 50      | content            | answer              |
+      | 21       | 60      | content            | answer              |
+      | 21       | 70      | content            | result              |
+      | 22       | 50      | content            | answer              |
+
+  Scenario: Invalid item_id
+    Given I am the user with id "21"
+    When I send a GET request to "/items/abc/similarity?watched_group_id=13"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for item_id (should be int64)"
+
+  Scenario: Missing watched_group_id
+    Given I am the user with id "21"
+    When I send a GET request to "/items/50/similarity"
+    Then the response code should be 400
+    And the response error message should contain "Missing watched_group_id"
+
+  Scenario: Invalid watched_group_id
+    Given I am the user with id "21"
+    When I send a GET request to "/items/50/similarity?watched_group_id=abc"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for watched_group_id (should be int64)"
+
+  Scenario Outline: Invalid min_similarity
+    Given I am the user with id "21"
+    When I send a GET request to "/items/50/similarity?watched_group_id=13&min_similarity=<min_similarity>"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for min_similarity (should be a number between 0 and 1)"
+  Examples:
+    | min_similarity |
+    | abc            |
+    | -0.1           |
+    | 1.5            |
+
+  Scenario: The user cannot watch members of the watched group
+    Given I am the user with id "22"
+    When I send a GET request to "/items/50/similarity?watched_group_id=13"
+    Then the response code should be 403
+    And the response error message should contain "No rights to watch for watched_group_id"
+
+  Scenario: The user cannot watch answers of the item
+    Given I am the user with id "21"
+    When I send a GET request to "/items/70/similarity?watched_group_id=13"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The item is not a task
+    Given I am the user with id "21"
+    When I send a GET request to "/items/60/similarity?watched_group_id=13"
+    Then the response code should be 403
+    And the response error message should contain "The item is not a task"
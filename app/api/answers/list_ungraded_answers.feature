@@ -0,0 +1,110 @@
+Feature: List ungraded submissions
+  Background:
+    Given the database has the following users:
+      | group_id | login   | first_name | last_name |
+      | 11       | jdoe    | John       | Doe       |
+      | 12       | jane    | Jane       | Doe       |
+      | 14       | other   | Other      | User      |
+      | 21       | trainer | Train      | Er        |
+    And the database has the following table "groups":
+      | id | name    | type  |
+      | 13 | Class A | Class |
+      | 15 | Team    | Team  |
+      | 16 | Class B | Class |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id | personal_info_view_approved_at |
+      | 13              | 11             | 2019-05-30 11:00:00            |
+      | 13              | 15             | null                           |
+      | 15              | 12             | null                           |
+      | 16              | 14             | null                           |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 21         | true              |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | type | validation_type | default_language_tag |
+      | 50 | Task | Manual          | fr                   |
+      | 60 | Task | Manual          | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_watch_generated |
+      | 21       | 50      | content            | answer              |
+    And the database has the following table "attempts":
+      | id | participant_id |
+      | 0  | 11             |
+      | 0  | 14             |
+      | 0  | 15             |
+    And the database has the following table "results":
+      | attempt_id | participant_id | item_id |
+      | 0          | 11             | 50      |
+      | 0          | 11             | 60      |
+      | 0          | 14             | 50      |
+      | 0          | 15             | 50      |
+    And the database has the following table "answers":
+      | id  | author_id | participant_id | attempt_id | item_id | type       | created_at          |
+      | 101 | 11        | 11             | 0          | 50      | Submission | 2017-05-29 06:38:38 |
+      | 102 | 11        | 11             | 0          | 50      | Submission | 2017-05-29 06:39:38 |
+      | 103 | 11        | 11             | 0          | 50      | Saved      | 2017-05-29 06:40:38 |
+      | 104 | 11        | 11             | 0          | 60      | Submission | 2017-05-29 06:41:38 |
+      | 105 | 12        | 15             | 0          | 50      | Submission | 2017-05-29 06:37:38 |
+      | 106 | 14        | 14             | 0          | 50      | Submission | 2017-05-29 06:36:38 |
+    And the database has the following table "gradings":
+      | answer_id | score | graded_at           |
+      | 101       | 50    | 2017-05-29 07:00:00 |
+
+  Scenario: List ungraded submissions of participants of the watched group
+    Given I am the user with id "21"
+    When I send a GET request to "/items/50/ungraded-answers?watched_group_id=13"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "id": "105",
+        "participant_id": "15",
+        "attempt_id": "0",
+        "created_at": "2017-05-29T06:37:38Z",
+        "author": {"login": "jane"}
+      },
+      {
+        "id": "102",
+        "participant_id": "11",
+        "attempt_id": "0",
+        "created_at": "2017-05-29T06:39:38Z",
+        "author": {"login": "jdoe", "first_name": "John", "last_name": "Doe"}
+      }
+    ]
+    """
+
+  Scenario: List ungraded submissions of participants of the watched group (the newest first, with limit)
+    Given I am the user with id "21"
+    When I send a GET request to "/items/50/ungraded-answers?watched_group_id=13&sort=-created_at,id&limit=1"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "id": "102",
+        "participant_id": "11",
+        "attempt_id": "0",
+        "created_at": "2017-05-29T06:39:38Z",
+        "author": {"login": "jdoe", "first_name": "John", "last_name": "Doe"}
+      }
+    ]
+    """
+
+  Scenario: List ungraded submissions of a participant
+    Given I am the user with id "21"
+    When I send a GET request to "/items/50/ungraded-answers?watched_group_id=15"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "id": "105",
+        "participant_id": "15",
+        "attempt_id": "0",
+        "created_at": "2017-05-29T06:37:38Z",
+        "author": {"login": "jane"}
+      }
+    ]
+    """
@@ -0,0 +1,190 @@
+package answers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/structures"
+)
+
+// swagger:model
+type ungradedAnswersResponseAnswer struct {
+	// `answers.id`
+	// required: true
+	ID int64 `json:"id,string"`
+	// required: true
+	ParticipantID int64 `json:"participant_id,string"`
+	// required: true
+	AttemptID int64 `json:"attempt_id,string"`
+	// required: true
+	CreatedAt database.Time `json:"created_at"`
+
+	// required: true
+	Author answersResponseAnswerUser `json:"author"`
+}
+
+// swagger:ignore
+type rawUngradedAnswersData struct {
+	ID               int64
+	ParticipantID    int64
+	AttemptID        int64
+	CreatedAt        database.Time
+	UserLogin        string  `sql:"column:login"`
+	UserFirstName    *string `sql:"column:first_name"`
+	UserLastName     *string `sql:"column:last_name"`
+	ShowPersonalInfo bool
+}
+
+// swagger:operation GET /items/{item_id}/ungraded-answers answers ungradedAnswersList
+//
+//	---
+//	summary: List ungraded submissions
+//	description: >
+//		Lists submissions (`answers.type` = 'Submission') for the item without grading
+//		whose participants are descendants of `{watched_group_id}` (or the group itself),
+//		so that a trainer can grade them manually via `POST /answers/{answer_id}/grade`.
+//
+//		* The item should have `validation_type` = 'Manual'.
+//
+//		* The current user should have `can_watch` >= 'answer' on the item.
+//
+//		* The current user should be a manager with `can_watch_members` of `{watched_group_id}` or of its ancestor.
+//
+//
+//		Authors' `first_name` and `last_name` are only shown for the authenticated user or if the user
+//		approved access to their personal info for some group managed by the authenticated user.
+//	parameters:
+//		- name: item_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: watched_group_id
+//			in: query
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: sort
+//			in: query
+//			default: [created_at,id]
+//			type: array
+//			items:
+//				type: string
+//				enum: [created_at,-created_at,id,-id]
+//		- name: from.id
+//			description: Start the page from the answer next to the answer with `answers.id`=`{from.id}`
+//			in: query
+//			type: integer
+//		- name: limit
+//			description: Display the first N answers
+//			in: query
+//			type: integer
+//			maximum: 1000
+//			default: 500
+//	responses:
+//		"200":
+//			description: OK. Success response with an array of ungraded submissions
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/ungradedAnswersResponseAnswer"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) listUngradedAnswers(rw http.ResponseWriter, httpReq *http.Request) service.APIError {
+	itemID, err := service.ResolveURLQueryPathInt64Field(httpReq, "item_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	watchedGroupID, watchedGroupIDIsSet, apiError := srv.ResolveWatchedGroupID(httpReq)
+	if apiError != service.NoError {
+		return apiError
+	}
+	if !watchedGroupIDIsSet {
+		return service.ErrInvalidRequest(i18n.NewError("missing_parameter", "watched_group_id"))
+	}
+
+	user := srv.GetUser(httpReq)
+	store := srv.GetStore(httpReq)
+
+	if !user.CanWatchItemAnswer(store, itemID) {
+		return service.InsufficientAccessRightsError
+	}
+
+	found, err := store.Items().ByID(itemID).Where("validation_type = 'Manual'").HasRows()
+	service.MustNotBeError(err)
+	if !found {
+		return service.ErrForbidden(errors.New("the item is not graded manually"))
+	}
+
+	dataQuery := store.Answers().WithUsers().
+		Joins(`
+			JOIN groups_ancestors_active
+				ON groups_ancestors_active.child_group_id = answers.participant_id AND
+				   groups_ancestors_active.ancestor_group_id = ?`, watchedGroupID).
+		Joins("LEFT JOIN gradings ON gradings.answer_id = answers.id").
+		Select(`
+			answers.id, answers.participant_id, answers.attempt_id, answers.created_at,
+			users.login,
+			users.group_id = ? OR personal_info_view_approvals.approved AS show_personal_info,
+			IF(users.group_id = ? OR personal_info_view_approvals.approved, users.first_name, NULL) AS first_name,
+			IF(users.group_id = ? OR personal_info_view_approvals.approved, users.last_name, NULL) AS last_name`,
+			user.GroupID, user.GroupID, user.GroupID).
+		Where("answers.item_id = ?", itemID).
+		Where("answers.type = 'Submission'").
+		Where("gradings.answer_id IS NULL").
+		WithPersonalInfoViewApprovals(user)
+
+	dataQuery, apiError = service.ApplySortingAndPaging(
+		httpReq, dataQuery,
+		&service.SortingAndPagingParameters{
+			Fields: service.SortingAndPagingFields{
+				"created_at": {ColumnName: "answers.created_at"},
+				"id":         {ColumnName: "answers.id"},
+			},
+			DefaultRules: "created_at,id",
+			TieBreakers:  service.SortingAndPagingTieBreakers{"id": service.FieldTypeInt64},
+		})
+	if apiError != service.NoError {
+		return apiError
+	}
+	dataQuery = service.NewQueryLimiter().Apply(httpReq, dataQuery)
+
+	var result []rawUngradedAnswersData
+	service.MustNotBeError(dataQuery.Scan(&result).Error())
+
+	responseData := make([]ungradedAnswersResponseAnswer, 0, len(result))
+	for index := range result {
+		row := &result[index]
+		responseDataRow := ungradedAnswersResponseAnswer{
+			ID:            row.ID,
+			ParticipantID: row.ParticipantID,
+			AttemptID:     row.AttemptID,
+			CreatedAt:     row.CreatedAt,
+			Author:        answersResponseAnswerUser{Login: row.UserLogin},
+		}
+		if row.ShowPersonalInfo {
+			responseDataRow.Author.UserPersonalInfo = &structures.UserPersonalInfo{
+				FirstName: row.UserFirstName,
+				LastName:  row.UserLastName,
+			}
+		}
+		responseData = append(responseData, responseDataRow)
+	}
+
+	render.Respond(rw, httpReq, responseData)
+	return service.NoError
+}
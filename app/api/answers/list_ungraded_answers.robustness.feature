@@ -0,0 +1,71 @@
+Feature: List ungraded submissions - robustness
+  Background:
+    Given the database has the following users:
+      | group_id | login    |
+      | 11       | jdoe     |
+      | 21       | trainer  |
+      | 22       | observer |
+    And the database has the following table "groups":
+      | id | name    | type  |
+      | 13 | Class A | Class |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 11             |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 21         | true              |
+      | 13       | 22         | false             |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | type | validation_type | default_language_tag |
+      | 50 | Task | Manual          | fr                   |
+      | 60 | Task | All             | fr                   |
+      | 70 | Task | Manual          | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_watch_generated |
+      | 21       | 50      | content            | answer              |
+      | 21       | 60      | content            | answer              |
+      | 21       | 70      | content            | result              |
+      | 22       | 50      | content            | answer              |
+
+  Scenario: Invalid item_id
+    Given I am the user with id "21"
+    When I send a GET request to "/items/abc/ungraded-answers?watched_group_id=13"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for item_id (should be int64)"
+
+  Scenario: Missing watched_group_id
+    Given I am the user with id "21"
+    When I send a GET request to "/items/50/ungraded-answers"
+    Then the response code should be 400
+    And the response error message should contain "Missing watched_group_id"
+
+  Scenario: Invalid watched_group_id
+    Given I am the user with id "21"
+    When I send a GET request to "/items/50/ungraded-answers?watched_group_id=abc"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for watched_group_id (should be int64)"
+
+  Scenario: The user cannot watch members of the watched group
+    Given I am the user with id "22"
+    When I send a GET request to "/items/50/ungraded-answers?watched_group_id=13"
+    Then the response code should be 403
+    And the response error message should contain "No rights to watch for watched_group_id"
+
+  Scenario: The user cannot watch answers of the item
+    Given I am the user with id "21"
+    When I send a GET request to "/items/70/ungraded-answers?watched_group_id=13"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The item is not graded manually
+    Given I am the user with id "21"
+    When I send a GET request to "/items/60/ungraded-answers?watched_group_id=13"
+    Then the response code should be 403
+    And the response error message should contain "The item is not graded manually"
+
+  Scenario: Wrong sort
+    Given I am the user with id "21"
+    When I send a GET request to "/items/50/ungraded-answers?watched_group_id=13&sort=title"
+    Then the response code should be 400
+    And the response error message should contain "Unallowed field in sorting parameters: "title""
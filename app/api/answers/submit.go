@@ -13,6 +13,7 @@ import (
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/doc"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/token"
@@ -82,6 +83,8 @@ type answerSubmitResponse struct {
 //			"$ref": "#/responses/forbiddenResponse"
 //		"408":
 //			"$ref": "#/responses/requestTimeoutResponse"
+//		"429":
+//			"$ref": "#/responses/tooManyRequestsResponse"
 //		"500":
 //			"$ref": "#/responses/internalErrorResponse"
 func (srv *Service) submit(rw http.ResponseWriter, httpReq *http.Request) service.APIError {
@@ -185,11 +188,11 @@ func (requestData *SubmitRequest) UnmarshalJSON(raw []byte) error {
 // all the needed values are valid.
 func (requestData *SubmitRequest) Bind(_ *http.Request) error {
 	if requestData.TaskToken == nil {
-		return errors.New("missing task_token")
+		return i18n.NewError("missing_parameter", "task_token")
 	}
 
 	if requestData.Answer == nil {
-		return errors.New("missing answer")
+		return i18n.NewError("missing_parameter", "answer")
 	}
 
 	return nil
@@ -0,0 +1,111 @@
+Feature: Submit a new answer - rate limit
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | john  | 101      |
+      | jane  | 102      |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | default_language_tag |
+      | 50 | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated |
+      | 101      | 50      | content            |
+      | 102      | 50      | content            |
+    And the database has the following table "attempts":
+      | id | participant_id |
+      | 1  | 101            |
+      | 1  | 102            |
+    And the database has the following table "results":
+      | attempt_id | participant_id | item_id | started_at          |
+      | 1          | 101            | 50      | 2019-05-30 11:00:00 |
+      | 1          | 102            | 50      | 2019-05-30 11:00:00 |
+    And the server time is frozen
+    And "johnTaskToken" is a token signed by the app with the following payload:
+      """
+      {
+        "idUser": "101",
+        "idItemLocal": "50",
+        "idAttempt": "101/1",
+        "platformName": "{{app().Config.GetString("token.platformName")}}"
+      }
+      """
+    And "janeTaskToken" is a token signed by the app with the following payload:
+      """
+      {
+        "idUser": "102",
+        "idItemLocal": "50",
+        "idAttempt": "102/1",
+        "platformName": "{{app().Config.GetString("token.platformName")}}"
+      }
+      """
+
+  Scenario Outline: Users behind the same IP address have separate buckets
+    Given the application config is:
+      """
+      server:
+        rateLimits:
+          answers: {limit: 1, period: 1h, key: <key>}
+      """
+    When I send a POST request to "/answers" with the following body:
+      """
+      {
+        "task_token": "{{johnTaskToken}}",
+        "answer": "print 1"
+      }
+      """
+    Then the response code should be 201
+    And the response header "RateLimit-Remaining" should be "0"
+    When I send a POST request to "/answers" with the following body:
+      """
+      {
+        "task_token": "{{johnTaskToken}}",
+        "answer": "print 2"
+      }
+      """
+    Then the response code should be 429
+    And the response header "Retry-After" should be "3600"
+    When I send a POST request to "/answers" with the following body:
+      """
+      {
+        "task_token": "{{janeTaskToken}}",
+        "answer": "print 3"
+      }
+      """
+    Then the response code should be 201
+    And the response header "RateLimit-Remaining" should be "0"
+    And the table "answers" should be:
+      | author_id | participant_id | attempt_id | item_id | answer  |
+      | 101       | 101            | 1          | 50      | print 1 |
+      | 102       | 102            | 1          | 50      | print 3 |
+  Examples:
+    | key     |
+    | user    |
+    | session |
+
+  Scenario: Users behind the same IP address share the bucket of the IP address
+    Given the application config is:
+      """
+      server:
+        rateLimits:
+          answers: {limit: 1, period: 1h, key: ip}
+      """
+    When I send a POST request to "/answers" with the following body:
+      """
+      {
+        "task_token": "{{johnTaskToken}}",
+        "answer": "print 1"
+      }
+      """
+    Then the response code should be 201
+    When I send a POST request to "/answers" with the following body:
+      """
+      {
+        "task_token": "{{janeTaskToken}}",
+        "answer": "print 2"
+      }
+      """
+    Then the response code should be 429
+    And the table "answers" should be:
+      | author_id | participant_id | attempt_id | item_id | answer  |
+      | 101       | 101            | 1          | 50      | print 1 |
@@ -83,6 +83,7 @@ Feature: Update participant's current answer
           "answer": ["missing field"]
         },
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "success": false
       }
       """
@@ -105,6 +106,7 @@ Feature: Update participant's current answer
           "state": ["missing field"]
         },
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "success": false
       }
       """
@@ -9,12 +9,16 @@ import (
 	"github.com/France-ioi/AlgoreaBackend/v2/app/api/auth"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/api/contests"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/api/currentuser"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/api/exports"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/api/groups"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/api/items"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/api/threads"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/api/users"
+	appauth "github.com/France-ioi/AlgoreaBackend/v2/app/auth"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/domain"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/lti"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/ratelimit"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/token"
 )
@@ -26,15 +30,18 @@ type Ctx struct {
 
 // Router provides routes for the whole API.
 func Router(db *database.DB, serverConfig, authConfig *viper.Viper, domainConfig []domain.ConfigItem,
-	tokenConfig *token.Config,
+	tokenConfig *token.Config, rateLimiter *ratelimit.Limiter,
 ) (*Ctx, *chi.Mux) {
 	r := chi.NewRouter()
 
 	srv := &service.Base{
-		ServerConfig: serverConfig,
-		AuthConfig:   authConfig,
-		DomainConfig: domainConfig,
-		TokenConfig:  tokenConfig,
+		ServerConfig:      serverConfig,
+		AuthConfig:        authConfig,
+		DomainConfig:      domainConfig,
+		TokenConfig:       tokenConfig,
+		RateLimiter:       rateLimiter,
+		IdentityProviders: appauth.NewIdentityProviders(),
+		LTIKeySets:        lti.NewKeySets(),
 	}
 	srv.SetGlobalStore(database.NewDataStore(db))
 
@@ -47,6 +54,7 @@ func Router(db *database.DB, serverConfig, authConfig *viper.Viper, domainConfig
 	r.Group((&answers.Service{Base: srv}).SetRoutes)
 	r.Group((&currentuser.Service{Base: srv}).SetRoutes)
 	r.Group((&users.Service{Base: srv}).SetRoutes)
+	r.Group((&exports.Service{Base: srv}).SetRoutes)
 	r.Get("/status", ctx.status)
 	r.NotFound(service.NotFound)
 
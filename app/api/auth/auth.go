@@ -18,10 +18,21 @@ type Service struct {
 // SetRoutes defines the routes for this package in a route group.
 func (srv *Service) SetRoutes(router chi.Router) {
 	router.Use(render.SetContentType(render.ContentTypeJSON))
-	router.Post("/auth/temp-user", service.AppHandler(srv.createTempUser).ServeHTTP)
+	router.With(srv.RateLimitMiddleware("temp-user")).
+		Post("/auth/temp-user", service.AppHandler(srv.createTempUser).ServeHTTP)
 
 	router.With(middleware.AllowContentType("", "application/json", "application/x-www-form-urlencoded")).
 		Post("/auth/token", service.AppHandler(srv.createAccessToken).ServeHTTP)
 	router.With(auth.UserMiddleware(srv.Base)).
 		Post("/auth/logout", service.AppHandler(srv.logout).ServeHTTP)
+
+	router.Get("/auth/lti/login", service.AppHandler(srv.initiateLTILogin).ServeHTTP)
+	router.With(middleware.AllowContentType("", "application/x-www-form-urlencoded")).
+		Post("/auth/lti/login", service.AppHandler(srv.initiateLTILogin).ServeHTTP)
+	router.With(middleware.AllowContentType("application/x-www-form-urlencoded")).
+		Post("/auth/lti/launch", service.AppHandler(srv.launchLTI).ServeHTTP)
+	router.Get("/auth/lti/jwks", service.AppHandler(srv.getLTIJWKS).ServeHTTP)
+	router.With(auth.UserMiddleware(srv.Base)).
+		Post("/auth/lti/deep-linking-requests/{request_id}/response",
+			service.AppHandler(srv.createLTIDeepLinkingResponse).ServeHTTP)
 }
@@ -894,3 +894,121 @@ Feature: Create an access token
     And the table "users" at group_id "5577006791947779410" should be:
       | group_id            | login_id | login        | temp_user | default_language | ABS(TIMESTAMPDIFF(SECOND, registered_at, NOW())) < 3 | last_ip   |
       | 5577006791947779410 | 0        | tmp-49727887 | true      | fr                | true                                                 | 127.0.0.1 |
+
+  Scenario: Create a new user with the OpenID Connect identity provider
+    Given the application config is:
+      """
+      auth:
+        loginModuleURL: "https://login.algorea.org"
+        clientID: "1"
+        clientSecret: "tzxsLyFtJiGnmD6sjZMqSEidVpVsL3hEoSxIXCpI"
+        oidc:
+          issuer: "https://sso.example.org"
+          clientID: "algorea"
+          clientSecret: "oidcsecret"
+      domains:
+        -
+          domains: [127.0.0.1]
+          allUsersGroup: 2
+          TempUsersGroup: 4
+      """
+    And the time now is "2019-07-16T22:02:28Z"
+    And the DB time now is "2019-07-16 22:02:28"
+    And the generated auth key is "ny93zqri9a2adn4v1ut6izd76xb3pccw"
+    And the OIDC provider "token" endpoint for code "somecode" returns the refresh token "oidcrefreshtoken" and an ID token with claims:
+      """
+      {
+        "sub": "user-1", "preferred_username": "jdoe", "email": "jdoe@example.org", "email_verified": true,
+        "given_name": "John", "family_name": "Doe", "locale": "fr-FR", "zoneinfo": "Europe/Paris"
+      }
+      """
+    When I send a POST request to "/auth/token?code=somecode&provider=oidc"
+    Then the response code should be 201
+    And the response body should be, in JSON:
+      """
+      {
+        "success": true,
+        "message": "created",
+        "data": {
+          "access_token": "ny93zqri9a2adn4v1ut6izd76xb3pccw",
+          "expires_in": 3600
+        }
+      }
+      """
+    And the table "users" should be:
+      | group_id            | latest_login_at     | temp_user | registered_at       | login_id | login | email            | email_verified | first_name | last_name | default_language | time_zone    | last_ip   |
+      | 5577006791947779410 | 2019-07-16 22:02:28 | 0         | 2019-07-16 22:02:28 | null     | jdoe  | jdoe@example.org | 1              | John       | Doe       | fr               | Europe/Paris | 127.0.0.1 |
+    And the table "user_identities" should be:
+      | issuer                  | subject | user_id             |
+      | https://sso.example.org | user-1  | 5577006791947779410 |
+    And the table "groups_groups" should be:
+      | parent_group_id | child_group_id      |
+      | 2               | 4                   |
+      | 2               | 5577006791947779410 |
+    And the table "sessions" should be:
+      | session_id          | user_id             | refresh_token    | identity_provider |
+      | 8674665223082153551 | 5577006791947779410 | oidcrefreshtoken | oidc              |
+    And the table "access_tokens" should be:
+      | session_id          | token                            | expires_at          | issued_at           |
+      | 8674665223082153551 | ny93zqri9a2adn4v1ut6izd76xb3pccw | 2019-07-16 23:02:28 | 2019-07-16 22:02:28 |
+
+  Scenario: Log in an existing user with the OpenID Connect identity provider
+    Given the application config is:
+      """
+      auth:
+        loginModuleURL: "https://login.algorea.org"
+        clientID: "1"
+        clientSecret: "tzxsLyFtJiGnmD6sjZMqSEidVpVsL3hEoSxIXCpI"
+        oidc:
+          issuer: "https://sso.example.org"
+          clientID: "algorea"
+          clientSecret: "oidcsecret"
+      domains:
+        -
+          domains: [127.0.0.1]
+          allUsersGroup: 2
+          TempUsersGroup: 4
+      """
+    And the time now is "2019-07-16T22:02:28Z"
+    And the DB time now is "2019-07-16 22:02:28"
+    And the generated auth key is "ny93zqri9a2adn4v1ut6izd76xb3pccw"
+    And the database has the following users:
+      | group_id | temp_user | registered_at       | login_id | login | email            | first_name | last_name | default_language |
+      | 11       | 0         | 2019-05-10 10:42:11 | null     | jdoe  | jdoe@example.com | Johnny     | Doe       | en               |
+    And the database has the following table "user_identities":
+      | issuer                  | subject | user_id |
+      | https://sso.example.org | user-1  | 11      |
+    And the OIDC provider "token" endpoint for code "somecode" returns the refresh token "oidcrefreshtoken" and an ID token with claims:
+      """
+      {
+        "sub": "user-1", "preferred_username": "jdoe", "email": "jdoe@example.org",
+        "given_name": "John", "family_name": "Doe", "locale": "fr"
+      }
+      """
+    When I send a POST request to "/auth/token?code=somecode&provider=oidc"
+    Then the response code should be 201
+    And the response body should be, in JSON:
+      """
+      {
+        "success": true,
+        "message": "created",
+        "data": {
+          "access_token": "ny93zqri9a2adn4v1ut6izd76xb3pccw",
+          "expires_in": 3600
+        }
+      }
+      """
+    And the table "users" should be:
+      | group_id | latest_login_at     | temp_user | registered_at       | login_id | login | email            | email_verified | first_name | last_name | default_language | last_ip   |
+      | 11       | 2019-07-16 22:02:28 | 0         | 2019-05-10 10:42:11 | null     | jdoe  | jdoe@example.org | 0              | John       | Doe       | en               | 127.0.0.1 |
+    And the table "user_identities" should stay unchanged
+    And the table "groups_groups" should be:
+      | parent_group_id | child_group_id |
+      | 2               | 4              |
+      | 2               | 11             |
+    And the table "sessions" should be:
+      | session_id          | user_id | refresh_token    | identity_provider |
+      | 5577006791947779410 | 11      | oidcrefreshtoken | oidc              |
+    And the table "access_tokens" should be:
+      | session_id          | token                            | expires_at          | issued_at           |
+      | 5577006791947779410 | ny93zqri9a2adn4v1ut6izd76xb3pccw | 2019-07-16 23:02:28 | 2019-07-16 22:02:28 |
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -19,8 +18,8 @@ import (
 	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/domain"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
-	"github.com/France-ioi/AlgoreaBackend/v2/app/loginmodule"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/rand"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 )
@@ -58,6 +57,12 @@ const parsedRequestData ctxKey = iota
 //			If OAuth2 authentication has used the PKCE extension, the `{code_verifier}` should be provided
 //			so it can be sent together with the `{code}` to the authentication server.
 //
+//			The `{provider}` parameter tells which identity provider has issued the `{code}`:
+//			the login module (`login_module`, by default) or the OpenID Connect identity provider
+//			configured for the backend (`oidc`). Users logged in with an OpenID Connect provider are linked
+//			to the provider's subject identifiers, their profiles are filled from the claims of the ID token,
+//			and they receive access tokens generated by the backend (the tokens of the provider are never returned).
+//
 //
 //		* If the `{code}` is not given while the "Authorization" header or/and the "access_token" cookie is given
 //			(when both are given, the "Authorization" header is used, and the cookie gets deleted),
@@ -75,7 +80,8 @@ const parsedRequestData ctxKey = iota
 //
 //			3. If the access token used is the most recent access token of the user, and it has been refreshed BEFORE 5 minutes ago,
 //				we refresh the access token and return the new access token
-//				(locally for temporary users or via the login module for normal users) and
+//				(locally for temporary users and for sessions launched by LTI platforms,
+//				or via the identity provider for normal users) and
 //				saves it into the DB keeping only the input token and the new token.
 //				Since the login module responds with both access and refresh tokens, the service updates the user's
 //				refresh token in this case as well.
@@ -113,6 +119,8 @@ const parsedRequestData ctxKey = iota
 //		Validations
 //			* The "Authorization" header is not allowed when the `{code}` is given.
 //
+//			* The `{provider}` must be configured.
+//
 //			* When `{use_cookie}`=1, at least one of `{cookie_secure}` and `{cookie_same_site}` must be true.
 //	security: []
 //	consumes:
@@ -131,6 +139,12 @@ const parsedRequestData ctxKey = iota
 //			in: query
 //			description: OAuth2 redirection URI
 //			type: string
+//		- name: provider
+//			in: query
+//			description: The identity provider which has issued the OAuth2 code (can also be given in form data)
+//			type: string
+//			enum: [login_module,oidc]
+//			default: login_module
 //		- name: use_cookie
 //			in: query
 //			description: If 1, set a cookie instead of returning the OAuth2 code in the data
@@ -175,6 +189,10 @@ const parsedRequestData ctxKey = iota
 //					redirect_uri:
 //						type: string
 //						description: OAuth2 redirection URI
+//					provider:
+//						type: string
+//						enum: [login_module,oidc]
+//						description: The identity provider which has issued the OAuth2 code
 //					use_cookie:
 //						type: boolean
 //						description: If true, set a cookie instead of returning the OAuth2 code in the data
@@ -194,6 +212,8 @@ const parsedRequestData ctxKey = iota
 //				"$ref": "#/definitions/userCreateTmpResponse"
 //		"400":
 //			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
 //		"404":
 //			"$ref": "#/responses/notFoundResponse"
 //		"408":
@@ -249,7 +269,15 @@ func (srv *Service) createAccessToken(w http.ResponseWriter, r *http.Request) se
 		return service.NoError
 	}
 
-	oauthConfig := auth.GetOAuthConfig(srv.AuthConfig)
+	identityProviderName := auth.IdentityProviderLoginModule
+	if providerName, ok := requestData["provider"]; ok {
+		identityProviderName = providerName.(string)
+	}
+	identityProvider, err := srv.IdentityProviders.Get(srv.AuthConfig, identityProviderName)
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
 	oauthOptions := make([]oauth2.AuthCodeOption, 0, 1)
 	if codeVerifier, ok := requestData["code_verifier"]; ok {
 		oauthOptions = append(oauthOptions, oauth2.SetAuthURLParam("code_verifier", codeVerifier.(string)))
@@ -258,37 +286,51 @@ func (srv *Service) createAccessToken(w http.ResponseWriter, r *http.Request) se
 		oauthOptions = append(oauthOptions, oauth2.SetAuthURLParam("redirect_uri", redirectURI.(string)))
 	}
 
-	token, err := oauthConfig.Exchange(r.Context(), code.(string), oauthOptions...)
+	token, err := identityProvider.ExchangeCode(r.Context(), code.(string), oauthOptions...)
 	service.MustNotBeError(err)
 
-	userProfile, err := loginmodule.NewClient(srv.AuthConfig.GetString("loginModuleURL")).GetUserProfile(r.Context(), token.AccessToken)
+	userProfile, err := identityProvider.GetUserProfile(r.Context(), token)
+	if errors.Is(err, auth.ErrInvalidUserProfile) {
+		return service.APIError{HTTPStatusCode: http.StatusUnauthorized, Error: err}
+	}
+	service.MustNotBeError(err)
+	userProfile.Attributes["last_ip"] = strings.SplitN(r.RemoteAddr, ":", 2)[0]
+
+	accessToken, expiresAt, err := identityProvider.SessionAccessToken(token)
 	service.MustNotBeError(err)
-	userProfile["last_ip"] = strings.SplitN(r.RemoteAddr, ":", 2)[0]
 
 	domainConfig := domain.ConfigFromContext(r.Context())
 
-	service.MustNotBeError(srv.GetStore(r).InTransaction(func(store *database.DataStore) error {
-		userID := createOrUpdateUser(store.Users(), userProfile, domainConfig)
+	err = srv.GetStore(r).InTransaction(func(store *database.DataStore) error {
+		var userID int64
+		userID, apiError = createOrUpdateUser(store.Users(), userProfile, domainConfig)
+		if apiError != service.NoError {
+			return apiError.Error // rollback
+		}
 		logging.LogEntrySetField(r, "user_id", userID)
-		service.MustNotBeError(store.Groups().StoreBadges(userProfile["badges"].([]database.Badge), userID, true))
+		service.MustNotBeError(store.Groups().StoreBadges(userProfile.Badges, userID, true))
 
 		sessionID := rand.Int63()
 		service.MustNotBeError(store.Exec(
-			"INSERT INTO sessions (session_id, user_id, refresh_token) VALUES (?, ?, ?)",
-			sessionID, userID, token.RefreshToken).Error())
+			"INSERT INTO sessions (session_id, user_id, refresh_token, identity_provider) VALUES (?, ?, ?, ?)",
+			sessionID, userID, token.RefreshToken, identityProviderName).Error())
 		service.MustNotBeError(store.AccessTokens().InsertNewToken(
 			sessionID,
-			token.AccessToken,
-			int32(time.Until(token.Expiry)/time.Second),
+			accessToken,
+			int32(time.Until(expiresAt)/time.Second),
 		))
 
 		// Delete the oldest sessions of the user to keep a maximum of 10 sessions.
 		store.Sessions().DeleteOldSessionsToKeepMaximum(userID, 10)
 
 		return nil
-	}))
+	})
+	if apiError != service.NoError {
+		return apiError
+	}
+	service.MustNotBeError(err)
 
-	srv.respondWithNewAccessToken(r, w, service.CreationSuccess[map[string]interface{}], token.AccessToken, token.Expiry, cookieAttributes)
+	srv.respondWithNewAccessToken(r, w, service.CreationSuccess[map[string]interface{}], accessToken, expiresAt, cookieAttributes)
 	return service.NoError
 }
 
@@ -339,7 +381,7 @@ func (srv *Service) resolveCookieAttributes(r *http.Request, requestData map[str
 
 func parseRequestParametersForCreateAccessToken(r *http.Request) (map[string]interface{}, service.APIError) {
 	allowedParameters := []string{
-		"code", "code_verifier", "redirect_uri",
+		"code", "code_verifier", "redirect_uri", "provider",
 		"use_cookie", "cookie_secure", "cookie_same_site",
 	}
 	requestData := make(map[string]interface{}, 2)
@@ -371,6 +413,7 @@ func parseJSONParams(r *http.Request, requestData map[string]interface{}) servic
 		Code           *string `json:"code"`
 		CodeVerifier   *string `json:"code_verifier"`
 		RedirectURI    *string `json:"redirect_uri"`
+		Provider       *string `json:"provider"`
 		UseCookie      *bool   `json:"use_cookie"`
 		CookieSecure   *bool   `json:"cookie_secure"`
 		CookieSameSite *bool   `json:"cookie_same_site"`
@@ -389,6 +432,9 @@ func parseJSONParams(r *http.Request, requestData map[string]interface{}) servic
 	if jsonPayload.RedirectURI != nil {
 		requestData["redirect_uri"] = *jsonPayload.RedirectURI
 	}
+	if jsonPayload.Provider != nil {
+		requestData["provider"] = *jsonPayload.Provider
+	}
 	bool2String := map[bool]string{false: "0", true: "1"}
 	if jsonPayload.UseCookie != nil {
 		requestData["use_cookie"] = bool2String[*jsonPayload.UseCookie]
@@ -406,7 +452,7 @@ func preprocessBooleanCookieAttributes(requestData map[string]interface{}) (map[
 	for _, flagName := range []string{"use_cookie", "cookie_secure", "cookie_same_site"} {
 		if stringValue, ok := requestData[flagName]; ok {
 			if _, ok = map[string]bool{"0": false, "1": true}[stringValue.(string)]; !ok {
-				return nil, service.ErrInvalidRequest(fmt.Errorf("wrong value for %s (should have a boolean value (0 or 1))", flagName))
+				return nil, service.ErrInvalidRequest(i18n.NewError("wrong_boolean_value", flagName))
 			}
 			delete(requestData, flagName)
 			if stringValue == "1" {
@@ -423,10 +469,27 @@ func extractOptionalParameter(query url.Values, paramName string, requestData ma
 	}
 }
 
-func createOrUpdateUser(s *database.UserStore, userData map[string]interface{}, domainConfig *domain.CtxConfig) int64 {
+func createOrUpdateUser(s *database.UserStore, userProfile *auth.UserProfile, domainConfig *domain.CtxConfig) (int64, service.APIError) {
+	userData := userProfile.Attributes
 	var groupID int64
-	err := s.WithExclusiveWriteLock().
-		Where("login_id = ?", userData["login_id"]).PluckFirst("group_id", &groupID).Error()
+	var err error
+	if userProfile.Subject == "" {
+		err = s.WithExclusiveWriteLock().
+			Where("login_id = ?", userData["login_id"]).PluckFirst("group_id", &groupID).Error()
+	} else {
+		err = s.UserIdentities().WithExclusiveWriteLock().
+			Where("issuer = ? AND subject = ?", userProfile.Issuer, userProfile.Subject).PluckFirst("user_id", &groupID).Error()
+	}
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		service.MustNotBeError(err)
+	}
+
+	loginIsTaken, takenErr := s.WithExclusiveWriteLock().
+		Where("login = ? AND group_id != ?", userData["login"], groupID).HasRows()
+	service.MustNotBeError(takenErr)
+	if loginIsTaken {
+		return 0, service.ErrConflict(errors.New("the login is already used by another user"))
+	}
 
 	userData["latest_login_at"] = database.Now()
 	userData["latest_activity_at"] = database.Now()
@@ -436,10 +499,6 @@ func createOrUpdateUser(s *database.UserStore, userData map[string]interface{},
 		userData["default_language"] = database.Default()
 	}
 
-	badges := userData["badges"]
-	delete(userData, "badges")
-	defer func() { userData["badges"] = badges }()
-
 	if gorm.IsRecordNotFoundError(err) {
 		selfGroupID := createGroupFromLogin(s.Groups(), userData["login"].(string), domainConfig)
 		userData["temp_user"] = 0
@@ -453,10 +512,16 @@ func createOrUpdateUser(s *database.UserStore, userData map[string]interface{},
 			"creator_id":     selfGroupID,
 			"created_at":     database.Now(),
 		}))
+		if userProfile.Subject != "" {
+			service.MustNotBeError(s.UserIdentities().InsertMap(map[string]interface{}{
+				"issuer":  userProfile.Issuer,
+				"subject": userProfile.Subject,
+				"user_id": selfGroupID,
+			}))
+		}
 
-		return selfGroupID
+		return selfGroupID, service.NoError
 	}
-	service.MustNotBeError(err)
 
 	found, err := s.GroupGroups().WithExclusiveWriteLock().Where("parent_group_id = ?", domainConfig.AllUsersGroupID).
 		Where("child_group_id = ?", groupID).HasRows()
@@ -470,7 +535,7 @@ func createOrUpdateUser(s *database.UserStore, userData map[string]interface{},
 	service.MustNotBeError(s.GroupGroups().CreateRelationsWithoutChecking(groupsToCreate))
 	delete(userData, "default_language")
 	service.MustNotBeError(s.ByID(groupID).UpdateColumn(userData).Error())
-	return groupID
+	return groupID, service.NoError
 }
 
 func createGroupFromLogin(store *database.GroupStore, login string, domainConfig *domain.CtxConfig) (selfGroupID int64) {
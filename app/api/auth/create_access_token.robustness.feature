@@ -208,3 +208,79 @@ Feature: Login callback - robustness
     | ?use_cookie=abc                                  | Wrong value for use_cookie (should have a boolean value (0 or 1))              |
     | ?cookie_same_site=abc                            | Wrong value for cookie_same_site (should have a boolean value (0 or 1))        |
     | ?cookie_secure=abc                               | Wrong value for cookie_secure (should have a boolean value (0 or 1))           |
+
+  Scenario: Unknown identity provider
+    When I send a POST request to "/auth/token?code=somecode&provider=saml"
+    Then the response code should be 400
+    And the response error message should contain "Unknown identity provider"
+    And the table "users" should stay unchanged
+    And the table "sessions" should stay unchanged
+    And the table "access_tokens" should stay unchanged
+
+  Scenario: The OpenID Connect identity provider is not configured
+    Given the application config is:
+      """
+      auth:
+        loginModuleURL: "https://login.algorea.org"
+        clientID: "1"
+        clientSecret: "tzxsLyFtJiGnmD6sjZMqSEidVpVsL3hEoSxIXCpI"
+      """
+    When I send a POST request to "/auth/token?code=somecode&provider=oidc"
+    Then the response code should be 400
+    And the response error message should contain "No OpenID Connect identity provider is configured"
+    And the table "users" should stay unchanged
+    And the table "sessions" should stay unchanged
+    And the table "access_tokens" should stay unchanged
+
+  Scenario: The ID token given by the OpenID Connect identity provider is invalid
+    Given the application config is:
+      """
+      auth:
+        oidc:
+          issuer: "https://sso.example.org"
+          clientID: "algorea"
+      """
+    And the OIDC provider "token" endpoint for code "somecode" returns the refresh token "oidcrefreshtoken" and an ID token with claims:
+      """
+      {"sub": "user-1", "aud": "another_client", "preferred_username": "jdoe"}
+      """
+    When I send a POST request to "/auth/token?code=somecode&provider=oidc"
+    Then the response code should be 401
+    And the response error message should contain "Invalid user profile: the ID token is not issued for this client"
+    And the table "users" should stay unchanged
+    And the table "user_identities" should stay unchanged
+    And the table "sessions" should stay unchanged
+    And the table "access_tokens" should stay unchanged
+
+  Scenario: The login given by the OpenID Connect identity provider is already used by another user
+    Given the application config is:
+      """
+      auth:
+        oidc:
+          issuer: "https://sso.example.org"
+          clientID: "algorea"
+      domains:
+        -
+          domains: [127.0.0.1]
+          allUsersGroup: 2
+          TempUsersGroup: 4
+      """
+    And the database has the following table "groups":
+      | id | name      | type |
+      | 2  | AllUsers  | Base |
+      | 4  | TempUsers | Base |
+    And the database has the following users:
+      | group_id | login | login_id |
+      | 11       | jdoe  | 12345    |
+    And the OIDC provider "token" endpoint for code "somecode" returns the refresh token "oidcrefreshtoken" and an ID token with claims:
+      """
+      {"sub": "user-1", "preferred_username": "jdoe"}
+      """
+    When I send a POST request to "/auth/token?code=somecode&provider=oidc"
+    Then the response code should be 409
+    And the response error message should contain "The login is already used by another user"
+    And the table "users" should stay unchanged
+    And the table "groups" should stay unchanged
+    And the table "user_identities" should stay unchanged
+    And the table "sessions" should stay unchanged
+    And the table "access_tokens" should stay unchanged
@@ -0,0 +1,47 @@
+Feature: Respond to an LTI deep linking request
+  Background:
+    Given the application config is:
+      """
+      auth:
+        lti:
+          keyID: "algorea"
+          launchURL: "https://backend.algorea.org/auth/lti/launch"
+          frontendURL: "https://app.algorea.org/lti"
+          platforms:
+            - issuer: "https://moodle.example.org"
+              clientID: "algorea-tool"
+              deploymentIDs: ["1"]
+              authLoginURL: "https://moodle.example.org/mod/lti/auth.php"
+              jwksURL: "https://moodle.example.org/mod/lti/certs.php"
+      """
+    And the database has the following user:
+      | group_id | login        |
+      | 11       | lti-12345678 |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | default_language_tag |
+      | 50 | fr                   |
+      | 51 | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated |
+      | 11       | 50      | info               |
+      | 11       | 51      | content            |
+    And the DB time now is "2019-07-16 22:02:28"
+    And the database has the following table "lti_deep_linking_requests":
+      | id  | user_id | issuer                     | client_id    | deployment_id | return_url                                                | data   | expires_at          |
+      | 100 | 11      | https://moodle.example.org | algorea-tool | 1             | https://moodle.example.org/mod/lti/contentitem_return.php | opaque | 2019-07-16 23:00:00 |
+      | 101 | 11      | https://moodle.example.org | algorea-tool | 1             | https://moodle.example.org/mod/lti/contentitem_return.php | null   | 2019-07-16 23:00:00 |
+
+  Scenario: Respond with the selected items
+    Given I am the user with id "11"
+    When I send a POST request to "/auth/lti/deep-linking-requests/100/response" with the following body:
+      """
+      {"items": [{"item_id": "50", "title": "Algorithms"}, {"item_id": "51"}]}
+      """
+    Then the response code should be 201
+    And the response at $.success should be "true"
+    And the response at $.message should be "created"
+    And the response at $.data.return_url should be "https://moodle.example.org/mod/lti/contentitem_return.php"
+    And the table "lti_deep_linking_requests" should be:
+      | id  |
+      | 101 |
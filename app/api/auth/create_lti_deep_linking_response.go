@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/lti"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+	"github.com/France-ioi/AlgoreaBackend/v2/golang"
+)
+
+type ltiDeepLinkingContentItem struct {
+	// required: true
+	ItemID int64 `json:"item_id,string" validate:"set"`
+	// The title of the resource link created at the platform
+	Title string `json:"title" validate:"max=255"`
+}
+
+// swagger:model createLTIDeepLinkingResponseRequest
+type createLTIDeepLinkingResponseRequest struct {
+	// required: true
+	// minItems: 1
+	// maxItems: 100
+	Items []ltiDeepLinkingContentItem `json:"items" validate:"set,min=1,max=100,dive"`
+}
+
+// swagger:model createLTIDeepLinkingResponseResponse
+type createLTIDeepLinkingResponseResponse struct {
+	// The URL of the platform the JWT should be posted to (as the `JWT` form parameter)
+	// required: true
+	ReturnURL string `json:"return_url"`
+	// The deep linking response signed by the backend
+	// required: true
+	JWT string `json:"jwt"`
+}
+
+// swagger:operation POST /auth/lti/deep-linking-requests/{request_id}/response auth ltiDeepLinkingResponseCreate
+//
+//	---
+//	summary: Respond to an LTI deep linking request
+//	description: >
+//
+//		Generates the response to a deep linking request of an LTI platform (stored by `POST /auth/lti/launch`)
+//		with the items selected by the current user. Each item becomes a resource link of the platform
+//		launching the tool with the `item_id` custom parameter.
+//
+//		The frontend is expected to post the returned `jwt` to the `return_url` (as the `JWT` form parameter)
+//		from the browser of the user. The request is deleted once responded.
+//
+//
+//		Restrictions:
+//
+//		* the deep linking request should have been launched by the current user and should not be expired (1 hour),
+//		* the current user should have `can_view` >= 'info' on the items,
+//
+//		otherwise the 'forbidden' error is returned.
+//	parameters:
+//		- name: request_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- in: body
+//			name: data
+//			required: true
+//			description: The selected items
+//			schema:
+//				"$ref": "#/definitions/createLTIDeepLinkingResponseRequest"
+//	responses:
+//		"201":
+//			description: Created. The deep linking response has been generated.
+//			schema:
+//				type: object
+//				required: [success, message, data]
+//				properties:
+//					success:
+//						description: "true"
+//						type: boolean
+//						enum: [true]
+//					message:
+//						description: created
+//						type: string
+//						enum: [created]
+//					data:
+//						"$ref": "#/definitions/createLTIDeepLinkingResponseResponse"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) createLTIDeepLinkingResponse(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	requestID, err := service.ResolveURLQueryPathInt64Field(r, "request_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	ltiConfig, err := auth.GetLTIConfig(srv.AuthConfig)
+	service.MustNotBeError(err)
+	if ltiConfig == nil {
+		return service.ErrInvalidRequest(errors.New("LTI is not configured"))
+	}
+
+	input := createLTIDeepLinkingResponseRequest{}
+	formData := formdata.NewFormData(&input)
+	if err = formData.ParseJSONRequestData(r); err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	itemIDs := golang.NewSet[int64]()
+	for _, item := range input.Items {
+		itemIDs.Add(item.ItemID)
+	}
+	var visibleItemsCount int64
+	service.MustNotBeError(store.Permissions().MatchingUserAncestors(user).
+		WherePermissionIsAtLeast("view", "info").
+		Where("item_id IN (?)", itemIDs.Values()).
+		PluckFirst("COUNT(DISTINCT item_id)", &visibleItemsCount).Error())
+	if visibleItemsCount != int64(itemIDs.Size()) {
+		return service.InsufficientAccessRightsError
+	}
+
+	var response createLTIDeepLinkingResponseResponse
+	apiError := service.NoError
+	err = store.InTransaction(func(store *database.DataStore) error {
+		var request struct {
+			Issuer       string
+			ClientID     string
+			DeploymentID string
+			ReturnURL    string
+			Data         *string
+		}
+		err := store.LTIDeepLinkingRequests().WithExclusiveWriteLock().
+			Where("id = ? AND user_id = ? AND expires_at > NOW()", requestID, user.GroupID).
+			Select("issuer, client_id, deployment_id, return_url, data").Take(&request).Error()
+		if gorm.IsRecordNotFoundError(err) {
+			apiError = service.InsufficientAccessRightsError
+			return apiError.Error // rollback
+		}
+		service.MustNotBeError(err)
+
+		platform := ltiConfig.Platform(request.Issuer, request.ClientID)
+		if platform == nil {
+			apiError = service.ErrInvalidRequest(errors.New("unknown LTI platform"))
+			return apiError.Error // rollback
+		}
+
+		deepLinkingResponse := &lti.DeepLinkingResponse{
+			Platform:     platform,
+			DeploymentID: request.DeploymentID,
+			Items:        make([]lti.ContentItem, 0, len(input.Items)),
+		}
+		if request.Data != nil {
+			deepLinkingResponse.Data = *request.Data
+		}
+		for _, item := range input.Items {
+			deepLinkingResponse.Items = append(deepLinkingResponse.Items, lti.ContentItem{ItemID: item.ItemID, Title: item.Title})
+		}
+		response.JWT, err = deepLinkingResponse.Sign(ltiConfig, srv.TokenConfig.PrivateKey)
+		service.MustNotBeError(err)
+		response.ReturnURL = request.ReturnURL
+
+		return store.LTIDeepLinkingRequests().Where("id = ?", requestID).Delete().Error()
+	})
+	if apiError != service.NoError {
+		return apiError
+	}
+	service.MustNotBeError(err)
+
+	service.MustNotBeError(render.Render(w, r, service.CreationSuccess(&response)))
+	return service.NoError
+}
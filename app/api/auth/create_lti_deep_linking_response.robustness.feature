@@ -0,0 +1,120 @@
+Feature: Respond to an LTI deep linking request - robustness
+  Background:
+    Given the application config is:
+      """
+      auth:
+        lti:
+          keyID: "algorea"
+          launchURL: "https://backend.algorea.org/auth/lti/launch"
+          frontendURL: "https://app.algorea.org/lti"
+          platforms:
+            - issuer: "https://moodle.example.org"
+              clientID: "algorea-tool"
+              deploymentIDs: ["1"]
+              authLoginURL: "https://moodle.example.org/mod/lti/auth.php"
+              jwksURL: "https://moodle.example.org/mod/lti/certs.php"
+      """
+    And the database has the following users:
+      | group_id | login        |
+      | 11       | lti-12345678 |
+      | 12       | lti-87654321 |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | default_language_tag |
+      | 50 | fr                   |
+      | 51 | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated |
+      | 11       | 50      | info               |
+      | 11       | 51      | none               |
+    And the DB time now is "2019-07-16 22:02:28"
+    And the database has the following table "lti_deep_linking_requests":
+      | id  | user_id | issuer                     | client_id    | deployment_id | return_url                                                | expires_at          |
+      | 100 | 11      | https://moodle.example.org | algorea-tool | 1             | https://moodle.example.org/mod/lti/contentitem_return.php | 2019-07-16 23:00:00 |
+      | 101 | 11      | https://moodle.example.org | algorea-tool | 1             | https://moodle.example.org/mod/lti/contentitem_return.php | 2019-07-16 22:00:00 |
+      | 102 | 12      | https://moodle.example.org | algorea-tool | 1             | https://moodle.example.org/mod/lti/contentitem_return.php | 2019-07-16 23:00:00 |
+      | 103 | 11      | https://canvas.example.org | algorea-tool | 1             | https://canvas.example.org/deep_linking_response          | 2019-07-16 23:00:00 |
+
+  Scenario: Wrong request_id
+    Given I am the user with id "11"
+    When I send a POST request to "/auth/lti/deep-linking-requests/abc/response" with the following body:
+      """
+      {"items": [{"item_id": "50"}]}
+      """
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for request_id (should be int64)"
+    And the table "lti_deep_linking_requests" should stay unchanged
+
+  Scenario: LTI is not configured
+    Given the application config is:
+      """
+      auth:
+        loginModuleURL: "https://login.algorea.org"
+      """
+    And I am the user with id "11"
+    When I send a POST request to "/auth/lti/deep-linking-requests/100/response" with the following body:
+      """
+      {"items": [{"item_id": "50"}]}
+      """
+    Then the response code should be 400
+    And the response error message should contain "LTI is not configured"
+    And the table "lti_deep_linking_requests" should stay unchanged
+
+  Scenario Outline: Invalid items
+    Given I am the user with id "11"
+    When I send a POST request to "/auth/lti/deep-linking-requests/100/response" with the following body:
+      """
+      <body>
+      """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "Bad Request",
+        "error_code": "invalid_input_data",
+        "error_text": "Invalid input data",
+        "errors": <errors>
+      }
+      """
+    And the table "lti_deep_linking_requests" should stay unchanged
+  Examples:
+    | body                        | errors                                            |
+    | {}                          | {"items": ["missing field"]}                      |
+    | {"items": []}               | {"items": ["items must contain at least 1 item"]} |
+    | {"items": [{"title": "a"}]} | {"items[0].item_id": ["missing field"]}           |
+
+  Scenario: The item is not visible to the user
+    Given I am the user with id "11"
+    When I send a POST request to "/auth/lti/deep-linking-requests/100/response" with the following body:
+      """
+      {"items": [{"item_id": "50"}, {"item_id": "51"}]}
+      """
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "lti_deep_linking_requests" should stay unchanged
+
+  Scenario Outline: The request should be the user's and should not be expired
+    Given I am the user with id "11"
+    When I send a POST request to "/auth/lti/deep-linking-requests/<request_id>/response" with the following body:
+      """
+      {"items": [{"item_id": "50"}]}
+      """
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "lti_deep_linking_requests" should stay unchanged
+  Examples:
+    | request_id |
+    | 101        |
+    | 102        |
+    | 404        |
+
+  Scenario: The platform of the request is not configured anymore
+    Given I am the user with id "11"
+    When I send a POST request to "/auth/lti/deep-linking-requests/103/response" with the following body:
+      """
+      {"items": [{"item_id": "50"}]}
+      """
+    Then the response code should be 400
+    And the response error message should contain "Unknown LTI platform"
+    And the table "lti_deep_linking_requests" should stay unchanged
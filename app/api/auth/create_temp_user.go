@@ -60,6 +60,8 @@ import (
 //			"$ref": "#/responses/badRequestResponse"
 //		"408":
 //			"$ref": "#/responses/requestTimeoutResponse"
+//		"429":
+//			"$ref": "#/responses/tooManyRequestsResponse"
 //		"500":
 //			"$ref": "#/responses/internalErrorResponse"
 func (srv *Service) createTempUser(w http.ResponseWriter, r *http.Request) service.APIError {
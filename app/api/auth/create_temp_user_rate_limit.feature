@@ -0,0 +1,33 @@
+Feature: Create a temporary user - rate limit
+  Background:
+    Given the application config is:
+      """
+      server:
+        rateLimits:
+          temp-user: {limit: 1, period: 1h, key: user}
+      domains:
+        -
+          domains: [127.0.0.1]
+          allUsersGroup: 2
+          tempUsersGroup: 4
+      """
+    And the database has the following table "groups":
+      | id | name      | type | text_id   |
+      | 2  | AllUsers  | Base | AllUsers  |
+      | 4  | TempUsers | User | TempUsers |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 2               | 4              |
+    And the time now is "2020-07-16T22:02:28Z"
+
+  Scenario: Anonymous requests are limited per IP address
+    When I send a POST request to "/auth/temp-user"
+    Then the response code should be 201
+    And the response header "RateLimit-Remaining" should be "0"
+    When I send a POST request to "/auth/temp-user"
+    Then the response code should be 429
+    And the response error message should contain "Too many requests, retry later"
+    And the response header "Retry-After" should be "3600"
+    And the table "users" should be:
+      | group_id            | temp_user |
+      | 5577006791947779410 | true      |
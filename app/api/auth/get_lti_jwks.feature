@@ -0,0 +1,22 @@
+Feature: Get the key set of the LTI tool
+  Scenario: Get the key set
+    Given the application config is:
+      """
+      auth:
+        lti:
+          keyID: "algorea"
+          launchURL: "https://backend.algorea.org/auth/lti/launch"
+          frontendURL: "https://app.algorea.org/lti"
+          platforms:
+            - issuer: "https://moodle.example.org"
+              clientID: "algorea-tool"
+              authLoginURL: "https://moodle.example.org/mod/lti/auth.php"
+              jwksURL: "https://moodle.example.org/mod/lti/certs.php"
+      """
+    When I send a GET request to "/auth/lti/jwks"
+    Then the response code should be 200
+    And the response at $.keys[0].kty should be "RSA"
+    And the response at $.keys[0].kid should be "algorea"
+    And the response at $.keys[0].use should be "sig"
+    And the response at $.keys[0].alg should be "RS256"
+    And the response at $.keys[0].e should be "AQAB"
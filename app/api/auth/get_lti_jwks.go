@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/lti"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:operation GET /auth/lti/jwks auth ltiJWKSGet
+//
+//	---
+//	summary: Get the key set of the LTI tool
+//	description: >
+//
+//		Returns the public key set (JWKS) of the backend as an LTI 1.3 tool,
+//		so the platforms can verify the messages signed by the backend
+//		(deep linking responses and client assertions of the Assignment and Grade Services).
+//
+//
+//		LTI should be configured for the backend, otherwise the 'bad request' error is returned.
+//	security: []
+//	responses:
+//		"200":
+//			description: OK. The key set
+//			schema:
+//				type: object
+//				required: [keys]
+//				properties:
+//					keys:
+//						type: array
+//						items:
+//							type: object
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getLTIJWKS(w http.ResponseWriter, r *http.Request) service.APIError {
+	ltiConfig, err := auth.GetLTIConfig(srv.AuthConfig)
+	service.MustNotBeError(err)
+	if ltiConfig == nil {
+		return service.ErrInvalidRequest(errors.New("LTI is not configured"))
+	}
+
+	render.Respond(w, r, lti.KeySet(srv.TokenConfig.PublicKey, ltiConfig.KeyID))
+	return service.NoError
+}
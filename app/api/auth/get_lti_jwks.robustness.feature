@@ -0,0 +1,10 @@
+Feature: Get the key set of the LTI tool - robustness
+  Scenario: LTI is not configured
+    Given the application config is:
+      """
+      auth:
+        loginModuleURL: "https://login.algorea.org"
+      """
+    When I send a GET request to "/auth/lti/jwks"
+    Then the response code should be 400
+    And the response error message should contain "LTI is not configured"
@@ -0,0 +1,183 @@
+Feature: Launch the tool from an LTI 1.3 platform
+  Background:
+    Given the application config is:
+      """
+      auth:
+        loginModuleURL: "https://login.algorea.org"
+        clientID: "1"
+        clientSecret: "tzxsLyFtJiGnmD6sjZMqSEidVpVsL3hEoSxIXCpI"
+        lti:
+          keyID: "algorea"
+          launchURL: "https://backend.algorea.org/auth/lti/launch"
+          frontendURL: "https://app.algorea.org/lti"
+          platforms:
+            - issuer: "https://moodle.example.org"
+              clientID: "algorea-tool"
+              deploymentIDs: ["1"]
+              authLoginURL: "https://moodle.example.org/mod/lti/auth.php"
+              accessTokenURL: "https://moodle.example.org/mod/lti/token.php"
+              jwksURL: "https://moodle.example.org/mod/lti/certs.php"
+      domains:
+        -
+          domains: [127.0.0.1]
+          allUsersGroup: 2
+          TempUsersGroup: 4
+      """
+    And the database has the following table "groups":
+      | id | name     | type  | text_id  |
+      | 2  | AllUsers | Base  | AllUsers |
+      | 4  | TmpUsers | Base  | TmpUsers |
+      | 30 | Course 1 | Class | null     |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 2               | 4              |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | default_language_tag |
+      | 50 | fr                   |
+    And the database has the following table "lti_contexts":
+      | issuer                     | deployment_id | context_id | group_id |
+      | https://moodle.example.org | 1             | course1    | 30       |
+    And the DB time now is "2019-07-16 22:02:28"
+    And the database has the following table "lti_launch_states":
+      | state                            | nonce  | issuer                     | client_id    | expires_at          |
+      | 5ci5tt3gk9nnt9mkqvgnb2kj0pydrqk4 | nonce1 | https://moodle.example.org | algorea-tool | 2019-07-16 22:10:00 |
+      | expiredexpiredexpiredexpiredexpi | nonce2 | https://moodle.example.org | algorea-tool | 2019-07-16 22:00:00 |
+    And the generated auth key is "ny93zqri9a2adn4v1ut6izd76xb3pccw"
+    And the "Content-Type" request header is "application/x-www-form-urlencoded"
+
+  Scenario: Create a new user launching an item from a course
+    Given "launch" is an LTI launch signed by the platform "https://moodle.example.org" with the following claims:
+      """
+      {
+        "sub": "moodle-user-1", "nonce": "nonce1", "given_name": "John", "family_name": "Doe", "email": "jdoe@example.org",
+        "https://purl.imsglobal.org/spec/lti/claim/message_type": "LtiResourceLinkRequest",
+        "https://purl.imsglobal.org/spec/lti/claim/deployment_id": "1",
+        "https://purl.imsglobal.org/spec/lti/claim/resource_link": {"id": "link1"},
+        "https://purl.imsglobal.org/spec/lti/claim/roles": ["http://purl.imsglobal.org/vocab/lis/v2/membership#Learner"],
+        "https://purl.imsglobal.org/spec/lti/claim/context": {"id": "course1", "title": "Course 1"},
+        "https://purl.imsglobal.org/spec/lti/claim/custom": {"item_id": "50"},
+        "https://purl.imsglobal.org/spec/lti-ags/claim/endpoint": {
+          "scope": ["https://purl.imsglobal.org/spec/lti-ags/scope/score"],
+          "lineitem": "https://moodle.example.org/mod/lti/services.php/2/lineitems/7/lineitem"
+        }
+      }
+      """
+    When I send a POST request to "/auth/lti/launch" with the following body:
+      """
+      id_token={{launch}}&state=5ci5tt3gk9nnt9mkqvgnb2kj0pydrqk4
+      """
+    Then the response code should be 303
+    And the response header "Location" should be "https://app.algorea.org/lti?item_id=50#access_token=ny93zqri9a2adn4v1ut6izd76xb3pccw&expires_in=7200"
+    And the table "users" should be:
+      | group_id            | login        | login_id | temp_user | first_name | last_name | email            | latest_login_at     | registered_at       | last_ip   |
+      | 5577006791947779410 | lti-49727887 | null     | 0         | John       | Doe       | jdoe@example.org | 2019-07-16 22:02:28 | 2019-07-16 22:02:28 | 127.0.0.1 |
+    And the table "user_identities" should be:
+      | issuer                     | subject       | user_id             |
+      | https://moodle.example.org | moodle-user-1 | 5577006791947779410 |
+    And the table "groups" should stay unchanged but the row with id "5577006791947779410"
+    And the table "groups" at id "5577006791947779410" should be:
+      | id                  | name         | type | description  |
+      | 5577006791947779410 | lti-49727887 | User | lti-49727887 |
+    And the table "groups_groups" should be:
+      | parent_group_id | child_group_id      |
+      | 2               | 4                   |
+      | 2               | 5577006791947779410 |
+      | 30              | 5577006791947779410 |
+    And the table "group_membership_changes" should be:
+      | group_id | member_id           | action        | initiator_id        | ABS(TIMESTAMPDIFF(SECOND, at, NOW())) < 3 |
+      | 30       | 5577006791947779410 | joined_by_lti | 5577006791947779410 | 1                                         |
+    And the table "lti_contexts" should stay unchanged
+    And the table "lti_line_items" should be:
+      | participant_id      | item_id | issuer                     | client_id    | subject       | line_item_url                                                          |
+      | 5577006791947779410 | 50      | https://moodle.example.org | algorea-tool | moodle-user-1 | https://moodle.example.org/mod/lti/services.php/2/lineitems/7/lineitem |
+    And the table "lti_launch_states" should be:
+      | state                            |
+      | expiredexpiredexpiredexpiredexpi |
+    And the table "sessions" should be:
+      | session_id          | user_id             | refresh_token | identity_provider |
+      | 6129484611666145821 | 5577006791947779410 | null          | lti               |
+    And the table "access_tokens" should be:
+      | session_id          | token                            | expires_at          |
+      | 6129484611666145821 | ny93zqri9a2adn4v1ut6izd76xb3pccw | 2019-07-17 00:02:28 |
+    And the table "attempts" should be:
+      | participant_id      | id | creator_id          |
+      | 5577006791947779410 | 0  | 5577006791947779410 |
+
+  Scenario: An existing user launching from a new course as an instructor becomes a manager of the course group
+    Given the database has the following users:
+      | group_id | login        | temp_user | first_name | last_name | email            |
+      | 11       | lti-12345678 | 0         | Johnny     | Doe       | jdoe@example.com |
+    And the database has the following table "user_identities":
+      | issuer                     | subject       | user_id |
+      | https://moodle.example.org | moodle-user-1 | 11      |
+    And "launch" is an LTI launch signed by the platform "https://moodle.example.org" with the following claims:
+      """
+      {
+        "sub": "moodle-user-1", "nonce": "nonce1", "given_name": "John",
+        "https://purl.imsglobal.org/spec/lti/claim/message_type": "LtiResourceLinkRequest",
+        "https://purl.imsglobal.org/spec/lti/claim/deployment_id": "1",
+        "https://purl.imsglobal.org/spec/lti/claim/resource_link": {"id": "link1"},
+        "https://purl.imsglobal.org/spec/lti/claim/roles": ["http://purl.imsglobal.org/vocab/lis/v2/membership#Instructor"],
+        "https://purl.imsglobal.org/spec/lti/claim/context": {"id": "course2", "label": "C2"}
+      }
+      """
+    When I send a POST request to "/auth/lti/launch" with the following body:
+      """
+      id_token={{launch}}&state=5ci5tt3gk9nnt9mkqvgnb2kj0pydrqk4
+      """
+    Then the response code should be 303
+    And the response header "Location" should be "https://app.algorea.org/lti#access_token=ny93zqri9a2adn4v1ut6izd76xb3pccw&expires_in=7200"
+    And the table "users" should be:
+      | group_id | login        | temp_user | first_name | last_name | email            | latest_login_at     | last_ip   |
+      | 11       | lti-12345678 | 0         | John       | Doe       | jdoe@example.com | 2019-07-16 22:02:28 | 127.0.0.1 |
+    And the table "user_identities" should stay unchanged
+    And the table "groups" should stay unchanged but the row with id "5577006791947779410"
+    And the table "groups" at id "5577006791947779410" should be:
+      | id                  | name | type  |
+      | 5577006791947779410 | C2   | Class |
+    And the table "lti_contexts" should be:
+      | issuer                     | deployment_id | context_id | group_id            |
+      | https://moodle.example.org | 1             | course1    | 30                  |
+      | https://moodle.example.org | 1             | course2    | 5577006791947779410 |
+    And the table "group_managers" should be:
+      | group_id            | manager_id | can_manage  | can_grant_group_access | can_watch_members |
+      | 5577006791947779410 | 11         | memberships | 1                      | 1                 |
+    And the table "groups_groups" should stay unchanged
+    And the table "group_membership_changes" should be empty
+    And the table "lti_line_items" should be empty
+    And the table "sessions" should be:
+      | session_id          | user_id | identity_provider |
+      | 8674665223082153551 | 11      | lti               |
+
+  Scenario: Store a deep linking request
+    Given the database has the following users:
+      | group_id | login        | temp_user |
+      | 11       | lti-12345678 | 0         |
+    And the database has the following table "user_identities":
+      | issuer                     | subject       | user_id |
+      | https://moodle.example.org | moodle-user-1 | 11      |
+    And "launch" is an LTI launch signed by the platform "https://moodle.example.org" with the following claims:
+      """
+      {
+        "sub": "moodle-user-1", "nonce": "nonce1",
+        "https://purl.imsglobal.org/spec/lti/claim/message_type": "LtiDeepLinkingRequest",
+        "https://purl.imsglobal.org/spec/lti/claim/deployment_id": "1",
+        "https://purl.imsglobal.org/spec/lti/claim/roles": ["http://purl.imsglobal.org/vocab/lis/v2/membership#Instructor"],
+        "https://purl.imsglobal.org/spec/lti-dl/claim/deep_linking_settings": {
+          "deep_link_return_url": "https://moodle.example.org/mod/lti/contentitem_return.php", "data": "opaque"
+        }
+      }
+      """
+    When I send a POST request to "/auth/lti/launch" with the following body:
+      """
+      id_token={{launch}}&state=5ci5tt3gk9nnt9mkqvgnb2kj0pydrqk4
+      """
+    Then the response code should be 303
+    And the response header "Location" should be "https://app.algorea.org/lti?deep_linking_request_id=5577006791947779410#access_token=ny93zqri9a2adn4v1ut6izd76xb3pccw&expires_in=7200"
+    And the table "lti_deep_linking_requests" should be:
+      | id                  | user_id | issuer                     | client_id    | deployment_id | return_url                                                | data   | expires_at          |
+      | 5577006791947779410 | 11      | https://moodle.example.org | algorea-tool | 1             | https://moodle.example.org/mod/lti/contentitem_return.php | opaque | 2019-07-16 23:02:28 |
+    And the table "sessions" should be:
+      | session_id          | user_id | identity_provider |
+      | 8674665223082153551 | 11      | lti               |
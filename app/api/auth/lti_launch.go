@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/domain"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/lti"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/rand"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// ltiDeepLinkingRequestLifetimeInSeconds is the time given to an instructor to select items for a deep linking request.
+const ltiDeepLinkingRequestLifetimeInSeconds = 3600 // 1 hour
+
+// swagger:operation POST /auth/lti/launch auth ltiLaunch
+//
+//	---
+//	summary: Launch the tool from an LTI 1.3 platform
+//	description: >
+//
+//		The launch endpoint (the redirect URI) of the backend as an LTI 1.3 tool.
+//		The platform posts the signed launch (`{id_token}`) together with the `{state}`
+//		generated by `GET /auth/lti/login`.
+//
+//		The service verifies the launch and then:
+//
+//		* creates a user (with a generated login) on the first launch of the platform user,
+//			or updates the names and the email of the user (the users are linked to the platform users
+//			by the issuer of the platform and the subject of the launch);
+//
+//		* if the launch comes from a context (e.g., a course), makes the user a member of the group
+//			of the context (a group of type "Class" created on the first launch from the context)
+//			with the 'joined_by_lti' action, or a manager of the group if the user is an instructor
+//			or an administrator of the context;
+//
+//		* if the resource link has the `item_id` custom parameter and the platform provides a line item
+//			of its gradebook, stores the line item so the scores of the user on the item are published
+//			to the platform (see `POST /items/{item_id}/attempts/{attempt_id}/publish`);
+//
+//		* for a deep linking request, stores the request so the user can select items
+//			with `POST /auth/lti/deep-linking-requests/{request_id}/response`;
+//
+//		* creates a session (its access token is valid for 2 hours and gets refreshed locally)
+//			and redirects the user to the frontend with `item_id` or `deep_linking_request_id` in the query
+//			and the `access_token` & `expires_in` in the fragment of the URL.
+//
+//
+//		Validations:
+//			* LTI should be configured for the backend;
+//			* the `{state}` should have been generated by `GET /auth/lti/login` and should not be expired (10 minutes);
+//			* the launch should be signed by the platform and valid (otherwise, the 'unauthorized' error is returned);
+//			* the `item_id` custom parameter (if given) should be the id of an existing item.
+//	security: []
+//	consumes:
+//		- application/x-www-form-urlencoded
+//	parameters:
+//		- name: id_token
+//			in: formData
+//			description: The launch signed by the platform (JWT)
+//			type: string
+//			required: true
+//		- name: state
+//			in: formData
+//			description: The state generated on the login initiation
+//			type: string
+//			required: true
+//	responses:
+//		"303":
+//			description: See other. Redirect to the frontend.
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) launchLTI(w http.ResponseWriter, r *http.Request) service.APIError {
+	ltiConfig, err := auth.GetLTIConfig(srv.AuthConfig)
+	service.MustNotBeError(err)
+	if ltiConfig == nil {
+		return service.ErrInvalidRequest(errors.New("LTI is not configured"))
+	}
+
+	if err = r.ParseForm(); err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+	rawIDToken := r.PostForm.Get("id_token")
+	state := r.PostForm.Get("state")
+	if rawIDToken == "" || state == "" {
+		return service.ErrInvalidRequest(errors.New("the id_token and state parameters are required"))
+	}
+
+	store := srv.GetStore(r)
+	var launchState struct {
+		Nonce    string
+		Issuer   string
+		ClientID string
+	}
+	apiError := service.NoError
+	err = store.InTransaction(func(store *database.DataStore) error {
+		err := store.LTILaunchStates().WithExclusiveWriteLock().
+			Where("state = ? AND expires_at > NOW()", state).
+			Select("nonce, issuer, client_id").Take(&launchState).Error()
+		if gorm.IsRecordNotFoundError(err) {
+			apiError = service.ErrInvalidRequest(errors.New("unknown or expired state"))
+			return apiError.Error // rollback
+		}
+		service.MustNotBeError(err)
+		return store.LTILaunchStates().Where("state = ?", state).Delete().Error()
+	})
+	if apiError != service.NoError {
+		return apiError
+	}
+	service.MustNotBeError(err)
+
+	platform := ltiConfig.Platform(launchState.Issuer, launchState.ClientID)
+	if platform == nil {
+		return service.ErrInvalidRequest(errors.New("unknown LTI platform"))
+	}
+	launch, err := lti.ValidateLaunch(r.Context(), srv.LTIKeySets, platform, rawIDToken, launchState.Nonce)
+	if err != nil {
+		return service.APIError{HTTPStatusCode: http.StatusUnauthorized, Error: fmt.Errorf("invalid LTI launch: %w", err)}
+	}
+
+	itemID, itemIDGiven, err := launch.ItemID()
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+	if itemIDGiven {
+		found, err := store.Items().ByID(itemID).HasRows()
+		service.MustNotBeError(err)
+		if !found {
+			return service.ErrInvalidRequest(errors.New("unknown item_id"))
+		}
+	}
+
+	domainConfig := domain.ConfigFromContext(r.Context())
+	lastIP := strings.SplitN(r.RemoteAddr, ":", 2)[0]
+
+	var accessToken string
+	var expiresIn int32
+	var deepLinkingRequestID int64
+	service.MustNotBeError(store.InTransaction(func(store *database.DataStore) error {
+		userID := createOrUpdateLTIUser(store, platform.Issuer, launch, domainConfig, lastIP)
+		logging.LogEntrySetField(r, "user_id", userID)
+
+		if launch.ContextID != "" {
+			_, err := store.LTIContexts().JoinGroupOfContext(&database.LTIContext{
+				Issuer:       platform.Issuer,
+				DeploymentID: launch.DeploymentID,
+				ContextID:    launch.ContextID,
+				Title:        launch.ContextTitle,
+			}, userID, launch.IsInstructor())
+			service.MustNotBeError(err)
+		}
+
+		if itemIDGiven && launch.LineItemURL != "" {
+			service.MustNotBeError(store.LTILineItems().InsertOrUpdateMap(map[string]interface{}{
+				"participant_id": userID,
+				"item_id":        itemID,
+				"issuer":         platform.Issuer,
+				"client_id":      platform.ClientID,
+				"subject":        launch.Subject,
+				"line_item_url":  launch.LineItemURL,
+			}, []string{"subject", "line_item_url"}))
+		}
+
+		if launch.DeepLinking != nil {
+			deepLinkingRequestID = createLTIDeepLinkingRequest(store, userID, platform, launch)
+		}
+
+		var err error
+		accessToken, expiresIn, err = auth.CreateNewLTISession(store, userID)
+		service.MustNotBeError(err)
+
+		// Delete the oldest sessions of the user to keep a maximum of 10 sessions.
+		store.Sessions().DeleteOldSessionsToKeepMaximum(userID, 10)
+		return nil
+	}))
+
+	frontendURL, err := url.Parse(ltiConfig.FrontendURL)
+	service.MustNotBeError(err)
+	query := frontendURL.Query()
+	if deepLinkingRequestID != 0 {
+		query.Set("deep_linking_request_id", strconv.FormatInt(deepLinkingRequestID, 10))
+	} else if itemIDGiven {
+		query.Set("item_id", strconv.FormatInt(itemID, 10))
+	}
+	frontendURL.RawQuery = query.Encode()
+	frontendURL.Fragment = url.Values{
+		"access_token": {accessToken},
+		"expires_in":   {strconv.FormatInt(int64(expiresIn), 10)},
+	}.Encode()
+
+	http.Redirect(w, r, frontendURL.String(), http.StatusSeeOther)
+	return service.NoError
+}
+
+// createOrUpdateLTIUser returns the user linked to the platform user of the launch,
+// creating the user (with a generated login) on the first launch.
+func createOrUpdateLTIUser(
+	store *database.DataStore, issuer string, launch *lti.Launch, domainConfig *domain.CtxConfig, lastIP string,
+) int64 {
+	userData := map[string]interface{}{
+		"latest_login_at":    database.Now(),
+		"latest_activity_at": database.Now(),
+		"last_ip":            lastIP,
+	}
+	for column, value := range map[string]string{
+		"first_name": launch.GivenName, "last_name": launch.FamilyName, "email": launch.Email,
+	} {
+		if value != "" {
+			userData[column] = value
+		}
+	}
+
+	var userID int64
+	err := store.UserIdentities().WithExclusiveWriteLock().
+		Where("issuer = ? AND subject = ?", issuer, launch.Subject).PluckFirst("user_id", &userID).Error()
+	if !gorm.IsRecordNotFoundError(err) {
+		service.MustNotBeError(err)
+		service.MustNotBeError(store.Users().ByID(userID).UpdateColumn(userData).Error())
+		return userID
+	}
+
+	userID = createTempUserGroup(store)
+	var login string
+	service.MustNotBeError(store.RetryOnDuplicateKeyError("users", "login", "login", func(retryLoginStore *database.DataStore) error {
+		login = fmt.Sprintf("lti-%d", rand.Int31n(99999999-10000000+1)+10000000)
+		userData["login"] = login
+		userData["temp_user"] = false
+		userData["registered_at"] = database.Now()
+		userData["group_id"] = userID
+		return retryLoginStore.Users().InsertMap(userData)
+	}))
+	service.MustNotBeError(store.Groups().ByID(userID).UpdateColumn(map[string]interface{}{
+		"name":        login,
+		"description": login,
+	}).Error())
+	service.MustNotBeError(store.GroupGroups().CreateRelationsWithoutChecking([]map[string]interface{}{
+		{"parent_group_id": domainConfig.AllUsersGroupID, "child_group_id": userID},
+	}))
+	service.MustNotBeError(store.Attempts().InsertMap(map[string]interface{}{
+		"participant_id": userID,
+		"id":             0,
+		"creator_id":     userID,
+		"created_at":     database.Now(),
+	}))
+	service.MustNotBeError(store.UserIdentities().InsertMap(map[string]interface{}{
+		"issuer":  issuer,
+		"subject": launch.Subject,
+		"user_id": userID,
+	}))
+	return userID
+}
+
+func createLTIDeepLinkingRequest(
+	store *database.DataStore, userID int64, platform *lti.PlatformConfig, launch *lti.Launch,
+) (requestID int64) {
+	service.MustNotBeError(store.LTIDeepLinkingRequests().Where("expires_at <= NOW()").Delete().Error())
+
+	var data interface{}
+	if launch.DeepLinking.Data != "" {
+		data = launch.DeepLinking.Data
+	}
+	service.MustNotBeError(store.RetryOnDuplicatePrimaryKeyError("lti_deep_linking_requests",
+		func(retryIDStore *database.DataStore) error {
+			requestID = retryIDStore.NewID()
+			return retryIDStore.Exec(`
+				INSERT INTO lti_deep_linking_requests
+					(id, user_id, issuer, client_id, deployment_id, return_url, data, expires_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, NOW() + INTERVAL ? SECOND)`,
+				requestID, userID, platform.Issuer, platform.ClientID, launch.DeploymentID,
+				launch.DeepLinking.ReturnURL, data, ltiDeepLinkingRequestLifetimeInSeconds).Error()
+		}))
+	return requestID
+}
@@ -0,0 +1,142 @@
+Feature: Launch the tool from an LTI 1.3 platform - robustness
+  Background:
+    Given the application config is:
+      """
+      auth:
+        loginModuleURL: "https://login.algorea.org"
+        clientID: "1"
+        clientSecret: "tzxsLyFtJiGnmD6sjZMqSEidVpVsL3hEoSxIXCpI"
+        lti:
+          keyID: "algorea"
+          launchURL: "https://backend.algorea.org/auth/lti/launch"
+          frontendURL: "https://app.algorea.org/lti"
+          platforms:
+            - issuer: "https://moodle.example.org"
+              clientID: "algorea-tool"
+              deploymentIDs: ["1"]
+              authLoginURL: "https://moodle.example.org/mod/lti/auth.php"
+              accessTokenURL: "https://moodle.example.org/mod/lti/token.php"
+              jwksURL: "https://moodle.example.org/mod/lti/certs.php"
+      domains:
+        -
+          domains: [127.0.0.1]
+          allUsersGroup: 2
+          TempUsersGroup: 4
+      """
+    And the database has the following table "groups":
+      | id | name     | type | text_id  |
+      | 2  | AllUsers | Base | AllUsers |
+    And the database has the following table "items":
+      | id | default_language_tag |
+      | 50 | fr                   |
+    And the DB time now is "2019-07-16 22:02:28"
+    And the database has the following table "lti_launch_states":
+      | state                            | nonce  | issuer                     | client_id    | expires_at          |
+      | 5ci5tt3gk9nnt9mkqvgnb2kj0pydrqk4 | nonce1 | https://moodle.example.org | algorea-tool | 2019-07-16 22:10:00 |
+      | expiredexpiredexpiredexpiredexpi | nonce2 | https://moodle.example.org | algorea-tool | 2019-07-16 22:00:00 |
+    And the "Content-Type" request header is "application/x-www-form-urlencoded"
+
+  Scenario: LTI is not configured
+    Given the application config is:
+      """
+      auth:
+        loginModuleURL: "https://login.algorea.org"
+      """
+    When I send a POST request to "/auth/lti/launch" with the following body:
+      """
+      id_token=abcd&state=5ci5tt3gk9nnt9mkqvgnb2kj0pydrqk4
+      """
+    Then the response code should be 400
+    And the response error message should contain "LTI is not configured"
+    And the table "lti_launch_states" should stay unchanged
+
+  Scenario Outline: Missing parameters
+    When I send a POST request to "/auth/lti/launch" with the following body:
+      """
+      <body>
+      """
+    Then the response code should be 400
+    And the response error message should contain "The id_token and state parameters are required"
+    And the table "lti_launch_states" should stay unchanged
+  Examples:
+    | body                                   |
+    | id_token=abcd                          |
+    | state=5ci5tt3gk9nnt9mkqvgnb2kj0pydrqk4 |
+
+  Scenario Outline: Unknown or expired state
+    When I send a POST request to "/auth/lti/launch" with the following body:
+      """
+      id_token=abcd&state=<state>
+      """
+    Then the response code should be 400
+    And the response error message should contain "Unknown or expired state"
+    And the table "lti_launch_states" should stay unchanged
+  Examples:
+    | state                            |
+    | unknownunknownunknownunknownunkn |
+    | expiredexpiredexpiredexpiredexpi |
+
+  Scenario: Wrong nonce (the state is consumed anyway)
+    Given "launch" is an LTI launch signed by the platform "https://moodle.example.org" with the following claims:
+      """
+      {
+        "sub": "moodle-user-1", "nonce": "nonce2",
+        "https://purl.imsglobal.org/spec/lti/claim/message_type": "LtiResourceLinkRequest",
+        "https://purl.imsglobal.org/spec/lti/claim/deployment_id": "1",
+        "https://purl.imsglobal.org/spec/lti/claim/resource_link": {"id": "link1"}
+      }
+      """
+    When I send a POST request to "/auth/lti/launch" with the following body:
+      """
+      id_token={{launch}}&state=5ci5tt3gk9nnt9mkqvgnb2kj0pydrqk4
+      """
+    Then the response code should be 401
+    And the response error message should contain "Invalid LTI launch: wrong nonce of the launch"
+    And the table "lti_launch_states" should be:
+      | state                            |
+      | expiredexpiredexpiredexpiredexpi |
+    And the table "users" should be empty
+    And the table "sessions" should be empty
+
+  Scenario: Unknown deployment
+    Given "launch" is an LTI launch signed by the platform "https://moodle.example.org" with the following claims:
+      """
+      {
+        "sub": "moodle-user-1", "nonce": "nonce1",
+        "https://purl.imsglobal.org/spec/lti/claim/message_type": "LtiResourceLinkRequest",
+        "https://purl.imsglobal.org/spec/lti/claim/deployment_id": "2",
+        "https://purl.imsglobal.org/spec/lti/claim/resource_link": {"id": "link1"}
+      }
+      """
+    When I send a POST request to "/auth/lti/launch" with the following body:
+      """
+      id_token={{launch}}&state=5ci5tt3gk9nnt9mkqvgnb2kj0pydrqk4
+      """
+    Then the response code should be 401
+    And the response error message should contain "Invalid LTI launch: unknown deployment of the tool"
+    And the table "users" should be empty
+    And the table "sessions" should be empty
+
+  Scenario Outline: Wrong item_id
+    Given "launch" is an LTI launch signed by the platform "https://moodle.example.org" with the following claims:
+      """
+      {
+        "sub": "moodle-user-1", "nonce": "nonce1",
+        "https://purl.imsglobal.org/spec/lti/claim/message_type": "LtiResourceLinkRequest",
+        "https://purl.imsglobal.org/spec/lti/claim/deployment_id": "1",
+        "https://purl.imsglobal.org/spec/lti/claim/resource_link": {"id": "link1"},
+        "https://purl.imsglobal.org/spec/lti/claim/custom": {"item_id": "<item_id>"}
+      }
+      """
+    When I send a POST request to "/auth/lti/launch" with the following body:
+      """
+      id_token={{launch}}&state=5ci5tt3gk9nnt9mkqvgnb2kj0pydrqk4
+      """
+    Then the response code should be 400
+    And the response error message should contain "<expected_error>"
+    And the table "users" should be empty
+    And the table "sessions" should be empty
+  Examples:
+    | item_id | expected_error                              |
+    | abc     | Wrong value of the item_id custom parameter |
+    | 404     | Unknown item_id                             |
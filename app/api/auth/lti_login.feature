@@ -0,0 +1,45 @@
+Feature: Initiate an LTI 1.3 login
+  Background:
+    Given the application config is:
+      """
+      auth:
+        lti:
+          keyID: "algorea"
+          launchURL: "https://backend.algorea.org/auth/lti/launch"
+          frontendURL: "https://app.algorea.org/lti"
+          platforms:
+            - issuer: "https://moodle.example.org"
+              clientID: "algorea-tool"
+              deploymentIDs: ["1"]
+              authLoginURL: "https://moodle.example.org/mod/lti/auth.php"
+              accessTokenURL: "https://moodle.example.org/mod/lti/token.php"
+              jwksURL: "https://moodle.example.org/mod/lti/certs.php"
+      """
+    And the DB time now is "2019-07-16 22:02:28"
+    And the database has the following table "lti_launch_states":
+      | state                            | nonce  | issuer                     | client_id    | expires_at          |
+      | expiredexpiredexpiredexpiredexpi | nonce2 | https://moodle.example.org | algorea-tool | 2019-07-16 22:00:00 |
+      | validvalidvalidvalidvalidvalidva | nonce3 | https://moodle.example.org | algorea-tool | 2019-07-16 22:10:00 |
+    And the generated auth key is "ny93zqri9a2adn4v1ut6izd76xb3pccw"
+
+  Scenario: Initiate a login with GET
+    When I send a GET request to "/auth/lti/login?iss=https%3A%2F%2Fmoodle.example.org&login_hint=user1&lti_message_hint=msg1&target_link_uri=https%3A%2F%2Fbackend.algorea.org%2Fauth%2Flti%2Flaunch"
+    Then the response code should be 302
+    And the response header "Location" should be "https://moodle.example.org/mod/lti/auth.php?client_id=algorea-tool&login_hint=user1&lti_message_hint=msg1&nonce=ny93zqri9a2adn4v1ut6izd76xb3pccw&prompt=none&redirect_uri=https%3A%2F%2Fbackend.algorea.org%2Fauth%2Flti%2Flaunch&response_mode=form_post&response_type=id_token&scope=openid&state=ny93zqri9a2adn4v1ut6izd76xb3pccw"
+    And the table "lti_launch_states" should be:
+      | state                            | nonce                            | issuer                     | client_id    | expires_at          |
+      | ny93zqri9a2adn4v1ut6izd76xb3pccw | ny93zqri9a2adn4v1ut6izd76xb3pccw | https://moodle.example.org | algorea-tool | 2019-07-16 22:12:28 |
+      | validvalidvalidvalidvalidvalidva | nonce3                           | https://moodle.example.org | algorea-tool | 2019-07-16 22:10:00 |
+
+  Scenario: Initiate a login with POST
+    Given the "Content-Type" request header is "application/x-www-form-urlencoded"
+    When I send a POST request to "/auth/lti/login" with the following body:
+      """
+      iss=https%3A%2F%2Fmoodle.example.org&client_id=algorea-tool&login_hint=user1&target_link_uri=https%3A%2F%2Fbackend.algorea.org%2Fauth%2Flti%2Flaunch
+      """
+    Then the response code should be 302
+    And the response header "Location" should be "https://moodle.example.org/mod/lti/auth.php?client_id=algorea-tool&login_hint=user1&nonce=ny93zqri9a2adn4v1ut6izd76xb3pccw&prompt=none&redirect_uri=https%3A%2F%2Fbackend.algorea.org%2Fauth%2Flti%2Flaunch&response_mode=form_post&response_type=id_token&scope=openid&state=ny93zqri9a2adn4v1ut6izd76xb3pccw"
+    And the table "lti_launch_states" should be:
+      | state                            | nonce                            | issuer                     | client_id    | expires_at          |
+      | ny93zqri9a2adn4v1ut6izd76xb3pccw | ny93zqri9a2adn4v1ut6izd76xb3pccw | https://moodle.example.org | algorea-tool | 2019-07-16 22:12:28 |
+      | validvalidvalidvalidvalidvalidva | nonce3                           | https://moodle.example.org | algorea-tool | 2019-07-16 22:10:00 |
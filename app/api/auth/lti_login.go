@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// ltiLaunchStateLifetimeInSeconds is the time given to a platform to launch the tool after a login initiation.
+const ltiLaunchStateLifetimeInSeconds = 600 // 10 minutes
+
+// swagger:operation GET /auth/lti/login auth ltiLoginInitiate
+//
+//	---
+//	summary: Initiate an LTI 1.3 login
+//	description: >
+//
+//		The third-party login initiation endpoint of the backend as an LTI 1.3 tool.
+//		A platform calls it (with GET or POST) when a user launches the tool.
+//
+//		The service generates a state and a nonce for the launch and redirects the user to
+//		the OpenID Connect authentication endpoint of the platform which is expected to post
+//		the signed launch to `POST /auth/lti/launch`.
+//
+//		The parameters can be given in the query or in form data.
+//
+//
+//		Validations:
+//			* LTI should be configured for the backend;
+//			* the platform (`{iss}`, `{client_id}`) should be configured;
+//			* `{target_link_uri}` should be the launch URL of the tool.
+//	security: []
+//	parameters:
+//		- name: iss
+//			in: query
+//			description: Issuer of the platform
+//			type: string
+//			required: true
+//		- name: login_hint
+//			in: query
+//			description: Opaque value of the platform identifying the user
+//			type: string
+//			required: true
+//		- name: target_link_uri
+//			in: query
+//			description: URL the platform is going to launch
+//			type: string
+//			required: true
+//		- name: lti_message_hint
+//			in: query
+//			description: Opaque value of the platform identifying the message
+//			type: string
+//		- name: client_id
+//			in: query
+//			description: Client ID of the tool at the platform (required if the tool is registered several times at the platform)
+//			type: string
+//	responses:
+//		"302":
+//			description: Found. Redirect to the authentication endpoint of the platform.
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) initiateLTILogin(w http.ResponseWriter, r *http.Request) service.APIError {
+	ltiConfig, err := auth.GetLTIConfig(srv.AuthConfig)
+	service.MustNotBeError(err)
+	if ltiConfig == nil {
+		return service.ErrInvalidRequest(errors.New("LTI is not configured"))
+	}
+
+	if err = r.ParseForm(); err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+	issuer := r.Form.Get("iss")
+	loginHint := r.Form.Get("login_hint")
+	targetLinkURI := r.Form.Get("target_link_uri")
+	if issuer == "" || loginHint == "" || targetLinkURI == "" {
+		return service.ErrInvalidRequest(errors.New("the iss, login_hint, and target_link_uri parameters are required"))
+	}
+	platform := ltiConfig.Platform(issuer, r.Form.Get("client_id"))
+	if platform == nil {
+		return service.ErrInvalidRequest(errors.New("unknown LTI platform"))
+	}
+	if targetLinkURI != ltiConfig.LaunchURL {
+		return service.ErrInvalidRequest(errors.New("wrong target_link_uri"))
+	}
+
+	state, err := auth.GenerateKey()
+	service.MustNotBeError(err)
+	nonce, err := auth.GenerateKey()
+	service.MustNotBeError(err)
+
+	service.MustNotBeError(srv.GetStore(r).InTransaction(func(store *database.DataStore) error {
+		service.MustNotBeError(store.LTILaunchStates().Where("expires_at <= NOW()").Delete().Error())
+		return store.Exec(`
+			INSERT INTO lti_launch_states (state, nonce, issuer, client_id, expires_at)
+			VALUES (?, ?, ?, ?, NOW() + INTERVAL ? SECOND)`,
+			state, nonce, platform.Issuer, platform.ClientID, ltiLaunchStateLifetimeInSeconds).Error()
+	}))
+
+	authURL, err := url.Parse(platform.AuthLoginURL)
+	service.MustNotBeError(err)
+	query := authURL.Query()
+	query.Set("scope", "openid")
+	query.Set("response_type", "id_token")
+	query.Set("response_mode", "form_post")
+	query.Set("prompt", "none")
+	query.Set("client_id", platform.ClientID)
+	query.Set("redirect_uri", ltiConfig.LaunchURL)
+	query.Set("login_hint", loginHint)
+	if messageHint := r.Form.Get("lti_message_hint"); messageHint != "" {
+		query.Set("lti_message_hint", messageHint)
+	}
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	authURL.RawQuery = query.Encode()
+
+	http.Redirect(w, r, authURL.String(), http.StatusFound)
+	return service.NoError
+}
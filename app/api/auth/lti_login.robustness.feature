@@ -0,0 +1,63 @@
+Feature: Initiate an LTI 1.3 login - robustness
+  Background:
+    Given the application config is:
+      """
+      auth:
+        lti:
+          keyID: "algorea"
+          launchURL: "https://backend.algorea.org/auth/lti/launch"
+          frontendURL: "https://app.algorea.org/lti"
+          platforms:
+            - issuer: "https://moodle.example.org"
+              clientID: "algorea-tool"
+              deploymentIDs: ["1"]
+              authLoginURL: "https://moodle.example.org/mod/lti/auth.php"
+              jwksURL: "https://moodle.example.org/mod/lti/certs.php"
+            - issuer: "https://canvas.example.org"
+              clientID: "tool1"
+              authLoginURL: "https://canvas.example.org/api/lti/authorize_redirect"
+              jwksURL: "https://canvas.example.org/api/lti/security/jwks"
+            - issuer: "https://canvas.example.org"
+              clientID: "tool2"
+              authLoginURL: "https://canvas.example.org/api/lti/authorize_redirect"
+              jwksURL: "https://canvas.example.org/api/lti/security/jwks"
+      """
+
+  Scenario: LTI is not configured
+    Given the application config is:
+      """
+      auth:
+        loginModuleURL: "https://login.algorea.org"
+      """
+    When I send a GET request to "/auth/lti/login?iss=https%3A%2F%2Fmoodle.example.org&login_hint=user1&target_link_uri=https%3A%2F%2Fbackend.algorea.org%2Fauth%2Flti%2Flaunch"
+    Then the response code should be 400
+    And the response error message should contain "LTI is not configured"
+    And the table "lti_launch_states" should be empty
+
+  Scenario Outline: Missing parameters
+    When I send a GET request to "/auth/lti/login?<query>"
+    Then the response code should be 400
+    And the response error message should contain "The iss, login_hint, and target_link_uri parameters are required"
+    And the table "lti_launch_states" should be empty
+  Examples:
+    | query                                                                                         |
+    | login_hint=user1&target_link_uri=https%3A%2F%2Fbackend.algorea.org%2Fauth%2Flti%2Flaunch      |
+    | iss=https%3A%2F%2Fmoodle.example.org&target_link_uri=https%3A%2F%2Fbackend.algorea.org%2Fauth |
+    | iss=https%3A%2F%2Fmoodle.example.org&login_hint=user1                                         |
+
+  Scenario Outline: Unknown platform
+    When I send a GET request to "/auth/lti/login?iss=<iss>&client_id=<client_id>&login_hint=user1&target_link_uri=https%3A%2F%2Fbackend.algorea.org%2Fauth%2Flti%2Flaunch"
+    Then the response code should be 400
+    And the response error message should contain "Unknown LTI platform"
+    And the table "lti_launch_states" should be empty
+  Examples:
+    | iss                               | client_id    |
+    | https%3A%2F%2Funknown.example.org |              |
+    | https%3A%2F%2Fmoodle.example.org  | unknown-tool |
+    | https%3A%2F%2Fcanvas.example.org  |              |
+
+  Scenario: Wrong target_link_uri
+    When I send a GET request to "/auth/lti/login?iss=https%3A%2F%2Fmoodle.example.org&login_hint=user1&target_link_uri=https%3A%2F%2Fevil.example.org"
+    Then the response code should be 400
+    And the response error message should contain "Wrong target_link_uri"
+    And the table "lti_launch_states" should be empty
@@ -65,6 +65,38 @@ Feature: Create a new access token
     | ?use_cookie=1&cookie_same_site=1 | [Header not defined]  |                                 | access_token=1!tmp_new_token!127.0.0.1!/; Path=/; Domain=127.0.0.1; Expires=Wed, 01 Jan 2020 04:00:00 GMT; Max-Age=7200; HttpOnly; SameSite=Strict       |
     | ?use_cookie=0                    | access_token=0!1234!! | "access_token":"tmp_new_token", | access_token=; Expires=Wed, 01 Jan 2020 01:43:20 GMT; Max-Age=0; HttpOnly; SameSite=None                                                                 |
 
+  Scenario: Request a new access token for a session launched by an LTI platform
+    Given the database has the following users:
+      | group_id | login        | temp_user |
+      | 15       | lti-12345678 | false     |
+    And the database table "sessions" also has the following row:
+      | session_id | user_id | identity_provider |
+      | 4          | 15      | lti               |
+    And the database table "access_tokens" also has the following row:
+      | session_id | issued_at           | expires_at          | token             |
+      | 4          | 2020-01-01 01:00:12 | 2020-01-01 03:00:12 | lti_current_token |
+    And the generated auth key is "lti_new_token"
+    And the "Authorization" request header is "Bearer lti_current_token"
+    When I send a POST request to "/auth/token"
+    Then the response code should be 201
+    And the response body should be, in JSON:
+      """
+      {
+        "success": true,
+        "message": "created",
+        "data": {"access_token": "lti_new_token", "expires_in": 7200}
+      }
+      """
+    And logs should contain:
+      """
+      Refreshed a session token expiring in 7200 seconds for an LTI session of a user with group_id = 15
+      """
+    And the table "sessions" should stay unchanged
+    And the table "access_tokens" at session_id "4" should be:
+      | session_id | issued_at           | expires_at          | token             |
+      | 4          | 2020-01-01 01:00:12 | 2020-01-01 03:00:12 | lti_current_token |
+      | 4          | 2020-01-01 02:00:00 | 2020-01-01 04:00:00 | lti_new_token     |
+
   Scenario Outline: Request a new access token for a normal user
     Given the login module "token" endpoint for refresh token "jane_current_refreshtoken" returns 200 with body:
       """
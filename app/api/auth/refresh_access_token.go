@@ -7,8 +7,6 @@ import (
 	"sync"
 	"time"
 
-	"golang.org/x/oauth2"
-
 	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
@@ -93,26 +91,36 @@ func (srv *Service) refreshTokens(
 	user *database.User,
 	sessionID int64,
 ) (newToken string, expiresIn int32, apiError service.APIError) {
-	var refreshToken string
+	var session struct {
+		RefreshToken     string
+		IdentityProvider string
+	}
 	err := store.Sessions().Where("session_id = ?", sessionID).
-		PluckFirst("refresh_token", &refreshToken).Error()
+		Select("refresh_token, identity_provider").Take(&session).Error()
+	if session.IdentityProvider == auth.IdentityProviderLTI {
+		newToken, expiresIn, err = auth.RefreshLTISession(store, user.GroupID, sessionID)
+		service.MustNotBeError(err)
+		return newToken, expiresIn, service.NoError
+	}
+	refreshToken := session.RefreshToken
 	if refreshToken == "" {
 		logging.SharedLogger.WithContext(ctx).
 			Warnf("No refresh token found in the DB for user %d", user.GroupID)
 		return "", 0, service.ErrNotFound(errors.New("no refresh token found in the DB for the authenticated user"))
 	}
 	service.MustNotBeError(err)
-	// oldToken is invalid since its AccessToken is empty, so the lib will refresh it
-	oldToken := &oauth2.Token{RefreshToken: refreshToken}
-	oauthConfig := auth.GetOAuthConfig(srv.AuthConfig)
-	token, err := oauthConfig.TokenSource(ctx, oldToken).Token()
+	identityProvider, err := srv.IdentityProviders.Get(srv.AuthConfig, session.IdentityProvider)
+	service.MustNotBeError(err)
+	token, err := identityProvider.RefreshToken(ctx, refreshToken)
+	service.MustNotBeError(err)
+	accessToken, expiresAt, err := identityProvider.SessionAccessToken(token)
 	service.MustNotBeError(err)
 	service.MustNotBeError(store.InTransaction(func(store *database.DataStore) error {
 		// insert the new access token
 		service.MustNotBeError(store.AccessTokens().InsertNewToken(
 			sessionID,
-			token.AccessToken,
-			int32(time.Until(token.Expiry)/time.Second),
+			accessToken,
+			int32(time.Until(expiresAt)/time.Second),
 		))
 		if refreshToken != token.RefreshToken {
 			service.MustNotBeError(store.Sessions().
@@ -122,8 +130,8 @@ func (srv *Service) refreshTokens(
 			)
 		}
 
-		newToken = token.AccessToken
-		expiresIn = int32(time.Until(token.Expiry).Round(time.Second) / time.Second)
+		newToken = accessToken
+		expiresIn = int32(time.Until(expiresAt).Round(time.Second) / time.Second)
 
 		return nil
 	}))
@@ -67,9 +67,10 @@ func TestService_refreshAccessToken_NotAllowRefreshTokenRaces(t *testing.T) {
 
 				if !timeout {
 					mock.ExpectQuery("^" +
-						regexp.QuoteMeta("SELECT refresh_token FROM `sessions` WHERE (session_id = ?) LIMIT 1") + "$").
+						regexp.QuoteMeta("SELECT refresh_token, identity_provider FROM `sessions` WHERE (session_id = ?) LIMIT 1") + "$").
 						WithArgs(sqlmock.AnyArg()).
-						WillReturnRows(mock.NewRows([]string{"refresh_token"}).AddRow("firstrefreshtoken"))
+						WillReturnRows(mock.NewRows([]string{"refresh_token", "identity_provider"}).
+							AddRow("firstrefreshtoken", "login_module"))
 					mock.ExpectBegin()
 					mock.ExpectExec("^"+regexp.QuoteMeta(
 						"INSERT INTO `access_tokens` (`expires_at`, `issued_at`, `session_id`, `token`) "+
@@ -29,6 +29,8 @@ func (srv *Service) SetRoutes(router chi.Router) {
 		service.AppHandler(srv.setAdditionalTime).ServeHTTP)
 	router.Get("/contests/{item_id}/groups/{group_id}/members/additional-times",
 		service.AppHandler(srv.getMembersAdditionalTimes).ServeHTTP)
+	router.Get("/contests/{item_id}/groups/{group_id}/ranking", service.AppHandler(srv.getRanking).ServeHTTP)
+	router.Get("/contests/{item_id}/groups/{group_id}/ranking-csv", service.AppHandler(srv.getRankingCSV).ServeHTTP)
 }
 
 // swagger:model contestInfo
@@ -0,0 +1,181 @@
+Feature: Get the ranking of a contest (contestGetRanking)
+  Background:
+    Given the database has the following table "groups":
+      | id | name    | type    |
+      | 10 | Parent  | Club    |
+      | 11 | Group A | Friends |
+      | 21 | owner   | User    |
+      | 31 | john    | User    |
+      | 41 | jane    | User    |
+      | 51 | jack    | User    |
+      | 61 | paul    | User    |
+      | 71 | lisa    | User    |
+    And the database has the following users:
+      | group_id | login |
+      | 21       | owner |
+      | 31       | john  |
+      | 41       | jane  |
+      | 51       | jack  |
+      | 61       | paul  |
+      | 71       | lisa  |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 10       | 21         | true              |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 10              | 11             |
+      | 11              | 31             |
+      | 11              | 41             |
+      | 11              | 51             |
+      | 11              | 61             |
+      | 11              | 71             |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | duration | requires_explicit_entry | entry_participant_type | ranking_freeze_duration | default_language_tag |
+      | 50 | 01:00:00 | 1                       | User                   | 00:10:00                | fr                   |
+      | 60 | 01:00:00 | 1                       | User                   | null                    | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_watch_generated |
+      | 10       | 50      | info               | none                |
+      | 10       | 60      | info               | none                |
+      | 21       | 50      | content            | result              |
+    And the database has the following table "attempts":
+      | participant_id | id | root_item_id | created_at          | allows_submissions_until |
+      | 31             | 1  | 50           | 2020-01-01 12:00:00 | 2020-01-01 13:00:00      |
+      | 41             | 1  | 50           | 2020-01-01 12:45:00 | 2020-01-01 13:30:00      |
+      | 51             | 1  | 50           | 2020-01-01 12:00:00 | 2020-01-01 13:00:00      |
+      | 51             | 2  | 50           | 2020-01-01 12:40:00 | 2020-01-01 13:00:00      |
+      | 51             | 3  | 60           | 2020-01-01 12:40:00 | 2020-01-01 13:00:00      |
+      | 71             | 1  | 50           | 2020-01-01 12:00:00 | 2020-01-01 13:00:00      |
+    And the database has the following table "results":
+      | participant_id | attempt_id | item_id | started_at          | score_computed | score_obtained_at   |
+      | 31             | 1          | 50      | 2020-01-01 12:00:00 | 100            | 2020-01-01 12:30:00 |
+      | 41             | 1          | 50      | 2020-01-01 12:45:00 | 100            | 2020-01-01 13:15:00 |
+      | 51             | 1          | 50      | 2020-01-01 12:00:00 | 50             | 2020-01-01 12:20:00 |
+      | 51             | 2          | 50      | 2020-01-01 12:40:00 | 80             | 2020-01-01 12:55:00 |
+      | 51             | 3          | 60      | 2020-01-01 12:40:00 | 100            | 2020-01-01 12:41:00 |
+      | 71             | 1          | 50      | 2020-01-01 12:00:00 | 0              | null                |
+    And the database has the following table "results_ranking_snapshots":
+      | participant_id | attempt_id | item_id | score_computed | score_obtained_at   |
+      | 51             | 2          | 50      | 70             | 2020-01-01 12:45:00 |
+
+  Scenario: The manager sees the unfrozen ranking
+    Given I am the user with id "21"
+    When I send a GET request to "/contests/50/groups/10/ranking"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "rank": 1, "group_id": "41", "name": "jane", "type": "User", "score": 100,
+        "score_obtained_at": "2020-01-01T13:15:00Z", "time_to_score": 1800, "is_frozen": false
+      },
+      {
+        "rank": 1, "group_id": "31", "name": "john", "type": "User", "score": 100,
+        "score_obtained_at": "2020-01-01T12:30:00Z", "time_to_score": 1800, "is_frozen": false
+      },
+      {
+        "rank": 3, "group_id": "51", "name": "jack", "type": "User", "score": 80,
+        "score_obtained_at": "2020-01-01T12:55:00Z", "time_to_score": 900, "is_frozen": false
+      },
+      {
+        "rank": 4, "group_id": "71", "name": "lisa", "type": "User", "score": 0,
+        "score_obtained_at": null, "time_to_score": null, "is_frozen": false
+      }
+    ]
+    """
+
+  Scenario: A member sees the frozen ranking (additional times are honored)
+    Given I am the user with id "31"
+    When I send a GET request to "/contests/50/groups/11/ranking"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "rank": 1, "group_id": "41", "name": "jane", "type": "User", "score": 100,
+        "score_obtained_at": "2020-01-01T13:15:00Z", "time_to_score": 1800, "is_frozen": false
+      },
+      {
+        "rank": 1, "group_id": "31", "name": "john", "type": "User", "score": 100,
+        "score_obtained_at": "2020-01-01T12:30:00Z", "time_to_score": 1800, "is_frozen": false
+      },
+      {
+        "rank": 3, "group_id": "51", "name": "jack", "type": "User", "score": 70,
+        "score_obtained_at": "2020-01-01T12:45:00Z", "time_to_score": 300, "is_frozen": true
+      },
+      {
+        "rank": 4, "group_id": "71", "name": "lisa", "type": "User", "score": 0,
+        "score_obtained_at": null, "time_to_score": null, "is_frozen": false
+      }
+    ]
+    """
+
+  Scenario: A member sees the results changed during the freeze as they were when the freeze started
+    Given I am the user with id "31"
+    And the database table "attempts" also has the following row:
+      | participant_id | id | root_item_id | created_at          | allows_submissions_until |
+      | 61             | 1  | 50           | 2020-01-01 12:00:00 | 2020-01-01 13:00:00      |
+    And the database table "results" also has the following row:
+      | participant_id | attempt_id | item_id | started_at          | score_computed | score_obtained_at   |
+      | 61             | 1          | 50      | 2020-01-01 12:00:00 | 90             | 2020-01-01 12:55:00 |
+    And the database table "results_ranking_snapshots" also has the following row:
+      | participant_id | attempt_id | item_id | score_computed | score_obtained_at |
+      | 61             | 1          | 50      | 0              | null              |
+    When I send a GET request to "/contests/50/groups/11/ranking"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "rank": 1, "group_id": "41", "name": "jane", "type": "User", "score": 100,
+        "score_obtained_at": "2020-01-01T13:15:00Z", "time_to_score": 1800, "is_frozen": false
+      },
+      {
+        "rank": 1, "group_id": "31", "name": "john", "type": "User", "score": 100,
+        "score_obtained_at": "2020-01-01T12:30:00Z", "time_to_score": 1800, "is_frozen": false
+      },
+      {
+        "rank": 3, "group_id": "51", "name": "jack", "type": "User", "score": 70,
+        "score_obtained_at": "2020-01-01T12:45:00Z", "time_to_score": 300, "is_frozen": true
+      },
+      {
+        "rank": 4, "group_id": "71", "name": "lisa", "type": "User", "score": 0,
+        "score_obtained_at": null, "time_to_score": null, "is_frozen": false
+      },
+      {
+        "rank": 4, "group_id": "61", "name": "paul", "type": "User", "score": 0,
+        "score_obtained_at": null, "time_to_score": null, "is_frozen": true
+      }
+    ]
+    """
+
+  Scenario: A member sees the ranking of a contest without freeze
+    Given I am the user with id "31"
+    When I send a GET request to "/contests/60/groups/11/ranking"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "rank": 1, "group_id": "51", "name": "jack", "type": "User", "score": 100,
+        "score_obtained_at": "2020-01-01T12:41:00Z", "time_to_score": 60, "is_frozen": false
+      }
+    ]
+    """
+
+  Scenario: Get the ranking as a CSV file
+    Given I am the user with id "31"
+    When I send a GET request to "/contests/50/groups/11/ranking-csv"
+    Then the response code should be 200
+    And the response header "Content-Type" should be "text/csv"
+    And the response header "Content-Disposition" should be "attachment; filename=ranking_for_item_50_and_group_11.csv"
+    And the response body should be:
+    """
+    Rank;Name;Score;Time to score
+    1;jane;100;1800
+    1;john;100;1800
+    3;jack;70;300
+    4;lisa;0;
+
+    """
@@ -0,0 +1,244 @@
+package contests
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/render"
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+const resultsRankingSnapshotsJoin = `
+	results_ranking_snapshots AS snapshots ON snapshots.participant_id = results.participant_id AND
+		snapshots.attempt_id = results.attempt_id AND snapshots.item_id = results.item_id`
+
+// swagger:model contestRankingRow
+type contestRankingRow struct {
+	// Participants with the same score and the same time to score share the same rank
+	// required: true
+	Rank int `json:"rank"`
+	// required: true
+	GroupID int64 `json:"group_id,string"`
+	// required: true
+	Name string `json:"name"`
+	// required: true
+	// enum: User,Team
+	Type string `json:"type"`
+	// The best score of the participant (0 if no score)
+	// required: true
+	Score float32 `json:"score"`
+	// Time when the best score was obtained
+	// required: true
+	ScoreObtainedAt *database.Time `json:"score_obtained_at"`
+	// Number of seconds between the start of the participation and the time when the best score was obtained
+	// required: true
+	TimeToScore *int64 `json:"time_to_score"`
+	// Whether some results of the participant are hidden because of the ranking freeze
+	// required: true
+	IsFrozen bool `json:"is_frozen"`
+}
+
+// swagger:operation GET /contests/{item_id}/groups/{group_id}/ranking contests contestGetRanking
+//
+//	---
+//	summary: Get the ranking of a contest
+//	description: >
+//							 Ranks all the descendant
+//
+//								 * teams if `items.entry_participant_type` = 'Team'
+//								 * end-users otherwise
+//
+//							 of the group having entered the item (having attempts with `attempts.root_item_id` = `{item_id}`)
+//							 by their best score on the item (`results.score_computed` over all their attempts),
+//							 then by the time needed to obtain the best score
+//							 (`results.score_obtained_at` relative to `results.started_at`, i.e. to the entry).
+//							 Participants with the same score and the same time share the same rank.
+//
+//
+//							 If `items.ranking_freeze_duration` is set, for users who are not managers,
+//							 results whose scores have changed less than `ranking_freeze_duration` before the end
+//							 of the participation (`attempts.allows_submissions_until`, so honoring additional times)
+//							 are replaced by the snapshots of the results taken when the freeze started
+//							 (i.e. by the scores the participant had at that time)
+//							 and the participant gets `is_frozen` = true.
+//
+//
+//							 Restrictions:
+//								 * `item_id` should require explicit entry;
+//								 * the authenticated user should be either
+//									 - a manager of the group with `can_watch_members` having `can_watch` >= 'result' on the item
+//										 (the ranking is never frozen for such users), or
+//									 - a member of the group having `can_view` >= 'info' on the item,
+//
+//							 otherwise the 'forbidden' error is returned.
+//	parameters:
+//		- name: item_id
+//			description: "`id` of an item requiring explicit entry"
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: group_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//	responses:
+//		"200":
+//			description: OK. Success response with the ranking
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/contestRankingRow"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getRanking(w http.ResponseWriter, r *http.Request) service.APIError {
+	ranking, _, _, apiError := srv.computeRanking(r)
+	if apiError != service.NoError {
+		return apiError
+	}
+
+	render.Respond(w, r, ranking)
+	return service.NoError
+}
+
+func (srv *Service) computeRanking(r *http.Request) (
+	ranking []contestRankingRow, itemID, groupID int64, apiError service.APIError,
+) {
+	var err error
+	itemID, err = service.ResolveURLQueryPathInt64Field(r, "item_id")
+	if err != nil {
+		return nil, 0, 0, service.ErrInvalidRequest(err)
+	}
+
+	groupID, err = service.ResolveURLQueryPathInt64Field(r, "group_id")
+	if err != nil {
+		return nil, 0, 0, service.ErrInvalidRequest(err)
+	}
+
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	var itemInfo struct {
+		EntryParticipantType  string
+		RankingFreezeDuration *int64
+	}
+	err = store.Items().ByID(itemID).Where("items.requires_explicit_entry").
+		Select("items.entry_participant_type, TIME_TO_SEC(items.ranking_freeze_duration) AS ranking_freeze_duration").
+		Take(&itemInfo).Error()
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, 0, 0, service.InsufficientAccessRightsError
+	}
+	service.MustNotBeError(err)
+
+	isManager := user.CanWatchItemResult(store, itemID) && user.CanWatchGroupMembers(store, groupID)
+	if !isManager {
+		isMember, err := store.ActiveGroupAncestors().
+			Where("groups_ancestors_active.ancestor_group_id = ?", groupID).
+			Where("groups_ancestors_active.child_group_id = ?", user.GroupID).HasRows()
+		service.MustNotBeError(err)
+		if !isMember || !user.CanViewItemInfo(store, itemID) {
+			return nil, 0, 0, service.InsufficientAccessRightsError
+		}
+	}
+
+	isFrozen := !isManager && itemInfo.RankingFreezeDuration != nil
+	participantResultsQuery := store.Results().
+		Joins(`
+			JOIN attempts ON attempts.participant_id = results.participant_id AND attempts.id = results.attempt_id AND
+				attempts.root_item_id = results.item_id`).
+		Where("results.participant_id = participants.id AND results.item_id = ?", itemID)
+	frozenResultsQuery := participantResultsQuery.Joins("JOIN " + resultsRankingSnapshotsJoin)
+
+	bestResultQuery := participantResultsQuery.
+		Select(`
+			results.score_computed, results.score_obtained_at,
+			TIMESTAMPDIFF(SECOND, results.started_at, results.score_obtained_at) AS time_to_score`)
+	if isFrozen {
+		// The results changed during the freeze are replaced by their snapshots taken when the freeze started
+		bestResultQuery = participantResultsQuery.Joins("LEFT JOIN " + resultsRankingSnapshotsJoin).
+			Select(`
+				IF(snapshots.participant_id IS NULL, results.score_computed, snapshots.score_computed) AS score_computed,
+				IF(snapshots.participant_id IS NULL, results.score_obtained_at, snapshots.score_obtained_at) AS score_obtained_at,
+				TIMESTAMPDIFF(SECOND, results.started_at,
+					IF(snapshots.participant_id IS NULL, results.score_obtained_at, snapshots.score_obtained_at)) AS time_to_score`)
+	}
+
+	service.MustNotBeError(store.ActiveGroupAncestors().
+		Where("groups_ancestors_active.ancestor_group_id = ?", groupID).
+		Joins(`
+			JOIN `+"`groups`"+` AS participants
+				ON participants.id = groups_ancestors_active.child_group_id AND participants.type = ?`, itemInfo.EntryParticipantType).
+		Where("EXISTS(?)", store.Attempts().
+			Where("attempts.participant_id = participants.id AND attempts.root_item_id = ?", itemID).QueryExpr()).
+		Joins(`
+			LEFT JOIN LATERAL ? AS best_result ON 1`,
+			bestResultQuery.Order("score_computed DESC, time_to_score IS NULL, time_to_score").Limit(1).SubQuery()).
+		Select(`
+			participants.id AS group_id, participants.name, participants.type,
+			IFNULL(best_result.score_computed, 0) AS score, best_result.score_obtained_at, best_result.time_to_score,
+			? AND EXISTS(?) AS is_frozen`,
+			isFrozen, frozenResultsQuery.QueryExpr()).
+		Scan(&ranking).Error())
+
+	sortAndRankContestRanking(ranking)
+	return ranking, itemID, groupID, service.NoError
+}
+
+// sortAndRankContestRanking sorts the ranking by score (descending) and time to score (ascending, missing times go last),
+// then by name and id, and sets the ranks, participants with the same score and time to score share the same rank.
+func sortAndRankContestRanking(ranking []contestRankingRow) {
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if compared := compareContestRankingRows(&ranking[i], &ranking[j]); compared != 0 {
+			return compared < 0
+		}
+		if ranking[i].Name != ranking[j].Name {
+			return ranking[i].Name < ranking[j].Name
+		}
+		return ranking[i].GroupID < ranking[j].GroupID
+	})
+
+	for index := range ranking {
+		if index > 0 && compareContestRankingRows(&ranking[index-1], &ranking[index]) == 0 {
+			ranking[index].Rank = ranking[index-1].Rank
+		} else {
+			ranking[index].Rank = index + 1
+		}
+	}
+}
+
+// compareContestRankingRows returns a negative number if the first row is ranked before the second one,
+// a positive number if it is ranked after the second one, and 0 if both rows share the same rank.
+func compareContestRankingRows(row1, row2 *contestRankingRow) int {
+	switch {
+	case row1.Score != row2.Score:
+		if row1.Score > row2.Score {
+			return -1
+		}
+		return 1
+	case row1.TimeToScore == nil && row2.TimeToScore == nil:
+		return 0
+	case row1.TimeToScore == nil:
+		return 1
+	case row2.TimeToScore == nil:
+		return -1
+	case *row1.TimeToScore != *row2.TimeToScore:
+		if *row1.TimeToScore < *row2.TimeToScore {
+			return -1
+		}
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,79 @@
+Feature: Get the ranking of a contest - robustness
+  Background:
+    Given the database has the following table "groups":
+      | id | name   | type |
+      | 10 | Parent | Club |
+      | 21 | owner  | User |
+      | 31 | john   | User |
+      | 41 | jane   | User |
+    And the database has the following users:
+      | group_id | login |
+      | 21       | owner |
+      | 31       | john  |
+      | 41       | jane  |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 10       | 41         | false             |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 10              | 31             |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id | duration | requires_explicit_entry | entry_participant_type | default_language_tag |
+      | 50 | 01:00:00 | 1                       | User                   | fr                   |
+      | 60 | 01:00:00 | 0                       | User                   | fr                   |
+      | 70 | 01:00:00 | 1                       | User                   | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_watch_generated |
+      | 10       | 60      | info               | none                |
+      | 21       | 50      | content            | result              |
+      | 31       | 50      | info               | none                |
+      | 41       | 50      | content            | result              |
+
+  Scenario: Wrong item_id
+    Given I am the user with id "31"
+    When I send a GET request to "/contests/abc/groups/10/ranking"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for item_id (should be int64)"
+
+  Scenario: Wrong group_id
+    Given I am the user with id "31"
+    When I send a GET request to "/contests/50/groups/abc/ranking"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for group_id (should be int64)"
+
+  Scenario: No such item
+    Given I am the user with id "31"
+    When I send a GET request to "/contests/90/groups/10/ranking"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The item does not require explicit entry
+    Given I am the user with id "31"
+    When I send a GET request to "/contests/60/groups/10/ranking"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The member cannot view the item
+    Given I am the user with id "31"
+    When I send a GET request to "/contests/70/groups/10/ranking"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The user is neither a member nor a manager of the group
+    Given I am the user with id "21"
+    When I send a GET request to "/contests/50/groups/10/ranking"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The manager cannot watch members of the group
+    Given I am the user with id "41"
+    When I send a GET request to "/contests/50/groups/10/ranking"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The CSV export has the same restrictions
+    Given I am the user with id "21"
+    When I send a GET request to "/contests/50/groups/10/ranking-csv"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
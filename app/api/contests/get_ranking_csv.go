@@ -0,0 +1,82 @@
+package contests
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:operation GET /contests/{item_id}/groups/{group_id}/ranking-csv contests contestGetRankingCSV
+//
+//	---
+//	summary: Get the ranking of a contest as a CSV file
+//	description: >
+//							 Returns the same ranking as `contestGetRanking` as a CSV file
+//							 with the rank, the name, the score, and the time to score (in seconds) of each participant.
+//
+//
+//							 Restrictions are the same as for `contestGetRanking`.
+//	parameters:
+//		- name: item_id
+//			description: "`id` of an item requiring explicit entry"
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: group_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//	responses:
+//		"200":
+//			description: OK. Success response with the ranking
+//			content:
+//				text/csv:
+//					schema:
+//					type: string
+//			examples:
+//				text/csv:
+//					Rank;Name;Score;Time to score
+//
+//					1;john;100;3600
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getRankingCSV(w http.ResponseWriter, r *http.Request) service.APIError {
+	ranking, itemID, groupID, apiError := srv.computeRanking(r)
+	if apiError != service.NoError {
+		return apiError
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf("attachment; filename=ranking_for_item_%d_and_group_%d.csv", itemID, groupID))
+
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Comma = ';'
+	service.MustNotBeError(csvWriter.Write([]string{"Rank", "Name", "Score", "Time to score"}))
+	for index := range ranking {
+		timeToScore := ""
+		if ranking[index].TimeToScore != nil {
+			timeToScore = strconv.FormatInt(*ranking[index].TimeToScore, 10)
+		}
+		service.MustNotBeError(csvWriter.Write([]string{
+			strconv.Itoa(ranking[index].Rank), ranking[index].Name,
+			strconv.FormatFloat(float64(ranking[index].Score), 'f', -1, 32), timeToScore,
+		}))
+	}
+	csvWriter.Flush()
+	service.MustNotBeError(csvWriter.Error())
+	return service.NoError
+}
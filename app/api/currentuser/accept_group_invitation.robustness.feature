@@ -42,6 +42,7 @@ Feature: User accepts an invitation to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Cycles in the group relations graph are not allowed"
     }
     """
@@ -57,6 +58,7 @@ Feature: User accepts an invitation to join a group - robustness
     {
       "success": false,
       "message": "Not Found",
+      "error_code": "not_found",
       "error_text": "No such relation"
     }
     """
@@ -72,6 +74,7 @@ Feature: User accepts an invitation to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Team's participations are in conflict with the user's participations"
     }
     """
@@ -96,6 +99,7 @@ Feature: User accepts an invitation to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Entry conditions would not be satisfied"
     }
     """
@@ -137,6 +141,7 @@ Feature: User accepts an invitation to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "approvals_missing",
       "error_text": "Missing required approvals",
       "data": {
         "missing_approvals": ["personal_info_view"]
@@ -155,6 +160,7 @@ Feature: User accepts an invitation to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Group membership is frozen"
     }
     """
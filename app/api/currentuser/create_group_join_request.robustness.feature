@@ -50,6 +50,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Cycles in the group relations graph are not allowed"
     }
     """
@@ -67,6 +68,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "A conflicting relation exists"
     }
     """
@@ -93,6 +95,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Entry conditions would not be satisfied"
     }
     """
@@ -114,6 +117,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Team's participations are in conflict with the user's participations"
     }
     """
@@ -131,6 +135,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Team's participations are in conflict with the user's participations"
     }
     """
@@ -170,6 +175,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "approvals_missing",
       "error_text": "Missing required approvals",
       "data": {"missing_approvals": ["personal_info_view","lock_membership","watch"]}
     }
@@ -184,6 +190,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "approvals_missing",
       "error_text": "Missing required approvals",
       "data": {"missing_approvals": ["lock_membership","watch"]}
     }
@@ -198,6 +205,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "approvals_missing",
       "error_text": "Missing required approvals",
       "data": {"missing_approvals": ["watch"]}
     }
@@ -215,6 +223,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "approvals_missing",
       "error_text": "Missing required approvals",
       "data": {"missing_approvals": ["watch"]}
     }
@@ -244,6 +253,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Group membership is frozen"
     }
     """
@@ -257,6 +267,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Conflict",
+      "error_code": "conflict",
       "error_text": "The group is full"
     }
     """
@@ -273,6 +284,7 @@ Feature: User sends a request to join a group - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Group membership is frozen"
     }
     """
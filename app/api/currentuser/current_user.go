@@ -43,7 +43,8 @@ func (srv *Service) SetRoutes(router chi.Router) {
 	router.Get("/current-user/managed-groups", service.AppHandler(srv.getManagedGroups).ServeHTTP)
 
 	router.Get("/current-user/group-memberships", service.AppHandler(srv.getGroupMemberships).ServeHTTP)
-	router.Post("/current-user/group-memberships/by-code", service.AppHandler(srv.joinGroupByCode).ServeHTTP)
+	router.With(srv.RateLimitMiddleware("code-check")).
+		Post("/current-user/group-memberships/by-code", service.AppHandler(srv.joinGroupByCode).ServeHTTP)
 	router.Delete("/current-user/group-memberships/{group_id}", service.AppHandler(srv.leaveGroup).ServeHTTP)
 	router.Get("/current-user/group-memberships-history", service.AppHandler(srv.getGroupMembershipsHistory).ServeHTTP)
 
@@ -51,7 +52,10 @@ func (srv *Service) SetRoutes(router chi.Router) {
 	routerWithParticipant.Get("/current-user/group-memberships/activities", service.AppHandler(srv.getRootActivities).ServeHTTP)
 	routerWithParticipant.Get("/current-user/group-memberships/skills", service.AppHandler(srv.getRootSkills).ServeHTTP)
 
+	router.Get("/current-user/events", service.AppHandler(srv.getEvents).ServeHTTP)
 	router.Put("/current-user/notifications-read-at", service.AppHandler(srv.updateNotificationsReadAt).ServeHTTP)
+	router.Get("/current-user/notifications", service.AppHandler(srv.getNotifications).ServeHTTP)
+	router.Put("/current-user/notifications/{notification_id}/read-at", service.AppHandler(srv.updateNotificationReadAt).ServeHTTP)
 	router.Put("/current-user/refresh", service.AppHandler(srv.refresh).ServeHTTP)
 
 	router.Get("/current-user/full-dump", service.AppHandler(srv.getFullDump).ServeHTTP)
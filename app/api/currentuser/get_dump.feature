@@ -85,7 +85,7 @@ Feature: Export the short version of the current user's data
         "cell_phone_number": null, "city": null, "country_code": "", "default_language": "fr", "email": null,
         "first_name": "John", "free_text": null, "land_line_number": null, "lang_prog": "Python",
         "latest_activity_at": null, "last_ip": null, "latest_login_at": null, "last_name": "Doe", "login": "user",
-        "notifications_read_at": null, "notify": "Answers", "open_id_identity": null, "password_md5": null,
+        "notifications_read_at": null, "email_notifications_opt_out": "", "notify": "Answers", "open_id_identity": null, "password_md5": null,
         "recover": null, "registered_at": null, "salt": null, "sex": null, "student_id": null, "time_zone": null,
         "web_site": null, "zipcode": null, "temp_user": 0
       },
@@ -176,7 +176,7 @@ Feature: Export the short version of the current user's data
         "login_id": null, "help_given": 0, "spaces_for_tab": 3, "address": null, "birth_date": null, "cell_phone_number": null,
         "city": null, "country_code": "", "default_language": "fr", "email": null, "first_name": "Jane",
         "free_text": null, "land_line_number": null, "lang_prog": "Python", "latest_activity_at": null, "last_ip": null,
-        "latest_login_at": null, "last_name": "Doe", "login": "jane", "notifications_read_at": null, "notify": "Answers",
+        "latest_login_at": null, "last_name": "Doe", "login": "jane", "notifications_read_at": null, "email_notifications_opt_out": "", "notify": "Answers",
         "open_id_identity": null, "password_md5": null, "recover": null, "registered_at": null, "salt": null,
         "sex": null, "student_id": null, "time_zone": null, "web_site": null, "zipcode": null, "temp_user": 0,
         "latest_profile_sync_at": null
@@ -0,0 +1,113 @@
+Feature: Stream events for the current user
+  Background:
+    Given the database has the following table "groups":
+      | id | name    | type  |
+      | 13 | Group B | Class |
+      | 50 | Team    | Team  |
+    And the database has the following users:
+      | group_id | login | first_name  | last_name |
+      | 11       | jdoe  | John        | Doe       |
+      | 21       | owner | Jean-Michel | Blanquer  |
+      | 31       | other | Other       | User      |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 21         | true              |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 11             |
+      | 50              | 11             |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id  | type    | default_language_tag |
+      | 190 | Chapter | fr                   |
+      | 200 | Chapter | fr                   |
+      | 210 | Task    | fr                   |
+      | 220 | Task    | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_watch_generated |
+      | 13       | 190     | none               | none                |
+      | 13       | 200     | content            | none                |
+      | 50       | 210     | info               | none                |
+      | 21       | 190     | none               | result              |
+      | 21       | 200     | none               | answer              |
+    And the database has the following table "participant_events":
+      | id | type           | participant_id | attempt_id | item_id | created_at              |
+      | 1  | grade_saved    | 11             | 1          | 200     | 2020-01-01 00:00:00.000 |
+      | 2  | result_changed | 11             | 1          | 190     | 2020-01-01 00:00:01.000 |
+      | 3  | item_unlocked  | 50             | null       | 210     | 2020-01-01 00:00:02.000 |
+      | 4  | result_changed | 31             | 1          | 200     | 2020-01-01 00:00:03.000 |
+      | 5  | grade_saved    | 11             | 1          | 220     | 2020-01-01 00:00:04.123 |
+    And the application config is:
+      """
+      server:
+        eventsStreamDuration: 0s
+      """
+
+  Scenario: Participant gets events of his own and of his teams on visible items
+    Given I am the user with id "11"
+    When I send a GET request to "/current-user/events?since_id=0"
+    Then the response code should be 200
+    And the response header "Content-Type" should be "text/event-stream"
+    And the response header "Cache-Control" should be "no-cache"
+    And the response body should be:
+      """
+      id: 1
+      event: grade_saved
+      data: {"id":"1","type":"grade_saved","participant_id":"11","attempt_id":"1","item_id":"200","created_at":"2020-01-01T00:00:00Z"}
+
+      id: 3
+      event: item_unlocked
+      data: {"id":"3","type":"item_unlocked","participant_id":"50","attempt_id":null,"item_id":"210","created_at":"2020-01-01T00:00:02Z"}
+
+
+      """
+
+  Scenario: Events emitted during the commit lag are sent with the id of the last event emitted before
+    Given I am the user with id "11"
+    And the DB time now is "2020-01-01 00:00:11.500"
+    When I send a GET request to "/current-user/events?since_id=0"
+    Then the response code should be 200
+    And the response body should be:
+      """
+      id: 1
+      event: grade_saved
+      data: {"id":"1","type":"grade_saved","participant_id":"11","attempt_id":"1","item_id":"200","created_at":"2020-01-01T00:00:00Z"}
+
+      id: 2
+      event: item_unlocked
+      data: {"id":"3","type":"item_unlocked","participant_id":"50","attempt_id":null,"item_id":"210","created_at":"2020-01-01T00:00:02Z"}
+
+
+      """
+
+  Scenario: Observer gets events of watched participants on items with can_watch>=result
+    Given I am the user with id "21"
+    And the "Last-Event-ID" request header is "1"
+    When I send a GET request to "/current-user/events?since_id=0"
+    Then the response code should be 200
+    And the response header "Content-Type" should be "text/event-stream"
+    And the response body should be:
+      """
+      id: 2
+      event: result_changed
+      data: {"id":"2","type":"result_changed","participant_id":"11","attempt_id":"1","item_id":"190","created_at":"2020-01-01T00:00:01Z"}
+
+
+      """
+
+  Scenario: Only new events are sent when neither Last-Event-ID nor since_id is given
+    Given I am the user with id "11"
+    When I send a GET request to "/current-user/events"
+    Then the response code should be 200
+    And the response header "Content-Type" should be "text/event-stream"
+    And the response body should be:
+      """
+      """
+
+  Scenario: No events for a user who cannot see anything
+    Given I am the user with id "31"
+    When I send a GET request to "/current-user/events?since_id=3"
+    Then the response code should be 200
+    And the response body should be:
+      """
+      """
@@ -0,0 +1,267 @@
+package currentuser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+const (
+	defaultEventsStreamDuration = 50 * time.Second
+	defaultEventsPollInterval   = time.Second
+	defaultEventsCommitLag      = 10 * time.Second
+	eventsHeartbeatInterval     = 15 * time.Second
+)
+
+// swagger:model currentUserEvent
+type currentUserEvent struct {
+	// required: true
+	ID int64 `json:"id,string"`
+	// required: true
+	// enum: grade_saved,result_changed,item_unlocked
+	Type string `json:"type"`
+	// required: true
+	ParticipantID int64 `json:"participant_id,string"`
+	// `null` for 'item_unlocked'
+	// required: true
+	AttemptID *int64 `json:"attempt_id,string"`
+	// required: true
+	ItemID int64 `json:"item_id,string"`
+	// required: true
+	CreatedAt database.Time `json:"created_at"`
+}
+
+// swagger:operation GET /current-user/events users currentUserEventsStream
+//
+//	---
+//	summary: Stream events
+//	description: >
+//		Streams changes of results and item unlocks as
+//		[server-sent events](https://html.spec.whatwg.org/multipage/server-sent-events.html) (`text/event-stream`).
+//		Each event has the `id` of the event, the `event` type ('grade_saved', 'result_changed' or 'item_unlocked')
+//		and the JSON-encoded `data` (see `currentUserEvent`).
+//
+//
+//		Only events the current user can see are sent, i.e. events
+//
+//		* of the current user or of his teams on items visible (`can_view` >= 'info') to the participant, or
+//		* of participants the current user can watch (as a manager with `can_watch_members` of one of the participant's ancestors)
+//			on items for which the current user has `can_watch` >= 'result'.
+//
+//
+//		Events are emitted when a grade is saved, when a result is modified by the results propagation and
+//		when an item is unlocked.
+//
+//
+//		The server closes the stream after a while (`server.eventsStreamDuration`, 50 seconds by default).
+//		The client is expected to reconnect providing the id of the last received event in the `Last-Event-ID` header
+//		(which is what `EventSource` does automatically). If neither `Last-Event-ID` nor `since_id` is given,
+//		only events emitted after the connection are sent.
+//
+//
+//		As events are inserted within transactions, an event can be committed after an event having a greater id.
+//		So events emitted during the last seconds (`server.eventsCommitLag`, 10 seconds by default) are scanned again
+//		on each poll, and the SSE `id` of such events is the id of the last event emitted before this window.
+//		This way, no event is lost on reconnection, but some events can be received twice
+//		(the client can skip them using the `id` in the event data).
+//	parameters:
+//		- name: Last-Event-ID
+//			in: header
+//			description: Only events with greater ids are sent
+//			type: integer
+//			format: int64
+//		- name: since_id
+//			in: query
+//			description: Only events with greater ids are sent (ignored if `Last-Event-ID` is given)
+//			type: integer
+//			format: int64
+//	produces:
+//		- text/event-stream
+//	responses:
+//		"200":
+//			description: OK. The stream of events
+//			schema:
+//				"$ref": "#/definitions/currentUserEvent"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getEvents(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	lastEventID, hasLastEventID, err := resolveLastEventID(r)
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+	if !hasLastEventID {
+		lastEventID, err = getMaxParticipantEventID(store)
+		service.MustNotBeError(err)
+	}
+	cursor := &eventsCursor{lastEventID: lastEventID, sentEventIDs: make(map[int64]bool)}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return service.ErrUnexpected(errors.New("streaming is not supported"))
+	}
+
+	streamDuration := getDurationFromConfig(srv.ServerConfig, "eventsStreamDuration", defaultEventsStreamDuration)
+	pollInterval := getDurationFromConfig(srv.ServerConfig, "eventsPollInterval", defaultEventsPollInterval)
+	commitLag := getDurationFromConfig(srv.ServerConfig, "eventsCommitLag", defaultEventsCommitLag)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // disable buffering in nginx
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	startTime := time.Now()
+	lastWriteTime := startTime
+	for {
+		var events []currentUserEvent
+		events, err = getNewEventsVisibleByUser(store, user, cursor, commitLag)
+		if err != nil {
+			if r.Context().Err() == nil {
+				logging.GetLogEntry(r).Errorf("cannot load events: %v", err)
+			}
+			return service.NoError // the headers have been sent already, the client will reconnect
+		}
+
+		for index := range events {
+			if err = writeEvent(w, &events[index], cursor.resumeID(events[index].ID)); err != nil {
+				return service.NoError // the client has gone
+			}
+		}
+		if len(events) > 0 {
+			lastWriteTime = time.Now()
+		} else if time.Since(lastWriteTime) >= eventsHeartbeatInterval {
+			// a comment line keeps the connection alive through proxies
+			if _, err = fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return service.NoError // the client has gone
+			}
+			lastWriteTime = time.Now()
+		}
+		flusher.Flush()
+
+		if time.Since(startTime)+pollInterval > streamDuration {
+			return service.NoError
+		}
+
+		select {
+		case <-r.Context().Done():
+			return service.NoError
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func resolveLastEventID(r *http.Request) (lastEventID int64, isSet bool, err error) {
+	if headerValue := r.Header.Get("Last-Event-ID"); headerValue != "" {
+		lastEventID, err = strconv.ParseInt(headerValue, 10, 64)
+		if err != nil {
+			return 0, false, errors.New("wrong value for the Last-Event-ID header (should be int64)")
+		}
+		return lastEventID, true, nil
+	}
+
+	if len(r.URL.Query()["since_id"]) > 0 {
+		lastEventID, err = service.ResolveURLQueryGetInt64Field(r, "since_id")
+		if err != nil {
+			return 0, false, err
+		}
+		return lastEventID, true, nil
+	}
+
+	return 0, false, nil
+}
+
+func getDurationFromConfig(config *viper.Viper, key string, defaultValue time.Duration) time.Duration {
+	if config.IsSet(key) {
+		return config.GetDuration(key)
+	}
+	return defaultValue
+}
+
+func getMaxParticipantEventID(store *database.DataStore) (maxID int64, err error) {
+	err = store.ParticipantEvents().PluckFirst("IFNULL(MAX(id), 0)", &maxID).Error()
+	return maxID, err
+}
+
+// eventsCursor tracks the events already processed by the stream.
+type eventsCursor struct {
+	// all the events with ids <= lastEventID have been processed
+	lastEventID int64
+	// ids of the sent events with ids > lastEventID (which are scanned again on each poll)
+	sentEventIDs map[int64]bool
+}
+
+// resumeID returns the id from which the stream should be resumed after sending the event with the given id.
+func (c *eventsCursor) resumeID(eventID int64) int64 {
+	if eventID < c.lastEventID {
+		return eventID
+	}
+	return c.lastEventID
+}
+
+// getNewEventsVisibleByUser returns the events visible by the user not processed yet and advances the cursor
+// up to the last event emitted more than commitLag ago (taking into account all the events, even the invisible ones).
+// Events emitted during the last commitLag are scanned again on each call as events with lower ids
+// may be committed later.
+func getNewEventsVisibleByUser(store *database.DataStore, user *database.User, cursor *eventsCursor, commitLag time.Duration) (
+	events []currentUserEvent, err error,
+) {
+	var stableEventID int64
+	err = store.ParticipantEvents().
+		Where("id > ? AND created_at < NOW(3) - INTERVAL ? MICROSECOND", cursor.lastEventID, commitLag.Microseconds()).
+		PluckFirst("IFNULL(MAX(id), 0)", &stableEventID).Error()
+	if err != nil {
+		return nil, err
+	}
+
+	var newEvents []currentUserEvent
+	err = store.ParticipantEvents().VisibleBy(user).
+		Where("participant_events.id > ?", cursor.lastEventID).
+		Select("id, type, participant_id, attempt_id, item_id, created_at").
+		Order("participant_events.id").
+		Scan(&newEvents).Error()
+	if err != nil {
+		return nil, err
+	}
+
+	for index := range newEvents {
+		if !cursor.sentEventIDs[newEvents[index].ID] {
+			cursor.sentEventIDs[newEvents[index].ID] = true
+			events = append(events, newEvents[index])
+		}
+	}
+	if stableEventID > cursor.lastEventID {
+		cursor.lastEventID = stableEventID
+		for eventID := range cursor.sentEventIDs {
+			if eventID <= stableEventID {
+				delete(cursor.sentEventIDs, eventID)
+			}
+		}
+	}
+	return events, nil
+}
+
+func writeEvent(w http.ResponseWriter, event *currentUserEvent, resumeID int64) error {
+	data, err := json.Marshal(event)
+	service.MustNotBeError(err)
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", resumeID, event.Type, data)
+	return err
+}
@@ -0,0 +1,24 @@
+Feature: Stream events for the current user - robustness
+  Background:
+    Given the database has the following users:
+      | group_id | login | first_name | last_name |
+      | 11       | jdoe  | John       | Doe       |
+
+  Scenario: User doesn't exist
+    Given I am the user with id "404"
+    When I send a GET request to "/current-user/events"
+    Then the response code should be 401
+    And the response error message should contain "Invalid access token"
+
+  Scenario: Wrong since_id
+    Given I am the user with id "11"
+    When I send a GET request to "/current-user/events?since_id=abc"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for since_id (should be int64)"
+
+  Scenario: Wrong Last-Event-ID
+    Given I am the user with id "11"
+    And the "Last-Event-ID" request header is "abc"
+    When I send a GET request to "/current-user/events"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for the Last-Event-ID header (should be int64)"
@@ -44,7 +44,7 @@ import (
 //
 //
 //		In case of unexpected error (e.g. a DB error), the response will be a malformed JSON like
-//		```{"current_user":{"success":false,"message":"Internal Server Error","error_text":"Some error"}```
+//		```{"current_user":{"success":false,"message":"Internal Server Error","error_code":"internal_server_error","error_text":"Some error"}```
 //	produces:
 //		- application/json
 //	responses:
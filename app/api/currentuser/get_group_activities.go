@@ -1,13 +1,13 @@
 package currentuser
 
 import (
-	"errors"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/render"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/structures"
 )
@@ -143,7 +143,7 @@ func (srv *Service) getRootItems(w http.ResponseWriter, r *http.Request, getActi
 		return apiError
 	}
 	if watchedGroupIDIsSet && len(r.URL.Query()["as_team_id"]) != 0 {
-		return service.ErrInvalidRequest(errors.New("only one of as_team_id and watched_group_id can be given"))
+		return service.ErrInvalidRequest(i18n.NewError("both_team_and_watched_group"))
 	}
 
 	rawData := getRootItemsFromDB(store, participantID, watchedGroupID, watchedGroupIDIsSet, user, getActivities)
@@ -1,9 +1,14 @@
 Feature: Get user info for the current user
   Background:
     Given the database has the following users:
-      | group_id | temp_user | login | registered_at       | latest_profile_sync_at | email          | first_name | last_name | student_id | country_code | time_zone | birth_date | graduation_year | grade | sex  | address          | zipcode | city          | land_line_number | cell_phone_number | default_language | public_first_name | public_last_name | notify_news | notify  | free_text | web_site   | photo_autoload | lang_prog | basic_editor_mode | spaces_for_tab | step_level_in_site | is_admin | no_ranking |
-      | 2        | 0         | user  | 2017-02-26 06:38:38 | 2019-05-30 12:00:00    | user@gmail.com | John       | Doe       | Some id    | us           | PT        | 1975-12-13 | 1997            | 10    | Male | 314 N Beverly Dr | 90210   | Beverly Hills | +1 310-435-9669  | +1 310-860-9581   | en               | true              | true             | true        | Answers | Some text | mysite.com | true           | Python    | true              | 3              | 11                 | false    | false      |
-      | 3        | 1         | jane  | null                | null                   | null           | null       | null      | null       |              | null      | null       | 0               | null  | null | null             | null    | null          | null             | null              | fr               | false             | false            | false       | Never   | null      | null       | false          | null      | false             | 0              | 0                  | true     | true       |
+      | group_id | temp_user | login | registered_at       | latest_profile_sync_at | email          | first_name | last_name | student_id | country_code | time_zone | birth_date | graduation_year | grade | sex  | address          | zipcode | city          | land_line_number | cell_phone_number | default_language | public_first_name | public_last_name | notify_news | notify  | free_text | web_site   | photo_autoload | lang_prog | basic_editor_mode | spaces_for_tab | step_level_in_site | is_admin | no_ranking | email_notifications_opt_out            |
+      | 2        | 0         | user  | 2017-02-26 06:38:38 | 2019-05-30 12:00:00    | user@gmail.com | John       | Doe       | Some id    | us           | PT        | 1975-12-13 | 1997            | 10    | Male | 314 N Beverly Dr | 90210   | Beverly Hills | +1 310-435-9669  | +1 310-860-9581   | en               | true              | true             | true        | Answers | Some text | mysite.com | true           | Python    | true              | 3              | 11                 | false    | false      |                                        |
+      | 3        | 1         | jane  | null                | null                   | null           | null       | null      | null       |              | null      | null       | 0               | null  | null | null             | null    | null          | null             | null              | fr               | false             | false            | false       | Never   | null      | null       | false          | null      | false             | 0              | 0                  | true     | true       | invitation_created,membership_expiring |
+    And the database has the following table "notifications":
+      | id | user_id | type           | payload | created_at          | read_at             |
+      | 1  | 2       | removed        | {}      | 2019-05-30 12:00:00 | null                |
+      | 2  | 2       | thread_updated | {}      | 2019-05-30 12:00:01 | 2019-05-30 12:00:02 |
+      | 3  | 2       | answer_graded  | {}      | 2019-05-30 12:00:03 | null                |
 
   Scenario: All field values are not nulls
     Given I am the user with id "2"
@@ -46,7 +51,9 @@ Feature: Get user info for the current user
       "spaces_for_tab": 3,
       "step_level_in_site": 11,
       "is_admin": false,
-      "no_ranking": false
+      "no_ranking": false,
+      "email_notifications_opt_out": [],
+      "unread_notifications_count": 2
     }
     """
 
@@ -91,6 +98,8 @@ Feature: Get user info for the current user
       "spaces_for_tab": 0,
       "step_level_in_site": 0,
       "is_admin": true,
-      "no_ranking": true
+      "no_ranking": true,
+      "email_notifications_opt_out": ["invitation_created", "membership_expiring"],
+      "unread_notifications_count": 0
     }
     """
@@ -2,6 +2,7 @@ package currentuser
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/render"
 	"github.com/jinzhu/gorm"
@@ -85,13 +86,23 @@ type getInfoData struct {
 	IsAdmin bool `json:"is_admin"`
 	// required: true
 	NoRanking bool `json:"no_ranking"`
+	// Types of email notifications the user does not want to receive
+	// required: true
+	// items:
+	//   enum: invitation_created,join_request_accepted,thread_status_changed,membership_expiring
+	EmailNotificationsOptOut []string `json:"email_notifications_opt_out" gorm:"-"`
+	// Number of unread notifications (see `GET /current-user/notifications`)
+	// required: true
+	UnreadNotificationsCount int64 `json:"unread_notifications_count" gorm:"-"`
+
+	EmailNotificationsOptOutList string `json:"-"`
 }
 
 // swagger:operation GET /current-user users userData
 //
 //	---
 //	summary: Get profile info for the current user
-//	description: Returns the data from the `users` table.
+//	description: Returns the data from the `users` table together with the number of unread notifications.
 //	responses:
 //		"200":
 //				description: OK. Success response with user's data
@@ -115,7 +126,7 @@ func (srv *Service) getInfo(w http.ResponseWriter, r *http.Request) service.APIE
 			CONVERT(birth_date, char) AS birth_date, graduation_year, grade, sex, address, zipcode,
 			city, land_line_number, cell_phone_number, default_language, public_first_name, public_last_name,
 			notify_news, notify, free_text, web_site, photo_autoload, lang_prog, basic_editor_mode, spaces_for_tab,
-			step_level_in_site, is_admin, no_ranking`).
+			step_level_in_site, is_admin, no_ranking, email_notifications_opt_out AS email_notifications_opt_out_list`).
 		Scan(&userInfo).Error()
 
 	// This is very unlikely since the user middleware has already checked that the user exists
@@ -124,6 +135,14 @@ func (srv *Service) getInfo(w http.ResponseWriter, r *http.Request) service.APIE
 	}
 	service.MustNotBeError(err)
 
+	userInfo.EmailNotificationsOptOut = []string{}
+	if userInfo.EmailNotificationsOptOutList != "" {
+		userInfo.EmailNotificationsOptOut = strings.Split(userInfo.EmailNotificationsOptOutList, ",")
+	}
+
+	userInfo.UnreadNotificationsCount, err = srv.GetStore(r).Notifications().CountUnread(user.GroupID)
+	service.MustNotBeError(err)
+
 	render.Respond(w, r, &userInfo)
 	return service.NoError
 }
@@ -0,0 +1,95 @@
+Feature: List notifications of the current user (notificationsView)
+  Background:
+    Given the database has the following users:
+      | group_id | login | notifications_read_at |
+      | 11       | user  | 2025-03-01 10:00:00   |
+      | 12       | jane  | null                  |
+      | 13       | john  | null                  |
+    And the database has the following table "notifications":
+      | id | user_id | type                 | payload                                                                         | created_at              | read_at             |
+      | 1  | 11      | invitation_created   | {"group_id": "13", "group_name": "Our Class"}                                   | 2025-03-01 09:00:00     | null                |
+      | 2  | 11      | join_request_refused | {"group_id": "14", "group_name": "Our Club"}                                    | 2025-03-01 11:00:00     | null                |
+      | 3  | 11      | thread_updated       | {"item_id": "210", "participant_id": "11", "status": "waiting_for_participant"} | 2025-03-01 12:00:00     | 2025-03-01 12:30:00 |
+      | 4  | 11      | answer_graded        | {"answer_id": "100", "item_id": "210", "participant_id": "11"}                  | 2025-03-01 12:00:00     | null                |
+      | 5  | 12      | removed              | {"group_id": "13", "group_name": "Our Class"}                                   | 2025-03-01 12:00:00.001 | null                |
+
+  Scenario: List notifications (the latest first)
+    Given I am the user with id "11"
+    When I send a GET request to "/current-user/notifications"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "id": "4",
+        "type": "answer_graded",
+        "payload": {"answer_id": "100", "item_id": "210", "participant_id": "11"},
+        "created_at": "2025-03-01T12:00:00Z",
+        "is_read": false
+      },
+      {
+        "id": "3",
+        "type": "thread_updated",
+        "payload": {"item_id": "210", "participant_id": "11", "status": "waiting_for_participant"},
+        "created_at": "2025-03-01T12:00:00Z",
+        "is_read": true
+      },
+      {
+        "id": "2",
+        "type": "join_request_refused",
+        "payload": {"group_id": "14", "group_name": "Our Club"},
+        "created_at": "2025-03-01T11:00:00Z",
+        "is_read": false
+      },
+      {
+        "id": "1",
+        "type": "invitation_created",
+        "payload": {"group_id": "13", "group_name": "Our Class"},
+        "created_at": "2025-03-01T09:00:00Z",
+        "is_read": true
+      }
+    ]
+    """
+
+  Scenario: Start from the notification next to the given one
+    Given I am the user with id "11"
+    When I send a GET request to "/current-user/notifications?from.id=3&limit=1"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "id": "2",
+        "type": "join_request_refused",
+        "payload": {"group_id": "14", "group_name": "Our Club"},
+        "created_at": "2025-03-01T11:00:00Z",
+        "is_read": false
+      }
+    ]
+    """
+
+  Scenario: Sort by id
+    Given I am the user with id "12"
+    When I send a GET request to "/current-user/notifications?sort=id"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "id": "5",
+        "type": "removed",
+        "payload": {"group_id": "13", "group_name": "Our Class"},
+        "created_at": "2025-03-01T12:00:00.001Z",
+        "is_read": false
+      }
+    ]
+    """
+
+  Scenario: No notifications
+    Given I am the user with id "13"
+    When I send a GET request to "/current-user/notifications"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    []
+    """
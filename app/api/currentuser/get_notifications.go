@@ -0,0 +1,118 @@
+package currentuser
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:model notificationsViewResponseRow
+type notificationsViewResponseRow struct {
+	// required: true
+	ID int64 `json:"id,string"`
+	// required: true
+	// enum: invitation_created,join_request_accepted,join_request_refused,removed,thread_updated,answer_graded
+	Type string `json:"type"`
+	// The objects the notification is about:
+	//
+	// * `group_id` & `group_name` for 'invitation_created', 'join_request_accepted', 'join_request_refused', and 'removed',
+	//
+	// * `item_id`, `participant_id` & `status` for 'thread_updated',
+	//
+	// * `answer_id`, `item_id` & `participant_id` for 'answer_graded'
+	// required: true
+	Payload json.RawMessage `json:"payload"`
+	// required: true
+	CreatedAt database.Time `json:"created_at"`
+	// Whether the notification has been marked as read or has been created before `users.notifications_read_at`
+	// required: true
+	IsRead bool `json:"is_read"`
+
+	PayloadJSON string `json:"-"`
+}
+
+// swagger:operation GET /current-user/notifications users notificationsView
+//
+//	---
+//	summary: List notifications of the current user
+//	description: >
+//
+//		Lists the in-app notifications of the current user, the latest first.
+//		Notifications are created when the user is invited to a group, when a join request of the user is accepted
+//		or refused, when the user is removed from a group, when a thread the user participates in (or helps in) is updated
+//		by someone else, and when an answer of the user (or of their team) is graded manually.
+//
+//
+//		A notification is read if it has been marked as read (`PUT /current-user/notifications/{notification_id}/read-at`)
+//		or if it has been created before `users.notifications_read_at` (`PUT /current-user/notifications-read-at`).
+//		The number of unread notifications is returned by `GET /current-user`.
+//	parameters:
+//		- name: sort
+//			in: query
+//			default: [-created_at,-id]
+//			type: array
+//			items:
+//				type: string
+//				enum: [created_at,-created_at,id,-id]
+//		- name: from.id
+//			description: Start the page from the notification next to the notification with `id`=`{from.id}`
+//			in: query
+//			type: integer
+//			format: int64
+//		- name: limit
+//			description: Display the first N notifications
+//			in: query
+//			type: integer
+//			maximum: 1000
+//			default: 500
+//	responses:
+//		"200":
+//			description: OK. The array of notifications
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/notificationsViewResponseRow"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getNotifications(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+
+	query := srv.GetStore(r).Notifications().ForUser(user.GroupID).
+		Select(`
+			notifications.id, notifications.type, CAST(notifications.payload AS CHAR) AS payload_json,
+			notifications.created_at, ` + database.NotificationIsReadColumn + ` AS is_read`)
+
+	query = service.NewQueryLimiter().Apply(r, query)
+	query, apiError := service.ApplySortingAndPaging(
+		r, query,
+		&service.SortingAndPagingParameters{
+			Fields: service.SortingAndPagingFields{
+				"created_at": {ColumnName: "notifications.created_at"},
+				"id":         {ColumnName: "notifications.id"},
+			},
+			DefaultRules: "-created_at,-id",
+			TieBreakers:  service.SortingAndPagingTieBreakers{"id": service.FieldTypeInt64},
+		})
+	if apiError != service.NoError {
+		return apiError
+	}
+
+	result := make([]notificationsViewResponseRow, 0)
+	service.MustNotBeError(query.Scan(&result).Error())
+	for index := range result {
+		result[index].Payload = json.RawMessage(result[index].PayloadJSON)
+	}
+
+	render.Respond(w, r, result)
+	return service.NoError
+}
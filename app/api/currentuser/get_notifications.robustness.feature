@@ -0,0 +1,17 @@
+Feature: List notifications of the current user (notificationsView) - robustness
+  Background:
+    Given the database has the following user:
+      | group_id | login |
+      | 11       | user  |
+
+  Scenario: Wrong sorting
+    Given I am the user with id "11"
+    When I send a GET request to "/current-user/notifications?sort=type"
+    Then the response code should be 400
+    And the response error message should contain "Unallowed field in sorting parameters: "type""
+
+  Scenario: Invalid from.id
+    Given I am the user with id "11"
+    When I send a GET request to "/current-user/notifications?from.id=abc"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for from.id (should be int64)"
@@ -74,6 +74,8 @@ import (
 //			"$ref": "#/responses/conflictResponse"
 //		"422":
 //			"$ref": "#/responses/unprocessableEntityResponseWithMissingApprovals"
+//		"429":
+//			"$ref": "#/responses/tooManyRequestsResponse"
 //		"500":
 //			"$ref": "#/responses/internalErrorResponse"
 func (srv *Service) joinGroupByCode(w http.ResponseWriter, r *http.Request) service.APIError {
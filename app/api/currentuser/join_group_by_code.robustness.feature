@@ -82,6 +82,7 @@ Feature: Join a group using a code (groupsJoinByCode) - robustness
       {
         "success": false,
         "message": "Unprocessable Entity",
+        "error_code": "unprocessable_entity",
         "error_text": "A conflicting relation exists"
       }
       """
@@ -98,6 +99,7 @@ Feature: Join a group using a code (groupsJoinByCode) - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Team's participations are in conflict with the user's participations"
     }
     """
@@ -113,6 +115,7 @@ Feature: Join a group using a code (groupsJoinByCode) - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "approvals_missing",
       "data": {"missing_approvals":["watch"]},
       "error_text": "Missing required approvals"
     }
@@ -130,6 +133,7 @@ Feature: Join a group using a code (groupsJoinByCode) - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Group membership is frozen"
     }
     """
@@ -146,6 +150,7 @@ Feature: Join a group using a code (groupsJoinByCode) - robustness
     {
       "success": false,
       "message": "Conflict",
+      "error_code": "conflict",
       "error_text": "The group is full"
     }
     """
@@ -171,6 +176,7 @@ Feature: Join a group using a code (groupsJoinByCode) - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "Entry conditions would not be satisfied"
     }
     """
@@ -187,6 +193,7 @@ Feature: Join a group using a code (groupsJoinByCode) - robustness
     {
       "success": false,
       "message": "Forbidden",
+      "error_code": "insufficient_access_rights",
       "error_text": "Insufficient access rights"
     }
     """
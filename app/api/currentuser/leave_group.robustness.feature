@@ -32,6 +32,7 @@ Feature: User leaves a group - robustness
     {
       "success": false,
       "message": "Not Found",
+      "error_code": "not_found",
       "error_text": "No such relation"
     }
     """
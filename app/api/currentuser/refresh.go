@@ -1,6 +1,7 @@
 package currentuser
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/render"
@@ -17,17 +18,32 @@ import (
 //	summary: Refresh the local user info cache
 //	description: Gets the user info from the login module, updates the local user info cache stored in the `users` table
 //						 and badges.
+//
+//
+//						 The service is not available for users logged in with an OpenID Connect identity provider
+//						 since their profiles are synchronized on login.
 //	responses:
 //		"200":
 //			"$ref": "#/responses/updatedResponse"
 //		"401":
 //			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
 //		"408":
 //			"$ref": "#/responses/requestTimeoutResponse"
 //		"500":
 //			"$ref": "#/responses/internalErrorResponse"
 func (srv *Service) refresh(w http.ResponseWriter, r *http.Request) service.APIError {
 	user := srv.GetUser(r)
+
+	var identityProvider string
+	service.MustNotBeError(srv.GetStore(r).Sessions().Where("session_id = ?", srv.GetSessionID(r)).
+		PluckFirst("identity_provider", &identityProvider).Error())
+	if identityProvider != auth.IdentityProviderLoginModule {
+		return service.ErrForbidden(
+			errors.New("the profile of a user logged in with an OpenID Connect provider is synchronized on login"))
+	}
+
 	accessToken := auth.BearerTokenFromContext(r.Context())
 
 	userProfile, err := loginmodule.NewClient(srv.AuthConfig.GetString("loginModuleURL")).GetUserProfile(r.Context(), accessToken)
@@ -0,0 +1,16 @@
+Feature: Refresh the local user info cache - robustness
+  Scenario: The profile of a user logged in with an OpenID Connect provider cannot be refreshed
+    Given the database has the following users:
+      | group_id | login | login_id |
+      | 11       | jdoe  | null     |
+    And the database has the following table "sessions":
+      | session_id | user_id | identity_provider |
+      | 1          | 11      | oidc              |
+    And the database has the following table "access_tokens":
+      | session_id | token       | expires_at          | issued_at           |
+      | 1          | accesstoken | 3020-06-16 22:02:49 | 2019-06-16 22:02:28 |
+    And the "Authorization" request header is "Bearer accesstoken"
+    When I send a PUT request to "/current-user/refresh"
+    Then the response code should be 403
+    And the response error message should contain "The profile of a user logged in with an OpenID Connect provider is synchronized on login"
+    And the table "users" should stay unchanged
@@ -24,6 +24,7 @@ Feature: User rejects an invitation to join a group - robustness
     {
       "success": false,
       "message": "Not Found",
+      "error_code": "not_found",
       "error_text": "No such relation"
     }
     """
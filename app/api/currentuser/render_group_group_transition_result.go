@@ -37,6 +37,7 @@ func RenderGroupGroupTransitionResult(w http.ResponseWriter, r *http.Request, re
 				Success:        false,
 				Message:        "Unprocessable Entity",
 			},
+			ErrorCode: "approvals_missing",
 			ErrorText: "Missing required approvals",
 			Errors:    nil,
 		}
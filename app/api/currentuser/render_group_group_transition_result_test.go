@@ -26,21 +26,21 @@ func TestRenderGroupGroupTransitionResult(t *testing.T) {
 			result:         database.Cycle,
 			wantStatusCode: http.StatusUnprocessableEntity,
 			wantResponseBody: `{"success":false,"message":"Unprocessable Entity",` +
-				`"error_text":"Cycles in the group relations graph are not allowed"}`,
+				`"error_code":"unprocessable_entity","error_text":"Cycles in the group relations graph are not allowed"}`,
 		},
 		{
 			name:           "full",
 			result:         database.Full,
 			wantStatusCode: http.StatusConflict,
 			wantResponseBody: `{"success":false,"message":"Conflict",` +
-				`"error_text":"The group is full"}`,
+				`"error_code":"conflict","error_text":"The group is full"}`,
 		},
 		{
 			name:             "invalid (not found)",
 			result:           database.Invalid,
 			actions:          []userGroupRelationAction{acceptInvitationAction, rejectInvitationAction, leaveGroupAction},
 			wantStatusCode:   http.StatusNotFound,
-			wantResponseBody: `{"success":false,"message":"Not Found","error_text":"No such relation"}`,
+			wantResponseBody: `{"success":false,"message":"Not Found","error_code":"not_found","error_text":"No such relation"}`,
 		},
 		{
 			name:           "invalid (unprocessable entity)",
@@ -48,7 +48,7 @@ func TestRenderGroupGroupTransitionResult(t *testing.T) {
 			actions:        []userGroupRelationAction{createGroupJoinRequestAction, joinGroupByCodeAction},
 			wantStatusCode: http.StatusUnprocessableEntity,
 			wantResponseBody: `{"success":false,"message":"Unprocessable Entity",` +
-				`"error_text":"A conflicting relation exists"}`,
+				`"error_code":"unprocessable_entity","error_text":"A conflicting relation exists"}`,
 		},
 		{
 			name:             "unchanged (created)",
@@ -86,10 +86,11 @@ func TestRenderGroupGroupTransitionResult(t *testing.T) {
 			wantResponseBody: `{"success":true,"message":"deleted","data":{"changed":true}}`,
 		},
 		{
-			name:             "approvals_missing",
-			result:           database.ApprovalsMissing,
-			wantStatusCode:   http.StatusUnprocessableEntity,
-			wantResponseBody: `{"success":false,"message":"Unprocessable Entity","error_text":"Missing required approvals"}`,
+			name:           "approvals_missing",
+			result:         database.ApprovalsMissing,
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantResponseBody: `{"success":false,"message":"Unprocessable Entity","error_code":"approvals_missing",` +
+				`"error_text":"Missing required approvals"}`,
 		},
 		{
 			name:   "approvals_missing (with approvals listed)",
@@ -102,7 +103,7 @@ func TestRenderGroupGroupTransitionResult(t *testing.T) {
 			wantStatusCode: http.StatusUnprocessableEntity,
 			wantResponseBody: `{"success":false,"message":"Unprocessable Entity",` +
 				`"data":{"missing_approvals":["personal_info_view","lock_membership","watch"]},` +
-				`"error_text":"Missing required approvals"}`,
+				`"error_code":"approvals_missing","error_text":"Missing required approvals"}`,
 		},
 	}
 	for _, tt := range tests {
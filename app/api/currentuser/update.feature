@@ -21,3 +21,40 @@ Feature: Update user data
     And the table "users" at group_id "11" should be:
       | group_id | latest_login_at     | latest_activity_at  | registered_at       | default_language   |
       | 11       | 2019-06-16 21:01:25 | 2019-06-16 22:05:44 | 2019-05-10 10:42:11 | sl                 |
+
+  Scenario: Update the email notifications opted out
+    Given I am the user with id "11"
+    And the database has the following users:
+      | group_id | login    | default_language | email_notifications_opt_out |
+      | 11       | mohammed | en               | thread_status_changed       |
+      | 13       | john     | en               |                             |
+    When I send a PUT request to "/current-user" with the following body:
+      """
+      {"email_notifications_opt_out": ["invitation_created", "membership_expiring"]}
+      """
+    Then the response code should be 200
+    And the response body should be, in JSON:
+      """
+      {
+        "success": true,
+        "message": "updated"
+      }
+      """
+    And the table "users" should stay unchanged but the row with group_id "11"
+    And the table "users" at group_id "11" should be:
+      | group_id | default_language | email_notifications_opt_out            |
+      | 11       | en               | invitation_created,membership_expiring |
+
+  Scenario: Opt in for all the email notifications
+    Given I am the user with id "11"
+    And the database has the following users:
+      | group_id | login    | default_language | email_notifications_opt_out |
+      | 11       | mohammed | en               | thread_status_changed       |
+    When I send a PUT request to "/current-user" with the following body:
+      """
+      {"email_notifications_opt_out": []}
+      """
+    Then the response code should be 200
+    And the table "users" at group_id "11" should be:
+      | group_id | default_language | email_notifications_opt_out |
+      | 11       | en               |                             |
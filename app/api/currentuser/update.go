@@ -2,6 +2,7 @@ package currentuser
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/render"
 
@@ -12,13 +13,17 @@ import (
 // swagger:model
 type userDataUpdateRequest struct {
 	DefaultLanguage string `json:"default_language"`
+	// Types of email notifications the user does not want to receive (replaces the current list)
+	// items:
+	//   enum: invitation_created,join_request_accepted,thread_status_changed,membership_expiring
+	EmailNotificationsOptOut []string `json:"email_notifications_opt_out" gorm:"-" validate:"unique,dive,oneof=invitation_created join_request_accepted thread_status_changed membership_expiring"` //nolint:lll
 }
 
 // swagger:operation PUT /current-user users userDataUpdate
 //
 //	---
 //	summary: Update user's data
-//	description: Allows changing the user's default language
+//	description: Allows changing the user's default language and the types of email notifications the user has opted out of
 //	parameters:
 //		- name: data
 //			in: body
@@ -44,8 +49,13 @@ func (srv *Service) update(w http.ResponseWriter, r *http.Request) service.APIEr
 		return service.ErrInvalidRequest(err)
 	}
 
+	store := srv.GetStore(r)
 	// the user middleware has already checked that the user exists so we just ignore the case where nothing is updated
-	service.MustNotBeError(srv.GetStore(r).Users().ByID(user.GroupID).UpdateColumn(requestData).Error())
+	service.MustNotBeError(store.Users().ByID(user.GroupID).UpdateColumn(requestData).Error())
+	if formData.IsSet("email_notifications_opt_out") {
+		service.MustNotBeError(store.Users().ByID(user.GroupID).
+			UpdateColumn("email_notifications_opt_out", strings.Join(requestData.EmailNotificationsOptOut, ",")).Error())
+	}
 
 	response := service.Response[*struct{}]{Success: true, Message: "updated"}
 	render.Respond(w, r, &response)
@@ -17,6 +17,7 @@ Feature: Update user data - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "default_language":["should not be null (expected type: string)"]
@@ -24,3 +25,24 @@ Feature: Update user data - robustness
       }
       """
     And the table "users" should stay unchanged
+
+  Scenario: invalid email_notifications_opt_out
+    Given I am the user with id "11"
+    When I send a PUT request to "/current-user" with the following body:
+      """
+      {"email_notifications_opt_out": ["invitation_created", "unknown"]}
+      """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "Bad Request",
+        "error_code": "invalid_input_data",
+        "error_text": "Invalid input data",
+        "errors":{
+          "email_notifications_opt_out[1]":["email_notifications_opt_out[1] must be one of [invitation_created join_request_accepted thread_status_changed membership_expiring]"]
+         }
+      }
+      """
+    And the table "users" should stay unchanged
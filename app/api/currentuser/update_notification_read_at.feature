@@ -0,0 +1,24 @@
+Feature: Mark a notification of the current user as read (notificationReadDateUpdate)
+  Background:
+    Given the database has the following users:
+      | group_id | login |
+      | 11       | user  |
+    And the database has the following table "notifications":
+      | id | user_id | type    | payload | created_at          | read_at             |
+      | 1  | 11      | removed | {}      | 2025-03-01 09:00:00 | null                |
+      | 2  | 11      | removed | {}      | 2025-03-01 10:00:00 | 2025-03-01 11:00:00 |
+
+  Scenario: Mark an unread notification as read
+    Given I am the user with id "11"
+    When I send a PUT request to "/current-user/notifications/1/read-at"
+    Then the response should be "updated"
+    And the table "notifications" should stay unchanged but the row with id "1"
+    And the table "notifications" at id "1" should be:
+      | id | user_id | ABS(TIMESTAMPDIFF(SECOND, read_at, NOW())) < 3 |
+      | 1  | 11      | 1                                              |
+
+  Scenario: Keep the read date of a notification already marked as read
+    Given I am the user with id "11"
+    When I send a PUT request to "/current-user/notifications/2/read-at"
+    Then the response should be "updated"
+    And the table "notifications" should stay unchanged
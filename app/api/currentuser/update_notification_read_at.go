@@ -0,0 +1,61 @@
+package currentuser
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:operation PUT /current-user/notifications/{notification_id}/read-at users notificationReadDateUpdate
+//
+//	---
+//	summary: Mark a notification as read
+//	description: >
+//
+//		Sets `notifications.read_at` to NOW() for the given notification of the current user
+//		(if the notification has not been marked as read yet).
+//
+//
+//		If the notification doesn't exist or doesn't belong to the current user, the 'forbidden' error is returned.
+//	parameters:
+//		- name: notification_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//	responses:
+//		"200":
+//			"$ref": "#/responses/updatedResponse"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) updateNotificationReadAt(w http.ResponseWriter, r *http.Request) service.APIError {
+	notificationID, err := service.ResolveURLQueryPathInt64Field(r, "notification_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+	found, err := store.Notifications().Where("id = ? AND user_id = ?", notificationID, user.GroupID).HasRows()
+	service.MustNotBeError(err)
+	if !found {
+		return service.InsufficientAccessRightsError
+	}
+
+	service.MustNotBeError(store.Notifications().Where("id = ? AND user_id = ?", notificationID, user.GroupID).
+		UpdateColumn("read_at", gorm.Expr("IFNULL(read_at, NOW(3))")).Error())
+
+	service.MustNotBeError(render.Render(w, r, service.UpdateSuccess[*struct{}](nil)))
+	return service.NoError
+}
@@ -0,0 +1,30 @@
+Feature: Mark a notification of the current user as read (notificationReadDateUpdate) - robustness
+  Background:
+    Given the database has the following users:
+      | group_id | login |
+      | 11       | user  |
+      | 12       | jane  |
+    And the database has the following table "notifications":
+      | id | user_id | type    | payload | created_at          |
+      | 1  | 12      | removed | {}      | 2025-03-01 09:00:00 |
+
+  Scenario: Invalid notification_id
+    Given I am the user with id "11"
+    When I send a PUT request to "/current-user/notifications/abc/read-at"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for notification_id (should be int64)"
+    And the table "notifications" should stay unchanged
+
+  Scenario: The notification belongs to another user
+    Given I am the user with id "11"
+    When I send a PUT request to "/current-user/notifications/1/read-at"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "notifications" should stay unchanged
+
+  Scenario: The notification doesn't exist
+    Given I am the user with id "11"
+    When I send a PUT request to "/current-user/notifications/404/read-at"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "notifications" should stay unchanged
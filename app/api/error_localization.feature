@@ -0,0 +1,125 @@
+Feature: Localization of error messages
+  Background:
+    Given the database has the following users:
+      | group_id | login | default_language |
+      | 11       | jdoe  | de               |
+      | 12       | jane  | xx               |
+      | 13       | jean  | fr               |
+
+  Scenario Outline: The language is negotiated from the Accept-Language header
+    Given the "Accept-Language" request header is "<accept_language>"
+    When I send a GET request to "/non-existing-path"
+    Then the response code should be 404
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "<message>",
+        "error_code": "not_found"
+      }
+      """
+  Examples:
+    | accept_language        | message        |
+    | fr                     | Introuvable    |
+    | fr-CH, en;q=0.8        | Introuvable    |
+    | es, de;q=0.5, fr;q=0.3 | Nicht gefunden |
+    | ar                     | غير موجود      |
+    | es                     | Not Found      |
+    | en                     | Not Found      |
+
+  Scenario: An error message with a parameter is localized
+    Given I am the user with id "12"
+    And the "Accept-Language" request header is "fr"
+    When I send a GET request to "/answers/abc"
+    Then the response code should be 400
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "Requête invalide",
+        "error_code": "wrong_int64_value",
+        "error_text": "Valeur incorrecte pour answer_id (un entier est attendu)"
+      }
+      """
+
+  Scenario: Errors of fields are localized
+    Given I am the user with id "12"
+    And the "Accept-Language" request header is "fr"
+    When I send a POST request to "/answers/123/grade" with the following body:
+      """
+      {"score": 101, "comment": "Good"}
+      """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "Requête invalide",
+        "error_code": "invalid_input_data",
+        "error_text": "Données invalides",
+        "errors": {
+          "score": ["score doit être inférieur ou égal à 100"],
+          "comment": ["champ inattendu"]
+        }
+      }
+      """
+
+  Scenario: The default language of the user is used when the Accept-Language header requests no supported language
+    Given I am the user with id "11"
+    And the "Accept-Language" request header is "es"
+    When I send a GET request to "/answers/404"
+    Then the response code should be 403
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "Zugriff verweigert",
+        "error_code": "insufficient_access_rights",
+        "error_text": "Unzureichende Zugriffsrechte"
+      }
+      """
+
+  Scenario: The default language of the user is used when there is no Accept-Language header
+    Given I am the user with id "13"
+    And the "Accept-Language" request header is ""
+    When I send a GET request to "/answers/404"
+    Then the response code should be 403
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "Accès interdit",
+        "error_code": "insufficient_access_rights",
+        "error_text": "Droits d'accès insuffisants"
+      }
+      """
+
+  Scenario: An error message missing from the catalogue is not localized
+    Given I am the user with id "11"
+    And the "Accept-Language" request header is ""
+    When I send a GET request to "/items/search?search=ab"
+    Then the response code should be 400
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "Ungültige Anfrage",
+        "error_code": "bad_request",
+        "error_text": "The search string should be at least 3 characters long"
+      }
+      """
+
+  Scenario: The default language is used when neither the request nor the user selects a supported language
+    Given I am the user with id "12"
+    And the "Accept-Language" request header is ""
+    When I send a GET request to "/answers/abc"
+    Then the response code should be 400
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "Bad Request",
+        "error_code": "wrong_int64_value",
+        "error_text": "Wrong value for answer_id (should be int64)"
+      }
+      """
@@ -0,0 +1,66 @@
+Feature: Request an export (exportCreate)
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+
+  Scenario: Request an export of the progress of groups
+    Given I am the user with id "21"
+    When I send a POST request to "/exports" with the following body:
+    """
+    {
+      "type": "group_progress",
+      "group_id": "13",
+      "parent_item_ids": ["210", "220"]
+    }
+    """
+    Then the response code should be 201
+    And the response body should be, in JSON:
+    """
+    {
+      "success": true,
+      "message": "created",
+      "data": {"id": "5577006791947779410"}
+    }
+    """
+    And the table "export_jobs" should be:
+      | id                  | user_id | type           | parameters                                            | domain    | status  | started_at | finished_at | error | artifact_key | expires_at | ABS(TIMESTAMPDIFF(SECOND, NOW(), created_at)) < 3 |
+      | 5577006791947779410 | 21      | group_progress | {"group_id": "13", "parent_item_ids": ["210", "220"]} | 127.0.0.1 | pending | null       | null        | null  | null         | null       | 1                                                 |
+
+  Scenario: Request an NDJSON export of the progress of teams
+    Given I am the user with id "21"
+    When I send a POST request to "/exports" with the following body:
+    """
+    {
+      "type": "team_progress",
+      "group_id": "13",
+      "parent_item_ids": ["210"],
+      "format": "ndjson"
+    }
+    """
+    Then the response code should be 201
+    And the table "export_jobs" should be:
+      | id                  | user_id | type          | parameters                                                         | status  |
+      | 5577006791947779410 | 21      | team_progress | {"format": "ndjson", "group_id": "13", "parent_item_ids": ["210"]} | pending |
+
+  Scenario: Request a full dump
+    Given I am the user with id "21"
+    When I send a POST request to "/exports" with the following body:
+    """
+    {"type": "full_dump"}
+    """
+    Then the response code should be 201
+    And the table "export_jobs" should be:
+      | id                  | user_id | type      | parameters | status  |
+      | 5577006791947779410 | 21      | full_dump | {}         | pending |
+
+  Scenario: Request an export of the activity log of an item
+    Given I am the user with id "21"
+    When I send a POST request to "/exports" with the following body:
+    """
+    {"type": "activity_log", "item_id": "210", "watched_group_id": "13"}
+    """
+    Then the response code should be 201
+    And the table "export_jobs" should be:
+      | id                  | user_id | type         | parameters                                   | status  |
+      | 5577006791947779410 | 21      | activity_log | {"item_id": "210", "watched_group_id": "13"} | pending |
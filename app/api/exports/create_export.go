@@ -0,0 +1,171 @@
+package exports
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/France-ioi/validator"
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/domain"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/exports"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:model createExportRequest
+type createExportRequest struct {
+	// required: true
+	// enum: group_progress,user_progress,team_progress,full_dump,activity_log
+	Type string `json:"type" validate:"set,oneof=group_progress user_progress team_progress full_dump activity_log"`
+	// Required for 'group_progress', 'user_progress' & 'team_progress'
+	GroupID int64 `json:"group_id,string" validate:"export_parameter_allowed"`
+	// Required for 'group_progress', 'user_progress' & 'team_progress'
+	ParentItemIDs []int64 `json:"parent_item_ids" validate:"export_parameter_allowed"`
+	// Allowed for 'group_progress', 'user_progress' & 'team_progress' only
+	// enum: csv,ndjson
+	Format string `json:"format" validate:"export_parameter_allowed,omitempty,oneof=csv ndjson"`
+	// Allowed for 'activity_log' only
+	ItemID int64 `json:"item_id,string" validate:"export_parameter_allowed"`
+	// Allowed for 'activity_log' only
+	WatchedGroupID int64 `json:"watched_group_id,string" validate:"export_parameter_allowed"`
+	// Allowed for 'activity_log' only
+	AsTeamID int64 `json:"as_team_id,string" validate:"export_parameter_allowed"`
+}
+
+// swagger:model createExportResponse
+type createExportResponse struct {
+	// required: true
+	ID int64 `json:"id,string"`
+}
+
+// swagger:operation POST /exports exports exportCreate
+//
+//	---
+//	summary: Request an export
+//	description: >
+//
+//		Creates an export job which is run asynchronously (by the export worker) on behalf of the current user.
+//		The status of the job and the link to download its result can be obtained with the `exportView` service.
+//
+//
+//		The types of exports and their parameters are:
+//
+//		* 'group_progress', 'user_progress', 'team_progress': same as `groupGroupProgressCSV`, `groupUserProgressCSV`
+//			& `groupTeamProgressCSV` (`group_id` & `parent_item_ids` are required, `format` is optional),
+//		* 'full_dump': same as `getFullDump` (no parameters),
+//		* 'activity_log': same as `itemActivityLogForItem` (if `item_id` is given) or `itemActivityLogForAllItems`
+//			(`watched_group_id` & `as_team_id` are optional), the whole log is exported as NDJSON.
+//
+//
+//		The export is run with the same access rights checks as the corresponding service.
+//		If the checks fail when the job is run, the job fails with the error message of the service.
+//
+//
+//		Missing required parameters or parameters not allowed for the given type cause the 'bad request' error.
+//	parameters:
+//		- in: body
+//			name: data
+//			required: true
+//			description: The export to run
+//			schema:
+//				"$ref": "#/definitions/createExportRequest"
+//	responses:
+//		"201":
+//			description: Created. The export job has been created.
+//			schema:
+//				type: object
+//				required: [success, message, data]
+//				properties:
+//					success:
+//						description: "true"
+//						type: boolean
+//						enum: [true]
+//					message:
+//						description: created
+//						type: string
+//						enum: [created]
+//					data:
+//						"$ref": "#/definitions/createExportResponse"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) createExport(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	input := createExportRequest{}
+	formData := formdata.NewFormData(&input)
+	formData.RegisterValidation("export_parameter_allowed",
+		formData.ValidatorSkippingUnsetFields(constructExportParameterAllowedValidator(&input)))
+	formData.RegisterTranslation("export_parameter_allowed", "is not allowed for this type of export")
+	if err := formData.ParseJSONRequestData(r); err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+	if missingFieldErrors := checkRequiredExportParameters(formData, input.Type); len(missingFieldErrors) > 0 {
+		return service.ErrInvalidRequest(missingFieldErrors)
+	}
+
+	parameters := exports.Parameters{
+		GroupID:        input.GroupID,
+		Format:         input.Format,
+		ItemID:         input.ItemID,
+		WatchedGroupID: input.WatchedGroupID,
+		AsTeamID:       input.AsTeamID,
+	}
+	for _, itemID := range input.ParentItemIDs {
+		parameters.ParentItemIDs = append(parameters.ParentItemIDs, strconv.FormatInt(itemID, 10))
+	}
+	parametersJSON, err := json.Marshal(&parameters)
+	service.MustNotBeError(err)
+
+	var exportID int64
+	service.MustNotBeError(store.RetryOnDuplicatePrimaryKeyError("export_jobs", func(store *database.DataStore) error {
+		exportID = store.NewID()
+		return store.ExportJobs().InsertMap(map[string]interface{}{
+			"id":         exportID,
+			"user_id":    user.GroupID,
+			"type":       input.Type,
+			"parameters": string(parametersJSON),
+			"domain":     domain.CurrentDomainFromContext(r.Context()),
+		})
+	}))
+
+	service.MustNotBeError(render.Render(w, r, service.CreationSuccess(&createExportResponse{ID: exportID})))
+	return service.NoError
+}
+
+func checkRequiredExportParameters(formData *formdata.FormData, jobType string) formdata.FieldErrors {
+	fieldErrors := make(formdata.FieldErrors)
+	required, _ := exports.ParametersOfJobType(jobType)
+	for _, parameterName := range required {
+		if !formData.IsSet(parameterName) {
+			fieldErrors[parameterName] = []error{errors.New("is required for this type of export")}
+		}
+	}
+	return fieldErrors
+}
+
+func constructExportParameterAllowedValidator(input *createExportRequest) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		required, optional := exports.ParametersOfJobType(input.Type)
+		return containsString(required, fl.FieldName()) || containsString(optional, fl.FieldName())
+	}
+}
+
+func containsString(list []string, value string) bool {
+	for _, element := range list {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+Feature: Request an export (exportCreate) - robustness
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+
+  Scenario: Missing type
+    Given I am the user with id "21"
+    When I send a POST request to "/exports" with the following body:
+    """
+    {}
+    """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+    """
+    {
+      "success": false,
+      "message": "Bad Request",
+      "error_code": "invalid_input_data",
+      "error_text": "Invalid input data",
+      "errors": {
+        "type": ["missing field"]
+      }
+    }
+    """
+    And the table "export_jobs" should stay unchanged
+
+  Scenario: Wrong type
+    Given I am the user with id "21"
+    When I send a POST request to "/exports" with the following body:
+    """
+    {"type": "everything"}
+    """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+    """
+    {
+      "success": false,
+      "message": "Bad Request",
+      "error_code": "invalid_input_data",
+      "error_text": "Invalid input data",
+      "errors": {
+        "type": ["type must be one of [group_progress user_progress team_progress full_dump activity_log]"]
+      }
+    }
+    """
+    And the table "export_jobs" should stay unchanged
+
+  Scenario: Missing required parameters
+    Given I am the user with id "21"
+    When I send a POST request to "/exports" with the following body:
+    """
+    {"type": "user_progress"}
+    """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+    """
+    {
+      "success": false,
+      "message": "Bad Request",
+      "error_code": "invalid_input_data",
+      "error_text": "Invalid input data",
+      "errors": {
+        "group_id": ["is required for this type of export"],
+        "parent_item_ids": ["is required for this type of export"]
+      }
+    }
+    """
+    And the table "export_jobs" should stay unchanged
+
+  Scenario: Parameters not allowed for the type
+    Given I am the user with id "21"
+    When I send a POST request to "/exports" with the following body:
+    """
+    {"type": "full_dump", "group_id": "13", "format": "csv"}
+    """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+    """
+    {
+      "success": false,
+      "message": "Bad Request",
+      "error_code": "invalid_input_data",
+      "error_text": "Invalid input data",
+      "errors": {
+        "group_id": ["is not allowed for this type of export"],
+        "format": ["is not allowed for this type of export"]
+      }
+    }
+    """
+    And the table "export_jobs" should stay unchanged
+
+  Scenario: Wrong format
+    Given I am the user with id "21"
+    When I send a POST request to "/exports" with the following body:
+    """
+    {"type": "group_progress", "group_id": "13", "parent_item_ids": ["210"], "format": "xlsx"}
+    """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+    """
+    {
+      "success": false,
+      "message": "Bad Request",
+      "error_code": "invalid_input_data",
+      "error_text": "Invalid input data",
+      "errors": {
+        "format": ["format must be one of [csv ndjson]"]
+      }
+    }
+    """
+    And the table "export_jobs" should stay unchanged
@@ -0,0 +1,25 @@
+// Package exports provides API services for asynchronous exports.
+package exports
+
+import (
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// Service is the mount point for services related to `exports`.
+type Service struct {
+	*service.Base
+}
+
+// SetRoutes defines the routes for this package in a route group.
+func (srv *Service) SetRoutes(router chi.Router) {
+	router.Use(render.SetContentType(render.ContentTypeJSON))
+	router.Use(auth.UserMiddleware(srv.Base))
+
+	router.Post("/exports", service.AppHandler(srv.createExport).ServeHTTP)
+	router.Get("/exports/{export_id}", service.AppHandler(srv.getExport).ServeHTTP)
+	router.Get("/exports/{export_id}/artifact", service.AppHandler(srv.getExportArtifact).ServeHTTP)
+}
@@ -0,0 +1,71 @@
+Feature: Get an export (exportView)
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+    And the database has the following table "export_jobs":
+      | id | user_id | type           | parameters                                     | domain    | status    | created_at              | started_at              | finished_at             | error                      | artifact_key | artifact_file_name  | artifact_content_type   | artifact_size | expires_at              |
+      | 1  | 21      | group_progress | {"group_id": "13", "parent_item_ids": ["210"]} | 127.0.0.1 | succeeded | 2025-02-20 10:00:00.000 | 2025-02-20 10:00:01.000 | 2025-02-20 10:00:02.500 | null                       | 1            | groups_progress.csv | text/csv; charset=utf-8 | 1234          | 2025-02-21 10:00:02.500 |
+      | 2  | 21      | full_dump      | {}                                             | 127.0.0.1 | pending   | 2025-02-20 11:00:00.000 | null                    | null                    | null                       | null         | null                | null                    | null          | null                    |
+      | 3  | 21      | activity_log   | {"item_id": "210"}                             | 127.0.0.1 | failed    | 2025-02-20 12:00:00.000 | 2025-02-20 12:00:01.000 | 2025-02-20 12:00:02.000 | Insufficient access rights | null         | null                | null                    | null          | null                    |
+
+  Scenario: Get a succeeded export
+    Given I am the user with id "21"
+    When I send a GET request to "/exports/1"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "id": "1",
+      "type": "group_progress",
+      "parameters": {"group_id": "13", "parent_item_ids": ["210"]},
+      "status": "succeeded",
+      "created_at": "2025-02-20T10:00:00Z",
+      "started_at": "2025-02-20T10:00:01Z",
+      "finished_at": "2025-02-20T10:00:02.5Z",
+      "error": null,
+      "artifact": {
+        "file_name": "groups_progress.csv",
+        "content_type": "text/csv; charset=utf-8",
+        "size": "1234",
+        "download_url": "/exports/1/artifact",
+        "expires_at": "2025-02-21T10:00:02.5Z"
+      }
+    }
+    """
+
+  Scenario: Get a pending export
+    Given I am the user with id "21"
+    When I send a GET request to "/exports/2"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "id": "2",
+      "type": "full_dump",
+      "parameters": {},
+      "status": "pending",
+      "created_at": "2025-02-20T11:00:00Z",
+      "started_at": null,
+      "finished_at": null,
+      "error": null
+    }
+    """
+
+  Scenario: Get a failed export
+    Given I am the user with id "21"
+    When I send a GET request to "/exports/3"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "id": "3",
+      "type": "activity_log",
+      "parameters": {"item_id": "210"},
+      "status": "failed",
+      "created_at": "2025-02-20T12:00:00Z",
+      "started_at": "2025-02-20T12:00:01Z",
+      "finished_at": "2025-02-20T12:00:02Z",
+      "error": "Insufficient access rights"
+    }
+    """
@@ -0,0 +1,136 @@
+package exports
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/go-chi/render"
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:model exportArtifact
+type exportArtifact struct {
+	// required: true
+	FileName string `json:"file_name"`
+	// required: true
+	ContentType string `json:"content_type"`
+	// Size of the artifact in bytes
+	// required: true
+	Size int64 `json:"size,string"`
+	// Path of the `exportArtifactDownload` service for the export
+	// required: true
+	DownloadURL string `json:"download_url"`
+	// The artifact cannot be downloaded after this time
+	// required: true
+	ExpiresAt database.Time `json:"expires_at"`
+}
+
+// swagger:model exportViewResponse
+type exportViewResponse struct {
+	// required: true
+	ID int64 `json:"id,string"`
+	// required: true
+	// enum: group_progress,user_progress,team_progress,full_dump,activity_log
+	Type string `json:"type"`
+	// The parameters of the export as given on creation
+	// required: true
+	Parameters json.RawMessage `json:"parameters" gorm:"-"`
+	// required: true
+	// enum: pending,running,succeeded,failed,expired
+	Status string `json:"status"`
+	// required: true
+	CreatedAt database.Time `json:"created_at"`
+	// `null` if the export has not been started yet
+	// required: true
+	StartedAt *database.Time `json:"started_at"`
+	// `null` if the export has not been finished yet
+	// required: true
+	FinishedAt *database.Time `json:"finished_at"`
+	// Why the export has failed (`null` unless `status` = 'failed')
+	// required: true
+	Error *string `json:"error"`
+	// Only if `status` = 'succeeded'
+	Artifact *exportArtifact `json:"artifact,omitempty" gorm:"-"`
+
+	UserID              int64          `json:"-"`
+	ParametersJSON      string         `json:"-"`
+	ArtifactFileName    *string        `json:"-"`
+	ArtifactContentType *string        `json:"-"`
+	ArtifactSize        *int64         `json:"-"`
+	ExpiresAt           *database.Time `json:"-"`
+}
+
+// swagger:operation GET /exports/{export_id} exports exportView
+//
+//	---
+//	summary: Get an export
+//	description: >
+//
+//		Returns the status of the export job and, when the job has succeeded,
+//		the description of its artifact with the link to download it and the expiration time of the artifact.
+//
+//
+//		The export should have been requested by the current user, otherwise the 'forbidden' error is returned.
+//	parameters:
+//		- name: export_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//	responses:
+//		"200":
+//			description: OK. The export job.
+//			schema:
+//				"$ref": "#/definitions/exportViewResponse"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getExport(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	exportID, err := service.ResolveURLQueryPathInt64Field(r, "export_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	var result exportViewResponse
+	err = store.ExportJobs().Where("id = ?", exportID).
+		Select(`
+			id, user_id, type, CAST(parameters AS CHAR) AS parameters_json, status, created_at, started_at, finished_at, error,
+			artifact_file_name, artifact_content_type, artifact_size, expires_at`).
+		Scan(&result).Error()
+	if gorm.IsRecordNotFoundError(err) {
+		return service.InsufficientAccessRightsError
+	}
+	service.MustNotBeError(err)
+	if result.UserID != user.GroupID {
+		return service.InsufficientAccessRightsError
+	}
+
+	result.Parameters = json.RawMessage(result.ParametersJSON)
+	if result.Status == "succeeded" {
+		result.Artifact = &exportArtifact{
+			FileName:    *result.ArtifactFileName,
+			ContentType: *result.ArtifactContentType,
+			Size:        *result.ArtifactSize,
+			DownloadURL: path.Join("/", srv.ServerConfig.GetString("rootPath"), "exports", strconv.FormatInt(exportID, 10), "artifact"),
+			ExpiresAt:   *result.ExpiresAt,
+		}
+	}
+
+	render.Respond(w, r, &result)
+	return service.NoError
+}
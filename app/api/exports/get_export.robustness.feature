@@ -0,0 +1,27 @@
+Feature: Get an export (exportView) - robustness
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+      | other | 22       |
+    And the database has the following table "export_jobs":
+      | id | user_id | type      | parameters | domain    | status  |
+      | 1  | 21      | full_dump | {}         | 127.0.0.1 | pending |
+
+  Scenario: Invalid export_id
+    Given I am the user with id "21"
+    When I send a GET request to "/exports/abc"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for export_id (should be int64)"
+
+  Scenario: The export does not exist
+    Given I am the user with id "21"
+    When I send a GET request to "/exports/404"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The export has been requested by another user
+    Given I am the user with id "22"
+    When I send a GET request to "/exports/1"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
@@ -0,0 +1,23 @@
+Feature: Download the result of an export (exportArtifactDownload)
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+    And the database has the following table "export_jobs":
+      | id | user_id | type      | parameters | domain    | status    | artifact_key                 | artifact_file_name | artifact_content_type           | artifact_size | expires_at          |
+      | 1  | 21      | full_dump | {}         | 127.0.0.1 | succeeded | feature-test-export-artifact | user_data.json     | application/json; charset=utf-8 | 18            | 9999-12-31 23:59:59 |
+    And the exports storage has the artifact "feature-test-export-artifact" with the following content:
+    """
+    {"current_user":1}
+    """
+
+  Scenario: Download the artifact
+    Given I am the user with id "21"
+    When I send a GET request to "/exports/1/artifact"
+    Then the response code should be 200
+    And the response header "Content-Type" should be "application/json; charset=utf-8"
+    And the response header "Content-Disposition" should be "attachment; filename=user_data.json"
+    And the response body should be:
+    """
+    {"current_user":1}
+    """
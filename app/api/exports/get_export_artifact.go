@@ -0,0 +1,100 @@
+package exports
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/exports"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:operation GET /exports/{export_id}/artifact exports exportArtifactDownload
+//
+//	---
+//	summary: Download the result of an export
+//	description: >
+//
+//		Returns the artifact of the succeeded export job as an attachment.
+//
+//
+//		The export should have been requested by the current user, otherwise the 'forbidden' error is returned.
+//		If the export has not succeeded or its artifact has expired, the 'not found' error is returned.
+//	parameters:
+//		- name: export_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//	produces:
+//		- application/octet-stream
+//	responses:
+//		"200":
+//			description: OK. The artifact (with the content type of the artifact).
+//			schema:
+//				type: string
+//				format: binary
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"404":
+//			"$ref": "#/responses/notFoundResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getExportArtifact(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	exportID, err := service.ResolveURLQueryPathInt64Field(r, "export_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	var job struct {
+		UserID              int64
+		Status              string
+		ArtifactKey         *string
+		ArtifactFileName    *string
+		ArtifactContentType *string
+		ExpiresAt           *bool
+	}
+	err = store.ExportJobs().Where("id = ?", exportID).
+		Select("user_id, status, artifact_key, artifact_file_name, artifact_content_type, expires_at <= NOW(3) AS expires_at").
+		Scan(&job).Error()
+	if gorm.IsRecordNotFoundError(err) {
+		return service.InsufficientAccessRightsError
+	}
+	service.MustNotBeError(err)
+	if job.UserID != user.GroupID {
+		return service.InsufficientAccessRightsError
+	}
+	if job.Status != "succeeded" || job.ArtifactKey == nil || job.ExpiresAt == nil || *job.ExpiresAt {
+		return service.ErrNotFound(errors.New("no artifact available for the export"))
+	}
+
+	storage, err := exports.NewStorage(srv.ServerConfig)
+	service.MustNotBeError(err)
+	artifact, err := storage.Open(r.Context(), *job.ArtifactKey)
+	if errors.Is(err, os.ErrNotExist) {
+		return service.ErrNotFound(errors.New("no artifact available for the export"))
+	}
+	service.MustNotBeError(err)
+	defer func() { _ = artifact.Close() }()
+
+	w.Header().Set("Content-Type", *job.ArtifactContentType)
+	w.Header().Set("Content-Disposition",
+		mime.FormatMediaType("attachment", map[string]string{"filename": *job.ArtifactFileName}))
+	w.WriteHeader(http.StatusOK)
+	_, err = io.Copy(w, artifact)
+	service.MustNotBeError(err)
+	return service.NoError
+}
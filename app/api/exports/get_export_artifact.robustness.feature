@@ -0,0 +1,46 @@
+Feature: Download the result of an export (exportArtifactDownload) - robustness
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+      | other | 22       |
+    And the database has the following table "export_jobs":
+      | id | user_id | type      | parameters | domain    | status    | artifact_key                  | artifact_file_name | artifact_content_type | artifact_size | expires_at          |
+      | 1  | 21      | full_dump | {}         | 127.0.0.1 | running   | null                          | null               | null                  | null          | null                |
+      | 2  | 21      | full_dump | {}         | 127.0.0.1 | expired   | null                          | user_data.json     | application/json      | 2             | 2025-02-20 10:00:00 |
+      | 3  | 21      | full_dump | {}         | 127.0.0.1 | succeeded | feature-test-expired-artifact | user_data.json     | application/json      | 2             | 2025-02-20 10:00:00 |
+      | 4  | 21      | full_dump | {}         | 127.0.0.1 | succeeded | feature-test-missing-artifact | user_data.json     | application/json      | 2             | 9999-12-31 23:59:59 |
+    And the exports storage has the artifact "feature-test-expired-artifact" with the following content:
+    """
+    {}
+    """
+
+  Scenario: Invalid export_id
+    Given I am the user with id "21"
+    When I send a GET request to "/exports/abc/artifact"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for export_id (should be int64)"
+
+  Scenario: The export does not exist
+    Given I am the user with id "21"
+    When I send a GET request to "/exports/404/artifact"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The export has been requested by another user
+    Given I am the user with id "22"
+    When I send a GET request to "/exports/1/artifact"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario Outline: The artifact is not available
+    Given I am the user with id "21"
+    When I send a GET request to "/exports/<export_id>/artifact"
+    Then the response code should be 404
+    And the response error message should contain "No artifact available for the export"
+  Examples:
+    | export_id |
+    | 1         |
+    | 2         |
+    | 3         |
+    | 4         |
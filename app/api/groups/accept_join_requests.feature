@@ -61,6 +61,10 @@ Feature: Accept group requests
       | 14       | 31        | join_request | 1                           | 0                        | 0              | 2019-06-02 00:00:00.000 |
       | 14       | 141       | join_request | 1                           | 1                        | 1              | 2019-06-03 00:00:00.000 |
       | 14       | 161       | join_request | 0                           | 0                        | 0              | 2019-06-04 00:00:00.000 |
+    And the database has the following table "users":
+      | group_id | login |
+      | 31       | john  |
+      | 141      | jane  |
     When I send a POST request to "/groups/14/join-requests/accept?group_ids=31,141,21,11,13,122,151"
     Then the response code should be 200
     And the response body should be, in JSON:
@@ -98,6 +102,10 @@ Feature: Accept group requests
       | group_id | member_id | action                | initiator_id | ABS(TIMESTAMPDIFF(SECOND, at, NOW())) < 3 |
       | 14       | 31        | join_request_accepted | 21           | 1                                         |
       | 14       | 141       | join_request_accepted | 21           | 1                                         |
+    And the table "notifications" should be:
+      | user_id | type                  | CAST(payload AS CHAR)                | ABS(TIMESTAMPDIFF(SECOND, created_at, NOW())) < 3 |
+      | 31      | join_request_accepted | {"group_id": "14", "group_name": ""} | 1                                                 |
+      | 141     | join_request_accepted | {"group_id": "14", "group_name": ""} | 1                                                 |
     And the table "groups_ancestors" should be:
       | ancestor_group_id | child_group_id | is_self |
       | 11                | 11             | 1       |
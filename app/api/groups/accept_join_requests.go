@@ -18,7 +18,8 @@ import (
 //		for each of `group_ids`. The `groups_groups.*_approved_at` fields are set to `group_pending_requests.at`
 //		for each approval given in the pending join requests.
 //		Then the appropriate pending requests get removed from `group_pending_requests`.
-//		The service also refreshes the access rights.
+//		The service also refreshes the access rights and creates in-app notifications for the users
+//		(see `GET /current-user/notifications`).
 //
 //
 //		The authenticated user should be a manager of the `{parent_group_id}` with `can_manage` >= 'memberships',
@@ -0,0 +1,160 @@
+Feature: Change parent-child relations between groups in bulk
+  Background:
+    Given the database has the following table "groups":
+      | id | name    | type  |
+      | 11 | Group A | Class |
+      | 13 | Group B | Class |
+      | 14 | Group C | Class |
+      | 15 | Group D | Class |
+      | 16 | Group E | Class |
+      | 21 | Self    | User  |
+    And the database has the following user:
+      | group_id | login | first_name  | last_name |
+      | 21       | owner | Jean-Michel | Blanquer  |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_manage            |
+      | 11       | 21         | memberships_and_group |
+      | 13       | 21         | memberships_and_group |
+      | 14       | 21         | memberships_and_group |
+      | 15       | 21         | memberships           |
+      | 16       | 21         | memberships_and_group |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 11             |
+      | 13              | 16             |
+      | 14              | 15             |
+    And the groups ancestors are computed
+    And the database has the following table "group_pending_requests":
+      | group_id | member_id | type       |
+      | 13       | 14        | invitation |
+    And the database has the following table "items":
+      | id | default_language_tag |
+      | 20 | fr                   |
+    And the database has the following table "permissions_granted":
+      | group_id | item_id | source_group_id | origin           | can_view |
+      | 15       | 20      | 14              | group_membership | content  |
+      | 15       | 20      | 15              | group_membership | info     |
+
+  Scenario: User adds, moves, and removes relations
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/relations" with the following body:
+    """
+    {
+      "operations": [
+        {"action": "add", "parent_group_id": "13", "child_group_id": "14"},
+        {"action": "move", "parent_group_id": "14", "child_group_id": "11", "from_parent_group_id": "13"},
+        {"action": "remove", "parent_group_id": "14", "child_group_id": "15"},
+        {"action": "add", "parent_group_id": "13", "child_group_id": "16"}
+      ]
+    }
+    """
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "success": true,
+      "message": "updated",
+      "data": ["success", "success", "success", "unchanged"]
+    }
+    """
+    And the table "groups_groups" should be:
+      | parent_group_id | child_group_id |
+      | 13              | 14             |
+      | 13              | 16             |
+      | 14              | 11             |
+    And the table "groups_ancestors" should be:
+      | ancestor_group_id | child_group_id | is_self |
+      | 11                | 11             | 1       |
+      | 13                | 11             | 0       |
+      | 13                | 13             | 1       |
+      | 13                | 14             | 0       |
+      | 13                | 16             | 0       |
+      | 14                | 11             | 0       |
+      | 14                | 14             | 1       |
+      | 15                | 15             | 1       |
+      | 16                | 16             | 1       |
+      | 21                | 21             | 1       |
+    And the table "group_pending_requests" should be empty
+    And the table "permissions_granted" should be:
+      | group_id | item_id | source_group_id | origin           | can_view |
+      | 15       | 20      | 15              | group_membership | info     |
+    And the table "groups" should stay unchanged
+
+  Scenario: Moving a group to a parent which already contains it only removes the old relation
+    Given I am the user with id "21"
+    And the database table "groups_groups" also has the following row:
+      | parent_group_id | child_group_id |
+      | 14              | 11             |
+    And the groups ancestors are computed
+    When I send a POST request to "/groups/relations" with the following body:
+    """
+    {
+      "operations": [
+        {"action": "move", "parent_group_id": "14", "child_group_id": "11", "from_parent_group_id": "13"}
+      ]
+    }
+    """
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "success": true,
+      "message": "updated",
+      "data": ["success"]
+    }
+    """
+    And the table "groups_groups" should be:
+      | parent_group_id | child_group_id |
+      | 13              | 16             |
+      | 14              | 11             |
+      | 14              | 15             |
+    And the table "groups_ancestors" should be:
+      | ancestor_group_id | child_group_id | is_self |
+      | 11                | 11             | 1       |
+      | 13                | 13             | 1       |
+      | 13                | 16             | 0       |
+      | 14                | 11             | 0       |
+      | 14                | 14             | 1       |
+      | 14                | 15             | 0       |
+      | 15                | 15             | 1       |
+      | 16                | 16             | 1       |
+      | 21                | 21             | 1       |
+    And the table "permissions_granted" should stay unchanged
+
+  Scenario: A group becomes the parent of its former parent
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/relations" with the following body:
+    """
+    {
+      "operations": [
+        {"action": "remove", "parent_group_id": "13", "child_group_id": "16"},
+        {"action": "add", "parent_group_id": "16", "child_group_id": "13"}
+      ]
+    }
+    """
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "success": true,
+      "message": "updated",
+      "data": ["success", "success"]
+    }
+    """
+    And the table "groups_groups" should be:
+      | parent_group_id | child_group_id |
+      | 13              | 11             |
+      | 14              | 15             |
+      | 16              | 13             |
+    And the table "groups_ancestors" should be:
+      | ancestor_group_id | child_group_id | is_self |
+      | 11                | 11             | 1       |
+      | 13                | 11             | 0       |
+      | 13                | 13             | 1       |
+      | 14                | 14             | 1       |
+      | 14                | 15             | 0       |
+      | 15                | 15             | 1       |
+      | 16                | 11             | 0       |
+      | 16                | 13             | 0       |
+      | 16                | 16             | 1       |
+      | 21                | 21             | 1       |
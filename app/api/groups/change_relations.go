@@ -0,0 +1,268 @@
+package groups
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+const (
+	addRelationAction    = "add"
+	removeRelationAction = "remove"
+	moveRelationAction   = "move"
+)
+
+const (
+	relationChangeSucceeded = "success"
+	relationUnchanged       = "unchanged"
+)
+
+type changeRelationsRequestOperation struct {
+	// required: true
+	// enum: add,remove,move
+	Action string `json:"action" validate:"set,oneof=add remove move"`
+	// required: true
+	ParentGroupID int64 `json:"parent_group_id,string" validate:"set"`
+	// required: true
+	ChildGroupID int64 `json:"child_group_id,string" validate:"set"`
+	// The current parent of `child_group_id` (required for 'move', not allowed for other actions)
+	FromParentGroupID *int64 `json:"from_parent_group_id,string"`
+}
+
+// swagger:model changeRelationsRequest
+type changeRelationsRequest struct {
+	// required: true
+	// minItems: 1
+	// maxItems: 1000
+	Operations []changeRelationsRequestOperation `json:"operations" validate:"set,min=1,max=1000,dive"`
+}
+
+// swagger:operation POST /groups/relations group-memberships groupRelationsChange
+//
+//	---
+//	summary: Change group relations in bulk
+//	description: >
+//
+//		Applies a list of operations on relations between groups:
+//
+//		* 'add' adds `child_group_id` as a child to `parent_group_id` (like `groupAddChild`),
+//
+//		* 'remove' removes `child_group_id` from `parent_group_id` (like `groupRemoveChild` without deleting orphans),
+//
+//		* 'move' removes `child_group_id` from `from_parent_group_id` and adds it as a child to `parent_group_id`.
+//
+//
+//		All the operations are checked before anything is changed. Then they are applied in one transaction,
+//		the groups ancestors are recomputed once, and the results (and the permissions if needed) are propagated once.
+//		If one of the operations cannot be applied, nothing is changed.
+//
+//
+//		Restrictions (otherwise the 'forbidden' error is returned with the reasons listed by operations):
+//			* for every relation being added or removed, the authenticated user should be a manager
+//				of both the parent and the child group with the same permissions as for `groupAddChild`
+//				(for added relations) and `groupRemoveChild` (for removed relations),
+//			* every removed relation should exist,
+//			* the operations should not create cycles in the groups relations graph.
+//
+//
+//		The 'bad request' error is returned if a group is given as its own parent, if `from_parent_group_id`
+//		is missing for 'move' (or is given for other actions or is equal to `parent_group_id`),
+//		or if the same relation is changed by several operations.
+//	parameters:
+//		- in: body
+//			name: data
+//			required: true
+//			description: The operations to apply
+//			schema:
+//				"$ref": "#/definitions/changeRelationsRequest"
+//	responses:
+//		"200":
+//			description: >
+//				OK. Success response with the per-operation results in the order of the operations
+//				('unchanged' if the relation to add already exists, 'success' otherwise)
+//			schema:
+//				type: object
+//				required: [success, message, data]
+//				properties:
+//					success:
+//						description: "true"
+//						type: boolean
+//						enum: [true]
+//					message:
+//						description: updated
+//						type: string
+//						enum: [updated]
+//					data:
+//						type: array
+//						items:
+//							type: string
+//							enum: [success,unchanged]
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) changeRelations(w http.ResponseWriter, r *http.Request) service.APIError {
+	input := changeRelationsRequest{}
+	formData := formdata.NewFormData(&input)
+	if err := formData.ParseJSONRequestData(r); err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	if fieldErrors := checkChangeRelationsOperations(input.Operations); len(fieldErrors) > 0 {
+		return service.ErrInvalidRequest(fieldErrors)
+	}
+
+	user := srv.GetUser(r)
+	apiError := service.NoError
+	var results []string
+
+	err := srv.GetStore(r).InTransaction(func(store *database.DataStore) error {
+		var relationsToDelete, relationsToCreate []database.ParentChild
+		results, relationsToDelete, relationsToCreate, apiError = planRelationChanges(store, user, input.Operations)
+		if apiError != service.NoError {
+			return apiError.Error // rollback
+		}
+
+		return store.GroupGroups().ChangeRelations(relationsToDelete, relationsToCreate)
+	})
+
+	if apiError != service.NoError {
+		return apiError
+	}
+	service.MustNotBeError(err)
+
+	service.MustNotBeError(render.Render(w, r, service.UpdateSuccess(results)))
+	return service.NoError
+}
+
+func operationFieldName(index int) string {
+	return fmt.Sprintf("operations[%d]", index)
+}
+
+func checkChangeRelationsOperations(operations []changeRelationsRequestOperation) formdata.FieldErrors {
+	fieldErrors := make(formdata.FieldErrors)
+	changedRelations := make(map[database.ParentChild]bool, len(operations))
+	for index, operation := range operations {
+		fieldName := operationFieldName(index)
+		relations := []database.ParentChild{{ParentID: operation.ParentGroupID, ChildID: operation.ChildGroupID}}
+
+		switch {
+		case operation.Action == moveRelationAction && operation.FromParentGroupID == nil:
+			fieldErrors[fieldName+".from_parent_group_id"] = []error{errors.New("should be set for the 'move' action")}
+			continue
+		case operation.Action != moveRelationAction && operation.FromParentGroupID != nil:
+			fieldErrors[fieldName+".from_parent_group_id"] = []error{errors.New("is only allowed for the 'move' action")}
+			continue
+		case operation.ParentGroupID == operation.ChildGroupID:
+			fieldErrors[fieldName] = []error{errors.New("a group cannot become its own parent")}
+			continue
+		case operation.Action == moveRelationAction:
+			if *operation.FromParentGroupID == operation.ParentGroupID {
+				fieldErrors[fieldName+".from_parent_group_id"] = []error{errors.New("should differ from parent_group_id")}
+				continue
+			}
+			relations = append(relations, database.ParentChild{ParentID: *operation.FromParentGroupID, ChildID: operation.ChildGroupID})
+		}
+
+		for _, relation := range relations {
+			if changedRelations[relation] {
+				fieldErrors[fieldName] = []error{errors.New("the relation is changed by another operation")}
+			}
+			changedRelations[relation] = true
+		}
+	}
+	return fieldErrors
+}
+
+// planRelationChanges checks the operations against the permissions of the user, the existing relations,
+// and the groups relations graph, and returns the per-operation results with the relations to delete & to create.
+func planRelationChanges(store *database.DataStore, user *database.User, operations []changeRelationsRequestOperation) (
+	results []string, relationsToDelete, relationsToCreate []database.ParentChild, apiError service.APIError,
+) {
+	fieldErrors := make(formdata.FieldErrors)
+	results = make([]string, len(operations))
+	createdRelationOperations := make([]int, 0, len(operations))
+
+	for index, operation := range operations {
+		results[index] = relationChangeSucceeded
+
+		if operation.Action == removeRelationAction || operation.Action == moveRelationAction {
+			parentGroupID := operation.ParentGroupID
+			if operation.Action == moveRelationAction {
+				parentGroupID = *operation.FromParentGroupID
+			}
+			if reason := checkRelationCanBeChanged(store, user, parentGroupID, operation.ChildGroupID, deleteRelation); reason != "" {
+				fieldErrors[operationFieldName(index)] = []error{errors.New(reason)}
+				continue
+			}
+			relationsToDelete = append(relationsToDelete, database.ParentChild{ParentID: parentGroupID, ChildID: operation.ChildGroupID})
+		}
+
+		if operation.Action == addRelationAction || operation.Action == moveRelationAction {
+			reason := checkRelationCanBeChanged(store, user, operation.ParentGroupID, operation.ChildGroupID, createRelation)
+			switch reason {
+			case "":
+				relationsToCreate = append(relationsToCreate,
+					database.ParentChild{ParentID: operation.ParentGroupID, ChildID: operation.ChildGroupID})
+				createdRelationOperations = append(createdRelationOperations, index)
+			case relationExists:
+				if operation.Action == addRelationAction {
+					results[index] = relationUnchanged
+				}
+			default:
+				fieldErrors[operationFieldName(index)] = []error{errors.New(reason)}
+			}
+		}
+	}
+
+	if len(fieldErrors) == 0 {
+		cycleIndexes, err := store.GroupGroups().FindRelationsCreatingCycles(relationsToDelete, relationsToCreate)
+		service.MustNotBeError(err)
+		for _, cycleIndex := range cycleIndexes {
+			fieldErrors[operationFieldName(createdRelationOperations[cycleIndex])] = []error{database.ErrRelationCycle}
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, nil, nil, service.ErrForbidden(fieldErrors)
+	}
+	return results, relationsToDelete, relationsToCreate, service.NoError
+}
+
+const (
+	relationExists       = "the relation already exists"
+	relationDoesNotExist = "the relation does not exist"
+)
+
+// checkRelationCanBeChanged returns the reason why the user cannot create/delete the given relation
+// (relationExists if the relation to create already exists) or an empty string.
+func checkRelationCanBeChanged(store *database.DataStore, user *database.User,
+	parentGroupID, childGroupID int64, createOrDelete createOrDeleteRelation,
+) string {
+	if checkThatUserHasRightsForDirectRelation(store, user, parentGroupID, childGroupID, createOrDelete) != service.NoError {
+		return "insufficient access rights"
+	}
+
+	found, err := store.ActiveGroupGroups().WithExclusiveWriteLock().
+		Where("parent_group_id = ? AND child_group_id = ?", parentGroupID, childGroupID).HasRows()
+	service.MustNotBeError(err)
+	switch {
+	case found && createOrDelete == createRelation:
+		return relationExists
+	case !found && createOrDelete == deleteRelation:
+		return relationDoesNotExist
+	}
+	return ""
+}
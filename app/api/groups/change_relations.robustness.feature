@@ -0,0 +1,205 @@
+Feature: Change parent-child relations between groups in bulk - robustness
+  Background:
+    Given the database has the following table "groups":
+      | id | name    | type  |
+      | 11 | Group A | Class |
+      | 13 | Group B | Class |
+      | 14 | Group C | Class |
+      | 15 | Group D | Class |
+      | 21 | Self    | User  |
+    And the database has the following user:
+      | group_id | login | first_name  | last_name |
+      | 21       | owner | Jean-Michel | Blanquer  |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_manage            |
+      | 11       | 21         | memberships_and_group |
+      | 13       | 21         | memberships_and_group |
+      | 14       | 21         | memberships           |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 11             |
+      | 11              | 14             |
+    And the groups ancestors are computed
+
+  Scenario: Invalid operations
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/relations" with the following body:
+    """
+    {
+      "operations": [
+        {"action": "copy", "parent_group_id": "13"},
+        {"action": "move", "parent_group_id": "13", "child_group_id": "11"},
+        {"action": "add", "parent_group_id": "13", "child_group_id": "11", "from_parent_group_id": "14"},
+        {"action": "add", "parent_group_id": "13", "child_group_id": "13"},
+        {"action": "move", "parent_group_id": "13", "child_group_id": "14", "from_parent_group_id": "13"}
+      ]
+    }
+    """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+    """
+    {
+      "success": false,
+      "message": "Bad Request",
+      "error_code": "invalid_input_data",
+      "error_text": "Invalid input data",
+      "errors": {
+        "operations[0].action": ["action must be one of [add remove move]"],
+        "operations[0].child_group_id": ["missing field"]
+      }
+    }
+    """
+    And the table "groups_groups" should stay unchanged
+    When I send a POST request to "/groups/relations" with the following body:
+    """
+    {
+      "operations": [
+        {"action": "move", "parent_group_id": "13", "child_group_id": "11"},
+        {"action": "add", "parent_group_id": "13", "child_group_id": "11", "from_parent_group_id": "14"},
+        {"action": "add", "parent_group_id": "13", "child_group_id": "13"},
+        {"action": "move", "parent_group_id": "13", "child_group_id": "14", "from_parent_group_id": "13"},
+        {"action": "remove", "parent_group_id": "11", "child_group_id": "14"},
+        {"action": "move", "parent_group_id": "15", "child_group_id": "14", "from_parent_group_id": "11"}
+      ]
+    }
+    """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+    """
+    {
+      "success": false,
+      "message": "Bad Request",
+      "error_code": "invalid_input_data",
+      "error_text": "Invalid input data",
+      "errors": {
+        "operations[0].from_parent_group_id": ["should be set for the 'move' action"],
+        "operations[1].from_parent_group_id": ["is only allowed for the 'move' action"],
+        "operations[2]": ["a group cannot become its own parent"],
+        "operations[3].from_parent_group_id": ["should differ from parent_group_id"],
+        "operations[5]": ["the relation is changed by another operation"]
+      }
+    }
+    """
+    And the table "groups_groups" should stay unchanged
+
+  Scenario: No operations
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/relations" with the following body:
+    """
+    {"operations": []}
+    """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+    """
+    {
+      "success": false,
+      "message": "Bad Request",
+      "error_code": "invalid_input_data",
+      "error_text": "Invalid input data",
+      "errors": {
+        "operations": ["operations must contain at least 1 item"]
+      }
+    }
+    """
+    And the table "groups_groups" should stay unchanged
+
+  Scenario: Nothing is changed if one of the operations is not allowed
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/relations" with the following body:
+    """
+    {
+      "operations": [
+        {"action": "remove", "parent_group_id": "13", "child_group_id": "11"},
+        {"action": "add", "parent_group_id": "13", "child_group_id": "14"},
+        {"action": "remove", "parent_group_id": "13", "child_group_id": "15"},
+        {"action": "move", "parent_group_id": "11", "child_group_id": "15", "from_parent_group_id": "14"}
+      ]
+    }
+    """
+    Then the response code should be 403
+    And the response body should be, in JSON:
+    """
+    {
+      "success": false,
+      "message": "Forbidden",
+      "error_code": "invalid_input_data",
+      "error_text": "Invalid input data",
+      "errors": {
+        "operations[1]": ["insufficient access rights"],
+        "operations[2]": ["insufficient access rights"],
+        "operations[3]": ["insufficient access rights"]
+      }
+    }
+    """
+    And the table "groups_groups" should stay unchanged
+    And the table "groups_ancestors" should stay unchanged
+
+  Scenario: The relation to remove does not exist
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/relations" with the following body:
+    """
+    {
+      "operations": [
+        {"action": "remove", "parent_group_id": "13", "child_group_id": "11"},
+        {"action": "move", "parent_group_id": "11", "child_group_id": "13", "from_parent_group_id": "14"}
+      ]
+    }
+    """
+    Then the response code should be 403
+    And the response body should be, in JSON:
+    """
+    {
+      "success": false,
+      "message": "Forbidden",
+      "error_code": "invalid_input_data",
+      "error_text": "Invalid input data",
+      "errors": {
+        "operations[1]": ["the relation does not exist"]
+      }
+    }
+    """
+    And the table "groups_groups" should stay unchanged
+    And the table "groups_ancestors" should stay unchanged
+
+  Scenario: Operations creating cycles
+    Given I am the user with id "21"
+    And the database table "group_managers" also has the following row:
+      | group_id | manager_id | can_manage            |
+      | 15       | 21         | memberships_and_group |
+    When I send a POST request to "/groups/relations" with the following body:
+    """
+    {
+      "operations": [
+        {"action": "add", "parent_group_id": "11", "child_group_id": "15"},
+        {"action": "add", "parent_group_id": "15", "child_group_id": "13"},
+        {"action": "add", "parent_group_id": "13", "child_group_id": "15"}
+      ]
+    }
+    """
+    Then the response code should be 403
+    And the response body should be, in JSON:
+    """
+    {
+      "success": false,
+      "message": "Forbidden",
+      "error_code": "invalid_input_data",
+      "error_text": "Invalid input data",
+      "errors": {
+        "operations[0]": ["a group cannot become an ancestor of itself"],
+        "operations[1]": ["a group cannot become an ancestor of itself"],
+        "operations[2]": ["a group cannot become an ancestor of itself"]
+      }
+    }
+    """
+    And the table "groups_groups" should stay unchanged
+    And the table "groups_ancestors" should stay unchanged
+
+  Scenario: User does not exist
+    Given I am the user with id "404"
+    When I send a POST request to "/groups/relations" with the following body:
+    """
+    {"operations": [{"action": "add", "parent_group_id": "13", "child_group_id": "14"}]}
+    """
+    Then the response code should be 401
+    And the response error message should contain "Invalid access token"
+    And the table "groups_groups" should stay unchanged
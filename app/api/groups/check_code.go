@@ -90,6 +90,8 @@ type groupCodeCheckResponse struct {
 //			"$ref": "#/responses/unauthorizedResponse"
 //		"408":
 //			"$ref": "#/responses/requestTimeoutResponse"
+//		"429":
+//			"$ref": "#/responses/tooManyRequestsResponse"
 //		"500":
 //			"$ref": "#/responses/internalErrorResponse"
 func (srv *Service) checkCode(w http.ResponseWriter, r *http.Request) service.APIError {
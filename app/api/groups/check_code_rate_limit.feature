@@ -0,0 +1,66 @@
+Feature: Check if the group code is valid - rate limit
+  Background:
+    Given the database has the following table "groups":
+      | id | type | code       | name     |
+      | 11 | Team | 3456789abc | Our Team |
+    And the database has the following users:
+      | group_id | login | temp_user |
+      | 21       | john  | false     |
+      | 22       | jane  | false     |
+    And the groups ancestors are computed
+    And the server time now is "2020-01-01T00:00:00Z"
+
+  Scenario Outline: Guessing codes is limited per user
+    Given the application config is:
+      """
+      server:
+        rateLimitsStore: <store>
+        rateLimits:
+          code-check: {limit: 2, period: 1m, key: user}
+      """
+    And I am the user with id "21"
+    When I send a GET request to "/groups/is-code-valid?code=0000000000"
+    Then the response code should be 200
+    And the response header "RateLimit-Limit" should be "2"
+    And the response header "RateLimit-Remaining" should be "1"
+    And the response header "RateLimit-Reset" should be "30"
+    And the response header "RateLimit-Policy" should be "2;w=60"
+    And the response header "Retry-After" should not be set
+    When I send a GET request to "/groups/is-code-valid?code=1111111111"
+    Then the response code should be 200
+    And the response header "RateLimit-Remaining" should be "0"
+    And the response header "RateLimit-Reset" should be "60"
+    When I send a GET request to "/groups/is-code-valid?code=3456789abc"
+    Then the response code should be 429
+    And the response error message should contain "Too many requests, retry later"
+    And the response header "RateLimit-Remaining" should be "0"
+    And the response header "RateLimit-Reset" should be "60"
+    And the response header "Retry-After" should be "30"
+    Given I am the user with id "22"
+    When I send a GET request to "/groups/is-code-valid?code=3456789abc"
+    Then the response code should be 200
+    And the response header "RateLimit-Remaining" should be "1"
+  Examples:
+    | store    |
+    | memory   |
+    | database |
+
+  Scenario: The buckets are stored in the database
+    Given the application config is:
+      """
+      server:
+        rateLimitsStore: database
+        rateLimits:
+          code-check: {limit: 2, period: 1m, key: user}
+      """
+    And the database has the following table "rate_limit_buckets":
+      | bucket_key         | tokens | updated_at                 | full_at                    |
+      | code-check:user:21 | 1.5    | 2020-01-01 00:00:00.000000 | 2020-01-01 00:00:15.000000 |
+    And I am the user with id "21"
+    When I send a GET request to "/groups/is-code-valid?code=3456789abc"
+    Then the response code should be 200
+    And the response header "RateLimit-Remaining" should be "0"
+    And the response header "RateLimit-Reset" should be "45"
+    And the table "rate_limit_buckets" should be:
+      | bucket_key         | tokens | updated_at = '2020-01-01 00:00:00' | full_at = '2020-01-01 00:00:45' |
+      | code-check:user:21 | 0.5    | 1                                  | 1                               |
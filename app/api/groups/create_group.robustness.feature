@@ -20,6 +20,7 @@ Feature: Create a group (groupCreate) - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors": {"name": ["missing field"]}
     }
@@ -40,6 +41,7 @@ Feature: Create a group (groupCreate) - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors": {"name": ["name must be at least 1 character in length"]}
     }
@@ -60,6 +62,7 @@ Feature: Create a group (groupCreate) - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors": {"type": ["missing field"]}
     }
@@ -80,6 +83,7 @@ Feature: Create a group (groupCreate) - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors": {"type": ["type must be one of [Class Team Club Friends Other Session]"]}
     }
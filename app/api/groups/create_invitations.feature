@@ -1,17 +1,17 @@
 Feature: Invite users
   Background:
     Given the database has the following table "groups":
-      | id  | type  | require_personal_info_access_approval | enforce_max_participants | max_participants |
-      | 13  | Team  | none                                  | true                     | 2                |
-      | 444 | Team  | none                                  | false                    | null             |
-      | 555 | Class | view                                  | false                    | null             |
+      | id  | type  | name       | require_personal_info_access_approval | enforce_max_participants | max_participants |
+      | 13  | Team  | Dream team | none                                  | true                     | 2                |
+      | 444 | Team  | Other team | none                                  | false                    | null             |
+      | 555 | Class | Class      | view                                  | false                    | null             |
     And the database has the following users:
-      | group_id | login | first_name  | last_name | temp_user |
-      | 21       | owner | Jean-Michel | Blanquer  | false     |
-      | 101      | john  | John        | Doe       | false     |
-      | 102      | jane  | Jane        | Doe       | false     |
-      | 103      | Jane  | Jane        | Smith     | false     |
-      | 104      | tmp   | Temp        | User      | true      |
+      | group_id | login | first_name  | last_name | temp_user | email             | email_notifications_opt_out |
+      | 21       | owner | Jean-Michel | Blanquer  | false     | owner@example.org | invitation_created          |
+      | 101      | john  | John        | Doe       | false     | john@example.org  |                             |
+      | 102      | jane  | Jane        | Doe       | false     | jane@example.org  |                             |
+      | 103      | Jane  | Jane        | Smith     | false     | null              |                             |
+      | 104      | tmp   | Temp        | User      | true      | tmp@example.org   |                             |
     And the groups ancestors are computed
     And the database has the following table "items":
       | id   | default_language_tag |
@@ -69,6 +69,12 @@ Feature: Invite users
       | group_id | member_id | action             | initiator_id | ABS(TIMESTAMPDIFF(SECOND, at, NOW())) < 3 |
       | 13       | 21        | invitation_created | 21           | 1                                         |
       | 13       | 101       | invitation_created | 21           | 1                                         |
+    And the table "email_notifications" should be:
+      | user_id | type               | CAST(payload AS CHAR)                          | status  |
+      | 101     | invitation_created | {"group_id": "13", "group_name": "Dream team"} | pending |
+    And the table "notifications" should be:
+      | user_id | type               | CAST(payload AS CHAR)                          | read_at |
+      | 101     | invitation_created | {"group_id": "13", "group_name": "Dream team"} | null    |
     And the table "groups_ancestors" should stay unchanged
     And the table "attempts" should stay unchanged
     And the table "results" should stay unchanged
@@ -186,6 +192,14 @@ Feature: Invite users
       | group_id | member_id | action                | initiator_id | ABS(TIMESTAMPDIFF(SECOND, at, NOW())) < 3 |
       | 555      | 101       | join_request_accepted | 21           | 1                                         |
       | 555      | 102       | invitation_created    | 21           | 1                                         |
+    And the table "email_notifications" should be:
+      | user_id | type                  | CAST(payload AS CHAR)                      |
+      | 101     | join_request_accepted | {"group_id": "555", "group_name": "Class"} |
+      | 102     | invitation_created    | {"group_id": "555", "group_name": "Class"} |
+    And the table "notifications" should be:
+      | user_id | type                  | CAST(payload AS CHAR)                      |
+      | 101     | join_request_accepted | {"group_id": "555", "group_name": "Class"} |
+      | 102     | invitation_created    | {"group_id": "555", "group_name": "Class"} |
     And the table "groups_ancestors" should be:
       | ancestor_group_id | child_group_id | is_self |
       | 13                | 13             | 1       |
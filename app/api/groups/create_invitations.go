@@ -45,7 +45,8 @@ const maxAllowedLoginsToInvite = 100
 //			* `initiator_id` = `users.group_id` of the authorized user.
 //
 //
-//		It also refreshes the access rights when needed.
+//		It also refreshes the access rights when needed and creates in-app notifications for the invited users
+//		(see `GET /current-user/notifications`).
 //
 //
 //		* Logins not corresponding to valid users or corresponding to temporary users are ignored (result = "not_found").
@@ -134,3 +134,40 @@ Feature: Create a user batch
       | 5577006791947779410 | 0  | 5577006791947779410 | true                                              | null              | null         |
       | 8674665223082153551 | 0  | 8674665223082153551 | true                                              | null              | null         |
     And the table "group_membership_changes" should be empty
+
+  Scenario: Create a new user batch with a credential sheet
+    Given the login module "create" endpoint with params "amount=1&language=en&login_fixed=1&password_length=6&postfix_length=3&prefix=test_custom_" returns 200 with encoded body:
+      """
+      {
+        "success": true,
+        "data": [
+          {"id":100000029,"login":"test_custom_jzk","password":"fy52ka"}
+        ]
+      }
+      """
+    And I am the user with id "21"
+    When I send a POST request to "/user-batches?sheet=1" with the following body:
+      """
+      {
+        "custom_prefix":"custom",
+        "group_prefix":"test",
+        "password_length":6,
+        "postfix_length":3,
+        "subgroups":[{"count":1,"group_id":4}]
+      }
+      """
+    Then the response code should be 201
+    And the response header "Content-Type" should be "application/pdf"
+    And the response header "Content-Disposition" should be "attachment; filename=user_batch_test_custom.pdf"
+    And the response body should contain "(test_custom_jzk) Tj"
+    And the response body should contain "(fy52ka) Tj"
+    And the response body should contain "(Friends) Tj"
+    And the table "user_batches_v2" should be:
+      | group_prefix | custom_prefix | size | creator_id |
+      | test         | custom        | 1    | 21         |
+    And the table "groups_groups" should be:
+      | parent_group_id | child_group_id      |
+      | 2               | 21                  |
+      | 2               | 5577006791947779410 |
+      | 3               | 4                   |
+      | 4               | 5577006791947779410 |
@@ -12,6 +12,7 @@ import (
 	"github.com/go-chi/render"
 	"github.com/jinzhu/gorm"
 
+	"github.com/France-ioi/AlgoreaBackend/v2/app/credentialsheet"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/domain"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
@@ -56,6 +57,12 @@ type subgroupApproval struct {
 	RequireWatchApproval              bool
 }
 
+// subgroupApprovalColumns selects the approvals required to join a group into subgroupApproval.
+const subgroupApprovalColumns = `
+	require_personal_info_access_approval != 'none' AS require_personal_info_access_approval,
+	IFNULL(require_lock_membership_approval_until > NOW(), 0) AS require_lock_membership_approval,
+	require_watch_approval`
+
 // swagger:operation POST /user-batches groups createUserBatch
 //
 //	---
@@ -73,6 +80,10 @@ type subgroupApproval struct {
 //		* adds the created users into groups specified as `subgroups[...].group_id` giving all the required approvals.
 //
 //
+//		If `sheet` = 1, the service returns a printable PDF document with a card for each created user
+//		(see `userBatchSheetGet`, here the cards contain the passwords) instead of the JSON response.
+//
+//
 //			Restrictions:
 //
 //		* The authenticated user (or one of his group ancestors) should be a manager of the group
@@ -87,15 +98,23 @@ type subgroupApproval struct {
 //		* Sum of `subgroups.count` + sum of sizes of existing batches under the same `group_prefix`
 //			should not be greater than `max_users` of the prefix, otherwise the 'bad request' response is returned.
 //	parameters:
+//		- name: sheet
+//			in: query
+//			type: integer
+//			enum: [0,1]
+//			default: 0
 //		- in: body
 //			name: data
 //			required: true
 //			description: The user batch to create
 //			schema:
 //				"$ref": "#/definitions/createUserBatchRequest"
+//	produces:
+//		- application/json
+//		- application/pdf
 //	responses:
 //		"201":
-//			description: "Created. Success response with the newly created task token"
+//			description: "Created. Success response with the newly created task token (or the PDF document if `sheet` = 1)"
 //			schema:
 //					type: object
 //					required: [success, message, data]
@@ -123,7 +142,11 @@ type subgroupApproval struct {
 //		"500":
 //			"$ref": "#/responses/internalErrorResponse"
 func (srv *Service) createUserBatch(w http.ResponseWriter, r *http.Request) service.APIError {
-	var err error
+	withSheet, err := service.ResolveURLQueryGetBoolFieldWithDefault(r, "sheet", false)
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
 	user := srv.GetUser(r)
 	store := srv.GetStore(r)
 
@@ -180,10 +203,44 @@ func (srv *Service) createUserBatch(w http.ResponseWriter, r *http.Request) serv
 
 	users := createBatchUsersInDB(store, input, r, numberOfUsersToBeCreated, createdUsers, subgroupsApprovals, user)
 
+	if withSheet {
+		writeCredentialSheet(w, r, http.StatusCreated, input.GroupPrefix, input.CustomPrefix, credentialSheetCards(store, users))
+		return service.NoError
+	}
+
 	service.MustNotBeError(render.Render(w, r, service.CreationSuccess(users)))
 	return service.NoError
 }
 
+func credentialSheetCards(store *database.DataStore, rows []*resultRow) []credentialsheet.Card {
+	groupIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		groupIDs = append(groupIDs, row.GroupID)
+	}
+	var groups []struct {
+		ID   int64
+		Name string
+		Code string
+	}
+	service.MustNotBeError(store.Groups().Where("id IN (?)", groupIDs).
+		Select("id, name, IFNULL(code, '') AS code").Scan(&groups).Error())
+	groupsMap := make(map[int64]int, len(groups))
+	for index := range groups {
+		groupsMap[groups[index].ID] = index
+	}
+
+	var cards []credentialsheet.Card
+	for _, row := range rows {
+		group := groups[groupsMap[row.GroupID]]
+		for _, user := range row.Users {
+			cards = append(cards, credentialsheet.Card{
+				Login: user.Login, Password: user.Password, GroupName: group.Name, GroupCode: group.Code,
+			})
+		}
+	}
+	return cards
+}
+
 func checkCreateUserBatchRequestParameters(store *database.DataStore, user *database.User, input createUserBatchRequest) (
 	numberOfUsersToBeCreated int, subgroupsApprovals []subgroupApproval, apiError service.APIError,
 ) {
@@ -219,10 +276,7 @@ func checkCreateUserBatchRequestParameters(store *database.DataStore, user *data
 		Where("ancestor_group_id = ?", prefixInfo.GroupID).
 		Where("groups.id IN(?)", subgroupIDs).
 		Where("groups.type != 'User'").
-		Select(`
-			require_personal_info_access_approval != 'none' AS require_personal_info_access_approval,
-			IFNULL(require_lock_membership_approval_until > NOW(), 0) AS require_lock_membership_approval,
-			require_watch_approval`).
+		Select(subgroupApprovalColumns).
 		Order(gorm.Expr("FIELD(groups.id"+strings.Repeat(", ?", len(subgroupIDs))+")", subgroupIDs...)).
 		Scan(&subgroupsApprovals).Error())
 	if len(subgroupsApprovals) != len(subgroupIDs) {
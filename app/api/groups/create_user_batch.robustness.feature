@@ -38,6 +38,16 @@ Feature: Create a user batch - robustness
           allUsersGroup: 2
       """
 
+  Scenario: Wrong sheet
+    Given I am the user with id "21"
+    When I send a POST request to "/user-batches?sheet=2" with the following body:
+    """
+    {}
+    """
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for sheet (should have a boolean value (0 or 1))"
+    And the table "user_batches_v2" should stay unchanged
+
   Scenario: Missing required fields
     Given I am the user with id "21"
     When I send a POST request to "/user-batches" with the following body:
@@ -50,6 +60,7 @@ Feature: Create a user batch - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors": {
         "custom_prefix": ["missing field"],
@@ -84,6 +95,7 @@ Feature: Create a user batch - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors": {
         "custom_prefix": ["The custom prefix should only consist of letters/digits/hyphens and be 2-14 characters long"],
@@ -118,6 +130,7 @@ Feature: Create a user batch - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors": {
         "custom_prefix": ["The custom prefix should only consist of letters/digits/hyphens and be 2-14 characters long"],
@@ -153,6 +166,7 @@ Feature: Create a user batch - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors": {
         "custom_prefix": ["The custom prefix should only consist of letters/digits/hyphens and be 2-14 characters long"],
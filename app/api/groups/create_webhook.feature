@@ -0,0 +1,88 @@
+Feature: Register a webhook (webhookCreate)
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+    And the database has the following table "groups":
+      | id |
+      | 13 |
+      | 14 |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 14             |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 21         | true              |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id  | type    | default_language_tag |
+      | 210 | Chapter | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_watch_generated |
+      | 21       | 210     | info               | result              |
+    And the generated auth key is "generatedsecret"
+
+  Scenario: Register a webhook for all the events of a descendant group
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/14/webhooks" with the following body:
+    """
+    {
+      "url": "https://example.org/hooks/algorea?token=abc",
+      "event_types": ["grade_saved", "result_validated", "item_unlocked", "group_membership_changed"]
+    }
+    """
+    Then the response code should be 201
+    And the response body should be, in JSON:
+    """
+    {
+      "success": true,
+      "message": "created",
+      "data": {"id": "5577006791947779410", "secret": "generatedsecret"}
+    }
+    """
+    And the table "webhooks" should be:
+      | id                  | group_id | item_id | url                                         | secret          | event_types                                                       | creator_id | ABS(TIMESTAMPDIFF(SECOND, NOW(), created_at)) < 3 |
+      | 5577006791947779410 | 14       | null    | https://example.org/hooks/algorea?token=abc | generatedsecret | grade_saved,result_validated,item_unlocked,group_membership_changed | 21         | 1                                                 |
+
+  Scenario: Register a webhook restricted to an item subtree
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/13/webhooks" with the following body:
+    """
+    {
+      "url": "https://example.org/hooks",
+      "item_id": "210",
+      "event_types": ["result_validated"]
+    }
+    """
+    Then the response code should be 201
+    And the response body should be, in JSON:
+    """
+    {
+      "success": true,
+      "message": "created",
+      "data": {"id": "5577006791947779410", "secret": "generatedsecret"}
+    }
+    """
+    And the table "webhooks" should be:
+      | id                  | group_id | item_id | url                       | secret          | event_types      | creator_id |
+      | 5577006791947779410 | 13       | 210     | https://example.org/hooks | generatedsecret | result_validated | 21         |
+
+  Scenario: Register a webhook with an insecure URL of a loopback address when allowed by the config
+    Given the application config is:
+    """
+    server:
+      webhooksAllowInsecureURLs: true
+      webhooksAllowPrivateAddresses: true
+    """
+    And I am the user with id "21"
+    When I send a POST request to "/groups/13/webhooks" with the following body:
+    """
+    {
+      "url": "http://localhost:8080/hooks",
+      "event_types": ["item_unlocked"]
+    }
+    """
+    Then the response code should be 201
+    And the table "webhooks" should be:
+      | id                  | group_id | item_id | url                         | event_types   |
+      | 5577006791947779410 | 13       | null    | http://localhost:8080/hooks | item_unlocked |
@@ -0,0 +1,217 @@
+package groups
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/France-ioi/validator"
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/webhooks"
+)
+
+// swagger:model createWebhookRequest
+type createWebhookRequest struct {
+	// URL the events are posted to (should be an absolute 'https' URL not pointing to a loopback, private or link-local address)
+	// required: true
+	URL string `json:"url" validate:"set,webhook_url,webhook_host"`
+	// If given, only events on descendants of this item (or on the item itself) are delivered
+	ItemID int64 `json:"item_id,string" validate:"webhook_item_id"`
+	// required: true
+	// minItems: 1
+	// items:
+	//   enum: grade_saved,result_validated,item_unlocked,group_membership_changed
+	EventTypes []string `json:"event_types" validate:"set,min=1,unique,webhook_event_types,dive,oneof=grade_saved result_validated item_unlocked group_membership_changed"` //nolint:lll
+}
+
+// swagger:model createWebhookResponse
+type createWebhookResponse struct {
+	// required: true
+	ID int64 `json:"id,string"`
+	// The secret used to sign the deliveries (it is not returned by other services)
+	// required: true
+	Secret string `json:"secret"`
+}
+
+// swagger:operation POST /groups/{group_id}/webhooks groups webhookCreate
+//
+//	---
+//	summary: Register a webhook
+//	description: >
+//
+//		Registers a webhook receiving the events of the given types happening to descendants of the group
+//		(and, if `item_id` is given, on descendants of the item):
+//
+//		* 'grade_saved': a grade has been saved for a result of a participant,
+//		* 'result_validated': a result of a participant has become validated
+//			(either by saving a grade or by the results propagation),
+//		* 'item_unlocked': an item has been unlocked for a participant,
+//		* 'group_membership_changed': a membership change has been recorded for the group or one of its descendants
+//			(not delivered to webhooks having an `item_id`).
+//
+//
+//		Each event is posted (asynchronously, by the webhook worker) to the `url` as a JSON object
+//		`{"delivery_id": "...", "event": "...", "created_at": "...", "data": {...}}`
+//		with the `X-Algorea-Event` & `X-Algorea-Delivery` headers and
+//		the `X-Algorea-Signature: t=<unix timestamp>,v1=<signature>` header where the signature is
+//		the hex-encoded HMAC-SHA256 of "<unix timestamp>.<request body>" keyed with the returned `secret`.
+//		A delivery is retried with an exponential backoff until a 2xx response is received (or too many attempts have failed).
+//
+//
+//		Restrictions:
+//
+//		* the current user should be a manager of the group with `can_watch_members`,
+//		* if `item_id` is given, the current user should have `can_watch` >= 'result' on the item,
+//		* the `url` should be an absolute 'https' URL, its host should not resolve to a loopback, private or link-local address
+//			(the addresses are checked again on each delivery),
+//
+//		otherwise the 'forbidden' (for access rights) or 'bad request' (for wrong values) error is returned.
+//	parameters:
+//		- name: group_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- in: body
+//			name: data
+//			required: true
+//			description: The webhook to register
+//			schema:
+//				"$ref": "#/definitions/createWebhookRequest"
+//	responses:
+//		"201":
+//			description: Created. The webhook has been registered.
+//			schema:
+//				type: object
+//				required: [success, message, data]
+//				properties:
+//					success:
+//						description: "true"
+//						type: boolean
+//						enum: [true]
+//					message:
+//						description: created
+//						type: string
+//						enum: [created]
+//					data:
+//						"$ref": "#/definitions/createWebhookResponse"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) createWebhook(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	groupID, err := service.ResolveURLQueryPathInt64Field(r, "group_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	if !user.CanWatchGroupMembers(store, groupID) {
+		return service.InsufficientAccessRightsError
+	}
+
+	input := createWebhookRequest{}
+	formData := formdata.NewFormData(&input)
+	allowInsecureURLs := srv.ServerConfig.GetBool("webhooksAllowInsecureURLs")
+	formData.RegisterValidation("webhook_url", constructWebhookURLValidator(allowInsecureURLs))
+	if allowInsecureURLs {
+		formData.RegisterTranslation("webhook_url", "should be an absolute 'http' or 'https' URL")
+	} else {
+		formData.RegisterTranslation("webhook_url", "should be an absolute 'https' URL")
+	}
+	formData.RegisterValidation("webhook_host",
+		constructWebhookHostValidator(r.Context(), srv.ServerConfig.GetBool("webhooksAllowPrivateAddresses")))
+	formData.RegisterTranslation("webhook_host", "should not point to a loopback, private or link-local address")
+	formData.RegisterValidation("webhook_item_id", formData.ValidatorSkippingUnsetFields(constructWebhookItemIDValidator(store, user)))
+	formData.RegisterTranslation("webhook_item_id", "should exist and the user should be able to watch results on it")
+	formData.RegisterValidation("webhook_event_types", constructWebhookEventTypesValidator(formData))
+	formData.RegisterTranslation("webhook_event_types", "group_membership_changed cannot be used together with item_id")
+	if err = formData.ParseJSONRequestData(r); err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	secret, err := auth.GenerateKey()
+	service.MustNotBeError(err)
+
+	var itemID interface{}
+	if formData.IsSet("item_id") {
+		itemID = input.ItemID
+	}
+	var webhookID int64
+	service.MustNotBeError(store.RetryOnDuplicatePrimaryKeyError("webhooks", func(store *database.DataStore) error {
+		webhookID = store.NewID()
+		return store.Webhooks().InsertMap(map[string]interface{}{
+			"id":          webhookID,
+			"group_id":    groupID,
+			"item_id":     itemID,
+			"url":         input.URL,
+			"secret":      secret,
+			"event_types": strings.Join(input.EventTypes, ","),
+			"creator_id":  user.GroupID,
+		})
+	}))
+
+	service.MustNotBeError(render.Render(w, r, service.CreationSuccess(&createWebhookResponse{ID: webhookID, Secret: secret})))
+	return service.NoError
+}
+
+func constructWebhookURLValidator(allowInsecureURLs bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		parsedURL, err := url.Parse(fl.Field().String())
+		if err != nil || parsedURL.Host == "" || parsedURL.User != nil {
+			return false
+		}
+		return parsedURL.Scheme == "https" || allowInsecureURLs && parsedURL.Scheme == "http"
+	}
+}
+
+func constructWebhookHostValidator(ctx context.Context, allowPrivateAddresses bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		if allowPrivateAddresses {
+			return true
+		}
+		parsedURL, err := url.Parse(fl.Field().String())
+		if err != nil {
+			return true // checked by the webhook_url validator
+		}
+		return webhooks.CheckHost(ctx, parsedURL.Hostname()) == nil
+	}
+}
+
+func constructWebhookItemIDValidator(store *database.DataStore, user *database.User) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		found, err := store.Permissions().MatchingUserAncestors(user).
+			WherePermissionIsAtLeast("watch", "result").
+			Where("item_id = ?", fl.Field().Int()).HasRows()
+		service.MustNotBeError(err)
+		return found
+	}
+}
+
+func constructWebhookEventTypesValidator(formData *formdata.FormData) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		if !formData.IsSet("item_id") {
+			return true
+		}
+		for index := 0; index < fl.Field().Len(); index++ {
+			if fl.Field().Index(index).String() == database.WebhookEventTypeGroupMembershipChanged {
+				return false
+			}
+		}
+		return true
+	}
+}
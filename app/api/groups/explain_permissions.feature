@@ -0,0 +1,166 @@
+Feature: Explain permissions of a group on an item
+  Background:
+    Given the database has the following table "groups":
+      | id | name       | type  |
+      | 10 | School     | Other |
+      | 25 | some class | Class |
+    And the database has the following users:
+      | group_id | login | first_name  | last_name |
+      | 21       | owner | Jean-Michel | Blanquer  |
+      | 23       | user  | John        | Doe       |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_grant_group_access |
+      | 25       | 21         | 1                      |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 10              | 25             |
+      | 25              | 23             |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id  | default_language_tag |
+      | 100 | fr                   |
+      | 101 | fr                   |
+      | 102 | fr                   |
+    And the database has the following table "items_items":
+      | parent_item_id | child_item_id | content_view_propagation | upper_view_levels_propagation | grant_view_propagation | watch_propagation | edit_propagation | child_order |
+      | 100            | 101           | as_content               | as_is                         | true                   | true              | true             | 0           |
+      | 101            | 102           | as_content               | as_content_with_descendants   | true                   | false             | true             | 0           |
+    And the database has the following table "items_ancestors":
+      | ancestor_item_id | child_item_id |
+      | 100              | 101           |
+      | 100              | 102           |
+      | 101              | 102           |
+    And the database has the following table "permissions_granted":
+      | group_id | item_id | source_group_id | can_view | can_grant_view | can_watch         | is_owner |
+      | 10       | 100     | 10              | solution | none           | answer_with_grant | false    |
+      | 21       | 101     | 21              | none     | enter          | none              | false    |
+      | 21       | 102     | 21              | none     | enter          | none              | false    |
+      | 25       | 101     | 25              | none     | none           | none              | true     |
+      | 25       | 102     | 25              | none     | content        | none              | false    |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated       | can_grant_view_generated | can_watch_generated | can_edit_generated | is_owner_generated |
+      | 10       | 100     | solution                 | none                     | answer_with_grant   | none               | false              |
+      | 10       | 101     | solution                 | none                     | answer              | none               | false              |
+      | 10       | 102     | content_with_descendants | none                     | none                | none               | false              |
+      | 21       | 101     | none                     | enter                    | none                | none               | false              |
+      | 21       | 102     | none                     | enter                    | none                | none               | false              |
+      | 23       | 102     | none                     | none                     | none                | none               | false              |
+      | 25       | 101     | solution                 | solution_with_grant      | answer_with_grant   | all_with_grant     | true               |
+      | 25       | 102     | content_with_descendants | solution                 | none                | all                | false              |
+
+  Scenario: Explains the permissions of a user
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/23/permissions/102/explain"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "can_view": {
+        "value": "content_with_descendants",
+        "group_chain": ["10", "25", "23"],
+        "item_chain": [
+          {"item_id": "100", "value": "solution", "source": "granted", "is_owner": false},
+          {
+            "item_id": "101", "value": "solution", "source": "propagated", "is_owner": false,
+            "parent_item_id": "100", "parent_value": "solution",
+            "relation": {
+              "content_view_propagation": "as_content", "upper_view_levels_propagation": "as_is",
+              "grant_view_propagation": true, "watch_propagation": true, "edit_propagation": true
+            }
+          },
+          {
+            "item_id": "102", "value": "content_with_descendants", "source": "propagated", "is_owner": false,
+            "parent_item_id": "101", "parent_value": "solution",
+            "relation": {
+              "content_view_propagation": "as_content", "upper_view_levels_propagation": "as_content_with_descendants",
+              "grant_view_propagation": true, "watch_propagation": false, "edit_propagation": true
+            },
+            "capped_by": "upper_view_levels_propagation"
+          }
+        ]
+      },
+      "can_grant_view": {
+        "value": "solution",
+        "group_chain": ["25", "23"],
+        "item_chain": [
+          {"item_id": "101", "value": "solution_with_grant", "source": "granted", "is_owner": true},
+          {
+            "item_id": "102", "value": "solution", "source": "propagated", "is_owner": false,
+            "parent_item_id": "101", "parent_value": "solution_with_grant",
+            "relation": {
+              "content_view_propagation": "as_content", "upper_view_levels_propagation": "as_content_with_descendants",
+              "grant_view_propagation": true, "watch_propagation": false, "edit_propagation": true
+            }
+          }
+        ]
+      },
+      "can_watch": {
+        "value": "none",
+        "group_chain": [],
+        "item_chain": []
+      },
+      "can_edit": {
+        "value": "all",
+        "group_chain": ["25", "23"],
+        "item_chain": [
+          {"item_id": "101", "value": "all_with_grant", "source": "granted", "is_owner": true},
+          {
+            "item_id": "102", "value": "all", "source": "propagated", "is_owner": false,
+            "parent_item_id": "101", "parent_value": "all_with_grant",
+            "relation": {
+              "content_view_propagation": "as_content", "upper_view_levels_propagation": "as_content_with_descendants",
+              "grant_view_propagation": true, "watch_propagation": false, "edit_propagation": true
+            }
+          }
+        ]
+      },
+      "is_owner": {
+        "value": false,
+        "group_chain": []
+      }
+    }
+    """
+
+  Scenario: Explains the permissions of an owner group
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/25/permissions/101/explain"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "can_view": {
+        "value": "solution",
+        "group_chain": ["10", "25"],
+        "item_chain": [
+          {"item_id": "100", "value": "solution", "source": "granted", "is_owner": false},
+          {
+            "item_id": "101", "value": "solution", "source": "propagated", "is_owner": false,
+            "parent_item_id": "100", "parent_value": "solution",
+            "relation": {
+              "content_view_propagation": "as_content", "upper_view_levels_propagation": "as_is",
+              "grant_view_propagation": true, "watch_propagation": true, "edit_propagation": true
+            }
+          }
+        ]
+      },
+      "can_grant_view": {
+        "value": "solution_with_grant",
+        "group_chain": ["25"],
+        "item_chain": [{"item_id": "101", "value": "solution_with_grant", "source": "granted", "is_owner": true}]
+      },
+      "can_watch": {
+        "value": "answer_with_grant",
+        "group_chain": ["25"],
+        "item_chain": [{"item_id": "101", "value": "answer_with_grant", "source": "granted", "is_owner": true}]
+      },
+      "can_edit": {
+        "value": "all_with_grant",
+        "group_chain": ["25"],
+        "item_chain": [{"item_id": "101", "value": "all_with_grant", "source": "granted", "is_owner": true}]
+      },
+      "is_owner": {
+        "value": true,
+        "group_chain": ["25"]
+      }
+    }
+    """
@@ -0,0 +1,232 @@
+package groups
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// Propagation settings of the `items_items` relation between the parent item and the item.
+type permissionExplanationRelation struct {
+	// required: true
+	// enum: none,as_info,as_content
+	ContentViewPropagation string `json:"content_view_propagation"`
+	// required: true
+	// enum: use_content_view_propagation,as_content_with_descendants,as_is
+	UpperViewLevelsPropagation string `json:"upper_view_levels_propagation"`
+	// required: true
+	GrantViewPropagation bool `json:"grant_view_propagation"`
+	// required: true
+	WatchPropagation bool `json:"watch_propagation"`
+	// required: true
+	EditPropagation bool `json:"edit_propagation"`
+}
+
+type permissionExplanationStep struct {
+	// required: true
+	ItemID int64 `json:"item_id,string"`
+	// The generated value of the permission for the item
+	// required: true
+	Value string `json:"value"`
+	// 'granted' if the value is granted on the item, 'propagated' if the value is propagated from the parent item
+	// required: true
+	// enum: granted,propagated
+	Source string `json:"source"`
+	// Whether the value is granted via `is_owner` (only for the 'granted' source)
+	// required: true
+	IsOwner bool `json:"is_owner"`
+	// Only for the 'propagated' source
+	ParentItemID *int64 `json:"parent_item_id,string,omitempty"`
+	// The generated value of the permission for the parent item (only for the 'propagated' source)
+	ParentValue *string `json:"parent_value,omitempty"`
+	// Only for the 'propagated' source
+	Relation *permissionExplanationRelation `json:"relation,omitempty"`
+	// The propagation setting of the relation which made the value lower than the value for the parent item
+	// (only for the 'propagated' source when the value has been capped by a propagation setting)
+	CappedBy *string `json:"capped_by,omitempty"`
+}
+
+type permissionExplanation struct {
+	// The generated value of the permission
+	// required: true
+	Value string `json:"value"`
+	// Ids of groups from the ancestor group having the permission to `{group_id}`
+	// (empty if the permission has its lowest value)
+	// required: true
+	GroupChain []string `json:"group_chain"`
+	// Items from the item on which the permission is granted to `{item_id}`
+	// (empty if the permission has its lowest value)
+	// required: true
+	ItemChain []permissionExplanationStep `json:"item_chain"`
+}
+
+type isOwnerExplanation struct {
+	// required: true
+	Value bool `json:"value"`
+	// Ids of groups from the ancestor group owning the item to `{group_id}`
+	// (empty if the group doesn't own the item)
+	// required: true
+	GroupChain []string `json:"group_chain"`
+}
+
+// swagger:model permissionsExplainResponse
+type permissionsExplainResponse struct {
+	// required: true
+	CanView permissionExplanation `json:"can_view"`
+	// required: true
+	CanGrantView permissionExplanation `json:"can_grant_view"`
+	// required: true
+	CanWatch permissionExplanation `json:"can_watch"`
+	// required: true
+	CanEdit permissionExplanation `json:"can_edit"`
+	// required: true
+	IsOwner isOwnerExplanation `json:"is_owner"`
+}
+
+// swagger:operation GET /groups/{group_id}/permissions/{item_id}/explain groups permissionsExplain
+//
+//	---
+//	summary: Explain generated permissions
+//	description: Lets a manager of a group see where the generated permissions of the group on an item come from.
+//
+//		For each permission (`can_view`, `can_grant_view`, `can_watch`, `can_edit`, `is_owner`),
+//		the service finds the ancestor group (or the group itself) having the highest generated value on the item
+//		(the group with the smallest id if there are several ones) and returns:
+//
+//		* the chain of groups from this ancestor group to `{group_id}`;
+//
+//		* the chain of items from the item on which the permission is granted to `{item_id}`,
+//			with, for each propagation step, the propagation settings of the `items_items` relation
+//			and the setting which capped the propagated value (`capped_by`).
+//
+//		The explanation relies on `permissions_generated`, so it reflects the last completed propagation of permissions.
+//
+//		* The current user must be a manager (with `can_grant_group_access` permission) of `{group_id}`
+//			or of one of its ancestors.
+//
+//		* The current user must have `can_grant_view` > 'none' or
+//			`can_watch` = 'answer_with_grant' or `can_edit` = 'all_with_grant' on `{item_id}`.
+//	parameters:
+//		- name: group_id
+//			in: path
+//			required: true
+//			type: integer
+//		- name: item_id
+//			in: path
+//			required: true
+//			type: integer
+//	responses:
+//		"200":
+//			description: OK. Explanation of the permissions of the group.
+//			schema:
+//				"$ref": "#/definitions/permissionsExplainResponse"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) explainPermissions(w http.ResponseWriter, r *http.Request) service.APIError {
+	groupID, err := service.ResolveURLQueryPathInt64Field(r, "group_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	itemID, err := service.ResolveURLQueryPathInt64Field(r, "item_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	found, err := store.Groups().ManagedBy(user).
+		Joins(`
+			JOIN groups_ancestors_active AS descendants
+				ON descendants.ancestor_group_id = groups.id AND descendants.child_group_id = ?`, groupID).
+		Where("group_managers.can_grant_group_access").
+		HasRows()
+	service.MustNotBeError(err)
+	if !found {
+		return service.InsufficientAccessRightsError
+	}
+
+	found, err = store.Permissions().MatchingUserAncestors(user).
+		Where("? OR can_watch_generated = 'answer_with_grant' OR can_edit_generated = 'all_with_grant'",
+			store.PermissionsGranted().PermissionIsAtLeastSQLExpr("grant_view", enter)).
+		Where("item_id = ?", itemID).HasRows()
+	service.MustNotBeError(err)
+	if !found {
+		return service.InsufficientAccessRightsError
+	}
+
+	explanations, err := store.Permissions().Explain(groupID, itemID)
+	service.MustNotBeError(err)
+
+	permissionsGrantedStore := store.PermissionsGranted()
+	render.Respond(w, r, &permissionsExplainResponse{
+		CanView:      permissionExplanationFromDatabase(explanations["view"], permissionsGrantedStore.ViewNameByIndex),
+		CanGrantView: permissionExplanationFromDatabase(explanations["grant_view"], permissionsGrantedStore.GrantViewNameByIndex),
+		CanWatch:     permissionExplanationFromDatabase(explanations["watch"], permissionsGrantedStore.WatchNameByIndex),
+		CanEdit:      permissionExplanationFromDatabase(explanations["edit"], permissionsGrantedStore.EditNameByIndex),
+		IsOwner: isOwnerExplanation{
+			Value:      explanations["is_owner"].Value == 1,
+			GroupChain: groupChainToStrings(explanations["is_owner"].GroupChain),
+		},
+	})
+	return service.NoError
+}
+
+func permissionExplanationFromDatabase(
+	explanation *database.PermissionExplanation, nameByIndex func(int) string,
+) permissionExplanation {
+	result := permissionExplanation{
+		Value:      nameByIndex(explanation.Value),
+		GroupChain: groupChainToStrings(explanation.GroupChain),
+		ItemChain:  make([]permissionExplanationStep, 0, len(explanation.ItemChain)),
+	}
+	for index := range explanation.ItemChain {
+		step := &explanation.ItemChain[index]
+		resultStep := permissionExplanationStep{
+			ItemID:  step.ItemID,
+			Value:   nameByIndex(step.Value),
+			Source:  "granted",
+			IsOwner: step.IsOwner,
+		}
+		if step.ParentItemID != nil {
+			parentValue := nameByIndex(step.ParentValue)
+			resultStep.Source = "propagated"
+			resultStep.ParentItemID = step.ParentItemID
+			resultStep.ParentValue = &parentValue
+			resultStep.Relation = &permissionExplanationRelation{
+				ContentViewPropagation:     step.Relation.ContentViewPropagation,
+				UpperViewLevelsPropagation: step.Relation.UpperViewLevelsPropagation,
+				GrantViewPropagation:       step.Relation.GrantViewPropagation,
+				WatchPropagation:           step.Relation.WatchPropagation,
+				EditPropagation:            step.Relation.EditPropagation,
+			}
+			if step.CappedBy != "" {
+				cappedBy := step.CappedBy
+				resultStep.CappedBy = &cappedBy
+			}
+		}
+		result.ItemChain = append(result.ItemChain, resultStep)
+	}
+	return result
+}
+
+func groupChainToStrings(groupChain []int64) []string {
+	result := make([]string, 0, len(groupChain))
+	for _, groupID := range groupChain {
+		result = append(result, strconv.FormatInt(groupID, 10))
+	}
+	return result
+}
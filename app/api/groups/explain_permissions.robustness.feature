@@ -0,0 +1,65 @@
+Feature: Explain permissions of a group on an item - robustness
+  Background:
+    Given the database has the following table "groups":
+      | id | name          | type  |
+      | 25 | some class    | Class |
+      | 26 | another class | Class |
+    And the database has the following users:
+      | group_id | login   | first_name  | last_name |
+      | 21       | owner   | Jean-Michel | Blanquer  |
+      | 23       | user    | John        | Doe       |
+      | 31       | manager | Allie       | Grater    |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_grant_group_access |
+      | 25       | 21         | 1                      |
+      | 26       | 21         | 0                      |
+      | 25       | 31         | 1                      |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 25              | 23             |
+      | 26              | 23             |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id  | default_language_tag |
+      | 100 | fr                   |
+      | 101 | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_grant_view_generated | can_watch_generated | can_edit_generated |
+      | 21       | 100     | solution           | enter                    | none                | none               |
+      | 31       | 100     | solution           | none                     | answer              | all                |
+
+  Scenario: Invalid group_id
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/abc/permissions/100/explain"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for group_id (should be int64)"
+
+  Scenario: Invalid item_id
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/23/permissions/abc/explain"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for item_id (should be int64)"
+
+  Scenario: The user is not a manager of the group
+    Given I am the user with id "23"
+    When I send a GET request to "/groups/23/permissions/100/explain"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The user is a manager of the group without can_grant_group_access
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/26/permissions/100/explain"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The user cannot grant permissions on the item
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/23/permissions/101/explain"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The user has can_grant_view = none, can_watch < answer_with_grant and can_edit < all_with_grant on the item
+    Given I am the user with id "31"
+    When I send a GET request to "/groups/23/permissions/100/explain"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
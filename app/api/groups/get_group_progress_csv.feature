@@ -346,3 +346,18 @@ Feature: Export the current progress of a group on a subset of items as a CSV fi
     Group name;Chapitre 210;1. Item 211;2. Item 212;3. Item 213;4. Item 214;5. Item 215;Chapter 220;1. Item 221;2. Item 222;3. Item 223;4. Item 224;5. Item 225;Chapitre 310;1. Item 311;2. Item 312;3. Item 313;4. Item 314;5. Item 315
 
     """
+
+  Scenario: Get progress of groups as NDJSON
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/1/group-progress-csv?parent_item_ids=210&format=ndjson"
+    Then the response code should be 200
+    And the response header "Content-Type" should be "application/x-ndjson"
+    And the response header "Content-Disposition" should be "attachment; filename=groups_progress_for_group_1_and_child_items_of_210.ndjson"
+    And the response body should be:
+    """
+    {"id":"17","name":"A custom group","scores":{"210":25,"211":25,"212":5,"213":null,"214":0,"215":null}}
+    {"id":"18","name":"Club","scores":{"210":null,"211":null,"212":null,"213":null,"214":null,"215":null}}
+    {"id":"11","name":"Our Class","scores":{"210":46.666666666666664,"211":16.666666666666668,"212":3.3333333333333335,"213":null,"214":0,"215":null}}
+    {"id":"12","name":"Zero Class","scores":{"210":null,"211":null,"212":null,"213":null,"214":null,"215":null}}
+
+    """
@@ -1,14 +1,13 @@
 package groups
 
 import (
-	"encoding/csv"
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/jinzhu/gorm"
 
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 )
 
@@ -40,6 +39,12 @@ type idName struct {
 //
 //
 //						 otherwise the 'forbidden' error is returned.
+//
+//
+//						 The rows are computed and sent to the client in chunks of participants.
+//						 With `format`=`ndjson`, each row is a JSON object on its own line (there is no header)
+//						 containing the group's `id`, `name` and `scores`, a map of scores by item IDs
+//						 (a score is `null` when there is no result).
 //	parameters:
 //		- name: group_id
 //			in: path
@@ -51,6 +56,11 @@ type idName struct {
 //			required: true
 //			items:
 //				type: integer
+//		- name: format
+//			in: query
+//			type: string
+//			enum: [csv,ndjson]
+//			default: csv
 //	responses:
 //		"200":
 //			description: OK. Success response with users progress on items
@@ -58,6 +68,9 @@ type idName struct {
 //				text/csv:
 //					schema:
 //					type: string
+//				application/x-ndjson:
+//					schema:
+//					type: string
 //			examples:
 //				text/csv:
 //					Group name;Parent item;1. First child item;2. Second child item
@@ -82,6 +95,11 @@ func (srv *Service) getGroupProgressCSV(w http.ResponseWriter, r *http.Request)
 		return service.ErrInvalidRequest(err)
 	}
 
+	format, apiError := resolveProgressExportFormat(r)
+	if apiError != service.NoError {
+		return apiError
+	}
+
 	if !user.CanWatchGroupMembers(store, groupID) {
 		return service.InsufficientAccessRightsError
 	}
@@ -91,121 +109,119 @@ func (srv *Service) getGroupProgressCSV(w http.ResponseWriter, r *http.Request)
 		return apiError
 	}
 
-	w.Header().Set("Content-Type", "text/csv")
-	itemParentIDsString := make([]string, len(itemParentIDs))
-	for i, id := range itemParentIDs {
-		itemParentIDsString[i] = strconv.FormatInt(id, 10)
-	}
-	w.Header().Set("Content-Disposition",
-		fmt.Sprintf("attachment; filename=groups_progress_for_group_%d_and_child_items_of_%s.csv",
-			groupID, strings.Join(itemParentIDsString, "_")))
+	writer := srv.newProgressExportWriter(w, format, "groups", groupID, itemParentIDs)
+	defer writer.close()
 	if len(itemParentIDs) == 0 {
-		_, err := w.Write([]byte("Group name\n"))
-		service.MustNotBeError(err)
+		writer.writeHeader([]string{"Group name"})
 		return service.NoError
 	}
 
 	// Preselect item IDs since we need them to build the results table (there shouldn't be many)
 	orderedItemIDListWithDuplicates, uniqueItemIDs, itemOrder, itemsSubQuery := preselectIDsOfVisibleItems(store, itemParentIDs, user)
 
-	csvWriter := csv.NewWriter(w)
-	defer csvWriter.Flush()
-	csvWriter.Comma = ';'
-
-	printTableHeader(store, user, uniqueItemIDs, orderedItemIDListWithDuplicates, itemOrder, csvWriter,
+	printTableHeader(store, user, uniqueItemIDs, orderedItemIDListWithDuplicates, itemOrder, writer,
 		[]string{"Group name"})
+	writer.setColumns([]string{"name"}, orderedItemIDListWithDuplicates)
 
-	// Preselect groups for that we will calculate the stats.
+	// Groups for that we calculate the stats are loaded chunk by chunk.
 	// All the "end members" are descendants of these groups.
-	var groups []idName
-	service.MustNotBeError(store.ActiveGroupGroups().
+	groupsQuery := store.ActiveGroupGroups().
 		Where("groups_groups_active.parent_group_id = ?", groupID).
 		Joins(`
 			JOIN ` + "`groups`" + ` AS group_child
 			ON group_child.id = groups_groups_active.child_group_id AND group_child.type NOT IN('Team', 'User')`).
 		Order("group_child.name, group_child.id").
 		Select("group_child.id, group_child.name").
-		Scan(&groups).Error())
+		Limit(csvExportGroupProgressBatchSize)
 
-	if len(groups) == 0 {
-		return service.NoError
-	}
+	var groups []idName
+	for {
+		query := groupsQuery
+		if len(groups) > 0 {
+			lastGroup := groups[len(groups)-1]
+			query = query.Where("group_child.name > ? OR group_child.name = ? AND group_child.id > ?",
+				lastGroup.Name, lastGroup.Name, lastGroup.ID)
+		}
+		groups = nil
+		service.MustNotBeError(query.Scan(&groups).Error())
+		if len(groups) == 0 {
+			break
+		}
 
-	ancestorGroupIDs := make([]string, len(groups))
-	for i := range groups {
-		ancestorGroupIDs[i] = strconv.FormatInt(groups[i].ID, 10)
-	}
+		writeGroupProgressChunk(store, groups, len(uniqueItemIDs), itemsSubQuery, writer)
+		writer.flushChunk()
 
-	for startFromGroup := 0; startFromGroup < len(ancestorGroupIDs); startFromGroup += csvExportGroupProgressBatchSize {
-		batchBoundary := startFromGroup + csvExportGroupProgressBatchSize
-		if batchBoundary > len(ancestorGroupIDs) {
-			batchBoundary = len(ancestorGroupIDs)
+		if len(groups) < csvExportGroupProgressBatchSize {
+			break
 		}
-		ancestorsInBatch := ancestorGroupIDs[startFromGroup:batchBoundary]
-		ancestorsInBatchIDsList := strings.Join(ancestorsInBatch, ", ")
-
-		endMembers := store.Groups().
-			Select("groups.id").
-			Joins(`
-				JOIN groups_ancestors_active
-				ON groups_ancestors_active.ancestor_group_id IN (?) AND
-					groups_ancestors_active.child_group_id = groups.id`, ancestorsInBatch).
-			Where("groups.type = 'Team' OR groups.type = 'User'").
-			Group("groups.id")
-
-		endMembersStats := store.Raw(`
-		SELECT
-			end_members.id,
-			items.id AS item_id,
-			(
-				SELECT score_computed AS score
-				FROM results
-				WHERE participant_id = end_members.id AND item_id = items.id
-				ORDER BY participant_id, item_id, score_computed DESC, score_obtained_at
-				LIMIT 1
-			) AS score
-		FROM ? AS end_members`, endMembers.SubQuery()).
-			Joins("JOIN ? AS items", itemsSubQuery)
-
-		groupNumber := startFromGroup
-		service.MustNotBeError(store.ActiveGroupAncestors().
-			Select(`
-				groups_ancestors_active.ancestor_group_id AS group_id,
-				member_stats.item_id,
-				IF(MAX(member_stats.score IS NOT NULL), AVG(IFNULL(member_stats.score, 0)), '') AS score`).
-			Joins("JOIN ? AS member_stats ON member_stats.id = groups_ancestors_active.child_group_id", endMembersStats.SubQuery()).
-			Where("groups_ancestors_active.ancestor_group_id IN (?)", ancestorsInBatch).
-			Group("groups_ancestors_active.ancestor_group_id, member_stats.item_id").
-			Order(gorm.Expr(
-				"FIELD(groups_ancestors_active.ancestor_group_id, " + ancestorsInBatchIDsList + ")")).
-			ScanAndHandleMaps(processCSVResultRow(orderedItemIDListWithDuplicates, len(uniqueItemIDs), &groupNumber,
-				generateGroupNameAndWriteEmptyRowsForSkippedGroups(&groupNumber, groups, len(orderedItemIDListWithDuplicates), csvWriter),
-				csvWriter)).Error())
-		writeEmptyRowsForSkippedGroupsAtTheEnd(groupNumber, batchBoundary, groups, len(orderedItemIDListWithDuplicates), csvWriter)
 	}
 
 	return service.NoError
 }
 
+func writeGroupProgressChunk(
+	store *database.DataStore, groups []idName, uniqueItemsCount int,
+	itemsSubQuery interface{}, writer *progressExportWriter,
+) {
+	ancestorsInBatch := make([]string, len(groups))
+	for i := range groups {
+		ancestorsInBatch[i] = strconv.FormatInt(groups[i].ID, 10)
+	}
+	ancestorsInBatchIDsList := strings.Join(ancestorsInBatch, ", ")
+
+	endMembers := store.Groups().
+		Select("groups.id").
+		Joins(`
+			JOIN groups_ancestors_active
+			ON groups_ancestors_active.ancestor_group_id IN (?) AND
+				groups_ancestors_active.child_group_id = groups.id`, ancestorsInBatch).
+		Where("groups.type = 'Team' OR groups.type = 'User'").
+		Group("groups.id")
+
+	endMembersStats := store.Raw(`
+	SELECT
+		end_members.id,
+		items.id AS item_id,
+		(
+			SELECT score_computed AS score
+			FROM results
+			WHERE participant_id = end_members.id AND item_id = items.id
+			ORDER BY participant_id, item_id, score_computed DESC, score_obtained_at
+			LIMIT 1
+		) AS score
+	FROM ? AS end_members`, endMembers.SubQuery()).
+		Joins("JOIN ? AS items", itemsSubQuery)
+
+	groupNumber := 0
+	service.MustNotBeError(store.ActiveGroupAncestors().
+		Select(`
+			groups_ancestors_active.ancestor_group_id AS group_id,
+			member_stats.item_id,
+			IF(MAX(member_stats.score IS NOT NULL), AVG(IFNULL(member_stats.score, 0)), '') AS score`).
+		Joins("JOIN ? AS member_stats ON member_stats.id = groups_ancestors_active.child_group_id", endMembersStats.SubQuery()).
+		Where("groups_ancestors_active.ancestor_group_id IN (?)", ancestorsInBatch).
+		Group("groups_ancestors_active.ancestor_group_id, member_stats.item_id").
+		Order(gorm.Expr(
+			"FIELD(groups_ancestors_active.ancestor_group_id, " + ancestorsInBatchIDsList + ")")).
+		ScanAndHandleMaps(processProgressExportResultRow(uniqueItemsCount, &groupNumber,
+			generateGroupNameAndWriteEmptyRowsForSkippedGroups(&groupNumber, groups, writer),
+			writer)).Error())
+	writeEmptyRowsForSkippedGroupsAtTheEnd(groupNumber, groups, writer)
+}
+
 func generateGroupNameAndWriteEmptyRowsForSkippedGroups(
-	groupNumber *int, groups []idName, numberOfItems int, csvWriter *csv.Writer,
+	groupNumber *int, groups []idName, writer *progressExportWriter,
 ) func(groupID int64) []string {
 	return func(groupID int64) []string {
 		for ; groups[*groupNumber].ID != groupID; *groupNumber++ {
-			writeEmptyGroupProgressResultRow(csvWriter, groups[*groupNumber].Name, numberOfItems)
+			writer.writeRow(groups[*groupNumber].ID, []string{groups[*groupNumber].Name}, nil)
 		}
 		return []string{groups[*groupNumber].Name}
 	}
 }
 
-func writeEmptyRowsForSkippedGroupsAtTheEnd(groupNumber, batchBoundary int, groups []idName, numberOfItems int, csvWriter *csv.Writer) {
-	for ; groupNumber < batchBoundary; groupNumber++ {
-		writeEmptyGroupProgressResultRow(csvWriter, groups[groupNumber].Name, numberOfItems)
+func writeEmptyRowsForSkippedGroupsAtTheEnd(groupNumber int, groups []idName, writer *progressExportWriter) {
+	for ; groupNumber < len(groups); groupNumber++ {
+		writer.writeRow(groups[groupNumber].ID, []string{groups[groupNumber].Name}, nil)
 	}
 }
-
-func writeEmptyGroupProgressResultRow(csvWriter *csv.Writer, groupName string, numberOfItems int) {
-	service.MustNotBeError(
-		csvWriter.Write(
-			append([]string{groupName}, make([]string, numberOfItems)...)))
-}
@@ -45,6 +45,12 @@ Feature: Export the current progress of a group on a subset of items as a CSV fi
     Then the response code should be 400
     And the response error message should contain "Wrong value for group_id (should be int64)"
 
+  Scenario: format is incorrect
+    Given I am the user with id "11"
+    When I send a GET request to "/groups/13/group-progress-csv?parent_item_ids=210&format=xlsx"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for format (should be one of csv, ndjson)"
+
   Scenario: parent_item_ids is incorrect
     Given I am the user with id "21"
     When I send a GET request to "/groups/13/group-progress-csv?parent_item_ids=abc,123"
@@ -7,6 +7,7 @@ import (
 	"github.com/go-chi/render"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/structures"
 )
@@ -326,7 +327,7 @@ func (srv *Service) parseParticipantProgressParameters(r *http.Request, store *d
 
 	if watchedGroupIDIsSet {
 		if len(r.URL.Query()["as_team_id"]) != 0 {
-			return params, service.ErrInvalidRequest(errors.New("only one of as_team_id and watched_group_id can be given"))
+			return params, service.ErrInvalidRequest(i18n.NewError("both_team_and_watched_group"))
 		}
 
 		params.ParticipantID = watchedGroupID
@@ -73,6 +73,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "none", "can_grant_view": "none", "can_edit": "none", "can_watch": "none",
           "can_enter_from": "9999-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -137,6 +138,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "solution", "can_grant_view": "solution_with_grant", "can_edit": "all_with_grant", "can_watch": "answer_with_grant",
           "can_enter_from": "2017-12-31T23:59:59Z", "can_enter_until": "9998-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": true, "is_owner": true,
           "can_request_help_to": null
         },
@@ -201,6 +203,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "9999-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -265,6 +268,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "9999-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -329,6 +333,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "9999-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -393,6 +398,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "9999-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -457,6 +463,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "9999-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -521,6 +528,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "9999-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -585,6 +593,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "9999-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -649,6 +658,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "9999-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -713,6 +723,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "2021-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -777,6 +788,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "2029-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -841,6 +853,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "2029-12-31T23:59:59Z", "can_enter_until": "2020-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -905,6 +918,7 @@ Feature: Get permissions for a group
         "granted": {
           "can_view": "content_with_descendants", "can_grant_view": "solution", "can_edit": "all", "can_watch": "answer",
           "can_enter_from": "2011-12-31T23:59:59Z", "can_enter_until": "9999-12-31T23:59:59Z",
+          "can_view_from": "1000-01-01T00:00:00Z", "can_view_until": "9999-12-31T23:59:59Z",
           "can_make_session_official": false, "is_owner": false,
           "can_request_help_to": null
         },
@@ -51,6 +51,12 @@ type grantedPermissionsStruct struct {
 	CanEnterFrom string `json:"can_enter_from"`
 	// required: true
 	CanEnterUntil string `json:"can_enter_until"`
+	// Time from which `can_view` is granted
+	// required: true
+	CanViewFrom string `json:"can_view_from"`
+	// Time until which `can_view` is granted
+	// required: true
+	CanViewUntil string `json:"can_view_until"`
 	// required: true
 	CanRequestHelpTo *canRequestHelpTo `json:"can_request_help_to"`
 }
@@ -230,6 +236,8 @@ func (srv *Service) getPermissions(w http.ResponseWriter, r *http.Request) servi
 			IFNULL(MAX(can_edit_value), 1) AS can_edit_value,
 			IFNULL(MAX(can_enter_from), '9999-12-31 23:59:59') AS can_enter_from,
 			IFNULL(MAX(can_enter_until), '9999-12-31 23:59:59') AS can_enter_until,
+			IFNULL(MAX(can_view_from), '1000-01-01 00:00:00') AS can_view_from,
+			IFNULL(MAX(can_view_until), '9999-12-31 23:59:59') AS can_view_until,
 			IFNULL(MAX(is_owner), 0) AS is_owner, ` + canMakeSessionOfficialColumn)
 
 	generatedPermissions := store.Permissions().
@@ -279,6 +287,7 @@ func (srv *Service) getPermissions(w http.ResponseWriter, r *http.Request) servi
 				grp.can_watch_value AS granted_directly_can_watch_value, grp.can_edit_value AS granted_directly_can_edit_value,
 				grp.can_make_session_official AS granted_directly_can_make_session_official, grp.can_enter_from AS granted_directly_can_enter_from,
 				grp.can_enter_until AS granted_directly_can_enter_until, grp.is_owner AS granted_directly_is_owner,
+				grp.can_view_from AS granted_directly_can_view_from, grp.can_view_until AS granted_directly_can_view_until,
 
 				gep.can_view_generated_value AS generated_can_view_value, gep.can_grant_view_generated_value AS generated_can_grant_view_value,
 				gep.can_watch_generated_value AS generated_can_watch_value, gep.can_edit_generated_value AS generated_can_edit_value,
@@ -349,6 +358,8 @@ func (srv *Service) getPermissions(w http.ResponseWriter, r *http.Request) servi
 			},
 			CanEnterFrom:     service.ConvertDBTimeToJSONTime(permissionsRow["granted_directly_can_enter_from"]),
 			CanEnterUntil:    service.ConvertDBTimeToJSONTime(permissionsRow["granted_directly_can_enter_until"]),
+			CanViewFrom:      service.ConvertDBTimeToJSONTime(permissionsRow["granted_directly_can_view_from"]),
+			CanViewUntil:     service.ConvertDBTimeToJSONTime(permissionsRow["granted_directly_can_view_until"]),
 			CanRequestHelpTo: canRequestHelpToPermission,
 		},
 		Computed: computedPermissions{aggregatedPermissionsWithCanEnterFromStruct{
@@ -294,3 +294,26 @@ Feature: Export the current progress of teams on a subset of items as CSV (group
     Team name;Chapitre 210;1. Item 211;2. Item 212;3. Item 213;4. Item 214;5. Item 215;Chapter 220;1. Item 221;2. Item 222;3. Item 223;4. Item 224;5. Item 225;Chapitre 310;1. Item 311;2. Item 312;3. Item 313;4. Item 314;5. Item 315
 
     """
+
+  Scenario: Get progress of teams as NDJSON
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/1/team-progress-csv?parent_item_ids=210&format=ndjson"
+    Then the response code should be 200
+    And the response header "Content-Type" should be "application/x-ndjson"
+    And the response header "Content-Disposition" should be "attachment; filename=teams_progress_for_group_1_and_child_items_of_210.ndjson"
+    And the response body should be:
+    """
+    {"id":"16","name":"First Team","scores":{"210":null,"211":null,"212":10,"213":null,"214":null,"215":null}}
+    {"id":"14","name":"Super Team","scores":{"210":50,"211":50,"212":null,"213":null,"214":0,"215":null}}
+
+    """
+
+  Scenario: No parent item ids given (NDJSON)
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/1/team-progress-csv?parent_item_ids=&format=ndjson"
+    Then the response code should be 200
+    And the response header "Content-Type" should be "application/x-ndjson"
+    And the response header "Content-Disposition" should be "attachment; filename=teams_progress_for_group_1_and_child_items_of_.ndjson"
+    And the response body should be:
+    """
+    """
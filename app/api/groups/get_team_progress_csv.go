@@ -1,8 +1,6 @@
 package groups
 
 import (
-	"encoding/csv"
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -33,6 +31,12 @@ import (
 //
 //
 //						 otherwise the 'forbidden' error is returned.
+//
+//
+//						 The rows are computed and sent to the client in chunks of participants.
+//						 With `format`=`ndjson`, each row is a JSON object on its own line (there is no header)
+//						 containing the team's `id`, `name` and `scores`, a map of scores by item IDs
+//						 (a score is `null` when there is no result).
 //	parameters:
 //		- name: group_id
 //			in: path
@@ -44,6 +48,11 @@ import (
 //			type: array
 //			items:
 //				type: integer
+//		- name: format
+//			in: query
+//			type: string
+//			enum: [csv,ndjson]
+//			default: csv
 //	responses:
 //		"200":
 //			description: OK. Success response with users progress on items
@@ -51,6 +60,9 @@ import (
 //				text/csv:
 //					schema:
 //					type: string
+//				application/x-ndjson:
+//					schema:
+//					type: string
 //			examples:
 //				text/csv:
 //					Team name;Parent item;1. First child item;2. Second child item
@@ -75,6 +87,11 @@ func (srv *Service) getTeamProgressCSV(w http.ResponseWriter, r *http.Request) s
 		return service.ErrInvalidRequest(err)
 	}
 
+	format, apiError := resolveProgressExportFormat(r)
+	if apiError != service.NoError {
+		return apiError
+	}
+
 	if !user.CanWatchGroupMembers(store, groupID) {
 		return service.InsufficientAccessRightsError
 	}
@@ -84,58 +101,48 @@ func (srv *Service) getTeamProgressCSV(w http.ResponseWriter, r *http.Request) s
 		return apiError
 	}
 
-	w.Header().Set("Content-Type", "text/csv")
-	itemParentIDsString := make([]string, len(itemParentIDs))
-	for i, id := range itemParentIDs {
-		itemParentIDsString[i] = strconv.FormatInt(id, 10)
-	}
-	w.Header().Set("Content-Disposition",
-		fmt.Sprintf("attachment; filename=teams_progress_for_group_%d_and_child_items_of_%s.csv",
-			groupID, strings.Join(itemParentIDsString, "_")))
+	writer := srv.newProgressExportWriter(w, format, "teams", groupID, itemParentIDs)
+	defer writer.close()
 	if len(itemParentIDs) == 0 {
-		_, err := w.Write([]byte("Team name\n"))
-		service.MustNotBeError(err)
+		writer.writeHeader([]string{"Team name"})
 		return service.NoError
 	}
 
 	// Preselect item IDs since we need them to build the results table (there shouldn't be many)
 	orderedItemIDListWithDuplicates, uniqueItemIDs, itemOrder, itemsSubQuery := preselectIDsOfVisibleItems(store, itemParentIDs, user)
 
-	csvWriter := csv.NewWriter(w)
-	defer csvWriter.Flush()
-	csvWriter.Comma = ';'
-
-	printTableHeader(store, user, uniqueItemIDs, orderedItemIDListWithDuplicates, itemOrder, csvWriter,
+	printTableHeader(store, user, uniqueItemIDs, orderedItemIDListWithDuplicates, itemOrder, writer,
 		[]string{"Team name"})
+	writer.setColumns([]string{"name"}, orderedItemIDListWithDuplicates)
 
-	// Preselect teams for that we will calculate the stats.
-	var teams []struct {
-		ID   int64
-		Name string
-	}
-	service.MustNotBeError(store.ActiveGroupAncestors().
+	// Teams for that we calculate the stats are loaded chunk by chunk.
+	teamsQuery := store.ActiveGroupAncestors().
 		Joins("JOIN `groups` ON groups.id = groups_ancestors_active.child_group_id AND groups.type = 'Team'").
 		Where("groups_ancestors_active.ancestor_group_id = ?", groupID).
 		Where("groups_ancestors_active.child_group_id != groups_ancestors_active.ancestor_group_id").
 		Order("groups.name, groups.id").
 		Select("groups.id, groups.name").
-		Scan(&teams).Error())
+		Limit(csvExportBatchSize)
 
-	if len(teams) == 0 {
-		return service.NoError
-	}
-	teamIDs := make([]string, len(teams))
-	for i := range teams {
-		teamIDs[i] = strconv.FormatInt(teams[i].ID, 10)
-	}
+	var teams []idName
+	for {
+		query := teamsQuery
+		if len(teams) > 0 {
+			lastTeam := teams[len(teams)-1]
+			query = query.Where("groups.name > ? OR groups.name = ? AND groups.id > ?", lastTeam.Name, lastTeam.Name, lastTeam.ID)
+		}
+		teams = nil
+		service.MustNotBeError(query.Scan(&teams).Error())
+		if len(teams) == 0 {
+			break
+		}
 
-	for startFromTeam := 0; startFromTeam < len(teamIDs); startFromTeam += csvExportBatchSize {
-		batchBoundary := startFromTeam + csvExportBatchSize
-		if batchBoundary > len(teamIDs) {
-			batchBoundary = len(teamIDs)
+		teamIDs := make([]string, len(teams))
+		for i := range teams {
+			teamIDs[i] = strconv.FormatInt(teams[i].ID, 10)
 		}
-		teamIDsList := strings.Join(teamIDs[startFromTeam:batchBoundary], ", ")
-		teamNumber := startFromTeam
+		teamIDsList := strings.Join(teamIDs, ", ")
+		teamNumber := 0
 		// nolint:gosec
 		service.MustNotBeError(store.Raw(`
 				SELECT
@@ -155,11 +162,16 @@ func (srv *Service) getTeamProgressCSV(w http.ResponseWriter, r *http.Request) s
 			Order(gorm.Expr(
 				"FIELD(groups.id, " + teamIDsList + ")")).
 			ScanAndHandleMaps(
-				processCSVResultRow(
-					orderedItemIDListWithDuplicates, len(uniqueItemIDs), &teamNumber,
+				processProgressExportResultRow(
+					len(uniqueItemIDs), &teamNumber,
 					func(_ int64) []string {
 						return []string{teams[teamNumber].Name}
-					}, csvWriter)).Error())
+					}, writer)).Error())
+		writer.flushChunk()
+
+		if len(teams) < csvExportBatchSize {
+			break
+		}
 	}
 
 	return service.NoError
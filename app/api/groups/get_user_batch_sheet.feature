@@ -0,0 +1,55 @@
+Feature: Get a credential sheet of a user batch (userBatchSheetGet)
+  Background:
+    Given the database has the following table "groups":
+      | id | name    | type  | code       |
+      | 3  | Club    | Club  | 3456789abc |
+      | 4  | Friends | Class | null       |
+    And the database has the following users:
+      | group_id | login         |
+      | 21       | owner         |
+      | 31       | test_custom_b |
+      | 32       | test_custom_a |
+      | 33       | test_other_c  |
+      | 34       | test_custom_d |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_manage  |
+      | 3        | 21         | memberships |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 3               | 4              |
+      | 3               | 31             |
+      | 3               | 33             |
+      | 4               | 32             |
+    And the groups ancestors are computed
+    And the database has the following table "user_batch_prefixes":
+      | group_prefix | group_id | allow_new |
+      | test         | 3        | 1         |
+    And the database has the following table "user_batches_v2":
+      | group_prefix | custom_prefix | size | creator_id |
+      | test         | custom        | 3    | 21         |
+      | test         | other         | 1    | 21         |
+    And the application config is:
+      """
+      domains:
+        -
+          domains: [127.0.0.1]
+          allUsersGroup: 2
+          platformURL: "https://example.org"
+      """
+
+  Scenario: Get the credential sheet
+    Given I am the user with id "21"
+    When I send a GET request to "/user-batches/test/custom/sheet"
+    Then the response code should be 200
+    And the response header "Content-Type" should be "application/pdf"
+    And the response header "Content-Disposition" should be "attachment; filename=user_batch_test_custom.pdf"
+    And the response body should contain "%PDF-1.4"
+    And the response body should contain "/Count 1 >>"
+    And the response body should contain "(https://example.org) Tj"
+    And the response body should contain "(test_custom_a) Tj"
+    And the response body should contain "(test_custom_b) Tj"
+    And the response body should contain "(test_custom_d) Tj"
+    And the response body should contain "(Friends) Tj"
+    And the response body should contain "(Club) Tj"
+    And the response body should contain "(Group code:) Tj"
+    And the response body should contain "(3456789abc) Tj"
@@ -0,0 +1,113 @@
+package groups
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/credentialsheet"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/domain"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:operation GET /user-batches/{group_prefix}/{custom_prefix}/sheet groups userBatchSheetGet
+//
+//	---
+//	summary: Get a credential sheet of a user batch
+//	description: >
+//
+//		Returns a printable PDF document with a card for each user having "{group_prefix}_{custom_prefix}_"
+//		as login prefix (ordered by login). The cards are laid out on A4 pages (10 cards per page)
+//		with dashed cutting lines.
+//
+//
+//		Each card contains the URL of the platform (the `platformURL` of the domain configuration),
+//		the login of the user, and the name & the code of the group the user has been added into
+//		(the first of the groups the user is a member of among the descendants of the `group_prefix`'s group,
+//		including the group itself).
+//		As passwords are not stored, a blank line is left for writing the password by hand
+//		(the passwords are only printed on the sheet returned on the creation of the batch
+//		by `POST /user-batches?sheet=1`).
+//
+//
+//		The authenticated user should be a manager of the `group_prefix`'s group (or its ancestor)
+//		with `can_manage` >= 'memberships', otherwise the 'forbidden' error is returned.
+//	parameters:
+//		- name: group_prefix
+//			in: path
+//			type: string
+//			required: true
+//		- name: custom_prefix
+//			in: path
+//			type: string
+//			required: true
+//	produces:
+//		- application/pdf
+//	responses:
+//		"200":
+//			description: OK. The PDF document
+//			schema:
+//				type: string
+//				format: binary
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getUserBatchSheet(w http.ResponseWriter, r *http.Request) service.APIError {
+	groupPrefix := chi.URLParam(r, "group_prefix")
+	customPrefix := chi.URLParam(r, "custom_prefix")
+
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	// The user batch should exist and the current user should be a manager of the group
+	// linked to the group_prefix
+	var prefixGroupID int64
+	err := store.UserBatches().
+		Joins("JOIN user_batch_prefixes USING(group_prefix)").
+		Joins("JOIN ? AS managed_groups ON managed_groups.id = user_batch_prefixes.group_id",
+			store.ActiveGroupAncestors().ManagedByUser(user).
+				Where("can_manage != 'none'").
+				Select("groups_ancestors_active.child_group_id AS id").SubQuery()).
+		Where("group_prefix = ?", groupPrefix).
+		Where("custom_prefix = ?", customPrefix).
+		PluckFirst("user_batch_prefixes.group_id", &prefixGroupID).Error()
+	if gorm.IsRecordNotFoundError(err) {
+		return service.InsufficientAccessRightsError
+	}
+	service.MustNotBeError(err)
+
+	var cards []credentialsheet.Card
+	service.MustNotBeError(store.Users().
+		Joins("LEFT JOIN `groups` ON groups.id = ?", store.ActiveGroupGroups().
+			Joins(`
+				JOIN groups_ancestors_active AS ancestors
+					ON ancestors.child_group_id = groups_groups_active.parent_group_id AND
+						ancestors.ancestor_group_id = ?`, prefixGroupID).
+			Where("groups_groups_active.child_group_id = users.group_id").
+			Select("MIN(groups_groups_active.parent_group_id)").SubQuery()).
+		Where("login LIKE CONCAT(?, '\\_', ?, '\\_%')", groupPrefix, customPrefix).
+		Order("login").
+		Select("login, IFNULL(groups.name, '') AS group_name, IFNULL(groups.code, '') AS group_code").
+		Scan(&cards).Error())
+
+	writeCredentialSheet(w, r, http.StatusOK, groupPrefix, customPrefix, cards)
+	return service.NoError
+}
+
+func writeCredentialSheet(w http.ResponseWriter, r *http.Request, statusCode int, groupPrefix, customPrefix string,
+	cards []credentialsheet.Card,
+) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment",
+		map[string]string{"filename": fmt.Sprintf("user_batch_%s_%s.pdf", groupPrefix, customPrefix)}))
+	w.WriteHeader(statusCode)
+	service.MustNotBeError(credentialsheet.Write(w, domain.ConfigFromContext(r.Context()).PlatformURL, cards))
+}
@@ -0,0 +1,40 @@
+Feature: Get a credential sheet of a user batch (userBatchSheetGet) - robustness
+  Background:
+    Given the database has the following table "groups":
+      | id | name  | type  |
+      | 13 | class | Class |
+    And the database has the following users:
+      | group_id | login            |
+      | 21       | owner            |
+      | 22       | test_custom_user |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_manage  |
+      | 13       | 21         | memberships |
+      | 13       | 22         | none        |
+    And the groups ancestors are computed
+    And the database has the following table "user_batch_prefixes":
+      | group_prefix | group_id | allow_new |
+      | test         | 13       | 1         |
+      | test1        | 21       | 1         |
+    And the database has the following table "user_batches_v2":
+      | group_prefix | custom_prefix | size | creator_id |
+      | test         | custom        | 100  | null       |
+      | test1        | custom        | 100  | null       |
+
+  Scenario: The user batch doesn't exist
+    Given I am the user with id "21"
+    When I send a GET request to "/user-batches/test/unknown/sheet"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The user is not a manager of the prefix group
+    Given I am the user with id "21"
+    When I send a GET request to "/user-batches/test1/custom/sheet"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The user cannot manage memberships of the prefix group
+    Given I am the user with id "22"
+    When I send a GET request to "/user-batches/test/custom/sheet"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
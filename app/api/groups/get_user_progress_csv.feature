@@ -393,3 +393,21 @@ Feature: Export the current progress of users on a subset of items as CSV (group
     Login;First name;Last name
 
     """
+
+  Scenario: Get progress of users as NDJSON
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/1/user-progress-csv?parent_item_ids=210&format=ndjson"
+    Then the response code should be 200
+    And the response header "Content-Type" should be "application/x-ndjson"
+    And the response header "Content-Disposition" should be "attachment; filename=users_progress_for_group_1_and_child_items_of_210.ndjson"
+    And the response body should be:
+    """
+    {"first_name":"","id":"63","last_name":"","login":"janeb","scores":{"210":null,"211":null,"212":10,"213":null,"214":null,"215":null}}
+    {"first_name":"","id":"65","last_name":"","login":"janec","scores":{"210":null,"211":null,"212":10,"213":null,"214":null,"215":null}}
+    {"first_name":"","id":"67","last_name":"","login":"janed","scores":{"210":null,"211":null,"212":20,"213":0,"214":15,"215":0}}
+    {"first_name":"John","id":"51","last_name":"Adams","login":"johna","scores":{"210":50,"211":50,"212":null,"213":null,"214":null,"215":null}}
+    {"first_name":"John","id":"53","last_name":"Black","login":"johnb","scores":{"210":50,"211":50,"212":null,"213":null,"214":null,"215":null}}
+    {"first_name":"","id":"55","last_name":"","login":"johnc","scores":{"210":50,"211":50,"212":null,"213":null,"214":null,"215":null}}
+    {"first_name":"","id":"59","last_name":"Eliot","login":"johne","scores":{"210":null,"211":0,"212":100,"213":null,"214":null,"215":null}}
+
+    """
@@ -1,7 +1,6 @@
 package groups
 
 import (
-	"encoding/csv"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -42,6 +41,12 @@ const csvExportBatchSize = 500
 //
 //
 //						 otherwise the 'forbidden' error is returned.
+//
+//
+//						 The rows are computed and sent to the client in chunks of participants.
+//						 With `format`=`ndjson`, each row is a JSON object on its own line (there is no header)
+//						 containing the user's `id`, `login`, `first_name`, `last_name` and `scores`, a map of scores by item IDs
+//						 (a score is `null` when there is no result).
 //	parameters:
 //		- name: group_id
 //			in: path
@@ -53,6 +58,11 @@ const csvExportBatchSize = 500
 //			type: array
 //			items:
 //				type: integer
+//		- name: format
+//			in: query
+//			type: string
+//			enum: [csv,ndjson]
+//			default: csv
 //	responses:
 //		"200":
 //			description: OK. Success response with users progress on items
@@ -60,6 +70,9 @@ const csvExportBatchSize = 500
 //				text/csv:
 //					schema:
 //					type: string
+//				application/x-ndjson:
+//					schema:
+//					type: string
 //			examples:
 //				text/csv:
 //					Login;Last name;First name;Parent item;1. First child item;2. Second child item
@@ -84,6 +97,11 @@ func (srv *Service) getUserProgressCSV(w http.ResponseWriter, r *http.Request) s
 		return service.ErrInvalidRequest(err)
 	}
 
+	format, apiError := resolveProgressExportFormat(r)
+	if apiError != service.NoError {
+		return apiError
+	}
+
 	if !user.CanWatchGroupMembers(store, groupID) {
 		return service.InsufficientAccessRightsError
 	}
@@ -93,43 +111,27 @@ func (srv *Service) getUserProgressCSV(w http.ResponseWriter, r *http.Request) s
 		return apiError
 	}
 
-	w.Header().Set("Content-Type", "text/csv")
-	itemParentIDsString := make([]string, len(itemParentIDs))
-	for i, id := range itemParentIDs {
-		itemParentIDsString[i] = strconv.FormatInt(id, 10)
-	}
-	w.Header().Set("Content-Disposition",
-		fmt.Sprintf("attachment; filename=users_progress_for_group_%d_and_child_items_of_%s.csv",
-			groupID, strings.Join(itemParentIDsString, "_")))
+	writer := srv.newProgressExportWriter(w, format, "users", groupID, itemParentIDs)
+	defer writer.close()
 	if len(itemParentIDs) == 0 {
-		_, err := w.Write([]byte("Login;First name;Last name\n"))
-		service.MustNotBeError(err)
+		writer.writeHeader([]string{"Login", "First name", "Last name"})
 		return service.NoError
 	}
 
 	// Preselect item IDs since we need them to build the results table (there shouldn't be many)
 	orderedItemIDListWithDuplicates, uniqueItemIDs, itemOrder, itemsSubQuery := preselectIDsOfVisibleItems(store, itemParentIDs, user)
 
-	csvWriter := csv.NewWriter(w)
-	defer csvWriter.Flush()
-	csvWriter.Comma = ';'
-
-	printTableHeader(store, user, uniqueItemIDs, orderedItemIDListWithDuplicates, itemOrder, csvWriter,
+	printTableHeader(store, user, uniqueItemIDs, orderedItemIDListWithDuplicates, itemOrder, writer,
 		[]string{"Login", "First name", "Last name"})
+	writer.setColumns([]string{"login", "last_name", "first_name"}, orderedItemIDListWithDuplicates)
 
-	// Preselect end member for that we will calculate the stats.
-	var users []struct {
-		ID        int64
-		FirstName string
-		LastName  string
-		Login     string
-	}
-	service.MustNotBeError(store.ActiveGroupAncestors().
+	// End members for that we calculate the stats are loaded chunk by chunk (logins are unique).
+	usersQuery := store.ActiveGroupAncestors().
 		Joins("JOIN groups_groups_active ON groups_groups_active.parent_group_id = groups_ancestors_active.child_group_id").
 		Joins("JOIN `users` ON users.group_id = groups_groups_active.child_group_id").
 		Where("groups_ancestors_active.ancestor_group_id = ?", groupID).
 		Group("users.group_id").
-		Order("login, first_name, last_name, users.group_id").
+		Order("users.login").
 		Select(`
 			users.group_id AS id,
 			users.login,
@@ -137,23 +139,31 @@ func (srv *Service) getUserProgressCSV(w http.ResponseWriter, r *http.Request) s
 			IF(users.group_id = ? OR MAX(personal_info_view_approvals.approved), users.last_name, NULL) AS last_name`,
 			user.GroupID, user.GroupID).
 		WithPersonalInfoViewApprovals(user).
-		Scan(&users).Error())
+		Limit(csvExportBatchSize)
 
-	if len(users) == 0 {
-		return service.NoError
-	}
-	userIDs := make([]string, len(users))
-	for i := range users {
-		userIDs[i] = strconv.FormatInt(users[i].ID, 10)
+	var users []struct {
+		ID        int64
+		FirstName string
+		LastName  string
+		Login     string
 	}
+	for {
+		query := usersQuery
+		if len(users) > 0 {
+			query = query.Where("users.login > ?", users[len(users)-1].Login)
+		}
+		users = nil
+		service.MustNotBeError(query.Scan(&users).Error())
+		if len(users) == 0 {
+			break
+		}
 
-	for startFromUser := 0; startFromUser < len(userIDs); startFromUser += csvExportBatchSize {
-		batchBoundary := startFromUser + csvExportBatchSize
-		if batchBoundary > len(userIDs) {
-			batchBoundary = len(userIDs)
+		userIDs := make([]string, len(users))
+		for i := range users {
+			userIDs[i] = strconv.FormatInt(users[i].ID, 10)
 		}
-		userIDsList := strings.Join(userIDs[startFromUser:batchBoundary], ", ")
-		userNumber := startFromUser
+		userIDsList := strings.Join(userIDs, ", ")
+		userNumber := 0
 		service.MustNotBeError(
 			// nolint:gosec
 			joinUserProgressResultsForCSV(
@@ -168,11 +178,16 @@ func (srv *Service) getUserProgressCSV(w http.ResponseWriter, r *http.Request) s
 				Group("users.group_id, items.id").
 				Order(gorm.Expr("FIELD(users.group_id, " + userIDsList + ")")).
 				ScanAndHandleMaps(
-					processCSVResultRow(
-						orderedItemIDListWithDuplicates, len(uniqueItemIDs), &userNumber,
+					processProgressExportResultRow(
+						len(uniqueItemIDs), &userNumber,
 						func(_ int64) []string {
 							return []string{users[userNumber].Login, users[userNumber].LastName, users[userNumber].FirstName}
-						}, csvWriter)).Error())
+						}, writer)).Error())
+		writer.flushChunk()
+
+		if len(users) < csvExportBatchSize {
+			break
+		}
 	}
 
 	return service.NoError
@@ -180,8 +195,12 @@ func (srv *Service) getUserProgressCSV(w http.ResponseWriter, r *http.Request) s
 
 func printTableHeader(
 	store *database.DataStore, user *database.User, uniqueItemIDs []string, orderedItemIDListWithDuplicates []interface{},
-	itemOrder []int, csvWriter *csv.Writer, firstColumns []string,
+	itemOrder []int, writer *progressExportWriter, firstColumns []string,
 ) {
+	if writer.csvWriter == nil {
+		return
+	}
+
 	var items []struct {
 		ID           int64  `json:"id"`
 		ParentItemID int64  `json:"parent_item_id"`
@@ -205,43 +224,32 @@ func printTableHeader(
 		}
 		itemTitles = append(itemTitles, title)
 	}
-	service.MustNotBeError(csvWriter.Write(itemTitles))
+	writer.writeHeader(itemTitles)
 }
 
-func processCSVResultRow(
-	orderedItemIDListWithDuplicates []interface{},
+func processProgressExportResultRow(
 	uniqueItemsCount int,
 	groupNumber *int,
 	generateGroupNamesFunc func(groupID int64) []string,
-	csvWriter *csv.Writer,
+	writer *progressExportWriter,
 ) func(m map[string]interface{}) error {
-	var rowArray []string
-	var cellsMap map[int64]string
+	var groupNames []string
+	var cellsMap map[int64]interface{}
 	currentRowNumber := 0
 	return func(m map[string]interface{}) error {
-		var score string
-		if m["score"] != nil {
-			score = fmt.Sprintf("%v", m["score"])
-		}
-
 		itemID := m["item_id"].(int64)
 		groupID := m["group_id"].(int64)
 
 		if currentRowNumber%uniqueItemsCount == 0 {
-			groupNames := generateGroupNamesFunc(groupID)
-			rowArray = make([]string, 0, len(orderedItemIDListWithDuplicates)+len(groupNames))
-			cellsMap = make(map[int64]string, len(orderedItemIDListWithDuplicates))
-			rowArray = append(rowArray, groupNames...)
+			groupNames = generateGroupNamesFunc(groupID)
+			cellsMap = make(map[int64]interface{}, uniqueItemsCount)
 			*groupNumber++
 		}
 
-		cellsMap[itemID] = score
+		cellsMap[itemID] = m["score"]
 
 		if currentRowNumber%uniqueItemsCount == uniqueItemsCount-1 {
-			for _, id := range orderedItemIDListWithDuplicates {
-				rowArray = append(rowArray, cellsMap[id.(int64)])
-			}
-			service.MustNotBeError(csvWriter.Write(rowArray))
+			writer.writeRow(groupID, groupNames, cellsMap)
 		}
 		currentRowNumber++
 		return nil
@@ -0,0 +1,90 @@
+Feature: List deliveries of a webhook (webhookDeliveriesView)
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+    And the database has the following table "groups":
+      | id |
+      | 13 |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 21         | true              |
+    And the groups ancestors are computed
+    And the database has the following table "webhooks":
+      | id | group_id | url                        | secret  | event_types                  |
+      | 1  | 13       | https://example.org/hooks1 | secret1 | grade_saved,item_unlocked    |
+      | 2  | 13       | https://example.org/hooks2 | secret2 | grade_saved                  |
+    And the database has the following table "webhook_deliveries":
+      | id | webhook_id | event_type    | payload                                                        | created_at          | status    | attempts_count | next_attempt_at     | last_attempt_at     | last_response_code | last_error                     |
+      | 1  | 1          | grade_saved   | {"participant_id": "14", "attempt_id": "0", "item_id": "210"}  | 2025-02-18 10:00:00 | delivered | 1              | 2025-02-18 10:00:00 | 2025-02-18 10:00:01 | 200                | null                           |
+      | 2  | 1          | item_unlocked | {"participant_id": "14", "attempt_id": null, "item_id": "211"} | 2025-02-18 10:00:00 | pending   | 2              | 2025-02-18 10:01:00 | 2025-02-18 10:00:31 | 503                | unexpected status code: 503    |
+      | 3  | 1          | grade_saved   | {"participant_id": "15", "attempt_id": "0", "item_id": "210"}  | 2025-02-18 11:00:00 | failed    | 10             | 2025-02-18 12:00:00 | 2025-02-18 12:00:00 | null               | connection refused             |
+      | 4  | 2          | grade_saved   | {"participant_id": "15", "attempt_id": "0", "item_id": "210"}  | 2025-02-18 11:00:00 | pending   | 0              | 2025-02-18 11:00:00 | null                | null               | null                           |
+
+  Scenario: List deliveries of the webhook (the newest first)
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/13/webhooks/1/deliveries"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "id": "3",
+        "event_type": "grade_saved",
+        "payload": {"participant_id": "15", "attempt_id": "0", "item_id": "210"},
+        "created_at": "2025-02-18T11:00:00Z",
+        "status": "failed",
+        "attempts_count": 10,
+        "next_attempt_at": null,
+        "last_attempt_at": "2025-02-18T12:00:00Z",
+        "last_response_code": null,
+        "last_error": "connection refused"
+      },
+      {
+        "id": "2",
+        "event_type": "item_unlocked",
+        "payload": {"participant_id": "14", "attempt_id": null, "item_id": "211"},
+        "created_at": "2025-02-18T10:00:00Z",
+        "status": "pending",
+        "attempts_count": 2,
+        "next_attempt_at": "2025-02-18T10:01:00Z",
+        "last_attempt_at": "2025-02-18T10:00:31Z",
+        "last_response_code": 503,
+        "last_error": "unexpected status code: 503"
+      },
+      {
+        "id": "1",
+        "event_type": "grade_saved",
+        "payload": {"participant_id": "14", "attempt_id": "0", "item_id": "210"},
+        "created_at": "2025-02-18T10:00:00Z",
+        "status": "delivered",
+        "attempts_count": 1,
+        "next_attempt_at": null,
+        "last_attempt_at": "2025-02-18T10:00:01Z",
+        "last_response_code": 200,
+        "last_error": null
+      }
+    ]
+    """
+
+  Scenario: List pending deliveries only
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/13/webhooks/1/deliveries?status=pending"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "id": "2",
+        "event_type": "item_unlocked",
+        "payload": {"participant_id": "14", "attempt_id": null, "item_id": "211"},
+        "created_at": "2025-02-18T10:00:00Z",
+        "status": "pending",
+        "attempts_count": 2,
+        "next_attempt_at": "2025-02-18T10:01:00Z",
+        "last_attempt_at": "2025-02-18T10:00:31Z",
+        "last_response_code": 503,
+        "last_error": "unexpected status code: 503"
+      }
+    ]
+    """
@@ -0,0 +1,171 @@
+package groups
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:model webhookDeliveryViewResponseRow
+type webhookDeliveryViewResponseRow struct {
+	// required: true
+	ID int64 `json:"id,string"`
+	// required: true
+	// enum: grade_saved,result_validated,item_unlocked,group_membership_changed
+	EventType string `json:"event_type"`
+	// The data of the event (as sent in the `data` field of the delivery)
+	// required: true
+	Payload json.RawMessage `json:"payload"`
+	// Time of the event
+	// required: true
+	CreatedAt database.Time `json:"created_at"`
+	// 'failed' means that the delivery has been given up after too many failed attempts
+	// required: true
+	// enum: pending,delivered,failed
+	Status string `json:"status"`
+	// required: true
+	AttemptsCount int32 `json:"attempts_count"`
+	// Only for pending deliveries
+	// required: true
+	NextAttemptAt *database.Time `json:"next_attempt_at"`
+	// required: true
+	LastAttemptAt *database.Time `json:"last_attempt_at"`
+	// HTTP status code of the response to the latest attempt (`null` if there was no response)
+	// required: true
+	LastResponseCode *int32 `json:"last_response_code"`
+	// Error of the latest attempt (`null` if it has succeeded)
+	// required: true
+	LastError *string `json:"last_error"`
+
+	PayloadJSON string `json:"-"`
+}
+
+// swagger:operation GET /groups/{group_id}/webhooks/{webhook_id}/deliveries groups webhookDeliveriesView
+//
+//	---
+//	summary: List deliveries of a webhook
+//	description: >
+//
+//		Lists the deliveries of the webhook (pending, delivered and failed ones) with the status of their latest attempt.
+//
+//
+//		The current user should be a manager of the group with `can_watch_members`
+//		and the webhook should belong to the group, otherwise the 'forbidden' error is returned.
+//	parameters:
+//		- name: group_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: webhook_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: status
+//			description: If given, only deliveries with this status are listed
+//			in: query
+//			type: string
+//			enum: [pending,delivered,failed]
+//		- name: sort
+//			in: query
+//			default: [-created_at,-id]
+//			type: array
+//			items:
+//				type: string
+//				enum: [created_at,-created_at,id,-id]
+//		- name: from.id
+//			description: Start the page from the delivery next to the delivery with `id`=`{from.id}`
+//			in: query
+//			type: integer
+//			format: int64
+//		- name: limit
+//			description: Display the first N deliveries
+//			in: query
+//			type: integer
+//			maximum: 1000
+//			default: 500
+//	responses:
+//		"200":
+//			description: OK. The array of deliveries
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/webhookDeliveryViewResponseRow"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getWebhookDeliveries(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	groupID, err := service.ResolveURLQueryPathInt64Field(r, "group_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+	webhookID, err := service.ResolveURLQueryPathInt64Field(r, "webhook_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	if !user.CanWatchGroupMembers(store, groupID) {
+		return service.InsufficientAccessRightsError
+	}
+	found, err := store.Webhooks().Where("id = ? AND group_id = ?", webhookID, groupID).HasRows()
+	service.MustNotBeError(err)
+	if !found {
+		return service.InsufficientAccessRightsError
+	}
+
+	query := store.WebhookDeliveries().
+		Where("webhook_deliveries.webhook_id = ?", webhookID).
+		Select(`
+			webhook_deliveries.id, webhook_deliveries.event_type, CAST(webhook_deliveries.payload AS CHAR) AS payload_json,
+			webhook_deliveries.created_at, webhook_deliveries.status, webhook_deliveries.attempts_count,
+			IF(webhook_deliveries.status = 'pending', webhook_deliveries.next_attempt_at, NULL) AS next_attempt_at,
+			webhook_deliveries.last_attempt_at, webhook_deliveries.last_response_code, webhook_deliveries.last_error`)
+
+	if len(r.URL.Query()["status"]) > 0 {
+		status := r.URL.Query().Get("status")
+		if status != "pending" && status != "delivered" && status != "failed" {
+			return service.ErrInvalidRequest(errors.New("wrong value for status (should be one of pending, delivered, failed)"))
+		}
+		query = query.Where("webhook_deliveries.status = ?", status)
+	}
+
+	query = service.NewQueryLimiter().Apply(r, query)
+	query, apiError := service.ApplySortingAndPaging(
+		r, query,
+		&service.SortingAndPagingParameters{
+			Fields: service.SortingAndPagingFields{
+				"created_at": {ColumnName: "webhook_deliveries.created_at"},
+				"id":         {ColumnName: "webhook_deliveries.id"},
+			},
+			DefaultRules: "-created_at,-id",
+			TieBreakers:  service.SortingAndPagingTieBreakers{"id": service.FieldTypeInt64},
+		})
+	if apiError != service.NoError {
+		return apiError
+	}
+
+	var result []webhookDeliveryViewResponseRow
+	service.MustNotBeError(query.Scan(&result).Error())
+	for index := range result {
+		result[index].Payload = json.RawMessage(result[index].PayloadJSON)
+	}
+
+	render.Respond(w, r, result)
+	return service.NoError
+}
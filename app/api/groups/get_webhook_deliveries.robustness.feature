@@ -0,0 +1,56 @@
+Feature: List deliveries of a webhook (webhookDeliveriesView) - robustness
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+      | user  | 11       |
+    And the database has the following table "groups":
+      | id |
+      | 13 |
+      | 14 |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 11         | false             |
+      | 13       | 21         | true              |
+      | 14       | 21         | true              |
+    And the groups ancestors are computed
+    And the database has the following table "webhooks":
+      | id | group_id | url                        | secret  | event_types |
+      | 1  | 13       | https://example.org/hooks1 | secret1 | grade_saved |
+      | 2  | 14       | https://example.org/hooks2 | secret2 | grade_saved |
+
+  Scenario: Invalid group_id
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/abc/webhooks/1/deliveries"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for group_id (should be int64)"
+
+  Scenario: Invalid webhook_id
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/13/webhooks/abc/deliveries"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for webhook_id (should be int64)"
+
+  Scenario: Invalid status
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/13/webhooks/1/deliveries?status=unknown"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for status (should be one of pending, delivered, failed)"
+
+  Scenario: The user cannot watch members of the group
+    Given I am the user with id "11"
+    When I send a GET request to "/groups/13/webhooks/1/deliveries"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The webhook belongs to another group
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/13/webhooks/2/deliveries"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: Wrong sorting
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/13/webhooks/1/deliveries?sort=status"
+    Then the response code should be 400
+    And the response error message should contain "Unallowed field in sorting parameters: "status""
@@ -0,0 +1,79 @@
+Feature: List webhooks of a group (webhooksView)
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+    And the database has the following table "groups":
+      | id |
+      | 13 |
+      | 14 |
+      | 15 |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 14             |
+      | 13              | 15             |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 21         | true              |
+    And the groups ancestors are computed
+    And the database has the following table "items":
+      | id  | type    | default_language_tag |
+      | 210 | Chapter | fr                   |
+    And the database has the following table "webhooks":
+      | id | group_id | item_id | url                        | secret  | event_types                              | creator_id | created_at          |
+      | 1  | 13       | null    | https://example.org/hooks1 | secret1 | grade_saved,group_membership_changed     | 21         | 2025-02-18 10:00:00 |
+      | 2  | 13       | 210     | https://example.org/hooks2 | secret2 | result_validated                         | null       | 2025-02-18 11:00:00 |
+      | 3  | 14       | null    | https://example.org/hooks3 | secret3 | item_unlocked                            | 21         | 2025-02-18 12:00:00 |
+
+  Scenario: List webhooks of the group (the newest first)
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/13/webhooks"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "id": "2",
+        "item_id": "210",
+        "url": "https://example.org/hooks2",
+        "event_types": ["result_validated"],
+        "creator_id": null,
+        "created_at": "2025-02-18T11:00:00Z"
+      },
+      {
+        "id": "1",
+        "item_id": null,
+        "url": "https://example.org/hooks1",
+        "event_types": ["grade_saved", "group_membership_changed"],
+        "creator_id": "21",
+        "created_at": "2025-02-18T10:00:00Z"
+      }
+    ]
+    """
+
+  Scenario: List webhooks of a descendant group with paging
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/14/webhooks?limit=1"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "id": "3",
+        "item_id": null,
+        "url": "https://example.org/hooks3",
+        "event_types": ["item_unlocked"],
+        "creator_id": "21",
+        "created_at": "2025-02-18T12:00:00Z"
+      }
+    ]
+    """
+
+  Scenario: No webhooks
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/15/webhooks"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    []
+    """
@@ -0,0 +1,126 @@
+package groups
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:model webhookViewResponseRow
+type webhookViewResponseRow struct {
+	// required: true
+	ID int64 `json:"id,string"`
+	// `null` if the webhook is not restricted to an item subtree
+	// required: true
+	ItemID *int64 `json:"item_id,string"`
+	// required: true
+	URL string `json:"url"`
+	// required: true
+	// items:
+	//   enum: grade_saved,result_validated,item_unlocked,group_membership_changed
+	EventTypes []string `json:"event_types" gorm:"-"`
+	// `null` if the creator has been deleted
+	// required: true
+	CreatorID *int64 `json:"creator_id,string"`
+	// required: true
+	CreatedAt database.Time `json:"created_at"`
+
+	EventTypesList string `json:"-"`
+}
+
+// swagger:operation GET /groups/{group_id}/webhooks groups webhooksView
+//
+//	---
+//	summary: List webhooks of a group
+//	description: >
+//
+//		Lists the webhooks registered for the given group (the secrets are not returned).
+//
+//
+//		The current user should be a manager of the group with `can_watch_members`,
+//		otherwise the 'forbidden' error is returned.
+//	parameters:
+//		- name: group_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: sort
+//			in: query
+//			default: [-created_at,id]
+//			type: array
+//			items:
+//				type: string
+//				enum: [created_at,-created_at,id,-id]
+//		- name: from.id
+//			description: Start the page from the webhook next to the webhook with `id`=`{from.id}`
+//			in: query
+//			type: integer
+//			format: int64
+//		- name: limit
+//			description: Display the first N webhooks
+//			in: query
+//			type: integer
+//			maximum: 1000
+//			default: 500
+//	responses:
+//		"200":
+//			description: OK. The array of webhooks
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/webhookViewResponseRow"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getWebhooks(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	groupID, err := service.ResolveURLQueryPathInt64Field(r, "group_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	if !user.CanWatchGroupMembers(store, groupID) {
+		return service.InsufficientAccessRightsError
+	}
+
+	query := store.Webhooks().
+		Where("webhooks.group_id = ?", groupID).
+		Select("webhooks.id, webhooks.item_id, webhooks.url, webhooks.event_types AS event_types_list, webhooks.creator_id, webhooks.created_at")
+	query = service.NewQueryLimiter().Apply(r, query)
+	query, apiError := service.ApplySortingAndPaging(
+		r, query,
+		&service.SortingAndPagingParameters{
+			Fields: service.SortingAndPagingFields{
+				"created_at": {ColumnName: "webhooks.created_at"},
+				"id":         {ColumnName: "webhooks.id"},
+			},
+			DefaultRules: "-created_at,id",
+			TieBreakers:  service.SortingAndPagingTieBreakers{"id": service.FieldTypeInt64},
+		})
+	if apiError != service.NoError {
+		return apiError
+	}
+
+	var result []webhookViewResponseRow
+	service.MustNotBeError(query.Scan(&result).Error())
+	for index := range result {
+		result[index].EventTypes = strings.Split(result[index].EventTypesList, ",")
+	}
+
+	render.Respond(w, r, result)
+	return service.NoError
+}
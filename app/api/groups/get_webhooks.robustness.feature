@@ -0,0 +1,32 @@
+Feature: List webhooks of a group (webhooksView) - robustness
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+      | user  | 11       |
+    And the database has the following table "groups":
+      | id |
+      | 13 |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 11         | false             |
+      | 13       | 21         | true              |
+    And the groups ancestors are computed
+
+  Scenario: Invalid group_id
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/abc/webhooks"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for group_id (should be int64)"
+
+  Scenario: The user cannot watch members of the group
+    Given I am the user with id "11"
+    When I send a GET request to "/groups/13/webhooks"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: Wrong sorting
+    Given I am the user with id "21"
+    When I send a GET request to "/groups/13/webhooks?sort=url"
+    Then the response code should be 400
+    And the response error message should contain "Unallowed field in sorting parameters: "url""
@@ -35,10 +35,13 @@ func (srv *Service) SetRoutes(router chi.Router) {
 		service.AppHandler(srv.getGrantedPermissions).ServeHTTP)
 	router.Put("/groups/{source_group_id}/permissions/{group_id}/{item_id}",
 		service.AppHandler(srv.updatePermissions).ServeHTTP)
+	router.Get("/groups/{group_id}/permissions/{item_id}/explain",
+		service.AppHandler(srv.explainPermissions).ServeHTTP)
 
 	router.Post("/groups/{group_id}/code", service.AppHandler(srv.createCode).ServeHTTP)
 	router.Delete("/groups/{group_id}/code", service.AppHandler(srv.removeCode).ServeHTTP)
-	router.Get("/groups/is-code-valid", service.AppHandler(srv.checkCode).ServeHTTP)
+	router.With(srv.RateLimitMiddleware("code-check")).
+		Get("/groups/is-code-valid", service.AppHandler(srv.checkCode).ServeHTTP)
 
 	router.Get("/groups/{group_id}/navigation", service.AppHandler(srv.getNavigation).ServeHTTP)
 	router.Get("/groups/{group_id}/path-from-root", service.AppHandler(srv.getPathFromRoot).ServeHTTP)
@@ -73,15 +76,23 @@ func (srv *Service) SetRoutes(router chi.Router) {
 
 	router.Post("/groups/{parent_group_id}/invitations", service.AppHandler(srv.createGroupInvitations).ServeHTTP)
 	router.Post("/groups/{parent_group_id}/invitations/withdraw", service.AppHandler(srv.withdrawInvitations).ServeHTTP)
+	router.Post("/groups/{parent_group_id}/roster-imports", service.AppHandler(srv.importRoster).ServeHTTP)
 
 	router.Post("/groups/{parent_group_id}/relations/{child_group_id}", service.AppHandler(srv.addChild).ServeHTTP)
 	router.Delete("/groups/{parent_group_id}/relations/{child_group_id}", service.AppHandler(srv.removeChild).ServeHTTP)
+	router.Post("/groups/relations", service.AppHandler(srv.changeRelations).ServeHTTP)
 
 	router.Get("/current-user/teams/by-item/{item_id}", service.AppHandler(srv.getCurrentUserTeamByItem).ServeHTTP)
 	router.Post("/user-batches", service.AppHandler(srv.createUserBatch).ServeHTTP)
 	router.Get("/user-batches/by-group/{group_id}", service.AppHandler(srv.getUserBatches).ServeHTTP)
 	router.Delete("/user-batches/{group_prefix}/{custom_prefix}", service.AppHandler(srv.removeUserBatch).ServeHTTP)
+	router.Get("/user-batches/{group_prefix}/{custom_prefix}/sheet", service.AppHandler(srv.getUserBatchSheet).ServeHTTP)
 	router.Get("/groups/{group_id}/user-batch-prefixes", service.AppHandler(srv.getUserBatchPrefixes).ServeHTTP)
+
+	router.Post("/groups/{group_id}/webhooks", service.AppHandler(srv.createWebhook).ServeHTTP)
+	router.Get("/groups/{group_id}/webhooks", service.AppHandler(srv.getWebhooks).ServeHTTP)
+	router.Delete("/groups/{group_id}/webhooks/{webhook_id}", service.AppHandler(srv.removeWebhook).ServeHTTP)
+	router.Get("/groups/{group_id}/webhooks/{webhook_id}/deliveries", service.AppHandler(srv.getWebhookDeliveries).ServeHTTP)
 }
 
 func checkThatUserCanManageTheGroup(store *database.DataStore, user *database.User, groupID int64) service.APIError {
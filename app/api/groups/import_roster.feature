@@ -0,0 +1,265 @@
+Feature: Import a class roster
+  Background:
+    Given the database has the following table "groups":
+      | id | type  | name     | require_personal_info_access_approval | require_lock_membership_approval_until | require_watch_approval |
+      | 2  | Base  | AllUsers | none                                  | null                                   | 0                      |
+      | 3  | Club  | Club     | view                                  | 3030-01-01 00:00:00                    | 1                      |
+      | 4  | Class | 6A       | none                                  | null                                   | 0                      |
+    And the database has the following users:
+      | group_id | login | temp_user | first_name  | last_name | email            | default_language |
+      | 21       | owner | 0         | Jean-Michel | Blanquer  | null             | en               |
+      | 31       | john  | 0         | John        | Doe       | null             | en               |
+      | 32       | jane  | 0         | Jane        | Roe       | jane@example.com | en               |
+      | 33       | tmp   | 1         | Paul        | Poe       | paul@example.com | en               |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_manage  |
+      | 3        | 21         | memberships |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 2               | 21             |
+      | 2               | 31             |
+      | 2               | 32             |
+      | 3               | 4              |
+      | 3               | 32             |
+    And the groups ancestors are computed
+    And the database has the following table "user_batch_prefixes":
+      | group_prefix | group_id | allow_new | max_users |
+      | test         | 3        | 1         | 10        |
+    And the application config is:
+      """
+      auth:
+        loginModuleURL: "https://login.algorea.org"
+        clientID: "1"
+        clientSecret: "tzxsLyFtJiGnmD6sjZMqSEidVpVsL3hEoSxIXCpI"
+      domains:
+        -
+          domains: [127.0.0.1]
+          allUsersGroup: 2
+      """
+
+  Scenario: Import a CSV roster
+    Given the DB time now is "2019-07-16 22:02:28"
+    And the login module "create" endpoint with params "amount=2&language=en&login_fixed=1&password_length=8&postfix_length=5&prefix=test_custom_" returns 200 with encoded body:
+      """
+      {
+        "success": true,
+        "data": [
+          {"id":100000029,"login":"test_custom_jzk2a","password":"fy52ka3b"},
+          {"id":100000030,"login":"test_custom_ctc8x","password":"aa3k7i9z"}
+        ]
+      }
+      """
+    And I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {
+        "format": "csv",
+        "roster": "first_name,last_name,email,class,login\nJohn,Doe,,6A,john\nJane,Roe,jane@example.com,6B,\nPaul,Poe,paul@example.com,,\nAnna,,anna@example.com,6A,\nJane,Roe,JANE@example.com,6A,\nMark,Moe,,6B,\n",
+        "group_prefix": "test",
+        "custom_prefix": "custom"
+      }
+      """
+    Then the response code should be 201
+    And the response body should be, in JSON:
+      """
+      {
+        "success": true,
+        "message": "created",
+        "data": [
+          {
+            "row": 1, "first_name": "John", "last_name": "Doe", "email": "", "class": "6A", "status": "invited",
+            "group_id": "4", "user_id": "31", "login": "john"
+          },
+          {
+            "row": 2, "first_name": "Jane", "last_name": "Roe", "email": "jane@example.com", "class": "6B", "status": "invited",
+            "group_id": "5577006791947779410", "user_id": "32", "login": "jane"
+          },
+          {
+            "row": 3, "first_name": "Paul", "last_name": "Poe", "email": "paul@example.com", "class": "", "status": "created",
+            "group_id": "3", "user_id": "8674665223082153551", "login": "test_custom_jzk2a", "password": "fy52ka3b"
+          },
+          {
+            "row": 4, "first_name": "Anna", "last_name": "", "email": "anna@example.com", "class": "6A", "status": "missing_name",
+            "group_id": null, "user_id": null, "login": null
+          },
+          {
+            "row": 5, "first_name": "Jane", "last_name": "Roe", "email": "JANE@example.com", "class": "6A", "status": "duplicate",
+            "group_id": null, "user_id": null, "login": null
+          },
+          {
+            "row": 6, "first_name": "Mark", "last_name": "Moe", "email": "", "class": "6B", "status": "created",
+            "group_id": "5577006791947779410", "user_id": "6129484611666145821", "login": "test_custom_ctc8x", "password": "aa3k7i9z"
+          }
+        ]
+      }
+      """
+    And the table "user_batches_v2" should be:
+      | group_prefix | custom_prefix | size | creator_id | created_at          |
+      | test         | custom        | 2    | 21         | 2019-07-16 22:02:28 |
+    And the table "users" should be:
+      | group_id            | temp_user | registered_at       | login_id  | login             | default_language | email            | first_name  | last_name | creator_id |
+      | 21                  | 0         | null                | null      | owner             | en               | null             | Jean-Michel | Blanquer  | null       |
+      | 31                  | 0         | null                | null      | john              | en               | null             | John        | Doe       | null       |
+      | 32                  | 0         | null                | null      | jane              | en               | jane@example.com | Jane        | Roe       | null       |
+      | 33                  | 1         | null                | null      | tmp               | en               | paul@example.com | Paul        | Poe       | null       |
+      | 6129484611666145821 | 0         | 2019-07-16 22:02:28 | 100000030 | test_custom_ctc8x | en               | null             | Mark        | Moe       | 21         |
+      | 8674665223082153551 | 0         | 2019-07-16 22:02:28 | 100000029 | test_custom_jzk2a | en               | paul@example.com | Paul        | Poe       | 21         |
+    And the table "groups" should be:
+      | id                  | name              | type  |
+      | 2                   | AllUsers          | Base  |
+      | 3                   | Club              | Club  |
+      | 4                   | 6A                | Class |
+      | 21                  | owner             | User  |
+      | 31                  | john              | User  |
+      | 32                  | jane              | User  |
+      | 33                  | tmp               | User  |
+      | 5577006791947779410 | 6B                | Class |
+      | 6129484611666145821 | test_custom_ctc8x | User  |
+      | 8674665223082153551 | test_custom_jzk2a | User  |
+    And the table "groups_groups" should be:
+      | parent_group_id     | child_group_id      | personal_info_view_approved_at | lock_membership_approved_at | watch_approved_at   |
+      | 2                   | 21                  | null                           | null                        | null                |
+      | 2                   | 31                  | null                           | null                        | null                |
+      | 2                   | 32                  | null                           | null                        | null                |
+      | 2                   | 6129484611666145821 | null                           | null                        | null                |
+      | 2                   | 8674665223082153551 | null                           | null                        | null                |
+      | 3                   | 4                   | null                           | null                        | null                |
+      | 3                   | 32                  | null                           | null                        | null                |
+      | 3                   | 5577006791947779410 | null                           | null                        | null                |
+      | 3                   | 8674665223082153551 | 2019-07-16 22:02:28            | 2019-07-16 22:02:28         | 2019-07-16 22:02:28 |
+      | 5577006791947779410 | 6129484611666145821 | null                           | null                        | null                |
+    And the table "group_pending_requests" should be:
+      | group_id            | member_id | type       | ABS(TIMESTAMPDIFF(SECOND, at, NOW())) < 3 |
+      | 4                   | 31        | invitation | 1                                         |
+      | 5577006791947779410 | 32        | invitation | 1                                         |
+    And the table "group_membership_changes" should be:
+      | group_id            | member_id | action             | initiator_id | ABS(TIMESTAMPDIFF(SECOND, at, NOW())) < 3 |
+      | 4                   | 31        | invitation_created | 21           | 1                                         |
+      | 5577006791947779410 | 32        | invitation_created | 21           | 1                                         |
+    And the table "attempts" should be:
+      | participant_id      | id | creator_id          | parent_attempt_id | root_item_id |
+      | 6129484611666145821 | 0  | 6129484611666145821 | null              | null         |
+      | 8674665223082153551 | 0  | 8674665223082153551 | null              | null         |
+
+  Scenario: Import a CSV roster in the dry-run mode
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports?dry_run=1" with the following body:
+      """
+      {
+        "format": "csv",
+        "roster": "first_name,last_name,email,class,login\nJohn,Doe,,6A,john\nJane,Roe,jane@example.com,6B,\nPaul,Poe,paul@example.com,,\n",
+        "group_prefix": "test",
+        "custom_prefix": "custom"
+      }
+      """
+    Then the response code should be 200
+    And the response body should be, in JSON:
+      """
+      {
+        "success": true,
+        "message": "dry run",
+        "data": [
+          {
+            "row": 1, "first_name": "John", "last_name": "Doe", "email": "", "class": "6A", "status": "invited",
+            "group_id": "4", "user_id": "31", "login": "john"
+          },
+          {
+            "row": 2, "first_name": "Jane", "last_name": "Roe", "email": "jane@example.com", "class": "6B", "status": "invited",
+            "group_id": null, "user_id": "32", "login": "jane"
+          },
+          {
+            "row": 3, "first_name": "Paul", "last_name": "Poe", "email": "paul@example.com", "class": "", "status": "created",
+            "group_id": "3", "user_id": null, "login": null
+          }
+        ]
+      }
+      """
+    And the table "user_batches_v2" should be empty
+    And the table "users" should stay unchanged
+    And the table "groups" should stay unchanged
+    And the table "groups_groups" should stay unchanged
+    And the table "group_pending_requests" should be empty
+    And the table "group_membership_changes" should be empty
+
+  Scenario: Users not managed by the current user are not found by email in the dry-run mode
+    Given I am the user with id "21"
+    And the database also has the following users:
+      | group_id | login | temp_user | first_name | last_name | email            | default_language |
+      | 34       | lisa  | 0         | Lisa       | Loe       | lisa@example.com | en               |
+      | 35       | mike  | 0         | Mike       | Moe       | same@example.com | en               |
+      | 36       | nina  | 0         | Nina       | Noe       | same@example.com | en               |
+    And the groups ancestors are computed
+    When I send a POST request to "/groups/3/roster-imports?dry_run=1" with the following body:
+      """
+      {
+        "format": "csv",
+        "roster": "first_name,last_name,email\nLisa,Loe,lisa@example.com\nMike,Moe,same@example.com\nOlga,Oak,olga@example.com\n",
+        "group_prefix": "test",
+        "custom_prefix": "custom"
+      }
+      """
+    Then the response code should be 200
+    And the response body should be, in JSON:
+      """
+      {
+        "success": true,
+        "message": "dry run",
+        "data": [
+          {
+            "row": 1, "first_name": "Lisa", "last_name": "Loe", "email": "lisa@example.com", "class": "", "status": "created",
+            "group_id": "3", "user_id": null, "login": null
+          },
+          {
+            "row": 2, "first_name": "Mike", "last_name": "Moe", "email": "same@example.com", "class": "", "status": "created",
+            "group_id": "3", "user_id": null, "login": null
+          },
+          {
+            "row": 3, "first_name": "Olga", "last_name": "Oak", "email": "olga@example.com", "class": "", "status": "created",
+            "group_id": "3", "user_id": null, "login": null
+          }
+        ]
+      }
+      """
+    And the table "users" should stay unchanged
+    And the table "groups_groups" should stay unchanged
+    And the table "group_pending_requests" should be empty
+
+  Scenario: Import a OneRoster document with already invited and member users
+    Given I am the user with id "21"
+    And the database table "groups_groups" also has the following row:
+      | parent_group_id | child_group_id |
+      | 4               | 31             |
+    And the groups ancestors are computed
+    And the database has the following table "group_pending_requests":
+      | group_id | member_id | type       | at                  |
+      | 4        | 32        | invitation | 2019-05-30 11:00:00 |
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {
+        "format": "oneroster",
+        "roster": "{\"users\":[{\"sourcedId\":\"u1\",\"username\":\"john\",\"givenName\":\"John\",\"familyName\":\"Doe\",\"role\":\"student\"},{\"sourcedId\":\"u2\",\"givenName\":\"Jane\",\"familyName\":\"Roe\",\"email\":\"jane@example.com\",\"role\":\"student\"}],\"classes\":[{\"sourcedId\":\"c1\",\"title\":\"6A\"}],\"enrollments\":[{\"user\":{\"sourcedId\":\"u1\"},\"class\":{\"sourcedId\":\"c1\"},\"role\":\"student\"},{\"user\":{\"sourcedId\":\"u2\"},\"class\":{\"sourcedId\":\"c1\"},\"role\":\"student\"}]}"
+      }
+      """
+    Then the response code should be 201
+    And the response body should be, in JSON:
+      """
+      {
+        "success": true,
+        "message": "created",
+        "data": [
+          {
+            "row": 1, "first_name": "John", "last_name": "Doe", "email": "", "class": "6A", "status": "invalid",
+            "group_id": "4", "user_id": "31", "login": "john"
+          },
+          {
+            "row": 2, "first_name": "Jane", "last_name": "Roe", "email": "jane@example.com", "class": "6A", "status": "unchanged",
+            "group_id": "4", "user_id": "32", "login": "jane"
+          }
+        ]
+      }
+      """
+    And the table "user_batches_v2" should be empty
+    And the table "groups" should stay unchanged
+    And the table "groups_groups" should stay unchanged
+    And the table "group_pending_requests" should stay unchanged
+    And the table "group_membership_changes" should be empty
@@ -0,0 +1,791 @@
+package groups
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/France-ioi/validator"
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/domain"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/loginmodule"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+const (
+	maxAllowedRosterRows          = 500
+	defaultRosterPostfixLength    = 5
+	defaultRosterPasswordLength   = 8
+	rosterFormatCSV               = "csv"
+	rosterFormatOneRoster         = "oneroster"
+	rosterClassGroupType          = "Class"
+	rosterRowStatusCreated        = "created"
+	rosterRowStatusInvited        = "invited"
+	rosterRowStatusDuplicate      = "duplicate"
+	rosterRowStatusAmbiguousEmail = "ambiguous"
+	rosterRowStatusMissingName    = "missing_name"
+)
+
+var errRosterImportDryRun = errors.New("dry run")
+
+// swagger:model importRosterRequest
+type importRosterRequest struct {
+	// required: true
+	// enum: csv,oneroster
+	Format string `json:"format" validate:"set,oneof=csv oneroster"`
+	// The CSV file (with a header row) or the OneRoster JSON document
+	// required: true
+	Roster string `json:"roster" validate:"set,min=1"`
+	// Required if some accounts should be created
+	GroupPrefix string `json:"group_prefix"`
+	// Required if some accounts should be created
+	// pattern: ^[a-z0-9-]{2,14}$
+	CustomPrefix string `json:"custom_prefix" validate:"custom_prefix"`
+	//	min: 3
+	//	max: 29
+	//	default: 5
+	PostfixLength int `json:"postfix_length" validate:"omitempty,min=3,max=29"`
+	//	min: 6
+	//	max: 50
+	//	default: 8
+	PasswordLength int `json:"password_length" validate:"omitempty,min=6,max=50"`
+}
+
+type rosterRow struct {
+	FirstName string
+	LastName  string
+	Email     string
+	Class     string
+	Login     string
+}
+
+// swagger:model importRosterResultRow
+type importRosterResultRow struct {
+	// The number of the row in the roster (starting from 1, the CSV header is not counted)
+	// required: true
+	Row int `json:"row"`
+	// required: true
+	FirstName string `json:"first_name"`
+	// required: true
+	LastName string `json:"last_name"`
+	// required: true
+	Email string `json:"email"`
+	// required: true
+	Class string `json:"class"`
+	// required: true
+	// enum: created,invited,unchanged,invalid,full,cycle,duplicate,ambiguous,missing_name
+	Status string `json:"status"`
+	// The group the user is added or invited to
+	// (null if the row is skipped or if the class group would be created by the import in the dry-run mode)
+	// required: true
+	GroupID *int64 `json:"group_id,string"`
+	// Null if the row is skipped or if the user would be created by the import in the dry-run mode
+	// required: true
+	UserID *int64 `json:"user_id,string"`
+	// Null if the row is skipped or if the user would be created by the import in the dry-run mode
+	// required: true
+	Login *string `json:"login"`
+	// Only for created users
+	Password string `json:"password,omitempty"`
+}
+
+// swagger:operation POST /groups/{parent_group_id}/roster-imports group-memberships groupRosterImport
+//
+//	---
+//	summary: Import a class roster
+//	description: >
+//
+//		Imports a roster of students given as a CSV file or as a OneRoster JSON document
+//		into the group `{parent_group_id}`.
+//
+//
+//		The CSV file should have a header row with the `first_name` & `last_name` columns and may have
+//		the `email`, `class` & `login` columns (other columns are ignored).
+//		A OneRoster document should contain the `users`, `classes` & `enrollments` collections:
+//		each enrollment of a student gives a row (`givenName`, `familyName`, `email` & `username` of the user,
+//		`title` of the class), students without enrollments give rows without a class.
+//
+//
+//		For each row, the service
+//
+//		* finds the user by `login` or, if not found, by `email` (temporary users are ignored),
+//			only users being descendants of groups managed by the authenticated user are found by `email`
+//			(other users with the email are treated as not existing),
+//			the row is skipped if there are several such users with the email (status = "ambiguous")
+//			or if the user has already been found for a previous row (status = "duplicate");
+//
+//		* finds the group of the row: the `{parent_group_id}` for rows without a class,
+//			otherwise a child group of `{parent_group_id}` named as the class, which is created
+//			(with type = "Class") if there is no such group;
+//
+//		* invites the found user into the group (status = "invited", or one of "unchanged", "invalid", "full", "cycle"
+//			as described for the `groupInvitationsCreate` service);
+//
+//		* or creates a new user (status = "created") with the row's names & email in the login module
+//			(with logins prefixed by `group_prefix` & `custom_prefix` within a new user batch, like the
+//			`createUserBatch` service does), and adds the user into the group giving all the required approvals.
+//
+//
+//		Rows without a first name or a last name are skipped (status = "missing_name").
+//
+//
+//		If `dry_run` = 1, the service computes the report in a transaction which is rolled back,
+//		without creating users in the login module.
+//
+//
+//		Restrictions:
+//
+//		* The authenticated user should be a manager of the `{parent_group_id}` with `can_manage` >= 'memberships',
+//			otherwise the 'forbidden' error is returned. If the group is a user or a team,
+//			the 'forbidden' error is returned as well.
+//		* The roster should contain at most 500 rows, otherwise the 'bad request' response is returned.
+//		* If some users should be created, `group_prefix` & `custom_prefix` are required and the restrictions of
+//			the `createUserBatch` service apply (with `{parent_group_id}` as the only subgroup).
+//	parameters:
+//		- name: parent_group_id
+//			in: path
+//			type: integer
+//			required: true
+//		- name: dry_run
+//			in: query
+//			type: integer
+//			enum: [0,1]
+//			default: 0
+//		- in: body
+//			name: data
+//			required: true
+//			schema:
+//				"$ref": "#/definitions/importRosterRequest"
+//	responses:
+//		"200":
+//			description: OK. The report of the dry run (only if `dry_run` = 1)
+//			schema:
+//				type: object
+//				required: [success, message, data]
+//				properties:
+//					success:
+//						description: "true"
+//						type: boolean
+//						enum: [true]
+//					message:
+//						type: string
+//						enum: [dry run]
+//					data:
+//						type: array
+//						items:
+//							"$ref": "#/definitions/importRosterResultRow"
+//		"201":
+//			description: Created. The report of the import
+//			schema:
+//				type: object
+//				required: [success, message, data]
+//				properties:
+//					success:
+//						description: "true"
+//						type: boolean
+//						enum: [true]
+//					message:
+//						type: string
+//						enum: [created]
+//					data:
+//						type: array
+//						items:
+//							"$ref": "#/definitions/importRosterResultRow"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) importRoster(w http.ResponseWriter, r *http.Request) service.APIError {
+	parentGroupID, err := service.ResolveURLQueryPathInt64Field(r, "parent_group_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	dryRun, err := service.ResolveURLQueryGetBoolFieldWithDefault(r, "dry_run", false)
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	input := importRosterRequest{}
+	formData := formdata.NewFormData(&input)
+	formData.RegisterValidation("custom_prefix", formData.ValidatorSkippingUnsetFields(func(fl validator.FieldLevel) bool {
+		return customPrefixRegexp.MatchString(fl.Field().Interface().(string))
+	}))
+	formData.RegisterTranslation("custom_prefix",
+		"The custom prefix should only consist of letters/digits/hyphens and be 2-14 characters long")
+	if err = formData.ParseJSONRequestData(r); err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+	if input.PostfixLength == 0 {
+		input.PostfixLength = defaultRosterPostfixLength
+	}
+	if input.PasswordLength == 0 {
+		input.PasswordLength = defaultRosterPasswordLength
+	}
+
+	var rows []rosterRow
+	if input.Format == rosterFormatCSV {
+		rows, err = parseCSVRoster(input.Roster)
+	} else {
+		rows, err = parseOneRoster(input.Roster)
+	}
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+	if len(rows) > maxAllowedRosterRows {
+		return service.ErrInvalidRequest(fmt.Errorf("there should be no more than %d rows", maxAllowedRosterRows))
+	}
+
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+	if apiError := checkThatUserCanManageTheGroupMemberships(store, user, parentGroupID); apiError != service.NoError {
+		return apiError
+	}
+	isTeam, err := store.Groups().ByID(parentGroupID).Where("type = ?", groupTypeTeam).HasRows()
+	service.MustNotBeError(err)
+	if isTeam {
+		return service.InsufficientAccessRightsError
+	}
+
+	result, userGroupIDs := matchRosterRowsWithUsers(store, user, rows)
+
+	numberOfUsersToBeCreated := 0
+	for index := range result {
+		if result[index].Status == rosterRowStatusCreated {
+			numberOfUsersToBeCreated++
+		}
+	}
+
+	var createdUsers []loginmodule.CreateUsersResponseDataRow
+	if numberOfUsersToBeCreated > 0 {
+		if apiError := checkRosterUserBatchParameters(store, user, &input, parentGroupID, numberOfUsersToBeCreated); apiError != service.NoError {
+			return apiError
+		}
+
+		if !dryRun {
+			err = store.UserBatches().InsertMap(map[string]interface{}{
+				"group_prefix":  input.GroupPrefix,
+				"custom_prefix": input.CustomPrefix,
+				"size":          numberOfUsersToBeCreated,
+				"creator_id":    user.GroupID,
+				"created_at":    database.Now(),
+			})
+			if err != nil && database.IsDuplicateEntryError(err) {
+				return service.ErrInvalidRequest(errors.New("'custom_prefix' already exists for the given 'group_prefix'"))
+			}
+			service.MustNotBeError(err)
+
+			var loginModuleResult bool
+			loginModuleResult, createdUsers, err = loginmodule.NewClient(srv.AuthConfig.GetString("loginModuleURL")).
+				CreateUsers(r.Context(), srv.AuthConfig.GetString("clientID"), srv.AuthConfig.GetString("clientSecret"),
+					&loginmodule.CreateUsersParams{
+						Prefix:         fmt.Sprintf("%s_%s_", input.GroupPrefix, input.CustomPrefix),
+						Amount:         numberOfUsersToBeCreated,
+						PostfixLength:  input.PostfixLength,
+						PasswordLength: input.PasswordLength,
+						LoginFixed:     func(b bool) *bool { return &b }(true),
+						Language:       func(s string) *string { return &s }(user.DefaultLanguage),
+					})
+
+			defer func() {
+				if p := recover(); p != nil {
+					store.UserBatches().Delete("group_prefix = ? AND custom_prefix = ?", input.GroupPrefix, input.CustomPrefix)
+					panic(p)
+				}
+			}()
+			service.MustNotBeError(err)
+			if !loginModuleResult || len(createdUsers) != numberOfUsersToBeCreated {
+				panic(errors.New("login module failed"))
+			}
+		}
+	}
+
+	err = store.InTransaction(func(store *database.DataStore) error {
+		placeRosterRowsIntoGroups(store, r, user, parentGroupID, rows, result, userGroupIDs, createdUsers)
+		if dryRun {
+			return errRosterImportDryRun // rollback
+		}
+		return nil
+	})
+
+	if dryRun {
+		if !errors.Is(err, errRosterImportDryRun) {
+			service.MustNotBeError(err)
+		}
+		render.Respond(w, r, &service.Response[[]importRosterResultRow]{Success: true, Message: "dry run", Data: result})
+		return service.NoError
+	}
+	service.MustNotBeError(err)
+
+	service.MustNotBeError(render.Render(w, r, service.CreationSuccess(result)))
+	return service.NoError
+}
+
+func checkRosterUserBatchParameters(store *database.DataStore, user *database.User, input *importRosterRequest,
+	parentGroupID int64, numberOfUsersToBeCreated int,
+) service.APIError {
+	if input.GroupPrefix == "" || input.CustomPrefix == "" {
+		return service.ErrInvalidRequest(errors.New("'group_prefix' and 'custom_prefix' are required to create users"))
+	}
+	_, _, apiError := checkCreateUserBatchRequestParameters(store, user, createUserBatchRequest{
+		GroupPrefix:    input.GroupPrefix,
+		CustomPrefix:   input.CustomPrefix,
+		Subgroups:      []createUserBatchRequestSubgroup{{GroupID: parentGroupID, Count: numberOfUsersToBeCreated}},
+		PostfixLength:  input.PostfixLength,
+		PasswordLength: input.PasswordLength,
+	})
+	return apiError
+}
+
+type rosterFoundUser struct {
+	GroupID int64
+	Login   string
+	Email   string
+}
+
+// matchRosterRowsWithUsers initializes the report rows with the found users (status = "invited")
+// or with the users to be created (status = "created") and returns ids of the found users.
+// Users are only found by email if they are managed by the current user,
+// so that the service cannot be used to look up accounts by emails.
+func matchRosterRowsWithUsers(store *database.DataStore, user *database.User, rows []rosterRow) (
+	result []importRosterResultRow, userGroupIDs []int64,
+) {
+	logins := make([]string, 0, len(rows))
+	emails := make([]string, 0, len(rows))
+	for index := range rows {
+		if rows[index].Login != "" {
+			logins = append(logins, rows[index].Login)
+		}
+		if rows[index].Email != "" {
+			emails = append(emails, rows[index].Email)
+		}
+	}
+
+	var foundUsers []rosterFoundUser
+	usersByLogin := make(map[string]int, len(logins))
+	usersByEmail := make(map[string][]int, len(emails))
+	if len(logins) > 0 || len(emails) > 0 {
+		query := store.Users().Select("group_id, login, IFNULL(email, '') AS email").Where("NOT temp_user")
+		if len(logins) > 0 && len(emails) > 0 {
+			query = query.Where("login IN (?) OR email IN (?)", logins, emails)
+		} else if len(logins) > 0 {
+			query = query.Where("login IN (?)", logins)
+		} else {
+			query = query.Where("email IN (?)", emails)
+		}
+		service.MustNotBeError(query.Order("group_id").Scan(&foundUsers).Error())
+	}
+	managedUsers := findManagedUsersAmong(store, user, foundUsers)
+	for index := range foundUsers {
+		usersByLogin[strings.ToLower(foundUsers[index].Login)] = index
+		if managedUsers[foundUsers[index].GroupID] {
+			email := strings.ToLower(foundUsers[index].Email)
+			usersByEmail[email] = append(usersByEmail[email], index)
+		}
+	}
+
+	result = make([]importRosterResultRow, len(rows))
+	userGroupIDs = make([]int64, len(rows))
+	seenUsers := make(map[string]bool, len(rows))
+	for index := range rows {
+		row := &rows[index]
+		result[index] = importRosterResultRow{
+			Row: index + 1, FirstName: row.FirstName, LastName: row.LastName, Email: row.Email, Class: row.Class,
+		}
+		if row.FirstName == "" || row.LastName == "" {
+			result[index].Status = rosterRowStatusMissingName
+			continue
+		}
+
+		foundUserIndex, found := usersByLogin[strings.ToLower(row.Login)]
+		if row.Login == "" || !found {
+			foundUserIndexes := usersByEmail[strings.ToLower(row.Email)]
+			if row.Email != "" && len(foundUserIndexes) > 1 {
+				result[index].Status = rosterRowStatusAmbiguousEmail
+				continue
+			}
+			found = row.Email != "" && len(foundUserIndexes) == 1
+			if found {
+				foundUserIndex = foundUserIndexes[0]
+			}
+		}
+
+		var userKey string
+		if found {
+			userKey = fmt.Sprintf("user:%d", foundUsers[foundUserIndex].GroupID)
+		} else if row.Email != "" {
+			userKey = "email:" + strings.ToLower(row.Email)
+		}
+		if userKey != "" {
+			if seenUsers[userKey] {
+				result[index].Status = rosterRowStatusDuplicate
+				continue
+			}
+			seenUsers[userKey] = true
+		}
+
+		if !found {
+			result[index].Status = rosterRowStatusCreated
+			continue
+		}
+		result[index].Status = rosterRowStatusInvited
+		userGroupIDs[index] = foundUsers[foundUserIndex].GroupID
+		result[index].UserID = &userGroupIDs[index]
+		result[index].Login = &foundUsers[foundUserIndex].Login
+	}
+	return result, userGroupIDs
+}
+
+// findManagedUsersAmong returns the set of the given users which are managed by the given user
+// (i.e. are descendants of groups managed by the user).
+func findManagedUsersAmong(store *database.DataStore, user *database.User, users []rosterFoundUser) map[int64]bool {
+	managedUsers := make(map[int64]bool, len(users))
+	if len(users) == 0 {
+		return managedUsers
+	}
+	userIDs := make([]int64, 0, len(users))
+	for index := range users {
+		userIDs = append(userIDs, users[index].GroupID)
+	}
+
+	var managedUserIDs []int64
+	service.MustNotBeError(store.ActiveGroupAncestors().ManagedByUser(user).
+		Where("groups_ancestors_active.child_group_id IN (?)", userIDs).
+		Pluck("DISTINCT groups_ancestors_active.child_group_id", &managedUserIDs).Error())
+	for _, userID := range managedUserIDs {
+		managedUsers[userID] = true
+	}
+	return managedUsers
+}
+
+// placeRosterRowsIntoGroups finds or creates the class groups of the rows, creates the users in the DB
+// (if createdUsers are given), and invites the found users into the groups.
+func placeRosterRowsIntoGroups(store *database.DataStore, r *http.Request, user *database.User, parentGroupID int64,
+	rows []rosterRow, result []importRosterResultRow, userGroupIDs []int64,
+	createdUsers []loginmodule.CreateUsersResponseDataRow,
+) {
+	groupIDs, newGroups := findOrCreateRosterClassGroups(store, parentGroupID, rows, result)
+
+	rowGroupIDs := make([]int64, len(rows))
+	groupIDsToInvite := make([]int64, 0, len(groupIDs)+1)
+	usersToInvite := make(map[int64][]int64, len(groupIDs)+1)
+	for index := range result {
+		if result[index].Status != rosterRowStatusCreated && result[index].Status != rosterRowStatusInvited {
+			continue
+		}
+		rowGroupIDs[index] = parentGroupID
+		if rows[index].Class != "" {
+			rowGroupIDs[index] = groupIDs[strings.ToLower(rows[index].Class)]
+		}
+		if !newGroups[rowGroupIDs[index]] {
+			result[index].GroupID = &rowGroupIDs[index]
+		}
+		if result[index].Status == rosterRowStatusInvited {
+			if _, ok := usersToInvite[rowGroupIDs[index]]; !ok {
+				groupIDsToInvite = append(groupIDsToInvite, rowGroupIDs[index])
+			}
+			usersToInvite[rowGroupIDs[index]] = append(usersToInvite[rowGroupIDs[index]], userGroupIDs[index])
+		}
+	}
+
+	if len(createdUsers) > 0 {
+		createRosterUsersInDB(store, r, user, rows, result, rowGroupIDs, createdUsers)
+	}
+
+	for _, groupID := range groupIDsToInvite {
+		groupResults, _, err := store.GroupGroups().
+			Transition(database.AdminCreatesInvitation, groupID, usersToInvite[groupID], nil, user.GroupID)
+		service.MustNotBeError(err)
+		for index := range result {
+			if result[index].Status == rosterRowStatusInvited && rowGroupIDs[index] == groupID {
+				if groupResult := groupResults[userGroupIDs[index]]; groupResult != database.Success {
+					result[index].Status = string(groupResult)
+				}
+			}
+		}
+	}
+}
+
+// findOrCreateRosterClassGroups returns ids of the class groups of the rows (by lowercased names)
+// and the set of the groups created by the import.
+func findOrCreateRosterClassGroups(store *database.DataStore, parentGroupID int64,
+	rows []rosterRow, result []importRosterResultRow,
+) (groupIDs map[string]int64, newGroups map[int64]bool) {
+	classNames := make([]string, 0, len(rows))
+	for index := range rows {
+		if rows[index].Class != "" &&
+			(result[index].Status == rosterRowStatusCreated || result[index].Status == rosterRowStatusInvited) {
+			classNames = append(classNames, rows[index].Class)
+		}
+	}
+	groupIDs = make(map[string]int64, len(classNames))
+	newGroups = make(map[int64]bool, len(classNames))
+	if len(classNames) == 0 {
+		return groupIDs, newGroups
+	}
+
+	var existingGroups []struct {
+		ID   int64
+		Name string
+	}
+	service.MustNotBeError(store.Groups().
+		Joins("JOIN groups_groups_active ON groups_groups_active.child_group_id = groups.id").
+		Where("groups_groups_active.parent_group_id = ?", parentGroupID).
+		Where("groups.type != 'User'").
+		Where("groups.name IN (?)", classNames).
+		Order("groups.id").
+		Select("groups.id, groups.name").
+		Scan(&existingGroups).Error())
+	for _, group := range existingGroups {
+		if _, ok := groupIDs[strings.ToLower(group.Name)]; !ok {
+			groupIDs[strings.ToLower(group.Name)] = group.ID
+		}
+	}
+
+	relationsToCreate := make([]map[string]interface{}, 0, len(classNames))
+	for _, className := range classNames {
+		if _, ok := groupIDs[strings.ToLower(className)]; ok {
+			continue
+		}
+		groupID, err := store.Groups().CreateNew(className, rosterClassGroupType)
+		service.MustNotBeError(err)
+		groupIDs[strings.ToLower(className)] = groupID
+		newGroups[groupID] = true
+		relationsToCreate = append(relationsToCreate, map[string]interface{}{
+			"parent_group_id": parentGroupID, "child_group_id": groupID,
+		})
+	}
+	if len(relationsToCreate) > 0 {
+		service.MustNotBeError(store.GroupGroups().CreateRelationsWithoutChecking(relationsToCreate))
+	}
+	return groupIDs, newGroups
+}
+
+func createRosterUsersInDB(store *database.DataStore, r *http.Request, user *database.User,
+	rows []rosterRow, result []importRosterResultRow, rowGroupIDs []int64,
+	createdUsers []loginmodule.CreateUsersResponseDataRow,
+) {
+	groupIDs := make([]interface{}, 0, len(rows))
+	for index := range rowGroupIDs {
+		if result[index].Status == rosterRowStatusCreated {
+			groupIDs = append(groupIDs, rowGroupIDs[index])
+		}
+	}
+	var groupsApprovals []struct {
+		ID                                int64
+		RequirePersonalInfoAccessApproval bool
+		RequireLockMembershipApproval     bool
+		RequireWatchApproval              bool
+	}
+	service.MustNotBeError(store.Groups().Where("id IN (?)", groupIDs).
+		Select("id, " + subgroupApprovalColumns).Scan(&groupsApprovals).Error())
+	approvals := make(map[int64]subgroupApproval, len(groupsApprovals))
+	for _, groupApprovals := range groupsApprovals {
+		approvals[groupApprovals.ID] = subgroupApproval{
+			RequirePersonalInfoAccessApproval: groupApprovals.RequirePersonalInfoAccessApproval,
+			RequireLockMembershipApproval:     groupApprovals.RequireLockMembershipApproval,
+			RequireWatchApproval:              groupApprovals.RequireWatchApproval,
+		}
+	}
+
+	domainConfig := domain.ConfigFromContext(r.Context())
+	relationsToCreate := make([]map[string]interface{}, 0, 2*len(createdUsers))
+	usersToCreate := make([]map[string]interface{}, 0, len(createdUsers))
+	attemptsToCreate := make([]map[string]interface{}, 0, len(createdUsers))
+	createdUserIndex := 0
+	for index := range result {
+		if result[index].Status != rosterRowStatusCreated {
+			continue
+		}
+		createdUser := createdUsers[createdUserIndex]
+		createdUserIndex++
+
+		userGroupID := createUserGroup(store, createdUser.Login)
+
+		var personalInfoApprovedAt, lockMembershipApprovedAt, watchApprovedAt interface{}
+		groupApprovals := approvals[rowGroupIDs[index]]
+		if groupApprovals.RequirePersonalInfoAccessApproval {
+			personalInfoApprovedAt = database.Now()
+		}
+		if groupApprovals.RequireLockMembershipApproval {
+			lockMembershipApprovedAt = database.Now()
+		}
+		if groupApprovals.RequireWatchApproval {
+			watchApprovedAt = database.Now()
+		}
+		relationsToCreate = append(relationsToCreate,
+			map[string]interface{}{
+				"parent_group_id":                domainConfig.AllUsersGroupID,
+				"child_group_id":                 userGroupID,
+				"personal_info_view_approved_at": nil, "lock_membership_approved_at": nil, "watch_approved_at": nil,
+			},
+			map[string]interface{}{
+				"parent_group_id":                rowGroupIDs[index],
+				"child_group_id":                 userGroupID,
+				"personal_info_view_approved_at": personalInfoApprovedAt,
+				"lock_membership_approved_at":    lockMembershipApprovedAt,
+				"watch_approved_at":              watchApprovedAt,
+			},
+		)
+
+		var email interface{}
+		if rows[index].Email != "" {
+			email = rows[index].Email
+		}
+		usersToCreate = append(usersToCreate, map[string]interface{}{
+			"temp_user":        0,
+			"registered_at":    database.Now(),
+			"group_id":         userGroupID,
+			"login_id":         createdUser.ID,
+			"login":            createdUser.Login,
+			"first_name":       rows[index].FirstName,
+			"last_name":        rows[index].LastName,
+			"email":            email,
+			"default_language": user.DefaultLanguage,
+			"creator_id":       user.GroupID,
+		})
+
+		attemptsToCreate = append(attemptsToCreate, map[string]interface{}{
+			"participant_id": userGroupID,
+			"id":             0,
+			"creator_id":     userGroupID,
+			"created_at":     database.Now(),
+		})
+
+		result[index].UserID = &userGroupID
+		result[index].Login = &createdUser.Login
+		result[index].Password = createdUser.Password
+	}
+	service.MustNotBeError(store.Users().InsertMaps(usersToCreate))
+	service.MustNotBeError(store.Attempts().InsertMaps(attemptsToCreate))
+	service.MustNotBeError(store.GroupGroups().CreateRelationsWithoutChecking(relationsToCreate))
+}
+
+func parseCSVRoster(roster string) ([]rosterRow, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(roster, "\ufeff")))
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("the CSV header is missing")
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for index, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = index
+	}
+	for _, requiredColumn := range []string{"first_name", "last_name"} {
+		if _, ok := columns[requiredColumn]; !ok {
+			return nil, fmt.Errorf("the CSV column %q is missing", requiredColumn)
+		}
+	}
+	value := func(record []string, column string) string {
+		if index, ok := columns[column]; ok {
+			return strings.TrimSpace(record[index])
+		}
+		return ""
+	}
+
+	rows := make([]rosterRow, 0, len(records)-1)
+	for _, record := range records[1:] {
+		rows = append(rows, rosterRow{
+			FirstName: value(record, "first_name"),
+			LastName:  value(record, "last_name"),
+			Email:     value(record, "email"),
+			Class:     value(record, "class"),
+			Login:     value(record, "login"),
+		})
+	}
+	return rows, nil
+}
+
+type oneRosterReference struct {
+	SourcedID string `json:"sourcedId"`
+}
+
+type oneRosterDocument struct {
+	Users []struct {
+		SourcedID  string `json:"sourcedId"`
+		Username   string `json:"username"`
+		GivenName  string `json:"givenName"`
+		FamilyName string `json:"familyName"`
+		Email      string `json:"email"`
+		Role       string `json:"role"`
+	} `json:"users"`
+	Classes []struct {
+		SourcedID string `json:"sourcedId"`
+		Title     string `json:"title"`
+	} `json:"classes"`
+	Enrollments []struct {
+		User  oneRosterReference `json:"user"`
+		Class oneRosterReference `json:"class"`
+		Role  string             `json:"role"`
+	} `json:"enrollments"`
+}
+
+const oneRosterStudentRole = "student"
+
+func parseOneRoster(roster string) ([]rosterRow, error) {
+	var document oneRosterDocument
+	if err := json.Unmarshal([]byte(roster), &document); err != nil {
+		return nil, fmt.Errorf("invalid OneRoster document: %w", err)
+	}
+
+	usersIndexes := make(map[string]int, len(document.Users))
+	for index := range document.Users {
+		usersIndexes[document.Users[index].SourcedID] = index
+	}
+	classTitles := make(map[string]string, len(document.Classes))
+	for _, class := range document.Classes {
+		classTitles[class.SourcedID] = class.Title
+	}
+
+	newRow := func(userIndex int, class string) rosterRow {
+		user := &document.Users[userIndex]
+		return rosterRow{
+			FirstName: strings.TrimSpace(user.GivenName),
+			LastName:  strings.TrimSpace(user.FamilyName),
+			Email:     strings.TrimSpace(user.Email),
+			Class:     strings.TrimSpace(class),
+			Login:     strings.TrimSpace(user.Username),
+		}
+	}
+
+	rows := make([]rosterRow, 0, len(document.Enrollments))
+	enrolledUsers := make(map[int]bool, len(document.Users))
+	for enrollmentIndex, enrollment := range document.Enrollments {
+		if enrollment.Role != "" && enrollment.Role != oneRosterStudentRole {
+			continue
+		}
+		userIndex, ok := usersIndexes[enrollment.User.SourcedID]
+		if !ok {
+			return nil, fmt.Errorf("unknown user of the enrollment #%d", enrollmentIndex+1)
+		}
+		classTitle, ok := classTitles[enrollment.Class.SourcedID]
+		if !ok {
+			return nil, fmt.Errorf("unknown class of the enrollment #%d", enrollmentIndex+1)
+		}
+		enrolledUsers[userIndex] = true
+		rows = append(rows, newRow(userIndex, classTitle))
+	}
+	for userIndex := range document.Users {
+		if !enrolledUsers[userIndex] && document.Users[userIndex].Role == oneRosterStudentRole {
+			rows = append(rows, newRow(userIndex, ""))
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,238 @@
+Feature: Import a class roster - robustness
+  Background:
+    Given the database has the following table "groups":
+      | id | type | name     |
+      | 2  | Base | AllUsers |
+      | 3  | Club | Club     |
+      | 5  | Team | Team     |
+    And the database has the following users:
+      | group_id | login | first_name  | last_name | email            | default_language |
+      | 21       | owner | Jean-Michel | Blanquer  | null             | en               |
+      | 22       | jane  | Jane        | Doe       | jane@example.com | en               |
+      | 23       | jean  | Jean        | Doe       | jane@example.com | en               |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_manage  |
+      | 3        | 21         | memberships |
+      | 3        | 22         | none        |
+      | 5        | 21         | memberships |
+    And the groups ancestors are computed
+    And the database has the following table "user_batch_prefixes":
+      | group_prefix | group_id | allow_new | max_users |
+      | test         | 3        | 1         | 2         |
+    And the database has the following table "user_batches_v2":
+      | group_prefix | custom_prefix | size | creator_id |
+      | test         | existing      | 1    | 21         |
+
+  Scenario: Invalid parent_group_id
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/abc/roster-imports" with the following body:
+      """
+      {"format": "csv", "roster": "first_name,last_name\nJohn,Doe"}
+      """
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for parent_group_id (should be int64)"
+    And the table "groups" should stay unchanged
+    And the table "users" should stay unchanged
+
+  Scenario: Invalid dry_run
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports?dry_run=2" with the following body:
+      """
+      {"format": "csv", "roster": "first_name,last_name\nJohn,Doe"}
+      """
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for dry_run (should have a boolean value (0 or 1))"
+    And the table "groups" should stay unchanged
+    And the table "users" should stay unchanged
+
+  Scenario: Missing fields
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {}
+      """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "Bad Request",
+        "error_code": "invalid_input_data",
+        "error_text": "Invalid input data",
+        "errors": {
+          "format": ["missing field"],
+          "roster": ["missing field"]
+        }
+      }
+      """
+    And the table "groups" should stay unchanged
+    And the table "users" should stay unchanged
+
+  Scenario: Invalid fields
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {"format": "xlsx", "roster": "", "custom_prefix": "A", "postfix_length": 2, "password_length": 51}
+      """
+    Then the response code should be 400
+    And the response body should be, in JSON:
+      """
+      {
+        "success": false,
+        "message": "Bad Request",
+        "error_code": "invalid_input_data",
+        "error_text": "Invalid input data",
+        "errors": {
+          "custom_prefix": ["The custom prefix should only consist of letters/digits/hyphens and be 2-14 characters long"],
+          "format": ["format must be one of [csv oneroster]"],
+          "password_length": ["password_length must be 50 or less"],
+          "postfix_length": ["postfix_length must be 3 or greater"],
+          "roster": ["roster must be at least 1 character in length"]
+        }
+      }
+      """
+    And the table "groups" should stay unchanged
+    And the table "users" should stay unchanged
+
+  Scenario: Invalid CSV
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {"format": "csv", "roster": "first_name,last_name\nJohn"}
+      """
+    Then the response code should be 400
+    And the response error message should contain "Invalid CSV: record on line 2: wrong number of fields"
+    And the table "groups" should stay unchanged
+    And the table "users" should stay unchanged
+
+  Scenario: Missing CSV column
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {"format": "csv", "roster": "first_name,email\nJohn,john@example.com"}
+      """
+    Then the response code should be 400
+    And the response error message should contain "is missing"
+    And the table "groups" should stay unchanged
+    And the table "users" should stay unchanged
+
+  Scenario: Invalid OneRoster document
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {"format": "oneroster", "roster": "{\"enrollments\":[{\"user\":{\"sourcedId\":\"u1\"},\"class\":{\"sourcedId\":\"c1\"}}]}"}
+      """
+    Then the response code should be 400
+    And the response error message should contain "Unknown user of the enrollment #1"
+    And the table "groups" should stay unchanged
+    And the table "users" should stay unchanged
+
+  Scenario: The user is not a manager of the group
+    Given I am the user with id "23"
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {"format": "csv", "roster": "first_name,last_name,login\nJane,Doe,jane"}
+      """
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "groups" should stay unchanged
+    And the table "group_pending_requests" should be empty
+
+  Scenario: The user cannot manage memberships of the group
+    Given I am the user with id "22"
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {"format": "csv", "roster": "first_name,last_name,login\nJane,Doe,jane"}
+      """
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "groups" should stay unchanged
+    And the table "group_pending_requests" should be empty
+
+  Scenario: The group is a team
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/5/roster-imports" with the following body:
+      """
+      {"format": "csv", "roster": "first_name,last_name,login\nJane,Doe,jane"}
+      """
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "groups" should stay unchanged
+    And the table "group_pending_requests" should be empty
+
+  Scenario: The group is a user
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/21/roster-imports" with the following body:
+      """
+      {"format": "csv", "roster": "first_name,last_name,login\nJane,Doe,jane"}
+      """
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "groups" should stay unchanged
+    And the table "group_pending_requests" should be empty
+
+  Scenario: The prefixes are not given while some users should be created
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {"format": "csv", "roster": "first_name,last_name,class\nJohn,Smith,6A"}
+      """
+    Then the response code should be 400
+    And the response error message should contain "'group_prefix' and 'custom_prefix' are required to create users"
+    And the table "groups" should stay unchanged
+    And the table "users" should stay unchanged
+    And the table "user_batches_v2" should stay unchanged
+
+  Scenario: Too many users to create for the prefix
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports?dry_run=1" with the following body:
+      """
+      {
+        "format": "csv", "roster": "first_name,last_name\nJohn,Smith\nJane,Smith",
+        "group_prefix": "test", "custom_prefix": "custom"
+      }
+      """
+    Then the response code should be 400
+    And the response error message should contain "'user_batch_prefix.max_users' exceeded"
+    And the table "groups" should stay unchanged
+    And the table "users" should stay unchanged
+    And the table "user_batches_v2" should stay unchanged
+
+  Scenario: The custom prefix already exists
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {
+        "format": "csv", "roster": "first_name,last_name\nJohn,Smith",
+        "group_prefix": "test", "custom_prefix": "existing"
+      }
+      """
+    Then the response code should be 400
+    And the response error message should contain "'custom_prefix' already exists for the given 'group_prefix'"
+    And the table "groups" should stay unchanged
+    And the table "users" should stay unchanged
+    And the table "user_batches_v2" should stay unchanged
+
+  Scenario: Ambiguous emails are reported
+    Given I am the user with id "21"
+    When I send a POST request to "/groups/3/roster-imports" with the following body:
+      """
+      {"format": "csv", "roster": "first_name,last_name,email\nJane,Doe,jane@example.com"}
+      """
+    Then the response code should be 201
+    And the response body should be, in JSON:
+      """
+      {
+        "success": true,
+        "message": "created",
+        "data": [
+          {
+            "row": 1, "first_name": "Jane", "last_name": "Doe", "email": "jane@example.com", "class": "", "status": "ambiguous",
+            "group_id": null, "user_id": null, "login": null
+          }
+        ]
+      }
+      """
+    And the table "groups" should stay unchanged
+    And the table "users" should stay unchanged
+    And the table "group_pending_requests" should be empty
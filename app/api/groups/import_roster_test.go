@@ -0,0 +1,115 @@
+package groups
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseCSVRoster(t *testing.T) {
+	tests := []struct {
+		name          string
+		roster        string
+		want          []rosterRow
+		expectedError string
+	}{
+		{
+			name: "all columns",
+			roster: "\ufefflast_name,First_Name,email,class,login,comment\n" +
+				"Doe, John ,john@example.com,6A,jdoe,a comment\n" +
+				"Roe,Jane,,6B,,\n",
+			want: []rosterRow{
+				{FirstName: "John", LastName: "Doe", Email: "john@example.com", Class: "6A", Login: "jdoe"},
+				{FirstName: "Jane", LastName: "Roe", Class: "6B"},
+			},
+		},
+		{
+			name:   "only names",
+			roster: "first_name,last_name\nJohn,Doe",
+			want:   []rosterRow{{FirstName: "John", LastName: "Doe"}},
+		},
+		{
+			name:   "no rows",
+			roster: "first_name,last_name\n",
+			want:   []rosterRow{},
+		},
+		{name: "empty", roster: "\n", expectedError: "the CSV header is missing"},
+		{name: "missing column", roster: "first_name,email\nJohn,john@example.com", expectedError: `the CSV column "last_name" is missing`},
+		{
+			name:          "wrong number of fields",
+			roster:        "first_name,last_name\nJohn",
+			expectedError: "invalid CSV: record on line 2: wrong number of fields",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCSVRoster(tt.roster)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseOneRoster(t *testing.T) {
+	tests := []struct {
+		name          string
+		roster        string
+		want          []rosterRow
+		expectedError string
+	}{
+		{
+			name: "enrollments",
+			roster: `{
+				"users": [
+					{"sourcedId": "u1", "username": "jdoe", "givenName": "John", "familyName": "Doe",
+					 "email": "john@example.com", "role": "student"},
+					{"sourcedId": "u2", "givenName": "Jane", "familyName": "Roe", "role": "student"},
+					{"sourcedId": "u3", "givenName": "Paul", "familyName": "Poe", "role": "teacher"},
+					{"sourcedId": "u4", "givenName": "Lena", "familyName": "Loe", "role": "student"}
+				],
+				"classes": [{"sourcedId": "c1", "title": "6A"}, {"sourcedId": "c2", "title": "6B"}],
+				"enrollments": [
+					{"user": {"sourcedId": "u1"}, "class": {"sourcedId": "c1"}, "role": "student"},
+					{"user": {"sourcedId": "u3"}, "class": {"sourcedId": "c1"}, "role": "teacher"},
+					{"user": {"sourcedId": "u2"}, "class": {"sourcedId": "c2"}},
+					{"user": {"sourcedId": "u1"}, "class": {"sourcedId": "c2"}, "role": "student"}
+				]
+			}`,
+			want: []rosterRow{
+				{FirstName: "John", LastName: "Doe", Email: "john@example.com", Class: "6A", Login: "jdoe"},
+				{FirstName: "Jane", LastName: "Roe", Class: "6B"},
+				{FirstName: "John", LastName: "Doe", Email: "john@example.com", Class: "6B", Login: "jdoe"},
+				{FirstName: "Lena", LastName: "Loe"},
+			},
+		},
+		{name: "invalid JSON", roster: `[]`, expectedError: "invalid OneRoster document: " +
+			"json: cannot unmarshal array into Go value of type groups.oneRosterDocument"},
+		{
+			name:          "unknown user",
+			roster:        `{"classes": [{"sourcedId": "c1"}], "enrollments": [{"user": {"sourcedId": "u1"}, "class": {"sourcedId": "c1"}}]}`,
+			expectedError: "unknown user of the enrollment #1",
+		},
+		{
+			name:          "unknown class",
+			roster:        `{"users": [{"sourcedId": "u1"}], "enrollments": [{"user": {"sourcedId": "u1"}, "class": {"sourcedId": "c1"}}]}`,
+			expectedError: "unknown class of the enrollment #1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOneRoster(tt.roster)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
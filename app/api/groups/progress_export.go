@@ -0,0 +1,166 @@
+package groups
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+const (
+	progressExportFormatCSV    = "csv"
+	progressExportFormatNDJSON = "ndjson"
+
+	defaultProgressExportWriteTimeout = 60 * time.Second
+)
+
+func resolveProgressExportFormat(r *http.Request) (string, service.APIError) {
+	if len(r.URL.Query()["format"]) == 0 {
+		return progressExportFormatCSV, service.NoError
+	}
+	format := r.URL.Query().Get("format")
+	if format != progressExportFormatCSV && format != progressExportFormatNDJSON {
+		return "", service.ErrInvalidRequest(errors.New("wrong value for format (should be one of csv, ndjson)"))
+	}
+	return format, service.NoError
+}
+
+// progressExportWriter writes rows of a progress export either as CSV or as NDJSON.
+// Rows are written as they are computed, the output is flushed to the client after each chunk of participants.
+type progressExportWriter struct {
+	responseController *http.ResponseController
+	writeTimeout       time.Duration
+	csvWriter          *csv.Writer
+	jsonEncoder        *json.Encoder
+	nameKeys           []string
+	itemIDs            []interface{}
+}
+
+// newProgressExportWriter sets the headers of the response and creates a writer of the export.
+func (srv *Service) newProgressExportWriter(
+	w http.ResponseWriter, format, fileNamePrefix string, groupID int64, itemParentIDs []int64,
+) *progressExportWriter {
+	itemParentIDsString := make([]string, len(itemParentIDs))
+	for i, id := range itemParentIDs {
+		itemParentIDsString[i] = strconv.FormatInt(id, 10)
+	}
+	contentType := "text/csv"
+	if format == progressExportFormatNDJSON {
+		contentType = "application/x-ndjson"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf("attachment; filename=%s_progress_for_group_%d_and_child_items_of_%s.%s",
+			fileNamePrefix, groupID, strings.Join(itemParentIDsString, "_"), format))
+
+	writeTimeout := defaultProgressExportWriteTimeout
+	if configuredTimeout := srv.ServerConfig.GetInt64("writeTimeout"); configuredTimeout > 0 {
+		writeTimeout = time.Duration(configuredTimeout) * time.Second
+	}
+
+	writer := &progressExportWriter{
+		responseController: http.NewResponseController(w),
+		writeTimeout:       writeTimeout,
+	}
+	if format == progressExportFormatNDJSON {
+		writer.jsonEncoder = json.NewEncoder(w)
+	} else {
+		writer.csvWriter = csv.NewWriter(w)
+		writer.csvWriter.Comma = ';'
+	}
+	return writer
+}
+
+// writeHeader writes the header of the export (only CSV exports have a header).
+func (writer *progressExportWriter) writeHeader(columns []string) {
+	if writer.csvWriter != nil {
+		service.MustNotBeError(writer.csvWriter.Write(columns))
+	}
+}
+
+// setColumns sets the keys of the participant names and the ordered list of item IDs (possibly with duplicates)
+// of the rows to be written.
+func (writer *progressExportWriter) setColumns(nameKeys []string, orderedItemIDListWithDuplicates []interface{}) {
+	writer.nameKeys = nameKeys
+	writer.itemIDs = orderedItemIDListWithDuplicates
+}
+
+// writeRow writes a row of scores of a participant. A missing score (nil or an empty string) is written as an empty cell
+// for CSV and as null for NDJSON.
+func (writer *progressExportWriter) writeRow(participantID int64, names []string, scores map[int64]interface{}) {
+	if writer.csvWriter != nil {
+		row := make([]string, 0, len(names)+len(writer.itemIDs))
+		row = append(row, names...)
+		for _, itemID := range writer.itemIDs {
+			var cell string
+			if score := scores[itemID.(int64)]; score != nil {
+				cell = fmt.Sprintf("%v", score)
+			}
+			row = append(row, cell)
+		}
+		service.MustNotBeError(writer.csvWriter.Write(row))
+		return
+	}
+
+	row := make(map[string]interface{}, len(names)+2)
+	row["id"] = strconv.FormatInt(participantID, 10)
+	for index, name := range names {
+		row[writer.nameKeys[index]] = name
+	}
+	scoresMap := make(map[string]*float64, len(writer.itemIDs))
+	for _, itemID := range writer.itemIDs {
+		scoresMap[strconv.FormatInt(itemID.(int64), 10)] = convertProgressExportScore(scores[itemID.(int64)])
+	}
+	row["scores"] = scoresMap
+	service.MustNotBeError(writer.jsonEncoder.Encode(row))
+}
+
+func convertProgressExportScore(score interface{}) *float64 {
+	var value float64
+	switch typedScore := score.(type) {
+	case float64:
+		value = typedScore
+	case float32:
+		value = float64(typedScore)
+	case int64:
+		value = float64(typedScore)
+	case string:
+		if typedScore == "" {
+			return nil
+		}
+		var err error
+		value, err = strconv.ParseFloat(typedScore, 64)
+		service.MustNotBeError(err)
+	default:
+		return nil
+	}
+	return &value
+}
+
+// flushChunk sends the rows written so far to the client and extends the write deadline of the connection,
+// so the whole export doesn't need to fit into the write timeout of the server.
+func (writer *progressExportWriter) flushChunk() {
+	if writer.csvWriter != nil {
+		writer.csvWriter.Flush()
+		service.MustNotBeError(writer.csvWriter.Error())
+	}
+	if err := writer.responseController.Flush(); !errors.Is(err, http.ErrNotSupported) {
+		service.MustNotBeError(err)
+	}
+	if err := writer.responseController.SetWriteDeadline(time.Now().Add(writer.writeTimeout)); !errors.Is(err, http.ErrNotSupported) {
+		service.MustNotBeError(err)
+	}
+}
+
+// close flushes the remaining rows.
+func (writer *progressExportWriter) close() {
+	if writer.csvWriter != nil {
+		writer.csvWriter.Flush()
+	}
+}
@@ -17,6 +17,9 @@ Feature: Reject group requests
     And the database has the following user:
       | group_id | login | first_name  | last_name |
       | 21       | owner | Jean-Michel | Blanquer  |
+    And the database has the following table "users":
+      | group_id | login |
+      | 31       | john  |
     And the database has the following table "groups_groups":
       | parent_group_id | child_group_id |
       | 13              | 111            |
@@ -65,6 +68,9 @@ Feature: Reject group requests
       | group_id | member_id | action               | initiator_id | ABS(TIMESTAMPDIFF(SECOND, at, NOW())) < 3 |
       | 13       | 31        | join_request_refused | 21           | 1                                         |
       | 13       | 141       | join_request_refused | 21           | 1                                         |
+    And the table "notifications" should be:
+      | user_id | type                 | CAST(payload AS CHAR)                | ABS(TIMESTAMPDIFF(SECOND, created_at, NOW())) < 3 |
+      | 31      | join_request_refused | {"group_id": "13", "group_name": ""} | 1                                                 |
     And the table "groups_ancestors" should stay unchanged
   Examples:
     | can_manage            |
@@ -14,7 +14,7 @@ import (
 //		Lets an admin reject requests (of users with ids in {group_ids}) to join a group (identified by {parent_group_id}).
 //		On success the service removes rows with `type` = 'join_request' from `group_pending_requests` and
 //		creates new rows with `action` = 'join_request_refused' and `at` = current UTC time in `group_membership_changes`
-//		for each of `group_ids`.
+//		for each of `group_ids`. In-app notifications are created for the users (see `GET /current-user/notifications`).
 //
 //
 //		The authenticated user should be a manager of the `parent_group_id` with `can_manage` >= 'memberships',
@@ -73,6 +73,12 @@ Feature: Remove members from a group (groupRemoveMembers)
       | 13       | 61        | removed | 21           | 1                                         |
       | 13       | 91        | removed | 21           | 1                                         |
       | 13       | 111       | removed | 21           | 1                                         |
+    And the table "notifications" should be:
+      | user_id | type    | CAST(payload AS CHAR)                | read_at | ABS(TIMESTAMPDIFF(SECOND, created_at, NOW())) < 3 |
+      | 51      | removed | {"group_id": "13", "group_name": ""} | null    | 1                                                 |
+      | 61      | removed | {"group_id": "13", "group_name": ""} | null    | 1                                                 |
+      | 91      | removed | {"group_id": "13", "group_name": ""} | null    | 1                                                 |
+      | 111     | removed | {"group_id": "13", "group_name": ""} | null    | 1                                                 |
     And the table "groups_ancestors" should be:
       | ancestor_group_id | child_group_id | is_self |
       | 13                | 13             | 1       |
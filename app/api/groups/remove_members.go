@@ -18,7 +18,8 @@ import (
 //		Lets an admin remove users from a group.
 //		On success the service removes relations from `groups_groups` and creates `group_membership_changes` rows
 //		with `action` = 'removed and `at` = current UTC time
-//		for each of `user_ids`. It also refreshes the access rights.
+//		for each of `user_ids`. It also refreshes the access rights and creates in-app notifications for the users
+//		(see `GET /current-user/notifications`).
 //
 //
 //		The authenticated user should be a manager of the `group_id` with `can_manage` >= 'memberships',
@@ -0,0 +1,42 @@
+Feature: Remove a webhook (webhookDelete)
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+    And the database has the following table "groups":
+      | id |
+      | 13 |
+      | 14 |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 13              | 14             |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 21         | true              |
+    And the groups ancestors are computed
+    And the database has the following table "webhooks":
+      | id | group_id | url                        | secret  | event_types   |
+      | 1  | 13       | https://example.org/hooks1 | secret1 | grade_saved   |
+      | 2  | 14       | https://example.org/hooks2 | secret2 | item_unlocked |
+    And the database has the following table "webhook_deliveries":
+      | id | webhook_id | event_type    | payload                                                          |
+      | 1  | 1          | grade_saved   | {"participant_id": "14", "attempt_id": "0", "item_id": "210"}    |
+      | 2  | 2          | item_unlocked | {"participant_id": "14", "attempt_id": null, "item_id": "210"}   |
+
+  Scenario: Remove a webhook together with its deliveries
+    Given I am the user with id "21"
+    When I send a DELETE request to "/groups/14/webhooks/2"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "success": true,
+      "message": "deleted"
+    }
+    """
+    And the table "webhooks" should be:
+      | id |
+      | 1  |
+    And the table "webhook_deliveries" should be:
+      | id | webhook_id |
+      | 1  | 1          |
@@ -0,0 +1,71 @@
+package groups
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:operation DELETE /groups/{group_id}/webhooks/{webhook_id} groups webhookDelete
+//
+//	---
+//	summary: Remove a webhook
+//	description: >
+//
+//		Removes a webhook of the group together with its deliveries (pending deliveries are canceled).
+//
+//
+//		The current user should be a manager of the group with `can_watch_members`
+//		and the webhook should belong to the group, otherwise the 'forbidden' error is returned.
+//	parameters:
+//		- name: group_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: webhook_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//	responses:
+//		"200":
+//			"$ref": "#/responses/deletedResponse"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) removeWebhook(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	groupID, err := service.ResolveURLQueryPathInt64Field(r, "group_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+	webhookID, err := service.ResolveURLQueryPathInt64Field(r, "webhook_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	if !user.CanWatchGroupMembers(store, groupID) {
+		return service.InsufficientAccessRightsError
+	}
+
+	result := store.Webhooks().Where("id = ? AND group_id = ?", webhookID, groupID).Delete()
+	service.MustNotBeError(result.Error())
+	if result.RowsAffected() == 0 {
+		return service.InsufficientAccessRightsError
+	}
+
+	service.MustNotBeError(render.Render(w, r, service.DeletionSuccess[*struct{}](nil)))
+	return service.NoError
+}
@@ -0,0 +1,55 @@
+Feature: Remove a webhook (webhookDelete) - robustness
+  Background:
+    Given the database has the following users:
+      | login | group_id |
+      | owner | 21       |
+      | user  | 11       |
+    And the database has the following table "groups":
+      | id |
+      | 13 |
+      | 14 |
+    And the database has the following table "group_managers":
+      | group_id | manager_id | can_watch_members |
+      | 13       | 11         | false             |
+      | 13       | 21         | true              |
+      | 14       | 21         | true              |
+    And the groups ancestors are computed
+    And the database has the following table "webhooks":
+      | id | group_id | url                        | secret  | event_types |
+      | 1  | 13       | https://example.org/hooks1 | secret1 | grade_saved |
+      | 2  | 14       | https://example.org/hooks2 | secret2 | grade_saved |
+
+  Scenario: Invalid group_id
+    Given I am the user with id "21"
+    When I send a DELETE request to "/groups/abc/webhooks/1"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for group_id (should be int64)"
+    And the table "webhooks" should stay unchanged
+
+  Scenario: Invalid webhook_id
+    Given I am the user with id "21"
+    When I send a DELETE request to "/groups/13/webhooks/abc"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for webhook_id (should be int64)"
+    And the table "webhooks" should stay unchanged
+
+  Scenario: The user cannot watch members of the group
+    Given I am the user with id "11"
+    When I send a DELETE request to "/groups/13/webhooks/1"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "webhooks" should stay unchanged
+
+  Scenario: The webhook belongs to another group
+    Given I am the user with id "21"
+    When I send a DELETE request to "/groups/13/webhooks/2"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "webhooks" should stay unchanged
+
+  Scenario: The webhook doesn't exist
+    Given I am the user with id "21"
+    When I send a DELETE request to "/groups/13/webhooks/404"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+    And the table "webhooks" should stay unchanged
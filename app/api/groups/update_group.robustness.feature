@@ -127,6 +127,7 @@ Feature: Update a group (groupEdit) - robustness
         "expected_start": ["decoding error: parsing time \"abc\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"abc\" as \"2006\""]
       },
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "success": false
     }
     """
@@ -320,6 +321,7 @@ Feature: Update a group (groupEdit) - robustness
         "frozen_membership": ["can only be changed from false to true"]
       },
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "success": false
     }
     """
@@ -361,6 +363,7 @@ Feature: Update a group (groupEdit) - robustness
           "<error_field>": ["Strengthening requires parameter approval_change_action"]
         },
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "success": false
       }
       """
@@ -394,6 +397,7 @@ Feature: Update a group (groupEdit) - robustness
         "approval_change_action": ["approval_change_action must be one of [empty reinvite]"]
       },
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "success": false
     }
     """
@@ -417,6 +421,7 @@ Feature: Update a group (groupEdit) - robustness
         "approval_change_action": ["must be present only if a 'require_*' field is strengthened"]
       },
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "success": false
     }
     """
@@ -444,6 +449,7 @@ Feature: Update a group (groupEdit) - robustness
         "max_participants": ["cannot be set to null when 'enforce_max_participants' is true"]
       },
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "success": false
     }
     """
@@ -467,6 +473,7 @@ Feature: Update a group (groupEdit) - robustness
         "enforce_max_participants": ["cannot be set to true when 'max_participants' is null"]
       },
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "success": false
     }
     """
@@ -492,6 +499,7 @@ Feature: Update a group (groupEdit) - robustness
         "enforce_max_participants": ["cannot be set to true when 'max_participants' is null"]
       },
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "success": false
     }
     """
@@ -559,6 +567,7 @@ Feature: Update a group (groupEdit) - robustness
         "require_watch_approval": ["only managers with 'can_manage' \u003e= 'memberships_and_group' can modify this field"]
       },
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "success": false
     }
     """
@@ -632,6 +641,7 @@ Feature: Update a group (groupEdit) - robustness
         "enforce_max_participants": ["only managers with 'can_manage' \u003e= 'memberships' can modify this field"]
       },
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "success": false
     }
     """
@@ -458,3 +458,81 @@ Feature: Change item access rights for a group
     | root_activity_id | root_skill_id |
     | 104              | null          |
     | null             | 104           |
+
+  Scenario Outline: Create a new permissions_granted row with a can_view window
+    Given I am the user with id "21"
+    And the database table "permissions_generated" also has the following rows:
+      | group_id | item_id | can_view_generated | can_grant_view_generated | can_watch_generated | can_edit_generated | is_owner_generated |
+      | 21       | 102     | solution           | solution_with_grant      | answer_with_grant   | all_with_grant     | true               |
+      | 21       | 103     | solution           | solution                 | answer              | all                | true               |
+    And the database table "permissions_granted" also has the following rows:
+      | group_id | item_id | can_view | can_grant_view      | can_watch         | can_edit       | source_group_id | latest_update_at    |
+      | 21       | 102     | solution | solution_with_grant | answer_with_grant | all_with_grant | 23              | 2019-05-30 11:00:00 |
+    When I send a PUT request to "/groups/25/permissions/23/102" with the following body:
+      """
+      <json>
+      """
+    Then the response should be "updated"
+    And the table "permissions_granted" should be:
+      | group_id | item_id | source_group_id | origin           | can_view | can_view_from       | can_view_until      | TIMESTAMPDIFF(SECOND, latest_update_at, NOW()) < 3 |
+      | 21       | 102     | 23              | group_membership | solution | 1000-01-01 00:00:00 | 9999-12-31 23:59:59 | 0                                                  |
+      | 23       | 100     | 23              | group_membership | content  | 1000-01-01 00:00:00 | 9999-12-31 23:59:59 | 0                                                  |
+      | 23       | 102     | 25              | group_membership | solution | <can_view_from>     | <can_view_until>    | 1                                                  |
+    And the table "permissions_generated" should be:
+      | group_id | item_id | can_view_generated    | can_grant_view_generated | can_watch_generated | can_edit_generated | is_owner_generated |
+      | 21       | 102     | solution              | solution_with_grant      | answer_with_grant   | all_with_grant     | false              |
+      | 21       | 103     | content               | solution                 | answer              | all                | false              |
+      | 23       | 100     | content               | none                     | none                | none               | false              |
+      | 23       | 101     | info                  | none                     | none                | none               | false              |
+      | 23       | 102     | <can_view_generated>  | none                     | none                | none               | false              |
+      | 23       | 103     | <can_view_propagated> | none                     | none                | none               | false              |
+  Examples:
+    | json                                                            | can_view_from       | can_view_until      | can_view_generated | can_view_propagated |
+    | {"can_view":"solution","can_view_until":"3019-05-30T11:00:00Z"} | 1000-01-01 00:00:00 | 3019-05-30 11:00:00 | solution           | content             |
+    | {"can_view":"solution","can_view_from":"2019-05-30T11:00:00Z"}  | 2019-05-30 11:00:00 | 9999-12-31 23:59:59 | solution           | content             |
+    | {"can_view":"solution","can_view_from":"3019-05-30T11:00:00Z"}  | 3019-05-30 11:00:00 | 9999-12-31 23:59:59 | none               | none                |
+    | {"can_view":"solution","can_view_until":"2019-05-30T11:00:00Z"} | 1000-01-01 00:00:00 | 2019-05-30 11:00:00 | none               | none                |
+
+  Scenario: Preview the changes of generated permissions without applying them
+    Given I am the user with id "21"
+    And the database table "permissions_generated" also has the following rows:
+      | group_id | item_id | can_view_generated | can_grant_view_generated | can_watch_generated | can_edit_generated | is_owner_generated |
+      | 21       | 102     | solution           | solution_with_grant      | answer_with_grant   | all_with_grant     | true               |
+    And the database table "permissions_granted" also has the following rows:
+      | group_id | item_id | can_view | can_grant_view      | can_watch         | can_edit       | is_owner | source_group_id | latest_update_at    |
+      | 21       | 102     | solution | solution_with_grant | answer_with_grant | all_with_grant | true     | 23              | 2019-05-30 11:00:00 |
+    When I send a PUT request to "/groups/25/permissions/23/102?dry_run=1" with the following body:
+    """
+    {
+      "can_view": "solution"
+    }
+    """
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "success": true,
+      "message": "dry run",
+      "data": {
+        "items_count": 2,
+        "groups_count": 1,
+        "sample_changes": [
+          {
+            "item_id": "102",
+            "before": {"can_view": "none", "can_grant_view": "none", "can_watch": "none", "can_edit": "none", "is_owner": false},
+            "after": {"can_view": "solution", "can_grant_view": "none", "can_watch": "none", "can_edit": "none", "is_owner": false}
+          },
+          {
+            "item_id": "103",
+            "before": {"can_view": "info", "can_grant_view": "none", "can_watch": "none", "can_edit": "none", "is_owner": false},
+            "after": {"can_view": "content", "can_grant_view": "none", "can_watch": "none", "can_edit": "none", "is_owner": false}
+          }
+        ],
+        "sample_group_ids": ["23"]
+      }
+    }
+    """
+    And the table "permissions_granted" should stay unchanged
+    And the table "permissions_generated" should stay unchanged
+    And the table "permissions_propagate" should be empty
+    And the table "results_propagate" should be empty
@@ -1,7 +1,9 @@
 package groups
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/France-ioi/validator"
@@ -12,6 +14,7 @@ import (
 	"github.com/France-ioi/AlgoreaBackend/v2/app/domain"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/structures"
 )
 
 // Access rights to be set
@@ -44,6 +47,12 @@ type updatePermissionsInput struct {
 	// The current user should have permissions_generated.can_grant_view_generated >= 'enter' in order to
 	// increase this field's value.
 	CanEnterUntil time.Time `json:"can_enter_until" validate:"can_enter_until"`
+	// Time from which `can_view` is granted (before this time, the row gives no `can_view` permission).
+	// The current user should be able to give the granted `can_view` in order to decrease this field's value.
+	CanViewFrom time.Time `json:"can_view_from" validate:"can_view_from"`
+	// Time until which `can_view` is granted (after this time, the row gives no `can_view` permission).
+	// The current user should be able to give the granted `can_view` in order to increase this field's value.
+	CanViewUntil time.Time `json:"can_view_until" validate:"can_view_until"`
 	// Optional
 	// The current user should have `permissions_generated.can_grant_view` >= 'content',
 	// in order to set this field's value.
@@ -68,11 +77,44 @@ type userPermissions struct {
 	CanEditValue           int
 	CanEnterFrom           database.Time
 	CanEnterUntil          database.Time
+	CanViewFrom            database.Time
+	CanViewUntil           database.Time
 	CanMakeSessionOfficial bool
 	IsOwner                bool
 	CanRequestHelpTo       *int64
 }
 
+// The changes of the generated permissions of `{group_id}` on an item.
+type permissionsGeneratedChange struct {
+	// required: true
+	ItemID int64 `json:"item_id,string"`
+	// required: true
+	Before structures.ItemPermissions `json:"before"`
+	// required: true
+	After structures.ItemPermissions `json:"after"`
+}
+
+// swagger:model permissionsChangePreview
+type permissionsChangePreview struct {
+	// The number of items (`{item_id}` and its descendants) on which the generated permissions of `{group_id}` would change
+	// required: true
+	ItemsCount int `json:"items_count"`
+	// The number of groups (`{group_id}` and its descendants, including users)
+	// whose aggregated generated permissions on at least one item would change
+	// required: true
+	GroupsCount int `json:"groups_count"`
+	// The first changes ordered by item id
+	// required: true
+	SampleChanges []permissionsGeneratedChange `json:"sample_changes"`
+	// Ids of the first affected groups ordered by id
+	// required: true
+	SampleGroupIDs []string `json:"sample_group_ids"`
+}
+
+const permissionsChangePreviewSamplesLimit = 10
+
+var errPermissionsDryRun = errors.New("dry run")
+
 type managerGeneratedPermissions struct {
 	CanGrantViewGeneratedValue int
 	CanWatchGeneratedValue     int
@@ -94,6 +136,14 @@ type managerGeneratedPermissions struct {
 //
 //		* The group must already have access to one of the parents of the item or the item itself. If it does not,
 //			the item must be a root activity/skill for an ancestor of the group.
+//
+//		`can_view_from` & `can_view_until` define the time window during which the granted `can_view` is effective.
+//		The windows are taken into account by the permissions propagation which is triggered by the
+//		`update-permissions-windows` command when a window opens or closes.
+//
+//		If `dry_run` = 1, the service computes the resulting changes of the generated permissions
+//		(propagating the permissions in a transaction which is rolled back) and returns their summary
+//		without applying anything.
 //	parameters:
 //		- name: group_id
 //			in: path
@@ -107,6 +157,11 @@ type managerGeneratedPermissions struct {
 //			in: path
 //			required: true
 //			type: integer
+//		- name: dry_run
+//			in: query
+//			type: integer
+//			enum: [0,1]
+//			default: 0
 //		- name: access rights information
 //			in: body
 //			required: true
@@ -114,7 +169,21 @@ type managerGeneratedPermissions struct {
 //				"$ref": "#/definitions/updatePermissionsInput"
 //	responses:
 //		"200":
-//			"$ref": "#/responses/updatedResponse"
+//			description: OK. Success response (with the summary of the changes if `dry_run` = 1)
+//			schema:
+//				type: object
+//				required: [success, message]
+//				properties:
+//					success:
+//						description: "true"
+//						type: boolean
+//						enum: [true]
+//					message:
+//						type: string
+//						enum: [updated, dry run]
+//					data:
+//						description: Only if `dry_run` = 1
+//						"$ref": "#/definitions/permissionsChangePreview"
 //		"400":
 //			"$ref": "#/responses/badRequestResponse"
 //		"401":
@@ -141,12 +210,22 @@ func (srv *Service) updatePermissions(w http.ResponseWriter, r *http.Request) se
 		return service.ErrInvalidRequest(err)
 	}
 
+	dryRun, err := service.ResolveURLQueryGetBoolFieldWithDefault(r, "dry_run", false)
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
 	user := srv.GetUser(r)
 
 	rawRequestData, apiErr := service.ResolveJSONBodyIntoMap(r)
 	service.MustBeNoError(apiErr)
 
+	preview := &database.PermissionsChangePreview{}
 	err = srv.GetStore(r).InTransaction(func(s *database.DataStore) error {
+		if dryRun {
+			service.MustNotBeError(s.SetPropagationsModeToSync())
+		}
+
 		apiErr = checkIfUserIsManagerAllowedToGrantPermissionsOnItem(s, user, sourceGroupID, groupID, itemID)
 		if apiErr != service.NoError {
 			return apiErr.Error
@@ -169,6 +248,8 @@ func (srv *Service) updatePermissions(w http.ResponseWriter, r *http.Request) se
 				IFNULL(MAX(can_make_session_official), 0) AS can_make_session_official,
 				IFNULL(MAX(can_enter_from), '9999-12-31 23:59:59') AS can_enter_from,
 				IFNULL(MAX(can_enter_until), '9999-12-31 23:59:59') AS can_enter_until,
+				IFNULL(MAX(can_view_from), '1000-01-01 00:00:00') AS can_view_from,
+				IFNULL(MAX(can_view_until), '9999-12-31 23:59:59') AS can_view_until,
 				MAX(can_request_help_to) AS can_request_help_to,
 				IFNULL(MAX(is_owner), 0) AS is_owner`).
 			Scan(&currentPermissions).Error()
@@ -205,7 +286,18 @@ func (srv *Service) updatePermissions(w http.ResponseWriter, r *http.Request) se
 				dataMap["can_request_help_to"] = &allUsersGroupID
 			}
 
-			savePermissionsIntoDB(groupID, itemID, sourceGroupID, dataMap, s)
+			if !dryRun {
+				savePermissionsIntoDB(groupID, itemID, sourceGroupID, dataMap, s)
+				return nil
+			}
+
+			preview, err = s.PermissionsGranted().PreviewChange(groupID, itemID, permissionsChangePreviewSamplesLimit, func() {
+				savePermissionsIntoDB(groupID, itemID, sourceGroupID, dataMap, s)
+			})
+			service.MustNotBeError(err)
+		}
+		if dryRun {
+			return errPermissionsDryRun // rollback
 		}
 		return nil
 	})
@@ -214,6 +306,16 @@ func (srv *Service) updatePermissions(w http.ResponseWriter, r *http.Request) se
 		return apiErr
 	}
 
+	if dryRun {
+		if !errors.Is(err, errPermissionsDryRun) {
+			service.MustNotBeError(err)
+		}
+		render.Respond(w, r, &service.Response[*permissionsChangePreview]{
+			Success: true, Message: "dry run", Data: permissionsChangePreviewFromDatabase(srv.GetStore(r), preview),
+		})
+		return service.NoError
+	}
+
 	service.MustNotBeError(err)
 
 	response := service.Response[*struct{}]{Success: true, Message: "updated"}
@@ -222,6 +324,38 @@ func (srv *Service) updatePermissions(w http.ResponseWriter, r *http.Request) se
 	return service.NoError
 }
 
+func permissionsChangePreviewFromDatabase(store *database.DataStore, preview *database.PermissionsChangePreview) *permissionsChangePreview {
+	permissionGrantedStore := store.PermissionsGranted()
+	itemPermissions := func(values *database.PermissionsGeneratedValues) structures.ItemPermissions {
+		return structures.ItemPermissions{
+			CanView:      permissionGrantedStore.ViewNameByIndex(values.CanViewGeneratedValue),
+			CanGrantView: permissionGrantedStore.GrantViewNameByIndex(values.CanGrantViewGeneratedValue),
+			CanWatch:     permissionGrantedStore.WatchNameByIndex(values.CanWatchGeneratedValue),
+			CanEdit:      permissionGrantedStore.EditNameByIndex(values.CanEditGeneratedValue),
+			IsOwner:      values.IsOwnerGenerated,
+		}
+	}
+
+	result := &permissionsChangePreview{
+		ItemsCount:     preview.ItemsCount,
+		GroupsCount:    preview.GroupsCount,
+		SampleChanges:  make([]permissionsGeneratedChange, 0, len(preview.SampleChanges)),
+		SampleGroupIDs: make([]string, 0, len(preview.SampleGroupIDs)),
+	}
+	for index := range preview.SampleChanges {
+		change := &preview.SampleChanges[index]
+		result.SampleChanges = append(result.SampleChanges, permissionsGeneratedChange{
+			ItemID: change.ItemID,
+			Before: itemPermissions(&change.Before),
+			After:  itemPermissions(&change.After),
+		})
+	}
+	for _, groupID := range preview.SampleGroupIDs {
+		result.SampleGroupIDs = append(result.SampleGroupIDs, strconv.FormatInt(groupID, 10))
+	}
+	return result
+}
+
 func registerOptionalValidator(data *formdata.FormData, tag, message string, validatorFunc func(fl validator.FieldLevel) bool) {
 	data.RegisterValidation(tag, data.ValidatorSkippingUnsetFields(validatorFunc))
 	data.RegisterTranslation(tag, message)
@@ -264,6 +398,8 @@ func registerPermissionsValidators(
 	registerCanMakeSessionOfficialValidator(data, managerPermissions, currentPermissions, &modified, s)
 	registerCanEnterFromValidator(data, managerPermissions, currentPermissions, &modified, s)
 	registerCanEnterUntilValidator(data, managerPermissions, currentPermissions, &modified, s)
+	registerCanViewFromValidator(data, managerPermissions, currentPermissions, &modified, s)
+	registerCanViewUntilValidator(data, managerPermissions, currentPermissions, &modified, s)
 	registerCanRequestHelpToSetValidator(data, currentPermissions, &modified)
 	registerCanRequestHelpToConsistentValidator(data)
 	registerCanRequestHelpToVisibleValidator(data, currentPermissions, user, groupID, s)
@@ -404,6 +540,38 @@ func registerCanEnterUntilValidator(
 	})
 }
 
+func registerCanViewFromValidator(data *formdata.FormData, managerPermissions *managerGeneratedPermissions,
+	currentPermissions *userPermissions, modified *bool, s *database.DataStore,
+) {
+	registerOptionalValidator(data, "can_view_from", "the value is not permitted", func(fl validator.FieldLevel) bool {
+		newValue := fl.Field().Interface().(time.Time)
+		if time.Time(currentPermissions.CanViewFrom).After(newValue) &&
+			!checkIfPossibleToExtendCanViewWindow(currentPermissions, managerPermissions, s) {
+			return false
+		}
+		if !newValue.Equal(time.Time(currentPermissions.CanViewFrom)) {
+			*modified = true
+		}
+		return true
+	})
+}
+
+func registerCanViewUntilValidator(data *formdata.FormData, managerPermissions *managerGeneratedPermissions,
+	currentPermissions *userPermissions, modified *bool, s *database.DataStore,
+) {
+	registerOptionalValidator(data, "can_view_until", "the value is not permitted", func(fl validator.FieldLevel) bool {
+		newValue := fl.Field().Interface().(time.Time)
+		if time.Time(currentPermissions.CanViewUntil).Before(newValue) &&
+			!checkIfPossibleToExtendCanViewWindow(currentPermissions, managerPermissions, s) {
+			return false
+		}
+		if !newValue.Equal(time.Time(currentPermissions.CanViewUntil)) {
+			*modified = true
+		}
+		return true
+	})
+}
+
 func registerCanRequestHelpToSetValidator(data *formdata.FormData, currentPermissions *userPermissions, modified *bool) {
 	registerOptionalValidator(
 		data,
@@ -693,6 +861,24 @@ func checkIfPossibleToModifyCanEnterUntil(canEnterUntilToSet time.Time, currentP
 	return store.PermissionsGranted().GrantViewIndexByName(enter) <= managerPermissions.CanGrantViewGeneratedValue
 }
 
+// checkIfPossibleToExtendCanViewWindow checks that the manager can give the granted `can_view`
+// (which is the new value of `can_view` if it is being changed as the validators of `can_view_from`/`can_view_until`
+// run after the validator of `can_view`).
+func checkIfPossibleToExtendCanViewWindow(currentPermissions *userPermissions,
+	managerPermissions *managerGeneratedPermissions, store *database.DataStore,
+) bool {
+	permissionGrantedStore := store.PermissionsGranted()
+	requiredGrantViewPermission := permissionGrantedStore.ViewNameByIndex(currentPermissions.CanViewValue)
+	switch requiredGrantViewPermission {
+	case none:
+		return true
+	case info: // no "info" in can_grant_view
+		requiredGrantViewPermission = enter
+	}
+
+	return permissionGrantedStore.GrantViewIndexByName(requiredGrantViewPermission) <= managerPermissions.CanGrantViewGeneratedValue
+}
+
 func correctPermissionsDataMap(store *database.DataStore, dataMap map[string]interface{}, currentPermissions *userPermissions) {
 	permissionGrantedStore := store.PermissionsGranted()
 
@@ -99,6 +99,19 @@ Feature: Change item access rights for a group - robustness
     And the table "permissions_granted" should stay unchanged
     And the table "permissions_generated" should stay unchanged
 
+  Scenario: Invalid dry_run
+    Given I am the user with id "21"
+    When I send a PUT request to "/groups/25/permissions/23/102?dry_run=abc" with the following body:
+    """
+    {
+      "can_view": "solution"
+    }
+    """
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for dry_run (should have a boolean value (0 or 1))"
+    And the table "permissions_granted" should stay unchanged
+    And the table "permissions_generated" should stay unchanged
+
   Scenario: Invalid can_view
     Given I am the user with id "21"
     When I send a PUT request to "/groups/26/permissions/23/102" with the following body:
@@ -116,6 +129,7 @@ Feature: Change item access rights for a group - robustness
         "can_view": ["can_view must be one of [none info content content_with_descendants solution]"]
       },
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "success": false
     }
     """
@@ -167,6 +181,37 @@ Feature: Change item access rights for a group - robustness
         "can_enter_until": ["the value is not permitted"]
       },
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
+      "success": false
+    }
+    """
+    And the table "permissions_granted" should stay unchanged
+    And the table "permissions_generated" should stay unchanged
+
+  Scenario: The user doesn't have enough rights to extend the can_view window
+    Given I am the user with id "31"
+    And the database table "permissions_granted" also has the following rows:
+      | group_id | item_id | can_view | can_view_from       | can_view_until      | source_group_id | latest_update_at    |
+      | 23       | 101     | info     | 2020-01-01 00:00:00 | 2030-01-01 00:00:00 | 25              | 2019-05-30 11:00:00 |
+    When I send a PUT request to "/groups/25/permissions/23/101" with the following body:
+    """
+    {
+      "can_view_from": "2019-01-01T00:00:00Z",
+      "can_view_until": "2031-01-01T00:00:00Z"
+    }
+    """
+    Then the response code should be 400
+    And the response error message should contain "Invalid input data"
+    And the response body should be, in JSON:
+    """
+    {
+      "error_text": "Invalid input data",
+      "errors": {
+        "can_view_from": ["the value is not permitted"],
+        "can_view_until": ["the value is not permitted"]
+      },
+      "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "success": false
     }
     """
@@ -67,6 +67,7 @@ Feature: Change item access rights for a group - can_request_help_to
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "can_request_help_to": ["cannot set can_request_help_to id and is_all_users_group at the same time"]
@@ -99,6 +100,7 @@ Feature: Change item access rights for a group - can_request_help_to
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "can_request_help_to": ["the current user doesn't have the right to update can_request_help_to"]
@@ -133,6 +135,7 @@ Feature: Change item access rights for a group - can_request_help_to
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "can_request_help_to": ["can_request_help_to is not visible either by the current-user or the groupID"]
@@ -161,6 +164,7 @@ Feature: Change item access rights for a group - can_request_help_to
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "can_request_help_to": ["can_request_help_to is not visible either by the current-user or the groupID"]
@@ -14,6 +14,7 @@ import (
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/token"
@@ -82,6 +83,8 @@ import (
 //			"$ref": "#/responses/notFoundResponse"
 //		"408":
 //			"$ref": "#/responses/requestTimeoutResponse"
+//		"429":
+//			"$ref": "#/responses/tooManyRequestsResponse"
 //		"500":
 //			"$ref": "#/responses/internalErrorResponse"
 func (srv *Service) askHint(w http.ResponseWriter, r *http.Request) service.APIError {
@@ -226,7 +229,7 @@ func (requestData *AskHintRequest) UnmarshalJSON(raw []byte) error {
 		return err
 	}
 	if wrapper.TaskToken == nil {
-		return errors.New("missing task_token")
+		return i18n.NewError("missing_parameter", "task_token")
 	}
 	requestData.TaskToken = &token.Task{PublicKey: requestData.publicKey}
 	if err := requestData.TaskToken.UnmarshalString(*wrapper.TaskToken); err != nil {
@@ -237,7 +240,7 @@ func (requestData *AskHintRequest) UnmarshalJSON(raw []byte) error {
 
 func (requestData *AskHintRequest) unmarshalHintToken(wrapper *askHintRequestWrapper) error {
 	if wrapper.HintRequestedToken == nil {
-		return errors.New("missing hint_requested")
+		return i18n.NewError("missing_parameter", "hint_requested")
 	}
 
 	itemID := requestData.TaskToken.Converted.LocalItemID
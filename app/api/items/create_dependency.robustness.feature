@@ -94,6 +94,7 @@ Feature: Create an item dependency - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "score": ["score must be 0 or greater"]
@@ -114,6 +115,7 @@ Feature: Create an item dependency - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "score": ["score must be 100 or less"]
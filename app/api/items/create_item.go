@@ -55,10 +55,15 @@ type Item struct {
 	// example: 838:59:59
 	Duration *string `json:"duration" validate:"omitempty,duration,cannot_be_set_for_skills,duration_requires_explicit_entry"`
 	// should be true when the duration is not null, cannot be set for skill items
-	RequiresExplicitEntry   bool `json:"requires_explicit_entry" validate:"cannot_be_set_for_skills,duration_requires_explicit_entry"`
-	ShowUserInfos           bool `json:"show_user_infos"`
-	UsesAPI                 bool `json:"uses_api"`
-	PromptToJoinGroupByCode bool `json:"prompt_to_join_group_by_code"`
+	RequiresExplicitEntry bool `json:"requires_explicit_entry" validate:"cannot_be_set_for_skills,duration_requires_explicit_entry"`
+	// MySQL time (max value is 838:59:59), the changes of results during this time before the end of a participation
+	// are hidden in the contest ranking for non-managers
+	// pattern: ^\d{1,3}:[0-5]?\d:[0-5]?\d$
+	// example: 01:00:00
+	RankingFreezeDuration   *string `json:"ranking_freeze_duration" validate:"omitempty,duration"`
+	ShowUserInfos           bool    `json:"show_user_infos"`
+	UsesAPI                 bool    `json:"uses_api"`
+	PromptToJoinGroupByCode bool    `json:"prompt_to_join_group_by_code"`
 }
 
 // ItemWithRequiredType represents common item fields plus the required type field.
@@ -239,6 +244,10 @@ func validateAndInsertItem(srv *Service, r *http.Request) (itemID int64, apiErro
 			return err // rollback
 		}
 
+		if formData.IsSet("parent") {
+			service.MustNotBeError(store.ItemRevisions().RecordInitialIfMissing(input.Parent.ItemID))
+		}
+
 		err = store.ItemItems().WithItemsRelationsLock(func(lockedStore *database.DataStore) error {
 			if formData.IsSet("parent") && !input.canCreateItemsRelationsWithoutCycles(lockedStore) {
 				apiError = service.ErrForbidden(errors.New("an item cannot become an ancestor of itself"))
@@ -266,10 +275,15 @@ func validateAndInsertItem(srv *Service, r *http.Request) (itemID int64, apiErro
 
 		setNewItemAsRootActivityOrSkill(store, formData, &input, itemID)
 
+		service.MustNotBeError(store.ItemRevisions().Record(itemID, user.GroupID))
+		if formData.IsSet("parent") {
+			service.MustNotBeError(store.ItemRevisions().Record(input.Parent.ItemID, user.GroupID))
+		}
+
 		return nil
 	})
 	if err == nil {
-		service.SchedulePropagation(store, srv.GetPropagationEndpoint(), []string{"permissions", "results"})
+		srv.SchedulePropagation(store, []string{"permissions", "results"})
 	}
 
 	return itemID, apiError, err
@@ -550,8 +564,8 @@ func (srv *Service) insertItem(store *database.DataStore, user *database.User, f
 
 	itemID, err := insertItemRow(store, itemMap)
 	if err != nil && database.IsDuplicateEntryError(err) {
-		return 0, service.ErrForbidden(formdata.FieldErrors{"text_id": []string{
-			"text_id must be unique",
+		return 0, service.ErrForbidden(formdata.FieldErrors{"text_id": []error{
+			errors.New("text_id must be unique"),
 		}})
 	}
 	service.MustNotBeError(err)
@@ -59,6 +59,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "type": ["missing field"]
@@ -88,6 +89,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "language_tag": ["missing field"]
@@ -117,6 +119,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "title": ["missing field"]
@@ -166,6 +169,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "language_tag": ["expected type 'string', got unconvertible type 'float64'"]
@@ -196,6 +200,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "language_tag": ["no such language"]
@@ -227,6 +232,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "parent.item_id": ["decoding error: strconv.ParseInt: parsing \"sfaewr20\": invalid syntax"]
@@ -257,6 +263,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "parent.item_id": ["should exist and the user should be able to manage its children"]
@@ -287,6 +294,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "parent.item_id": ["should exist and the user should be able to manage its children"]
@@ -362,6 +370,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "<field>": ["<error>"]
@@ -413,6 +422,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "parent.<field>": ["<error>"]
@@ -447,6 +457,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "type": ["type can be equal to 'Skill' only if the parent item is a skill"]
@@ -480,6 +491,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "children[0]": ["a skill cannot be a child of a non-skill item"]
@@ -516,6 +528,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "parent.item_id": ["parent item cannot be Task"]
@@ -552,6 +565,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "children": ["a task cannot have children items"]
@@ -586,6 +600,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "children[0].order": ["missing field"]
@@ -621,6 +636,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "children[0].<field>": ["<error>"]
@@ -666,6 +682,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Forbidden",
+        "error_code": "forbidden",
         "error_text": "<error>"
       }
       """
@@ -707,6 +724,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "children": ["children IDs should be unique and each should be visible to the user"]
@@ -741,6 +759,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "children": ["children IDs should be unique and each should be visible to the user"]
@@ -818,6 +837,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "<field>": ["cannot be set for skill items"]
@@ -853,6 +873,7 @@ Feature: Create item - robustness
       {
         "success": false,
         "message": "Forbidden",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "text_id": ["text_id must be unique"]
@@ -112,3 +112,7 @@ Feature: Delete an item
     And the table "results_propagate" should be empty
     And the table "answers" should stay unchanged but the rows with item_id "22" should be deleted
     And the table "filters" should stay unchanged
+    And the table "item_revisions" should be:
+      | item_id | revision | author_id |
+      | 21      | 1        | null      |
+      | 21      | 2        | 11        |
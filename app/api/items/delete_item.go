@@ -23,6 +23,7 @@ import (
 //		`items_ancestors` (by `child_item_id`), `items_items` (by `child_item_id`), `items_strings`,
 //		`permissions_generated`, `permissions_granted`, `permissions_propagate`, `results`
 //		linked to the item.
+//		As the parents of the item lose a child, new revisions of the parents are recorded.
 //
 //
 //		The authenticated user should be an owner of the `{item_id}`, otherwise the "forbidden" error is returned.
@@ -75,7 +76,21 @@ func (srv *Service) deleteItem(w http.ResponseWriter, r *http.Request) service.A
 			return apiErr.Error // rollback
 		}
 
-		return s.Items().DeleteItem(itemID)
+		var parentIDs []int64
+		service.MustNotBeError(s.ItemItems().Where("child_item_id = ?", itemID).WithExclusiveWriteLock().
+			Pluck("parent_item_id", &parentIDs).Error())
+		for _, parentID := range parentIDs {
+			service.MustNotBeError(s.ItemRevisions().RecordInitialIfMissing(parentID))
+		}
+
+		if err = s.Items().DeleteItem(itemID); err != nil {
+			return err
+		}
+
+		for _, parentID := range parentIDs {
+			service.MustNotBeError(s.ItemRevisions().Record(parentID, user.GroupID))
+		}
+		return nil
 	})
 
 	if apiErr != service.NoError {
@@ -130,6 +130,7 @@ Feature: Delete an item - robustness
     {
       "success": false,
       "message": "Unprocessable Entity",
+      "error_code": "unprocessable_entity",
       "error_text": "The item must not have children"
     }
     """
@@ -0,0 +1,74 @@
+Feature: Export an item with its descendants
+  Background:
+    Given the database has the following users:
+      | group_id | login |
+      | 11       | jdoe  |
+      | 12       | jane  |
+    And the database has the following table "languages":
+      | tag |
+      | en  |
+      | fr  |
+    And the database has the following table "items":
+      | id | type    | url              | text_id | no_score | default_language_tag |
+      | 50 | Chapter | null             | chapter | 0        | fr                   |
+      | 60 | Task    | http://task1.com | task1   | 1        | fr                   |
+      | 70 | Task    | http://task2.com | null    | 0        | en                   |
+      | 80 | Task    | http://task3.com | task3   | 0        | fr                   |
+    And the database has the following table "items_strings":
+      | item_id | language_tag | title    |
+      | 50      | en           | Chapter  |
+      | 50      | fr           | Chapitre |
+      | 60      | fr           | Tâche 1  |
+      | 70      | en           | Task 2   |
+      | 80      | fr           | Tâche 3  |
+    And the database has the following table "items_items":
+      | parent_item_id | child_item_id | child_order | category   | content_view_propagation |
+      | 50             | 60            | 1           | Validation | as_info                  |
+      | 50             | 70            | 2           | Undefined  | as_content               |
+      | 80             | 60            | 1           | Undefined  | none                     |
+    And the database has the following table "items_ancestors":
+      | ancestor_item_id | child_item_id |
+      | 50               | 60            |
+      | 50               | 70            |
+      | 80               | 60            |
+    And the database has the following table "item_dependencies":
+      | item_id | dependent_item_id | score | grant_content_view |
+      | 60      | 70                | 50    | 1                  |
+      | 80      | 70                | 100   | 0                  |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated       | can_edit_generated |
+      | 11       | 50      | content                  | all                |
+      | 11       | 60      | content                  | none               |
+      | 11       | 70      | content_with_descendants | none               |
+      | 11       | 80      | info                     | none               |
+      | 12       | 80      | content                  | all                |
+
+  Scenario: Export an item with its descendants
+    Given I am the user with id "11"
+    When I send a GET request to "/items/50/export"
+    Then the response code should be 200
+    And the response header "Content-Disposition" should be "attachment; filename=item_50_export.json"
+    And the response at $.version should be "1"
+    And the response at $.root_item_id should be "50"
+    And the response at $.items[*] should be:
+      | id | type    | properties.url   | properties.text_id | properties.no_score | properties.default_language_tag | strings.fr.title | strings.en.title |
+      | 50 | Chapter | <null>           | chapter            | 0                   | fr                              | Chapitre         | Chapter          |
+      | 60 | Task    | http://task1.com | task1              | 1                   | fr                              | Tâche 1          | <undefined>      |
+      | 70 | Task    | http://task2.com | <null>             | 0                   | en                              | <undefined>      | Task 2           |
+    And the response at $.relations[*] should be:
+      | parent_item_id | child_item_id | properties.child_order | properties.category | properties.content_view_propagation |
+      | 50             | 60            | 1                      | Validation          | as_info                             |
+      | 50             | 70            | 2                      | Undefined           | as_content                          |
+    And the response at $.dependencies[*] should be:
+      | item_id | dependent_item_id | score | grant_content_view |
+      | 60      | 70                | 50    | true               |
+
+  Scenario: Export an item without descendants
+    Given I am the user with id "12"
+    When I send a GET request to "/items/80/export"
+    Then the response code should be 200
+    And the response at $.items[*] should be:
+      | id | type | properties.text_id | strings.fr.title |
+      | 80 | Task | task3              | Tâche 3          |
+    And the response at $.relations should be "[]"
+    And the response at $.dependencies should be "[]"
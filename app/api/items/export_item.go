@@ -0,0 +1,126 @@
+package items
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:operation GET /items/{item_id}/export items itemExport
+//
+//	---
+//	summary: Export an item with its descendants
+//	description: >
+//
+//		Returns a JSON archive of the item and all its descendants to be imported with `itemsImport`
+//		(for instance, on another instance of the platform). The archive contains:
+//
+//		* the items with their types, their properties (the ones that can be changed by `itemUpdate`,
+//			including `text_id`), and their strings in all languages;
+//
+//		* the `items_items` relations between the items with their ordering and propagation settings;
+//
+//		* the `item_dependencies` between the items.
+//
+//
+//		Ids in the archive are the ids of the items on this instance. They are replaced by new ids on import.
+//
+//
+//		The user should have `can_view` >= 'content' and `can_edit` >= 'all' on the item
+//		and `can_view` >= 'content' on all its descendants, otherwise the "forbidden" response is returned.
+//	parameters:
+//		- name: item_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//	produces:
+//		- application/json
+//	responses:
+//		"200":
+//			description: OK. Success response with the archive
+//			headers:
+//				Content-Disposition:
+//					type: string
+//					description: "attachment; filename=item_{item_id}_export.json"
+//			schema:
+//				type: object
+//				properties:
+//					version:
+//						type: integer
+//						enum: [1]
+//					root_item_id:
+//						type: string
+//						format: int64
+//					items:
+//						type: array
+//						items:
+//							type: object
+//					relations:
+//						type: array
+//						items:
+//							type: object
+//					dependencies:
+//						type: array
+//						items:
+//							type: object
+//				required: [version, root_item_id, items, relations, dependencies]
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) exportItem(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	itemID, err := service.ResolveURLQueryPathInt64Field(r, "item_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	found, err := store.Permissions().MatchingUserAncestors(user).
+		Where("item_id = ?", itemID).
+		WherePermissionIsAtLeast("view", "content").
+		WherePermissionIsAtLeast("edit", "all").
+		HasRows()
+	service.MustNotBeError(err)
+	if !found {
+		return service.InsufficientAccessRightsError
+	}
+
+	archive, err := store.Items().ExportSubtree(itemID)
+	service.MustNotBeError(err)
+
+	if !userCanViewContentOfAllItemsOfArchive(store, user, archive) {
+		return service.ErrForbidden(errors.New("no access to the content of some descendants of the item"))
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=item_%d_export.json", itemID))
+	render.Respond(w, r, archive)
+	return service.NoError
+}
+
+func userCanViewContentOfAllItemsOfArchive(store *database.DataStore, user *database.User, archive *database.ItemArchive) bool {
+	itemIDs := make([]int64, 0, len(archive.Items))
+	for index := range archive.Items {
+		itemIDs = append(itemIDs, archive.Items[index].ID)
+	}
+
+	var visibleItemsCount int
+	service.MustNotBeError(store.Permissions().MatchingUserAncestors(user).
+		Where("item_id IN (?)", itemIDs).
+		WherePermissionIsAtLeast("view", "content").
+		PluckFirst("COUNT(DISTINCT item_id)", &visibleItemsCount).Error())
+	return visibleItemsCount == len(itemIDs)
+}
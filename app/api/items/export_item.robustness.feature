@@ -0,0 +1,62 @@
+Feature: Export an item with its descendants - robustness
+  Background:
+    Given the database has the following users:
+      | group_id | login |
+      | 11       | jdoe  |
+      | 12       | jane  |
+      | 13       | john  |
+    And the database has the following table "languages":
+      | tag |
+      | fr  |
+    And the database has the following table "items":
+      | id | type    | default_language_tag |
+      | 50 | Chapter | fr                   |
+      | 60 | Task    | fr                   |
+    And the database has the following table "items_strings":
+      | item_id | language_tag | title    |
+      | 50      | fr           | Chapitre |
+      | 60      | fr           | Tâche    |
+    And the database has the following table "items_items":
+      | parent_item_id | child_item_id | child_order |
+      | 50             | 60            | 1           |
+    And the database has the following table "items_ancestors":
+      | ancestor_item_id | child_item_id |
+      | 50               | 60            |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_edit_generated |
+      | 11       | 50      | content            | all                |
+      | 11       | 60      | info               | none               |
+      | 12       | 50      | content            | children           |
+      | 12       | 60      | content            | none               |
+      | 13       | 50      | info               | all                |
+      | 13       | 60      | content            | none               |
+
+  Scenario: Wrong item_id
+    Given I am the user with id "11"
+    When I send a GET request to "/items/abc/export"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for item_id (should be int64)"
+
+  Scenario: The item doesn't exist
+    Given I am the user with id "11"
+    When I send a GET request to "/items/404/export"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The user cannot edit all the properties of the item
+    Given I am the user with id "12"
+    When I send a GET request to "/items/50/export"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The user cannot view the content of the item
+    Given I am the user with id "13"
+    When I send a GET request to "/items/50/export"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: The user cannot view the content of a descendant
+    Given I am the user with id "11"
+    When I send a GET request to "/items/50/export"
+    Then the response code should be 403
+    And the response error message should contain "No access to the content of some descendants of the item"
@@ -9,6 +9,7 @@ import (
 	"github.com/go-chi/render"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/structures"
 	"github.com/France-ioi/AlgoreaBackend/v2/golang"
@@ -331,7 +332,7 @@ func (srv *Service) constructActivityLogQuery(store *database.DataStore, r *http
 
 	if watchedGroupIDIsSet {
 		if len(r.URL.Query()["as_team_id"]) != 0 {
-			return nil, service.ErrInvalidRequest(errors.New("only one of as_team_id and watched_group_id can be given"))
+			return nil, service.ErrInvalidRequest(i18n.NewError("both_team_and_watched_group"))
 		}
 		participantsQuery = store.ActiveGroupAncestors().Where("ancestor_group_id = ?", watchedGroupID).
 			Select("child_group_id AS id")
@@ -0,0 +1,99 @@
+package items
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:model itemRevisionViewResponse
+type itemRevisionViewResponse struct {
+	itemRevisionsListResponseRow
+
+	// State of the item after the change: its properties (`item`), its strings by language tag (`strings`),
+	// and the properties of its children relations by child item id (`children`)
+	// required: true
+	// example: {"item": {"url": null, "no_score": 0}, "strings": {"fr": {"title": "Chapitre 1"}}, "children": {}}
+	Snapshot json.RawMessage `json:"snapshot"`
+
+	SnapshotJSON string `json:"-"`
+}
+
+// swagger:operation GET /items/{item_id}/revisions/{revision} items itemRevisionView
+//
+//	---
+//	summary: Get a revision of an item
+//	description: >
+//
+//		Returns the revision of the item with the state of the item after the change.
+//
+//
+//		The user should have `can_view` >= 'content' and `can_edit` >= 'children' on the item,
+//		otherwise the "forbidden" response is returned.
+//	parameters:
+//		- name: item_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: revision
+//			in: path
+//			type: integer
+//			format: int32
+//			required: true
+//	responses:
+//		"200":
+//			description: OK. Success response with the revision
+//			schema:
+//				"$ref": "#/definitions/itemRevisionViewResponse"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"404":
+//			"$ref": "#/responses/notFoundResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) getItemRevision(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	itemID, err := service.ResolveURLQueryPathInt64Field(r, "item_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+	revision, err := service.ResolveURLQueryPathInt64Field(r, "revision")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	if !userCanViewItemRevisions(store, user, itemID) {
+		return service.InsufficientAccessRightsError
+	}
+
+	var result itemRevisionViewResponse
+	err = itemRevisionsQuery(store, itemID).
+		Where("item_revisions.revision = ?", revision).
+		Select(`
+			item_revisions.revision, item_revisions.created_at, item_revisions.author_id, users.login AS author_login,
+			CAST(item_revisions.diff AS CHAR) AS diff_json, CAST(item_revisions.snapshot AS CHAR) AS snapshot_json`).
+		Take(&result).Error()
+	if gorm.IsRecordNotFoundError(err) {
+		return service.ErrNotFound(errors.New("no such revision"))
+	}
+	service.MustNotBeError(err)
+	result.Diff = json.RawMessage(result.DiffJSON)
+	result.Snapshot = json.RawMessage(result.SnapshotJSON)
+
+	render.Respond(w, r, &result)
+	return service.NoError
+}
@@ -0,0 +1,236 @@
+Feature: Import items from an archive
+  Background:
+    Given the database has the following user:
+      | group_id | login |
+      | 11       | jdoe  |
+    And the database has the following table "languages":
+      | tag |
+      | en  |
+      | fr  |
+    And the database has the following table "items":
+      | id | type    | text_id | default_language_tag |
+      | 21 | Chapter | null    | fr                   |
+      | 22 | Task    | task    | fr                   |
+    And the database has the following table "items_strings":
+      | item_id | language_tag | title    |
+      | 21      | fr           | Chapitre |
+      | 22      | fr           | Tâche    |
+    And the database has the following table "items_items":
+      | parent_item_id | child_item_id | child_order |
+      | 21             | 22            | 1           |
+    And the database has the following table "items_ancestors":
+      | ancestor_item_id | child_item_id |
+      | 21               | 22            |
+    And the database has the following table "permissions_granted":
+      | group_id | item_id | source_group_id | can_view | can_edit |
+      | 11       | 21      | 11              | content  | children |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_edit_generated |
+      | 11       | 21      | content            | children           |
+
+  Scenario Outline: Import items
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {
+        "parent_item_id": "21",
+        "on_text_id_conflict": "<on_text_id_conflict>",
+        "archive": {
+          "version": 1,
+          "root_item_id": "100",
+          "items": [
+            {
+              "id": "100", "type": "Chapter",
+              "properties": {"url": null, "text_id": "imported-chapter", "no_score": 1, "default_language_tag": "en"},
+              "strings": {"en": {"title": "Chapter", "subtitle": null}, "fr": {"title": "Chapitre importé", "subtitle": "Sous-titre"}}
+            },
+            {
+              "id": "101", "type": "Task",
+              "properties": {"url": "http://task1.com", "text_id": "task", "no_score": 0, "default_language_tag": "fr"},
+              "strings": {"fr": {"title": "Tâche 1"}}
+            },
+            {
+              "id": "102", "type": "Task",
+              "properties": {"url": "http://task2.com", "text_id": null, "no_score": 0, "default_language_tag": "fr"},
+              "strings": {"fr": {"title": "Tâche 2"}}
+            }
+          ],
+          "relations": [
+            {
+              "parent_item_id": "100", "child_item_id": "101",
+              "properties": {"child_order": 1, "category": "Validation", "score_weight": 2, "content_view_propagation": "as_content",
+                             "upper_view_levels_propagation": "use_content_view_propagation", "grant_view_propagation": 0,
+                             "watch_propagation": 1, "edit_propagation": 0, "request_help_propagation": 1}
+            },
+            {
+              "parent_item_id": "100", "child_item_id": "102",
+              "properties": {"child_order": 2, "category": "Undefined", "score_weight": 1, "content_view_propagation": "none",
+                             "upper_view_levels_propagation": "as_is", "grant_view_propagation": 1,
+                             "watch_propagation": 0, "edit_propagation": 1, "request_help_propagation": 0}
+            }
+          ],
+          "dependencies": [
+            {"item_id": "101", "dependent_item_id": "102", "score": 50, "grant_content_view": true}
+          ]
+        }
+      }
+      """
+    Then the response code should be 201
+    And the response body should be, in JSON:
+      """
+      {
+        "success": true,
+        "message": "created",
+        "data": {
+          "dry_run": false,
+          "items_count": 3,
+          "relations_count": 2,
+          "dependencies_count": 1,
+          "text_id_conflicts": [{"item_id": "101", "text_id": "task", "new_text_id": <new_text_id_in_response>}],
+          "id_mapping": {"100": "5577006791947779410", "101": "8674665223082153551", "102": "6129484611666145821"}
+        }
+      }
+      """
+    And the table "items" should be:
+      | id                  | type    | url              | text_id          | no_score | default_language_tag | participants_group_id |
+      | 21                  | Chapter | null             | null             | 0        | fr                   | null                  |
+      | 22                  | Task    | null             | task             | 0        | fr                   | null                  |
+      | 5577006791947779410 | Chapter | null             | imported-chapter | 1        | en                   | null                  |
+      | 6129484611666145821 | Task    | http://task2.com | null             | 0        | fr                   | null                  |
+      | 8674665223082153551 | Task    | http://task1.com | <new_text_id>    | 0        | fr                   | null                  |
+    And the table "items_strings" should be:
+      | item_id             | language_tag | title            | subtitle   |
+      | 21                  | fr           | Chapitre         | null       |
+      | 22                  | fr           | Tâche            | null       |
+      | 5577006791947779410 | en           | Chapter          | null       |
+      | 5577006791947779410 | fr           | Chapitre importé | Sous-titre |
+      | 6129484611666145821 | fr           | Tâche 2          | null       |
+      | 8674665223082153551 | fr           | Tâche 1          | null       |
+    And the table "items_items" at child_item_id "5577006791947779410" should be:
+      | parent_item_id | child_item_id       | child_order | category  | score_weight | content_view_propagation | upper_view_levels_propagation | grant_view_propagation | watch_propagation | edit_propagation | request_help_propagation |
+      | 21             | 5577006791947779410 | 2           | Undefined | 1            | as_info                  | as_is                         | 1                      | 1                 | 1                | 1                        |
+    And the table "items_items" at parent_item_id "5577006791947779410" should be:
+      | parent_item_id      | child_item_id       | child_order | category   | score_weight | content_view_propagation | upper_view_levels_propagation | grant_view_propagation | watch_propagation | edit_propagation | request_help_propagation |
+      | 5577006791947779410 | 6129484611666145821 | 2           | Undefined  | 1            | none                     | as_is                         | 1                      | 0                 | 1                | 0                        |
+      | 5577006791947779410 | 8674665223082153551 | 1           | Validation | 2            | as_content               | use_content_view_propagation  | 0                      | 1                 | 0                | 1                        |
+    And the table "items_ancestors" should be:
+      | ancestor_item_id    | child_item_id       |
+      | 21                  | 22                  |
+      | 21                  | 5577006791947779410 |
+      | 21                  | 6129484611666145821 |
+      | 21                  | 8674665223082153551 |
+      | 5577006791947779410 | 6129484611666145821 |
+      | 5577006791947779410 | 8674665223082153551 |
+    And the table "item_dependencies" should be:
+      | item_id             | dependent_item_id   | score | grant_content_view |
+      | 8674665223082153551 | 6129484611666145821 | 50    | 1                  |
+    And the table "permissions_granted" should be:
+      | group_id | item_id             | source_group_id | origin           | can_view | can_edit | is_owner |
+      | 11       | 21                  | 11              | group_membership | content  | children | 0        |
+      | 11       | 5577006791947779410 | 11              | self             | none     | none     | 1        |
+      | 11       | 6129484611666145821 | 11              | self             | none     | none     | 1        |
+      | 11       | 8674665223082153551 | 11              | self             | none     | none     | 1        |
+    And the table "item_revisions" should be:
+      | item_id             | revision | author_id |
+      | 21                  | 1        | null      |
+      | 21                  | 2        | 11        |
+      | 5577006791947779410 | 1        | 11        |
+      | 6129484611666145821 | 1        | 11        |
+      | 8674665223082153551 | 1        | 11        |
+  Examples:
+    | on_text_id_conflict | new_text_id   | new_text_id_in_response |
+    | rename              | task-imported | "task-imported"         |
+    | clear               | null          | null                    |
+
+  Scenario: Dry run
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {
+        "parent_item_id": "21",
+        "dry_run": true,
+        "archive": {
+          "version": 1,
+          "root_item_id": "100",
+          "items": [
+            {
+              "id": "100", "type": "Chapter",
+              "properties": {"text_id": "imported-chapter", "default_language_tag": "fr"},
+              "strings": {"fr": {"title": "Chapitre importé"}}
+            },
+            {
+              "id": "101", "type": "Task",
+              "properties": {"text_id": "task", "default_language_tag": "fr"},
+              "strings": {"fr": {"title": "Tâche 1"}}
+            }
+          ],
+          "relations": [{"parent_item_id": "100", "child_item_id": "101", "properties": {"child_order": 1}}],
+          "dependencies": []
+        }
+      }
+      """
+    Then the response code should be 200
+    And the response body should be, in JSON:
+      """
+      {
+        "dry_run": true,
+        "items_count": 2,
+        "relations_count": 1,
+        "dependencies_count": 0,
+        "text_id_conflicts": [{"item_id": "101", "text_id": "task", "new_text_id": null}]
+      }
+      """
+    And the table "items" should stay unchanged
+    And the table "items_strings" should stay unchanged
+    And the table "items_items" should stay unchanged
+    And the table "permissions_granted" should stay unchanged
+    And the table "item_revisions" should be empty
+
+  Scenario: Import an item requiring explicit entry
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {
+        "parent_item_id": "21",
+        "archive": {
+          "version": 1,
+          "root_item_id": "100",
+          "items": [
+            {
+              "id": "100", "type": "Task",
+              "properties": {"requires_explicit_entry": 1, "duration": "01:00:00", "default_language_tag": "fr"},
+              "strings": {"fr": {"title": "Concours"}}
+            }
+          ],
+          "relations": [],
+          "dependencies": []
+        }
+      }
+      """
+    Then the response code should be 201
+    And the response body should be, in JSON:
+      """
+      {
+        "success": true,
+        "message": "created",
+        "data": {
+          "dry_run": false,
+          "items_count": 1,
+          "relations_count": 0,
+          "dependencies_count": 0,
+          "text_id_conflicts": [],
+          "id_mapping": {"100": "5577006791947779410"}
+        }
+      }
+      """
+    And the table "items" at id "5577006791947779410" should be:
+      | id                  | type | requires_explicit_entry | duration | participants_group_id |
+      | 5577006791947779410 | Task | 1                       | 01:00:00 | 8674665223082153551   |
+    And the table "groups" at id "8674665223082153551" should be:
+      | id                  | type                | name                             |
+      | 8674665223082153551 | ContestParticipants | 5577006791947779410-participants |
+    And the table "permissions_granted" should be:
+      | group_id            | item_id             | source_group_id     | origin           | can_view | is_owner |
+      | 11                  | 21                  | 11                  | group_membership | content  | 0        |
+      | 11                  | 5577006791947779410 | 11                  | self             | none     | 1        |
+      | 8674665223082153551 | 5577006791947779410 | 8674665223082153551 | group_membership | content  | 0        |
@@ -0,0 +1,239 @@
+package items
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// itemsImportRequest is the expected input for importing items
+// swagger:model itemsImportRequest
+type itemsImportRequest struct {
+	// required: true
+	ParentItemID int64 `json:"parent_item_id,string"`
+	// The archive produced by `itemExport`
+	// required: true
+	Archive *database.ItemArchive `json:"archive"`
+	// What to do when a `text_id` of the archive is already used by an existing item:
+	// fail, set the `text_id` of the imported item to null, or add a suffix
+	// ("-imported", "-imported-2", ...) to the `text_id` of the imported item
+	// enum: fail,clear,rename
+	// default: fail
+	OnTextIDConflict string `json:"on_text_id_conflict"`
+	// If true, the archive is only checked and the report is returned without importing anything
+	DryRun bool `json:"dry_run"`
+}
+
+// Bind of itemsImportRequest checks the required fields and sets the defaults.
+func (requestData *itemsImportRequest) Bind(_ *http.Request) error {
+	if requestData.ParentItemID == 0 {
+		return errors.New("parent_item_id is required")
+	}
+	if requestData.Archive == nil {
+		return errors.New("archive is required")
+	}
+	switch requestData.OnTextIDConflict {
+	case "":
+		requestData.OnTextIDConflict = database.ItemArchiveTextIDConflictFail
+	case database.ItemArchiveTextIDConflictFail, database.ItemArchiveTextIDConflictClear, database.ItemArchiveTextIDConflictRename:
+	default:
+		return fmt.Errorf("on_text_id_conflict should be one of %q", []string{
+			database.ItemArchiveTextIDConflictFail, database.ItemArchiveTextIDConflictClear, database.ItemArchiveTextIDConflictRename,
+		})
+	}
+	return nil
+}
+
+type itemsImportTextIDConflict struct {
+	// required: true
+	ItemID string `json:"item_id"`
+	// required: true
+	TextID string `json:"text_id"`
+	// The `text_id` given to the imported item (null if it is set to null or if the import fails)
+	// required: true
+	NewTextID *string `json:"new_text_id"`
+}
+
+// swagger:model itemsImportReport
+type itemsImportReport struct {
+	// required: true
+	DryRun bool `json:"dry_run"`
+	// required: true
+	ItemsCount int `json:"items_count"`
+	// required: true
+	RelationsCount int `json:"relations_count"`
+	// required: true
+	DependenciesCount int `json:"dependencies_count"`
+	// required: true
+	TextIDConflicts []itemsImportTextIDConflict `json:"text_id_conflicts"`
+	// New ids of the imported items by their ids in the archive (only if `dry_run` is false)
+	// example: {"1234": "5678"}
+	IDMapping map[string]string `json:"id_mapping,omitempty"`
+}
+
+// swagger:operation POST /items/import items itemsImport
+//
+//	---
+//	summary: Import items from an archive
+//	description: >
+//
+//		Recreates the items of an archive produced by `itemExport` with new ids.
+//		The root item of the archive becomes the last child of the given parent item
+//		(with the same propagation settings as the ones `itemCreate` uses by default).
+//
+//
+//		For each imported item, the service:
+//
+//		* inserts the item with its properties and its strings in all languages,
+//
+//		* gives the current user ownership of the item,
+//
+//		* creates a participants group if the item requires explicit entry,
+//
+//		* records a revision of the item.
+//
+//
+//		The relations and the dependencies between the imported items are recreated with the new ids.
+//		Then the service recomputes the items ancestors and runs the propagations.
+//
+//
+//		If `dry_run` is true, the archive is only checked and the service returns the report
+//		(including `text_id` conflicts) without importing anything.
+//
+//
+//		Restrictions:
+//
+//		* the user should have `can_view` >= 'content' and `can_edit` >= 'children' on the parent item,
+//			otherwise the "forbidden" response is returned;
+//
+//		* the archive should be consistent and the root item of the archive should be allowed to become a child
+//			of the parent item (a task cannot have children, a skill can only be a child of a skill),
+//			otherwise the "bad request" response is returned;
+//
+//		* if `on_text_id_conflict` is 'fail' and some `text_id`s of the archive are already used,
+//			the "conflict" response is returned (when `dry_run` is false).
+//	parameters:
+//		- in: body
+//			name: data
+//			required: true
+//			description: The archive and the import options
+//			schema:
+//				"$ref": "#/definitions/itemsImportRequest"
+//	responses:
+//		"200":
+//			description: OK. The report of the dry run
+//			schema:
+//				"$ref": "#/definitions/itemsImportReport"
+//		"201":
+//			description: Created. Success response with the report of the import
+//			schema:
+//				type: object
+//				required: [success, message, data]
+//				properties:
+//					success:
+//						description: "true"
+//						type: boolean
+//						enum: [true]
+//					message:
+//						description: created
+//						type: string
+//						enum: [created]
+//					data:
+//						"$ref": "#/definitions/itemsImportReport"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"409":
+//			"$ref": "#/responses/conflictResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) importItems(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	var requestData itemsImportRequest
+	if err := render.Bind(r, &requestData); err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	var report *database.ItemArchiveImportReport
+	apiError := service.NoError
+	err := store.InTransaction(func(store *database.DataStore) error {
+		found, err := store.Permissions().MatchingUserAncestors(user).WithSharedWriteLock().
+			Where("item_id = ?", requestData.ParentItemID).
+			WherePermissionIsAtLeast("view", "content").
+			WherePermissionIsAtLeast("edit", "children").
+			HasRows()
+		service.MustNotBeError(err)
+		if !found {
+			apiError = service.ErrForbidden(errors.New("no access rights to edit the parent item"))
+			return apiError.Error // rollback
+		}
+
+		report, err = store.Items().ImportArchive(requestData.Archive, &database.ItemArchiveImportOptions{
+			ParentItemID:     requestData.ParentItemID,
+			OwnerGroupID:     user.GroupID,
+			OnTextIDConflict: requestData.OnTextIDConflict,
+			DryRun:           requestData.DryRun,
+		})
+		switch {
+		case errors.Is(err, database.ErrInvalidItemArchive):
+			apiError = service.ErrInvalidRequest(err)
+		case errors.Is(err, database.ErrItemArchiveTextIDConflict):
+			textIDs := make([]string, 0, len(report.TextIDConflicts))
+			for _, conflict := range report.TextIDConflicts {
+				textIDs = append(textIDs, conflict.TextID)
+			}
+			apiError = service.ErrConflict(fmt.Errorf("%w: %s", err, strings.Join(textIDs, ", ")))
+		}
+		return err
+	})
+	if apiError != service.NoError {
+		return apiError
+	}
+	service.MustNotBeError(err)
+
+	response := itemsImportReportFromDatabaseReport(report, requestData.DryRun)
+	if requestData.DryRun {
+		render.Respond(w, r, response)
+		return service.NoError
+	}
+	service.MustNotBeError(render.Render(w, r, service.CreationSuccess(response)))
+	return service.NoError
+}
+
+func itemsImportReportFromDatabaseReport(report *database.ItemArchiveImportReport, dryRun bool) *itemsImportReport {
+	response := &itemsImportReport{
+		DryRun:            dryRun,
+		ItemsCount:        report.ItemsCount,
+		RelationsCount:    report.RelationsCount,
+		DependenciesCount: report.DependenciesCount,
+		TextIDConflicts:   make([]itemsImportTextIDConflict, 0, len(report.TextIDConflicts)),
+	}
+	for _, conflict := range report.TextIDConflicts {
+		response.TextIDConflicts = append(response.TextIDConflicts, itemsImportTextIDConflict{
+			ItemID:    strconv.FormatInt(conflict.ItemID, 10),
+			TextID:    conflict.TextID,
+			NewTextID: conflict.NewTextID,
+		})
+	}
+	if !dryRun {
+		response.IDMapping = make(map[string]string, len(report.IDMapping))
+		for oldItemID, newItemID := range report.IDMapping {
+			response.IDMapping[strconv.FormatInt(oldItemID, 10)] = strconv.FormatInt(newItemID, 10)
+		}
+	}
+	return response
+}
@@ -0,0 +1,178 @@
+Feature: Import items from an archive - robustness
+  Background:
+    Given the database has the following users:
+      | group_id | login |
+      | 11       | jdoe  |
+      | 12       | jane  |
+    And the database has the following table "languages":
+      | tag |
+      | fr  |
+    And the database has the following table "items":
+      | id | type    | text_id | default_language_tag |
+      | 21 | Chapter | null    | fr                   |
+      | 22 | Task    | task    | fr                   |
+    And the database has the following table "items_strings":
+      | item_id | language_tag | title    |
+      | 21      | fr           | Chapitre |
+      | 22      | fr           | Tâche    |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_edit_generated |
+      | 11       | 21      | content            | children           |
+      | 11       | 22      | content            | children           |
+      | 12       | 21      | info               | all                |
+
+  Scenario: Invalid JSON
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {
+      """
+    Then the response code should be 400
+    And the table "items" should stay unchanged
+
+  Scenario: parent_item_id is missing
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {"archive": {"version": 1}}
+      """
+    Then the response code should be 400
+    And the response error message should contain "Parent_item_id is required"
+    And the table "items" should stay unchanged
+
+  Scenario: archive is missing
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {"parent_item_id": "21"}
+      """
+    Then the response code should be 400
+    And the response error message should contain "Archive is required"
+    And the table "items" should stay unchanged
+
+  Scenario: Wrong on_text_id_conflict
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {"parent_item_id": "21", "archive": {"version": 1}, "on_text_id_conflict": "ignore"}
+      """
+    Then the response code should be 400
+    And the response error message should contain "On_text_id_conflict should be one of"
+    And the table "items" should stay unchanged
+
+  Scenario: The user cannot edit the children of the parent item
+    Given I am the user with id "12"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {
+        "parent_item_id": "21",
+        "archive": {
+          "version": 1, "root_item_id": "100",
+          "items": [{"id": "100", "type": "Task", "properties": {"default_language_tag": "fr"}, "strings": {"fr": {"title": "Tâche"}}}],
+          "relations": [], "dependencies": []
+        }
+      }
+      """
+    Then the response code should be 403
+    And the response error message should contain "No access rights to edit the parent item"
+    And the table "items" should stay unchanged
+
+  Scenario: The parent item is a task
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {
+        "parent_item_id": "22",
+        "archive": {
+          "version": 1, "root_item_id": "100",
+          "items": [{"id": "100", "type": "Task", "properties": {"default_language_tag": "fr"}, "strings": {"fr": {"title": "Tâche"}}}],
+          "relations": [], "dependencies": []
+        }
+      }
+      """
+    Then the response code should be 400
+    And the response error message should contain "Invalid item archive: the root item cannot be a child of the parent item: a task cannot have children items"
+    And the table "items" should stay unchanged
+
+  Scenario: Unsupported version
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {"parent_item_id": "21", "archive": {"version": 2, "root_item_id": "100", "items": [], "relations": [], "dependencies": []}}
+      """
+    Then the response code should be 400
+    And the response error message should contain "Invalid item archive: unsupported version 2"
+    And the table "items" should stay unchanged
+
+  Scenario: Wrong value of an item property
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {
+        "parent_item_id": "21",
+        "archive": {
+          "version": 1, "root_item_id": "100",
+          "items": [{
+            "id": "100", "type": "Task", "properties": {"default_language_tag": "fr", "validation_type": "Some"},
+            "strings": {"fr": {"title": "Tâche"}}
+          }],
+          "relations": [], "dependencies": []
+        }
+      }
+      """
+    Then the response code should be 400
+    And the response error message should contain "Invalid item archive: item 100: wrong value for validation_type"
+    And the table "items" should stay unchanged
+
+  Scenario: Unknown language
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {
+        "parent_item_id": "21",
+        "archive": {
+          "version": 1, "root_item_id": "100",
+          "items": [{"id": "100", "type": "Task", "properties": {"default_language_tag": "de"}, "strings": {"de": {"title": "Aufgabe"}}}],
+          "relations": [], "dependencies": []
+        }
+      }
+      """
+    Then the response code should be 400
+    And the response error message should contain "Invalid item archive: no such languages"
+    And the table "items" should stay unchanged
+
+  Scenario: Relation to an item outside the archive
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {
+        "parent_item_id": "21",
+        "archive": {
+          "version": 1, "root_item_id": "100",
+          "items": [{"id": "100", "type": "Chapter", "properties": {"default_language_tag": "fr"}, "strings": {"fr": {"title": "Chapitre"}}}],
+          "relations": [{"parent_item_id": "100", "child_item_id": "22", "properties": {}}], "dependencies": []
+        }
+      }
+      """
+    Then the response code should be 400
+    And the response error message should contain "Invalid item archive: relation 100-22 refers to an item outside the archive"
+    And the table "items" should stay unchanged
+    And the table "items_items" should stay unchanged
+
+  Scenario: Conflicting text_ids
+    Given I am the user with id "11"
+    When I send a POST request to "/items/import" with the following body:
+      """
+      {
+        "parent_item_id": "21",
+        "archive": {
+          "version": 1, "root_item_id": "100",
+          "items": [{"id": "100", "type": "Task", "properties": {"text_id": "task", "default_language_tag": "fr"}, "strings": {"fr": {"title": "Tâche"}}}],
+          "relations": [], "dependencies": []
+        }
+      }
+      """
+    Then the response code should be 409
+    And the response error message should contain "Some text_ids of the archive are already used: task"
+    And the table "items" should stay unchanged
+    And the table "item_revisions" should be empty
@@ -29,7 +29,8 @@ const (
 // SetRoutes defines the routes for this package in a route group.
 func (srv *Service) SetRoutes(router chi.Router) {
 	router.Use(render.SetContentType(render.ContentTypeJSON))
-	router.Post("/items/ask-hint", service.AppHandler(srv.askHint).ServeHTTP)
+	router.With(srv.TaskTokenRateLimitMiddleware("ask-hint")).
+		Post("/items/ask-hint", service.AppHandler(srv.askHint).ServeHTTP)
 	router.Post("/items/save-grade", service.AppHandler(srv.saveGrade).ServeHTTP)
 
 	routerWithAuth := router.With(auth.UserMiddleware(srv.Base))
@@ -64,6 +65,11 @@ func (srv *Service) SetRoutes(router chi.Router) {
 	routerWithAuthAndParticipant.Get("/items/log", service.AppHandler(srv.getActivityLogForAllItems).ServeHTTP)
 	routerWithAuth.Get("/items/{item_id}/official-sessions", service.AppHandler(srv.listOfficialSessions).ServeHTTP)
 	routerWithAuth.Put("/items/{item_id}/strings/{language_tag}", service.AppHandler(srv.updateItemString).ServeHTTP)
+	routerWithAuth.Get("/items/{item_id}/revisions", service.AppHandler(srv.listItemRevisions).ServeHTTP)
+	routerWithAuth.Get("/items/{item_id}/revisions/{revision}", service.AppHandler(srv.getItemRevision).ServeHTTP)
+	routerWithAuth.Post("/items/{item_id}/revisions/{revision}/restore", service.AppHandler(srv.restoreItemRevision).ServeHTTP)
+	routerWithAuth.Get("/items/{item_id}/export", service.AppHandler(srv.exportItem).ServeHTTP)
+	routerWithAuth.Post("/items/import", service.AppHandler(srv.importItems).ServeHTTP)
 	routerWithAuth.Get("/items/{item_id}/entry-state",
 		service.AppHandler(srv.getEntryState).ServeHTTP)
 	routerWithAuthAndParticipant.Post("/items/{ids:(\\d+/)+}enter", service.AppHandler(srv.enter).ServeHTTP)
@@ -0,0 +1,67 @@
+Feature: List revisions of an item
+  Background:
+    Given the database has the following users:
+      | group_id | login |
+      | 11       | jdoe  |
+    And the database has the following table "items":
+      | id | type    | default_language_tag |
+      | 50 | Chapter | fr                   |
+      | 60 | Chapter | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_edit_generated |
+      | 11       | 50      | content            | children           |
+    And the database has the following table "item_revisions":
+      | item_id | revision | author_id | created_at              | snapshot                           | diff                                                           |
+      | 50      | 1        | null      | 2020-01-01 00:00:00.000 | {"item": {"url": "http://a.com/"}} | {"item.url": {"old": null, "new": "http://a.com/"}}            |
+      | 50      | 2        | 11        | 2020-01-02 00:00:00.000 | {"item": {"url": "http://b.com/"}} | {"item.url": {"old": "http://a.com/", "new": "http://b.com/"}} |
+      | 50      | 3        | 11        | 2020-01-03 00:00:00.000 | {"item": {"url": "http://c.com/"}} | {"item.url": {"old": "http://b.com/", "new": "http://c.com/"}} |
+      | 60      | 1        | 11        | 2020-01-01 00:00:00.000 | {"item": {"url": "http://d.com/"}} | {"item.url": {"old": null, "new": "http://d.com/"}}            |
+
+  Scenario: List revisions of an item
+    Given I am the user with id "11"
+    When I send a GET request to "/items/50/revisions"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "revision": 3, "created_at": "2020-01-03T00:00:00Z", "author_id": "11", "author_login": "jdoe",
+        "diff": {"item.url": {"old": "http://b.com/", "new": "http://c.com/"}}
+      },
+      {
+        "revision": 2, "created_at": "2020-01-02T00:00:00Z", "author_id": "11", "author_login": "jdoe",
+        "diff": {"item.url": {"old": "http://a.com/", "new": "http://b.com/"}}
+      },
+      {
+        "revision": 1, "created_at": "2020-01-01T00:00:00Z", "author_id": null, "author_login": null,
+        "diff": {"item.url": {"old": null, "new": "http://a.com/"}}
+      }
+    ]
+    """
+
+  Scenario: List revisions of an item (start from the second revision, ascending order)
+    Given I am the user with id "11"
+    When I send a GET request to "/items/50/revisions?sort=revision&from.revision=1&limit=1"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    [
+      {
+        "revision": 2, "created_at": "2020-01-02T00:00:00Z", "author_id": "11", "author_login": "jdoe",
+        "diff": {"item.url": {"old": "http://a.com/", "new": "http://b.com/"}}
+      }
+    ]
+    """
+
+  Scenario: Get a revision of an item
+    Given I am the user with id "11"
+    When I send a GET request to "/items/50/revisions/2"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+    """
+    {
+      "revision": 2, "created_at": "2020-01-02T00:00:00Z", "author_id": "11", "author_login": "jdoe",
+      "diff": {"item.url": {"old": "http://a.com/", "new": "http://b.com/"}},
+      "snapshot": {"item": {"url": "http://b.com/"}}
+    }
+    """
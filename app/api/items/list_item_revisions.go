@@ -0,0 +1,141 @@
+package items
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:model itemRevisionsListResponseRow
+type itemRevisionsListResponseRow struct {
+	// required: true
+	Revision int32 `json:"revision"`
+	// required: true
+	CreatedAt database.Time `json:"created_at"`
+	// `null` if the author has been deleted
+	// required: true
+	AuthorID *int64 `json:"author_id,string"`
+	// required: true
+	AuthorLogin *string `json:"author_login"`
+	// Changed values compared to the previous revision by their paths
+	// (like "item.url", "strings.fr.title", or "children.1234.child_order")
+	// required: true
+	// example: {"strings.fr.title": {"old": "Chapitre", "new": "Chapitre 1"}}
+	Diff json.RawMessage `json:"diff"`
+
+	DiffJSON string `json:"-"`
+}
+
+// swagger:operation GET /items/{item_id}/revisions items itemRevisionsList
+//
+//	---
+//	summary: List revisions of an item
+//	description: >
+//
+//		Lists the revisions of the item. A revision is recorded each time the item's properties,
+//		its strings, or its children are changed (by `itemCreate`, `itemUpdate`, `itemStringUpdate`,
+//		or `itemRevisionRestore`).
+//
+//
+//		The user should have `can_view` >= 'content' and `can_edit` >= 'children' on the item,
+//		otherwise the "forbidden" response is returned.
+//	parameters:
+//		- name: item_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: sort
+//			in: query
+//			default: [-revision]
+//			type: array
+//			items:
+//				type: string
+//				enum: [revision,-revision]
+//		- name: from.revision
+//			description: Start the page from the revision next to the revision with `revision` = `{from.revision}`
+//			in: query
+//			type: integer
+//		- name: limit
+//			description: Display the first N revisions
+//			in: query
+//			type: integer
+//			maximum: 1000
+//			default: 500
+//	responses:
+//		"200":
+//			description: OK. Success response with an array of revisions
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/itemRevisionsListResponseRow"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) listItemRevisions(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	itemID, err := service.ResolveURLQueryPathInt64Field(r, "item_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	if !userCanViewItemRevisions(store, user, itemID) {
+		return service.InsufficientAccessRightsError
+	}
+
+	query := itemRevisionsQuery(store, itemID).
+		Select(`
+			item_revisions.revision, item_revisions.created_at, item_revisions.author_id, users.login AS author_login,
+			CAST(item_revisions.diff AS CHAR) AS diff_json`)
+	query = service.NewQueryLimiter().Apply(r, query)
+	query, apiError := service.ApplySortingAndPaging(
+		r, query,
+		&service.SortingAndPagingParameters{
+			Fields: service.SortingAndPagingFields{
+				"revision": {ColumnName: "item_revisions.revision"},
+			},
+			DefaultRules: "-revision",
+			TieBreakers:  service.SortingAndPagingTieBreakers{"revision": service.FieldTypeInt64},
+		})
+	if apiError != service.NoError {
+		return apiError
+	}
+
+	var result []itemRevisionsListResponseRow
+	service.MustNotBeError(query.Scan(&result).Error())
+	for index := range result {
+		result[index].Diff = json.RawMessage(result[index].DiffJSON)
+	}
+
+	render.Respond(w, r, result)
+	return service.NoError
+}
+
+func userCanViewItemRevisions(store *database.DataStore, user *database.User, itemID int64) bool {
+	found, err := store.Permissions().MatchingUserAncestors(user).
+		Where("item_id = ?", itemID).
+		WherePermissionIsAtLeast("view", "content").
+		WherePermissionIsAtLeast("edit", "children").
+		HasRows()
+	service.MustNotBeError(err)
+	return found
+}
+
+func itemRevisionsQuery(store *database.DataStore, itemID int64) *database.DB {
+	return store.ItemRevisions().
+		Joins("LEFT JOIN users ON users.group_id = item_revisions.author_id").
+		Where("item_revisions.item_id = ?", itemID)
+}
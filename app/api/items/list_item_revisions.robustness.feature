@@ -0,0 +1,52 @@
+Feature: List revisions of an item - robustness
+  Background:
+    Given the database has the following users:
+      | group_id | login |
+      | 11       | jdoe  |
+      | 12       | jane  |
+    And the database has the following table "items":
+      | id | type    | default_language_tag |
+      | 50 | Chapter | fr                   |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_edit_generated |
+      | 11       | 50      | content            | children           |
+      | 12       | 50      | solution           | none               |
+    And the database has the following table "item_revisions":
+      | item_id | revision | author_id | snapshot                           | diff |
+      | 50      | 1        | 11        | {"item": {"url": "http://a.com/"}} | {}   |
+
+  Scenario: Wrong item_id
+    Given I am the user with id "11"
+    When I send a GET request to "/items/abc/revisions"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for item_id (should be int64)"
+
+  Scenario: The user cannot edit the item
+    Given I am the user with id "12"
+    When I send a GET request to "/items/50/revisions"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: Wrong sorting
+    Given I am the user with id "11"
+    When I send a GET request to "/items/50/revisions?sort=created_at"
+    Then the response code should be 400
+    And the response error message should contain "Unallowed field in sorting parameters: "created_at""
+
+  Scenario: Wrong revision of a revision to get
+    Given I am the user with id "11"
+    When I send a GET request to "/items/50/revisions/abc"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for revision (should be int64)"
+
+  Scenario: The user cannot edit the item of a revision to get
+    Given I am the user with id "12"
+    When I send a GET request to "/items/50/revisions/1"
+    Then the response code should be 403
+    And the response error message should contain "Insufficient access rights"
+
+  Scenario: No such revision
+    Given I am the user with id "11"
+    When I send a GET request to "/items/50/revisions/2"
+    Then the response code should be 404
+    And the response error message should contain "No such revision"
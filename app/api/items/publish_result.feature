@@ -88,3 +88,49 @@ Feature: Publish a result to LTI
         "message": "failed"
       }
       """
+
+  Scenario Outline: Publish the score to the LTI 1.3 line items of the user
+    Given the application config is:
+      """
+      auth:
+        loginModuleURL: "https://login.algorea.org"
+        clientID: "1"
+        clientSecret: "tzxsLyFtJiGnmD6sjZMqSEidVpVsL3hEoSxIXCpI"
+        lti:
+          keyID: "algorea"
+          launchURL: "https://backend.algorea.org/auth/lti/launch"
+          frontendURL: "https://app.algorea.org/lti"
+          platforms:
+            - issuer: "https://moodle.example.org"
+              clientID: "algorea-tool"
+              deploymentIDs: ["1"]
+              authLoginURL: "https://moodle.example.org/mod/lti/auth.php"
+              accessTokenURL: "https://moodle.example.org/mod/lti/token.php"
+              jwksURL: "https://moodle.example.org/mod/lti/certs.php"
+      """
+    And the time now is "2019-07-16T22:02:28Z"
+    And the database has the following table "lti_line_items":
+      | participant_id | item_id | issuer                     | client_id    | subject       | line_item_url                                                          |
+      | 21             | 123     | https://moodle.example.org | algorea-tool | moodle-user-1 | https://moodle.example.org/mod/lti/services.php/2/lineitems/7/lineitem |
+    And I am the user with id "21"
+    And the LTI platform "https://moodle.example.org" issues the access token "agstoken" for publishing scores
+    And the LTI line item "https://moodle.example.org/mod/lti/services.php/2/lineitems/7/lineitem" receives with the access token "agstoken" and responds <status> to the score:
+      """
+      {
+        "activityProgress": "Completed", "gradingProgress": "FullyGraded",
+        "scoreGiven": 15.6, "scoreMaximum": 100, "timestamp": "2019-07-16T22:02:28Z", "userId": "moodle-user-1"
+      }
+      """
+    When I send a POST request to "/items/123/attempts/1/publish"
+    Then the response code should be 200
+    And the response body should be, in JSON:
+      """
+      {
+        "success": <success>,
+        "message": "<message>"
+      }
+      """
+  Examples:
+    | status | success | message   |
+    | 200    | true    | published |
+    | 500    | false   | failed    |
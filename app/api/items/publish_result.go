@@ -3,11 +3,15 @@ package items
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/render"
 	"github.com/jinzhu/gorm"
 
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/loginmodule"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/lti"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 )
 
@@ -18,12 +22,16 @@ import (
 //	description: >
 //		Publishes score (divided by 100) obtained for the item within the attempt to LTI (via the login module).
 //
+//		If the current user has launched the item from an LTI 1.3 platform providing a line item of its gradebook
+//		(see `POST /auth/lti/launch`), the score is published to the line items of the user for the item
+//		directly (with the Assignment and Grade Services) instead.
+//
 //
 //			Restrictions:
 //
 //		* if `as_team_id` is given, it should be a user's parent team group,
 //		* the current user should have at least 'content' access on each of the `{item_id}` item,
-//		* the current user should have non-empty `login_id`,
+//		* the current user should have non-empty `login_id` (unless the user has LTI 1.3 line items for the item),
 //
 //		otherwise the 'forbidden' error is returned.
 //	parameters:
@@ -62,10 +70,15 @@ func (srv *Service) publishResult(w http.ResponseWriter, r *http.Request) servic
 	}
 
 	user := srv.GetUser(r)
-	if user.LoginID == nil {
+	store := srv.GetStore(r)
+
+	var lineItems []ltiLineItem
+	service.MustNotBeError(store.LTILineItems().
+		Where("participant_id = ? AND item_id = ?", user.GroupID, itemID).
+		Select("issuer, client_id, subject, line_item_url").Scan(&lineItems).Error())
+	if user.LoginID == nil && len(lineItems) == 0 {
 		return service.InsufficientAccessRightsError
 	}
-	store := srv.GetStore(r)
 
 	found, err := store.Permissions().MatchingUserAncestors(user).WherePermissionIsAtLeast("view", "content").
 		Where("item_id = ?", itemID).HasRows()
@@ -89,13 +102,18 @@ func (srv *Service) publishResult(w http.ResponseWriter, r *http.Request) servic
 		service.MustNotBeError(err)
 	}
 
-	result, err := loginmodule.NewClient(srv.AuthConfig.GetString("loginModuleURL")).SendLTIResult(
-		r.Context(),
-		srv.AuthConfig.GetString("clientID"),
-		srv.AuthConfig.GetString("clientSecret"),
-		*user.LoginID, itemID, score/100.0,
-	)
-	service.MustNotBeError(err)
+	var result bool
+	if len(lineItems) > 0 {
+		result = srv.publishScoreToLTILineItems(r, lineItems, score)
+	} else {
+		result, err = loginmodule.NewClient(srv.AuthConfig.GetString("loginModuleURL")).SendLTIResult(
+			r.Context(),
+			srv.AuthConfig.GetString("clientID"),
+			srv.AuthConfig.GetString("clientSecret"),
+			*user.LoginID, itemID, score/100.0,
+		)
+		service.MustNotBeError(err)
+	}
 
 	message := "published"
 	if !result {
@@ -104,3 +122,42 @@ func (srv *Service) publishResult(w http.ResponseWriter, r *http.Request) servic
 	render.Respond(w, r, &service.Response[*struct{}]{Success: result, Message: message})
 	return service.NoError
 }
+
+type ltiLineItem struct {
+	Issuer      string
+	ClientID    string
+	Subject     string
+	LineItemURL string
+}
+
+// publishScoreToLTILineItems publishes the score to the line items of LTI 1.3 platforms.
+// It returns false if the score has not been published to some of the line items.
+func (srv *Service) publishScoreToLTILineItems(r *http.Request, lineItems []ltiLineItem, score float32) bool {
+	ltiConfig, err := auth.GetLTIConfig(srv.AuthConfig)
+	service.MustNotBeError(err)
+
+	result := true
+	for _, lineItem := range lineItems {
+		var platform *lti.PlatformConfig
+		if ltiConfig != nil {
+			platform = ltiConfig.Platform(lineItem.Issuer, lineItem.ClientID)
+		}
+		if platform == nil {
+			logging.GetLogEntry(r).Warnf("Cannot publish a score to the LTI platform %q (client %q): the platform is not configured",
+				lineItem.Issuer, lineItem.ClientID)
+			result = false
+			continue
+		}
+		err = lti.PublishScore(r.Context(), ltiConfig, platform, srv.TokenConfig.PrivateKey, lineItem.LineItemURL, &lti.Score{
+			Subject:      lineItem.Subject,
+			ScoreGiven:   score,
+			ScoreMaximum: 100,
+			Timestamp:    time.Now(),
+		})
+		if err != nil {
+			logging.GetLogEntry(r).Warnf("Cannot publish a score to the LTI line item %q: %s", lineItem.LineItemURL, err)
+			result = false
+		}
+	}
+	return result
+}
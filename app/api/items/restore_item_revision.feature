@@ -0,0 +1,66 @@
+Feature: Restore a revision of an item
+  Background:
+    Given the database has the following table "groups":
+      | id | name | type |
+      | 10 | Club | Club |
+    And the database has the following user:
+      | group_id | login |
+      | 11       | jdoe  |
+    And the database has the following table "groups_groups":
+      | parent_group_id | child_group_id |
+      | 10              | 11             |
+    And the groups ancestors are computed
+    And the database has the following table "languages":
+      | tag |
+      | en  |
+      | fr  |
+    And the database has the following table "items":
+      | id | type    | url             | text_id | no_score | default_language_tag | entering_time_min   |
+      | 50 | Chapter | http://new.com/ | new     | 0        | en                   | 2010-01-01 00:00:00 |
+      | 60 | Task    | null            | null    | 0        | fr                   | 2007-01-01 00:00:00 |
+      | 70 | Task    | null            | null    | 0        | fr                   | 2007-01-01 00:00:00 |
+    And the database has the following table "items_strings":
+      | item_id | language_tag | title         |
+      | 50      | en           | New title     |
+      | 50      | fr           | Nouveau titre |
+      | 60      | fr           | Tâche 60      |
+      | 70      | fr           | Tâche 70      |
+    And the database has the following table "items_items":
+      | parent_item_id | child_item_id | child_order |
+      | 50             | 70            | 1           |
+    And the database has the following table "items_ancestors":
+      | ancestor_item_id | child_item_id |
+      | 50               | 70            |
+    And the database has the following table "permissions_granted":
+      | group_id | item_id | source_group_id | can_view | can_grant_view | can_edit |
+      | 11       | 50      | 11              | content  | none           | all      |
+      | 11       | 60      | 11              | content  | content        | none     |
+      | 11       | 70      | 11              | content  | none           | none     |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_grant_view_generated | can_edit_generated |
+      | 11       | 50      | content            | none                     | all                |
+      | 11       | 60      | content            | content                  | none               |
+      | 11       | 70      | content            | none                     | none               |
+    And the database has the following table "item_revisions":
+      | item_id | revision | author_id | created_at          | snapshot                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                        | diff |
+      | 50      | 1        | 11        | 2020-01-01 00:00:00 | {"item": {"url": "http://old.com/", "text_id": "old", "no_score": 1, "default_language_tag": "fr", "entering_time_min": "2007-01-01 01:02:03", "duration": null, "requires_explicit_entry": 0}, "strings": {"fr": {"title": "Ancien titre", "image_url": null, "subtitle": null, "description": null}}, "children": {"60": {"child_order": 1, "category": "Validation", "score_weight": 2, "content_view_propagation": "as_info", "upper_view_levels_propagation": "use_content_view_propagation", "grant_view_propagation": 0, "watch_propagation": 0, "edit_propagation": 0, "request_help_propagation": 0}}} | {}   |
+
+  Scenario: Restore a revision
+    Given I am the user with id "11"
+    When I send a POST request to "/items/50/revisions/1/restore"
+    Then the response should be "updated"
+    And the table "items" at id "50" should be:
+      | id | type    | url             | text_id | no_score | default_language_tag | entering_time_min   | duration | requires_explicit_entry |
+      | 50 | Chapter | http://old.com/ | old     | 1        | fr                   | 2007-01-01 01:02:03 | null     | 0                       |
+    And the table "items_strings" should be:
+      | item_id | language_tag | title        |
+      | 50      | fr           | Ancien titre |
+      | 60      | fr           | Tâche 60     |
+      | 70      | fr           | Tâche 70     |
+    And the table "items_items" should be:
+      | parent_item_id | child_item_id | child_order | category   | score_weight | content_view_propagation | upper_view_levels_propagation | grant_view_propagation | watch_propagation | edit_propagation | request_help_propagation |
+      | 50             | 60            | 1           | Validation | 2            | as_info                  | use_content_view_propagation  | 0                      | 0                 | 0                | 0                        |
+    And the table "item_revisions" should be:
+      | item_id | revision | author_id |
+      | 50      | 1        | 11        |
+      | 50      | 2        | 11        |
@@ -0,0 +1,213 @@
+package items
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+)
+
+// swagger:operation POST /items/{item_id}/revisions/{revision}/restore items itemRevisionRestore
+//
+//	---
+//	summary: Restore a revision of an item
+//	description: >
+//
+//		Restores the properties, the strings, and the children of the item as they were in the given revision
+//		and records the result as a new revision.
+//
+//
+//		The strings of the item which did not exist in the revision are deleted.
+//		The properties and the children are restored like `itemUpdate` would do with the values
+//		from the revision (so the same validations apply).
+//
+//
+//		The user should have `can_view` >= 'content' and `can_edit` >= 'all' on the item,
+//		otherwise the "forbidden" response is returned.
+//	parameters:
+//		- name: item_id
+//			in: path
+//			type: integer
+//			format: int64
+//			required: true
+//		- name: revision
+//			in: path
+//			type: integer
+//			format: int32
+//			required: true
+//	responses:
+//		"200":
+//			"$ref": "#/responses/updatedResponse"
+//		"400":
+//			"$ref": "#/responses/badRequestResponse"
+//		"401":
+//			"$ref": "#/responses/unauthorizedResponse"
+//		"403":
+//			"$ref": "#/responses/forbiddenResponse"
+//		"404":
+//			"$ref": "#/responses/notFoundResponse"
+//		"408":
+//			"$ref": "#/responses/requestTimeoutResponse"
+//		"500":
+//			"$ref": "#/responses/internalErrorResponse"
+func (srv *Service) restoreItemRevision(w http.ResponseWriter, r *http.Request) service.APIError {
+	user := srv.GetUser(r)
+	store := srv.GetStore(r)
+
+	itemID, err := service.ResolveURLQueryPathInt64Field(r, "item_id")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+	revision, err := service.ResolveURLQueryPathInt64Field(r, "revision")
+	if err != nil {
+		return service.ErrInvalidRequest(err)
+	}
+
+	var propagationsToRun []string
+	apiError := service.NoError
+	err = store.InTransaction(func(store *database.DataStore) error {
+		var found bool
+		found, err = store.Permissions().MatchingUserAncestors(user).WithSharedWriteLock().
+			Where("item_id = ?", itemID).
+			WherePermissionIsAtLeast("view", "content").
+			WherePermissionIsAtLeast("edit", "all").
+			HasRows()
+		service.MustNotBeError(err)
+		if !found {
+			apiError = service.ErrForbidden(errors.New("no access rights to edit the item"))
+			return apiError.Error // rollback
+		}
+
+		snapshot, snapshotErr := store.ItemRevisions().GetSnapshot(itemID, int(revision))
+		if gorm.IsRecordNotFoundError(snapshotErr) {
+			apiError = service.ErrNotFound(errors.New("no such revision"))
+			return apiError.Error // rollback
+		}
+		service.MustNotBeError(snapshotErr)
+
+		// the strings should exist before the default language is restored
+		restoreItemStrings(store, itemID, snapshot.Strings)
+
+		propagationsToRun, apiError, err = updateItemWithRawData(store, user, itemID, itemUpdateRequestFromRevisionSnapshot(&snapshot))
+		if err != nil {
+			return err // rollback
+		}
+
+		languageTags := make([]string, 0, len(snapshot.Strings))
+		for languageTag := range snapshot.Strings {
+			languageTags = append(languageTags, languageTag)
+		}
+		service.MustNotBeError(store.ItemStrings().Where("item_id = ?", itemID).
+			Where("language_tag NOT IN (?)", languageTags).Delete().Error())
+
+		return store.ItemRevisions().Record(itemID, user.GroupID)
+	})
+
+	if apiError != service.NoError {
+		return apiError
+	}
+	service.MustNotBeError(err)
+
+	srv.SchedulePropagation(store, propagationsToRun)
+
+	service.MustNotBeError(render.Render(w, r, service.UpdateSuccess[*struct{}](nil)))
+	return service.NoError
+}
+
+func restoreItemStrings(store *database.DataStore, itemID int64, itemStrings map[string]map[string]interface{}) {
+	for languageTag, values := range itemStrings {
+		dbMap := make(map[string]interface{}, len(values))
+		for column, value := range values {
+			dbMap[column] = value
+		}
+		updateItemStringData(store, itemID, languageTag, dbMap)
+	}
+}
+
+// itemUpdateRequestFromRevisionSnapshot converts the item properties and the children relations
+// of a revision snapshot into the raw data of an itemUpdate request.
+func itemUpdateRequestFromRevisionSnapshot(snapshot *database.ItemRevisionSnapshot) map[string]interface{} {
+	result := convertRevisionValuesForRequest(snapshot.Item, reflect.TypeOf(ItemWithDefaultLanguageTag{}))
+
+	children := make([]interface{}, 0, len(snapshot.Children))
+	for childItemID, relation := range snapshot.Children {
+		child := convertRevisionValuesForRequest(relation, reflect.TypeOf(itemChild{}))
+		child["item_id"] = childItemID
+		children = append(children, child)
+	}
+	result["children"] = children
+
+	return result
+}
+
+// convertRevisionValuesForRequest converts values of DB columns stored in a revision snapshot
+// into values of the JSON fields of the given request struct type
+// (MySQL stores booleans as numbers in JSON while the request expects JSON booleans or RFC3339 times).
+func convertRevisionValuesForRequest(values map[string]interface{}, structType reflect.Type) map[string]interface{} {
+	fields := make(map[string]reflect.StructField)
+	collectRequestFieldsByColumn(structType, fields)
+
+	result := make(map[string]interface{}, len(values))
+	for column, value := range values {
+		field, ok := fields[column]
+		if !ok {
+			continue
+		}
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		result[jsonName] = convertRevisionValueForRequest(value, field.Type)
+	}
+	return result
+}
+
+func collectRequestFieldsByColumn(structType reflect.Type, fields map[string]reflect.StructField) {
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if field.Anonymous {
+			collectRequestFieldsByColumn(field.Type, fields)
+			continue
+		}
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if jsonName == "" || jsonName == "-" {
+			continue
+		}
+		column := jsonName
+		if sqlTag := field.Tag.Get("sql"); strings.HasPrefix(sqlTag, "column:") {
+			column = strings.TrimPrefix(sqlTag, "column:")
+		}
+		fields[column] = field
+	}
+}
+
+func convertRevisionValueForRequest(value interface{}, fieldType reflect.Type) interface{} {
+	if value == nil {
+		return nil
+	}
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	switch {
+	case fieldType.Kind() == reflect.Bool:
+		if number, ok := value.(float64); ok {
+			return number != 0
+		}
+	case fieldType == reflect.TypeOf(time.Time{}):
+		if stringValue, ok := value.(string); ok {
+			if parsedTime, err := time.Parse(time.DateTime, stringValue); err == nil {
+				return parsedTime.Format(time.RFC3339)
+			}
+		}
+	case fieldType.Kind() == reflect.String:
+		if _, ok := value.(string); !ok {
+			return fmt.Sprint(value)
+		}
+	}
+	return value
+}
@@ -0,0 +1,63 @@
+Feature: Restore a revision of an item - robustness
+  Background:
+    Given the database has the following users:
+      | group_id | login |
+      | 11       | jdoe  |
+      | 12       | jane  |
+    And the database has the following table "languages":
+      | tag |
+      | fr  |
+    And the database has the following table "items":
+      | id | type    | url             | default_language_tag |
+      | 50 | Chapter | http://new.com/ | fr                   |
+    And the database has the following table "items_strings":
+      | item_id | language_tag | title         |
+      | 50      | fr           | Nouveau titre |
+    And the database has the following table "permissions_generated":
+      | group_id | item_id | can_view_generated | can_edit_generated |
+      | 11       | 50      | content            | all                |
+      | 12       | 50      | content            | children           |
+    And the database has the following table "item_revisions":
+      | item_id | revision | author_id | snapshot                                                                                                                         | diff |
+      | 50      | 1        | 11        | {"item": {"url": "http://old.com/", "default_language_tag": "fr"}, "strings": {"fr": {"title": "Ancien titre"}}, "children": {}} | {}   |
+
+  Scenario: Wrong item_id
+    Given I am the user with id "11"
+    When I send a POST request to "/items/abc/revisions/1/restore"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for item_id (should be int64)"
+    And the table "items" should stay unchanged
+    And the table "item_revisions" should stay unchanged
+
+  Scenario: Wrong revision
+    Given I am the user with id "11"
+    When I send a POST request to "/items/50/revisions/abc/restore"
+    Then the response code should be 400
+    And the response error message should contain "Wrong value for revision (should be int64)"
+    And the table "items" should stay unchanged
+    And the table "item_revisions" should stay unchanged
+
+  Scenario: The user cannot edit all the properties of the item
+    Given I am the user with id "12"
+    When I send a POST request to "/items/50/revisions/1/restore"
+    Then the response code should be 403
+    And the response error message should contain "No access rights to edit the item"
+    And the table "items" should stay unchanged
+    And the table "items_strings" should stay unchanged
+    And the table "item_revisions" should stay unchanged
+
+  Scenario: No such item
+    Given I am the user with id "11"
+    When I send a POST request to "/items/404/revisions/1/restore"
+    Then the response code should be 403
+    And the response error message should contain "No access rights to edit the item"
+    And the table "item_revisions" should stay unchanged
+
+  Scenario: No such revision
+    Given I am the user with id "11"
+    When I send a POST request to "/items/50/revisions/2/restore"
+    Then the response code should be 404
+    And the response error message should contain "No such revision"
+    And the table "items" should stay unchanged
+    And the table "items_strings" should stay unchanged
+    And the table "item_revisions" should stay unchanged
@@ -7,13 +7,12 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/go-chi/render"
-	"github.com/jinzhu/gorm"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/payloads"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
@@ -166,57 +165,13 @@ func saveGradingResultsIntoDB(store *database.DataStore, requestData *saveGradeR
 		return validated, false, golang.NewSet[int64]()
 	}
 
-	// Build query to update results
-	columnsToUpdate := []string{
-		"tasks_tried",
-		"score_obtained_at",
-		"score_computed",
-	}
-	newScoreExpression := gorm.Expr(`
-			LEAST(GREATEST(
-				CASE score_edit_rule
-					WHEN 'set' THEN score_edit_value
-					WHEN 'diff' THEN ? + score_edit_value
-					ELSE ?
-				END, score_computed, 0), 100)`, score, score)
-	values := []interface{}{
-		requestData.ScoreToken.Converted.UserAnswerID, // for join
-		1, // tasks_tried
-		// for score_computed we compare patched scores
-		gorm.Expr(`
-			CASE
-			  -- New best score or no time saved yet
-				-- Note that when the score = 0, score_obtained_at is the time of the first submission
-				WHEN score_obtained_at IS NULL OR score_computed < ? THEN answers.created_at
-				-- We may get the result of an earlier submission after one with the same score
-				WHEN score_computed = ? THEN LEAST(score_obtained_at, answers.created_at)
-				-- New score if lower than the best score
-				ELSE score_obtained_at
-			END`, newScoreExpression, newScoreExpression), // score_obtained_at
-		newScoreExpression, // score_computed
-	}
-	if validated {
-		// Item was validated
-		columnsToUpdate = append(columnsToUpdate, "validated_at")
-		values = append(values, gorm.Expr("LEAST(IFNULL(validated_at, answers.created_at), answers.created_at)"))
-	}
-
-	updateExpr := "SET " + strings.Join(columnsToUpdate, " = ?, ") + " = ?"
-	values = append(values,
-		requestData.ScoreToken.Converted.ParticipantID,
-		requestData.ScoreToken.Converted.AttemptID,
-		requestData.ScoreToken.Converted.LocalItemID,
-	)
-	service.MustNotBeError(
-		store.DB.Exec("UPDATE results JOIN answers ON answers.id = ? "+ // nolint:gosec
-			updateExpr+" WHERE results.participant_id = ? AND results.attempt_id = ? AND results.item_id = ?", values...).
-			Error()) // nolint:gosec
 	resultStore := store.Results()
-	service.MustNotBeError(resultStore.MarkAsToBePropagated(
+	_, err := resultStore.UpdateWithGrading(
 		requestData.ScoreToken.Converted.ParticipantID, requestData.ScoreToken.Converted.AttemptID,
-		requestData.ScoreToken.Converted.LocalItemID, false))
+		requestData.ScoreToken.Converted.LocalItemID, requestData.ScoreToken.Converted.UserAnswerID, score)
+	service.MustNotBeError(err)
 
-	unlockedItemIDs, err := resultStore.PropagateAndCollectUnlockedItemsForParticipant(requestData.ScoreToken.Converted.ParticipantID)
+	unlockedItemIDs, err = resultStore.PropagateAndCollectUnlockedItemsForParticipant(requestData.ScoreToken.Converted.ParticipantID)
 	service.MustNotBeError(err)
 
 	return validated, true, unlockedItemIDs
@@ -323,7 +278,7 @@ func (requestData *saveGradeRequestParsed) unmarshalScoreToken(wrapper *saveGrad
 
 func (requestData *saveGradeRequestParsed) unmarshalAnswerToken(wrapper *saveGradeRequest) error {
 	if wrapper.AnswerToken == nil {
-		return errors.New("missing answer_token")
+		return i18n.NewError("missing_parameter", "answer_token")
 	}
 	requestData.AnswerToken = &token.Answer{PublicKey: requestData.publicKey}
 	if err := requestData.AnswerToken.UnmarshalJSON(wrapper.AnswerToken.Bytes()); err != nil {
@@ -338,7 +293,7 @@ func (requestData *saveGradeRequestParsed) reconstructScoreTokenData(wrapper *sa
 		return err
 	}
 	if wrapper.Score == nil {
-		return errors.New("missing score")
+		return i18n.NewError("missing_parameter", "score")
 	}
 
 	requestData.ScoreToken = &token.Score{
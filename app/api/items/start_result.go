@@ -128,7 +128,7 @@ func (srv *Service) startResult(w http.ResponseWriter, r *http.Request) service.
 			resultStore := store.Results()
 			service.MustNotBeError(resultStore.MarkAsToBePropagated(participantID, attemptID, itemID, false))
 
-			service.SchedulePropagation(store, srv.GetPropagationEndpoint(), []string{"results"})
+			srv.SchedulePropagation(store, []string{"results"})
 		}
 
 		service.MustNotBeError(constructQueryForGettingAttemptsList(store, participantID, itemID, srv.GetUser(r)).
@@ -128,89 +128,102 @@ func (srv *Service) updateItem(w http.ResponseWriter, r *http.Request) service.A
 	var propagationsToRun []string
 
 	err = store.InTransaction(func(store *database.DataStore) error {
-		input := updateItemRequest{}
-		formData := formdata.NewFormData(&input)
-		var itemInfo struct {
-			ParticipantsGroupID   *int64
-			Type                  string
-			CanEditGeneratedValue int
-			Duration              *string
-			RequiresExplicitEntry bool
-		}
-		err = store.Permissions().MatchingUserAncestors(user).WithExclusiveWriteLock().
-			Joins("JOIN items ON items.id = item_id").
-			Where("item_id = ?", itemID).
-			HavingMaxPermissionAtLeast("view", "content").
-			HavingMaxPermissionAtLeast("edit", "children").
-			Select(`
-				items.participants_group_id, items.type, MAX(can_edit_generated_value) AS can_edit_generated_value,
-				items.duration, items.requires_explicit_entry`).
-			Group("item_id").
-			Scan(&itemInfo).Error()
-
-		if gorm.IsRecordNotFoundError(err) {
-			apiError = service.ErrForbidden(errors.New("no access rights to edit the item"))
-			return apiError.Error // rollback
-		}
-		service.MustNotBeError(err)
-
-		var childrenInfoMap map[int64]permissionAndType
-		var oldPropagationLevelsMap map[int64]*itemsRelationData
-
-		registerChildrenValidator(formData, store, user, itemInfo.Type, &childrenInfoMap, &oldPropagationLevelsMap, &itemID)
-		formData.RegisterValidation("child_type_non_skill", constructUpdateItemChildTypeNonSkillValidator(itemInfo.Type, &childrenInfoMap))
-		formData.RegisterTranslation("child_type_non_skill", "a skill cannot be a child of a non-skill item")
-		formData.RegisterValidation("cannot_be_set_for_skills", constructUpdateItemCannotBeSetForSkillsValidator(itemInfo.Type))
-		formData.RegisterTranslation("cannot_be_set_for_skills", "cannot be set for skill items")
-		formData.RegisterValidation("duration_requires_explicit_entry",
-			constructUpdateItemDurationRequiresExplicitEntryValidator(formData, itemInfo.Duration, itemInfo.RequiresExplicitEntry))
-		formData.RegisterTranslation("duration_requires_explicit_entry", "requires_explicit_entry should be true when the duration is not null")
-		formData.RegisterValidation("options", constructItemOptionsValidator())
-		formData.RegisterTranslation("null|options", "options should be a valid JSON or null")
-
-		err = formData.ParseMapData(rawRequestData)
+		service.MustNotBeError(store.ItemRevisions().RecordInitialIfMissing(itemID))
+
+		propagationsToRun, apiError, err = updateItemWithRawData(store, user, itemID, rawRequestData)
 		if err != nil {
-			apiError = service.ErrInvalidRequest(err)
 			return err // rollback
 		}
-
-		itemData := formData.ConstructPartialMapForDB("ItemWithDefaultLanguageTag")
-		if len(itemData) == 0 && !formData.IsSet("children") {
-			return nil // Nothing to do
-		}
-
-		if len(itemData) > 0 &&
-			itemInfo.CanEditGeneratedValue < store.PermissionsGranted().PermissionIndexByKindAndName("edit", "all") {
-			apiError = service.ErrForbidden(errors.New("no access rights to edit the item's properties"))
-			return apiError.Error // rollback
-		}
-
-		apiError = updateItemInDB(itemData, itemInfo.ParticipantsGroupID, store, itemID)
-		if apiError != service.NoError {
-			return apiError.Error // rollback
-		}
-
-		propagationsToRun, apiError, err = updateChildrenAndRunListeners(
-			formData,
-			store,
-			itemID,
-			&input,
-			childrenInfoMap,
-			oldPropagationLevelsMap,
-		)
-		return err
+		return store.ItemRevisions().Record(itemID, user.GroupID)
 	})
 
 	service.MustBeNoError(apiError)
 	service.MustNotBeError(err)
 
-	service.SchedulePropagation(store, srv.GetPropagationEndpoint(), propagationsToRun)
+	srv.SchedulePropagation(store, propagationsToRun)
 
 	// response
 	service.MustNotBeError(render.Render(w, r, service.UpdateSuccess[*struct{}](nil)))
 	return service.NoError
 }
 
+// updateItemWithRawData updates the item with the given raw request data (see itemUpdate).
+// It should be called inside a transaction.
+func updateItemWithRawData(store *database.DataStore, user *database.User, itemID int64, rawRequestData map[string]interface{}) (
+	propagationsToRun []string, apiError service.APIError, err error,
+) {
+	input := updateItemRequest{}
+	formData := formdata.NewFormData(&input)
+	var itemInfo struct {
+		ParticipantsGroupID   *int64
+		Type                  string
+		CanEditGeneratedValue int
+		Duration              *string
+		RequiresExplicitEntry bool
+	}
+	err = store.Permissions().MatchingUserAncestors(user).WithExclusiveWriteLock().
+		Joins("JOIN items ON items.id = item_id").
+		Where("item_id = ?", itemID).
+		HavingMaxPermissionAtLeast("view", "content").
+		HavingMaxPermissionAtLeast("edit", "children").
+		Select(`
+			items.participants_group_id, items.type, MAX(can_edit_generated_value) AS can_edit_generated_value,
+			items.duration, items.requires_explicit_entry`).
+		Group("item_id").
+		Scan(&itemInfo).Error()
+
+	if gorm.IsRecordNotFoundError(err) {
+		apiError = service.ErrForbidden(errors.New("no access rights to edit the item"))
+		return nil, apiError, apiError.Error // rollback
+	}
+	service.MustNotBeError(err)
+
+	var childrenInfoMap map[int64]permissionAndType
+	var oldPropagationLevelsMap map[int64]*itemsRelationData
+
+	registerChildrenValidator(formData, store, user, itemInfo.Type, &childrenInfoMap, &oldPropagationLevelsMap, &itemID)
+	formData.RegisterValidation("child_type_non_skill", constructUpdateItemChildTypeNonSkillValidator(itemInfo.Type, &childrenInfoMap))
+	formData.RegisterTranslation("child_type_non_skill", "a skill cannot be a child of a non-skill item")
+	formData.RegisterValidation("cannot_be_set_for_skills", constructUpdateItemCannotBeSetForSkillsValidator(itemInfo.Type))
+	formData.RegisterTranslation("cannot_be_set_for_skills", "cannot be set for skill items")
+	formData.RegisterValidation("duration_requires_explicit_entry",
+		constructUpdateItemDurationRequiresExplicitEntryValidator(formData, itemInfo.Duration, itemInfo.RequiresExplicitEntry))
+	formData.RegisterTranslation("duration_requires_explicit_entry", "requires_explicit_entry should be true when the duration is not null")
+	formData.RegisterValidation("options", constructItemOptionsValidator())
+	formData.RegisterTranslation("null|options", "options should be a valid JSON or null")
+
+	err = formData.ParseMapData(rawRequestData)
+	if err != nil {
+		return nil, service.ErrInvalidRequest(err), err // rollback
+	}
+
+	itemData := formData.ConstructPartialMapForDB("ItemWithDefaultLanguageTag")
+	if len(itemData) == 0 && !formData.IsSet("children") {
+		return nil, service.NoError, nil // Nothing to do
+	}
+
+	if len(itemData) > 0 &&
+		itemInfo.CanEditGeneratedValue < store.PermissionsGranted().PermissionIndexByKindAndName("edit", "all") {
+		apiError = service.ErrForbidden(errors.New("no access rights to edit the item's properties"))
+		return nil, apiError, apiError.Error // rollback
+	}
+
+	apiError = updateItemInDB(itemData, itemInfo.ParticipantsGroupID, store, itemID)
+	if apiError != service.NoError {
+		return nil, apiError, apiError.Error // rollback
+	}
+
+	propagationsToRun, apiError, err = updateChildrenAndRunListeners(
+		formData,
+		store,
+		itemID,
+		&input,
+		childrenInfoMap,
+		oldPropagationLevelsMap,
+	)
+	return propagationsToRun, apiError, err
+}
+
 func updateItemInDB(itemData map[string]interface{}, participantsGroupID *int64, store *database.DataStore, itemID int64) service.APIError {
 	if itemData["requires_explicit_entry"] == true && participantsGroupID == nil {
 		createdParticipantsGroupID := createContestParticipantsGroup(store, itemID)
@@ -220,12 +233,12 @@ func updateItemInDB(itemData map[string]interface{}, participantsGroupID *int64,
 	err := store.Items().Where("id = ?", itemID).UpdateColumn(itemData).Error()
 	if err != nil {
 		if database.IsDuplicateEntryError(err) {
-			return service.ErrForbidden(formdata.FieldErrors{"text_id": []string{
-				"text_id must be unique",
+			return service.ErrForbidden(formdata.FieldErrors{"text_id": []error{
+				errors.New("text_id must be unique"),
 			}})
 		} else if database.IsForeignConstraintError(err) {
-			return service.ErrInvalidRequest(formdata.FieldErrors{"default_language_tag": []string{
-				"default language should exist and there should be item's strings in this language",
+			return service.ErrInvalidRequest(formdata.FieldErrors{"default_language_tag": []error{
+				errors.New("default language should exist and there should be item's strings in this language"),
 			}})
 		}
 	}
@@ -66,6 +66,7 @@ Feature: Update item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "<field>": ["<error>"]
@@ -212,6 +213,7 @@ Feature: Update item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "children": ["children IDs should be unique and each should be visible to the user"]
@@ -238,6 +240,7 @@ Feature: Update item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "children[0].order": ["missing field"]
@@ -267,6 +270,7 @@ Feature: Update item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "children": ["children IDs should be unique and each should be visible to the user"]
@@ -339,6 +343,7 @@ Feature: Update item - robustness
       {
         "success": false,
         "message": "Forbidden",
+        "error_code": "forbidden",
         "error_text": "<error>"
       }
       """
@@ -377,6 +382,7 @@ Feature: Update item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "children[0]": ["a skill cannot be a child of a non-skill item"]
@@ -406,6 +412,7 @@ Feature: Update item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "children": ["a task cannot have children items"]
@@ -437,6 +444,7 @@ Feature: Update item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "requires_explicit_entry": ["requires_explicit_entry should be true when the duration is not null"]
@@ -465,6 +473,7 @@ Feature: Update item - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "duration": ["cannot be set for skill items"],
@@ -493,6 +502,7 @@ Feature: Update item - robustness
       {
         "success": false,
         "message": "Forbidden",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "text_id": ["text_id must be unique"]
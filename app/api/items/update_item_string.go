@@ -114,7 +114,9 @@ func (srv *Service) updateItemString(w http.ResponseWriter, r *http.Request) ser
 				return apiError.Error // rollback
 			}
 		}
+		service.MustNotBeError(store.ItemRevisions().RecordInitialIfMissing(itemID))
 		updateItemStringData(store, itemID, languageTag, data.ConstructMapForDB())
+		service.MustNotBeError(store.ItemRevisions().Record(itemID, user.GroupID))
 		return nil // commit
 	})
 
@@ -62,6 +62,7 @@ Feature: Update an item string entry - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "title": ["title must be a maximum of 200 characters in length"]
@@ -84,6 +85,7 @@ Feature: Update an item string entry - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "image_url": ["image_url must be a maximum of 2,048 characters in length"]
@@ -106,6 +108,7 @@ Feature: Update an item string entry - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "subtitle": ["subtitle must be a maximum of 200 characters in length"]
@@ -91,6 +91,7 @@ Feature: Update an attempt result - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "help_requested": ["expected type 'bool', got unconvertible type 'float64'"]
@@ -114,6 +115,7 @@ Feature: Update an attempt result - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
           "attempt_id": ["unexpected field"],
@@ -7,6 +7,7 @@ And the response body should be, in JSON:
 """
 {
   "success": false,
-  "message": "Not Found"
+  "message": "Not Found",
+  "error_code": "not_found"
 }
 """
@@ -11,12 +11,12 @@ Feature: Update thread
       | 51 | Group  | Class |
       | 60 | Group  | Class |
     And the database has the following users:
-      | group_id | login   |
-      | 1        | john    |
-      | 2        | manager |
-      | 3        | jack    |
-      | 4        | jess    |
-      | 5        | owner   |
+      | group_id | login   | email            | default_language |
+      | 1        | john    | null             | en               |
+      | 2        | manager | null             | en               |
+      | 3        | jack    | jack@example.org | fr               |
+      | 4        | jess    | null             | en               |
+      | 5        | owner   | null             | en               |
     And the database has the following table "groups_groups":
       | parent_group_id | child_group_id |
       | 10              | 2              |
@@ -232,6 +232,7 @@ Feature: Update thread
     And the table "threads" at item_id "<item_id>" should be:
       | latest_update_at    | status   |
       | 2022-01-01 00:00:00 | <status> |
+    And the table "email_notifications" should be empty
     Examples:
       | item_id | old_status              | status                  |
       | 100     | waiting_for_trainer     | waiting_for_participant |
@@ -264,6 +265,54 @@ Feature: Update thread
       | 180     | waiting_for_participant | 30              |
       | 190     | waiting_for_trainer     | 30              |
 
+  Scenario: An email notification is enqueued for the participant when someone else changes the status
+    Given I am the user with id "2"
+    And I can view content of the item 160
+    And I have the watch permission set to "answer" on the item 160
+    And I am a manager of the group 3 and can watch for submissions from the group and its descendants
+    And there is a thread with "item_id=160,participant_id=3,status=closed"
+    And the database has the following table "items_strings":
+      | item_id | language_tag | title   |
+      | 160     | en           | Loops   |
+      | 160     | fr           | Boucles |
+    When I send a PUT request to "/items/160/participant/3/thread" with the following body:
+      """
+      {
+        "status": "waiting_for_trainer",
+        "helper_group_id": 30
+      }
+      """
+    Then the response should be "updated"
+    And the table "email_notifications" should be:
+      | user_id | type                  | CAST(payload AS CHAR)                                                                               | status  |
+      | 3       | thread_status_changed | {"status": "waiting_for_trainer", "item_id": "160", "item_title": "Boucles", "participant_id": "3"} | pending |
+    And the table "notifications" should be:
+      | user_id | type           | CAST(payload AS CHAR)                                                      | deduplication_key    | read_at |
+      | 3       | thread_updated | {"status": "waiting_for_trainer", "item_id": "160", "participant_id": "3"} | thread_updated:160:3 | null    |
+
+  Scenario: An in-app notification is created (or replaced) for the participant and the helpers except for the current user
+    Given I am the user with id "4"
+    And I can view content of the item 160
+    And I have the watch permission set to "answer" on the item 160
+    And there is a thread with "item_id=160,participant_id=3,status=waiting_for_participant,helper_group_id=50"
+    And the database has the following table "notifications":
+      | id | user_id | type           | payload | deduplication_key    | created_at          | read_at             |
+      | 1  | 3       | thread_updated | {}      | thread_updated:160:3 | 2021-01-01 00:00:00 | 2021-01-01 00:01:00 |
+      | 2  | 3       | thread_updated | {}      | thread_updated:161:3 | 2021-01-01 00:00:00 | null                |
+    When I send a PUT request to "/items/160/participant/3/thread" with the following body:
+      """
+      {
+        "status": "waiting_for_trainer",
+        "helper_group_id": 50
+      }
+      """
+    Then the response should be "updated"
+    And the table "notifications" should be:
+      | user_id | CAST(payload AS CHAR)                                                      | deduplication_key    | read_at | ABS(TIMESTAMPDIFF(SECOND, created_at, NOW())) < 3 |
+      | 2       | {"status": "waiting_for_trainer", "item_id": "160", "participant_id": "3"} | thread_updated:160:3 | null    | 1                                                 |
+      | 3       | {"status": "waiting_for_trainer", "item_id": "160", "participant_id": "3"} | thread_updated:160:3 | null    | 1                                                 |
+      | 3       | {}                                                                         | thread_updated:161:3 | null    | 0                                                 |
+
   Scenario Outline: A user who has can_watch>=answer on the item AND can_watch_members on the participant can always switch to an open status when thread doesn't exists
     Given I am the user with id "2"
     And I can view content of the item <item_id>
@@ -50,6 +50,12 @@ type updateThreadRequest struct {
 //		Once a thread has been created, it cannot be deleted or set back to `not_started`.
 //
 //
+//		When the status is changed, an email notification is enqueued for the participant (or for the members
+//		of the participant team) except for the current user (see the `notification-worker` command).
+//		On any update, an in-app notification (see `GET /current-user/notifications`) is created for the participant
+//		(or for the members of the participant team) and for the members of the helper group, except for the current user.
+//
+//
 //		Validations and restrictions:
 //			* the current user should have `can_view` >= content permission on the item in order to have the right to write to the thread.
 //			* if `status` is given:
@@ -178,6 +184,11 @@ func (srv *Service) updateThread(w http.ResponseWriter, r *http.Request) service
 			formData, oldThreadInfo.ThreadMessageCount, oldThreadInfo.ThreadHelperGroupID, input, itemID, participantID)
 		service.MustNotBeError(store.Threads().InsertOrUpdateMap(threadData, nil))
 
+		if formData.IsSet("status") && input.Status != oldThreadInfo.ThreadStatus {
+			service.MustNotBeError(store.EmailNotifications().EnqueueForThreadStatusChange(itemID, participantID, input.Status, user.GroupID))
+		}
+		service.MustNotBeError(store.Notifications().InsertForThreadUpdate(itemID, participantID, user.GroupID))
+
 		return nil
 	})
 	if apiError != service.NoError {
@@ -157,6 +157,7 @@ Feature: Update thread - robustness
       {
         "success": false,
         "message": "Bad Request",
+        "error_code": "invalid_input_data",
         "error_text": "Invalid input data",
         "errors":{
         "helper_group_id": ["the group must be visible to the current-user and the participant"]
@@ -181,6 +182,7 @@ Feature: Update thread - robustness
         {
           "success": false,
           "message": "Bad Request",
+          "error_code": "invalid_input_data",
           "error_text": "Invalid input data",
           "errors":{
             "status": ["status must be one of [waiting_for_participant waiting_for_trainer closed]"]
@@ -298,6 +300,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "message_count": ["message_count must be 0 or greater"]
@@ -322,6 +325,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "message_count": ["cannot have both message_count and message_count_increment set"]
@@ -347,6 +351,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "helper_group_id": ["the group must be descendant of a group the participant can request help to"]
@@ -379,6 +384,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "helper_group_id": ["the group must be descendant of a group the participant can request help to"]
@@ -534,6 +540,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "status": ["the helper_group_id must be set to switch from a non-open to an open status"]
@@ -560,6 +567,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "status": ["the helper_group_id must be set to switch from a non-open to an open status"]
@@ -588,6 +596,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "helper_group_id": ["the helper_group_id must not be given when setting or keeping status to closed"]
@@ -616,6 +625,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "helper_group_id": ["the helper_group_id must not be given when setting or keeping status to closed"]
@@ -642,6 +652,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "helper_group_id": ["the group must be visible to the current-user and the participant"]
@@ -665,6 +676,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "helper_group_id": ["the group must be visible to the current-user and the participant"]
@@ -772,6 +784,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "helper_group_id": ["the group must be descendant of a group the participant can request help to"]
@@ -795,6 +808,7 @@ Feature: Update thread - robustness
     {
       "success": false,
       "message": "Bad Request",
+      "error_code": "invalid_input_data",
       "error_text": "Invalid input data",
       "errors":{
         "helper_group_id": ["the group must be visible to the current-user and the participant"]
@@ -19,17 +19,21 @@ import (
 	_ "github.com/France-ioi/AlgoreaBackend/v2/app/doc" // for doc generation
 	"github.com/France-ioi/AlgoreaBackend/v2/app/domain"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/metrics"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/rand"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/ratelimit"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/tracing"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/version"
 )
 
 // Application is the core state of the app.
 type Application struct {
-	HTTPHandler *chi.Mux
-	Config      *viper.Viper
-	Database    *database.DB
-	apiCtx      *api.Ctx
+	HTTPHandler    *chi.Mux
+	MetricsHandler *chi.Mux // nil if metrics are disabled, to be served on a separate port
+	Config         *viper.Viper
+	Database       *database.DB
+	apiCtx         *api.Ctx
 }
 
 // New configures application resources and routes.
@@ -72,6 +76,9 @@ func (app *Application) Reset(config *viper.Viper) error {
 
 	// Apply the config to the global logger
 	logging.SharedLogger.Configure(loggingConfig)
+	if err = tracing.Configure(loggingConfig); err != nil {
+		return fmt.Errorf("unable to load the tracing configuration: %w", err)
+	}
 
 	// Init DB
 	db, err := database.Open(dbConfig.FormatDSN())
@@ -102,7 +109,13 @@ func (app *Application) Reset(config *viper.Viper) error {
 	if serverConfig.GetBool("compress") {
 		router.Use(middleware.DefaultCompress) // apply last on response
 	}
-	router.Use(middleware.RequestID)          // must be before any middleware using the request id (the logger and the recoverer do)
+	if serverConfig.GetBool("metricsEnabled") {
+		router.Use(metrics.Middleware) // must be before the recoverer so that panics are counted as internal errors
+	}
+	router.Use(middleware.RequestID) // must be before any middleware using the request id (the logger and the recoverer do)
+	if tracing.Enabled() {
+		router.Use(tracing.Middleware) // must be before logger so that the trace ids are logged
+	}
 	router.Use(logging.NewStructuredLogger()) //
 	router.Use(middleware.Recoverer)          // must be before logger so that it an log panics
 
@@ -113,10 +126,22 @@ func (app *Application) Reset(config *viper.Viper) error {
 		router.Mount("/debug", middleware.Profiler())
 	}
 
+	rateLimiter, err := ratelimit.NewLimiter(serverConfig, db)
+	if err != nil {
+		_ = db.Close()
+		return fmt.Errorf("unable to load the 'rateLimits' configuration: %w", err)
+	}
+
 	serverConfig.SetDefault("rootPath", "/")
-	apiCtx, apiRouter := api.Router(db, serverConfig, authConfig, domainsConfig, tokenConfig)
+	apiCtx, apiRouter := api.Router(db, serverConfig, authConfig, domainsConfig, tokenConfig, rateLimiter)
 	router.Mount(serverConfig.GetString("rootPath"), apiRouter)
 
+	app.MetricsHandler = nil
+	if serverConfig.GetBool("metricsEnabled") {
+		app.MetricsHandler = chi.NewRouter()
+		app.MetricsHandler.Method(http.MethodGet, "/metrics", metrics.Handler(metrics.NewPropagationBacklogCollector(tableRowsCounter(db))))
+	}
+
 	app.HTTPHandler = router
 	app.Config = config
 	if app.Database != nil {
@@ -126,3 +151,12 @@ func (app *Application) Reset(config *viper.Viper) error {
 	app.apiCtx = apiCtx
 	return nil
 }
+
+// tableRowsCounter returns a function counting the rows of a table of the given database.
+func tableRowsCounter(db *database.DB) func(table string) (int64, error) {
+	return func(table string) (int64, error) {
+		var count int64
+		err := database.NewDataStore(db).Table(table).Count(&count).Error()
+		return count, err
+	}
+}
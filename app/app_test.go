@@ -8,9 +8,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"regexp"
 	"testing"
 
 	"bou.ke/monkey"
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-sql-driver/mysql"
 	"github.com/sirupsen/logrus" //nolint:depguard
 	"github.com/spf13/viper"
@@ -61,6 +63,58 @@ func TestNew_NotDefaultRootPath(t *testing.T) {
 	assert.Equal("/api/*", app.HTTPHandler.Routes()[0].Pattern)
 }
 
+func TestNew_Metrics(t *testing.T) {
+	for _, metricsEnabled := range []bool{true, false} {
+		metricsEnabled := metricsEnabled
+		t.Run(fmt.Sprintf("metricsEnabled=%t", metricsEnabled), func(t *testing.T) {
+			assert := assertlib.New(t)
+			appenv.SetDefaultEnvToTest()
+			_ = os.Setenv("ALGOREA_SERVER__COMPRESS", "false")
+			_ = os.Setenv("ALGOREA_SERVER__METRICSENABLED", fmt.Sprintf("%t", metricsEnabled))
+			defer func() {
+				_ = os.Unsetenv("ALGOREA_SERVER__COMPRESS")
+				_ = os.Unsetenv("ALGOREA_SERVER__METRICSENABLED")
+			}()
+			app, err := New()
+			assert.NoError(err)
+
+			// the metrics are never served by the API router
+			recorder := httptest.NewRecorder()
+			app.HTTPHandler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", http.NoBody))
+			assert.Equal(http.StatusNotFound, recorder.Code)
+
+			if !metricsEnabled {
+				assert.Len(app.HTTPHandler.Middlewares(), 8)
+				assert.Nil(app.MetricsHandler)
+				return
+			}
+			assert.Len(app.HTTPHandler.Middlewares(), 9)
+			assert.NotNil(app.MetricsHandler)
+			recorder = httptest.NewRecorder()
+			app.MetricsHandler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", http.NoBody))
+			assert.Equal(http.StatusOK, recorder.Code)
+			assert.Contains(recorder.Body.String(), "algorea_propagation_backlog{table=\"results_propagate\"} ")
+		})
+	}
+}
+
+func Test_tableRowsCounter(t *testing.T) {
+	db, mock := database.NewDBMock()
+	defer func() { _ = db.Close() }()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `results_propagate`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	expectedError := errors.New("error")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `permissions_propagate`")).WillReturnError(expectedError)
+
+	countRows := tableRowsCounter(db)
+	count, err := countRows("results_propagate")
+	assertlib.NoError(t, err)
+	assertlib.Equal(t, int64(12), count)
+	_, err = countRows("permissions_propagate")
+	assertlib.Equal(t, expectedError, err)
+	assertlib.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestNew_DBErr(t *testing.T) {
 	assert := assertlib.New(t)
 	hook, restoreFct := logging.MockSharedLoggerHook()
@@ -125,6 +179,15 @@ func TestNew_DomainsConfigError(t *testing.T) {
 	assert.Contains(err.Error(), "unable to load the 'domain' configuration: 2 error(s) decoding")
 }
 
+func TestNew_RateLimitsConfigError(t *testing.T) {
+	assert := assertlib.New(t)
+	appenv.SetDefaultEnvToTest()
+	_ = os.Setenv("ALGOREA_SERVER__RATELIMITSSTORE", "redis")
+	defer func() { _ = os.Unsetenv("ALGOREA_SERVER__RATELIMITSSTORE") }()
+	_, err := New()
+	assert.EqualError(err, `unable to load the 'rateLimits' configuration: unknown rate limits store: "redis"`)
+}
+
 // The goal of the following `TestMiddlewares*` tests are not to test the middleware themselves
 // but their interaction (impacted by the order of definition)
 
@@ -3,6 +3,9 @@ package auth
 import (
 	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/lti"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidc"
 )
 
 // GetOAuthConfig generates the OAuth2 config from a configuration.
@@ -23,3 +26,45 @@ func GetOAuthConfig(config *viper.Viper) *oauth2.Config {
 	}
 	return &oauthConfig
 }
+
+// GetOIDCConfig generates the configuration of the OpenID Connect identity provider
+// from the 'oidc' section of a configuration. It returns nil if no issuer is configured.
+func GetOIDCConfig(config *viper.Viper) (*oidc.Config, error) {
+	issuer := config.GetString("oidc.issuer")
+	if issuer == "" {
+		return nil, nil
+	}
+	claimsMapping := oidc.DefaultClaimsMapping
+	if config.IsSet("oidc.claimsMapping") {
+		claimsMapping = config.GetStringMapString("oidc.claimsMapping")
+		if err := oidc.ValidateClaimsMapping(claimsMapping); err != nil {
+			return nil, err
+		}
+	}
+	return &oidc.Config{
+		Issuer:        issuer,
+		ClientID:      config.GetString("oidc.clientID"),
+		ClientSecret:  config.GetString("oidc.clientSecret"),
+		Scopes:        config.GetStringSlice("oidc.scopes"),
+		ClaimsMapping: claimsMapping,
+	}, nil
+}
+
+// GetLTIConfig generates the configuration of the backend as an LTI 1.3 tool
+// from the 'lti' section of a configuration. It returns nil if no platforms are configured.
+func GetLTIConfig(config *viper.Viper) (*lti.Config, error) {
+	if !config.IsSet("lti.platforms") {
+		return nil, nil
+	}
+	var ltiConfig lti.Config
+	if err := config.UnmarshalKey("lti", &ltiConfig); err != nil {
+		return nil, err
+	}
+	if len(ltiConfig.Platforms) == 0 {
+		return nil, nil
+	}
+	if err := ltiConfig.Validate(); err != nil {
+		return nil, err
+	}
+	return &ltiConfig, nil
+}
@@ -1,11 +1,15 @@
 package auth
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/lti"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidc"
 )
 
 func TestGetOAuthConfig(t *testing.T) {
@@ -21,3 +25,87 @@ func TestGetOAuthConfig(t *testing.T) {
 	assert.Equal(t, oauth2.AuthStyleInParams, c.Endpoint.AuthStyle)
 	assert.Equal(t, []string{"account"}, c.Scopes)
 }
+
+func TestGetOIDCConfig(t *testing.T) {
+	config := viper.New()
+	config.Set("oidc", map[string]interface{}{
+		"issuer":       "https://sso.example.org",
+		"clientID":     "c1",
+		"clientSecret": "c2",
+		"scopes":       []string{"openid", "profile"},
+	})
+	c, err := GetOIDCConfig(config)
+	assert.NoError(t, err)
+	assert.Equal(t, &oidc.Config{
+		Issuer:        "https://sso.example.org",
+		ClientID:      "c1",
+		ClientSecret:  "c2",
+		Scopes:        []string{"openid", "profile"},
+		ClaimsMapping: oidc.DefaultClaimsMapping,
+	}, c)
+}
+
+func TestGetOIDCConfig_ClaimsMapping(t *testing.T) {
+	config := viper.New()
+	config.Set("oidc.issuer", "https://sso.example.org")
+	config.Set("oidc.claimsMapping", map[string]interface{}{"login": "email", "student_id": "school_id"})
+	c, err := GetOIDCConfig(config)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"login": "email", "student_id": "school_id"}, c.ClaimsMapping)
+
+	config.Set("oidc.claimsMapping", map[string]interface{}{"login": "email", "temp_user": "temp"})
+	_, err = GetOIDCConfig(config)
+	assert.EqualError(t, err, `the column "temp_user" of users cannot be filled from claims`)
+}
+
+func TestGetOIDCConfig_NotConfigured(t *testing.T) {
+	c, err := GetOIDCConfig(viper.New())
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+}
+
+func TestGetLTIConfig(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	assert.NoError(t, config.ReadConfig(strings.NewReader(`
+lti:
+  keyID: "algorea"
+  launchURL: "https://backend.example.org/auth/lti/launch"
+  frontendURL: "https://app.example.org"
+  platforms:
+    - issuer: "https://moodle.example.org"
+      clientID: "c1"
+      deploymentIDs: ["1", "2"]
+      authLoginURL: "https://moodle.example.org/mod/lti/auth.php"
+      accessTokenURL: "https://moodle.example.org/mod/lti/token.php"
+      jwksURL: "https://moodle.example.org/mod/lti/certs.php"
+`)))
+	c, err := GetLTIConfig(config)
+	assert.NoError(t, err)
+	assert.Equal(t, &lti.Config{
+		KeyID:       "algorea",
+		LaunchURL:   "https://backend.example.org/auth/lti/launch",
+		FrontendURL: "https://app.example.org",
+		Platforms: []lti.PlatformConfig{{
+			Issuer:         "https://moodle.example.org",
+			ClientID:       "c1",
+			DeploymentIDs:  []string{"1", "2"},
+			AuthLoginURL:   "https://moodle.example.org/mod/lti/auth.php",
+			AccessTokenURL: "https://moodle.example.org/mod/lti/token.php",
+			JWKSURL:        "https://moodle.example.org/mod/lti/certs.php",
+		}},
+	}, c)
+}
+
+func TestGetLTIConfig_Invalid(t *testing.T) {
+	config := viper.New()
+	config.Set("lti.platforms", []map[string]interface{}{{"issuer": "https://moodle.example.org"}})
+	_, err := GetLTIConfig(config)
+	assert.EqualError(t, err, "no launch URL is configured for LTI")
+}
+
+func TestGetLTIConfig_NotConfigured(t *testing.T) {
+	c, err := GetLTIConfig(viper.New())
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+}
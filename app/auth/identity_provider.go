@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/loginmodule"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidc"
+)
+
+const (
+	// IdentityProviderLoginModule is the France-ioi login module (the default identity provider).
+	IdentityProviderLoginModule = "login_module"
+	// IdentityProviderOIDC is the OpenID Connect identity provider configured in the 'oidc' section of the auth config.
+	IdentityProviderOIDC = "oidc"
+	// IdentityProviderLTI marks sessions launched by LTI platforms (configured in the 'lti' section of the auth config).
+	// Such sessions have no refresh tokens, their access tokens are refreshed locally.
+	IdentityProviderLTI = "lti"
+)
+
+// ErrInvalidUserProfile is returned by IdentityProvider.GetUserProfile when the identity provider
+// has given an invalid profile (e.g., an invalid ID token).
+var ErrInvalidUserProfile = errors.New("invalid user profile")
+
+// UserProfile is the profile of a user given by an identity provider.
+type UserProfile struct {
+	// Attributes are values of columns of `users`.
+	Attributes map[string]interface{}
+	// Badges of the user (only given by the login module).
+	Badges []database.Badge
+	// Issuer & Subject identify the user at an OpenID Connect identity provider
+	// (empty for the login module which gives `login_id` in the attributes).
+	Issuer  string
+	Subject string
+}
+
+// IdentityProvider is an external service authenticating users with the OAuth2 authorization-code flow.
+type IdentityProvider interface {
+	// ExchangeCode converts an authorization code into tokens of the identity provider.
+	ExchangeCode(ctx context.Context, code string, options ...oauth2.AuthCodeOption) (*oauth2.Token, error)
+	// RefreshToken gets new tokens of the identity provider for the given refresh token.
+	RefreshToken(ctx context.Context, refreshToken string) (*oauth2.Token, error)
+	// GetUserProfile returns the profile of the user the tokens have been issued for.
+	GetUserProfile(ctx context.Context, token *oauth2.Token) (*UserProfile, error)
+	// SessionAccessToken returns the access token to be given to the user (and its expiration time)
+	// for the given tokens of the identity provider.
+	SessionAccessToken(token *oauth2.Token) (accessToken string, expiresAt time.Time, err error)
+}
+
+// IdentityProviders gives the identity providers configured in the auth config.
+// The client of the OpenID Connect identity provider is kept between requests (until its configuration changes),
+// so that the discovery document and the keys of the provider are loaded once
+// (the keys are reloaded when an ID token is signed with an unknown key).
+// A nil *IdentityProviders creates a new client each time.
+type IdentityProviders struct {
+	mutex      sync.Mutex
+	oidcConfig *oidc.Config
+	oidcClient *oidc.Client
+}
+
+// NewIdentityProviders creates an empty set of identity providers.
+func NewIdentityProviders() *IdentityProviders {
+	return &IdentityProviders{}
+}
+
+// Get returns the identity provider with the given name configured in the auth config.
+func (providers *IdentityProviders) Get(authConfig *viper.Viper, name string) (IdentityProvider, error) {
+	switch name {
+	case IdentityProviderLoginModule:
+		return &loginModuleIdentityProvider{authConfig: authConfig}, nil
+	case IdentityProviderOIDC:
+		config, err := GetOIDCConfig(authConfig)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			return nil, errors.New("no OpenID Connect identity provider is configured")
+		}
+		return &oidcIdentityProvider{client: providers.oidcClientFor(config), claimsMapping: config.ClaimsMapping}, nil
+	default:
+		return nil, fmt.Errorf("unknown identity provider: %q", name)
+	}
+}
+
+func (providers *IdentityProviders) oidcClientFor(config *oidc.Config) *oidc.Client {
+	if providers == nil {
+		return oidc.NewClient(config)
+	}
+
+	providers.mutex.Lock()
+	defer providers.mutex.Unlock()
+
+	if providers.oidcClient == nil || !reflect.DeepEqual(providers.oidcConfig, config) {
+		providers.oidcConfig = config
+		providers.oidcClient = oidc.NewClient(config)
+	}
+	return providers.oidcClient
+}
+
+type loginModuleIdentityProvider struct {
+	authConfig *viper.Viper
+}
+
+func (p *loginModuleIdentityProvider) ExchangeCode(
+	ctx context.Context, code string, options ...oauth2.AuthCodeOption,
+) (*oauth2.Token, error) {
+	return GetOAuthConfig(p.authConfig).Exchange(ctx, code, options...)
+}
+
+func (p *loginModuleIdentityProvider) RefreshToken(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+	// oldToken is invalid since its AccessToken is empty, so the lib will refresh it
+	oldToken := &oauth2.Token{RefreshToken: refreshToken}
+	return GetOAuthConfig(p.authConfig).TokenSource(ctx, oldToken).Token()
+}
+
+func (p *loginModuleIdentityProvider) GetUserProfile(ctx context.Context, token *oauth2.Token) (*UserProfile, error) {
+	profile, err := loginmodule.NewClient(p.authConfig.GetString("loginModuleURL")).GetUserProfile(ctx, token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	badges := profile["badges"].([]database.Badge)
+	delete(profile, "badges")
+	return &UserProfile{Attributes: profile, Badges: badges}, nil
+}
+
+// SessionAccessToken returns the access token of the login module as users use them directly.
+func (p *loginModuleIdentityProvider) SessionAccessToken(token *oauth2.Token) (string, time.Time, error) {
+	return token.AccessToken, token.Expiry, nil
+}
+
+type oidcIdentityProvider struct {
+	client        *oidc.Client
+	claimsMapping map[string]string
+}
+
+func (p *oidcIdentityProvider) ExchangeCode(
+	ctx context.Context, code string, options ...oauth2.AuthCodeOption,
+) (*oauth2.Token, error) {
+	return p.client.Exchange(ctx, code, options...)
+}
+
+func (p *oidcIdentityProvider) RefreshToken(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+	return p.client.Refresh(ctx, refreshToken)
+}
+
+// GetUserProfile validates the ID token given together with the tokens and converts its claims into a profile.
+func (p *oidcIdentityProvider) GetUserProfile(ctx context.Context, token *oauth2.Token) (*UserProfile, error) {
+	rawIDToken, err := oidc.IDTokenFromToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUserProfile, err)
+	}
+	claims, err := p.client.VerifyIDToken(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUserProfile, err)
+	}
+	attributes, err := oidc.ConvertClaims(claims, p.claimsMapping)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUserProfile, err)
+	}
+	return &UserProfile{Attributes: attributes, Issuer: p.client.Issuer(), Subject: claims["sub"].(string)}, nil
+}
+
+// SessionAccessToken generates an access token of the backend expiring together with the access token
+// of the identity provider, so the tokens of the provider are never given to users.
+func (p *oidcIdentityProvider) SessionAccessToken(token *oauth2.Token) (string, time.Time, error) {
+	accessToken, err := GenerateKey()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := token.Expiry
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(time.Duration(TemporaryUserSessionLifetimeInSeconds) * time.Second)
+	}
+	return accessToken, expiresAt, nil
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidctest"
+)
+
+func TestIdentityProviders_Get(t *testing.T) {
+	config := viper.New()
+	providers := NewIdentityProviders()
+
+	provider, err := providers.Get(config, IdentityProviderLoginModule)
+	require.NoError(t, err)
+	assert.IsType(t, &loginModuleIdentityProvider{}, provider)
+
+	_, err = providers.Get(config, IdentityProviderOIDC)
+	assert.EqualError(t, err, "no OpenID Connect identity provider is configured")
+
+	config.Set("oidc.issuer", "https://sso.example.org")
+	provider, err = providers.Get(config, IdentityProviderOIDC)
+	require.NoError(t, err)
+	assert.IsType(t, &oidcIdentityProvider{}, provider)
+
+	_, err = providers.Get(config, "saml")
+	assert.EqualError(t, err, `unknown identity provider: "saml"`)
+
+	// a nil set creates new clients
+	provider, err = (*IdentityProviders)(nil).Get(config, IdentityProviderOIDC)
+	require.NoError(t, err)
+	assert.IsType(t, &oidcIdentityProvider{}, provider)
+}
+
+func TestIdentityProviders_Get_ReusesTheOIDCClient(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+
+	config := viper.New()
+	config.Set("oidc", map[string]interface{}{"issuer": server.URL, "clientID": "client"})
+	providers := NewIdentityProviders()
+
+	for _, grant := range []string{"code1", "code2"} {
+		server.SetTokenResponse(grant, oidctest.TokenResponse("accesstoken", "", 3600, map[string]interface{}{
+			"iss": server.URL, "sub": "user-1", "aud": "client", "exp": time.Now().Add(time.Hour).Unix(), "preferred_username": "jdoe",
+		}))
+		provider, err := providers.Get(config, IdentityProviderOIDC)
+		require.NoError(t, err)
+		token, err := provider.ExchangeCode(context.Background(), grant)
+		require.NoError(t, err)
+		_, err = provider.GetUserProfile(context.Background(), token)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, server.DiscoveryRequests())
+	assert.Equal(t, 1, server.JWKSRequests())
+
+	// a new client is created when the configuration changes
+	config.Set("oidc.clientID", "other-client")
+	provider, err := providers.Get(config, IdentityProviderOIDC)
+	require.NoError(t, err)
+	_, err = provider.RefreshToken(context.Background(), "unknown")
+	assert.Error(t, err)
+	assert.Equal(t, 2, server.DiscoveryRequests())
+}
+
+func TestLoginModuleIdentityProvider_SessionAccessToken(t *testing.T) {
+	expiry := time.Now().Add(time.Hour)
+	accessToken, expiresAt, err := (&loginModuleIdentityProvider{}).
+		SessionAccessToken(&oauth2.Token{AccessToken: "accesstoken", Expiry: expiry})
+	require.NoError(t, err)
+	assert.Equal(t, "accesstoken", accessToken)
+	assert.Equal(t, expiry, expiresAt)
+}
+
+func TestOIDCIdentityProvider(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+	server.SetTokenResponse("somecode", oidctest.TokenResponse("accesstoken", "refreshtoken", 3600, map[string]interface{}{
+		"iss":                server.URL,
+		"sub":                "user-1",
+		"aud":                "client",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"preferred_username": "jdoe",
+		"given_name":         "John",
+	}))
+
+	config := viper.New()
+	config.Set("oidc", map[string]interface{}{
+		"issuer":        server.URL,
+		"clientID":      "client",
+		"claimsMapping": map[string]interface{}{"login": "preferred_username", "first_name": "given_name"},
+	})
+	provider, err := NewIdentityProviders().Get(config, IdentityProviderOIDC)
+	require.NoError(t, err)
+
+	token, err := provider.ExchangeCode(context.Background(), "somecode")
+	require.NoError(t, err)
+
+	profile, err := provider.GetUserProfile(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, &UserProfile{
+		Attributes: map[string]interface{}{"login": "jdoe", "first_name": "John"},
+		Issuer:     server.URL,
+		Subject:    "user-1",
+	}, profile)
+
+	accessToken, expiresAt, err := provider.SessionAccessToken(token)
+	require.NoError(t, err)
+	assert.Len(t, accessToken, 32)
+	assert.NotEqual(t, "accesstoken", accessToken)
+	assert.Equal(t, token.Expiry, expiresAt)
+}
+
+func TestOIDCIdentityProvider_SessionAccessToken_WithoutExpiry(t *testing.T) {
+	_, expiresAt, err := (&oidcIdentityProvider{}).SessionAccessToken(&oauth2.Token{AccessToken: "accesstoken"})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), expiresAt, time.Minute)
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/rand"
+)
+
+// LTISessionLifetimeInSeconds specifies the lifetime of the access token of a session launched by an LTI platform.
+const LTISessionLifetimeInSeconds = int32(2 * time.Hour / time.Second) // 2 hours (7200 seconds)
+
+// CreateNewLTISession creates a new session for a user launching the tool from an LTI platform.
+func CreateNewLTISession(s *database.DataStore, userID int64) (accessToken string, expiresIn int32, err error) {
+	expiresIn = LTISessionLifetimeInSeconds
+
+	accessToken, err = GenerateKey()
+	mustNotBeError(err)
+
+	sessionID := rand.Int63()
+	mustNotBeError(s.
+		// No refresh tokens for LTI sessions.
+		Exec("INSERT INTO sessions (session_id, user_id, identity_provider) VALUES (?, ?, ?)",
+			sessionID, userID, IdentityProviderLTI).
+		Error(),
+	)
+
+	mustNotBeError(s.AccessTokens().InsertNewToken(sessionID, accessToken, expiresIn))
+
+	logging.SharedLogger.WithContext(s.GetContext()).
+		Infof("Generated a session token expiring in %d seconds for an LTI launch of a user with group_id = %d",
+			expiresIn, userID)
+
+	return
+}
+
+// RefreshLTISession refreshes a session launched by an LTI platform.
+func RefreshLTISession(s *database.DataStore, userID, sessionID int64) (accessToken string, expiresIn int32, err error) {
+	expiresIn = LTISessionLifetimeInSeconds
+
+	accessToken, err = GenerateKey()
+	mustNotBeError(err)
+
+	mustNotBeError(s.AccessTokens().InsertNewToken(sessionID, accessToken, expiresIn))
+
+	logging.SharedLogger.WithContext(s.GetContext()).
+		Infof("Refreshed a session token expiring in %d seconds for an LTI session of a user with group_id = %d",
+			expiresIn, userID)
+
+	return
+}
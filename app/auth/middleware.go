@@ -19,6 +19,7 @@ const (
 	ctxBearer
 	ctxSessionCookieAttributes
 	ctxSessionID
+	ctxDelegatedUser
 )
 
 // GetStorer is an interface allowing to get a data store bound to the context of the given request.
@@ -36,11 +37,11 @@ func UserMiddleware(service GetStorer) func(next http.Handler) http.Handler {
 				w.Header().Set("Content-Type", "application/json; charset=utf-8")
 				if err != nil {
 					w.WriteHeader(http.StatusInternalServerError)
-					_, _ = fmt.Fprintf(w, `{"success":false,"message":"Internal server error"}`+"\n")
+					_, _ = fmt.Fprintf(w, `{"success":false,"message":"Internal server error","error_code":"internal_server_error"}`+"\n")
 					return
 				}
 				w.WriteHeader(http.StatusUnauthorized)
-				_, _ = fmt.Fprintf(w, `{"success":false,"message":"Unauthorized","error_text":"%s"}`+"\n", reason)
+				_, _ = fmt.Fprintf(w, `{"success":false,"message":"Unauthorized","error_code":"unauthorized","error_text":"%s"}`+"\n", reason)
 				return
 			}
 
@@ -49,6 +50,13 @@ func UserMiddleware(service GetStorer) func(next http.Handler) http.Handler {
 	}
 }
 
+// ContextWithDelegatedUser returns a copy of the context making the user middleware authenticate the given user
+// without checking any access token. It is used to run services on behalf of a user outside of HTTP requests
+// (like exports run by the export worker). The session ID of such a request is 0.
+func ContextWithDelegatedUser(ctx context.Context, user *database.User) context.Context {
+	return context.WithValue(ctx, ctxDelegatedUser, user)
+}
+
 // ValidatesUserAuthentication checks the authentication in the Authorization header and in the "access_token" cookie.
 // It returns:
 //   - A request context with the user authenticated on success
@@ -57,6 +65,17 @@ func UserMiddleware(service GetStorer) func(next http.Handler) http.Handler {
 func ValidatesUserAuthentication(service GetStorer, w http.ResponseWriter, r *http.Request) (
 	ctx context.Context, authorized bool, reason string, err error,
 ) {
+	if delegatedUser, ok := r.Context().Value(ctxDelegatedUser).(*database.User); ok {
+		ctx = context.WithValue(r.Context(), ctxBearer, "")
+		ctx = context.WithValue(ctx, ctxSessionCookieAttributes, &SessionCookieAttributes{})
+		ctx = context.WithValue(ctx, ctxUser, delegatedUser)
+		ctx = context.WithValue(ctx, ctxSessionID, int64(0))
+
+		logging.LogEntrySetField(r, "user_id", delegatedUser.GroupID)
+
+		return ctx, true, "", nil
+	}
+
 	var user database.User
 	var sessionID int64
 
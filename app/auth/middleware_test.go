@@ -62,7 +62,7 @@ func TestUserMiddleware(t *testing.T) {
 			dbError:                  errors.New("some error"),
 			expectedStatusCode:       500,
 			expectedServiceWasCalled: false,
-			expectedBody:             `{"success":false,"message":"Internal server error"}` + "\n",
+			expectedBody:             `{"success":false,"message":"Internal server error","error_code":"internal_server_error"}` + "\n",
 			expectedLogs:             `level=error .* msg="Can't validate an access token: some error"`,
 		},
 		{
@@ -71,14 +71,16 @@ func TestUserMiddleware(t *testing.T) {
 			expectedAccessToken:      "abcdefgh",
 			expectedStatusCode:       401,
 			expectedServiceWasCalled: false,
-			expectedBody:             `{"success":false,"message":"Unauthorized","error_text":"Invalid access token"}` + "\n",
+			expectedBody: `{"success":false,"message":"Unauthorized","error_code":"unauthorized",` +
+				`"error_text":"Invalid access token"}` + "\n",
 		},
 		{
 			name:                     "spaces before the access token",
 			authHeaders:              []string{"Bearer   1234567"},
 			expectedStatusCode:       401,
 			expectedServiceWasCalled: false,
-			expectedBody:             `{"success":false,"message":"Unauthorized","error_text":"No access token provided"}` + "\n",
+			expectedBody: `{"success":false,"message":"Unauthorized","error_code":"unauthorized",` +
+				`"error_text":"No access token provided"}` + "\n",
 		},
 		{
 			name:                     "spaces in access token",
@@ -300,3 +302,28 @@ func callAuthThroughMiddleware(expectedAccessToken string, authorizationHeaders,
 
 	return enteredService, resp, mock
 }
+
+func TestUserMiddleware_AuthenticatesDelegatedUser(t *testing.T) {
+	dbmock, mock := database.NewDBMock()
+	defer func() { _ = dbmock.Close() }()
+
+	delegatedUser := &database.User{GroupID: 123, Login: "john"}
+	var userInService *database.User
+	var sessionIDInService int64
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userInService = UserFromContext(r.Context())
+		sessionIDInService = SessionIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	request := httptest.NewRequest("GET", "/", http.NoBody)
+	request = request.WithContext(ContextWithDelegatedUser(request.Context(), delegatedUser))
+	request.Header.Set("Authorization", "Bearer sometoken")
+	recorder := httptest.NewRecorder()
+	UserMiddleware(&storeProvider{database.NewDataStore(dbmock)})(handler).ServeHTTP(recorder, request)
+
+	assertlib.Equal(t, http.StatusOK, recorder.Code)
+	assertlib.Equal(t, delegatedUser, userInService)
+	assertlib.Zero(t, sessionIDInService)
+	assertlib.NoError(t, mock.ExpectationsWereMet())
+}
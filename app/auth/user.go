@@ -13,6 +13,11 @@ func UserFromContext(ctx context.Context) *database.User {
 	return userFromContext.Clone()
 }
 
+// IsUserInContext returns true if the user has been set into the context by the middleware.
+func IsUserInContext(ctx context.Context) bool {
+	return ctx.Value(ctxUser) != nil
+}
+
 // SessionIDFromContext retrieves the session id from a context set by the middleware.
 func SessionIDFromContext(ctx context.Context) int64 {
 	return ctx.Value(ctxSessionID).(int64)
@@ -20,6 +20,13 @@ func TestUserFromContext(t *testing.T) {
 	assert.EqualValues(myUser, user)
 }
 
+func TestIsUserInContext(t *testing.T) {
+	assert := assertlib.New(t)
+
+	assert.False(IsUserInContext(context.Background()))
+	assert.True(IsUserInContext(context.WithValue(context.Background(), ctxUser, &database.User{GroupID: 8})))
+}
+
 func TestBearerTokenFromContext(t *testing.T) {
 	assert := assertlib.New(t)
 
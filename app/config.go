@@ -29,6 +29,7 @@ const (
 	authConfigKey     string = "auth"
 	tokenConfigKey    string = "token"
 	domainsConfigKey  string = "domains"
+	emailConfigKey    string = "email"
 )
 
 // LoadConfig loads and return the global configuration from files, flags, env, ...
@@ -100,6 +101,20 @@ func (app *Application) ReplaceAuthConfig(newGlobalConfig *viper.Viper) {
 	_ = app.Reset(app.Config) // cannot return an error in this case
 }
 
+// ReplaceServerConfig merges the server part of the given config into the current one
+// (the server config values which are not given are kept).
+func (app *Application) ReplaceServerConfig(newGlobalConfig *viper.Viper) {
+	newServerConfig := newGlobalConfig.Sub(serverConfigKey)
+	if newServerConfig != nil {
+		for _, key := range newServerConfig.AllKeys() {
+			app.Config.Set(serverConfigKey+"."+key, newServerConfig.Get(key))
+		}
+	}
+	if err := app.Reset(app.Config); err != nil {
+		panic(err)
+	}
+}
+
 // ReplaceDomainsConfig replaces the domains part of the config by the given one.
 func (app *Application) ReplaceDomainsConfig(newGlobalConfig *viper.Viper) {
 	app.Config.Set(domainsConfigKey, newGlobalConfig.Get(domainsConfigKey))
@@ -171,6 +186,12 @@ func ServerConfig(globalConfig *viper.Viper) *viper.Viper {
 	return subconfig(globalConfig, serverConfigKey)
 }
 
+// EmailConfig returns an email dynamic config from the global config
+// (env var changes impacts values).
+func EmailConfig(globalConfig *viper.Viper) *viper.Viper {
+	return subconfig(globalConfig, emailConfigKey)
+}
+
 // DomainsConfig returns the domains fixed config from the global config
 // Panic in case of marshaling error.
 func DomainsConfig(globalConfig *viper.Viper) (config []domain.ConfigItem, err error) {
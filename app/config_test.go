@@ -282,6 +282,17 @@ func TestServerConfig(t *testing.T) {
 	assert.Equal(999, config.GetInt("anykey"))
 }
 
+func TestEmailConfig(t *testing.T) {
+	assert := assertlib.New(t)
+	globalConfig := viper.New()
+	globalConfig.Set("email.anykey", 42)
+	config := EmailConfig(globalConfig)
+	assert.Equal(42, config.GetInt("anykey"))
+	_ = os.Setenv("ALGOREA_EMAIL__ANYKEY", "999")
+	defer func() { _ = os.Unsetenv("ALGOREA_EMAIL__ANYKEY") }()
+	assert.Equal(999, config.GetInt("anykey"))
+}
+
 func TestDomainsConfig_Success(t *testing.T) {
 	assert := assertlib.New(t)
 	globalConfig := viper.New()
@@ -325,6 +336,19 @@ func TestReplaceAuthConfig(t *testing.T) {
 	// not tested: that it is been pushed to the API
 }
 
+func TestReplaceServerConfig(t *testing.T) {
+	assert := assertlib.New(t)
+	globalConfig := viper.New()
+	globalConfig.Set("server.eventsStreamDuration", "2s")
+	application, err := New()
+	assert.NoError(err)
+	application.Config.Set("server.rootPath", "/api/")
+	application.ReplaceServerConfig(globalConfig)
+	assert.Equal("2s", application.Config.Get("server.eventsStreamDuration"))
+	assert.Equal("/api/", application.Config.Get("server.rootPath"))
+	// not tested: that it is been pushed to the API
+}
+
 func TestReplaceDomainsConfig(t *testing.T) {
 	assert := assertlib.New(t)
 	globalConfig := viper.New()
@@ -0,0 +1,187 @@
+// Package credentialsheet renders printable sheets of user credentials as PDF documents:
+// cards with logins & passwords laid out on A4 pages with dashed cutting lines.
+package credentialsheet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	// CardsPerPage is the number of cards on one page (in two columns).
+	CardsPerPage = 10
+
+	pageWidth   = 595.28 // A4 in points
+	pageHeight  = 841.89
+	pageMargin  = 28.35 // 1 cm
+	columns     = 2
+	rows        = CardsPerPage / columns
+	cardWidth   = (pageWidth - 2*pageMargin) / columns
+	cardHeight  = (pageHeight - 2*pageMargin) / rows
+	cardPadding = 14
+
+	// The standard Courier font has the width of 0.6 of its size for all the characters,
+	// other texts are truncated assuming that characters are not wider than the same ratio.
+	charWidthRatio = 0.6
+)
+
+// Card is a card of a credential sheet.
+type Card struct {
+	Login     string
+	Password  string // if empty, a blank line is left for writing the password by hand
+	GroupName string
+	GroupCode string
+}
+
+// Write renders the cards into a PDF document with the platform URL printed on every card.
+// The output only depends on the arguments.
+func Write(w io.Writer, platformURL string, cards []Card) error {
+	pagesCount := (len(cards) + CardsPerPage - 1) / CardsPerPage
+	if pagesCount == 0 {
+		pagesCount = 1
+	}
+
+	var document pdfDocument
+	document.addObject("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, 0, pagesCount)
+	const firstPageObjectNumber = 6 // after the catalog, the page tree & the fonts
+	for pageIndex := 0; pageIndex < pagesCount; pageIndex++ {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPageObjectNumber+2*pageIndex))
+	}
+	document.addObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pagesCount))
+	for _, font := range []string{"Helvetica", "Helvetica-Bold", "Courier-Bold"} {
+		document.addObject(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font))
+	}
+
+	for pageIndex := 0; pageIndex < pagesCount; pageIndex++ {
+		pageCards := cards[pageIndex*CardsPerPage : minInt((pageIndex+1)*CardsPerPage, len(cards))]
+		content := pageContent(platformURL, pageCards)
+		document.addObject(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+				"/Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPageObjectNumber+2*pageIndex+1))
+		document.addObject(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	_, err := w.Write(document.bytes())
+	return err
+}
+
+func pageContent(platformURL string, cards []Card) string {
+	var content strings.Builder
+
+	// cutting lines
+	content.WriteString("0.6 G 0.5 w [4 4] 0 d\n")
+	for column := 0; column <= columns; column++ {
+		x := pageMargin + float64(column)*cardWidth
+		fmt.Fprintf(&content, "%.2f %.2f m %.2f %.2f l S\n", x, pageMargin, x, pageHeight-pageMargin)
+	}
+	for row := 0; row <= rows; row++ {
+		y := pageMargin + float64(row)*cardHeight
+		fmt.Fprintf(&content, "%.2f %.2f m %.2f %.2f l S\n", pageMargin, y, pageWidth-pageMargin, y)
+	}
+	content.WriteString("[] 0 d 0 G\n")
+
+	for index := range cards {
+		left := pageMargin + float64(index%columns)*cardWidth + cardPadding
+		top := pageHeight - pageMargin - float64(index/columns)*cardHeight - cardPadding
+		writeCard(&content, left, top, platformURL, &cards[index])
+	}
+	return content.String()
+}
+
+func writeCard(content *strings.Builder, left, top float64, platformURL string, card *Card) {
+	const (
+		labelWidth  = 70
+		lineSpacing = 22
+	)
+	valueWidth := cardWidth - 2*cardPadding - labelWidth
+
+	y := top - 12
+	if card.GroupName != "" {
+		writeText(content, "F2", 12, left, y, cardWidth-2*cardPadding, card.GroupName)
+	}
+	y -= 18
+	if platformURL != "" {
+		writeText(content, "F1", 9, left, y, cardWidth-2*cardPadding, platformURL)
+	}
+
+	y -= lineSpacing + 4
+	writeText(content, "F1", 10, left, y, labelWidth, "Login:")
+	writeText(content, "F3", 12, left+labelWidth, y, valueWidth, card.Login)
+
+	y -= lineSpacing
+	writeText(content, "F1", 10, left, y, labelWidth, "Password:")
+	if card.Password != "" {
+		writeText(content, "F3", 12, left+labelWidth, y, valueWidth, card.Password)
+	} else {
+		fmt.Fprintf(content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", left+labelWidth, y-2, left+labelWidth+valueWidth, y-2)
+	}
+
+	if card.GroupCode != "" {
+		y -= lineSpacing
+		writeText(content, "F1", 10, left, y, labelWidth, "Group code:")
+		writeText(content, "F3", 12, left+labelWidth, y, valueWidth, card.GroupCode)
+	}
+}
+
+func writeText(content *strings.Builder, font string, size, x, y, maxWidth float64, text string) {
+	maxLength := int(maxWidth / (size * charWidthRatio))
+	if runes := []rune(text); len(runes) > maxLength {
+		text = string(runes[:maxLength-1]) + "…"
+	}
+	fmt.Fprintf(content, "BT /%s %.0f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, encodeText(text))
+}
+
+// encodeText converts the text into the WinAnsi encoding (replacing unsupported characters with '?')
+// and escapes it for a PDF literal string.
+func encodeText(text string) string {
+	var result strings.Builder
+	for _, r := range text {
+		b, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		if b == '(' || b == ')' || b == '\\' {
+			result.WriteByte('\\')
+		}
+		result.WriteByte(b)
+	}
+	return result.String()
+}
+
+type pdfDocument struct {
+	objects []string
+}
+
+func (d *pdfDocument) addObject(object string) {
+	d.objects = append(d.objects, object)
+}
+
+func (d *pdfDocument) bytes() []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("%PDF-1.4\n")
+	offsets := make([]int, 0, len(d.objects))
+	for index, object := range d.objects {
+		offsets = append(offsets, buffer.Len())
+		fmt.Fprintf(&buffer, "%d 0 obj\n%s\nendobj\n", index+1, object)
+	}
+	xrefOffset := buffer.Len()
+	fmt.Fprintf(&buffer, "xref\n0 %d\n0000000000 65535 f \n", len(d.objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buffer, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buffer, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.objects)+1, xrefOffset)
+	return buffer.Bytes()
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
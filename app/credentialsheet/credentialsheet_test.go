@@ -0,0 +1,75 @@
+package credentialsheet
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	cards := make([]Card, 0, CardsPerPage+1)
+	for i := 0; i < CardsPerPage; i++ {
+		cards = append(cards, Card{Login: fmt.Sprintf("test_custom_%02d", i), Password: "secret", GroupName: "Class (A)"})
+	}
+	cards = append(cards, Card{Login: "test_custom_last", GroupName: "Élèves ☺", GroupCode: "3456789abc"})
+
+	var buffer bytes.Buffer
+	require.NoError(t, Write(&buffer, "https://example.org", cards))
+	document := buffer.String()
+
+	assert.True(t, strings.HasPrefix(document, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(document, "%%EOF\n"))
+	assert.Contains(t, document, "/Count 2 >>")
+	assert.Equal(t, 2, strings.Count(document, "/Type /Page "))
+	assert.Equal(t, 2, strings.Count(document, "0.6 G 0.5 w [4 4] 0 d\n"))
+	assert.Equal(t, 1, strings.Count(document, "\n0.5 w ")) // the blank line for the password
+	for i := 0; i < CardsPerPage; i++ {
+		assert.Contains(t, document, fmt.Sprintf("(test_custom_%02d) Tj", i))
+	}
+	assert.Equal(t, CardsPerPage, strings.Count(document, "(secret) Tj"))
+	assert.Equal(t, 11, strings.Count(document, "(https://example.org) Tj"))
+	assert.Equal(t, CardsPerPage, strings.Count(document, `(Class \(A\)) Tj`))
+	assert.Contains(t, document, "(\xc9l\xe8ves ?) Tj")
+	assert.Contains(t, document, "(3456789abc) Tj")
+	assert.Equal(t, 1, strings.Count(document, "(Group code:) Tj"))
+
+	// the cross-reference table points to the objects
+	xrefOffset, err := strconv.Atoi(regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(document)[1])
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(document[xrefOffset:], "xref\n0 10\n"))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(document[xrefOffset:], -1)
+	require.Len(t, entries, 9)
+	for index, entry := range entries {
+		offset, err := strconv.Atoi(entry[1])
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(document[offset:], fmt.Sprintf("%d 0 obj\n", index+1)))
+	}
+
+	// the stream lengths are correct
+	for _, match := range regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)\nendstream`).FindAllStringSubmatch(document, -1) {
+		assert.Equal(t, match[1], strconv.Itoa(len(match[2])))
+	}
+
+	var sameBuffer bytes.Buffer
+	require.NoError(t, Write(&sameBuffer, "https://example.org", cards))
+	assert.Equal(t, document, sameBuffer.String())
+}
+
+func TestWrite_WithoutCards(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, Write(&buffer, "", nil))
+	assert.Contains(t, buffer.String(), "/Count 1 >>")
+	assert.NotContains(t, buffer.String(), "Tj")
+}
+
+func Test_writeText_TruncatesLongTexts(t *testing.T) {
+	var content strings.Builder
+	writeText(&content, "F3", 10, 1, 2, 60, "abcdefghijklmnop")
+	assert.Equal(t, "BT /F3 10 Tf 1.00 2.00 Td (abcdefghi\x85) Tj ET\n", content.String())
+}
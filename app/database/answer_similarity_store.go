@@ -0,0 +1,94 @@
+package database
+
+import "github.com/France-ioi/AlgoreaBackend/v2/app/similarity"
+
+// AnswerSimilarityStore implements database operations on `answer_similarities`.
+type AnswerSimilarityStore struct {
+	*DataStore
+}
+
+const answerSimilaritiesInsertChunkSize = 1000
+
+// DefaultMinStoredAnswerSimilarity is the default minimal similarity of pairs of answers stored by the similarity analysis.
+const DefaultMinStoredAnswerSimilarity = 0.5
+
+// ComputeForItemAndGroup computes the pairwise similarity of the latest submissions for the item
+// of participants being descendants of the group (or the group itself),
+// and stores pairs with the similarity >= minSimilarity into `answer_similarities`
+// (replacing the pairs of submissions of participants of the group stored by a previous analysis).
+// The method returns the number of the stored pairs.
+func (s *AnswerSimilarityStore) ComputeForItemAndGroup(itemID, groupID int64, minSimilarity float64) (pairsCount int, err error) {
+	s.mustBeInTransaction()
+	defer recoverPanics(&err)
+
+	participantsQuery := s.ActiveGroupAncestors().
+		Where("groups_ancestors_active.ancestor_group_id = ?", groupID).
+		Select("groups_ancestors_active.child_group_id")
+	mustNotBeError(s.Exec(`
+		DELETE answer_similarities FROM answer_similarities
+		JOIN answers AS answers1 ON answers1.id = answer_similarities.answer1_id
+		JOIN answers AS answers2 ON answers2.id = answer_similarities.answer2_id
+		WHERE answer_similarities.item_id = ? AND answers1.participant_id IN ? AND answers2.participant_id IN ?`,
+		itemID, participantsQuery.SubQuery(), participantsQuery.SubQuery()).Error())
+
+	var answers []struct {
+		ID     int64
+		Answer *string
+	}
+	mustNotBeError(s.Answers().
+		Where("answers.participant_id IN ?", participantsQuery.SubQuery()).
+		Where("answers.item_id = ?", itemID).
+		Where("answers.type = 'Submission'").
+		Where(`
+			NOT EXISTS(
+				SELECT 1 FROM answers AS later_answers
+				WHERE later_answers.participant_id = answers.participant_id AND later_answers.item_id = answers.item_id AND
+				      later_answers.type = 'Submission' AND
+				      (later_answers.created_at > answers.created_at OR
+				       later_answers.created_at = answers.created_at AND later_answers.id > answers.id)
+			)`).
+		Order("answers.id").
+		Select("answers.id, answers.answer").
+		Scan(&answers).Error())
+
+	fingerprints := make([]similarity.Fingerprint, 0, len(answers))
+	for index := range answers {
+		var text string
+		if answers[index].Answer != nil {
+			text = *answers[index].Answer
+		}
+		fingerprints = append(fingerprints, similarity.Compute(text))
+	}
+
+	now := Now()
+	rows := make([]map[string]interface{}, 0, answerSimilaritiesInsertChunkSize)
+	flush := func() {
+		if len(rows) == 0 {
+			return
+		}
+		mustNotBeError(s.InsertOrUpdateMaps(rows, []string{"similarity", "computed_at"}))
+		pairsCount += len(rows)
+		rows = rows[:0]
+	}
+	for index1 := range answers {
+		for index2 := index1 + 1; index2 < len(answers); index2++ {
+			pairSimilarity := similarity.Compare(fingerprints[index1], fingerprints[index2])
+			if pairSimilarity == 0 || pairSimilarity < minSimilarity {
+				continue
+			}
+			rows = append(rows, map[string]interface{}{
+				"answer1_id":  answers[index1].ID,
+				"answer2_id":  answers[index2].ID,
+				"item_id":     itemID,
+				"similarity":  pairSimilarity,
+				"computed_at": now,
+			})
+			if len(rows) == answerSimilaritiesInsertChunkSize {
+				flush()
+			}
+		}
+	}
+	flush()
+
+	return pairsCount, nil
+}
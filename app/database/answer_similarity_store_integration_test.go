@@ -0,0 +1,74 @@
+//go:build !unit
+
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+func TestAnswerSimilarityStore_ComputeForItemAndGroup(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		groups: [{id: 11}, {id: 12}, {id: 13}, {id: 14}, {id: 15}, {id: 16}]
+		groups_groups:
+			- {parent_group_id: 13, child_group_id: 11}
+			- {parent_group_id: 13, child_group_id: 12}
+			- {parent_group_id: 13, child_group_id: 15}
+			- {parent_group_id: 16, child_group_id: 14}
+		items: [{id: 50, type: Task, default_language_tag: fr}]
+		attempts: [{participant_id: 11, id: 0}, {participant_id: 12, id: 0}, {participant_id: 14, id: 0}, {participant_id: 15, id: 0}]
+		results:
+			- {participant_id: 11, attempt_id: 0, item_id: 50}
+			- {participant_id: 12, attempt_id: 0, item_id: 50}
+			- {participant_id: 14, attempt_id: 0, item_id: 50}
+			- {participant_id: 15, attempt_id: 0, item_id: 50}
+		answers:
+			- {id: 101, author_id: 11, participant_id: 11, attempt_id: 0, item_id: 50, type: Submission,
+			   answer: "print(1)", created_at: "2020-01-01 06:00:00"}
+			- {id: 102, author_id: 11, participant_id: 11, attempt_id: 0, item_id: 50, type: Submission,
+			   answer: "n = int(input())\nprint(n * (n + 1) // 2 + 0)", created_at: "2020-01-01 07:00:00"}
+			- {id: 103, author_id: 12, participant_id: 12, attempt_id: 0, item_id: 50, type: Submission,
+			   answer: "n = int(input())\nprint(n * (n + 1) // 2)", created_at: "2020-01-01 08:00:00"}
+			- {id: 104, author_id: 12, participant_id: 12, attempt_id: 0, item_id: 50, type: Saved,
+			   answer: "print(2)", created_at: "2020-01-01 09:00:00"}
+			- {id: 106, author_id: 15, participant_id: 15, attempt_id: 0, item_id: 50, type: Submission,
+			   answer: "N = int(input())\nprint(N * (N + 1) // 2)", created_at: "2020-01-01 10:00:00"}
+			- {id: 107, author_id: 14, participant_id: 14, attempt_id: 0, item_id: 50, type: Submission,
+			   answer: "n = int(input())\nprint(n * (n + 1) // 2)", created_at: "2020-01-01 11:00:00"}
+		answer_similarities:
+			- {answer1_id: 101, answer2_id: 103, item_id: 50, similarity: 0.75, computed_at: "2020-01-02 00:00:00"}
+			- {answer1_id: 103, answer2_id: 107, item_id: 50, similarity: 1, computed_at: "2020-01-02 00:00:00"}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.InTransaction(func(store *database.DataStore) error {
+		return store.GroupGroups().CreateNewAncestors()
+	}))
+
+	testhelpers.MockDBTime("2020-02-01 00:00:00")
+	defer testhelpers.RestoreDBTime()
+
+	var pairsCount int
+	require.NoError(t, store.InTransaction(func(store *database.DataStore) (err error) {
+		pairsCount, err = store.AnswerSimilarities().ComputeForItemAndGroup(50, 13, 0.9)
+		return err
+	}))
+	assert.Equal(t, 1, pairsCount)
+
+	var pairs []map[string]interface{}
+	require.NoError(t, store.AnswerSimilarities().
+		Select("answer1_id, answer2_id, ROUND(similarity, 2) AS similarity, CAST(computed_at AS CHAR) AS computed_at").
+		Order("answer1_id, answer2_id").ScanIntoSliceOfMaps(&pairs).Error())
+	assert.Equal(t, []map[string]interface{}{
+		{"answer1_id": int64(103), "answer2_id": int64(106), "similarity": 1.0, "computed_at": "2020-02-01 00:00:00"},
+		{"answer1_id": int64(103), "answer2_id": int64(107), "similarity": 1.0, "computed_at": "2020-01-02 00:00:00"},
+	}, pairs)
+}
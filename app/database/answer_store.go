@@ -5,15 +5,27 @@ type AnswerStore struct {
 	*DataStore
 }
 
+const answersWithGradingsColumns = `
+	answers.id, answers.author_id, answers.item_id, answers.attempt_id, answers.participant_id,
+	answers.type, answers.state, answers.answer, answers.created_at, gradings.score,
+	gradings.graded_at`
+
 // WithGradings creates a composable query for getting answers joined with gradings (via answer_id).
 func (s *AnswerStore) WithGradings() *AnswerStore {
 	return &AnswerStore{
 		NewDataStoreWithTable(
-			s.Select(`
-					answers.id, answers.author_id, answers.item_id, answers.attempt_id, answers.participant_id,
-					answers.type, answers.state, answers.answer, answers.created_at, gradings.score,
-					gradings.graded_at
-				`).
+			s.Select(answersWithGradingsColumns).
+				Joins("LEFT JOIN gradings ON gradings.answer_id = answers.id"), s.tableName,
+		),
+	}
+}
+
+// WithGradingsAndFeedbacks creates a composable query for getting answers joined with gradings (via answer_id)
+// like WithGradings(), but also selects the feedbacks of manual gradings.
+func (s *AnswerStore) WithGradingsAndFeedbacks() *AnswerStore {
+	return &AnswerStore{
+		NewDataStoreWithTable(
+			s.Select(answersWithGradingsColumns+", gradings.feedback").
 				Joins("LEFT JOIN gradings ON gradings.answer_id = answers.id"), s.tableName,
 		),
 	}
@@ -71,11 +71,26 @@ func (s *DataStore) Answers() *AnswerStore {
 	return &AnswerStore{NewDataStoreWithTable(s.DB, "answers")}
 }
 
+// AnswerSimilarities returns an AnswerSimilarityStore.
+func (s *DataStore) AnswerSimilarities() *AnswerSimilarityStore {
+	return &AnswerSimilarityStore{NewDataStoreWithTable(s.DB, "answer_similarities")}
+}
+
 // Attempts returns a AttemptStore.
 func (s *DataStore) Attempts() *AttemptStore {
 	return &AttemptStore{NewDataStoreWithTable(s.DB, "attempts")}
 }
 
+// EmailNotifications returns an EmailNotificationStore.
+func (s *DataStore) EmailNotifications() *EmailNotificationStore {
+	return &EmailNotificationStore{NewDataStoreWithTable(s.DB, "email_notifications")}
+}
+
+// ExportJobs returns an ExportJobStore.
+func (s *DataStore) ExportJobs() *ExportJobStore {
+	return &ExportJobStore{NewDataStoreWithTable(s.DB, "export_jobs")}
+}
+
 // Gradings returns a GradingStore.
 func (s *DataStore) Gradings() *GradingStore {
 	return &GradingStore{NewDataStoreWithTable(s.DB, "gradings")}
@@ -86,6 +101,11 @@ func (s *DataStore) Users() *UserStore {
 	return &UserStore{NewDataStoreWithTable(s.DB, "users")}
 }
 
+// UserIdentities returns a UserIdentityStore.
+func (s *DataStore) UserIdentities() *UserIdentityStore {
+	return &UserIdentityStore{NewDataStoreWithTable(s.DB, "user_identities")}
+}
+
 // Items returns a ItemStore.
 func (s *DataStore) Items() *ItemStore {
 	return &ItemStore{NewDataStoreWithTable(s.DB, "items")}
@@ -156,6 +176,11 @@ func (s *DataStore) ItemItems() *ItemItemStore {
 	return &ItemItemStore{NewDataStoreWithTable(s.DB, "items_items")}
 }
 
+// ItemRevisions returns an ItemRevisionStore.
+func (s *DataStore) ItemRevisions() *ItemRevisionStore {
+	return &ItemRevisionStore{NewDataStoreWithTable(s.DB, "item_revisions")}
+}
+
 // ItemDependencies returns an ItemDependencyStore.
 func (s *DataStore) ItemDependencies() *ItemDependencyStore {
 	return &ItemDependencyStore{NewDataStoreWithTable(s.DB, "item_dependencies")}
@@ -166,11 +191,51 @@ func (s *DataStore) Languages() *LanguageStore {
 	return &LanguageStore{NewDataStoreWithTable(s.DB, "languages")}
 }
 
+// LTIContexts returns an LTIContextStore.
+func (s *DataStore) LTIContexts() *LTIContextStore {
+	return &LTIContextStore{NewDataStoreWithTable(s.DB, "lti_contexts")}
+}
+
+// LTIDeepLinkingRequests returns an LTIDeepLinkingRequestStore.
+func (s *DataStore) LTIDeepLinkingRequests() *LTIDeepLinkingRequestStore {
+	return &LTIDeepLinkingRequestStore{NewDataStoreWithTable(s.DB, "lti_deep_linking_requests")}
+}
+
+// LTILaunchStates returns an LTILaunchStateStore.
+func (s *DataStore) LTILaunchStates() *LTILaunchStateStore {
+	return &LTILaunchStateStore{NewDataStoreWithTable(s.DB, "lti_launch_states")}
+}
+
+// LTILineItems returns an LTILineItemStore.
+func (s *DataStore) LTILineItems() *LTILineItemStore {
+	return &LTILineItemStore{NewDataStoreWithTable(s.DB, "lti_line_items")}
+}
+
+// Notifications returns a NotificationStore.
+func (s *DataStore) Notifications() *NotificationStore {
+	return &NotificationStore{NewDataStoreWithTable(s.DB, "notifications")}
+}
+
+// ParticipantEvents returns a ParticipantEventStore.
+func (s *DataStore) ParticipantEvents() *ParticipantEventStore {
+	return &ParticipantEventStore{NewDataStoreWithTable(s.DB, "participant_events")}
+}
+
 // Platforms returns a PlatformStore.
 func (s *DataStore) Platforms() *PlatformStore {
 	return &PlatformStore{NewDataStoreWithTable(s.DB, "platforms")}
 }
 
+// PropagationQueue returns a PropagationQueueStore.
+func (s *DataStore) PropagationQueue() *PropagationQueueStore {
+	return &PropagationQueueStore{NewDataStoreWithTable(s.DB, "propagation_queue")}
+}
+
+// RateLimitBuckets returns a RateLimitBucketStore.
+func (s *DataStore) RateLimitBuckets() *RateLimitBucketStore {
+	return &RateLimitBucketStore{NewDataStoreWithTable(s.DB, "rate_limit_buckets")}
+}
+
 // Results returns a ResultStore.
 func (s *DataStore) Results() *ResultStore {
 	return &ResultStore{NewDataStoreWithTable(s.DB, "results")}
@@ -201,6 +266,16 @@ func (s *DataStore) UserBatchPrefixes() *UserBatchPrefixStore {
 	return &UserBatchPrefixStore{NewDataStoreWithTable(s.DB, "user_batch_prefixes")}
 }
 
+// Webhooks returns a WebhookStore.
+func (s *DataStore) Webhooks() *WebhookStore {
+	return &WebhookStore{NewDataStoreWithTable(s.DB, "webhooks")}
+}
+
+// WebhookDeliveries returns a WebhookDeliveryStore.
+func (s *DataStore) WebhookDeliveries() *WebhookDeliveryStore {
+	return &WebhookDeliveryStore{NewDataStoreWithTable(s.DB, "webhook_deliveries")}
+}
+
 // NewID generates a positive random int64 to be used as id
 // !!! To be safe, the insertion should be retried if the id conflicts with an existing entry.
 func (s *DataStore) NewID() int64 {
@@ -27,7 +27,10 @@ func TestDataStore_StoreConstructorsSetTablesCorrectly(t *testing.T) {
 		wantTable string
 	}{
 		{"Answers", func(store *DataStore) *DB { return store.Answers().Where("") }, "`answers`"},
+		{"AnswerSimilarities", func(store *DataStore) *DB { return store.AnswerSimilarities().Where("") }, "`answer_similarities`"},
 		{"Attempts", func(store *DataStore) *DB { return store.Attempts().Where("") }, "`attempts`"},
+		{"EmailNotifications", func(store *DataStore) *DB { return store.EmailNotifications().Where("") }, "`email_notifications`"},
+		{"ExportJobs", func(store *DataStore) *DB { return store.ExportJobs().Where("") }, "`export_jobs`"},
 		{"Gradings", func(store *DataStore) *DB { return store.Gradings().Where("") }, "`gradings`"},
 		{"Groups", func(store *DataStore) *DB { return store.Groups().Where("") }, "`groups`"},
 		{"GroupAncestors", func(store *DataStore) *DB { return store.GroupAncestors().Where("") }, "`groups_ancestors`"},
@@ -44,16 +47,28 @@ func TestDataStore_StoreConstructorsSetTablesCorrectly(t *testing.T) {
 		{"ItemAncestors", func(store *DataStore) *DB { return store.ItemAncestors().Where("") }, "`items_ancestors`"},
 		{"ItemItems", func(store *DataStore) *DB { return store.ItemItems().Where("") }, "`items_items`"},
 		{"ItemStrings", func(store *DataStore) *DB { return store.ItemStrings().Where("") }, "`items_strings`"},
+		{"ItemRevisions", func(store *DataStore) *DB { return store.ItemRevisions().Where("") }, "`item_revisions`"},
 		{"ItemDependencies", func(store *DataStore) *DB { return store.ItemDependencies().Where("") }, "`item_dependencies`"},
 		{"Languages", func(store *DataStore) *DB { return store.Languages().Where("") }, "`languages`"},
+		{"LTIContexts", func(store *DataStore) *DB { return store.LTIContexts().Where("") }, "`lti_contexts`"},
+		{"LTIDeepLinkingRequests", func(store *DataStore) *DB { return store.LTIDeepLinkingRequests().Where("") }, "`lti_deep_linking_requests`"},
+		{"LTILaunchStates", func(store *DataStore) *DB { return store.LTILaunchStates().Where("") }, "`lti_launch_states`"},
+		{"LTILineItems", func(store *DataStore) *DB { return store.LTILineItems().Where("") }, "`lti_line_items`"},
+		{"Notifications", func(store *DataStore) *DB { return store.Notifications().Where("") }, "`notifications`"},
+		{"ParticipantEvents", func(store *DataStore) *DB { return store.ParticipantEvents().Where("") }, "`participant_events`"},
 		{"Platforms", func(store *DataStore) *DB { return store.Platforms().Where("") }, "`platforms`"},
+		{"PropagationQueue", func(store *DataStore) *DB { return store.PropagationQueue().Where("") }, "`propagation_queue`"},
+		{"RateLimitBuckets", func(store *DataStore) *DB { return store.RateLimitBuckets().Where("") }, "`rate_limit_buckets`"},
 		{"Results", func(store *DataStore) *DB { return store.Results().Where("") }, "`results`"},
 		{"Sessions", func(store *DataStore) *DB { return store.Sessions().Where("") }, "`sessions`"},
 		{"AccessTokens", func(store *DataStore) *DB { return store.AccessTokens().Where("") }, "`access_tokens`"},
 		{"Threads", func(store *DataStore) *DB { return store.Threads().Where("") }, "`threads`"},
 		{"Users", func(store *DataStore) *DB { return store.Users().Where("") }, "`users`"},
+		{"UserIdentities", func(store *DataStore) *DB { return store.UserIdentities().Where("") }, "`user_identities`"},
 		{"UserBatches", func(store *DataStore) *DB { return store.UserBatches().Where("") }, "`user_batches_v2`"},
 		{"UserBatchPrefixes", func(store *DataStore) *DB { return store.UserBatchPrefixes().Where("") }, "`user_batch_prefixes`"},
+		{"Webhooks", func(store *DataStore) *DB { return store.Webhooks().Where("") }, "`webhooks`"},
+		{"WebhookDeliveries", func(store *DataStore) *DB { return store.WebhookDeliveries().Where("") }, "`webhook_deliveries`"},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -79,8 +94,11 @@ func TestDataStore_StoreConstructorsReturnObjectsOfRightTypes(t *testing.T) {
 		wantType interface{}
 	}{
 		{"Answers", func(store *DataStore) interface{} { return store.Answers() }, &AnswerStore{}},
+		{"AnswerSimilarities", func(store *DataStore) interface{} { return store.AnswerSimilarities() }, &AnswerSimilarityStore{}},
 		{"Attempts", func(store *DataStore) interface{} { return store.Attempts() }, &AttemptStore{}},
 		{"Gradings", func(store *DataStore) interface{} { return store.Gradings() }, &GradingStore{}},
+		{"EmailNotifications", func(store *DataStore) interface{} { return store.EmailNotifications() }, &EmailNotificationStore{}},
+		{"ExportJobs", func(store *DataStore) interface{} { return store.ExportJobs() }, &ExportJobStore{}},
 		{"Groups", func(store *DataStore) interface{} { return store.Groups() }, &GroupStore{}},
 		{"GroupAncestors", func(store *DataStore) interface{} { return store.GroupAncestors() }, &GroupAncestorStore{}},
 		{"ActiveGroupAncestors", func(store *DataStore) interface{} { return store.ActiveGroupAncestors() }, &GroupAncestorStore{}},
@@ -96,16 +114,28 @@ func TestDataStore_StoreConstructorsReturnObjectsOfRightTypes(t *testing.T) {
 		{"ItemAncestors", func(store *DataStore) interface{} { return store.ItemAncestors() }, &ItemAncestorStore{}},
 		{"ItemItems", func(store *DataStore) interface{} { return store.ItemItems() }, &ItemItemStore{}},
 		{"ItemStrings", func(store *DataStore) interface{} { return store.ItemStrings() }, &ItemStringStore{}},
+		{"ItemRevisions", func(store *DataStore) interface{} { return store.ItemRevisions() }, &ItemRevisionStore{}},
 		{"ItemDependencies", func(store *DataStore) interface{} { return store.ItemDependencies() }, &ItemDependencyStore{}},
 		{"Languages", func(store *DataStore) interface{} { return store.Languages() }, &LanguageStore{}},
+		{"LTIContexts", func(store *DataStore) interface{} { return store.LTIContexts() }, &LTIContextStore{}},
+		{"LTIDeepLinkingRequests", func(store *DataStore) interface{} { return store.LTIDeepLinkingRequests() }, &LTIDeepLinkingRequestStore{}},
+		{"LTILaunchStates", func(store *DataStore) interface{} { return store.LTILaunchStates() }, &LTILaunchStateStore{}},
+		{"LTILineItems", func(store *DataStore) interface{} { return store.LTILineItems() }, &LTILineItemStore{}},
+		{"Notifications", func(store *DataStore) interface{} { return store.Notifications() }, &NotificationStore{}},
+		{"ParticipantEvents", func(store *DataStore) interface{} { return store.ParticipantEvents() }, &ParticipantEventStore{}},
 		{"Platforms", func(store *DataStore) interface{} { return store.Platforms() }, &PlatformStore{}},
+		{"PropagationQueue", func(store *DataStore) interface{} { return store.PropagationQueue() }, &PropagationQueueStore{}},
+		{"RateLimitBuckets", func(store *DataStore) interface{} { return store.RateLimitBuckets() }, &RateLimitBucketStore{}},
 		{"Results", func(store *DataStore) interface{} { return store.Results() }, &ResultStore{}},
 		{"Sessions", func(store *DataStore) interface{} { return store.Sessions() }, &SessionStore{}},
 		{"AccessTokens", func(store *DataStore) interface{} { return store.AccessTokens() }, &AccessTokenStore{}},
 		{"Threads", func(store *DataStore) interface{} { return store.Threads() }, &ThreadStore{}},
 		{"Users", func(store *DataStore) interface{} { return store.Users() }, &UserStore{}},
+		{"UserIdentities", func(store *DataStore) interface{} { return store.UserIdentities() }, &UserIdentityStore{}},
 		{"UserBatches", func(store *DataStore) interface{} { return store.UserBatches() }, &UserBatchStore{}},
 		{"UserBatchPrefixes", func(store *DataStore) interface{} { return store.UserBatchPrefixes() }, &UserBatchPrefixStore{}},
+		{"Webhooks", func(store *DataStore) interface{} { return store.Webhooks() }, &WebhookStore{}},
+		{"WebhookDeliveries", func(store *DataStore) interface{} { return store.WebhookDeliveries() }, &WebhookDeliveryStore{}},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -18,9 +18,13 @@ import (
 	"github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
 	"github.com/luna-duclos/instrumentedsql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	log "github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/metrics"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/rand"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/tracing"
 	"github.com/France-ioi/AlgoreaBackend/v2/golang"
 )
 
@@ -145,7 +149,10 @@ func (conn *DB) New() *DB {
 }
 
 func (conn *DB) inTransaction(txFunc func(*DB) error, txOptions ...*sql.TxOptions) (err error) {
-	return conn.inTransactionWithCount(txFunc, 0, txOptions...)
+	ctx, span := tracing.StartSpan(conn.ctx, "database.InTransaction")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return newDB(ctx, conn.db, conn.ctes, conn.logConfig).inTransactionWithCount(txFunc, 0, txOptions...)
 }
 
 const (
@@ -263,6 +270,10 @@ func (conn *DB) handleDeadlockAndLockWaitTimeout(txFunc func(*DB) error, count i
 		}
 		log.SharedLogger.WithContext(conn.ctx).WithField("type", "db").
 			Infof("Retrying transaction (count: %d) after %s", count+1, errToHandleError.Error())
+		retryReason := golang.IfElse(IsDeadlockError(errToHandleError), "deadlock", "lock_wait_timeout")
+		metrics.DBTransactionRetries.WithLabelValues(retryReason).Inc()
+		trace.SpanFromContext(conn.ctx).AddEvent("transaction retry",
+			trace.WithAttributes(attribute.Int64("retry.count", count+1), attribute.String("retry.reason", retryReason)))
 		*returnErr = conn.inTransactionWithCount(txFunc, count+1, txOptions...)
 		return true
 	}
@@ -278,6 +289,9 @@ func (conn *DB) isInTransaction() bool {
 
 func (conn *DB) withNamedLock(lockName string, timeout time.Duration, funcToCall func(*DB) error) (err error) {
 	initGetLockTime := time.Now()
+	// the span only covers the lock acquisition
+	_, lockSpan := tracing.StartSpan(conn.ctx, "database.WithNamedLock",
+		trace.WithAttributes(attribute.String("lock.name", lockName), attribute.String("lock.timeout", timeout.String())))
 
 	var sqlDB *sql.DB
 	if conn.isInTransaction() {
@@ -293,6 +307,7 @@ func (conn *DB) withNamedLock(lockName string, timeout time.Duration, funcToCall
 	var shouldDiscardNamedLockDBConnection bool
 	namedLockDBConnection, err := sqlDBWrapped.conn(conn.ctx)
 	if err != nil {
+		tracing.EndSpan(lockSpan, err)
 		return err
 	}
 	defer func() {
@@ -304,9 +319,11 @@ func (conn *DB) withNamedLock(lockName string, timeout time.Duration, funcToCall
 	var getLockResult *int64
 	err = namedLockDBConnection.QueryRowContext(conn.ctx, "SELECT GET_LOCK(?, ?)", lockName, int64(timeout/time.Second)).Scan(&getLockResult)
 	if err != nil {
+		tracing.EndSpan(lockSpan, err)
 		return err
 	}
 	if getLockResult == nil || *getLockResult != 1 {
+		tracing.EndSpan(lockSpan, ErrNamedLockWaitTimeoutExceeded)
 		return ErrNamedLockWaitTimeoutExceeded
 	}
 
@@ -324,6 +341,7 @@ func (conn *DB) withNamedLock(lockName string, timeout time.Duration, funcToCall
 		}
 	}()
 
+	tracing.EndSpan(lockSpan, nil)
 	log.SharedLogger.WithContext(conn.ctx).WithField("type", "db").
 		Debugf("Duration for GET_LOCK(%s, %v): %v", lockName, timeout, time.Since(initGetLockTime))
 
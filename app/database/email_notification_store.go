@@ -0,0 +1,201 @@
+package database
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// EmailNotificationTypeInvitationCreated is the type of email notifications sent to users invited to join a group.
+	EmailNotificationTypeInvitationCreated = "invitation_created"
+	// EmailNotificationTypeJoinRequestAccepted is the type of email notifications sent to users whose join request has been accepted.
+	EmailNotificationTypeJoinRequestAccepted = "join_request_accepted"
+	// EmailNotificationTypeThreadStatusChanged is the type of email notifications sent to participants of a thread
+	// whose status has been changed by someone else.
+	EmailNotificationTypeThreadStatusChanged = "thread_status_changed"
+	// EmailNotificationTypeMembershipExpiring is the type of email notifications sent to members of a group
+	// whose membership is about to expire.
+	EmailNotificationTypeMembershipExpiring = "membership_expiring"
+)
+
+// EmailNotificationTypes returns the list of all the types of email notifications.
+func EmailNotificationTypes() []string {
+	return []string{
+		EmailNotificationTypeInvitationCreated, EmailNotificationTypeJoinRequestAccepted,
+		EmailNotificationTypeThreadStatusChanged, EmailNotificationTypeMembershipExpiring,
+	}
+}
+
+// emailNotificationRecipientCondition is the condition on `users` to be satisfied by the recipients
+// of email notifications of the type given as the parameter.
+const emailNotificationRecipientCondition = `
+	NOT users.temp_user AND IFNULL(users.email, '') != '' AND NOT FIND_IN_SET(?, users.email_notifications_opt_out)`
+
+// EmailNotificationStore implements database operations on `email_notifications`.
+type EmailNotificationStore struct {
+	*DataStore
+}
+
+// enqueueForGroupMembershipChanges enqueues notifications of the given changes of members of the given group
+// ('invitation_created' and 'join_request_accepted' ones, other changes are ignored).
+func (s *EmailNotificationStore) enqueueForGroupMembershipChanges(groupID int64, changes map[int64]GroupMembershipAction) error {
+	memberIDsByType := make(map[string][]int64, 2)
+	for memberID, toAction := range changes {
+		switch GroupMembershipAction(toAction[strings.LastIndex(string(toAction), ",")+1:]) {
+		case InvitationCreated:
+			memberIDsByType[EmailNotificationTypeInvitationCreated] = append(
+				memberIDsByType[EmailNotificationTypeInvitationCreated], memberID)
+		case JoinRequestAccepted:
+			memberIDsByType[EmailNotificationTypeJoinRequestAccepted] = append(
+				memberIDsByType[EmailNotificationTypeJoinRequestAccepted], memberID)
+		}
+	}
+
+	for _, notificationType := range []string{EmailNotificationTypeInvitationCreated, EmailNotificationTypeJoinRequestAccepted} {
+		memberIDs := memberIDsByType[notificationType]
+		if len(memberIDs) == 0 {
+			continue
+		}
+		if err := s.db.Exec(`
+			INSERT INTO email_notifications (user_id, type, payload)
+			SELECT
+				users.group_id, ?,
+				JSON_OBJECT('group_id', CAST(groups.id AS CHAR), 'group_name', groups.name)
+			FROM users
+			JOIN `+"`groups`"+` ON groups.id = ?
+			WHERE users.group_id IN (?) AND`+emailNotificationRecipientCondition,
+			notificationType, groupID, memberIDs, notificationType).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnqueueForThreadStatusChange enqueues notifications of a change of the status of the thread of the given item
+// & participant for the participant (or for the members of the participant team), except for the user
+// who has changed the status. The title of the item is in the language of each recipient if possible.
+func (s *EmailNotificationStore) EnqueueForThreadStatusChange(itemID, participantID int64, status string, initiatorID int64) error {
+	return s.db.Exec(`
+		INSERT INTO email_notifications (user_id, type, payload)
+		SELECT
+			users.group_id, ?,
+			JSON_OBJECT(
+				'item_id', CAST(items.id AS CHAR),
+				'participant_id', CAST(? AS CHAR),
+				'item_title', IFNULL(COALESCE(user_strings.title, default_strings.title), ''),
+				'status', ?
+			)
+		FROM users
+		JOIN items ON items.id = ?
+		LEFT JOIN items_strings AS default_strings
+			ON default_strings.item_id = items.id AND default_strings.language_tag = items.default_language_tag
+		LEFT JOIN items_strings AS user_strings
+			ON user_strings.item_id = items.id AND user_strings.language_tag = users.default_language
+		WHERE (users.group_id = ? OR users.group_id IN (
+				SELECT child_group_id FROM groups_groups_active WHERE parent_group_id = ?
+			)) AND users.group_id != ? AND`+emailNotificationRecipientCondition,
+		EmailNotificationTypeThreadStatusChanged, participantID, status, itemID,
+		participantID, participantID, initiatorID, EmailNotificationTypeThreadStatusChanged).Error
+}
+
+// EnqueueForExpiringMemberships enqueues notifications for the members of groups whose membership
+// expires within the given duration. A member is notified only once for an expiration time of a membership.
+// It returns the number of enqueued notifications.
+func (s *EmailNotificationStore) EnqueueForExpiringMemberships(within time.Duration) (enqueuedCount int64, err error) {
+	result := s.db.Exec(`
+		INSERT INTO email_notifications (user_id, type, payload, deduplication_key)
+		SELECT
+			users.group_id, ?,
+			JSON_OBJECT(
+				'group_id', CAST(groups.id AS CHAR),
+				'group_name', groups.name,
+				'expires_at', DATE_FORMAT(groups_groups.expires_at, '%Y-%m-%d %H:%i')
+			),
+			CONCAT(?, ':', groups_groups.parent_group_id, ':', groups_groups.child_group_id, ':',
+				UNIX_TIMESTAMP(groups_groups.expires_at))
+		FROM groups_groups
+		JOIN users ON users.group_id = groups_groups.child_group_id
+		JOIN `+"`groups`"+` ON groups.id = groups_groups.parent_group_id
+		WHERE groups_groups.expires_at > NOW() AND groups_groups.expires_at <= NOW() + INTERVAL ? SECOND AND`+
+		emailNotificationRecipientCondition+`
+		ON DUPLICATE KEY UPDATE deduplication_key = email_notifications.deduplication_key`,
+		EmailNotificationTypeMembershipExpiring, EmailNotificationTypeMembershipExpiring,
+		int64(within.Seconds()), EmailNotificationTypeMembershipExpiring)
+	return result.RowsAffected, result.Error
+}
+
+// EmailNotification represents a pending notification together with its recipient.
+type EmailNotification struct {
+	ID            int64
+	UserID        int64
+	Type          string
+	Payload       string
+	AttemptsCount int
+	// the current email of the recipient, empty if the recipient cannot receive the notification anymore
+	// (no email or the type of notifications has been opted out)
+	Email           string
+	DefaultLanguage string
+}
+
+// GetDue returns at most `limit` pending notifications which can be attempted now, the oldest first.
+// As several workers can get the same notifications, a notification should be claimed (see Claim()) before being attempted.
+func (s *EmailNotificationStore) GetDue(limit int) (notifications []EmailNotification, err error) {
+	err = s.
+		Joins("JOIN users ON users.group_id = email_notifications.user_id").
+		Where("email_notifications.status = 'pending' AND email_notifications.next_attempt_at <= NOW(3)").
+		Select(`
+			email_notifications.id, email_notifications.user_id, email_notifications.type,
+			CAST(email_notifications.payload AS CHAR) AS payload, email_notifications.attempts_count,
+			IF(FIND_IN_SET(email_notifications.type, users.email_notifications_opt_out), '', IFNULL(users.email, '')) AS email,
+			users.default_language`).
+		Order("email_notifications.next_attempt_at, email_notifications.id").
+		Limit(limit).
+		Scan(&notifications).Error()
+	return notifications, err
+}
+
+// Claim postpones the next attempt of the given due notification by the given duration
+// so that other workers don't attempt it meanwhile. If the notification is neither marked as sent, canceled,
+// nor failed before the claim expires (e.g., because the worker has been stopped), it becomes due again.
+// It returns false if the notification is not due anymore (e.g., it has been claimed concurrently by another worker).
+func (s *EmailNotificationStore) Claim(notificationID int64, duration time.Duration) (claimed bool, err error) {
+	result := s.Where("id = ? AND status = 'pending' AND next_attempt_at <= NOW(3)", notificationID).
+		UpdateColumn("next_attempt_at", gorm.Expr("NOW(3) + INTERVAL ? MICROSECOND", duration.Microseconds()))
+	if result.Error() != nil {
+		return false, result.Error()
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// MarkAsSent records a successful attempt of the given notification.
+func (s *EmailNotificationStore) MarkAsSent(notificationID int64) error {
+	return s.Where("id = ?", notificationID).UpdateColumns(map[string]interface{}{
+		"status":          "sent",
+		"attempts_count":  gorm.Expr("attempts_count + 1"),
+		"last_attempt_at": gorm.Expr("NOW(3)"),
+		"last_error":      nil,
+	}).Error()
+}
+
+// MarkAsCanceled marks the given notification as canceled (when the recipient cannot receive it anymore).
+func (s *EmailNotificationStore) MarkAsCanceled(notificationID int64) error {
+	return s.Where("id = ?", notificationID).UpdateColumn("status", "canceled").Error()
+}
+
+// MarkAsFailedAttempt records a failed attempt of the given notification.
+// The next attempt is postponed by the given delay or, if giveUp is true, the notification is marked as 'failed'.
+func (s *EmailNotificationStore) MarkAsFailedAttempt(notificationID int64, attemptErr error, retryIn time.Duration, giveUp bool) error {
+	values := map[string]interface{}{
+		"attempts_count":  gorm.Expr("attempts_count + 1"),
+		"last_attempt_at": gorm.Expr("NOW(3)"),
+		"last_error":      attemptErr.Error(),
+	}
+	if giveUp {
+		values["status"] = "failed"
+	} else {
+		values["next_attempt_at"] = gorm.Expr("NOW(3) + INTERVAL ? MICROSECOND", retryIn.Microseconds())
+	}
+	return s.Where("id = ?", notificationID).UpdateColumns(values).Error()
+}
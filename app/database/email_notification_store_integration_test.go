@@ -0,0 +1,223 @@
+//go:build !unit
+
+package database_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+const emailNotificationsFixture = `
+	groups: [{id: 1, name: Club}, {id: 2, name: Team, type: Team}, {id: 3}, {id: 4}, {id: 5}, {id: 6}, {id: 7}]
+	users:
+		- {group_id: 3, login: jane, email: jane@example.org, default_language: fr}
+		- {group_id: 4, login: john, email: john@example.org, default_language: en,
+		   email_notifications_opt_out: "invitation_created,membership_expiring"}
+		- {group_id: 5, login: paul}
+		- {group_id: 6, login: tmp, email: tmp@example.org, temp_user: 1}
+		- {group_id: 7, login: anna, email: anna@example.org}
+	groups_ancestors:
+		- {ancestor_group_id: 1, child_group_id: 1}
+		- {ancestor_group_id: 2, child_group_id: 2}
+		- {ancestor_group_id: 3, child_group_id: 3}
+		- {ancestor_group_id: 4, child_group_id: 4}
+		- {ancestor_group_id: 5, child_group_id: 5}
+		- {ancestor_group_id: 6, child_group_id: 6}
+		- {ancestor_group_id: 7, child_group_id: 7}`
+
+func getEmailNotifications(t *testing.T, store *database.DataStore) []map[string]interface{} {
+	t.Helper()
+
+	var notifications []map[string]interface{}
+	require.NoError(t, store.EmailNotifications().
+		Select("user_id, type, CAST(payload AS CHAR) AS payload, status").
+		Order("user_id, id").ScanIntoSliceOfMaps(&notifications).Error())
+	return notifications
+}
+
+func TestEmailNotificationStore_EnqueuesInvitationsOnTransitions(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(emailNotificationsFixture)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.InTransaction(func(store *database.DataStore) error {
+		_, _, err := store.GroupGroups().Transition(database.AdminCreatesInvitation, 1, []int64{3, 4, 5, 6}, nil, 7)
+		return err
+	}))
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"user_id": int64(3), "type": "invitation_created",
+			"payload": `{"group_id": "1", "group_name": "Club"}`, "status": "pending",
+		},
+	}, getEmailNotifications(t, store))
+}
+
+func TestEmailNotificationStore_EnqueueForThreadStatusChange(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(emailNotificationsFixture, `
+		groups_groups: [{parent_group_id: 2, child_group_id: 3}, {parent_group_id: 2, child_group_id: 4},
+		                {parent_group_id: 2, child_group_id: 7}]
+		items: [{id: 10, default_language_tag: en}]
+		items_strings: [{item_id: 10, language_tag: en, title: Loops}, {item_id: 10, language_tag: fr, title: Boucles}]`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.EmailNotifications().EnqueueForThreadStatusChange(10, 2, "closed", 7))
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"user_id": int64(3), "type": "thread_status_changed",
+			"payload": `{"status": "closed", "item_id": "10", "item_title": "Boucles", "participant_id": "2"}`, "status": "pending",
+		},
+		{
+			"user_id": int64(4), "type": "thread_status_changed",
+			"payload": `{"status": "closed", "item_id": "10", "item_title": "Loops", "participant_id": "2"}`, "status": "pending",
+		},
+	}, getEmailNotifications(t, store))
+}
+
+func TestEmailNotificationStore_EnqueueForExpiringMemberships(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(emailNotificationsFixture, `
+		groups_groups:
+			- {parent_group_id: 1, child_group_id: 3, expires_at: "3000-01-01 00:00:00"}
+			- {parent_group_id: 1, child_group_id: 4}
+			- {parent_group_id: 1, child_group_id: 7}
+			- {parent_group_id: 2, child_group_id: 3}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.Exec(`
+		UPDATE groups_groups SET expires_at = NOW() + INTERVAL 1 DAY WHERE parent_group_id = 1 AND child_group_id IN (4, 7)`).Error())
+	require.NoError(t, store.Exec(`
+		UPDATE groups_groups SET expires_at = NOW() + INTERVAL 10 DAY WHERE parent_group_id = 2`).Error())
+
+	enqueuedCount, err := store.EmailNotifications().EnqueueForExpiringMemberships(3 * 24 * time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), enqueuedCount)
+
+	var expiresAt string
+	require.NoError(t, store.GroupGroups().Where("parent_group_id = 1 AND child_group_id = 7").
+		PluckFirst("DATE_FORMAT(expires_at, '%Y-%m-%d %H:%i')", &expiresAt).Error())
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"user_id": int64(7), "type": "membership_expiring",
+			"payload": `{"group_id": "1", "expires_at": "` + expiresAt + `", "group_name": "Club"}`, "status": "pending",
+		},
+	}, getEmailNotifications(t, store))
+
+	// each member is notified only once
+	enqueuedCount, err = store.EmailNotifications().EnqueueForExpiringMemberships(3 * 24 * time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, enqueuedCount)
+	assert.Len(t, getEmailNotifications(t, store), 1)
+}
+
+func TestEmailNotificationStore_GetDue(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(emailNotificationsFixture, `
+		email_notifications:
+			- {id: 1, user_id: 3, type: invitation_created, payload: '{"a": 1}', next_attempt_at: "2020-01-01 00:00:02"}
+			- {id: 2, user_id: 4, type: join_request_accepted, payload: '{"a": 2}', next_attempt_at: "2020-01-01 00:00:01",
+			   attempts_count: 3}
+			- {id: 3, user_id: 4, type: invitation_created, payload: '{}', next_attempt_at: "2020-01-01 00:00:03"}
+			- {id: 4, user_id: 5, type: invitation_created, payload: '{}', next_attempt_at: "2020-01-01 00:00:04"}
+			- {id: 5, user_id: 3, type: invitation_created, payload: '{}', next_attempt_at: "9999-12-31 23:59:59"}
+			- {id: 6, user_id: 3, type: invitation_created, payload: '{}', status: sent}
+			- {id: 7, user_id: 3, type: invitation_created, payload: '{}', status: failed}
+			- {id: 8, user_id: 3, type: invitation_created, payload: '{}', next_attempt_at: "2020-01-01 00:00:05"}`)
+	defer func() { _ = db.Close() }()
+
+	notifications, err := database.NewDataStore(db).EmailNotifications().GetDue(4)
+	require.NoError(t, err)
+	assert.Equal(t, []database.EmailNotification{
+		{
+			ID: 2, UserID: 4, Type: "join_request_accepted", Payload: `{"a": 2}`, AttemptsCount: 3,
+			Email: "john@example.org", DefaultLanguage: "en",
+		},
+		{ID: 1, UserID: 3, Type: "invitation_created", Payload: `{"a": 1}`, Email: "jane@example.org", DefaultLanguage: "fr"},
+		{ID: 3, UserID: 4, Type: "invitation_created", Payload: `{}`, Email: "", DefaultLanguage: "en"}, // opted out
+		{ID: 4, UserID: 5, Type: "invitation_created", Payload: `{}`, Email: "", DefaultLanguage: "fr"}, // no email
+	}, notifications)
+}
+
+func TestEmailNotificationStore_MarkAsSent_MarkAsCanceled_MarkAsFailedAttempt(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(emailNotificationsFixture, `
+		email_notifications:
+			- {id: 1, user_id: 3, type: invitation_created, payload: '{}', attempts_count: 1, last_error: "timeout"}
+			- {id: 2, user_id: 3, type: invitation_created, payload: '{}'}
+			- {id: 3, user_id: 3, type: invitation_created, payload: '{}', attempts_count: 9}
+			- {id: 4, user_id: 5, type: invitation_created, payload: '{}'}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.EmailNotifications().MarkAsSent(1))
+	require.NoError(t, store.EmailNotifications().MarkAsFailedAttempt(2, errors.New("connection refused"), time.Hour, false))
+	require.NoError(t, store.EmailNotifications().MarkAsFailedAttempt(3, errors.New("550 No such user"), time.Hour, true))
+	require.NoError(t, store.EmailNotifications().MarkAsCanceled(4))
+
+	var notifications []map[string]interface{}
+	require.NoError(t, store.EmailNotifications().
+		Select(`id, status, attempts_count, last_error,
+			IFNULL(ABS(TIMESTAMPDIFF(SECOND, last_attempt_at, NOW())) < 3, 0) AS attempted_now,
+			next_attempt_at > NOW() + INTERVAL 59 MINUTE AS postponed`).
+		Order("id").ScanIntoSliceOfMaps(&notifications).Error())
+	assert.Equal(t, []map[string]interface{}{
+		{"id": int64(1), "status": "sent", "attempts_count": int64(2), "last_error": nil, "attempted_now": int64(1), "postponed": int64(0)},
+		{
+			"id": int64(2), "status": "pending", "attempts_count": int64(1), "last_error": "connection refused",
+			"attempted_now": int64(1), "postponed": int64(1),
+		},
+		{
+			"id": int64(3), "status": "failed", "attempts_count": int64(10), "last_error": "550 No such user",
+			"attempted_now": int64(1), "postponed": int64(0),
+		},
+		{"id": int64(4), "status": "canceled", "attempts_count": int64(0), "last_error": nil, "attempted_now": int64(0), "postponed": int64(0)},
+	}, notifications)
+}
+
+func TestEmailNotificationStore_Claim(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(emailNotificationsFixture, `
+		email_notifications:
+			- {id: 1, user_id: 3, type: invitation_created, payload: '{}', next_attempt_at: "2020-01-01 00:00:00"}
+			- {id: 2, user_id: 3, type: invitation_created, payload: '{}', next_attempt_at: "9999-12-31 23:59:59"}
+			- {id: 3, user_id: 3, type: invitation_created, payload: '{}', status: sent}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	for _, test := range []struct {
+		notificationID int64
+		want           bool
+	}{
+		{notificationID: 1, want: true},
+		{notificationID: 1, want: false}, // already claimed
+		{notificationID: 2, want: false},
+		{notificationID: 3, want: false},
+	} {
+		claimed, err := store.EmailNotifications().Claim(test.notificationID, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, test.want, claimed, "notification %d", test.notificationID)
+	}
+
+	notifications, err := store.EmailNotifications().GetDue(10)
+	require.NoError(t, err)
+	assert.Empty(t, notifications)
+}
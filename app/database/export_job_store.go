@@ -0,0 +1,113 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ExportJobStore implements database operations on `export_jobs`.
+type ExportJobStore struct {
+	*DataStore
+}
+
+// ExportJob represents an export job taken by the export worker.
+type ExportJob struct {
+	ID         int64
+	UserID     int64
+	Type       string
+	Parameters string
+	Domain     string
+}
+
+// ExportArtifact describes the result of a succeeded export job.
+type ExportArtifact struct {
+	Key         string
+	FileName    string
+	ContentType string
+	Size        int64
+}
+
+// TakeNextPending marks the oldest pending export job as running and returns it.
+// It returns found=false if there are no pending jobs or if the job has been taken concurrently by another worker.
+func (s *ExportJobStore) TakeNextPending() (job ExportJob, found bool, err error) {
+	err = s.
+		Where("status = 'pending'").
+		Select("id, user_id, type, CAST(parameters AS CHAR) AS parameters, domain").
+		Order("created_at, id").
+		Limit(1).
+		Scan(&job).Error()
+	if gorm.IsRecordNotFoundError(err) {
+		return ExportJob{}, false, nil
+	}
+	if err != nil {
+		return ExportJob{}, false, err
+	}
+
+	result := s.Where("id = ? AND status = 'pending'", job.ID).UpdateColumns(map[string]interface{}{
+		"status":     "running",
+		"started_at": gorm.Expr("NOW(3)"),
+	})
+	if result.Error() != nil {
+		return ExportJob{}, false, result.Error()
+	}
+	return job, result.RowsAffected() == 1, nil
+}
+
+// MarkAsSucceeded records the artifact of the given export job which will expire in the given duration.
+func (s *ExportJobStore) MarkAsSucceeded(jobID int64, artifact *ExportArtifact, expiresIn time.Duration) error {
+	return s.Where("id = ?", jobID).UpdateColumns(map[string]interface{}{
+		"status":                "succeeded",
+		"finished_at":           gorm.Expr("NOW(3)"),
+		"artifact_key":          artifact.Key,
+		"artifact_file_name":    artifact.FileName,
+		"artifact_content_type": artifact.ContentType,
+		"artifact_size":         artifact.Size,
+		"expires_at":            gorm.Expr("NOW(3) + INTERVAL ? MICROSECOND", expiresIn.Microseconds()),
+	}).Error()
+}
+
+// MarkAsFailed records the failure of the given export job.
+func (s *ExportJobStore) MarkAsFailed(jobID int64, jobErr error) error {
+	return s.Where("id = ?", jobID).UpdateColumns(map[string]interface{}{
+		"status":      "failed",
+		"finished_at": gorm.Expr("NOW(3)"),
+		"error":       jobErr.Error(),
+	}).Error()
+}
+
+// GetIDsOfStale returns IDs of export jobs which have been running for longer than the given duration
+// (their worker has most likely been stopped).
+func (s *ExportJobStore) GetIDsOfStale(maxDuration time.Duration) (ids []int64, err error) {
+	err = s.
+		Where("status = 'running' AND started_at < NOW(3) - INTERVAL ? MICROSECOND", maxDuration.Microseconds()).
+		Order("id").
+		Pluck("id", &ids).Error()
+	return ids, err
+}
+
+// ExpiredExportJob represents a succeeded export job whose artifact has expired.
+type ExpiredExportJob struct {
+	ID          int64
+	ArtifactKey string
+}
+
+// GetExpired returns at most `limit` succeeded export jobs whose artifacts have expired.
+// Only IDs and keys of the artifacts are loaded.
+func (s *ExportJobStore) GetExpired(limit int) (jobs []ExpiredExportJob, err error) {
+	err = s.
+		Where("status = 'succeeded' AND expires_at <= NOW(3)").
+		Select("id, artifact_key").
+		Order("expires_at, id").
+		Limit(limit).
+		Scan(&jobs).Error()
+	return jobs, err
+}
+
+// MarkAsExpired records that the artifact of the given export job has been deleted.
+func (s *ExportJobStore) MarkAsExpired(jobID int64) error {
+	return s.Where("id = ?", jobID).UpdateColumns(map[string]interface{}{
+		"status":       "expired",
+		"artifact_key": nil,
+	}).Error()
+}
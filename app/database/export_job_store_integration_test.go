@@ -0,0 +1,119 @@
+//go:build !unit
+
+package database_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+const exportJobsFixture = `
+	groups: [{id: 4}]
+	users: [{group_id: 4}]
+	export_jobs:
+		- {id: 1, user_id: 4, type: full_dump, parameters: "{}", domain: example.org, status: succeeded,
+			created_at: 2025-02-20 10:00:00, artifact_key: "1", expires_at: 2020-01-01 00:00:00}
+		- {id: 2, user_id: 4, type: activity_log, parameters: '{"item_id": "10"}', domain: example.org, status: pending,
+			created_at: 2025-02-20 11:00:00}
+		- {id: 3, user_id: 4, type: full_dump, parameters: "{}", domain: example.org, status: pending,
+			created_at: 2025-02-20 10:30:00}
+		- {id: 4, user_id: 4, type: full_dump, parameters: "{}", domain: example.org, status: running,
+			created_at: 2025-02-20 09:00:00, started_at: 2020-01-01 00:00:00}
+		- {id: 5, user_id: 4, type: full_dump, parameters: "{}", domain: example.org, status: succeeded,
+			created_at: 2025-02-20 09:00:00, artifact_key: "5", expires_at: 9999-12-31 23:59:59}`
+
+func TestExportJobStore_TakeNextPending(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(exportJobsFixture)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	job, found, err := store.ExportJobs().TakeNextPending()
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, database.ExportJob{ID: 3, UserID: 4, Type: "full_dump", Parameters: "{}", Domain: "example.org"}, job)
+
+	job, found, err = store.ExportJobs().TakeNextPending()
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, database.ExportJob{ID: 2, UserID: 4, Type: "activity_log", Parameters: `{"item_id": "10"}`, Domain: "example.org"}, job)
+
+	_, found, err = store.ExportJobs().TakeNextPending()
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	var statuses []string
+	require.NoError(t, store.ExportJobs().Order("id").Pluck("status", &statuses).Error())
+	assert.Equal(t, []string{"succeeded", "running", "running", "running", "succeeded"}, statuses)
+}
+
+func TestExportJobStore_MarkAsSucceededAndFailed(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(exportJobsFixture)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.ExportJobs().MarkAsSucceeded(2,
+		&database.ExportArtifact{Key: "2", FileName: "activity_log.ndjson", ContentType: "application/x-ndjson", Size: 42}, time.Hour))
+	require.NoError(t, store.ExportJobs().MarkAsFailed(3, errors.New("some error")))
+
+	var jobs []map[string]interface{}
+	require.NoError(t, store.ExportJobs().Where("id IN (2, 3)").
+		Select(`
+			id, status, error, artifact_key, artifact_file_name, artifact_content_type, artifact_size,
+			finished_at IS NOT NULL AS finished,
+			ABS(TIMESTAMPDIFF(SECOND, NOW() + INTERVAL 1 HOUR, expires_at)) < 3 AS expires_in_an_hour`).
+		Order("id").ScanIntoSliceOfMaps(&jobs).Error())
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"id": int64(2), "status": "succeeded", "error": nil, "artifact_key": "2", "artifact_file_name": "activity_log.ndjson",
+			"artifact_content_type": "application/x-ndjson", "artifact_size": int64(42), "finished": int64(1), "expires_in_an_hour": int64(1),
+		},
+		{
+			"id": int64(3), "status": "failed", "error": "some error", "artifact_key": nil, "artifact_file_name": nil,
+			"artifact_content_type": nil, "artifact_size": nil, "finished": int64(1), "expires_in_an_hour": nil,
+		},
+	}, jobs)
+}
+
+func TestExportJobStore_GetIDsOfStale(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(exportJobsFixture)
+	defer func() { _ = db.Close() }()
+
+	ids, err := database.NewDataStore(db).ExportJobs().GetIDsOfStale(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4}, ids)
+}
+
+func TestExportJobStore_GetExpiredAndMarkAsExpired(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(exportJobsFixture)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	jobs, err := store.ExportJobs().GetExpired(10)
+	require.NoError(t, err)
+	assert.Equal(t, []database.ExpiredExportJob{{ID: 1, ArtifactKey: "1"}}, jobs)
+
+	require.NoError(t, store.ExportJobs().MarkAsExpired(1))
+	jobs, err = store.ExportJobs().GetExpired(10)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	var artifactKey *string
+	require.NoError(t, store.ExportJobs().Where("id = 1").PluckFirst("artifact_key", &artifactKey).Error())
+	assert.Nil(t, artifactKey)
+}
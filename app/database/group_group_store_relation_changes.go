@@ -0,0 +1,109 @@
+package database
+
+// FindRelationsCreatingCycles returns the indexes of the relations from relationsToCreate
+// which would be a part of a cycle in the groups_groups graph after deleting relationsToDelete
+// and creating relationsToCreate. Like CreateRelation(), it doesn't allow cycles even via expired relations.
+func (s *GroupGroupStore) FindRelationsCreatingCycles(relationsToDelete, relationsToCreate []ParentChild) (
+	indexes []int, err error,
+) {
+	defer recoverPanics(&err)
+
+	deleted := make(map[ParentChild]bool, len(relationsToDelete))
+	for _, relation := range relationsToDelete {
+		deleted[relation] = true
+	}
+	createdParents := make(map[int64][]int64, len(relationsToCreate))
+	for _, relation := range relationsToCreate {
+		createdParents[relation.ChildID] = append(createdParents[relation.ChildID], relation.ParentID)
+	}
+
+	// parents of groups in the resulting graph, loaded lazily from groups_groups
+	parents := make(map[int64][]int64)
+	loadParents := func(groupIDs []int64) {
+		var relations []ParentChild
+		mustNotBeError(s.GroupGroups().WithSharedWriteLock().
+			Where("child_group_id IN (?)", groupIDs).
+			Select("parent_group_id AS parent_id, child_group_id AS child_id").
+			Scan(&relations).Error())
+		for _, groupID := range groupIDs {
+			parents[groupID] = append([]int64(nil), createdParents[groupID]...)
+		}
+		for _, relation := range relations {
+			if !deleted[relation] {
+				parents[relation.ChildID] = append(parents[relation.ChildID], relation.ParentID)
+			}
+		}
+	}
+
+	for index, relation := range relationsToCreate {
+		// the relation is a part of a cycle if its child is an ancestor of its parent
+		visited := map[int64]bool{relation.ParentID: true}
+		currentLevel := []int64{relation.ParentID}
+		for len(currentLevel) > 0 && !visited[relation.ChildID] {
+			var groupsToLoad []int64
+			for _, groupID := range currentLevel {
+				if _, ok := parents[groupID]; !ok {
+					groupsToLoad = append(groupsToLoad, groupID)
+				}
+			}
+			if len(groupsToLoad) > 0 {
+				loadParents(groupsToLoad)
+			}
+
+			var nextLevel []int64
+			for _, groupID := range currentLevel {
+				for _, parentID := range parents[groupID] {
+					if !visited[parentID] {
+						visited[parentID] = true
+						nextLevel = append(nextLevel, parentID)
+					}
+				}
+			}
+			currentLevel = nextLevel
+		}
+		if visited[relation.ChildID] {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes, nil
+}
+
+// ChangeRelations deletes relationsToDelete and creates relationsToCreate (replacing expired relations if any)
+// at once. The groups ancestors are recomputed only once and the results propagation
+// (and the permissions propagation if needed) is scheduled.
+// The caller should check that the changes don't create cycles (see FindRelationsCreatingCycles()).
+func (s *GroupGroupStore) ChangeRelations(relationsToDelete, relationsToCreate []ParentChild) (err error) {
+	s.mustBeInTransaction()
+	defer recoverPanics(&err)
+
+	groupGroupStore := s.GroupGroups()
+	var shouldPropagatePermissions bool
+	for _, relation := range relationsToDelete {
+		// triggers will mark relations for propagation
+		mustNotBeError(groupGroupStore.
+			Delete("parent_group_id = ? AND child_group_id = ?", relation.ParentID, relation.ChildID).Error())
+		permissionsResult := s.PermissionsGranted().
+			Delete("origin = 'group_membership' AND source_group_id = ? AND group_id = ?", relation.ParentID, relation.ChildID)
+		mustNotBeError(permissionsResult.Error())
+		shouldPropagatePermissions = shouldPropagatePermissions || permissionsResult.RowsAffected() > 0
+	}
+
+	for _, relation := range relationsToCreate {
+		mustNotBeError(groupGroupStore.
+			Delete("parent_group_id = ? AND child_group_id = ?", relation.ParentID, relation.ChildID).Error())
+		mustNotBeError(s.GroupPendingRequests().
+			Delete("group_id = ? AND member_id = ?", relation.ParentID, relation.ChildID).Error())
+		groupGroupStore.createRelation(relation.ParentID, relation.ChildID)
+	}
+
+	if len(relationsToDelete) > 0 || len(relationsToCreate) > 0 {
+		groupGroupStore.createNewAncestors()
+	}
+	if len(relationsToCreate) > 0 {
+		s.ScheduleResultsPropagation()
+	}
+	if shouldPropagatePermissions {
+		s.SchedulePermissionsPropagation()
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+//go:build !unit
+
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+func TestGroupGroupStore_FindRelationsCreatingCycles(t *testing.T) {
+	tests := []struct {
+		name              string
+		relationsToDelete []database.ParentChild
+		relationsToCreate []database.ParentChild
+		expectedIndexes   []int
+	}{
+		{
+			name:              "no cycles",
+			relationsToCreate: []database.ParentChild{{ParentID: 4, ChildID: 5}, {ParentID: 5, ChildID: 6}},
+		},
+		{
+			name:              "cycle with existing relations",
+			relationsToCreate: []database.ParentChild{{ParentID: 4, ChildID: 5}, {ParentID: 3, ChildID: 1}},
+			expectedIndexes:   []int{1},
+		},
+		{
+			name:              "cycle via an expired relation",
+			relationsToCreate: []database.ParentChild{{ParentID: 6, ChildID: 4}},
+			expectedIndexes:   []int{0},
+		},
+		{
+			name:              "cycle of created relations",
+			relationsToCreate: []database.ParentChild{{ParentID: 5, ChildID: 6}, {ParentID: 6, ChildID: 5}, {ParentID: 1, ChildID: 5}},
+			expectedIndexes:   []int{0, 1},
+		},
+		{
+			name:              "no cycle when a relation of the cycle is deleted",
+			relationsToDelete: []database.ParentChild{{ParentID: 2, ChildID: 3}},
+			relationsToCreate: []database.ParentChild{{ParentID: 3, ChildID: 1}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			testoutput.SuppressIfPasses(t)
+
+			db := testhelpers.SetupDBWithFixtureString(`
+				groups: [{id: 1}, {id: 2}, {id: 3}, {id: 4}, {id: 5}, {id: 6}]
+				groups_groups:
+					- {parent_group_id: 1, child_group_id: 2}
+					- {parent_group_id: 2, child_group_id: 3}
+					- {parent_group_id: 4, child_group_id: 6, expires_at: "2019-05-30 11:00:00"}`)
+			defer func() { _ = db.Close() }()
+
+			var indexes []int
+			require.NoError(t, database.NewDataStore(db).InTransaction(func(store *database.DataStore) (err error) {
+				indexes, err = store.GroupGroups().FindRelationsCreatingCycles(tt.relationsToDelete, tt.relationsToCreate)
+				return err
+			}))
+			assert.Equal(t, tt.expectedIndexes, indexes)
+		})
+	}
+}
+
+func TestGroupGroupStore_ChangeRelations(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		groups: [{id: 1}, {id: 2}, {id: 3}, {id: 4}]
+		groups_groups:
+			- {parent_group_id: 1, child_group_id: 2}
+			- {parent_group_id: 2, child_group_id: 3}
+			- {parent_group_id: 4, child_group_id: 3, expires_at: "2019-05-30 11:00:00"}
+		group_pending_requests: [{group_id: 4, member_id: 3, type: invitation}]
+		items: [{id: 10, default_language_tag: fr}]
+		permissions_granted:
+			- {group_id: 3, item_id: 10, source_group_id: 2, origin: group_membership, can_view: content}
+			- {group_id: 3, item_id: 10, source_group_id: 3, origin: group_membership, can_view: info}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.InTransaction(func(store *database.DataStore) error {
+		require.NoError(t, store.GroupGroups().CreateNewAncestors())
+		return store.GroupGroups().ChangeRelations(
+			[]database.ParentChild{{ParentID: 2, ChildID: 3}},
+			[]database.ParentChild{{ParentID: 4, ChildID: 3}, {ParentID: 1, ChildID: 4}})
+	}))
+
+	var groupsGroups []map[string]interface{}
+	require.NoError(t, store.GroupGroups().Select("parent_group_id, child_group_id").
+		Order("parent_group_id, child_group_id").ScanIntoSliceOfMaps(&groupsGroups).Error())
+	assert.Equal(t, []map[string]interface{}{
+		{"parent_group_id": int64(1), "child_group_id": int64(2)},
+		{"parent_group_id": int64(1), "child_group_id": int64(4)},
+		{"parent_group_id": int64(4), "child_group_id": int64(3)},
+	}, groupsGroups)
+
+	var ancestors []groupAncestorsResultRow
+	require.NoError(t, store.GroupAncestors().Order("child_group_id, ancestor_group_id").Scan(&ancestors).Error())
+	assert.Equal(t, []groupAncestorsResultRow{
+		{ChildGroupID: 1, AncestorGroupID: 1, IsSelf: true, ExpiresAt: maxExpiresAt},
+		{ChildGroupID: 2, AncestorGroupID: 1, IsSelf: false, ExpiresAt: maxExpiresAt},
+		{ChildGroupID: 2, AncestorGroupID: 2, IsSelf: true, ExpiresAt: maxExpiresAt},
+		{ChildGroupID: 3, AncestorGroupID: 1, IsSelf: false, ExpiresAt: maxExpiresAt},
+		{ChildGroupID: 3, AncestorGroupID: 3, IsSelf: true, ExpiresAt: maxExpiresAt},
+		{ChildGroupID: 3, AncestorGroupID: 4, IsSelf: false, ExpiresAt: maxExpiresAt},
+		{ChildGroupID: 4, AncestorGroupID: 1, IsSelf: false, ExpiresAt: maxExpiresAt},
+		{ChildGroupID: 4, AncestorGroupID: 4, IsSelf: true, ExpiresAt: maxExpiresAt},
+	}, ancestors)
+
+	found, err := store.GroupPendingRequests().HasRows()
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	var sourceGroupIDs []int64
+	require.NoError(t, store.PermissionsGranted().Pluck("source_group_id", &sourceGroupIDs).Error())
+	assert.Equal(t, []int64{3}, sourceGroupIDs)
+}
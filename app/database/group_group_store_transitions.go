@@ -28,6 +28,8 @@ const (
 	JoinedByBadge GroupMembershipAction = "joined_by_badge"
 	// JoinedByCode means a user joined a group by the group's code.
 	JoinedByCode GroupMembershipAction = "joined_by_code"
+	// JoinedByLTI means a user has been added into a group because of an LTI launch from the group's context.
+	JoinedByLTI GroupMembershipAction = "joined_by_lti"
 	// JoinRequestRefused means an admin refused a user's request to join a group.
 	JoinRequestRefused GroupMembershipAction = "join_request_refused"
 	// JoinRequestWithdrawn means a user withdrew his request to join a group.
@@ -54,7 +56,7 @@ const (
 
 func (groupMembershipAction GroupMembershipAction) isActive() bool {
 	switch groupMembershipAction {
-	case JoinedByBadge, InvitationAccepted, JoinRequestAccepted, JoinedByCode, IsMember,
+	case JoinedByBadge, InvitationAccepted, JoinRequestAccepted, JoinedByCode, JoinedByLTI, IsMember,
 		LeaveRequestCreated, LeaveRequestWithdrawn, LeaveRequestRefused:
 		return true
 	}
@@ -133,6 +135,8 @@ const (
 	// UserJoinsGroupByCode means a user joins a group using a group's code
 	// We don't check the code here (a calling service should check the code by itself).
 	UserJoinsGroupByCode
+	// UserJoinsGroupByLTI means we add a user into a group because of his LTI launch from the group's context.
+	UserJoinsGroupByLTI
 	// AdminStrengthensApprovalWithEmpty means an admin strengthens the approval requirements for a group and empties it.
 	AdminStrengthensApprovalWithEmpty
 	// AdminStrengthensApprovalWithReinvite means an admin strengthens the approval requirements for a group,
@@ -189,6 +193,14 @@ var groupGroupTransitionRules = map[GroupGroupTransitionAction]groupGroupTransit
 			LeaveRequestExpired: JoinedByCode,
 		},
 	},
+	UserJoinsGroupByLTI: {
+		Transitions: map[GroupMembershipAction]GroupMembershipAction{
+			NoRelation:          JoinedByLTI,
+			JoinRequestCreated:  JoinedByLTI,
+			InvitationCreated:   JoinedByLTI,
+			LeaveRequestExpired: JoinedByLTI,
+		},
+	},
 	UserAcceptsInvitation: {
 		Transitions: map[GroupMembershipAction]GroupMembershipAction{
 			InvitationCreated: InvitationAccepted,
@@ -520,8 +532,8 @@ func enforceMaxSize(dataStore *DataStore, action GroupGroupTransitionAction, par
 	idsToInsertRelation, idsToDeletePending, idsToDeleteRelation map[int64]bool, idsChanged map[int64]GroupMembershipAction,
 ) {
 	if !limits.EnforceMaxParticipants || !map[GroupGroupTransitionAction]bool{
-		UserJoinsGroupByBadge: true, UserJoinsGroupByCode: true, UserCreatesJoinRequest: true, UserCreatesAcceptedJoinRequest: true,
-		AdminCreatesInvitation: true, AdminAcceptsJoinRequest: true,
+		UserJoinsGroupByBadge: true, UserJoinsGroupByCode: true, UserJoinsGroupByLTI: true,
+		UserCreatesJoinRequest: true, UserCreatesAcceptedJoinRequest: true, AdminCreatesInvitation: true, AdminAcceptsJoinRequest: true,
 	}[action] {
 		return
 	}
@@ -616,6 +628,9 @@ func insertGroupMembershipChanges(dataStore *DataStore, idsChanged map[int64]Gro
 			}
 			return dataStore.db.Exec(insertQuery, values...).Error
 		}))
+		mustNotBeError(dataStore.WebhookDeliveries().enqueueForGroupMembershipChanges(parentGroupID, idsChanged, performedByUserID))
+		mustNotBeError(dataStore.EmailNotifications().enqueueForGroupMembershipChanges(parentGroupID, idsChanged))
+		mustNotBeError(dataStore.Notifications().insertForGroupMembershipChanges(parentGroupID, idsChanged, performedByUserID))
 	}
 }
 
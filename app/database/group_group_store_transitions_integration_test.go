@@ -776,6 +776,12 @@ func TestGroupGroupStore_Transition(t *testing.T) {
 			database.UserJoinsGroupByCode, database.JoinedByCode, false, golang.Ptr(9)),
 		testTransitionAcceptingNoRelationAndAnyPendingRequestEnforcingMaxParticipants(
 			"UserJoinsGroupByCode (enforce max participants)", database.UserJoinsGroupByCode, false),
+		testTransitionAcceptingNoRelationAndAnyPendingRequest(
+			"UserJoinsGroupByLTI", database.UserJoinsGroupByLTI, database.JoinedByLTI, true, nil),
+		testTransitionAcceptingNoRelationAndAnyPendingRequest("UserJoinsGroupByLTI (max participants limit is not exceeded)",
+			database.UserJoinsGroupByLTI, database.JoinedByLTI, false, golang.Ptr(9)),
+		testTransitionAcceptingNoRelationAndAnyPendingRequestEnforcingMaxParticipants(
+			"UserJoinsGroupByLTI (enforce max participants)", database.UserJoinsGroupByLTI, false),
 		{
 			name:              "AdminRemovesDirectRelation",
 			action:            database.AdminRemovesDirectRelation,
@@ -1083,6 +1089,7 @@ func TestGroupGroupStore_Transition_ChecksApprovalsInJoinRequestIfJoinRequestExi
 	}{
 		{"when a user joins the group by code", database.UserJoinsGroupByCode},
 		{"when a user joins the group by badge", database.UserJoinsGroupByBadge},
+		{"when a user joins the group by LTI", database.UserJoinsGroupByLTI},
 		{"when a group owner creates an accepted join request", database.UserCreatesAcceptedJoinRequest},
 	} {
 		test := test
@@ -0,0 +1,565 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ItemArchiveVersion is the version of the format of item archives produced by ItemStore.ExportSubtree.
+const ItemArchiveVersion = 1
+
+// Possible ways of handling text_id conflicts while importing an item archive.
+const (
+	// ItemArchiveTextIDConflictFail makes the import fail if a text_id of the archive is already used.
+	ItemArchiveTextIDConflictFail = "fail"
+	// ItemArchiveTextIDConflictClear makes the import set text_id to NULL for the items with conflicting text_ids.
+	ItemArchiveTextIDConflictClear = "clear"
+	// ItemArchiveTextIDConflictRename makes the import add a suffix ("-imported", "-imported-2", ...)
+	// to the conflicting text_ids.
+	ItemArchiveTextIDConflictRename = "rename"
+)
+
+// ErrInvalidItemArchive is returned by ItemStore.ImportArchive when the archive cannot be imported
+// under the given parent item.
+var ErrInvalidItemArchive = errors.New("invalid item archive")
+
+// ErrItemArchiveTextIDConflict is returned by ItemStore.ImportArchive when some text_ids of the archive are already used
+// and the conflicts should make the import fail.
+var ErrItemArchiveTextIDConflict = errors.New("some text_ids of the archive are already used")
+
+// ItemArchive is a portable copy of a subtree of items: the items with their strings in all languages,
+// the items_items relations between them, and the item_dependencies between them.
+type ItemArchive struct {
+	Version      int                     `json:"version"`
+	RootItemID   int64                   `json:"root_item_id,string"`
+	Items        []ItemArchiveItem       `json:"items"`
+	Relations    []ItemArchiveRelation   `json:"relations"`
+	Dependencies []ItemArchiveDependency `json:"dependencies"`
+}
+
+// ItemArchiveItem is an item of an item archive: its type, its properties (see RevisionedItemColumns),
+// and its strings by language tag.
+type ItemArchiveItem struct {
+	ID         int64                             `json:"id,string"`
+	Type       string                            `json:"type"`
+	Properties map[string]interface{}            `json:"properties"`
+	Strings    map[string]map[string]interface{} `json:"strings"`
+}
+
+// ItemArchiveRelation is an items_items relation of an item archive with its ordering and propagation settings.
+type ItemArchiveRelation struct {
+	ParentItemID int64                  `json:"parent_item_id,string"`
+	ChildItemID  int64                  `json:"child_item_id,string"`
+	Properties   map[string]interface{} `json:"properties"`
+}
+
+// ItemArchiveDependency is an item_dependencies row of an item archive.
+type ItemArchiveDependency struct {
+	ItemID           int64 `json:"item_id,string"`
+	DependentItemID  int64 `json:"dependent_item_id,string"`
+	Score            int32 `json:"score"`
+	GrantContentView bool  `json:"grant_content_view"`
+}
+
+// ItemArchiveImportOptions are the options of ItemStore.ImportArchive.
+type ItemArchiveImportOptions struct {
+	// The imported root item becomes the last child of this item.
+	ParentItemID int64
+	// The group becoming the owner of the imported items and the author of their revisions.
+	OwnerGroupID int64
+	// One of ItemArchiveTextIDConflictFail (default), ItemArchiveTextIDConflictClear, ItemArchiveTextIDConflictRename.
+	OnTextIDConflict string
+	// If true, the archive is only checked and nothing is inserted.
+	DryRun bool
+}
+
+// ItemArchiveTextIDConflict is a text_id of an imported item which is already used by an existing item.
+type ItemArchiveTextIDConflict struct {
+	ItemID int64
+	TextID string
+	// The text_id given to the imported item (nil if the text_id is cleared or if the import fails)
+	NewTextID *string
+}
+
+// ItemArchiveImportReport describes the result of ItemStore.ImportArchive.
+type ItemArchiveImportReport struct {
+	ItemsCount        int
+	RelationsCount    int
+	DependenciesCount int
+	TextIDConflicts   []ItemArchiveTextIDConflict
+	// New ids of the imported items by their ids in the archive (empty on dry runs)
+	IDMapping map[int64]int64
+}
+
+var itemArchiveSubtreeQuery = `
+	WITH RECURSIVE subtree(item_id) AS (
+		SELECT ?
+		UNION
+		SELECT items_items.child_item_id FROM items_items JOIN subtree ON subtree.item_id = items_items.parent_item_id
+	)
+	SELECT item_id FROM subtree`
+
+// ExportSubtree returns an archive of the given item and all its descendants.
+// It returns gorm.ErrRecordNotFound if the item doesn't exist.
+func (s *ItemStore) ExportSubtree(rootItemID int64) (archive *ItemArchive, err error) {
+	defer recoverPanics(&err)
+
+	var rootExists bool
+	rootExists, err = s.ByID(rootItemID).HasRows()
+	mustNotBeError(err)
+	if !rootExists {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var itemIDs []int64
+	mustNotBeError(s.Raw(itemArchiveSubtreeQuery, rootItemID).ScanIntoSlices(&itemIDs).Error())
+
+	archive = &ItemArchive{Version: ItemArchiveVersion, RootItemID: rootItemID}
+
+	var items []struct {
+		ID             int64
+		Type           string
+		PropertiesJSON string
+		StringsJSON    string
+	}
+	mustNotBeError(s.Where("items.id IN (?)", itemIDs).
+		Select(`
+			items.id, items.type,
+			CAST(` + jsonObjectOfColumns("items", RevisionedItemColumns) + ` AS CHAR) AS properties_json,
+			CAST(IFNULL((
+				SELECT JSON_OBJECTAGG(items_strings.language_tag, ` +
+			jsonObjectOfColumns("items_strings", revisionedItemStringColumns) + `)
+				FROM items_strings WHERE items_strings.item_id = items.id
+			), JSON_OBJECT()) AS CHAR) AS strings_json`).
+		Order("items.id").Scan(&items).Error())
+	archive.Items = make([]ItemArchiveItem, 0, len(items))
+	for index := range items {
+		item := ItemArchiveItem{ID: items[index].ID, Type: items[index].Type}
+		mustNotBeError(json.Unmarshal([]byte(items[index].PropertiesJSON), &item.Properties))
+		mustNotBeError(json.Unmarshal([]byte(items[index].StringsJSON), &item.Strings))
+		archive.Items = append(archive.Items, item)
+	}
+
+	var relations []struct {
+		ParentItemID   int64
+		ChildItemID    int64
+		PropertiesJSON string
+	}
+	mustNotBeError(s.ItemItems().
+		Where("parent_item_id IN (?) AND child_item_id IN (?)", itemIDs, itemIDs).
+		Select(`
+			parent_item_id, child_item_id,
+			CAST(` + jsonObjectOfColumns("items_items", revisionedItemItemColumns) + ` AS CHAR) AS properties_json`).
+		Order("parent_item_id, child_order, child_item_id").Scan(&relations).Error())
+	archive.Relations = make([]ItemArchiveRelation, 0, len(relations))
+	for index := range relations {
+		relation := ItemArchiveRelation{ParentItemID: relations[index].ParentItemID, ChildItemID: relations[index].ChildItemID}
+		mustNotBeError(json.Unmarshal([]byte(relations[index].PropertiesJSON), &relation.Properties))
+		archive.Relations = append(archive.Relations, relation)
+	}
+
+	archive.Dependencies = make([]ItemArchiveDependency, 0)
+	mustNotBeError(s.ItemDependencies().
+		Where("item_id IN (?) AND dependent_item_id IN (?)", itemIDs, itemIDs).
+		Select("item_id, dependent_item_id, score, grant_content_view").
+		Order("item_id, dependent_item_id").Scan(&archive.Dependencies).Error())
+
+	return archive, nil
+}
+
+// ImportArchive recreates the items of the archive with new ids, the root item of the archive becoming
+// the last child of options.ParentItemID. The owner group gets is_owner on all the created items.
+// Participants groups are created for items requiring explicit entry. Revisions are recorded
+// for the created items and for the parent item.
+//
+// The method returns an error wrapping ErrInvalidItemArchive if the archive is inconsistent or cannot be placed
+// under the parent item, and ErrItemArchiveTextIDConflict (with the report listing the conflicts)
+// if some text_ids are already used while options.OnTextIDConflict is ItemArchiveTextIDConflictFail.
+// On dry runs, the archive is only checked and the report is returned without inserting anything.
+//
+// The method should be called inside a transaction.
+func (s *ItemStore) ImportArchive(archive *ItemArchive, options *ItemArchiveImportOptions) (
+	report *ItemArchiveImportReport, err error,
+) {
+	s.mustBeInTransaction()
+	defer recoverPanics(&err)
+
+	var parentType string
+	err = s.ByID(options.ParentItemID).WithExclusiveWriteLock().PluckFirst("type", &parentType).Error()
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, fmt.Errorf("%w: no such parent item", ErrInvalidItemArchive)
+	}
+	mustNotBeError(err)
+
+	if err = archive.validate(parentType); err != nil {
+		return nil, err
+	}
+	if err = s.checkItemArchiveLanguages(archive); err != nil {
+		return nil, err
+	}
+
+	report = &ItemArchiveImportReport{
+		ItemsCount:        len(archive.Items),
+		RelationsCount:    len(archive.Relations),
+		DependenciesCount: len(archive.Dependencies),
+		IDMapping:         map[int64]int64{},
+	}
+	newTextIDs := s.resolveItemArchiveTextIDConflicts(archive, options.OnTextIDConflict, report)
+	if options.DryRun {
+		return report, nil
+	}
+	if options.OnTextIDConflict != ItemArchiveTextIDConflictClear && options.OnTextIDConflict != ItemArchiveTextIDConflictRename &&
+		len(report.TextIDConflicts) > 0 {
+		return report, ErrItemArchiveTextIDConflict
+	}
+
+	mustNotBeError(s.ItemRevisions().RecordInitialIfMissing(options.ParentItemID))
+	mustNotBeError(s.ItemItems().WithItemsRelationsLock(func(store *DataStore) error {
+		store.Items().insertItemArchive(archive, options, newTextIDs, report.IDMapping)
+		return nil
+	}))
+
+	for index := range archive.Items {
+		mustNotBeError(s.ItemRevisions().Record(report.IDMapping[archive.Items[index].ID], options.OwnerGroupID))
+	}
+	mustNotBeError(s.ItemRevisions().Record(options.ParentItemID, options.OwnerGroupID))
+
+	return report, nil
+}
+
+func (s *ItemStore) insertItemArchive(
+	archive *ItemArchive, options *ItemArchiveImportOptions, newTextIDs map[int64]*string, idMapping map[int64]int64,
+) {
+	for index := range archive.Items {
+		item := &archive.Items[index]
+		itemMap := make(map[string]interface{}, len(RevisionedItemColumns)+2)
+		for _, column := range RevisionedItemColumns {
+			if value, ok := item.Properties[column]; ok {
+				itemMap[column] = value
+			}
+		}
+		itemMap["type"] = item.Type
+		itemMap["text_id"] = newTextIDs[item.ID]
+
+		mustNotBeError(s.WithForeignKeyChecksDisabled(func(store *DataStore) error {
+			return store.RetryOnDuplicatePrimaryKeyError("items", func(store *DataStore) error {
+				itemMap["id"] = store.NewID()
+				return store.Items().InsertMap(itemMap)
+			})
+		}))
+		newItemID := itemMap["id"].(int64)
+		idMapping[item.ID] = newItemID
+
+		for languageTag, values := range item.Strings {
+			stringMap := make(map[string]interface{}, len(revisionedItemStringColumns)+2)
+			for _, column := range revisionedItemStringColumns {
+				if value, ok := values[column]; ok {
+					stringMap[column] = value
+				}
+			}
+			stringMap["item_id"] = newItemID
+			stringMap["language_tag"] = languageTag
+			mustNotBeError(s.ItemStrings().InsertMap(stringMap))
+		}
+
+		if isTruthyJSONValue(item.Properties["requires_explicit_entry"]) {
+			participantsGroupID, err := s.Groups().CreateNew(fmt.Sprintf("%d-participants", newItemID), "ContestParticipants")
+			mustNotBeError(err)
+			mustNotBeError(s.PermissionsGranted().InsertMap(map[string]interface{}{
+				"group_id":        participantsGroupID,
+				"item_id":         newItemID,
+				"source_group_id": participantsGroupID,
+				"origin":          "group_membership",
+				"can_view":        "content",
+			}))
+			mustNotBeError(s.ByID(newItemID).UpdateColumn("participants_group_id", participantsGroupID).Error())
+		}
+
+		mustNotBeError(s.PermissionsGranted().InsertMap(map[string]interface{}{
+			"item_id":         newItemID,
+			"group_id":        options.OwnerGroupID,
+			"source_group_id": options.OwnerGroupID,
+			"origin":          "self",
+			"is_owner":        true,
+		}))
+	}
+
+	var childOrder int32
+	mustNotBeError(s.ItemItems().WithExclusiveWriteLock().Where("parent_item_id = ?", options.ParentItemID).
+		PluckFirst("IFNULL(MAX(`child_order`), 0)+1", &childOrder).Error())
+	relations := make([]map[string]interface{}, 0, len(archive.Relations)+1)
+	// the same defaults as for the parent relation of itemCreate
+	relations = append(relations, map[string]interface{}{
+		"parent_item_id": options.ParentItemID, "child_item_id": idMapping[archive.RootItemID], "child_order": childOrder,
+		"category": "Undefined", "score_weight": 1, "content_view_propagation": "as_info", "upper_view_levels_propagation": "as_is",
+		"grant_view_propagation": true, "watch_propagation": true, "edit_propagation": true, "request_help_propagation": true,
+	})
+	for index := range archive.Relations {
+		relation := &archive.Relations[index]
+		relationMap := make(map[string]interface{}, len(revisionedItemItemColumns)+2)
+		for _, column := range revisionedItemItemColumns {
+			if value, ok := relation.Properties[column]; ok {
+				relationMap[column] = value
+			}
+		}
+		relationMap["parent_item_id"] = idMapping[relation.ParentItemID]
+		relationMap["child_item_id"] = idMapping[relation.ChildItemID]
+		relations = append(relations, relationMap)
+	}
+	for _, relationMap := range relations {
+		mustNotBeError(s.ItemItems().InsertMap(relationMap))
+	}
+
+	for _, dependency := range archive.Dependencies {
+		mustNotBeError(s.ItemDependencies().InsertMap(map[string]interface{}{
+			"item_id":            idMapping[dependency.ItemID],
+			"dependent_item_id":  idMapping[dependency.DependentItemID],
+			"score":              dependency.Score,
+			"grant_content_view": dependency.GrantContentView,
+		}))
+	}
+
+	mustNotBeError(s.ItemItems().CreateNewAncestors())
+	s.SchedulePermissionsPropagation()
+	s.ScheduleResultsPropagation()
+}
+
+// validate checks that the archive is consistent and that its root item can become a child
+// of an item of the given type.
+func (archive *ItemArchive) validate(parentType string) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidItemArchive, fmt.Sprintf(format, args...))
+	}
+
+	if archive.Version != ItemArchiveVersion {
+		return invalid("unsupported version %d", archive.Version)
+	}
+
+	itemTypes := make(map[int64]string, len(archive.Items))
+	textIDs := make(map[string]bool, len(archive.Items))
+	for index := range archive.Items {
+		item := &archive.Items[index]
+		if _, ok := itemTypes[item.ID]; ok {
+			return invalid("duplicate item %d", item.ID)
+		}
+		if item.Type != "Chapter" && item.Type != "Task" && item.Type != "Skill" {
+			return invalid("wrong type of item %d", item.ID)
+		}
+		itemTypes[item.ID] = item.Type
+		if err := validateItemArchiveItemValues(item); err != nil {
+			return invalid("item %d: %s", item.ID, err)
+		}
+
+		defaultLanguageTag, _ := item.Properties["default_language_tag"].(string)
+		if _, ok := item.Strings[defaultLanguageTag]; !ok {
+			return invalid("item %d has no strings in its default language", item.ID)
+		}
+		if textID, ok := item.Properties["text_id"].(string); ok {
+			if textIDs[textID] {
+				return invalid("duplicate text_id %q", textID)
+			}
+			textIDs[textID] = true
+		}
+	}
+
+	rootType, ok := itemTypes[archive.RootItemID]
+	if !ok {
+		return invalid("no root item")
+	}
+	if err := validateItemArchiveRelationTypes(parentType, rootType); err != nil {
+		return invalid("the root item cannot be a child of the parent item: %s", err)
+	}
+
+	children := make(map[int64][]int64, len(archive.Items))
+	relations := make(map[[2]int64]bool, len(archive.Relations))
+	for _, relation := range archive.Relations {
+		parentItemType, parentFound := itemTypes[relation.ParentItemID]
+		childItemType, childFound := itemTypes[relation.ChildItemID]
+		if !parentFound || !childFound {
+			return invalid("relation %d-%d refers to an item outside the archive", relation.ParentItemID, relation.ChildItemID)
+		}
+		if relations[[2]int64{relation.ParentItemID, relation.ChildItemID}] {
+			return invalid("duplicate relation %d-%d", relation.ParentItemID, relation.ChildItemID)
+		}
+		if err := validateItemArchiveRelationTypes(parentItemType, childItemType); err != nil {
+			return invalid("relation %d-%d: %s", relation.ParentItemID, relation.ChildItemID, err)
+		}
+		if column, ok := checkItemArchiveValues(relation.Properties, revisionedItemItemColumns, itemArchiveRelationValueCheckers); !ok {
+			return invalid("relation %d-%d: wrong value for %s", relation.ParentItemID, relation.ChildItemID, column)
+		}
+		relations[[2]int64{relation.ParentItemID, relation.ChildItemID}] = true
+		children[relation.ParentItemID] = append(children[relation.ParentItemID], relation.ChildItemID)
+	}
+
+	if !itemArchiveRelationsFormSubtree(archive.RootItemID, children, len(archive.Items)) {
+		return invalid("the relations should make all the items descendants of the root item without cycles")
+	}
+
+	for _, dependency := range archive.Dependencies {
+		if _, ok := itemTypes[dependency.ItemID]; !ok {
+			return invalid("dependency %d-%d refers to an item outside the archive", dependency.ItemID, dependency.DependentItemID)
+		}
+		if _, ok := itemTypes[dependency.DependentItemID]; !ok {
+			return invalid("dependency %d-%d refers to an item outside the archive", dependency.ItemID, dependency.DependentItemID)
+		}
+	}
+
+	return nil
+}
+
+// validateItemArchiveRelationTypes checks the types of a parent and a child
+// (the only allowed parent-child relations are skills-*, chapter-task, chapter-chapter).
+func validateItemArchiveRelationTypes(parentType, childType string) error {
+	if parentType == "Task" {
+		return errors.New("a task cannot have children items")
+	}
+	if childType == "Skill" && parentType != "Skill" {
+		return errors.New("a skill can only be a child of a skill")
+	}
+	return nil
+}
+
+// itemArchiveRelationsFormSubtree checks that all the items are reachable from the root
+// and that the relations have no cycles.
+func itemArchiveRelationsFormSubtree(rootItemID int64, children map[int64][]int64, itemsCount int) bool {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := make(map[int64]int, itemsCount)
+	var visit func(itemID int64) bool
+	visit = func(itemID int64) bool {
+		switch states[itemID] {
+		case visiting:
+			return false
+		case visited:
+			return true
+		}
+		states[itemID] = visiting
+		for _, childID := range children[itemID] {
+			if !visit(childID) {
+				return false
+			}
+		}
+		states[itemID] = visited
+		return true
+	}
+	return visit(rootItemID) && len(states) == itemsCount
+}
+
+func (s *ItemStore) checkItemArchiveLanguages(archive *ItemArchive) error {
+	languageTagsMap := make(map[string]bool)
+	for index := range archive.Items {
+		for languageTag := range archive.Items[index].Strings {
+			languageTagsMap[languageTag] = true
+		}
+	}
+	languageTags := make([]string, 0, len(languageTagsMap))
+	for languageTag := range languageTagsMap {
+		languageTags = append(languageTags, languageTag)
+	}
+
+	var existingLanguageTags []string
+	mustNotBeError(s.Languages().Where("tag IN (?)", languageTags).WithSharedWriteLock().
+		Pluck("tag", &existingLanguageTags).Error())
+	if len(existingLanguageTags) != len(languageTags) {
+		for _, languageTag := range existingLanguageTags {
+			delete(languageTagsMap, languageTag)
+		}
+		missingLanguageTags := make([]string, 0, len(languageTagsMap))
+		for languageTag := range languageTagsMap {
+			missingLanguageTags = append(missingLanguageTags, languageTag)
+		}
+		sort.Strings(missingLanguageTags)
+		return fmt.Errorf("%w: no such languages %q", ErrInvalidItemArchive, missingLanguageTags)
+	}
+	return nil
+}
+
+// resolveItemArchiveTextIDConflicts returns the text_ids to give to the imported items by their ids in the archive
+// and lists the conflicts in the report.
+func (s *ItemStore) resolveItemArchiveTextIDConflicts(
+	archive *ItemArchive, onTextIDConflict string, report *ItemArchiveImportReport,
+) map[int64]*string {
+	newTextIDs := make(map[int64]*string, len(archive.Items))
+	textIDs := make([]string, 0, len(archive.Items))
+	for index := range archive.Items {
+		if textID, ok := archive.Items[index].Properties["text_id"].(string); ok {
+			textIDCopy := textID
+			newTextIDs[archive.Items[index].ID] = &textIDCopy
+			textIDs = append(textIDs, textID)
+		}
+	}
+	if len(textIDs) == 0 {
+		return newTextIDs
+	}
+
+	var usedTextIDs []string
+	mustNotBeError(s.Where("text_id IN (?)", textIDs).WithExclusiveWriteLock().Pluck("text_id", &usedTextIDs).Error())
+	if len(usedTextIDs) == 0 {
+		return newTextIDs
+	}
+	conflictingTextIDs := make(map[string]bool, len(usedTextIDs))
+	reservedTextIDs := make(map[string]bool, len(usedTextIDs)+len(textIDs))
+	for _, textID := range usedTextIDs {
+		conflictingTextIDs[textID] = true
+		reservedTextIDs[textID] = true
+	}
+	for _, textID := range textIDs {
+		reservedTextIDs[textID] = true
+	}
+
+	for index := range archive.Items {
+		itemID := archive.Items[index].ID
+		textID := newTextIDs[itemID]
+		if textID == nil || !conflictingTextIDs[*textID] {
+			continue
+		}
+		conflict := ItemArchiveTextIDConflict{ItemID: itemID, TextID: *textID}
+		switch onTextIDConflict {
+		case ItemArchiveTextIDConflictClear:
+			newTextIDs[itemID] = nil
+		case ItemArchiveTextIDConflictRename:
+			newTextID := s.findUnusedTextID(*textID, reservedTextIDs)
+			reservedTextIDs[newTextID] = true
+			newTextIDs[itemID] = &newTextID
+			conflict.NewTextID = &newTextID
+		}
+		report.TextIDConflicts = append(report.TextIDConflicts, conflict)
+	}
+	return newTextIDs
+}
+
+// findUnusedTextID returns the first of "<textID>-imported", "<textID>-imported-2", ...
+// which is neither reserved nor used by an existing item.
+func (s *ItemStore) findUnusedTextID(textID string, reservedTextIDs map[string]bool) string {
+	for number := 1; ; number++ {
+		candidate := textID + "-imported"
+		if number > 1 {
+			candidate += "-" + strconv.Itoa(number)
+		}
+		if reservedTextIDs[candidate] {
+			continue
+		}
+		found, err := s.Where("text_id = ?", candidate).WithExclusiveWriteLock().HasRows()
+		mustNotBeError(err)
+		if !found {
+			return candidate
+		}
+	}
+}
+
+func isTruthyJSONValue(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case bool:
+		return typedValue
+	case float64:
+		return typedValue != 0
+	}
+	return false
+}
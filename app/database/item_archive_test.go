@@ -0,0 +1,240 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemArchive_validate(t *testing.T) {
+	item := func(id int64, itemType string) ItemArchiveItem {
+		return ItemArchiveItem{
+			ID: id, Type: itemType,
+			Properties: map[string]interface{}{"default_language_tag": "fr"},
+			Strings:    map[string]map[string]interface{}{"fr": {"title": "Titre"}},
+		}
+	}
+	validArchive := func() *ItemArchive {
+		return &ItemArchive{
+			Version:    ItemArchiveVersion,
+			RootItemID: 1,
+			Items:      []ItemArchiveItem{item(1, "Chapter"), item(2, "Chapter"), item(3, "Task")},
+			Relations: []ItemArchiveRelation{
+				{ParentItemID: 1, ChildItemID: 2},
+				{ParentItemID: 2, ChildItemID: 3},
+				{ParentItemID: 1, ChildItemID: 3},
+			},
+			Dependencies: []ItemArchiveDependency{{ItemID: 2, DependentItemID: 3}},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(archive *ItemArchive)
+		parentType string
+		wantErr    string
+	}{
+		{name: "valid", modify: func(*ItemArchive) {}, parentType: "Chapter"},
+		{
+			name:       "wrong version",
+			modify:     func(archive *ItemArchive) { archive.Version = 2 },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: unsupported version 2",
+		},
+		{
+			name:       "duplicate item",
+			modify:     func(archive *ItemArchive) { archive.Items = append(archive.Items, item(2, "Chapter")) },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: duplicate item 2",
+		},
+		{
+			name:       "wrong type",
+			modify:     func(archive *ItemArchive) { archive.Items[1].Type = "Course" },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: wrong type of item 2",
+		},
+		{
+			name:       "no strings in the default language",
+			modify:     func(archive *ItemArchive) { archive.Items[2].Properties["default_language_tag"] = "en" },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: item 3 has no strings in its default language",
+		},
+		{
+			name: "duplicate text_id",
+			modify: func(archive *ItemArchive) {
+				archive.Items[0].Properties["text_id"] = "chapter"
+				archive.Items[1].Properties["text_id"] = "chapter"
+			},
+			parentType: "Chapter",
+			wantErr:    `invalid item archive: duplicate text_id "chapter"`,
+		},
+		{
+			name: "valid values",
+			modify: func(archive *ItemArchive) {
+				for key, value := range map[string]interface{}{
+					"url": nil, "options": `{"a":1}`, "no_score": float64(1), "children_layout": "Grid", "validation_type": "Manual",
+					"entering_time_min": "1000-01-01 00:00:00", "entry_max_team_size": float64(3), "entry_participant_type": "Team",
+					"duration": "838:59:59", "requires_explicit_entry": true, "ranking_freeze_duration": nil,
+				} {
+					archive.Items[0].Properties[key] = value
+				}
+				archive.Items[0].Strings["fr"]["subtitle"] = nil
+				archive.Relations[0].Properties = map[string]interface{}{
+					"child_order": float64(2), "category": "Challenge", "score_weight": float64(-1), "watch_propagation": float64(0),
+				}
+			},
+			parentType: "Chapter",
+		},
+		{
+			name:       "wrong enum value",
+			modify:     func(archive *ItemArchive) { archive.Items[1].Properties["validation_type"] = "Some" },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: item 2: wrong value for validation_type",
+		},
+		{
+			name:       "wrong boolean value",
+			modify:     func(archive *ItemArchive) { archive.Items[1].Properties["read_only"] = "yes" },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: item 2: wrong value for read_only",
+		},
+		{
+			name:       "wrong duration",
+			modify:     func(archive *ItemArchive) { archive.Items[1].Properties["ranking_freeze_duration"] = "839:00:00" },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: item 2: wrong value for ranking_freeze_duration",
+		},
+		{
+			name:       "wrong datetime",
+			modify:     func(archive *ItemArchive) { archive.Items[1].Properties["entering_time_max"] = "9999-12-31" },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: item 2: wrong value for entering_time_max",
+		},
+		{
+			name:       "wrong options",
+			modify:     func(archive *ItemArchive) { archive.Items[1].Properties["options"] = "{" },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: item 2: wrong value for options",
+		},
+		{
+			name:       "too long url",
+			modify:     func(archive *ItemArchive) { archive.Items[1].Properties["url"] = strings.Repeat("a", 2049) },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: item 2: wrong value for url",
+		},
+		{
+			name:       "non-integer team size",
+			modify:     func(archive *ItemArchive) { archive.Items[1].Properties["entry_max_team_size"] = 1.5 },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: item 2: wrong value for entry_max_team_size",
+		},
+		{
+			name:       "duration without explicit entry",
+			modify:     func(archive *ItemArchive) { archive.Items[1].Properties["duration"] = "01:00:00" },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: item 2: an item with a duration should require explicit entry",
+		},
+		{
+			name: "skill requiring explicit entry",
+			modify: func(archive *ItemArchive) {
+				archive.Items = []ItemArchiveItem{item(1, "Skill")}
+				archive.Items[0].Properties["requires_explicit_entry"] = float64(1)
+				archive.Relations = nil
+				archive.Dependencies = nil
+			},
+			parentType: "Skill",
+			wantErr:    "invalid item archive: item 1: a skill cannot have a duration or require explicit entry",
+		},
+		{
+			name:       "null title",
+			modify:     func(archive *ItemArchive) { archive.Items[2].Strings["fr"]["title"] = nil },
+			parentType: "Chapter",
+			wantErr:    `invalid item archive: item 3: wrong value for title in language "fr"`,
+		},
+		{
+			name: "wrong relation value",
+			modify: func(archive *ItemArchive) {
+				archive.Relations[1].Properties = map[string]interface{}{"score_weight": float64(128)}
+			},
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: relation 2-3: wrong value for score_weight",
+		},
+		{
+			name:       "no root item",
+			modify:     func(archive *ItemArchive) { archive.RootItemID = 4 },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: no root item",
+		},
+		{
+			name:       "task parent",
+			modify:     func(*ItemArchive) {},
+			parentType: "Task",
+			wantErr:    "invalid item archive: the root item cannot be a child of the parent item: a task cannot have children items",
+		},
+		{
+			name: "skill root under a chapter",
+			modify: func(archive *ItemArchive) {
+				archive.Items = []ItemArchiveItem{item(1, "Skill")}
+				archive.Relations = nil
+				archive.Dependencies = nil
+			},
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: the root item cannot be a child of the parent item: a skill can only be a child of a skill",
+		},
+		{
+			name:       "relation to an item outside the archive",
+			modify:     func(archive *ItemArchive) { archive.Relations[0].ChildItemID = 4 },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: relation 1-4 refers to an item outside the archive",
+		},
+		{
+			name: "duplicate relation",
+			modify: func(archive *ItemArchive) {
+				archive.Relations = append(archive.Relations, ItemArchiveRelation{ParentItemID: 1, ChildItemID: 2})
+			},
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: duplicate relation 1-2",
+		},
+		{
+			name:       "task with children",
+			modify:     func(archive *ItemArchive) { archive.Items[1].Type = "Task" },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: relation 2-3: a task cannot have children items",
+		},
+		{
+			name:       "unreachable item",
+			modify:     func(archive *ItemArchive) { archive.Relations = archive.Relations[1:2] },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: the relations should make all the items descendants of the root item without cycles",
+		},
+		{
+			name: "cycle",
+			modify: func(archive *ItemArchive) {
+				archive.Items[2].Type = "Chapter"
+				archive.Relations = append(archive.Relations, ItemArchiveRelation{ParentItemID: 3, ChildItemID: 2})
+			},
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: the relations should make all the items descendants of the root item without cycles",
+		},
+		{
+			name:       "dependency on an item outside the archive",
+			modify:     func(archive *ItemArchive) { archive.Dependencies[0].ItemID = 4 },
+			parentType: "Chapter",
+			wantErr:    "invalid item archive: dependency 4-3 refers to an item outside the archive",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			archive := validArchive()
+			tt.modify(archive)
+			err := archive.validate(tt.parentType)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+			assert.ErrorIs(t, err, ErrInvalidItemArchive)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// itemArchiveValueChecker checks a value of an item archive as decoded from JSON.
+type itemArchiveValueChecker func(value interface{}) bool
+
+// itemArchiveItemValueCheckers are the checkers of the item properties of archives.
+// They follow the validation rules of item creation (see items.itemCreate) and the types of the columns.
+var itemArchiveItemValueCheckers = map[string]itemArchiveValueChecker{
+	"url":                              archiveNullableString(2048),
+	"options":                          archiveNullableJSON,
+	"entry_frozen_teams":               archiveBool,
+	"no_score":                         archiveBool,
+	"text_id":                          archiveNullableString(255),
+	"display_details_in_parent":        archiveBool,
+	"read_only":                        archiveBool,
+	"children_layout":                  archiveEnum("List", "Grid"),
+	"full_screen":                      archiveEnum("forceYes", "forceNo", "default"),
+	"hints_allowed":                    archiveBool,
+	"fixed_ranks":                      archiveBool,
+	"validation_type":                  archiveEnum("None", "All", "AllButOne", "Categories", "One", "Manual"),
+	"entry_min_admitted_members_ratio": archiveEnum("All", "Half", "One", "None"),
+	"entering_time_min":                archiveDatetime,
+	"entering_time_max":                archiveDatetime,
+	"entry_max_team_size":              archiveInteger(0, math.MaxInt32),
+	"title_bar_visible":                archiveBool,
+	"allows_multiple_attempts":         archiveBool,
+	"entry_participant_type":           archiveEnum("User", "Team"),
+	"duration":                         archiveNullableDuration,
+	"requires_explicit_entry":          archiveBool,
+	"ranking_freeze_duration":          archiveNullableDuration,
+	"show_user_infos":                  archiveBool,
+	"uses_api":                         archiveBool,
+	"prompt_to_join_group_by_code":     archiveBool,
+	"default_language_tag":             archiveString(6),
+}
+
+// itemArchiveStringValueCheckers are the checkers of the strings of items of archives.
+var itemArchiveStringValueCheckers = map[string]itemArchiveValueChecker{
+	"title":       archiveString(200),
+	"image_url":   archiveNullableString(2048),
+	"subtitle":    archiveNullableString(200),
+	"description": archiveNullableString(0),
+}
+
+// itemArchiveRelationValueCheckers are the checkers of the properties of relations of archives.
+var itemArchiveRelationValueCheckers = map[string]itemArchiveValueChecker{
+	"child_order":                   archiveInteger(math.MinInt32, math.MaxInt32),
+	"category":                      archiveEnum("Undefined", "Discovery", "Application", "Validation", "Challenge"),
+	"score_weight":                  archiveInteger(math.MinInt8, math.MaxInt8),
+	"content_view_propagation":      archiveEnum("none", "as_info", "as_content"),
+	"upper_view_levels_propagation": archiveEnum("use_content_view_propagation", "as_content_with_descendants", "as_is"),
+	"grant_view_propagation":        archiveBool,
+	"watch_propagation":             archiveBool,
+	"edit_propagation":              archiveBool,
+	"request_help_propagation":      archiveBool,
+}
+
+// checkItemArchiveValues returns the first column (in the order of columns) whose value is wrong.
+// Missing values are allowed as they get the default values of the columns.
+func checkItemArchiveValues(values map[string]interface{}, columns []string, checkers map[string]itemArchiveValueChecker) (
+	wrongColumn string, ok bool,
+) {
+	for _, column := range columns {
+		if value, found := values[column]; found && !checkers[column](value) {
+			return column, false
+		}
+	}
+	return "", true
+}
+
+// validateItemArchiveItemValues checks the properties and the strings of an item of an archive.
+func validateItemArchiveItemValues(item *ItemArchiveItem) error {
+	if column, ok := checkItemArchiveValues(item.Properties, RevisionedItemColumns, itemArchiveItemValueCheckers); !ok {
+		return fmt.Errorf("wrong value for %s", column)
+	}
+
+	hasDuration := item.Properties["duration"] != nil
+	requiresExplicitEntry := isTruthyJSONValue(item.Properties["requires_explicit_entry"])
+	if item.Type == "Skill" && (hasDuration || requiresExplicitEntry) {
+		return errors.New("a skill cannot have a duration or require explicit entry")
+	}
+	if hasDuration && !requiresExplicitEntry {
+		return errors.New("an item with a duration should require explicit entry")
+	}
+
+	for languageTag, values := range item.Strings {
+		if column, ok := checkItemArchiveValues(values, revisionedItemStringColumns, itemArchiveStringValueCheckers); !ok {
+			return fmt.Errorf("wrong value for %s in language %q", column, languageTag)
+		}
+	}
+	return nil
+}
+
+func archiveBool(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case bool:
+		return true
+	case float64:
+		return typedValue == 0 || typedValue == 1
+	}
+	return false
+}
+
+func archiveEnum(allowedValues ...string) itemArchiveValueChecker {
+	return func(value interface{}) bool {
+		stringValue, ok := value.(string)
+		if !ok {
+			return false
+		}
+		for _, allowedValue := range allowedValues {
+			if stringValue == allowedValue {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// archiveString returns a checker of strings having at most maxLength characters (0 means no limit).
+func archiveString(maxLength int) itemArchiveValueChecker {
+	return func(value interface{}) bool {
+		stringValue, ok := value.(string)
+		return ok && (maxLength == 0 || utf8.RuneCountInString(stringValue) <= maxLength)
+	}
+}
+
+func archiveNullableString(maxLength int) itemArchiveValueChecker {
+	checkString := archiveString(maxLength)
+	return func(value interface{}) bool {
+		return value == nil || checkString(value)
+	}
+}
+
+func archiveInteger(minValue, maxValue float64) itemArchiveValueChecker {
+	return func(value interface{}) bool {
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number) && number >= minValue && number <= maxValue
+	}
+}
+
+func archiveNullableJSON(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	stringValue, ok := value.(string)
+	return ok && json.Valid([]byte(stringValue))
+}
+
+func archiveDatetime(value interface{}) bool {
+	stringValue, ok := value.(string)
+	if !ok {
+		return false
+	}
+	_, err := time.Parse("2006-01-02 15:04:05", stringValue)
+	return err == nil
+}
+
+// archiveNullableDuration checks that the value is null or a duration 'h:mm:ss' accepted by MySQL's TIME
+// (the same rule as the 'duration' validator of forms).
+func archiveNullableDuration(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	stringValue, ok := value.(string)
+	if !ok {
+		return false
+	}
+	hms := strings.Split(stringValue, ":")
+	if len(hms) != 3 {
+		return false
+	}
+	limits := [3]int{838, 59, 59}
+	for index, part := range hms {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 || number > limits[index] {
+			return false
+		}
+	}
+	return true
+}
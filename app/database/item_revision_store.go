@@ -0,0 +1,207 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ItemRevisionStore implements database operations on `item_revisions`.
+type ItemRevisionStore struct {
+	*DataStore
+}
+
+// ItemRevisionSnapshot is the state of an item recorded in a revision:
+// the properties of the item, its strings (by language tag) and its children relations (by child item id).
+type ItemRevisionSnapshot struct {
+	Item     map[string]interface{}            `json:"item"`
+	Strings  map[string]map[string]interface{} `json:"strings"`
+	Children map[string]map[string]interface{} `json:"children"`
+}
+
+// ItemRevisionChange is a changed value of an item revision.
+type ItemRevisionChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// RevisionedItemColumns is the list of `items` columns recorded in item revisions
+// (the properties of an item that can be changed by the itemUpdate service).
+var RevisionedItemColumns = []string{
+	"url", "options", "entry_frozen_teams", "no_score", "text_id", "display_details_in_parent", "read_only",
+	"children_layout", "full_screen", "hints_allowed", "fixed_ranks", "validation_type", "entry_min_admitted_members_ratio",
+	"entering_time_min", "entering_time_max", "entry_max_team_size", "title_bar_visible", "allows_multiple_attempts",
+	"entry_participant_type", "duration", "requires_explicit_entry", "ranking_freeze_duration", "show_user_infos",
+	"uses_api", "prompt_to_join_group_by_code", "default_language_tag",
+}
+
+var (
+	revisionedItemStringColumns = []string{"title", "image_url", "subtitle", "description"}
+	revisionedItemItemColumns   = []string{
+		"child_order", "category", "score_weight", "content_view_propagation", "upper_view_levels_propagation",
+		"grant_view_propagation", "watch_propagation", "edit_propagation", "request_help_propagation",
+	}
+	// temporal values are stored as strings in the format accepted by MySQL (JSON_OBJECT would add microseconds)
+	revisionedColumnsStoredAsStrings = map[string]bool{
+		"entering_time_min": true, "entering_time_max": true, "duration": true, "ranking_freeze_duration": true,
+	}
+)
+
+func jsonObjectOfColumns(tableName string, columns []string) string {
+	arguments := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if revisionedColumnsStoredAsStrings[column] {
+			arguments = append(arguments, fmt.Sprintf("'%s', CAST(%s.%s AS CHAR)", column, tableName, column))
+			continue
+		}
+		arguments = append(arguments, fmt.Sprintf("'%s', %s.%s", column, tableName, column))
+	}
+	return "JSON_OBJECT(" + strings.Join(arguments, ", ") + ")"
+}
+
+var itemRevisionSnapshotExpression = `
+	JSON_OBJECT(
+		'item', ` + jsonObjectOfColumns("items", RevisionedItemColumns) + `,
+		'strings', IFNULL((
+			SELECT JSON_OBJECTAGG(items_strings.language_tag, ` +
+	jsonObjectOfColumns("items_strings", revisionedItemStringColumns) + `)
+			FROM items_strings WHERE items_strings.item_id = items.id
+		), JSON_OBJECT()),
+		'children', IFNULL((
+			SELECT JSON_OBJECTAGG(items_items.child_item_id, ` + jsonObjectOfColumns("items_items", revisionedItemItemColumns) + `)
+			FROM items_items WHERE items_items.parent_item_id = items.id
+		), JSON_OBJECT())
+	)`
+
+// Record records the current state of the given item (its properties, strings, and children relations)
+// as a new revision made by the given author.
+// No revision is recorded if nothing has changed since the last revision or if the item doesn't exist.
+// The method should be called inside a transaction after the item has been modified.
+func (s *ItemRevisionStore) Record(itemID, authorID int64) error {
+	return s.record(itemID, &authorID)
+}
+
+// RecordInitialIfMissing records the current state of the given item as a revision without author
+// if the item has no revisions yet (like items created before revisions were introduced),
+// so that the state of the item before the first recorded change can be restored.
+// The method should be called inside a transaction before the item is modified.
+func (s *ItemRevisionStore) RecordInitialIfMissing(itemID int64) error {
+	s.mustBeInTransaction()
+
+	found, err := s.Where("item_id = ?", itemID).WithExclusiveWriteLock().HasRows()
+	if err != nil || found {
+		return err
+	}
+	return s.record(itemID, nil)
+}
+
+func (s *ItemRevisionStore) record(itemID int64, authorID *int64) error {
+	s.mustBeInTransaction()
+
+	var snapshot string
+	err := s.Items().ByID(itemID).PluckFirst(itemRevisionSnapshotExpression, &snapshot).Error()
+	if gorm.IsRecordNotFoundError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var previous struct {
+		Revision int
+		Snapshot string
+	}
+	err = s.Where("item_id = ?", itemID).WithExclusiveWriteLock().
+		Select("revision, CAST(snapshot AS CHAR) AS snapshot").
+		Order("revision DESC").Limit(1).Scan(&previous).Error()
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return err
+	}
+
+	diff, err := DiffItemRevisionSnapshots(previous.Snapshot, snapshot)
+	if err != nil {
+		return err
+	}
+	if previous.Revision > 0 && len(diff) == 0 {
+		return nil
+	}
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	return s.InsertMap(map[string]interface{}{
+		"item_id":   itemID,
+		"revision":  previous.Revision + 1,
+		"author_id": authorID,
+		"snapshot":  snapshot,
+		"diff":      string(diffJSON),
+	})
+}
+
+// GetSnapshot returns the snapshot of the given revision of the given item.
+// It returns gorm.ErrRecordNotFound if there is no such revision.
+func (s *ItemRevisionStore) GetSnapshot(itemID int64, revision int) (snapshot ItemRevisionSnapshot, err error) {
+	var snapshotJSON string
+	err = s.Where("item_id = ? AND revision = ?", itemID, revision).WithSharedWriteLock().
+		PluckFirst("CAST(snapshot AS CHAR)", &snapshotJSON).Error()
+	if err != nil {
+		return ItemRevisionSnapshot{}, err
+	}
+	err = json.Unmarshal([]byte(snapshotJSON), &snapshot)
+	return snapshot, err
+}
+
+// DiffItemRevisionSnapshots compares two JSON-encoded snapshots of an item and returns the changed values
+// by their paths (like "item.url", "strings.fr.title", or "children.1234.child_order").
+// An empty string stands for an empty snapshot.
+func DiffItemRevisionSnapshots(oldSnapshot, newSnapshot string) (map[string]ItemRevisionChange, error) {
+	oldValues, err := flattenItemRevisionSnapshot(oldSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	newValues, err := flattenItemRevisionSnapshot(newSnapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(map[string]ItemRevisionChange)
+	for path, oldValue := range oldValues {
+		if newValue, ok := newValues[path]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			diff[path] = ItemRevisionChange{Old: oldValue, New: newValue}
+		}
+	}
+	for path, newValue := range newValues {
+		if _, ok := oldValues[path]; !ok {
+			diff[path] = ItemRevisionChange{New: newValue}
+		}
+	}
+	return diff, nil
+}
+
+func flattenItemRevisionSnapshot(snapshot string) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if snapshot == "" {
+		return result, nil
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(snapshot), &decoded); err != nil {
+		return nil, err
+	}
+	flattenJSONObject("", decoded, result)
+	return result, nil
+}
+
+func flattenJSONObject(prefix string, object map[string]interface{}, result map[string]interface{}) {
+	for key, value := range object {
+		if nestedObject, ok := value.(map[string]interface{}); ok {
+			flattenJSONObject(prefix+key+".", nestedObject, result)
+			continue
+		}
+		result[prefix+key] = value
+	}
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffItemRevisionSnapshots(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldSnapshot string
+		newSnapshot string
+		want        map[string]ItemRevisionChange
+	}{
+		{
+			name:        "first revision",
+			newSnapshot: `{"item": {"url": null, "no_score": 0}, "strings": {"fr": {"title": "Titre"}}, "children": {}}`,
+			want: map[string]ItemRevisionChange{
+				"item.url":         {},
+				"item.no_score":    {New: float64(0)},
+				"strings.fr.title": {New: "Titre"},
+			},
+		},
+		{
+			name: "changes",
+			oldSnapshot: `{"item": {"url": "http://a", "no_score": 0}, "strings": {"fr": {"title": "Titre"}},
+				"children": {"12": {"child_order": 1}, "13": {"child_order": 2}}}`,
+			newSnapshot: `{"item": {"url": "http://b", "no_score": 0}, "strings": {"fr": {"title": "Titre"}, "en": {"title": "Title"}},
+				"children": {"13": {"child_order": 1}}}`,
+			want: map[string]ItemRevisionChange{
+				"item.url":                {Old: "http://a", New: "http://b"},
+				"strings.en.title":        {New: "Title"},
+				"children.12.child_order": {Old: float64(1)},
+				"children.13.child_order": {Old: float64(2), New: float64(1)},
+			},
+		},
+		{
+			name:        "no changes",
+			oldSnapshot: `{"item": {"url": "http://a"}, "strings": {}, "children": {}}`,
+			newSnapshot: `{"item": {"url": "http://a"}, "strings": {}, "children": {}}`,
+			want:        map[string]ItemRevisionChange{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := DiffItemRevisionSnapshots(tt.oldSnapshot, tt.newSnapshot)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, diff)
+		})
+	}
+}
+
+func TestDiffItemRevisionSnapshots_WrongJSON(t *testing.T) {
+	_, err := DiffItemRevisionSnapshots("{", "{}")
+	assert.EqualError(t, err, "unexpected end of JSON input")
+	_, err = DiffItemRevisionSnapshots("{}", "[")
+	assert.EqualError(t, err, "unexpected end of JSON input")
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+)
+
+// LTIContextStore implements database operations on `lti_contexts`
+// (contexts (courses) of LTI platforms linked to groups).
+type LTIContextStore struct {
+	*DataStore
+}
+
+// LTIContext identifies a context (e.g., a course) of an LTI platform.
+type LTIContext struct {
+	Issuer       string
+	DeploymentID string
+	ContextID    string
+	// Title is the name of the group created for the context.
+	Title string
+}
+
+// JoinGroupOfContext makes the user a member of the group linked to the LTI context
+// (or a manager of the group if asManager is true). On the first launch from the context,
+// it creates a group of type "Class" for the context.
+// The membership is created as a 'joined_by_lti' transition, so it respects the approvals
+// and the limits of the group.
+func (s *LTIContextStore) JoinGroupOfContext(context *LTIContext, userID int64, asManager bool) (groupID int64, err error) {
+	s.mustBeInTransaction()
+	defer recoverPanics(&err)
+
+	err = s.WithExclusiveWriteLock().
+		Where("issuer = ? AND deployment_id = ? AND context_id = ?", context.Issuer, context.DeploymentID, context.ContextID).
+		PluckFirst("group_id", &groupID).Error()
+	if gorm.IsRecordNotFoundError(err) {
+		groupID = s.createGroupOfContext(context)
+		err = nil
+	}
+	mustNotBeError(err)
+
+	if asManager {
+		mustNotBeError(s.Exec(`
+			INSERT IGNORE INTO group_managers (group_id, manager_id, can_manage, can_grant_group_access, can_watch_members)
+			VALUES (?, ?, "memberships", 1, 1)`, groupID, userID).Error())
+		return groupID, nil
+	}
+
+	alreadyMember, err := s.ActiveGroupGroups().
+		Where("parent_group_id = ? AND child_group_id = ?", groupID, userID).HasRows()
+	mustNotBeError(err)
+	if alreadyMember {
+		return groupID, nil
+	}
+
+	results, _, err := s.GroupGroups().Transition(
+		UserJoinsGroupByLTI, groupID, []int64{userID}, map[int64]GroupApprovals{}, userID)
+	mustNotBeError(err)
+	if results[userID] != Success {
+		logging.SharedLogger.WithContext(s.ctx).
+			Warnf("Cannot add the user %d into the group %d of the LTI context %q (%s), reason: %s",
+				userID, groupID, context.ContextID, context.Issuer, results[userID])
+	}
+	return groupID, nil
+}
+
+func (s *LTIContextStore) createGroupOfContext(context *LTIContext) int64 {
+	name := context.Title
+	if name == "" {
+		name = context.ContextID
+	}
+	var groupID int64
+	mustNotBeError(s.RetryOnDuplicatePrimaryKeyError("groups", func(retryIDStore *DataStore) error {
+		groupID = retryIDStore.NewID()
+		return retryIDStore.Groups().InsertMap(map[string]interface{}{
+			"id":          groupID,
+			"name":        name,
+			"type":        "Class",
+			"created_at":  Now(),
+			"is_open":     false,
+			"send_emails": false,
+		})
+	}))
+	mustNotBeError(s.InsertMap(map[string]interface{}{
+		"issuer":        context.Issuer,
+		"deployment_id": context.DeploymentID,
+		"context_id":    context.ContextID,
+		"group_id":      groupID,
+	}))
+	return groupID
+}
@@ -0,0 +1,85 @@
+//go:build !unit
+
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+const ltiContextsFixture = `
+	groups: [{id: 1, type: Class}, {id: 4, type: User}, {id: 5, type: User}]
+	users: [{group_id: 4, login: john}, {group_id: 5, login: jane}]
+	groups_ancestors:
+		- {ancestor_group_id: 1, child_group_id: 1}
+		- {ancestor_group_id: 4, child_group_id: 4}
+		- {ancestor_group_id: 5, child_group_id: 5}
+	lti_contexts: [{issuer: "https://moodle.example.org", deployment_id: "1", context_id: "course1", group_id: 1}]`
+
+func TestLTIContextStore_JoinGroupOfContext_JoinsTheExistingGroup(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(ltiContextsFixture)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	var groupID int64
+	require.NoError(t, store.InTransaction(func(store *database.DataStore) (err error) {
+		groupID, err = store.LTIContexts().JoinGroupOfContext(&database.LTIContext{
+			Issuer: "https://moodle.example.org", DeploymentID: "1", ContextID: "course1", Title: "Course 1",
+		}, 4, false)
+		return err
+	}))
+	assert.Equal(t, int64(1), groupID)
+
+	found, err := store.ActiveGroupGroups().Where("parent_group_id = 1 AND child_group_id = 4").HasRows()
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	var action string
+	require.NoError(t, store.Table("group_membership_changes").
+		Where("group_id = 1 AND member_id = 4").PluckFirst("action", &action).Error())
+	assert.Equal(t, "joined_by_lti", action)
+}
+
+func TestLTIContextStore_JoinGroupOfContext_CreatesTheGroupAndMakesInstructorsManagers(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(ltiContextsFixture)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	var groupID int64
+	require.NoError(t, store.InTransaction(func(store *database.DataStore) (err error) {
+		groupID, err = store.LTIContexts().JoinGroupOfContext(&database.LTIContext{
+			Issuer: "https://moodle.example.org", DeploymentID: "1", ContextID: "course2", Title: "Course 2",
+		}, 5, true)
+		return err
+	}))
+
+	var group struct {
+		Name string
+		Type string
+	}
+	require.NoError(t, store.Groups().ByID(groupID).Select("name, type").Take(&group).Error())
+	assert.Equal(t, "Course 2", group.Name)
+	assert.Equal(t, "Class", group.Type)
+
+	var linkedGroupID int64
+	require.NoError(t, store.LTIContexts().Where("context_id = 'course2'").PluckFirst("group_id", &linkedGroupID).Error())
+	assert.Equal(t, groupID, linkedGroupID)
+
+	found, err := store.GroupManagers().Where("group_id = ? AND manager_id = 5 AND can_manage = 'memberships'", groupID).HasRows()
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = store.GroupGroups().Where("parent_group_id = ?", groupID).HasRows()
+	require.NoError(t, err)
+	assert.False(t, found)
+}
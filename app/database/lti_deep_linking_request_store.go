@@ -0,0 +1,7 @@
+package database
+
+// LTIDeepLinkingRequestStore implements database operations on `lti_deep_linking_requests`
+// (LTI deep linking requests waiting for a response).
+type LTIDeepLinkingRequestStore struct {
+	*DataStore
+}
@@ -0,0 +1,7 @@
+package database
+
+// LTILaunchStateStore implements database operations on `lti_launch_states`
+// (states of LTI launches initiated by platforms).
+type LTILaunchStateStore struct {
+	*DataStore
+}
@@ -0,0 +1,7 @@
+package database
+
+// LTILineItemStore implements database operations on `lti_line_items`
+// (line items of LTI platforms the scores are published to).
+type LTILineItemStore struct {
+	*DataStore
+}
@@ -3,9 +3,32 @@ package database
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
+	"time"
 	"unsafe"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/metrics"
+)
+
+const (
+	queryOperationExec  = "exec"
+	queryOperationQuery = "query"
 )
 
+// observeQuery counts the query and measures its duration (see metrics.DBQueries & metrics.DBQueryDuration).
+// The queries skipped by the driver (to be run as prepared statements instead) are not counted.
+func observeQuery(operation string, startTime time.Time, err *error) {
+	if errors.Is(*err, driver.ErrSkip) {
+		return
+	}
+	status := "ok"
+	if *err != nil {
+		status = "error"
+	}
+	metrics.DBQueries.WithLabelValues(operation, status).Inc()
+	metrics.DBQueryDuration.WithLabelValues(operation).Observe(time.Since(startTime).Seconds())
+}
+
 type mysqlConnWrapper struct {
 	conn driver.Conn
 }
@@ -31,12 +54,19 @@ func (conn *mysqlConnWrapper) BeginTx(ctx context.Context, opts driver.TxOptions
 var _ driver.ConnBeginTx = &mysqlConnWrapper{}
 
 func (conn *mysqlConnWrapper) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
-	return conn.conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	stmt, err := conn.conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &mysqlStmtWrapper{stmt: stmt}, nil
 }
 
 var _ driver.ConnPrepareContext = &mysqlConnWrapper{}
 
-func (conn *mysqlConnWrapper) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+func (conn *mysqlConnWrapper) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (
+	result driver.Result, err error,
+) {
+	defer observeQuery(queryOperationExec, time.Now(), &err)
 	return conn.conn.(driver.ExecerContext).ExecContext(ctx, query, args)
 }
 
@@ -48,7 +78,10 @@ func (conn *mysqlConnWrapper) Ping(ctx context.Context) error {
 
 var _ driver.Pinger = &mysqlConnWrapper{}
 
-func (conn *mysqlConnWrapper) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+func (conn *mysqlConnWrapper) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (
+	rows driver.Rows, err error,
+) {
+	defer observeQuery(queryOperationQuery, time.Now(), &err)
 	return conn.conn.(driver.QueryerContext).QueryContext(ctx, query, args)
 }
 
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+)
+
+// mysqlStmtWrapper wraps prepared statements of the MySQL driver to collect metrics of their executions.
+type mysqlStmtWrapper struct {
+	stmt driver.Stmt
+}
+
+func (stmt *mysqlStmtWrapper) Close() error {
+	return stmt.stmt.Close()
+}
+
+func (stmt *mysqlStmtWrapper) NumInput() int {
+	return stmt.stmt.NumInput()
+}
+
+//nolint:staticcheck // SA1019: driver.Stmt requires Exec to be implemented
+func (stmt *mysqlStmtWrapper) Exec(args []driver.Value) (result driver.Result, err error) {
+	defer observeQuery(queryOperationExec, time.Now(), &err)
+	return stmt.stmt.Exec(args) //nolint:staticcheck // SA1019: the wrapped statement is called the same way
+}
+
+//nolint:staticcheck // SA1019: driver.Stmt requires Query to be implemented
+func (stmt *mysqlStmtWrapper) Query(args []driver.Value) (rows driver.Rows, err error) {
+	defer observeQuery(queryOperationQuery, time.Now(), &err)
+	return stmt.stmt.Query(args) //nolint:staticcheck // SA1019: the wrapped statement is called the same way
+}
+
+var _ driver.Stmt = &mysqlStmtWrapper{}
+
+func (stmt *mysqlStmtWrapper) ExecContext(ctx context.Context, args []driver.NamedValue) (result driver.Result, err error) {
+	defer observeQuery(queryOperationExec, time.Now(), &err)
+	return stmt.stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+}
+
+var _ driver.StmtExecContext = &mysqlStmtWrapper{}
+
+func (stmt *mysqlStmtWrapper) QueryContext(ctx context.Context, args []driver.NamedValue) (rows driver.Rows, err error) {
+	defer observeQuery(queryOperationQuery, time.Now(), &err)
+	return stmt.stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+}
+
+var _ driver.StmtQueryContext = &mysqlStmtWrapper{}
+
+func (stmt *mysqlStmtWrapper) CheckNamedValue(nv *driver.NamedValue) error {
+	return stmt.stmt.(driver.NamedValueChecker).CheckNamedValue(nv)
+}
+
+var _ driver.NamedValueChecker = &mysqlStmtWrapper{}
+
+func (stmt *mysqlStmtWrapper) ColumnConverter(idx int) driver.ValueConverter {
+	return stmt.stmt.(driver.ColumnConverter).ColumnConverter(idx) //nolint:staticcheck // SA1019: implemented by the MySQL driver
+}
+
+var _ driver.ColumnConverter = &mysqlStmtWrapper{} //nolint:staticcheck // SA1019: implemented by the MySQL driver
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/metrics"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/metrics/metricstest"
+)
+
+type stmtContextMock struct {
+	stmtMock
+	err error
+}
+
+func (stmt *stmtContextMock) ExecContext(context.Context, []driver.NamedValue) (driver.Result, error) {
+	return nil, stmt.err
+}
+
+func (stmt *stmtContextMock) QueryContext(context.Context, []driver.NamedValue) (driver.Rows, error) {
+	return nil, stmt.err
+}
+
+func Test_mysqlStmtWrapper_CountsQueries(t *testing.T) {
+	initialOKExecs := testutil.ToFloat64(metrics.DBQueries.WithLabelValues(queryOperationExec, "ok"))
+	initialFailedQueries := testutil.ToFloat64(metrics.DBQueries.WithLabelValues(queryOperationQuery, "error"))
+	initialExecDurations := metricstest.HistogramCount(metrics.DBQueryDuration.WithLabelValues(queryOperationExec))
+
+	stmt := &mysqlStmtWrapper{stmt: &stmtContextMock{}}
+	_, err := stmt.ExecContext(context.Background(), nil)
+	assert.NoError(t, err)
+	_, err = stmt.Exec(nil) //nolint:staticcheck // SA1019: the deprecated method should be counted as well
+	assert.NoError(t, err)
+
+	expectedErr := errors.New("error")
+	stmt = &mysqlStmtWrapper{stmt: &stmtContextMock{err: expectedErr}}
+	_, err = stmt.QueryContext(context.Background(), nil)
+	assert.Equal(t, expectedErr, err)
+
+	assert.Equal(t, initialOKExecs+2, testutil.ToFloat64(metrics.DBQueries.WithLabelValues(queryOperationExec, "ok")))
+	assert.Equal(t, initialFailedQueries+1, testutil.ToFloat64(metrics.DBQueries.WithLabelValues(queryOperationQuery, "error")))
+	assert.Equal(t, initialExecDurations+2, metricstest.HistogramCount(metrics.DBQueryDuration.WithLabelValues(queryOperationExec)))
+}
+
+func Test_observeQuery_IgnoresSkippedQueries(t *testing.T) {
+	initialSkipped := testutil.ToFloat64(metrics.DBQueries.WithLabelValues(queryOperationQuery, "error"))
+
+	err := driver.ErrSkip
+	observeQuery(queryOperationQuery, time.Now(), &err)
+
+	assert.Equal(t, initialSkipped, testutil.ToFloat64(metrics.DBQueries.WithLabelValues(queryOperationQuery, "error")))
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"strings"
+)
+
+const (
+	// NotificationTypeThreadUpdated is the type of notifications sent to participants & helpers of a thread updated by someone else.
+	NotificationTypeThreadUpdated = "thread_updated"
+	// NotificationTypeAnswerGraded is the type of notifications sent to participants whose answer has been graded manually.
+	NotificationTypeAnswerGraded = "answer_graded"
+)
+
+// NotificationStore implements database operations on `notifications` (the in-app notifications of users).
+type NotificationStore struct {
+	*DataStore
+}
+
+// insertForGroupMembershipChanges creates notifications of the given changes of members of the given group
+// ('invitation_created', 'join_request_accepted', 'join_request_refused', and 'removed' ones, other changes are ignored)
+// for the members being users, except for the user who has performed the changes.
+func (s *NotificationStore) insertForGroupMembershipChanges(groupID int64, changes map[int64]GroupMembershipAction,
+	performedByUserID int64,
+) error {
+	memberIDsByType := make(map[GroupMembershipAction][]int64, 4)
+	for memberID, toAction := range changes {
+		action := GroupMembershipAction(toAction[strings.LastIndex(string(toAction), ",")+1:])
+		switch action {
+		case InvitationCreated, JoinRequestAccepted, JoinRequestRefused, Removed:
+			if memberID != performedByUserID {
+				memberIDsByType[action] = append(memberIDsByType[action], memberID)
+			}
+		}
+	}
+
+	for _, notificationType := range []GroupMembershipAction{InvitationCreated, JoinRequestAccepted, JoinRequestRefused, Removed} {
+		memberIDs := memberIDsByType[notificationType]
+		if len(memberIDs) == 0 {
+			continue
+		}
+		if err := s.db.Exec(`
+			INSERT INTO notifications (user_id, type, payload)
+			SELECT
+				users.group_id, ?,
+				JSON_OBJECT('group_id', CAST(groups.id AS CHAR), 'group_name', groups.name)
+			FROM users
+			JOIN `+"`groups`"+` ON groups.id = ?
+			WHERE users.group_id IN (?)`,
+			notificationType, groupID, memberIDs).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InsertForThreadUpdate creates notifications of an update of the thread of the given item & participant
+// for the participant (or for the members of the participant team) and for the members of the helper group
+// of the thread, except for the user who has updated the thread.
+// A user has at most one notification per thread: the previous one is replaced (and marked as unread again).
+func (s *NotificationStore) InsertForThreadUpdate(itemID, participantID, initiatorID int64) error {
+	return s.db.Exec(`
+		INSERT INTO notifications (user_id, type, payload, deduplication_key)
+		SELECT
+			users.group_id, ?,
+			JSON_OBJECT(
+				'item_id', CAST(threads.item_id AS CHAR),
+				'participant_id', CAST(threads.participant_id AS CHAR),
+				'status', threads.status
+			),
+			CONCAT(?, ':', threads.item_id, ':', threads.participant_id)
+		FROM threads
+		JOIN users ON users.group_id = threads.participant_id OR users.group_id IN (
+			SELECT child_group_id FROM groups_groups_active WHERE parent_group_id = threads.participant_id
+		) OR users.group_id IN (
+			SELECT child_group_id FROM groups_ancestors_active WHERE ancestor_group_id = threads.helper_group_id
+		)
+		WHERE threads.item_id = ? AND threads.participant_id = ? AND users.group_id != ?
+		ON DUPLICATE KEY UPDATE payload = VALUES(payload), created_at = NOW(3), read_at = NULL`,
+		NotificationTypeThreadUpdated, NotificationTypeThreadUpdated, itemID, participantID, initiatorID).Error
+}
+
+// InsertForAnswerGrading creates notifications of a manual grading of the given answer
+// for the participant of the answer (or for the members of the participant team), except for the grader.
+// A user has at most one notification per answer: the previous one is replaced (and marked as unread again).
+func (s *NotificationStore) InsertForAnswerGrading(answerID, graderID int64) error {
+	return s.db.Exec(`
+		INSERT INTO notifications (user_id, type, payload, deduplication_key)
+		SELECT
+			users.group_id, ?,
+			JSON_OBJECT(
+				'answer_id', CAST(answers.id AS CHAR),
+				'item_id', CAST(answers.item_id AS CHAR),
+				'participant_id', CAST(answers.participant_id AS CHAR)
+			),
+			CONCAT(?, ':', answers.id)
+		FROM answers
+		JOIN users ON users.group_id = answers.participant_id OR users.group_id IN (
+			SELECT child_group_id FROM groups_groups_active WHERE parent_group_id = answers.participant_id
+		)
+		WHERE answers.id = ? AND users.group_id != ?
+		ON DUPLICATE KEY UPDATE payload = VALUES(payload), created_at = NOW(3), read_at = NULL`,
+		NotificationTypeAnswerGraded, NotificationTypeAnswerGraded, answerID, graderID).Error
+}
+
+// unreadCondition is the condition on `notifications` & `users` to be satisfied by unread notifications:
+// a notification is read if it has been marked as read or if it has been created
+// before `users.notifications_read_at` (see PUT /current-user/notifications-read-at).
+const unreadCondition = `
+	notifications.read_at IS NULL AND
+	(users.notifications_read_at IS NULL OR notifications.created_at > users.notifications_read_at)`
+
+// NotificationIsReadColumn is an SQL expression telling if a notification is read.
+// It can be used in queries built with NotificationStore.ForUser().
+const NotificationIsReadColumn = "NOT (" + unreadCondition + ")"
+
+// ForUser returns a composable query for getting notifications of the given user
+// (joined with the user so that the read status of the notifications can be computed).
+func (s *NotificationStore) ForUser(userID int64) *DB {
+	return s.
+		Joins("JOIN users ON users.group_id = notifications.user_id").
+		Where("notifications.user_id = ?", userID)
+}
+
+// CountUnread returns the number of unread notifications of the given user.
+func (s *NotificationStore) CountUnread(userID int64) (count int64, err error) {
+	err = s.ForUser(userID).Where(unreadCondition).Count(&count).Error()
+	return count, err
+}
@@ -0,0 +1,131 @@
+//go:build !unit
+
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+const notificationsFixture = `
+	groups: [{id: 1, name: Club}, {id: 2, name: Team, type: Team}, {id: 3}, {id: 4}, {id: 5}, {id: 6}, {id: 8, name: Helpers}]
+	users:
+		- {group_id: 3, login: jane}
+		- {group_id: 4, login: john}
+		- {group_id: 5, login: paul}
+		- {group_id: 6, login: anna}
+	groups_groups:
+		- {parent_group_id: 2, child_group_id: 3}
+		- {parent_group_id: 2, child_group_id: 4}
+		- {parent_group_id: 8, child_group_id: 6}
+	groups_ancestors:
+		- {ancestor_group_id: 1, child_group_id: 1}
+		- {ancestor_group_id: 2, child_group_id: 2}
+		- {ancestor_group_id: 2, child_group_id: 3}
+		- {ancestor_group_id: 2, child_group_id: 4}
+		- {ancestor_group_id: 3, child_group_id: 3}
+		- {ancestor_group_id: 4, child_group_id: 4}
+		- {ancestor_group_id: 5, child_group_id: 5}
+		- {ancestor_group_id: 6, child_group_id: 6}
+		- {ancestor_group_id: 8, child_group_id: 6}
+		- {ancestor_group_id: 8, child_group_id: 8}`
+
+func getNotifications(t *testing.T, store *database.DataStore) []map[string]interface{} {
+	t.Helper()
+
+	var notifications []map[string]interface{}
+	require.NoError(t, store.Notifications().
+		Select("user_id, type, CAST(payload AS CHAR) AS payload, read_at").
+		Order("user_id, id").ScanIntoSliceOfMaps(&notifications).Error())
+	return notifications
+}
+
+func TestNotificationStore_InsertsOnTransitions(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(notificationsFixture)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.InTransaction(func(store *database.DataStore) error {
+		_, _, err := store.GroupGroups().Transition(database.AdminCreatesInvitation, 1, []int64{3, 4}, nil, 4)
+		return err
+	}))
+
+	assert.Equal(t, []map[string]interface{}{
+		{"user_id": int64(3), "type": "invitation_created", "payload": `{"group_id": "1", "group_name": "Club"}`, "read_at": nil},
+	}, getNotifications(t, store))
+}
+
+func TestNotificationStore_InsertForThreadUpdate(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(notificationsFixture, `
+		items: [{id: 10, default_language_tag: en}]
+		threads:
+			- {item_id: 10, participant_id: 2, status: waiting_for_trainer, helper_group_id: 8, latest_update_at: "2020-01-01 00:00:00"}
+		notifications:
+			- {user_id: 3, type: thread_updated, payload: '{}', deduplication_key: "thread_updated:10:2",
+			   created_at: "2020-01-01 00:00:00", read_at: "2020-01-02 00:00:00"}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.Notifications().InsertForThreadUpdate(10, 2, 4))
+
+	payload := `{"status": "waiting_for_trainer", "item_id": "10", "participant_id": "2"}`
+	assert.Equal(t, []map[string]interface{}{
+		{"user_id": int64(3), "type": "thread_updated", "payload": payload, "read_at": nil},
+		{"user_id": int64(6), "type": "thread_updated", "payload": payload, "read_at": nil},
+	}, getNotifications(t, store))
+}
+
+func TestNotificationStore_InsertForAnswerGrading(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(notificationsFixture, `
+		items: [{id: 10, default_language_tag: en}]
+		attempts: [{participant_id: 2, id: 0}, {participant_id: 5, id: 0}]
+		answers:
+			- {id: 100, author_id: 3, participant_id: 2, attempt_id: 0, item_id: 10, type: Submission, created_at: "2020-01-01 00:00:00"}
+			- {id: 101, author_id: 5, participant_id: 5, attempt_id: 0, item_id: 10, type: Submission, created_at: "2020-01-01 00:00:00"}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.Notifications().InsertForAnswerGrading(100, 6))
+	require.NoError(t, store.Notifications().InsertForAnswerGrading(101, 5))
+	require.NoError(t, store.Notifications().InsertForAnswerGrading(100, 6)) // replaces the previous notifications
+
+	payload := `{"item_id": "10", "answer_id": "100", "participant_id": "2"}`
+	assert.Equal(t, []map[string]interface{}{
+		{"user_id": int64(3), "type": "answer_graded", "payload": payload, "read_at": nil},
+		{"user_id": int64(4), "type": "answer_graded", "payload": payload, "read_at": nil},
+	}, getNotifications(t, store))
+}
+
+func TestNotificationStore_CountUnread(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		groups: [{id: 3}, {id: 4}]
+		users: [{group_id: 3, login: jane, notifications_read_at: "2020-01-02 00:00:00"}, {group_id: 4, login: john}]
+		notifications:
+			- {user_id: 3, type: removed, payload: '{}', created_at: "2020-01-01 00:00:00"}
+			- {user_id: 3, type: removed, payload: '{}', created_at: "2020-01-03 00:00:00"}
+			- {user_id: 3, type: removed, payload: '{}', created_at: "2020-01-03 00:00:00", read_at: "2020-01-04 00:00:00"}
+			- {user_id: 3, type: removed, payload: '{}', created_at: "2020-01-05 00:00:00"}
+			- {user_id: 4, type: removed, payload: '{}', created_at: "2020-01-01 00:00:00"}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	for userID, want := range map[int64]int64{3: 2, 4: 1, 5: 0} {
+		count, err := store.Notifications().CountUnread(userID)
+		require.NoError(t, err)
+		assert.Equal(t, want, count, "user %d", userID)
+	}
+}
@@ -0,0 +1,66 @@
+package database
+
+import "time"
+
+const (
+	// ParticipantEventTypeGradeSaved is the type of events emitted when a grade is saved for a result.
+	ParticipantEventTypeGradeSaved = "grade_saved"
+	// ParticipantEventTypeResultChanged is the type of events emitted when a result is modified by the results propagation.
+	ParticipantEventTypeResultChanged = "result_changed"
+	// ParticipantEventTypeItemUnlocked is the type of events emitted when an item is unlocked for a participant.
+	ParticipantEventTypeItemUnlocked = "item_unlocked"
+)
+
+// ParticipantEventStore implements database operations on `participant_events`
+// (recent changes of results and item unlocks).
+type ParticipantEventStore struct {
+	*DataStore
+}
+
+// InsertGradeSaved records the saving of a grade for the given result.
+func (s *ParticipantEventStore) InsertGradeSaved(participantID, attemptID, itemID int64) error {
+	return s.InsertMap(map[string]interface{}{
+		"type":           ParticipantEventTypeGradeSaved,
+		"participant_id": participantID,
+		"attempt_id":     attemptID,
+		"item_id":        itemID,
+	})
+}
+
+// VisibleBy returns a composable query for getting events the given user can see, i.e. events
+//  1. of the user or of the user's teams on items visible to the participant, or
+//  2. of participants the user can watch (as a manager with `can_watch_members`)
+//     on items for which the user has `can_watch` >= 'result'.
+func (s *ParticipantEventStore) VisibleBy(user *User) *DB {
+	participantCanViewItemSubQuery := s.Permissions().
+		Joins(`
+			JOIN groups_ancestors_active AS ancestors
+				ON ancestors.ancestor_group_id = permissions.group_id AND ancestors.child_group_id = participant_events.participant_id`).
+		WherePermissionIsAtLeast("view", "info").
+		Where("permissions.item_id = participant_events.item_id").
+		Select("1").Limit(1).SubQuery()
+	userTeamsSubQuery := s.ActiveGroupGroups().WhereUserIsMember(user).
+		Where("groups_groups_active.is_team_membership").
+		Select("groups_groups_active.parent_group_id").SubQuery()
+
+	userCanWatchParticipantSubQuery := s.ActiveGroupAncestors().ManagedByUser(user).
+		Where("groups_ancestors_active.child_group_id = participant_events.participant_id").
+		Where("can_watch_members").
+		Select("1").Limit(1).SubQuery()
+	userCanWatchResultsOnItemSubQuery := s.Permissions().MatchingUserAncestors(user).
+		WherePermissionIsAtLeast("watch", "result").
+		Where("permissions.item_id = participant_events.item_id").
+		Select("1").Limit(1).SubQuery()
+
+	return s.Where(`
+			((participant_events.participant_id = ? OR participant_events.participant_id IN ?) AND ?) OR
+			(? AND ?)`,
+		user.GroupID, userTeamsSubQuery, participantCanViewItemSubQuery,
+		userCanWatchParticipantSubQuery, userCanWatchResultsOnItemSubQuery)
+}
+
+// DeleteOlderThan deletes the events created more than the given duration ago.
+func (s *ParticipantEventStore) DeleteOlderThan(age time.Duration) (deletedCount int64, err error) {
+	result := s.Where("created_at < NOW(3) - INTERVAL ? MICROSECOND", age.Microseconds()).Delete()
+	return result.RowsAffected(), result.Error()
+}
@@ -0,0 +1,54 @@
+//go:build !unit
+
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+func TestParticipantEventStore_InsertGradeSaved(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString()
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.ParticipantEvents().InsertGradeSaved(11, 2, 30))
+
+	var events []map[string]interface{}
+	require.NoError(t, store.ParticipantEvents().
+		Select("type, participant_id, attempt_id, item_id, ABS(TIMESTAMPDIFF(SECOND, created_at, NOW())) < 3 AS created_now").
+		ScanIntoSliceOfMaps(&events).Error())
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "grade_saved", "participant_id": int64(11), "attempt_id": int64(2), "item_id": int64(30), "created_now": int64(1)},
+	}, events)
+}
+
+func TestParticipantEventStore_DeleteOlderThan(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		participant_events:
+			- {id: 1, type: grade_saved, participant_id: 11, attempt_id: 1, item_id: 30, created_at: "2020-01-01 00:00:00"}
+			- {id: 2, type: item_unlocked, participant_id: 11, item_id: 30, created_at: "2020-01-01 00:00:00"}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.ParticipantEvents().InsertGradeSaved(11, 2, 30))
+
+	deletedCount, err := store.ParticipantEvents().DeleteOlderThan(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deletedCount)
+
+	var ids []int64
+	require.NoError(t, store.ParticipantEvents().Pluck("id", &ids).Error())
+	assert.Equal(t, []int64{3}, ids)
+}
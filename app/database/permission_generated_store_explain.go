@@ -0,0 +1,356 @@
+package database
+
+import (
+	"sort"
+)
+
+// PermissionExplanationRelation contains the propagation settings of an `items_items` relation.
+type PermissionExplanationRelation struct {
+	ContentViewPropagation     string
+	UpperViewLevelsPropagation string
+	GrantViewPropagation       bool
+	WatchPropagation           bool
+	EditPropagation            bool
+}
+
+func (relation *PermissionExplanationRelation) propagationFlag(kind string) bool {
+	switch kind {
+	case "grant_view":
+		return relation.GrantViewPropagation
+	case "watch":
+		return relation.WatchPropagation
+	default:
+		return relation.EditPropagation
+	}
+}
+
+// PermissionExplanationStep is a step of the chain of items explaining a generated permission.
+// The first step of a chain is the permission granted on an item (ParentItemID is nil),
+// the next steps are the permissions propagated from the previous items to their children.
+type PermissionExplanationStep struct {
+	ItemID int64
+	// The index of the generated value of the permission for the item
+	Value int
+	// Whether the granted value comes from is_owner (for the first step only)
+	IsOwner bool
+
+	ParentItemID *int64
+	// The index of the generated value of the permission for the parent item
+	ParentValue int
+	Relation    *PermissionExplanationRelation
+	// The name of the `items_items` column which made the propagated value lower than the value for the parent item
+	// (empty if the value has not been capped)
+	CappedBy string
+}
+
+// PermissionExplanation explains a generated permission of a group on an item.
+type PermissionExplanation struct {
+	// The index of the aggregated generated value of the permission (0 or 1 for is_owner)
+	Value int
+	// The chain of groups from the ancestor group having the permission to the group itself
+	// (empty if the permission has its lowest value)
+	GroupChain []int64
+	// The chain of items from the item on which the permission is granted to the item itself
+	// (empty if the permission has its lowest value)
+	ItemChain []PermissionExplanationStep
+}
+
+// Kinds of permissions explained by PermissionGeneratedStore.Explain.
+var explainedPermissionKinds = []string{"view", "grant_view", "watch", "edit", "is_owner"}
+
+type explainedPermissionValues struct {
+	CanViewValue      int
+	CanGrantViewValue int
+	CanWatchValue     int
+	CanEditValue      int
+	IsOwner           bool
+}
+
+func (values *explainedPermissionValues) value(kind string) int {
+	switch kind {
+	case "view":
+		return values.CanViewValue
+	case "grant_view":
+		return values.CanGrantViewValue
+	case "watch":
+		return values.CanWatchValue
+	case "edit":
+		return values.CanEditValue
+	default:
+		if values.IsOwner {
+			return 1
+		}
+		return 0
+	}
+}
+
+type explainedPermissionsKey struct {
+	GroupID int64
+	ItemID  int64
+}
+
+type explainedPermissionsEdge struct {
+	GroupID      int64
+	ParentItemID int64
+	ChildItemID  int64
+	PermissionExplanationRelation
+	Parent     explainedPermissionValues `gorm:"embedded;embedded_prefix:parent_"`
+	Propagated explainedPermissionValues `gorm:"embedded;embedded_prefix:propagated_"`
+}
+
+type explainedPermissionsData struct {
+	groupID     int64
+	itemID      int64
+	generated   map[explainedPermissionsKey]*explainedPermissionValues
+	granted     map[explainedPermissionsKey]*explainedPermissionValues
+	edges       map[explainedPermissionsKey][]*explainedPermissionsEdge
+	groupParent map[int64][]int64
+	maxValues   map[string]int
+}
+
+// Explain explains the permissions of the given group on the given item: for each kind of permission
+// ("view", "grant_view", "watch", "edit", "is_owner"), it finds the ancestor group (or the group itself)
+// having the highest generated value on the item and walks up the ancestor items (like computeAllAccess propagates
+// the permissions down) until the item on which the permission is granted.
+// The explanations rely on `permissions_generated`, so they reflect the last completed propagation.
+func (s *PermissionGeneratedStore) Explain(groupID, itemID int64) (explanations map[string]*PermissionExplanation, err error) {
+	defer recoverPanics(&err)
+
+	data := &explainedPermissionsData{
+		groupID:     groupID,
+		itemID:      itemID,
+		generated:   make(map[explainedPermissionsKey]*explainedPermissionValues),
+		granted:     make(map[explainedPermissionsKey]*explainedPermissionValues),
+		edges:       make(map[explainedPermissionsKey][]*explainedPermissionsEdge),
+		groupParent: make(map[int64][]int64),
+		maxValues: map[string]int{
+			"view":       s.PermissionsGranted().ViewIndexByName("solution"),
+			"grant_view": s.PermissionsGranted().GrantViewIndexByName("solution_with_grant"),
+			"watch":      s.PermissionsGranted().WatchIndexByName("answer_with_grant"),
+			"edit":       s.PermissionsGranted().EditIndexByName("all_with_grant"),
+			"is_owner":   1,
+		},
+	}
+
+	ancestorGroupsQuery := s.ActiveGroupAncestors().Where("child_group_id = ?", groupID).Select("ancestor_group_id").QueryExpr()
+	ancestorItemsQuery := s.Raw("SELECT ancestor_item_id FROM items_ancestors WHERE child_item_id = ? UNION SELECT ?",
+		itemID, itemID).QueryExpr()
+
+	var generatedRows []struct {
+		explainedPermissionsKey
+		explainedPermissionValues
+	}
+	mustNotBeError(s.Where("group_id IN (?) AND item_id IN (?)", ancestorGroupsQuery, ancestorItemsQuery).
+		Select(`
+			group_id, item_id, can_view_generated_value AS can_view_value, can_grant_view_generated_value AS can_grant_view_value,
+			can_watch_generated_value AS can_watch_value, can_edit_generated_value AS can_edit_value, is_owner_generated AS is_owner`).
+		Scan(&generatedRows).Error())
+	for index := range generatedRows {
+		data.generated[generatedRows[index].explainedPermissionsKey] = &generatedRows[index].explainedPermissionValues
+	}
+
+	var grantedRows []struct {
+		explainedPermissionsKey
+		explainedPermissionValues
+	}
+	mustNotBeError(s.PermissionsGranted().
+		Where("group_id IN (?) AND item_id IN (?)", ancestorGroupsQuery, ancestorItemsQuery).
+		Select(`
+			group_id, item_id, MAX(` + canViewGrantedValueSQL + `) AS can_view_value,
+			MAX(can_grant_view_value) AS can_grant_view_value, MAX(can_watch_value) AS can_watch_value,
+			MAX(can_edit_value) AS can_edit_value, MAX(is_owner) AS is_owner`).
+		Group("group_id, item_id").Scan(&grantedRows).Error())
+	for index := range grantedRows {
+		data.granted[grantedRows[index].explainedPermissionsKey] = &grantedRows[index].explainedPermissionValues
+	}
+
+	var edges []*explainedPermissionsEdge
+	mustNotBeError(s.ItemItems().
+		Joins("JOIN permissions_generated AS parent ON parent.item_id = items_items.parent_item_id").
+		Where("parent.group_id IN (?) AND items_items.child_item_id IN (?)", ancestorGroupsQuery, ancestorItemsQuery).
+		Select(`
+			parent.group_id, items_items.parent_item_id, items_items.child_item_id,
+			items_items.content_view_propagation, items_items.upper_view_levels_propagation,
+			items_items.grant_view_propagation, items_items.watch_propagation, items_items.edit_propagation,
+			parent.can_view_generated_value AS parent_can_view_value,
+			parent.can_grant_view_generated_value AS parent_can_grant_view_value,
+			parent.can_watch_generated_value AS parent_can_watch_value,
+			parent.can_edit_generated_value AS parent_can_edit_value,
+			` + canViewPropagatedToChildValueSQL + ` AS propagated_can_view_value,
+			` + canGrantViewPropagatedToChildValueSQL + ` AS propagated_can_grant_view_value,
+			` + canWatchPropagatedToChildValueSQL + ` AS propagated_can_watch_value,
+			` + canEditPropagatedToChildValueSQL + ` AS propagated_can_edit_value`).
+		Order("items_items.parent_item_id").
+		Scan(&edges).Error())
+	for _, edge := range edges {
+		key := explainedPermissionsKey{GroupID: edge.GroupID, ItemID: edge.ChildItemID}
+		data.edges[key] = append(data.edges[key], edge)
+	}
+
+	var groupRelations []struct {
+		ParentGroupID int64
+		ChildGroupID  int64
+	}
+	mustNotBeError(s.ActiveGroupGroups().
+		Where("parent_group_id IN (?) AND child_group_id IN (?)", ancestorGroupsQuery, ancestorGroupsQuery).
+		Select("parent_group_id, child_group_id").Order("parent_group_id").Scan(&groupRelations).Error())
+	for _, relation := range groupRelations {
+		data.groupParent[relation.ChildGroupID] = append(data.groupParent[relation.ChildGroupID], relation.ParentGroupID)
+	}
+
+	explanations = make(map[string]*PermissionExplanation, len(explainedPermissionKinds))
+	for _, kind := range explainedPermissionKinds {
+		explanations[kind] = data.explain(kind)
+	}
+	return explanations, nil
+}
+
+// explain explains the given kind of permission of the group on the item.
+func (data *explainedPermissionsData) explain(kind string) *PermissionExplanation {
+	lowestValue := 1
+	if kind == "is_owner" {
+		lowestValue = 0
+	}
+
+	groupIDs := make([]int64, 0, len(data.generated))
+	for key := range data.generated {
+		if key.ItemID == data.itemID {
+			groupIDs = append(groupIDs, key.GroupID)
+		}
+	}
+	sort.Slice(groupIDs, func(i, j int) bool { return groupIDs[i] < groupIDs[j] })
+
+	explanation := &PermissionExplanation{Value: lowestValue}
+	var sourceGroupID int64
+	for _, groupID := range groupIDs {
+		value := data.generated[explainedPermissionsKey{GroupID: groupID, ItemID: data.itemID}].value(kind)
+		if value > explanation.Value {
+			explanation.Value = value
+			sourceGroupID = groupID
+		}
+	}
+	if explanation.Value == lowestValue {
+		return explanation
+	}
+
+	explanation.GroupChain = data.groupChain(sourceGroupID)
+	explanation.ItemChain = data.itemChain(kind, sourceGroupID)
+	return explanation
+}
+
+// groupChain returns the chain of groups from the given ancestor group to the group.
+func (data *explainedPermissionsData) groupChain(ancestorGroupID int64) []int64 {
+	previous := map[int64]int64{data.groupID: data.groupID}
+	queue := []int64{data.groupID}
+	for len(queue) > 0 && queue[0] != ancestorGroupID {
+		current := queue[0]
+		queue = queue[1:]
+		for _, parentGroupID := range data.groupParent[current] {
+			if _, visited := previous[parentGroupID]; !visited {
+				previous[parentGroupID] = current
+				queue = append(queue, parentGroupID)
+			}
+		}
+	}
+
+	chain := []int64{ancestorGroupID}
+	for current := ancestorGroupID; current != data.groupID; {
+		next, ok := previous[current]
+		if !ok {
+			break
+		}
+		chain = append(chain, next)
+		current = next
+	}
+	return chain
+}
+
+// itemChain walks up the ancestor items of the item, following the granted or propagated values
+// equal to the generated values, and returns the chain from the item on which the permission is granted to the item.
+func (data *explainedPermissionsData) itemChain(kind string, groupID int64) []PermissionExplanationStep {
+	var reversedChain []PermissionExplanationStep
+	currentItemID := data.itemID
+	visited := make(map[int64]bool)
+	for !visited[currentItemID] {
+		visited[currentItemID] = true
+		key := explainedPermissionsKey{GroupID: groupID, ItemID: currentItemID}
+		generatedValue := data.generated[key].value(kind)
+
+		if granted := data.granted[key]; granted != nil {
+			grantedValue := granted.value(kind)
+			if granted.IsOwner {
+				grantedValue = data.maxValues[kind]
+			}
+			if grantedValue >= generatedValue {
+				reversedChain = append(reversedChain, PermissionExplanationStep{
+					ItemID: currentItemID, Value: generatedValue, IsOwner: granted.IsOwner,
+				})
+				break
+			}
+		}
+
+		edge := data.findPropagatingEdge(kind, key, generatedValue)
+		if edge == nil {
+			break // permissions_generated is not up-to-date
+		}
+		parentItemID := edge.ParentItemID
+		relation := edge.PermissionExplanationRelation
+		reversedChain = append(reversedChain, PermissionExplanationStep{
+			ItemID:       currentItemID,
+			Value:        generatedValue,
+			ParentItemID: &parentItemID,
+			ParentValue:  edge.Parent.value(kind),
+			Relation:     &relation,
+			CappedBy:     propagationSettingCappingPermission(kind, edge.Parent.value(kind), generatedValue, &relation),
+		})
+		currentItemID = parentItemID
+	}
+
+	chain := make([]PermissionExplanationStep, 0, len(reversedChain))
+	for index := len(reversedChain) - 1; index >= 0; index-- {
+		chain = append(chain, reversedChain[index])
+	}
+	return chain
+}
+
+func (data *explainedPermissionsData) findPropagatingEdge(
+	kind string, key explainedPermissionsKey, generatedValue int,
+) *explainedPermissionsEdge {
+	if kind == "is_owner" {
+		return nil // is_owner is not propagated
+	}
+	for _, edge := range data.edges[key] {
+		if edge.Propagated.value(kind) == generatedValue && data.generated[explainedPermissionsKey{
+			GroupID: key.GroupID, ItemID: edge.ParentItemID,
+		}] != nil {
+			return edge
+		}
+	}
+	return nil
+}
+
+// propagationSettingCappingPermission returns the name of the `items_items` column which made
+// the propagated value of the permission lower than the value for the parent item (see canViewPropagatedToChildValueSQL).
+func propagationSettingCappingPermission(kind string, parentValue, value int, relation *PermissionExplanationRelation) string {
+	if value >= parentValue {
+		return ""
+	}
+	if kind != "view" {
+		if relation.propagationFlag(kind) {
+			return "" // the '*_with_grant' levels are never propagated
+		}
+		return kind + "_propagation"
+	}
+
+	const (
+		viewInfoValue    = 2
+		viewContentValue = 3
+	)
+	if parentValue <= viewInfoValue {
+		return "" // 'info' is never propagated
+	}
+	if parentValue == viewContentValue || relation.UpperViewLevelsPropagation == "use_content_view_propagation" {
+		return "content_view_propagation"
+	}
+	return "upper_view_levels_propagation"
+}
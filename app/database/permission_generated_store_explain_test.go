@@ -0,0 +1,143 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainedPermissionsData_explain(t *testing.T) {
+	// Group 3 is a child of group 2 which is a child of group 1.
+	// Item 10 is the parent of item 11 which is the parent of item 12.
+	// Group 1 is granted can_view='solution' & can_watch='answer_with_grant' on item 10,
+	// group 2 is granted is_owner on item 11 and can_grant_view='content' on item 12.
+	relation1011 := PermissionExplanationRelation{
+		ContentViewPropagation: "as_content", UpperViewLevelsPropagation: "as_is",
+		GrantViewPropagation: true, WatchPropagation: true, EditPropagation: true,
+	}
+	relation1112 := PermissionExplanationRelation{
+		ContentViewPropagation: "as_content", UpperViewLevelsPropagation: "as_content_with_descendants",
+		GrantViewPropagation: true, WatchPropagation: false, EditPropagation: true,
+	}
+	data := &explainedPermissionsData{
+		groupID: 3,
+		itemID:  12,
+		generated: map[explainedPermissionsKey]*explainedPermissionValues{
+			{GroupID: 1, ItemID: 10}: {CanViewValue: 5, CanGrantViewValue: 1, CanWatchValue: 4, CanEditValue: 1},
+			{GroupID: 1, ItemID: 11}: {CanViewValue: 5, CanGrantViewValue: 1, CanWatchValue: 3, CanEditValue: 1},
+			{GroupID: 1, ItemID: 12}: {CanViewValue: 4, CanGrantViewValue: 1, CanWatchValue: 1, CanEditValue: 1},
+			{GroupID: 2, ItemID: 11}: {CanViewValue: 5, CanGrantViewValue: 6, CanWatchValue: 4, CanEditValue: 4, IsOwner: true},
+			{GroupID: 2, ItemID: 12}: {CanViewValue: 4, CanGrantViewValue: 5, CanWatchValue: 1, CanEditValue: 3},
+			{GroupID: 3, ItemID: 12}: {CanViewValue: 1, CanGrantViewValue: 1, CanWatchValue: 1, CanEditValue: 1},
+		},
+		granted: map[explainedPermissionsKey]*explainedPermissionValues{
+			{GroupID: 1, ItemID: 10}: {CanViewValue: 5, CanGrantViewValue: 1, CanWatchValue: 4, CanEditValue: 1},
+			{GroupID: 2, ItemID: 11}: {CanViewValue: 1, CanGrantViewValue: 1, CanWatchValue: 1, CanEditValue: 1, IsOwner: true},
+			{GroupID: 2, ItemID: 12}: {CanViewValue: 1, CanGrantViewValue: 3, CanWatchValue: 1, CanEditValue: 1},
+		},
+		edges: map[explainedPermissionsKey][]*explainedPermissionsEdge{
+			{GroupID: 1, ItemID: 11}: {{
+				GroupID: 1, ParentItemID: 10, ChildItemID: 11, PermissionExplanationRelation: relation1011,
+				Parent:     explainedPermissionValues{CanViewValue: 5, CanGrantViewValue: 1, CanWatchValue: 4, CanEditValue: 1},
+				Propagated: explainedPermissionValues{CanViewValue: 5, CanGrantViewValue: 1, CanWatchValue: 3, CanEditValue: 1},
+			}},
+			{GroupID: 1, ItemID: 12}: {{
+				GroupID: 1, ParentItemID: 11, ChildItemID: 12, PermissionExplanationRelation: relation1112,
+				Parent:     explainedPermissionValues{CanViewValue: 5, CanGrantViewValue: 1, CanWatchValue: 3, CanEditValue: 1},
+				Propagated: explainedPermissionValues{CanViewValue: 4, CanGrantViewValue: 1, CanWatchValue: 1, CanEditValue: 1},
+			}},
+			{GroupID: 2, ItemID: 12}: {{
+				GroupID: 2, ParentItemID: 11, ChildItemID: 12, PermissionExplanationRelation: relation1112,
+				Parent:     explainedPermissionValues{CanViewValue: 5, CanGrantViewValue: 6, CanWatchValue: 4, CanEditValue: 4},
+				Propagated: explainedPermissionValues{CanViewValue: 4, CanGrantViewValue: 5, CanWatchValue: 1, CanEditValue: 3},
+			}},
+		},
+		groupParent: map[int64][]int64{3: {2}, 2: {1}},
+		maxValues:   map[string]int{"view": 5, "grant_view": 6, "watch": 4, "edit": 4, "is_owner": 1},
+	}
+	item10, item11 := int64(10), int64(11)
+
+	tests := []struct {
+		kind string
+		want *PermissionExplanation
+	}{
+		{
+			kind: "view",
+			want: &PermissionExplanation{
+				Value:      4,
+				GroupChain: []int64{1, 2, 3},
+				ItemChain: []PermissionExplanationStep{
+					{ItemID: 10, Value: 5},
+					{ItemID: 11, Value: 5, ParentItemID: &item10, ParentValue: 5, Relation: &relation1011},
+					{
+						ItemID: 12, Value: 4, ParentItemID: &item11, ParentValue: 5, Relation: &relation1112,
+						CappedBy: "upper_view_levels_propagation",
+					},
+				},
+			},
+		},
+		{
+			kind: "grant_view",
+			want: &PermissionExplanation{
+				Value:      5,
+				GroupChain: []int64{2, 3},
+				ItemChain: []PermissionExplanationStep{
+					{ItemID: 11, Value: 6, IsOwner: true},
+					{ItemID: 12, Value: 5, ParentItemID: &item11, ParentValue: 6, Relation: &relation1112},
+				},
+			},
+		},
+		{
+			kind: "watch",
+			want: &PermissionExplanation{Value: 1},
+		},
+		{
+			kind: "edit",
+			want: &PermissionExplanation{
+				Value:      3,
+				GroupChain: []int64{2, 3},
+				ItemChain: []PermissionExplanationStep{
+					{ItemID: 11, Value: 4, IsOwner: true},
+					{ItemID: 12, Value: 3, ParentItemID: &item11, ParentValue: 4, Relation: &relation1112},
+				},
+			},
+		},
+		{
+			kind: "is_owner",
+			want: &PermissionExplanation{Value: 0},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.kind, func(t *testing.T) {
+			assert.Equal(t, tt.want, data.explain(tt.kind))
+		})
+	}
+}
+
+func TestPropagationSettingCappingPermission(t *testing.T) {
+	relation := &PermissionExplanationRelation{
+		ContentViewPropagation: "as_info", UpperViewLevelsPropagation: "use_content_view_propagation",
+		GrantViewPropagation: false, WatchPropagation: true, EditPropagation: true,
+	}
+	tests := []struct {
+		name        string
+		kind        string
+		parentValue int
+		value       int
+		want        string
+	}{
+		{name: "not capped", kind: "view", parentValue: 5, value: 5, want: ""},
+		{name: "info is never propagated", kind: "view", parentValue: 2, value: 1, want: ""},
+		{name: "content", kind: "view", parentValue: 3, value: 2, want: "content_view_propagation"},
+		{name: "upper levels using content propagation", kind: "view", parentValue: 5, value: 2, want: "content_view_propagation"},
+		{name: "grant_view disabled", kind: "grant_view", parentValue: 5, value: 1, want: "grant_view_propagation"},
+		{name: "watch with grant", kind: "watch", parentValue: 4, value: 3, want: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, propagationSettingCappingPermission(tt.kind, tt.parentValue, tt.value, relation))
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package database
+
+import "github.com/jinzhu/gorm"
+
+// UpdateCanViewWindowsStates updates `can_view_window_is_open` of rows of `permissions_granted`
+// whose `can_view_from`/`can_view_until` window has opened or closed since the last call.
+// The triggers mark the group-item pairs of the updated rows for the permissions propagation.
+// If at least one row has been updated, the permissions propagation and the results propagation
+// are scheduled (as opening a window may unlock items).
+func (s *PermissionGrantedStore) UpdateCanViewWindowsStates() (updatedCount int64, err error) {
+	s.mustBeInTransaction()
+	defer recoverPanics(&err)
+
+	result := s.Where("can_view_window_is_open != (NOW() BETWEEN can_view_from AND can_view_until)").
+		UpdateColumn("can_view_window_is_open", gorm.Expr("NOT can_view_window_is_open"))
+	mustNotBeError(result.Error())
+
+	updatedCount = result.RowsAffected()
+	if updatedCount > 0 {
+		s.SchedulePermissionsPropagation()
+		s.ScheduleResultsPropagation()
+	}
+	return updatedCount, nil
+}
@@ -7,6 +7,34 @@ import (
 	"github.com/France-ioi/AlgoreaBackend/v2/golang"
 )
 
+// canViewGrantedValueSQL is the SQL expression computing the value of `can_view` granted by a row of `permissions_granted`
+// taking into account its `can_view_from`/`can_view_until` window.
+const canViewGrantedValueSQL = `
+	IF(NOW() BETWEEN permissions_granted.can_view_from AND permissions_granted.can_view_until,
+		permissions_granted.can_view_value, 1 /* none */)`
+
+// SQL expressions computing the values of permissions propagated to a child item from its parent item
+// through an `items_items` relation, `parent` being the row of `permissions_generated` for the parent item
+// (NULL if the group has no permissions on the parent item).
+// Permissions granted with is_owner are never propagated as such.
+const (
+	canViewPropagatedToChildValueSQL = `
+		CASE
+		WHEN parent.can_view_generated IS NULL OR parent.can_view_generated IN ('none', 'info') THEN 1 /* none */
+		WHEN parent.can_view_generated = 'content' OR items_items.upper_view_levels_propagation = 'use_content_view_propagation' THEN
+			CASE items_items.content_view_propagation
+			WHEN 'as_info' THEN 2 /* info */
+			WHEN 'as_content' THEN 3 /* content */
+			ELSE 1 /* none */
+			END
+		WHEN items_items.upper_view_levels_propagation = 'as_content_with_descendants' THEN 4 /* content_with_descendants */
+		ELSE parent.can_view_generated_value
+		END`
+	canGrantViewPropagatedToChildValueSQL = `IF(items_items.grant_view_propagation, LEAST(parent.can_grant_view_generated_value, 5 /* solution */), 1)`
+	canWatchPropagatedToChildValueSQL     = `IF(items_items.watch_propagation, LEAST(parent.can_watch_generated_value, 3 /* answer */), 1)`
+	canEditPropagatedToChildValueSQL      = `IF(items_items.edit_propagation, LEAST(parent.can_edit_generated_value, 3 /* all */), 1)`
+)
+
 // computeAllAccess recomputes fields of permissions_generated.
 //
 // It starts from group-item pairs marked with propagate_to = 'self' in `permissions_propagate`.
@@ -79,31 +107,20 @@ func (s *PermissionGrantedStore) computeAllAccess() {
 			permissions_propagate_processing.group_id,
 			permissions_propagate_processing.item_id,
 			IF(MAX(permissions_granted.is_owner), 'solution', GREATEST(
-				IFNULL(MAX(permissions_granted.can_view_value), 1),
-				IFNULL(MAX(
-					CASE
-					WHEN parent.can_view_generated IS NULL OR parent.can_view_generated IN ('none', 'info') THEN 1 /* none */
-					WHEN parent.can_view_generated = 'content' OR items_items.upper_view_levels_propagation = 'use_content_view_propagation' THEN
-						CASE items_items.content_view_propagation
-						WHEN 'as_info' THEN 2 /* info */
-						WHEN 'as_content' THEN 3 /* content */
-						ELSE 1 /* none */
-						END
-					WHEN items_items.upper_view_levels_propagation = 'as_content_with_descendants' THEN 4 /* content_with_descendants */
-					ELSE parent.can_view_generated_value
-					END), 1)
+				IFNULL(MAX(` + canViewGrantedValueSQL + `), 1),
+				IFNULL(MAX(` + canViewPropagatedToChildValueSQL + `), 1)
 			)) AS can_view_generated,
 			IF(MAX(permissions_granted.is_owner), 'solution_with_grant', GREATEST(
 				IFNULL(MAX(permissions_granted.can_grant_view_value), 1),
-				IFNULL(MAX(IF(items_items.grant_view_propagation, LEAST(parent.can_grant_view_generated_value, 5 /* solution */), 1)), 1)
+				IFNULL(MAX(` + canGrantViewPropagatedToChildValueSQL + `), 1)
 			)) AS can_grant_view_generated,
 			IF(MAX(permissions_granted.is_owner), 'answer_with_grant', GREATEST(
 				IFNULL(MAX(permissions_granted.can_watch_value), 1),
-				IFNULL(MAX(IF(items_items.watch_propagation, LEAST(parent.can_watch_generated_value, 3 /* answer */), 1)), 1)
+				IFNULL(MAX(` + canWatchPropagatedToChildValueSQL + `), 1)
 			)) AS can_watch_generated,
 			IF(MAX(permissions_granted.is_owner), 'all_with_grant', GREATEST(
 				IFNULL(MAX(permissions_granted.can_edit_value), 1),
-				IFNULL(MAX(IF(items_items.edit_propagation, LEAST(parent.can_edit_generated_value, 3 /* all */), 1)), 1)
+				IFNULL(MAX(` + canEditPropagatedToChildValueSQL + `), 1)
 			)) AS can_edit_generated,
 			IFNULL(MAX(permissions_granted.is_owner), 0) AS is_owner_generated
 		FROM permissions_propagate_processing
@@ -132,7 +149,7 @@ func (s *PermissionGrantedStore) computeAllAccess() {
 	// ------------------------------------------------------------------------------------
 	hasChanges := true
 	for hasChanges {
-		CallBeforePropagationStepHook(PropagationStepAccessMain)
+		finishStep := startPropagationStep(s.GetContext(), PropagationStepAccessMain)
 
 		mustNotBeError(s.EnsureTransaction(func(store *DataStore) error {
 			initTransactionTime := time.Now()
@@ -156,5 +173,6 @@ func (s *PermissionGrantedStore) computeAllAccess() {
 
 			return nil
 		}))
+		finishStep()
 	}
 }
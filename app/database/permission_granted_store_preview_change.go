@@ -0,0 +1,150 @@
+package database
+
+// PermissionsGeneratedValues contains the generated permissions of a group on an item.
+type PermissionsGeneratedValues struct {
+	CanViewGeneratedValue      int
+	CanGrantViewGeneratedValue int
+	CanWatchGeneratedValue     int
+	CanEditGeneratedValue      int
+	IsOwnerGenerated           bool
+}
+
+// PermissionsGeneratedChange is a change of the generated permissions of a group on an item.
+type PermissionsGeneratedChange struct {
+	ItemID int64
+	Before PermissionsGeneratedValues `gorm:"embedded;embedded_prefix:before_"`
+	After  PermissionsGeneratedValues `gorm:"embedded;embedded_prefix:after_"`
+}
+
+// PermissionsChangePreview summarizes the changes of `permissions_generated` caused by a change of permissions.
+type PermissionsChangePreview struct {
+	// The number of items on which the generated permissions of the group change
+	ItemsCount int
+	// The number of groups (the group itself and its descendants, including users)
+	// whose aggregated generated permissions on at least one item change
+	GroupsCount int
+	// The first changes ordered by item id
+	SampleChanges []PermissionsGeneratedChange
+	// The first affected groups ordered by id
+	SampleGroupIDs []int64
+}
+
+// PreviewChange calls the given function changing permissions of the group on the item,
+// propagates permissions immediately and returns a summary of the resulting changes of `permissions_generated`
+// for the group on the item and its descendants.
+// At most samplesLimit changed items and affected groups are returned as samples.
+//
+// The method should be called inside a transaction marked with DataStore.SetPropagationsModeToSync()
+// (so that only the changes made by the transaction are propagated), and the transaction should be
+// rolled back afterwards if the changes are not to be applied.
+func (s *PermissionGrantedStore) PreviewChange(groupID, itemID int64, samplesLimit int, changeFunc func()) (
+	preview *PermissionsChangePreview, err error,
+) {
+	s.mustBeInTransaction()
+	defer recoverPanics(&err)
+
+	const queryDropTemporaryTables = `
+		DROP TEMPORARY TABLE IF EXISTS permissions_generated_before_change, permissions_generated_changes,
+			permissions_generated_of_other_ancestors`
+	mustNotBeError(s.Exec(queryDropTemporaryTables).Error())
+	defer s.Exec(queryDropTemporaryTables)
+
+	mustNotBeError(s.Exec(`
+		CREATE TEMPORARY TABLE permissions_generated_before_change (PRIMARY KEY (item_id))
+		SELECT item_id, can_view_generated_value, can_grant_view_generated_value, can_watch_generated_value,
+		       can_edit_generated_value, is_owner_generated
+		FROM permissions_generated
+		WHERE group_id = ? AND item_id IN (SELECT ? UNION SELECT child_item_id FROM items_ancestors WHERE ancestor_item_id = ?)`,
+		groupID, itemID, itemID).Error())
+
+	changeFunc()
+	s.computeAllAccess()
+
+	mustNotBeError(s.Exec(`
+		CREATE TEMPORARY TABLE permissions_generated_changes (PRIMARY KEY (item_id))
+		SELECT * FROM (
+			SELECT
+				items.item_id,
+				IFNULL(before_change.can_view_generated_value, 1) AS before_can_view_generated_value,
+				IFNULL(before_change.can_grant_view_generated_value, 1) AS before_can_grant_view_generated_value,
+				IFNULL(before_change.can_watch_generated_value, 1) AS before_can_watch_generated_value,
+				IFNULL(before_change.can_edit_generated_value, 1) AS before_can_edit_generated_value,
+				IFNULL(before_change.is_owner_generated, 0) AS before_is_owner_generated,
+				IFNULL(after_change.can_view_generated_value, 1) AS after_can_view_generated_value,
+				IFNULL(after_change.can_grant_view_generated_value, 1) AS after_can_grant_view_generated_value,
+				IFNULL(after_change.can_watch_generated_value, 1) AS after_can_watch_generated_value,
+				IFNULL(after_change.can_edit_generated_value, 1) AS after_can_edit_generated_value,
+				IFNULL(after_change.is_owner_generated, 0) AS after_is_owner_generated
+			FROM (SELECT ? AS item_id UNION SELECT child_item_id FROM items_ancestors WHERE ancestor_item_id = ?) AS items
+			LEFT JOIN permissions_generated_before_change AS before_change ON before_change.item_id = items.item_id
+			LEFT JOIN permissions_generated AS after_change
+				ON after_change.group_id = ? AND after_change.item_id = items.item_id
+		) AS changes
+		WHERE before_can_view_generated_value != after_can_view_generated_value OR
+		      before_can_grant_view_generated_value != after_can_grant_view_generated_value OR
+		      before_can_watch_generated_value != after_can_watch_generated_value OR
+		      before_can_edit_generated_value != after_can_edit_generated_value OR
+		      before_is_owner_generated != after_is_owner_generated`,
+		itemID, itemID, groupID).Error())
+
+	preview = &PermissionsChangePreview{}
+	mustNotBeError(s.Table("permissions_generated_changes").PluckFirst("COUNT(*)", &preview.ItemsCount).Error())
+	mustNotBeError(s.Table("permissions_generated_changes").Order("item_id").Limit(samplesLimit).
+		Scan(&preview.SampleChanges).Error())
+
+	// The aggregated permissions of the other ancestors of the descendant groups on the changed items.
+	mustNotBeError(s.Exec(`
+		CREATE TEMPORARY TABLE permissions_generated_of_other_ancestors (PRIMARY KEY (group_id, item_id))
+		SELECT
+			descendants.child_group_id AS group_id, others.item_id,
+			MAX(others.can_view_generated_value) AS can_view_generated_value,
+			MAX(others.can_grant_view_generated_value) AS can_grant_view_generated_value,
+			MAX(others.can_watch_generated_value) AS can_watch_generated_value,
+			MAX(others.can_edit_generated_value) AS can_edit_generated_value,
+			MAX(others.is_owner_generated) AS is_owner_generated
+		FROM groups_ancestors_active AS descendants
+		JOIN groups_ancestors_active AS other_ancestors
+			ON other_ancestors.child_group_id = descendants.child_group_id AND
+			   other_ancestors.ancestor_group_id != descendants.ancestor_group_id
+		JOIN permissions_generated AS others
+			ON others.group_id = other_ancestors.ancestor_group_id AND
+			   others.item_id IN (SELECT item_id FROM permissions_generated_changes)
+		WHERE descendants.ancestor_group_id = ?
+		GROUP BY descendants.child_group_id, others.item_id`, groupID).Error())
+
+	// The aggregated permissions of a descendant group on an item change only if the permissions of other ancestors
+	// of the descendant group are lower than the greatest of the values before and after the change.
+	// Every changed item should be checked for every descendant group.
+	affectedGroupsQuery := s.Raw(`
+		SELECT DISTINCT descendants.child_group_id AS group_id
+		FROM groups_ancestors_active AS descendants
+		CROSS JOIN permissions_generated_changes AS changes
+		LEFT JOIN permissions_generated_of_other_ancestors AS others
+			ON others.group_id = descendants.child_group_id AND others.item_id = changes.item_id
+		WHERE descendants.ancestor_group_id = ? AND (
+			(changes.before_can_view_generated_value != changes.after_can_view_generated_value AND
+			 IFNULL(others.can_view_generated_value, 1) <
+				GREATEST(changes.before_can_view_generated_value, changes.after_can_view_generated_value)) OR
+			(changes.before_can_grant_view_generated_value != changes.after_can_grant_view_generated_value AND
+			 IFNULL(others.can_grant_view_generated_value, 1) <
+				GREATEST(changes.before_can_grant_view_generated_value, changes.after_can_grant_view_generated_value)) OR
+			(changes.before_can_watch_generated_value != changes.after_can_watch_generated_value AND
+			 IFNULL(others.can_watch_generated_value, 1) <
+				GREATEST(changes.before_can_watch_generated_value, changes.after_can_watch_generated_value)) OR
+			(changes.before_can_edit_generated_value != changes.after_can_edit_generated_value AND
+			 IFNULL(others.can_edit_generated_value, 1) <
+				GREATEST(changes.before_can_edit_generated_value, changes.after_can_edit_generated_value)) OR
+			(changes.before_is_owner_generated != changes.after_is_owner_generated AND
+			 NOT IFNULL(others.is_owner_generated, 0)))`, groupID)
+
+	var affectedGroupIDs []int64
+	mustNotBeError(s.Raw("SELECT group_id FROM ? AS affected_groups ORDER BY group_id", affectedGroupsQuery.SubQuery()).
+		Pluck("group_id", &affectedGroupIDs).Error())
+	preview.GroupsCount = len(affectedGroupIDs)
+	if len(affectedGroupIDs) > samplesLimit {
+		affectedGroupIDs = affectedGroupIDs[:samplesLimit]
+	}
+	preview.SampleGroupIDs = affectedGroupIDs
+
+	return preview, nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// PropagationTypePermissions is the type of the permissions propagation.
+	PropagationTypePermissions = "permissions"
+	// PropagationTypeResults is the type of the results propagation.
+	PropagationTypeResults = "results"
+)
+
+// PropagationQueueStore implements database operations on `propagation_queue`.
+type PropagationQueueStore struct {
+	*DataStore
+}
+
+// PropagationQueueEntry represents a row of `propagation_queue`.
+type PropagationQueueEntry struct {
+	Type           string
+	RequestsCount  int64
+	FailedAttempts int
+}
+
+// Enqueue adds requests for the given types of propagation into the queue.
+// Requests for a type of propagation which is already queued are coalesced into the existing row.
+// When called inside a transaction, the requests are committed (or rolled back) together with the transaction.
+func (s *PropagationQueueStore) Enqueue(types []string) error {
+	if len(types) == 0 {
+		return nil
+	}
+
+	valuesMarks := make([]string, 0, len(types))
+	values := make([]interface{}, 0, len(types))
+	for _, propagationType := range types {
+		if propagationType != PropagationTypePermissions && propagationType != PropagationTypeResults {
+			return fmt.Errorf("unknown propagation type: %q", propagationType)
+		}
+		valuesMarks = append(valuesMarks, "(?)")
+		values = append(values, propagationType)
+	}
+
+	return s.Exec(`
+		INSERT INTO propagation_queue (type) VALUES `+strings.Join(valuesMarks, ", ")+`
+		ON DUPLICATE KEY UPDATE requests_count = requests_count + 1, requested_at = NOW(3)`, values...).Error()
+}
+
+// GetDue returns the queued propagations which can be run now
+// (the permissions propagation goes first as it may mark results for propagation).
+func (s *PropagationQueueStore) GetDue() (entries []PropagationQueueEntry, err error) {
+	err = s.Select("type, requests_count, failed_attempts").
+		Where("next_attempt_at <= NOW(3)").
+		Order("type = 'results', type").
+		Scan(&entries).Error()
+	return entries, err
+}
+
+// MarkAsDone removes the given entry from the queue.
+// If new requests for the same type of propagation have been coalesced into the entry
+// since it had been fetched, the entry is kept (with these new requests only), so the propagation will be run again.
+func (s *PropagationQueueStore) MarkAsDone(entry *PropagationQueueEntry) error {
+	return s.EnsureTransaction(func(store *DataStore) error {
+		queueStore := store.PropagationQueue()
+		if err := queueStore.Where("type = ? AND requests_count <= ?", entry.Type, entry.RequestsCount).Delete().Error(); err != nil {
+			return err
+		}
+		return queueStore.Where("type = ?", entry.Type).UpdateColumns(map[string]interface{}{
+			"requests_count":  gorm.Expr("requests_count - ?", entry.RequestsCount),
+			"failed_attempts": 0,
+			"next_attempt_at": gorm.Expr("NOW(3)"),
+			"last_error":      nil,
+		}).Error()
+	})
+}
+
+// MarkAsFailed records a failed run of the propagation of the entry's type
+// and postpones the next run by the given delay.
+func (s *PropagationQueueStore) MarkAsFailed(entry *PropagationQueueEntry, runErr error, retryIn time.Duration) error {
+	return s.Where("type = ?", entry.Type).UpdateColumns(map[string]interface{}{
+		"failed_attempts": gorm.Expr("failed_attempts + 1"),
+		"next_attempt_at": gorm.Expr("NOW(3) + INTERVAL ? MICROSECOND", retryIn.Microseconds()),
+		"last_error":      runErr.Error(),
+	}).Error()
+}
@@ -0,0 +1,149 @@
+//go:build !unit
+
+package database_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+type propagationQueueRow struct {
+	Type           string
+	RequestsCount  int64
+	FailedAttempts int
+	LastError      *string
+}
+
+func getPropagationQueueRows(t *testing.T, store *database.DataStore) []propagationQueueRow {
+	t.Helper()
+
+	var rows []propagationQueueRow
+	require.NoError(t, store.PropagationQueue().
+		Select("type, requests_count, failed_attempts, last_error").Order("type").Scan(&rows).Error())
+	return rows
+}
+
+func TestPropagationQueueStore_Enqueue_CoalescesRequests(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString()
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.PropagationQueue().Enqueue([]string{"permissions", "results"}))
+	require.NoError(t, store.PropagationQueue().Enqueue([]string{"results"}))
+	require.NoError(t, store.InTransaction(func(store *database.DataStore) error {
+		return store.PropagationQueue().Enqueue([]string{"results"})
+	}))
+
+	assert.Equal(t, []propagationQueueRow{
+		{Type: "permissions", RequestsCount: 1},
+		{Type: "results", RequestsCount: 3},
+	}, getPropagationQueueRows(t, store))
+}
+
+func TestPropagationQueueStore_Enqueue_RejectsUnknownTypes(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString()
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	assert.EqualError(t, store.PropagationQueue().Enqueue([]string{"results", "items"}), `unknown propagation type: "items"`)
+	assert.Empty(t, getPropagationQueueRows(t, store))
+}
+
+func TestPropagationQueueStore_Enqueue_IsRolledBackWithTransaction(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString()
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	expectedErr := errors.New("some error")
+	assert.Equal(t, expectedErr, store.InTransaction(func(store *database.DataStore) error {
+		require.NoError(t, store.PropagationQueue().Enqueue([]string{"permissions"}))
+		return expectedErr
+	}))
+	assert.Empty(t, getPropagationQueueRows(t, store))
+}
+
+func TestPropagationQueueStore_GetDue(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		propagation_queue:
+			- {type: results, requests_count: 2, failed_attempts: 1, next_attempt_at: "2000-01-01 00:00:00"}
+			- {type: permissions, requests_count: 5, next_attempt_at: "2000-01-01 00:00:00"}`)
+	defer func() { _ = db.Close() }()
+
+	entries, err := database.NewDataStore(db).PropagationQueue().GetDue()
+	require.NoError(t, err)
+	assert.Equal(t, []database.PropagationQueueEntry{
+		{Type: "permissions", RequestsCount: 5},
+		{Type: "results", RequestsCount: 2, FailedAttempts: 1},
+	}, entries)
+}
+
+func TestPropagationQueueStore_GetDue_SkipsPostponedEntries(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		propagation_queue:
+			- {type: results, next_attempt_at: "2000-01-01 00:00:00"}
+			- {type: permissions, next_attempt_at: "9999-12-31 23:59:59"}`)
+	defer func() { _ = db.Close() }()
+
+	entries, err := database.NewDataStore(db).PropagationQueue().GetDue()
+	require.NoError(t, err)
+	assert.Equal(t, []database.PropagationQueueEntry{{Type: "results", RequestsCount: 1}}, entries)
+}
+
+func TestPropagationQueueStore_MarkAsDone(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		propagation_queue:
+			- {type: permissions, requests_count: 2}
+			- {type: results, requests_count: 5, failed_attempts: 3, last_error: "error"}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.PropagationQueue().MarkAsDone(&database.PropagationQueueEntry{Type: "permissions", RequestsCount: 2}))
+	// two requests have been coalesced into the entry after it was fetched
+	require.NoError(t, store.PropagationQueue().MarkAsDone(&database.PropagationQueueEntry{Type: "results", RequestsCount: 3}))
+
+	assert.Equal(t, []propagationQueueRow{
+		{Type: "results", RequestsCount: 2},
+	}, getPropagationQueueRows(t, store))
+}
+
+func TestPropagationQueueStore_MarkAsFailed(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		propagation_queue:
+			- {type: permissions, requests_count: 2, failed_attempts: 1}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.PropagationQueue().MarkAsFailed(
+		&database.PropagationQueueEntry{Type: "permissions", RequestsCount: 2, FailedAttempts: 1}, errors.New("deadlock"), time.Hour))
+
+	expectedError := "deadlock"
+	assert.Equal(t, []propagationQueueRow{
+		{Type: "permissions", RequestsCount: 2, FailedAttempts: 2, LastError: &expectedError},
+	}, getPropagationQueueRows(t, store))
+
+	entries, err := store.PropagationQueue().GetDue()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -1,8 +1,15 @@
 package database
 
 import (
+	"context"
 	"sync"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/metrics"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/tracing"
 	"github.com/France-ioi/AlgoreaBackend/v2/golang"
 )
 
@@ -73,3 +80,16 @@ func GetBeforePropagationStepHook() BeforePropagationStepHookFunc {
 func CallBeforePropagationStepHook(step PropagationStep) {
 	GetBeforePropagationStepHook()(step)
 }
+
+// startPropagationStep calls the hook that is called before each propagation step, starts a span of the step,
+// and returns a function to be called at the end of the step to end the span and to observe the duration
+// of the step (including the nested steps).
+func startPropagationStep(ctx context.Context, step PropagationStep) (finishStep func()) {
+	CallBeforePropagationStepHook(step)
+	startTime := time.Now()
+	_, span := tracing.StartSpan(ctx, "propagation: "+string(step), trace.WithAttributes(attribute.String("propagation.step", string(step))))
+	return func() {
+		span.End()
+		metrics.PropagationStepDuration.WithLabelValues(string(step)).Observe(time.Since(startTime).Seconds())
+	}
+}
@@ -0,0 +1,7 @@
+package database
+
+// RateLimitBucketStore implements database operations on `rate_limit_buckets`
+// (token buckets limiting the rate of requests).
+type RateLimitBucketStore struct {
+	*DataStore
+}
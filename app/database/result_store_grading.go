@@ -0,0 +1,156 @@
+package database
+
+import (
+	"math"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// UpdateWithGrading updates the result of the participant on the item within the attempt
+// with the score of the answer (the grading should be already saved into `gradings`),
+// records the 'grade_saved' participant event, enqueues webhook deliveries,
+// and marks the result as to be propagated.
+// The method returns true if the result has become validated.
+func (s *ResultStore) UpdateWithGrading(participantID, attemptID, itemID, answerID int64, score float64) (
+	becameValidated bool, err error,
+) {
+	s.mustBeInTransaction()
+	defer recoverPanics(&err)
+
+	validated := score == 100 // currently a validated task is only a task with a full score (score == 100)
+
+	// Build query to update results
+	columnsToUpdate := []string{
+		"tasks_tried",
+		"score_obtained_at",
+		"score_computed",
+	}
+	newScoreExpression := gorm.Expr(`
+			LEAST(GREATEST(
+				CASE score_edit_rule
+					WHEN 'set' THEN score_edit_value
+					WHEN 'diff' THEN ? + score_edit_value
+					ELSE ?
+				END, score_computed, 0), 100)`, score, score)
+	values := []interface{}{
+		answerID, // for join
+		1,        // tasks_tried
+		// for score_computed we compare patched scores
+		gorm.Expr(`
+			CASE
+			  -- New best score or no time saved yet
+				-- Note that when the score = 0, score_obtained_at is the time of the first submission
+				WHEN score_obtained_at IS NULL OR score_computed < ? THEN answers.created_at
+				-- We may get the result of an earlier submission after one with the same score
+				WHEN score_computed = ? THEN LEAST(score_obtained_at, answers.created_at)
+				-- New score if lower than the best score
+				ELSE score_obtained_at
+			END`, newScoreExpression, newScoreExpression), // score_obtained_at
+		newScoreExpression, // score_computed
+	}
+	if validated {
+		// Item was validated
+		becameValidated, err = s.Results().
+			ByID(participantID, attemptID, itemID).
+			Where("validated_at IS NULL").WithExclusiveWriteLock().HasRows()
+		mustNotBeError(err)
+		columnsToUpdate = append(columnsToUpdate, "validated_at")
+		values = append(values, gorm.Expr("LEAST(IFNULL(validated_at, answers.created_at), answers.created_at)"))
+	}
+
+	updateExpr := "SET " + strings.Join(columnsToUpdate, " = ?, ") + " = ?"
+	values = append(values, participantID, attemptID, itemID)
+	mustNotBeError(
+		s.Exec("UPDATE results JOIN answers ON answers.id = ? "+ // nolint:gosec
+			updateExpr+" WHERE results.participant_id = ? AND results.attempt_id = ? AND results.item_id = ?", values...).
+			Error()) // nolint:gosec
+	s.recordGradeSaved(participantID, attemptID, itemID, becameValidated)
+
+	return becameValidated, nil
+}
+
+// RecomputeWithGradings recomputes the result of the participant on the item within the attempt
+// from all the gradings of the participant's answers (so that the score can decrease when an answer is re-graded,
+// while a validated result stays validated since the earliest time of its validation),
+// records the 'grade_saved' participant event, enqueues webhook deliveries,
+// and marks the result as to be propagated.
+// The method returns true if the result has become validated.
+func (s *ResultStore) RecomputeWithGradings(participantID, attemptID, itemID int64) (becameValidated bool, err error) {
+	s.mustBeInTransaction()
+	defer recoverPanics(&err)
+
+	var result struct {
+		ScoreEditRule  *string
+		ScoreEditValue *float64
+		Validated      bool
+	}
+	mustNotBeError(s.Results().ByID(participantID, attemptID, itemID).WithExclusiveWriteLock().
+		Select("score_edit_rule, score_edit_value, validated_at IS NOT NULL AS validated").
+		Take(&result).Error())
+
+	var gradings []struct {
+		AnswerID int64
+		Score    float64
+	}
+	mustNotBeError(s.Gradings().
+		Joins("JOIN answers ON answers.id = gradings.answer_id").
+		Where("answers.participant_id = ? AND answers.attempt_id = ? AND answers.item_id = ?", participantID, attemptID, itemID).
+		Order("answers.created_at, answers.id").
+		Select("gradings.answer_id, gradings.score").
+		Scan(&gradings).Error())
+
+	bestScore := 0.0
+	var bestAnswerID, validatingAnswerID *int64
+	for index := range gradings {
+		score := patchScore(gradings[index].Score, result.ScoreEditRule, result.ScoreEditValue)
+		if bestAnswerID == nil || score > bestScore {
+			bestScore = score
+			bestAnswerID = &gradings[index].AnswerID
+		}
+		// currently a validated task is only a task with a full score (score == 100)
+		if validatingAnswerID == nil && gradings[index].Score == 100 {
+			validatingAnswerID = &gradings[index].AnswerID
+		}
+	}
+	becameValidated = !result.Validated && validatingAnswerID != nil
+
+	mustNotBeError(s.Exec(`
+		UPDATE results
+		LEFT JOIN answers AS best_answers ON best_answers.id = ?
+		LEFT JOIN answers AS validating_answers ON validating_answers.id = ?
+		SET tasks_tried = 1, score_computed = ?,
+			score_obtained_at = best_answers.created_at,
+			validated_at = IF(validating_answers.id IS NULL, validated_at,
+				LEAST(IFNULL(validated_at, validating_answers.created_at), validating_answers.created_at))
+		WHERE results.participant_id = ? AND results.attempt_id = ? AND results.item_id = ?`,
+		bestAnswerID, validatingAnswerID, bestScore, participantID, attemptID, itemID).Error())
+	s.recordGradeSaved(participantID, attemptID, itemID, becameValidated)
+
+	return becameValidated, nil
+}
+
+// patchScore applies the score edit rule of a result to a score.
+func patchScore(score float64, scoreEditRule *string, scoreEditValue *float64) float64 {
+	if scoreEditRule != nil && scoreEditValue != nil {
+		switch *scoreEditRule {
+		case "set":
+			score = *scoreEditValue
+		case "diff":
+			score += *scoreEditValue
+		}
+	}
+	return math.Min(math.Max(score, 0), 100)
+}
+
+func (s *ResultStore) recordGradeSaved(participantID, attemptID, itemID int64, becameValidated bool) {
+	mustNotBeError(s.ParticipantEvents().InsertGradeSaved(participantID, attemptID, itemID))
+	webhookEventTypes := []string{WebhookEventTypeGradeSaved}
+	if becameValidated {
+		webhookEventTypes = append(webhookEventTypes, WebhookEventTypeResultValidated)
+	}
+	for _, eventType := range webhookEventTypes {
+		mustNotBeError(s.WebhookDeliveries().EnqueueForResult(eventType, participantID, attemptID, itemID))
+	}
+	mustNotBeError(s.MarkAsToBePropagated(participantID, attemptID, itemID, false))
+}
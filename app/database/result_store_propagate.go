@@ -17,12 +17,15 @@ const (
 func (s *ResultStore) processResultsRecomputeForItemsAndPropagate() (err error) {
 	defer recoverPanics(&err)
 
-	CallBeforePropagationStepHook(PropagationStepResultsNamedLockAcquire)
+	finishLockAcquisitionStep := startPropagationStep(s.GetContext(), PropagationStepResultsNamedLockAcquire)
 
 	// Use a lock so that we don't execute the listener multiple times in parallel
 	mustNotBeError(s.WithNamedLock(resultsPropagationLockName, resultsPropagationLockWaitTimeout, func(s *DataStore) error {
-		CallBeforePropagationStepHook(PropagationStepResultsInsideNamedLockInsertIntoResultsPropagate)
+		finishLockAcquisitionStep()
+
+		finishStep := startPropagationStep(s.GetContext(), PropagationStepResultsInsideNamedLockInsertIntoResultsPropagate)
 		setResultsPropagationFromTableResultsRecomputeForItems(s)
+		finishStep()
 
 		_, err = s.Results().propagate(nil)
 		return err
@@ -88,14 +91,16 @@ func (s *ResultStore) propagate(collectUnlockedItemsForParticipant *int64) (
 	for {
 		// First we take a chunk of results marked as 'to_be_propagated' and mark them as 'propagating'.
 		// Then we create missing results for their parents and mark those parent results as 'to_be_recomputed'.
-		CallBeforePropagationStepHook(PropagationStepResultsInsideNamedLockMarkAndInsertResults)
+		finishStep := startPropagationStep(s.GetContext(), PropagationStepResultsInsideNamedLockMarkAndInsertResults)
 		markAsPropagatingSomeResultsMarkedAsToBePropagatedAndMarkTheirParentsAsToBeRecomputed(s.DataStore, resultsPropagationPropagationChunkSize)
+		finishStep()
 
 		// Now we unlock dependent items for results marked as 'propagating' and unmark them.
-		CallBeforePropagationStepHook(PropagationStepResultsInsideNamedLockItemUnlocking)
+		finishStep = startPropagationStep(s.GetContext(), PropagationStepResultsInsideNamedLockItemUnlocking)
 
 		itemsUnlockedCountAtStep, participantItemsUnlockedAtStep := unlockDependedItemsForResultsMarkedAsPropagatingAndUnmarkThem(
 			s.DataStore, collectUnlockedItemsForParticipant)
+		finishStep()
 		itemsUnlockedCount += itemsUnlockedCountAtStep
 		participantItemsUnlocked.Add(participantItemsUnlockedAtStep...)
 
@@ -114,10 +119,11 @@ func (s *ResultStore) propagate(collectUnlockedItemsForParticipant *int64) (
 
 	// If items have been unlocked, need to recompute access
 	if itemsUnlockedCount > 0 {
-		CallBeforePropagationStepHook(PropagationStepResultsPropagationScheduling)
+		finishStep := startPropagationStep(s.GetContext(), PropagationStepResultsPropagationScheduling)
 
 		// generate permissions_generated from permissions_granted
 		s.PermissionsGranted().computeAllAccess()
+		finishStep()
 		// we should compute attempts again as new permissions were set and
 		// triggers on permissions_generated likely marked some attempts as 'to_be_propagated'
 		participantItemsUnlocked2, err := s.Results().propagate(collectUnlockedItemsForParticipant)
@@ -245,8 +251,11 @@ func markAsPropagatingSomeResultsMarkedAsToBePropagatedAndMarkTheirParentsAsToBe
 func recomputeResultsMarkedAsToBeRecomputedAndMarkThemAsToBePropagated(s *DataStore, chunkSize int) {
 	hasChanges := true
 
+	notifyWebhooksOnValidation, err := s.Webhooks().SubscribedTo(WebhookEventTypeResultValidated).HasRows()
+	mustNotBeError(err)
+
 	for hasChanges {
-		CallBeforePropagationStepHook(PropagationStepResultsInsideNamedLockMain)
+		finishStep := startPropagationStep(s.GetContext(), PropagationStepResultsInsideNamedLockMain)
 
 		mustNotBeError(s.EnsureTransaction(func(s *DataStore) error {
 			initTransactionTime := time.Now()
@@ -390,8 +399,36 @@ func recomputeResultsMarkedAsToBeRecomputedAndMarkThemAsToBePropagated(s *DataSt
 						target_results.recomputing_state = 'recomputing'
 					WHERE results_propagate.state = 'recomputing'`
 
+			if notifyWebhooksOnValidation {
+				// We remember the non-validated results to notify webhooks about those becoming validated.
+				mustNotBeError(s.Exec("DROP TEMPORARY TABLE IF EXISTS results_to_notify_on_validation").Error())
+				mustNotBeError(s.Exec(`
+					CREATE TEMPORARY TABLE results_to_notify_on_validation (PRIMARY KEY (participant_id, attempt_id, item_id))
+					SELECT results.participant_id, results.attempt_id, results.item_id
+					FROM ` + resultsPropagateTableName + ` AS results_propagate
+					JOIN results USING(participant_id, attempt_id, item_id)
+					WHERE results_propagate.state = 'recomputing' AND results.validated_at IS NULL`).Error())
+			}
+
 			mustNotBeError(s.Exec(updateQuery).Error())
 
+			if notifyWebhooksOnValidation {
+				mustNotBeError(s.WebhookDeliveries().enqueueForParticipantItems(WebhookEventTypeResultValidated, `
+					SELECT results.participant_id, results.attempt_id, results.item_id
+					FROM results_to_notify_on_validation
+					JOIN results USING(participant_id, attempt_id, item_id)
+					WHERE results.validated_at IS NOT NULL`))
+				mustNotBeError(s.Exec("DROP TEMPORARY TABLE results_to_notify_on_validation").Error())
+			}
+
+			// We record events for all modified results so that they can be streamed to users.
+			mustNotBeError(s.Exec(`
+				INSERT INTO participant_events (type, participant_id, attempt_id, item_id)
+				SELECT 'result_changed', results_propagate.participant_id, results_propagate.attempt_id, results_propagate.item_id
+				FROM ` + resultsPropagateTableName + ` AS results_propagate
+				JOIN results USING(participant_id, attempt_id, item_id)
+				WHERE results_propagate.state = 'recomputing' AND results.recomputing_state = 'modified'`).Error())
+
 			// We mark all modified results marked as 'recomputing' as 'to_be_propagated'.
 			result = s.Exec(`
 				UPDATE ` + resultsPropagateTableName + ` AS results_propagate
@@ -410,6 +447,7 @@ func recomputeResultsMarkedAsToBeRecomputedAndMarkThemAsToBePropagated(s *DataSt
 
 			return nil
 		}))
+		finishStep()
 	}
 }
 
@@ -489,6 +527,13 @@ func unlockDependedItemsForResultsMarkedAsPropagatingAndUnmarkThem(s *DataStore,
 
 			mustNotBeError(result.Error)
 			unlockedItemsCount = result.RowsAffected
+
+			// We record events for the unlocked items so that they can be streamed to users.
+			mustNotBeError(s.db.Exec(`
+				INSERT INTO participant_events (type, participant_id, item_id)
+				SELECT DISTINCT 'item_unlocked', participant_id, item_id FROM items_to_unlock`).Error)
+			mustNotBeError(s.WebhookDeliveries().enqueueForParticipantItems(WebhookEventTypeItemUnlocked,
+				"SELECT DISTINCT participant_id, NULL AS attempt_id, item_id FROM items_to_unlock"))
 		}
 
 		mustNotBeError(s.Exec("DELETE FROM " + resultsPropagateTableName + " WHERE state = 'propagating'").Error())
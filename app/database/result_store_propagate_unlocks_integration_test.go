@@ -34,6 +34,68 @@ func TestResultStore_Propagate_Unlocks(t *testing.T) {
 	testRegularUnlocks(db, t)
 }
 
+func TestResultStore_Propagate_Unlocks_RecordsEvents(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixture("results_propagation/_common", "results_propagation/unlocks")
+	defer func() { _ = db.Close() }()
+
+	prepareDependencies(db, t)
+	dataStore := database.NewDataStore(db)
+	assert.NoError(t, dataStore.InTransaction(func(s *database.DataStore) error {
+		s.ScheduleResultsPropagation()
+		return nil
+	}))
+
+	var unlockedItemIDs []int64
+	assert.NoError(t, dataStore.ParticipantEvents().
+		Where("type = 'item_unlocked' AND participant_id = 101 AND attempt_id IS NULL").
+		Order("item_id").Pluck("item_id", &unlockedItemIDs).Error())
+	assert.Equal(t, []int64{1001, 1002, 2001, 2002, 4001, 4002}, unlockedItemIDs)
+
+	found, err := dataStore.ParticipantEvents().
+		Where("type = 'result_changed' AND participant_id = 101 AND attempt_id = 1").HasRows()
+	assert.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestResultStore_Propagate_Unlocks_EnqueuesWebhookDeliveries(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixture("results_propagation/_common", "results_propagation/unlocks")
+	defer func() { _ = db.Close() }()
+
+	prepareDependencies(db, t)
+	dataStore := database.NewDataStore(db)
+	assert.NoError(t, dataStore.InTransaction(func(s *database.DataStore) error {
+		return s.GroupGroups().CreateNewAncestors()
+	}))
+	assert.NoError(t, dataStore.Webhooks().InsertMap(map[string]interface{}{
+		"id": 1, "group_id": 101, "url": "https://example.org/", "secret": "secret",
+		"event_types": "result_validated,item_unlocked",
+	}))
+	assert.NoError(t, dataStore.InTransaction(func(s *database.DataStore) error {
+		s.ScheduleResultsPropagation()
+		return nil
+	}))
+
+	var unlockedItemIDs []int64
+	assert.NoError(t, dataStore.WebhookDeliveries().
+		Where("event_type = 'item_unlocked' AND payload->>'$.participant_id' = '101' AND payload->'$.attempt_id' = CAST('null' AS JSON)").
+		Order("payload->>'$.item_id'").Pluck("payload->>'$.item_id'", &unlockedItemIDs).Error())
+	assert.Equal(t, []int64{1001, 1002, 2001, 2002, 4001, 4002}, unlockedItemIDs)
+
+	var validatedCount int64
+	assert.NoError(t, dataStore.WebhookDeliveries().
+		Joins(`
+			JOIN results ON results.participant_id = payload->>'$.participant_id' AND
+				results.attempt_id = payload->>'$.attempt_id' AND results.item_id = payload->>'$.item_id'`).
+		Where("event_type = 'result_validated'").
+		Where("results.validated_at IS NULL").
+		Count(&validatedCount).Error())
+	assert.Zero(t, validatedCount, "all the results the deliveries are about should be validated")
+}
+
 func TestResultStore_Propagate_Unlocks_UpdatesOldRecords(t *testing.T) {
 	testoutput.SuppressIfPasses(t)
 
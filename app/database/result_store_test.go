@@ -28,3 +28,28 @@ func TestResultStore_ByID(t *testing.T) {
 	assert.Equal(t, []map[string]interface{}{{"id": int64(123)}}, result)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func Test_patchScore(t *testing.T) {
+	set, diff := "set", "diff"
+	value := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name           string
+		score          float64
+		scoreEditRule  *string
+		scoreEditValue *float64
+		want           float64
+	}{
+		{name: "no rule", score: 40, want: 40},
+		{name: "set", score: 40, scoreEditRule: &set, scoreEditValue: value(70), want: 70},
+		{name: "diff", score: 40, scoreEditRule: &diff, scoreEditValue: value(-10), want: 30},
+		{name: "diff below zero", score: 5, scoreEditRule: &diff, scoreEditValue: value(-10), want: 0},
+		{name: "diff above 100", score: 95, scoreEditRule: &diff, scoreEditValue: value(10), want: 100},
+		{name: "rule without value", score: 40, scoreEditRule: &set, want: 40},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, patchScore(tt.score, tt.scoreEditRule, tt.scoreEditValue))
+		})
+	}
+}
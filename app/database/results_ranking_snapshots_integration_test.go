@@ -0,0 +1,54 @@
+//go:build !unit
+
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+func TestResults_AfterUpdateTriggerStoresRankingSnapshots(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		groups: [{id: 11}]
+		items:
+			- {id: 50, default_language_tag: fr, ranking_freeze_duration: "00:10:00"}
+			- {id: 51, default_language_tag: fr}
+			- {id: 60, default_language_tag: fr}
+		attempts:
+			- {participant_id: 11, id: 1, root_item_id: 50, allows_submissions_until: 2020-01-01 12:00:00}
+			- {participant_id: 11, id: 2, root_item_id: 50, allows_submissions_until: 9999-12-31 23:59:59}
+			- {participant_id: 11, id: 3, root_item_id: 60, allows_submissions_until: 2020-01-01 12:00:00}
+		results:
+			- {participant_id: 11, attempt_id: 1, item_id: 50, score_computed: 50, score_obtained_at: 2020-01-01 11:40:00}
+			- {participant_id: 11, attempt_id: 1, item_id: 51, score_computed: 20, score_obtained_at: 2020-01-01 11:40:00}
+			- {participant_id: 11, attempt_id: 2, item_id: 50, score_computed: 30, score_obtained_at: 2020-01-01 11:40:00}
+			- {participant_id: 11, attempt_id: 3, item_id: 60, score_computed: 40, score_obtained_at: 2020-01-01 11:40:00}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.Results().Where("item_id = 50 AND attempt_id = 1").
+		UpdateColumn("latest_activity_at", "2020-01-01 11:55:00").Error())
+	require.NoError(t, store.Results().
+		UpdateColumns(map[string]interface{}{"score_computed": 80, "score_obtained_at": "2020-01-01 11:55:00"}).Error())
+	require.NoError(t, store.Results().
+		UpdateColumns(map[string]interface{}{"score_computed": 90, "score_obtained_at": "2020-01-01 11:58:00"}).Error())
+
+	var snapshots []map[string]interface{}
+	require.NoError(t, store.Table("results_ranking_snapshots").
+		Select("participant_id, attempt_id, item_id, score_computed, CAST(score_obtained_at AS CHAR) AS score_obtained_at").
+		Order("participant_id, attempt_id, item_id").ScanIntoSliceOfMaps(&snapshots).Error())
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"participant_id": int64(11), "attempt_id": int64(1), "item_id": int64(50),
+			"score_computed": float32(50), "score_obtained_at": "2020-01-01 11:40:00",
+		},
+	}, snapshots)
+}
@@ -0,0 +1,7 @@
+package database
+
+// UserIdentityStore implements database operations on `user_identities`
+// (links between users and their accounts at OpenID Connect identity providers).
+type UserIdentityStore struct {
+	*DataStore
+}
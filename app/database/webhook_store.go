@@ -0,0 +1,185 @@
+package database
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// WebhookEventTypeGradeSaved is the type of webhook events emitted when a grade is saved for a result.
+	WebhookEventTypeGradeSaved = "grade_saved"
+	// WebhookEventTypeResultValidated is the type of webhook events emitted when a result becomes validated.
+	WebhookEventTypeResultValidated = "result_validated"
+	// WebhookEventTypeItemUnlocked is the type of webhook events emitted when an item is unlocked for a participant.
+	WebhookEventTypeItemUnlocked = "item_unlocked"
+	// WebhookEventTypeGroupMembershipChanged is the type of webhook events emitted when a row is added into `group_membership_changes`.
+	WebhookEventTypeGroupMembershipChanged = "group_membership_changed"
+)
+
+// WebhookEventTypes returns the list of all the types of webhook events.
+func WebhookEventTypes() []string {
+	return []string{
+		WebhookEventTypeGradeSaved, WebhookEventTypeResultValidated, WebhookEventTypeItemUnlocked, WebhookEventTypeGroupMembershipChanged,
+	}
+}
+
+// WebhookStore implements database operations on `webhooks`.
+type WebhookStore struct {
+	*DataStore
+}
+
+// SubscribedTo returns a composable query of webhooks subscribed to the given type of events.
+func (s *WebhookStore) SubscribedTo(eventType string) *DB {
+	return s.Where("FIND_IN_SET(?, webhooks.event_types)", eventType)
+}
+
+// WebhookDeliveryStore implements database operations on `webhook_deliveries`.
+type WebhookDeliveryStore struct {
+	*DataStore
+}
+
+// EnqueueForResult enqueues deliveries of an event of the given type related to the given result
+// for all the webhooks subscribed to the event type whose scope includes the result.
+func (s *WebhookDeliveryStore) EnqueueForResult(eventType string, participantID, attemptID, itemID int64) error {
+	return s.enqueueForParticipantItems(eventType, "SELECT ? AS participant_id, ? AS attempt_id, ? AS item_id",
+		participantID, attemptID, itemID)
+}
+
+// enqueueForParticipantItems enqueues deliveries of events of the given type for all the rows returned by the source query
+// (with columns participant_id, attempt_id & item_id) for all the webhooks subscribed to the event type
+// whose scope (the group and the optional item) includes the participant and the item.
+func (s *WebhookDeliveryStore) enqueueForParticipantItems(eventType, sourceQuery string, sourceQueryArgs ...interface{}) error {
+	args := append([]interface{}{eventType}, sourceQueryArgs...)
+	args = append(args, eventType)
+	return s.db.Exec(`
+		INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+		SELECT
+			webhooks.id, ?,
+			JSON_OBJECT(
+				'participant_id', CAST(events.participant_id AS CHAR),
+				'attempt_id', CAST(events.attempt_id AS CHAR),
+				'item_id', CAST(events.item_id AS CHAR)
+			)
+		FROM (`+sourceQuery+`) AS events
+		JOIN webhooks ON FIND_IN_SET(?, webhooks.event_types)
+		JOIN groups_ancestors_active AS participant_ancestors
+			ON participant_ancestors.ancestor_group_id = webhooks.group_id AND
+			   participant_ancestors.child_group_id = events.participant_id
+		WHERE webhooks.item_id IS NULL OR webhooks.item_id = events.item_id OR EXISTS(
+			SELECT 1 FROM items_ancestors
+			WHERE items_ancestors.ancestor_item_id = webhooks.item_id AND items_ancestors.child_item_id = events.item_id
+		)`, args...).Error
+}
+
+// enqueueForGroupMembershipChanges enqueues deliveries of 'group_membership_changed' events for the given changes
+// of members of the given group for all the subscribed webhooks whose group is the given group or its ancestor.
+func (s *WebhookDeliveryStore) enqueueForGroupMembershipChanges(
+	groupID int64, changes map[int64]GroupMembershipAction, initiatorID int64,
+) error {
+	var webhookIDs []int64
+	if err := s.Webhooks().SubscribedTo(WebhookEventTypeGroupMembershipChanged).
+		Joins(`
+			JOIN groups_ancestors_active
+				ON groups_ancestors_active.ancestor_group_id = webhooks.group_id AND groups_ancestors_active.child_group_id = ?`, groupID).
+		Where("webhooks.item_id IS NULL").
+		Pluck("webhooks.id", &webhookIDs).Error(); err != nil || len(webhookIDs) == 0 {
+		return err
+	}
+
+	deliveries := make([]map[string]interface{}, 0, len(webhookIDs)*len(changes))
+	for _, webhookID := range webhookIDs {
+		for memberID, toAction := range changes {
+			payload, err := json.Marshal(map[string]string{
+				"group_id":     strconv.FormatInt(groupID, 10),
+				"member_id":    strconv.FormatInt(memberID, 10),
+				"action":       string(toAction[strings.LastIndex(string(toAction), ",")+1:]),
+				"initiator_id": strconv.FormatInt(initiatorID, 10),
+			})
+			mustNotBeError(err)
+			deliveries = append(deliveries, map[string]interface{}{
+				"webhook_id": webhookID,
+				"event_type": WebhookEventTypeGroupMembershipChanged,
+				"payload":    string(payload),
+			})
+		}
+	}
+	return s.InsertMaps(deliveries)
+}
+
+// WebhookDelivery represents a pending delivery together with the target of its webhook.
+type WebhookDelivery struct {
+	ID            int64
+	WebhookID     int64
+	EventType     string
+	Payload       string
+	CreatedAt     Time
+	AttemptsCount int
+	URL           string
+	Secret        string
+}
+
+// GetDue returns at most `limit` pending deliveries which can be attempted now, the oldest first.
+// As several workers can get the same deliveries, a delivery should be claimed (see Claim()) before being attempted.
+func (s *WebhookDeliveryStore) GetDue(limit int) (deliveries []WebhookDelivery, err error) {
+	err = s.
+		Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id").
+		Where("webhook_deliveries.status = 'pending' AND webhook_deliveries.next_attempt_at <= NOW(3)").
+		Select(`
+			webhook_deliveries.id, webhook_deliveries.webhook_id, webhook_deliveries.event_type,
+			CAST(webhook_deliveries.payload AS CHAR) AS payload, webhook_deliveries.created_at,
+			webhook_deliveries.attempts_count, webhooks.url, webhooks.secret`).
+		Order("webhook_deliveries.next_attempt_at, webhook_deliveries.id").
+		Limit(limit).
+		Scan(&deliveries).Error()
+	return deliveries, err
+}
+
+// Claim postpones the next attempt of the given due delivery by the given duration
+// so that other workers don't attempt it meanwhile. If the delivery is neither marked as delivered
+// nor as failed before the claim expires (e.g., because the worker has been stopped), it becomes due again.
+// It returns false if the delivery is not due anymore (e.g., it has been claimed concurrently by another worker).
+func (s *WebhookDeliveryStore) Claim(deliveryID int64, duration time.Duration) (claimed bool, err error) {
+	result := s.Where("id = ? AND status = 'pending' AND next_attempt_at <= NOW(3)", deliveryID).
+		UpdateColumn("next_attempt_at", gorm.Expr("NOW(3) + INTERVAL ? MICROSECOND", duration.Microseconds()))
+	if result.Error() != nil {
+		return false, result.Error()
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// MarkAsDelivered records a successful attempt of the given delivery.
+func (s *WebhookDeliveryStore) MarkAsDelivered(deliveryID int64, responseCode int) error {
+	return s.Where("id = ?", deliveryID).UpdateColumns(map[string]interface{}{
+		"status":             "delivered",
+		"attempts_count":     gorm.Expr("attempts_count + 1"),
+		"last_attempt_at":    gorm.Expr("NOW(3)"),
+		"last_response_code": responseCode,
+		"last_error":         nil,
+	}).Error()
+}
+
+// MarkAsFailedAttempt records a failed attempt of the given delivery (responseCode is 0 if there was no response).
+// The next attempt is postponed by the given delay or, if giveUp is true, the delivery is marked as 'failed'.
+func (s *WebhookDeliveryStore) MarkAsFailedAttempt(
+	deliveryID int64, responseCode int, attemptErr error, retryIn time.Duration, giveUp bool,
+) error {
+	values := map[string]interface{}{
+		"attempts_count":     gorm.Expr("attempts_count + 1"),
+		"last_attempt_at":    gorm.Expr("NOW(3)"),
+		"last_response_code": nil,
+		"last_error":         attemptErr.Error(),
+	}
+	if responseCode != 0 {
+		values["last_response_code"] = responseCode
+	}
+	if giveUp {
+		values["status"] = "failed"
+	} else {
+		values["next_attempt_at"] = gorm.Expr("NOW(3) + INTERVAL ? MICROSECOND", retryIn.Microseconds())
+	}
+	return s.Where("id = ?", deliveryID).UpdateColumns(values).Error()
+}
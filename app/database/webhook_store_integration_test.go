@@ -0,0 +1,183 @@
+//go:build !unit
+
+package database_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+const webhooksFixture = `
+	groups: [{id: 1}, {id: 2}, {id: 3}, {id: 4}]
+	users: [{group_id: 4}]
+	groups_groups: [{parent_group_id: 1, child_group_id: 3}]
+	groups_ancestors:
+		- {ancestor_group_id: 1, child_group_id: 1}
+		- {ancestor_group_id: 1, child_group_id: 3}
+		- {ancestor_group_id: 2, child_group_id: 2}
+		- {ancestor_group_id: 3, child_group_id: 3}
+		- {ancestor_group_id: 4, child_group_id: 4}
+	items: [{id: 10, default_language_tag: fr}, {id: 11, default_language_tag: fr}, {id: 12, default_language_tag: fr}]
+	items_ancestors: [{ancestor_item_id: 10, child_item_id: 11}]
+	webhooks:
+		- {id: 1, group_id: 1, url: "https://example.org/1", secret: s1, event_types: "grade_saved,group_membership_changed"}
+		- {id: 2, group_id: 1, item_id: 10, url: "https://example.org/2", secret: s2, event_types: grade_saved}
+		- {id: 3, group_id: 1, item_id: 12, url: "https://example.org/3", secret: s3, event_types: grade_saved}
+		- {id: 4, group_id: 2, url: "https://example.org/4", secret: s4, event_types: "grade_saved,group_membership_changed"}
+		- {id: 5, group_id: 3, url: "https://example.org/5", secret: s5, event_types: "item_unlocked,group_membership_changed"}
+		- {id: 6, group_id: 3, item_id: 11, url: "https://example.org/6", secret: s6, event_types: grade_saved}`
+
+func getWebhookDeliveries(t *testing.T, store *database.DataStore) []map[string]interface{} {
+	t.Helper()
+
+	var deliveries []map[string]interface{}
+	require.NoError(t, store.WebhookDeliveries().
+		Select("webhook_id, event_type, CAST(payload AS CHAR) AS payload, status").
+		Order("webhook_id, id").ScanIntoSliceOfMaps(&deliveries).Error())
+	return deliveries
+}
+
+func TestWebhookDeliveryStore_EnqueueForResult(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(webhooksFixture)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.WebhookDeliveries().EnqueueForResult(database.WebhookEventTypeGradeSaved, 3, 0, 11))
+
+	payload := `{"item_id": "11", "attempt_id": "0", "participant_id": "3"}`
+	assert.Equal(t, []map[string]interface{}{
+		{"webhook_id": int64(1), "event_type": "grade_saved", "payload": payload, "status": "pending"},
+		{"webhook_id": int64(2), "event_type": "grade_saved", "payload": payload, "status": "pending"},
+		{"webhook_id": int64(6), "event_type": "grade_saved", "payload": payload, "status": "pending"},
+	}, getWebhookDeliveries(t, store))
+}
+
+func TestWebhookDeliveryStore_EnqueuesGroupMembershipChangesOnTransitions(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(webhooksFixture)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.InTransaction(func(store *database.DataStore) error {
+		_, _, err := store.GroupGroups().Transition(database.AdminCreatesInvitation, 3, []int64{4}, nil, 4)
+		return err
+	}))
+
+	payload := `{"action": "invitation_created", "group_id": "3", "member_id": "4", "initiator_id": "4"}`
+	assert.Equal(t, []map[string]interface{}{
+		{"webhook_id": int64(1), "event_type": "group_membership_changed", "payload": payload, "status": "pending"},
+		{"webhook_id": int64(5), "event_type": "group_membership_changed", "payload": payload, "status": "pending"},
+	}, getWebhookDeliveries(t, store))
+}
+
+func TestWebhookDeliveryStore_GetDue(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(webhooksFixture, `
+		webhook_deliveries:
+			- {id: 1, webhook_id: 1, event_type: grade_saved, payload: '{"a": 1}', created_at: "2020-01-01 00:00:00",
+			   next_attempt_at: "2020-01-01 00:00:02"}
+			- {id: 2, webhook_id: 4, event_type: grade_saved, payload: '{"a": 2}', created_at: "2020-01-01 00:00:00",
+			   next_attempt_at: "2020-01-01 00:00:01", attempts_count: 3}
+			- {id: 3, webhook_id: 1, event_type: grade_saved, payload: '{}', next_attempt_at: "9999-12-31 23:59:59"}
+			- {id: 4, webhook_id: 1, event_type: grade_saved, payload: '{}', status: delivered}
+			- {id: 5, webhook_id: 1, event_type: grade_saved, payload: '{}', status: failed}
+			- {id: 6, webhook_id: 1, event_type: grade_saved, payload: '{}', next_attempt_at: "2020-01-01 00:00:03"}`)
+	defer func() { _ = db.Close() }()
+
+	deliveries, err := database.NewDataStore(db).WebhookDeliveries().GetDue(2)
+	require.NoError(t, err)
+	createdAt := database.Time(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, []database.WebhookDelivery{
+		{
+			ID: 2, WebhookID: 4, EventType: "grade_saved", Payload: `{"a": 2}`, CreatedAt: createdAt, AttemptsCount: 3,
+			URL: "https://example.org/4", Secret: "s4",
+		},
+		{
+			ID: 1, WebhookID: 1, EventType: "grade_saved", Payload: `{"a": 1}`, CreatedAt: createdAt,
+			URL: "https://example.org/1", Secret: "s1",
+		},
+	}, deliveries)
+}
+
+func TestWebhookDeliveryStore_MarkAsDelivered_MarkAsFailedAttempt(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(webhooksFixture, `
+		webhook_deliveries:
+			- {id: 1, webhook_id: 1, event_type: grade_saved, payload: '{}', attempts_count: 1, last_error: "timeout"}
+			- {id: 2, webhook_id: 1, event_type: grade_saved, payload: '{}'}
+			- {id: 3, webhook_id: 1, event_type: grade_saved, payload: '{}', attempts_count: 9}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	require.NoError(t, store.WebhookDeliveries().MarkAsDelivered(1, 204))
+	require.NoError(t, store.WebhookDeliveries().MarkAsFailedAttempt(2, 500, errors.New("unexpected status code: 500"), time.Hour, false))
+	require.NoError(t, store.WebhookDeliveries().MarkAsFailedAttempt(3, 0, errors.New("connection refused"), time.Hour, true))
+
+	var deliveries []map[string]interface{}
+	require.NoError(t, store.WebhookDeliveries().
+		Select(`id, status, attempts_count, last_response_code, last_error,
+			ABS(TIMESTAMPDIFF(SECOND, last_attempt_at, NOW())) < 3 AS attempted_now,
+			next_attempt_at > NOW() + INTERVAL 59 MINUTE AS postponed`).
+		Order("id").ScanIntoSliceOfMaps(&deliveries).Error())
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"id": int64(1), "status": "delivered", "attempts_count": int64(2), "last_response_code": int64(204), "last_error": nil,
+			"attempted_now": int64(1), "postponed": int64(0),
+		},
+		{
+			"id": int64(2), "status": "pending", "attempts_count": int64(1), "last_response_code": int64(500),
+			"last_error": "unexpected status code: 500", "attempted_now": int64(1), "postponed": int64(1),
+		},
+		{
+			"id": int64(3), "status": "failed", "attempts_count": int64(10), "last_response_code": nil,
+			"last_error": "connection refused", "attempted_now": int64(1), "postponed": int64(0),
+		},
+	}, deliveries)
+
+	deliveriesDue, err := store.WebhookDeliveries().GetDue(10)
+	require.NoError(t, err)
+	assert.Empty(t, deliveriesDue)
+}
+
+func TestWebhookDeliveryStore_Claim(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(webhooksFixture, `
+		webhook_deliveries:
+			- {id: 1, webhook_id: 1, event_type: grade_saved, payload: '{}', next_attempt_at: "2020-01-01 00:00:00"}
+			- {id: 2, webhook_id: 1, event_type: grade_saved, payload: '{}', next_attempt_at: "9999-12-31 23:59:59"}
+			- {id: 3, webhook_id: 1, event_type: grade_saved, payload: '{}', status: delivered}`)
+	defer func() { _ = db.Close() }()
+
+	store := database.NewDataStore(db)
+	for _, test := range []struct {
+		deliveryID int64
+		want       bool
+	}{
+		{deliveryID: 1, want: true},
+		{deliveryID: 1, want: false}, // already claimed
+		{deliveryID: 2, want: false},
+		{deliveryID: 3, want: false},
+	} {
+		claimed, err := store.WebhookDeliveries().Claim(test.deliveryID, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, test.want, claimed, "delivery %d", test.deliveryID)
+	}
+
+	deliveries, err := store.WebhookDeliveries().GetDue(10)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
@@ -72,6 +72,13 @@ type unprocessableEntityResponseWithMissingApprovals struct {
 	}
 }
 
+// Too Many Requests. The rate limit of the service has been exceeded (see the `Retry-After` header).
+// swagger:response tooManyRequestsResponse
+type tooManyRequestsResponse struct {
+	// in: body
+	Body struct{ tooManyRequests }
+}
+
 // Internal Error. An unexpected error has happened on the server (e.g., uncaught database error).
 // If the problem persists, it should be reported.
 // swagger:response internalErrorResponse
@@ -16,69 +16,84 @@ type genericError struct {
 	// example: false
 	// required: true
 	Success bool `json:"success"`
-	// Error description, match the HTTP error code (see https://golang.org/src/net/http/status.go)
+	// Error description, match the HTTP error code (see https://golang.org/src/net/http/status.go),
+	// localized according to the `Accept-Language` header or to the user's default language
+	// (English, French, German and Arabic are supported, English is used by default)
 	// required: true
 	Message string `json:"message"`
-	// The error message, for developers, only to be used for debugging.
+	// Stable code of the error which does not depend on the language
+	// (like 'insufficient_access_rights' or 'invalid_input_data'),
+	// the code of the HTTP status (like 'bad_request') for errors having no specific code
+	// required: true
+	ErrorCode string `json:"error_code"`
+	// The error message, for developers, only to be used for debugging
+	// (localized like `message` if the error has a specific code).
 	ErrorText string `json:"error_text,omitempty"`
 }
 
 type badRequest struct {
 	genericError
 	// required: true
-	// enum: Bad Request
+	// example: Bad Request
 	Message string `json:"message"`
 	// In case of input data validation error, this may contain a map with, as key, the field in error
-	// and, as value, an array of strings describing errors in English.
+	// and, as value, an array of strings describing errors (localized like `message` when possible).
 	Errors interface{} `json:"errors,omitempty"`
 }
 
 type unauthorized struct {
 	genericError
 	// required: true
-	// enum: Unauthorized
+	// example: Unauthorized
 	Message string `json:"message"`
 }
 
 type forbidden struct {
 	genericError
 	// required: true
-	// enum: Forbidden
+	// example: Forbidden
 	Message string `json:"message"`
 }
 
 type conflict struct {
 	genericError
 	// required: true
-	// enum: Conflict
+	// example: Conflict
 	Message string `json:"message"`
 }
 
 type notFound struct {
 	genericError
 	// required: true
-	// enum: Not Found
+	// example: Not Found
 	Message string `json:"message"`
 }
 
 type requestTimeout struct {
 	genericError
 	// required: true
-	// enum: Request Timeout
+	// example: Request Timeout
 	Message string `json:"message"`
 }
 
 type unprocessableEntity struct {
 	genericError
 	// required: true
-	// enum: Unprocessable Entity
+	// example: Unprocessable Entity
+	Message string `json:"message"`
+}
+
+type tooManyRequests struct {
+	genericError
+	// required: true
+	// example: Too Many Requests
 	Message string `json:"message"`
 }
 
 type unprocessableEntityWithMissingApprovals struct {
 	genericError
 	// required: true
-	// enum: Unprocessable Entity
+	// example: Unprocessable Entity
 	Message string                                      `json:"message"`
 	Data    unprocessableEntityWithMissingApprovalsData `json:"data"`
 }
@@ -92,6 +107,6 @@ type unprocessableEntityWithMissingApprovalsData struct {
 type internalError struct {
 	genericError
 	// required: true
-	// enum: Internal Server Error
+	// example: Internal Server Error
 	Message string `json:"message"`
 }
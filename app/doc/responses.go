@@ -216,6 +216,9 @@ type itemAnswerGetResponse struct {
 		Score *float32 `json:"score"`
 		// required:true
 		GradedAt *time.Time `json:"graded_at"`
+		// The comment of the user who graded the answer manually
+		// (only returned by `GET /answers/{answer_id}`)
+		Feedback *string `json:"feedback"`
 	}
 }
 
@@ -16,6 +16,7 @@ const (
 type CtxConfig struct {
 	AllUsersGroupID  int64
 	TempUsersGroupID int64
+	PlatformURL      string
 }
 
 // ConfigItem is one item of the configuration list.
@@ -23,6 +24,7 @@ type ConfigItem struct {
 	Domains        []string
 	AllUsersGroup  int64
 	TempUsersGroup int64
+	PlatformURL    string // the URL of the platform users log into (printed on credential sheets)
 }
 
 // ConfigFromContext retrieves the current domain configuration from a context set by the middleware.
@@ -18,6 +18,7 @@ func Middleware(domains []ConfigItem, domainOverride string) func(next http.Hand
 			domainsMap[host] = &CtxConfig{
 				AllUsersGroupID:  domain.AllUsersGroup,
 				TempUsersGroupID: domain.TempUsersGroup,
+				PlatformURL:      domain.PlatformURL,
 			}
 			if host == "default" {
 				defaultConfig = domainsMap[host]
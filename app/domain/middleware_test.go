@@ -29,10 +29,10 @@ func TestMiddleware(t *testing.T) {
 				},
 				{
 					Domains:       []string{"192.168.0.1", "127.0.0.1"},
-					AllUsersGroup: 2, TempUsersGroup: 4,
+					AllUsersGroup: 2, TempUsersGroup: 4, PlatformURL: "https://example.org",
 				},
 			},
-			expectedConfig:     &CtxConfig{AllUsersGroupID: 2, TempUsersGroupID: 4},
+			expectedConfig:     &CtxConfig{AllUsersGroupID: 2, TempUsersGroupID: 4, PlatformURL: "https://example.org"},
 			expectedDomain:     "127.0.0.1",
 			expectedStatusCode: http.StatusOK,
 			shouldEnterService: true,
@@ -0,0 +1,92 @@
+// Package exports provides asynchronous export jobs: the description of the exports,
+// the storage of their artifacts and the worker running them.
+package exports
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// JobTypeGroupProgress is the type of exports of the progress of groups (as done by groupGroupProgressCSV).
+	JobTypeGroupProgress = "group_progress"
+	// JobTypeUserProgress is the type of exports of the progress of users (as done by groupUserProgressCSV).
+	JobTypeUserProgress = "user_progress"
+	// JobTypeTeamProgress is the type of exports of the progress of teams (as done by groupTeamProgressCSV).
+	JobTypeTeamProgress = "team_progress"
+	// JobTypeFullDump is the type of exports of all the data of the requesting user (as done by getFullDump).
+	JobTypeFullDump = "full_dump"
+	// JobTypeActivityLog is the type of exports of an activity log (as done by itemActivityLogForItem/itemActivityLogForAllItems).
+	JobTypeActivityLog = "activity_log"
+)
+
+// JobTypes returns the list of all the types of export jobs.
+func JobTypes() []string {
+	return []string{JobTypeGroupProgress, JobTypeUserProgress, JobTypeTeamProgress, JobTypeFullDump, JobTypeActivityLog}
+}
+
+// Parameters are the parameters of an export job. Which of them are required or allowed depends on the type of the job,
+// see ParametersOfJobType.
+type Parameters struct {
+	GroupID        int64    `json:"group_id,string,omitempty"`
+	ParentItemIDs  []string `json:"parent_item_ids,omitempty"`
+	Format         string   `json:"format,omitempty"`
+	ItemID         int64    `json:"item_id,string,omitempty"`
+	WatchedGroupID int64    `json:"watched_group_id,string,omitempty"`
+	AsTeamID       int64    `json:"as_team_id,string,omitempty"`
+}
+
+// ParametersOfJobType returns the names of the required and of the optional parameters of the given type of export jobs.
+func ParametersOfJobType(jobType string) (required, optional []string) {
+	switch jobType {
+	case JobTypeGroupProgress, JobTypeUserProgress, JobTypeTeamProgress:
+		return []string{"group_id", "parent_item_ids"}, []string{"format"}
+	case JobTypeActivityLog:
+		return nil, []string{"item_id", "watched_group_id", "as_team_id"}
+	default:
+		return nil, nil
+	}
+}
+
+// activityLogPageSize is the number of rows of the activity log requested at once.
+const activityLogPageSize = 1000
+
+// request returns the path (relative to the root of the API) and the query of the service
+// producing the export of the given type. If paged is true, the service returns pages of a JSON array
+// which should be requested one after another.
+func (parameters *Parameters) request(jobType string) (path string, query url.Values, paged bool, err error) {
+	query = url.Values{}
+	switch jobType {
+	case JobTypeGroupProgress, JobTypeUserProgress, JobTypeTeamProgress:
+		path = fmt.Sprintf("groups/%d/%s-progress-csv", parameters.GroupID, strings.TrimSuffix(jobType, "_progress"))
+		query.Set("parent_item_ids", strings.Join(parameters.ParentItemIDs, ","))
+		if parameters.Format != "" {
+			query.Set("format", parameters.Format)
+		}
+	case JobTypeFullDump:
+		path = "current-user/full-dump"
+	case JobTypeActivityLog:
+		path = "items/log"
+		if parameters.ItemID != 0 {
+			path = fmt.Sprintf("items/%d/log", parameters.ItemID)
+		}
+		if parameters.WatchedGroupID != 0 {
+			query.Set("watched_group_id", strconv.FormatInt(parameters.WatchedGroupID, 10))
+		}
+		if parameters.AsTeamID != 0 {
+			query.Set("as_team_id", strconv.FormatInt(parameters.AsTeamID, 10))
+		}
+		query.Set("limit", strconv.Itoa(activityLogPageSize))
+		paged = true
+	default:
+		return "", nil, false, fmt.Errorf("unknown type of export: %q", jobType)
+	}
+	return path, query, paged, nil
+}
+
+// ArtifactKey returns the key of the artifact of the given export job in the storage.
+func ArtifactKey(jobID int64) string {
+	return strconv.FormatInt(jobID, 10)
+}
@@ -0,0 +1,94 @@
+package exports
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameters_request(t *testing.T) {
+	tests := []struct {
+		name       string
+		jobType    string
+		parameters Parameters
+		wantPath   string
+		wantQuery  url.Values
+		wantPaged  bool
+	}{
+		{
+			name:       "group progress",
+			jobType:    JobTypeGroupProgress,
+			parameters: Parameters{GroupID: 1, ParentItemIDs: []string{"10", "11"}},
+			wantPath:   "groups/1/group-progress-csv",
+			wantQuery:  url.Values{"parent_item_ids": {"10,11"}},
+		},
+		{
+			name:       "user progress as NDJSON",
+			jobType:    JobTypeUserProgress,
+			parameters: Parameters{GroupID: 2, ParentItemIDs: []string{"10"}, Format: "ndjson"},
+			wantPath:   "groups/2/user-progress-csv",
+			wantQuery:  url.Values{"parent_item_ids": {"10"}, "format": {"ndjson"}},
+		},
+		{
+			name:       "team progress",
+			jobType:    JobTypeTeamProgress,
+			parameters: Parameters{GroupID: 3, ParentItemIDs: []string{"12"}},
+			wantPath:   "groups/3/team-progress-csv",
+			wantQuery:  url.Values{"parent_item_ids": {"12"}},
+		},
+		{
+			name:      "full dump",
+			jobType:   JobTypeFullDump,
+			wantPath:  "current-user/full-dump",
+			wantQuery: url.Values{},
+		},
+		{
+			name:      "activity log on all the items",
+			jobType:   JobTypeActivityLog,
+			wantPath:  "items/log",
+			wantQuery: url.Values{"limit": {"1000"}},
+			wantPaged: true,
+		},
+		{
+			name:       "activity log on an item",
+			jobType:    JobTypeActivityLog,
+			parameters: Parameters{ItemID: 20, WatchedGroupID: 4, AsTeamID: 5},
+			wantPath:   "items/20/log",
+			wantQuery:  url.Values{"limit": {"1000"}, "watched_group_id": {"4"}, "as_team_id": {"5"}},
+			wantPaged:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			path, query, paged, err := tt.parameters.request(tt.jobType)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPath, path)
+			assert.Equal(t, tt.wantQuery, query)
+			assert.Equal(t, tt.wantPaged, paged)
+		})
+	}
+}
+
+func TestParameters_request_UnknownType(t *testing.T) {
+	_, _, _, err := (&Parameters{}).request("unknown")
+	assert.EqualError(t, err, `unknown type of export: "unknown"`)
+}
+
+func TestParametersOfJobType(t *testing.T) {
+	for _, jobType := range JobTypes() {
+		required, optional := ParametersOfJobType(jobType)
+		switch jobType {
+		case JobTypeGroupProgress, JobTypeUserProgress, JobTypeTeamProgress:
+			assert.Equal(t, []string{"group_id", "parent_item_ids"}, required)
+			assert.Equal(t, []string{"format"}, optional)
+		case JobTypeFullDump:
+			assert.Empty(t, required)
+			assert.Empty(t, optional)
+		case JobTypeActivityLog:
+			assert.Empty(t, required)
+			assert.Equal(t, []string{"item_id", "watched_group_id", "as_team_id"}, optional)
+		}
+	}
+}
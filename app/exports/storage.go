@@ -0,0 +1,116 @@
+package exports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Storage stores artifacts of export jobs.
+type Storage interface {
+	// Create creates (or replaces) the artifact with the given key.
+	// The artifact becomes available when the returned writer is closed.
+	Create(ctx context.Context, key string) (io.WriteCloser, error)
+	// Open opens the artifact with the given key for reading.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete deletes the artifact with the given key. Deleting a missing artifact is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStorage creates the storage of artifacts configured in the server config:
+//   - 'exportsStorage' is the type of the storage (only 'local' is supported for now),
+//   - 'exportsLocalStoragePath' is the directory of the 'local' storage
+//     ('algorea-exports' in the temporary directory of the system by default).
+func NewStorage(serverConfig *viper.Viper) (Storage, error) {
+	switch storageType := serverConfig.GetString("exportsStorage"); storageType {
+	case "", "local":
+		directory := serverConfig.GetString("exportsLocalStoragePath")
+		if directory == "" {
+			directory = filepath.Join(os.TempDir(), "algorea-exports")
+		}
+		return NewLocalStorage(directory), nil
+	default:
+		return nil, fmt.Errorf("unknown exports storage: %q", storageType)
+	}
+}
+
+// LocalStorage stores artifacts as files in a directory of the local filesystem.
+type LocalStorage struct {
+	directory string
+}
+
+// NewLocalStorage creates a storage keeping artifacts in the given directory (created if needed).
+func NewLocalStorage(directory string) *LocalStorage {
+	return &LocalStorage{directory: directory}
+}
+
+var errInvalidArtifactKey = errors.New("invalid artifact key")
+
+func (s *LocalStorage) path(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+		return "", errInvalidArtifactKey
+	}
+	return filepath.Join(s.directory, key), nil
+}
+
+// Create creates (or replaces) the artifact with the given key.
+// The data is written into a temporary file which is renamed when the returned writer is closed.
+func (s *LocalStorage) Create(_ context.Context, key string) (io.WriteCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(s.directory, 0o700); err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(s.directory, "."+key+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &localArtifactWriter{File: file, path: path}, nil
+}
+
+// Open opens the artifact with the given key for reading.
+func (s *LocalStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Delete deletes the artifact with the given key.
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+type localArtifactWriter struct {
+	*os.File
+	path string
+}
+
+// Close closes the temporary file and renames it into the artifact file.
+func (w *localArtifactWriter) Close() error {
+	if err := w.File.Close(); err != nil {
+		_ = os.Remove(w.File.Name())
+		return err
+	}
+	if err := os.Rename(w.File.Name(), w.path); err != nil {
+		_ = os.Remove(w.File.Name())
+		return err
+	}
+	return nil
+}
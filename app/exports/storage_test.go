@@ -0,0 +1,72 @@
+package exports
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "exports")
+	storage := NewLocalStorage(directory)
+	ctx := context.Background()
+
+	writer, err := storage.Create(ctx, "123")
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("some data"))
+	require.NoError(t, err)
+	_, err = storage.Open(ctx, "123")
+	assert.ErrorIs(t, err, os.ErrNotExist, "the artifact should not be available before the writer is closed")
+	require.NoError(t, writer.Close())
+
+	reader, err := storage.Open(ctx, "123")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "some data", string(data))
+
+	entries, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files should be left")
+
+	require.NoError(t, storage.Delete(ctx, "123"))
+	_, err = storage.Open(ctx, "123")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.NoError(t, storage.Delete(ctx, "123"), "deleting a missing artifact is not an error")
+}
+
+func TestLocalStorage_RejectsInvalidKeys(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir())
+	ctx := context.Background()
+	for _, key := range []string{"", ".", "..", "../123", `a\b`} {
+		_, err := storage.Create(ctx, key)
+		assert.ErrorIs(t, err, errInvalidArtifactKey, key)
+		_, err = storage.Open(ctx, key)
+		assert.ErrorIs(t, err, errInvalidArtifactKey, key)
+		assert.ErrorIs(t, storage.Delete(ctx, key), errInvalidArtifactKey, key)
+	}
+}
+
+func TestNewStorage(t *testing.T) {
+	config := viper.New()
+	storage, err := NewStorage(config)
+	require.NoError(t, err)
+	assert.Equal(t, NewLocalStorage(filepath.Join(os.TempDir(), "algorea-exports")), storage)
+
+	config.Set("exportsLocalStoragePath", "/var/exports")
+	storage, err = NewStorage(config)
+	require.NoError(t, err)
+	assert.Equal(t, NewLocalStorage("/var/exports"), storage)
+
+	config.Set("exportsStorage", "s3")
+	storage, err = NewStorage(config)
+	assert.EqualError(t, err, `unknown exports storage: "s3"`)
+	assert.Nil(t, storage)
+}
@@ -0,0 +1,375 @@
+package exports
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/pollingworker"
+)
+
+const (
+	expiredJobsBatchSize = 100
+	maxErrorBodyBytes    = 64 << 10
+	maxErrorLength       = 1000
+)
+
+var (
+	errInterrupted = errors.New("the export has been interrupted")
+	errInternal    = errors.New("internal error")
+)
+
+// Config is the configuration of the export worker.
+type Config struct {
+	// PollInterval is the time to wait before checking for pending jobs again when there is nothing to do.
+	PollInterval time.Duration
+	// ArtifactTTL is the time during which an artifact can be downloaded.
+	ArtifactTTL time.Duration
+	// MaxDuration is the maximum duration of a job. Jobs running for longer are considered interrupted.
+	MaxDuration time.Duration
+}
+
+// DefaultConfig returns the default configuration of the export worker.
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		ArtifactTTL:  24 * time.Hour,
+		MaxDuration:  time.Hour,
+	}
+}
+
+// Worker runs the pending export jobs on behalf of the users who have requested them
+// by calling the services of the API (so the same permission checks apply) and stores their artifacts.
+type Worker struct {
+	db       *database.DB
+	handler  http.Handler
+	rootPath string
+	storage  Storage
+	config   Config
+}
+
+// New creates a new export worker. The handler should serve the API mounted on the given root path.
+func New(db *database.DB, handler http.Handler, rootPath string, storage Storage, config Config) *Worker {
+	return &Worker{db: db, handler: handler, rootPath: rootPath, storage: storage, config: config}
+}
+
+// Run runs the pending export jobs until the context is canceled.
+// Errors are logged and the processing is retried on the next poll.
+func (w *Worker) Run(ctx context.Context) {
+	pollingworker.Run(ctx, "Export", w.config.PollInterval, w.RunOnce)
+}
+
+// RunOnce fails the interrupted jobs, deletes the expired artifacts and runs the oldest pending job (if any).
+// It returns true if a job has been run (successfully or not).
+func (w *Worker) RunOnce(ctx context.Context) (processed bool, err error) {
+	store := database.NewDataStoreWithContext(ctx, w.db)
+	if err = w.failStaleJobs(ctx, store); err != nil {
+		return false, err
+	}
+	if err = w.deleteExpiredArtifacts(ctx, store); err != nil {
+		return false, err
+	}
+
+	job, found, err := store.ExportJobs().TakeNextPending()
+	if err != nil || !found {
+		return false, err
+	}
+	return true, w.run(ctx, &job)
+}
+
+func (w *Worker) failStaleJobs(ctx context.Context, store *database.DataStore) error {
+	jobIDs, err := store.ExportJobs().GetIDsOfStale(w.config.MaxDuration)
+	if err != nil {
+		return err
+	}
+	for _, jobID := range jobIDs {
+		if err = w.storage.Delete(ctx, ArtifactKey(jobID)); err != nil {
+			return err
+		}
+		if err = store.ExportJobs().MarkAsFailed(jobID, errInterrupted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Worker) deleteExpiredArtifacts(ctx context.Context, store *database.DataStore) error {
+	jobs, err := store.ExportJobs().GetExpired(expiredJobsBatchSize)
+	if err != nil {
+		return err
+	}
+	for index := range jobs {
+		if err = w.storage.Delete(ctx, jobs[index].ArtifactKey); err != nil {
+			return err
+		}
+		if err = store.ExportJobs().MarkAsExpired(jobs[index].ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Worker) run(ctx context.Context, job *database.ExportJob) error {
+	logEntry := logging.SharedLogger.WithContext(ctx).
+		WithField("export_job_id", job.ID).
+		WithField("type", job.Type).
+		WithField("user_id", job.UserID)
+
+	jobCtx, cancel := context.WithTimeout(ctx, w.config.MaxDuration)
+	defer cancel()
+
+	key := ArtifactKey(job.ID)
+	artifact, jobErr := w.produceArtifact(jobCtx, job, key)
+	if jobErr == nil {
+		logEntry.Info("Export succeeded")
+		return database.NewDataStoreWithContext(ctx, w.db).ExportJobs().MarkAsSucceeded(job.ID, artifact, w.config.ArtifactTTL)
+	}
+
+	// the context of the worker may be canceled, but the job should be marked as failed anyway
+	store := database.NewDataStore(w.db)
+	if err := w.storage.Delete(context.Background(), key); err != nil {
+		logEntry.Errorf("Cannot delete the artifact of a failed export: %v", err)
+	}
+	switch {
+	case ctx.Err() != nil || errors.Is(jobCtx.Err(), context.DeadlineExceeded):
+		jobErr = errInterrupted
+	case !errors.As(jobErr, new(*serviceError)):
+		logEntry.Errorf("Export failed: %v", jobErr)
+		jobErr = errInternal
+	}
+	logEntry.WithField("error", jobErr.Error()).Info("Export failed")
+	return store.ExportJobs().MarkAsFailed(job.ID, jobErr)
+}
+
+func (w *Worker) produceArtifact(ctx context.Context, job *database.ExportJob, key string) (*database.ExportArtifact, error) {
+	var parameters Parameters
+	if err := json.Unmarshal([]byte(job.Parameters), &parameters); err != nil {
+		return nil, err
+	}
+	servicePath, query, paged, err := parameters.request(job.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var user database.User
+	if err = database.NewDataStoreWithContext(ctx, w.db).Users().ByID(job.UserID).
+		Select("login, login_id, is_admin, group_id, access_group_id, temp_user, notifications_read_at, default_language").
+		Take(&user).Error(); err != nil {
+		return nil, err
+	}
+
+	artifactWriter, err := w.storage.Create(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	countingWriter := &countingWriter{writer: artifactWriter}
+	request := serviceRequest{
+		user:   &user,
+		domain: job.Domain,
+		path:   path.Join(w.rootPath, servicePath),
+		query:  query,
+	}
+	var header http.Header
+	if paged {
+		header, err = w.callPagedService(ctx, &request, countingWriter)
+	} else {
+		header, err = w.callService(ctx, &request, countingWriter)
+	}
+	if closeErr := artifactWriter.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var fileName string
+	if _, dispositionParams, parseErr := mime.ParseMediaType(header.Get("Content-Disposition")); parseErr == nil {
+		fileName = dispositionParams["filename"]
+	}
+	if fileName == "" {
+		fileName = job.Type
+	}
+	return &database.ExportArtifact{
+		Key:         key,
+		FileName:    fileName,
+		ContentType: header.Get("Content-Type"),
+		Size:        countingWriter.size,
+	}, nil
+}
+
+type serviceRequest struct {
+	user   *database.User
+	domain string
+	path   string
+	query  url.Values
+}
+
+// callService calls a service of the API on behalf of the user and copies the response body into the writer.
+// An unsuccessful response of the service is returned as a *serviceError.
+func (w *Worker) callService(ctx context.Context, serviceRequest *serviceRequest, writer io.Writer) (http.Header, error) {
+	request, err := http.NewRequestWithContext(auth.ContextWithDelegatedUser(ctx, serviceRequest.user), http.MethodGet,
+		serviceRequest.path, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	request.URL.RawQuery = serviceRequest.query.Encode()
+	request.Host = serviceRequest.domain
+	request.RemoteAddr = "127.0.0.1:0"
+
+	responseWriter := &artifactResponseWriter{header: http.Header{}, artifact: writer}
+	w.handler.ServeHTTP(responseWriter, request)
+	if responseWriter.writeErr != nil {
+		return nil, responseWriter.writeErr
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if responseWriter.statusCode != http.StatusOK || responseWriter.failed {
+		return nil, newServiceError(responseWriter.statusCode, responseWriter.errorBody.Bytes())
+	}
+	return responseWriter.header, nil
+}
+
+// callPagedService requests the pages of a service returning a JSON array one after another
+// (using the paging parameters of the last row of each page to request the next one)
+// and writes the rows into the writer as NDJSON.
+func (w *Worker) callPagedService(ctx context.Context, serviceRequest *serviceRequest, writer io.Writer) (http.Header, error) {
+	encoder := json.NewEncoder(writer)
+	for {
+		var page bytes.Buffer
+		if _, err := w.callService(ctx, serviceRequest, &page); err != nil {
+			return nil, err
+		}
+		var rows []json.RawMessage
+		if err := json.Unmarshal(page.Bytes(), &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return nil, err
+			}
+		}
+		if len(rows) < activityLogPageSize {
+			break
+		}
+
+		var lastRow struct {
+			ActivityType string `json:"activity_type"`
+			AttemptID    string `json:"attempt_id"`
+			FromAnswerID string `json:"from_answer_id"`
+			Participant  struct {
+				ID string `json:"id"`
+			} `json:"participant"`
+			Item struct {
+				ID string `json:"id"`
+			} `json:"item"`
+		}
+		if err := json.Unmarshal(rows[len(rows)-1], &lastRow); err != nil {
+			return nil, err
+		}
+		serviceRequest.query.Set("from.activity_type", lastRow.ActivityType)
+		serviceRequest.query.Set("from.attempt_id", lastRow.AttemptID)
+		serviceRequest.query.Set("from.answer_id", lastRow.FromAnswerID)
+		serviceRequest.query.Set("from.participant_id", lastRow.Participant.ID)
+		serviceRequest.query.Set("from.item_id", lastRow.Item.ID)
+	}
+	return http.Header{
+		"Content-Type":        {"application/x-ndjson"},
+		"Content-Disposition": {"attachment; filename=activity_log.ndjson"},
+	}, nil
+}
+
+// serviceError is an unsuccessful response of a service. Its message is shown to the user.
+type serviceError struct {
+	statusCode int
+	message    string
+}
+
+func newServiceError(statusCode int, body []byte) *serviceError {
+	var response struct {
+		Message   string `json:"message"`
+		ErrorText string `json:"error_text"`
+	}
+	_ = json.Unmarshal(body, &response)
+	message := response.ErrorText
+	if message == "" {
+		message = response.Message
+	}
+	if message == "" {
+		message = fmt.Sprintf("unexpected status code: %d", statusCode)
+	}
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+	return &serviceError{statusCode: statusCode, message: message}
+}
+
+func (e *serviceError) Error() string {
+	return e.message
+}
+
+// artifactResponseWriter is a response writer copying a successful response body into the artifact.
+// The body of an unsuccessful response is kept (partly) in errorBody.
+// If the service fails after having started to write a successful response, failed is set to true.
+type artifactResponseWriter struct {
+	header     http.Header
+	statusCode int
+	artifact   io.Writer
+	writeErr   error
+	failed     bool
+	errorBody  bytes.Buffer
+}
+
+func (rw *artifactResponseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *artifactResponseWriter) WriteHeader(statusCode int) {
+	if rw.statusCode == 0 {
+		rw.statusCode = statusCode
+		return
+	}
+	if rw.statusCode == http.StatusOK && statusCode != http.StatusOK {
+		rw.failed = true
+	}
+}
+
+func (rw *artifactResponseWriter) Write(data []byte) (int, error) {
+	rw.WriteHeader(http.StatusOK)
+	if rw.statusCode != http.StatusOK || rw.failed {
+		if rw.errorBody.Len() < maxErrorBodyBytes {
+			rw.errorBody.Write(data)
+		}
+		return len(data), nil
+	}
+	if rw.writeErr != nil {
+		return 0, rw.writeErr
+	}
+	written, err := rw.artifact.Write(data)
+	if err != nil {
+		rw.writeErr = err
+	}
+	return written, err
+}
+
+type countingWriter struct {
+	writer io.Writer
+	size   int64
+}
+
+func (w *countingWriter) Write(data []byte) (int, error) {
+	written, err := w.writer.Write(data)
+	w.size += int64(written)
+	return written, err
+}
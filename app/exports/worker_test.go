@@ -0,0 +1,140 @@
+package exports
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+type noStoreProvider struct{}
+
+func (noStoreProvider) GetStore(*http.Request) *database.DataStore { return nil }
+
+func newTestWorker(handler http.HandlerFunc) *Worker {
+	return New(nil, auth.UserMiddleware(noStoreProvider{})(handler), "/api/", nil, DefaultConfig())
+}
+
+func TestDefaultConfig(t *testing.T) {
+	assert.Equal(t, Config{PollInterval: time.Second, ArtifactTTL: 24 * time.Hour, MaxDuration: time.Hour}, DefaultConfig())
+}
+
+func TestWorker_callService(t *testing.T) {
+	user := &database.User{GroupID: 2, Login: "john"}
+	worker := newTestWorker(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, user, auth.UserFromContext(r.Context()))
+		assert.Equal(t, "example.org", r.Host)
+		assert.Equal(t, "/api/groups/1/group-progress-csv", r.URL.Path)
+		assert.Equal(t, "parent_item_ids=10%2C11", r.URL.RawQuery)
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write([]byte("Group name\n"))
+		_, _ = w.Write([]byte("Our group\n"))
+	})
+
+	var artifact bytes.Buffer
+	header, err := worker.callService(context.Background(), &serviceRequest{
+		user: user, domain: "example.org", path: "/api/groups/1/group-progress-csv",
+		query: url.Values{"parent_item_ids": {"10,11"}},
+	}, &artifact)
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", header.Get("Content-Type"))
+	assert.Equal(t, "Group name\nOur group\n", artifact.String())
+}
+
+func TestWorker_callService_ReturnsErrorsOfService(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		statusCode  int
+		body        string
+		wantMessage string
+	}{
+		{
+			name: "with error text", statusCode: http.StatusForbidden,
+			body:        `{"success":false,"message":"Forbidden","error_text":"Insufficient access rights"}`,
+			wantMessage: "Insufficient access rights",
+		},
+		{
+			name: "without error text", statusCode: http.StatusInternalServerError,
+			body:        `{"success":false,"message":"Internal server error"}`,
+			wantMessage: "Internal server error",
+		},
+		{
+			name: "not JSON", statusCode: http.StatusBadGateway, body: "Bad gateway",
+			wantMessage: "unexpected status code: 502",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			worker := newTestWorker(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			var artifact bytes.Buffer
+			_, err := worker.callService(context.Background(), &serviceRequest{user: &database.User{}, query: url.Values{}}, &artifact)
+			assert.Equal(t, &serviceError{statusCode: tt.statusCode, message: tt.wantMessage}, err)
+			assert.Empty(t, artifact.String())
+		})
+	}
+}
+
+func TestWorker_callService_FailsWhenServiceFailsAfterStartingResponse(t *testing.T) {
+	worker := newTestWorker(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("Group name\n"))
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"success":false,"message":"Internal server error"}`))
+	})
+
+	var artifact bytes.Buffer
+	_, err := worker.callService(context.Background(), &serviceRequest{user: &database.User{}, query: url.Values{}}, &artifact)
+	assert.Equal(t, &serviceError{statusCode: http.StatusOK, message: "Internal server error"}, err)
+}
+
+func TestWorker_callPagedService(t *testing.T) {
+	var queries []url.Values
+	worker := newTestWorker(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		rowsCount := activityLogPageSize
+		if len(queries) > 1 {
+			rowsCount = 1
+		}
+		rows := make([]string, 0, rowsCount)
+		for index := 0; index < rowsCount; index++ {
+			rows = append(rows, fmt.Sprintf(
+				`{"activity_type":"submission","attempt_id":"%d","from_answer_id":"%d","participant":{"id":"3"},"item":{"id":"4"}}`,
+				len(queries), index))
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(rows, ",") + "]"))
+	})
+
+	var artifact bytes.Buffer
+	header, err := worker.callPagedService(context.Background(), &serviceRequest{
+		user: &database.User{}, query: url.Values{"limit": {"1000"}},
+	}, &artifact)
+	require.NoError(t, err)
+	assert.Equal(t, "application/x-ndjson", header.Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=activity_log.ndjson", header.Get("Content-Disposition"))
+
+	lines := strings.Split(strings.TrimSuffix(artifact.String(), "\n"), "\n")
+	require.Len(t, lines, activityLogPageSize+1)
+	assert.Equal(t,
+		`{"activity_type":"submission","attempt_id":"2","from_answer_id":"0","participant":{"id":"3"},"item":{"id":"4"}}`,
+		lines[activityLogPageSize])
+
+	require.Len(t, queries, 2)
+	assert.Equal(t, url.Values{"limit": {"1000"}}, queries[0])
+	assert.Equal(t, url.Values{
+		"limit": {"1000"}, "from.activity_type": {"submission"}, "from.attempt_id": {"1"}, "from.answer_id": {"999"},
+		"from.participant_id": {"3"}, "from.item_id": {"4"},
+	}, queries[1])
+}
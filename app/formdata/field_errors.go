@@ -1,8 +1,51 @@
 package formdata
 
+import (
+	"encoding/json"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
+)
+
 // FieldErrors represents multiple errors for form fields.
-type FieldErrors map[string][]string
+// The errors having messages of the catalogue are i18n.LocalizableError, so they can be translated.
+type FieldErrors map[string][]error
+
+var _ i18n.LocalizableError = FieldErrors(nil)
+
+const invalidInputDataCode = "invalid_input_data"
 
 func (e FieldErrors) Error() string {
-	return "invalid input data"
+	return e.Localize(i18n.DefaultLanguage)
+}
+
+// Code returns the stable code of the error.
+func (e FieldErrors) Code() string {
+	return invalidInputDataCode
+}
+
+// Localize returns the message of the error in the given language.
+func (e FieldErrors) Localize(lang string) string {
+	message, _ := i18n.Localize(invalidInputDataCode, lang)
+	return message
+}
+
+// LocalizeFields returns the messages of the errors of fields in the given language.
+func (e FieldErrors) LocalizeFields(lang string) map[string][]string {
+	result := make(map[string][]string, len(e))
+	for field, fieldErrors := range e {
+		result[field] = make([]string, 0, len(fieldErrors))
+		for _, fieldError := range fieldErrors {
+			if localizableError, ok := fieldError.(i18n.LocalizableError); ok {
+				result[field] = append(result[field], localizableError.Localize(lang))
+				continue
+			}
+			result[field] = append(result[field], fieldError.Error())
+		}
+	}
+	return result
+}
+
+// MarshalJSON returns the messages of the errors of fields in the default language encoded in JSON.
+func (e FieldErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.LocalizeFields(i18n.DefaultLanguage))
 }
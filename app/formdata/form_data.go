@@ -3,6 +3,7 @@ package formdata
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -21,6 +22,7 @@ import (
 	"github.com/jinzhu/gorm"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 )
 
 // FormData can parse JSON, validate it and construct a map for updating DB.
@@ -35,6 +37,8 @@ type FormData struct {
 
 	validate *validator.Validate
 	trans    ut.Translator
+	// codes of the messages of the catalogue by validation tags (see fieldErrorOfValidatorError)
+	translationCodes map[string]string
 }
 
 const (
@@ -73,6 +77,10 @@ func NewFormData(definitionStructure interface{}) *FormData {
 		definitionStructure: definitionStructure,
 		validate:            validate,
 		trans:               trans,
+		// the default translations of the validator having messages in the catalogue
+		translationCodes: map[string]string{
+			"oneof": "one_of", "min": "min_number", "gte": "min_number", "max": "max_number", "lte": "max_number",
+		},
 	}
 
 	// Register global custom validations
@@ -82,17 +90,17 @@ func NewFormData(definitionStructure interface{}) *FormData {
 		path := formData.getUsedKeysPathFromValidatorPath(fl.Path())
 		return formData.usedKeys[path]
 	})
-	formData.RegisterTranslation(set, "missing field")
+	formData.registerCatalogueTranslation(set, "missing_field")
 
 	// This one is needed to check if the field is null
 	formData.RegisterValidation(null, formData.ValidatorSkippingUnsetFields(validateNull))
-	formData.RegisterTranslation(null, "should be null")
+	formData.registerCatalogueTranslation(null, "should_be_null")
 
 	formData.RegisterValidation("duration", validateDuration)
-	formData.RegisterTranslation("duration", "invalid duration")
+	formData.registerCatalogueTranslation("duration", "invalid_duration")
 
 	formData.RegisterValidation("dmy-date", validateDMYDate)
-	formData.RegisterTranslation("dmy-date", "should be dd-mm-yyyy")
+	formData.registerCatalogueTranslation("dmy-date", "invalid_dmy_date")
 
 	return formData
 }
@@ -104,6 +112,19 @@ func (f *FormData) RegisterValidation(tag string, fn validator.Func) {
 
 // RegisterTranslation registers translations against the provided tag.
 func (f *FormData) RegisterTranslation(tag, text string) {
+	delete(f.translationCodes, tag)
+	f.registerTranslation(tag, text)
+}
+
+// registerCatalogueTranslation registers the message of the catalogue having the given code
+// as the translation of the provided tag.
+func (f *FormData) registerCatalogueTranslation(tag, code string) {
+	text, _ := i18n.Localize(code, i18n.DefaultLanguage)
+	f.registerTranslation(tag, text)
+	f.translationCodes[tag] = code
+}
+
+func (f *FormData) registerTranslation(tag, text string) {
 	_ = f.validate.RegisterTranslation(tag, f.trans,
 		func(ut ut.Translator) (err error) {
 			err = ut.Add(tag, text, true) // Silently override the translation
@@ -215,6 +236,9 @@ func (f *FormData) ValidatorSkippingUnchangedFields(nestedValidator validator.Fu
 var (
 	mapstructTypeErrorRegexp     = regexp.MustCompile(`^'([^']*)'\s+(.*)$`)
 	mapstructDecodingErrorRegexp = regexp.MustCompile(`^error decoding '([^']*)':\s+(.*)$`)
+
+	mapstructUnexpectedTypeRegexp = regexp.MustCompile(`^expected type '([^']*)', got unconvertible type '([^']*)'$`)
+	mapstructNullRegexp           = regexp.MustCompile(`^should not be null \(expected type: (.*)\)$`)
 )
 
 func (f *FormData) decodeRequestJSONDataIntoStruct(r *http.Request) error {
@@ -222,7 +246,7 @@ func (f *FormData) decodeRequestJSONDataIntoStruct(r *http.Request) error {
 	defer func() { _, _ = io.Copy(ioutil.Discard, r.Body) }()
 	err := json.NewDecoder(r.Body).Decode(&rawData)
 	if err != nil {
-		return fmt.Errorf("invalid input JSON: %v", err)
+		return i18n.NewError("invalid_input_json", err)
 	}
 	f.decodeMapIntoStruct(rawData)
 	return nil
@@ -261,17 +285,17 @@ func (f *FormData) decodeMapIntoStruct(m map[string]interface{}) {
 				copy(key, matches[1])
 				value := make([]byte, len(matches[2]))
 				copy(value, matches[2])
-				f.fieldErrors[string(key)] = append(f.fieldErrors[string(key)], string(value))
+				f.fieldErrors[string(key)] = append(f.fieldErrors[string(key)], fieldErrorOfMapstructTypeError(string(value)))
 				f.usedKeys[string(key)] = true
 				f.decodeErrors[string(key)] = true
 			} else if matches := mapstructDecodingErrorRegexp.FindStringSubmatch(fieldErrorString); len(matches) > 0 {
 				key := make([]byte, len(matches[1]))
 				copy(key, matches[1])
-				f.fieldErrors[string(key)] = append(f.fieldErrors[string(key)], "decoding error: "+matches[2])
+				f.fieldErrors[string(key)] = append(f.fieldErrors[string(key)], errors.New("decoding error: "+matches[2]))
 				f.usedKeys[string(key)] = true
 				f.decodeErrors[string(key)] = true
 			} else {
-				f.fieldErrors[""] = append(f.fieldErrors[""], fieldErrorString) // should never happen
+				f.fieldErrors[""] = append(f.fieldErrors[""], errors.New(fieldErrorString)) // should never happen
 			}
 		}
 	}
@@ -289,10 +313,47 @@ func (f *FormData) processValidatorErrors(err error) {
 		path := validatorError.Namespace()
 		path = f.getUsedKeysPathFromValidatorPath(path)
 		if (f.usedKeys[path] || validatorError.Tag() == set) && !f.decodeErrors[path] {
-			errorMsg := validatorError.Translate(f.trans)
-			f.fieldErrors[path] = append(f.fieldErrors[path], errorMsg)
+			f.fieldErrors[path] = append(f.fieldErrors[path], f.fieldErrorOfValidatorError(validatorError))
+		}
+	}
+}
+
+// fieldErrorOfValidatorError returns an i18n.Error for the validator's errors having messages of the catalogue,
+// or an error with the translated message otherwise.
+func (f *FormData) fieldErrorOfValidatorError(validatorError validator.FieldError) error {
+	code, ok := f.translationCodes[validatorError.Tag()]
+	switch {
+	case !ok:
+	case code == "one_of":
+		return i18n.NewError(code, validatorError.Field(), validatorError.Param())
+	case code == "min_number" || code == "max_number":
+		if isNumberKind(validatorError) {
+			return i18n.NewError(code, validatorError.Field(), validatorError.Param())
 		}
+	default:
+		return i18n.NewError(code)
+	}
+	return errors.New(validatorError.Translate(f.trans))
+}
+
+func isNumberKind(validatorError validator.FieldError) bool {
+	kind := validatorError.Kind()
+	if kind == reflect.Ptr {
+		kind = validatorError.Type().Elem().Kind()
+	}
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// fieldErrorOfMapstructTypeError returns an i18n.Error for mapstructure's type errors having messages of the catalogue,
+// or an error with the message of mapstructure otherwise.
+func fieldErrorOfMapstructTypeError(message string) error {
+	if matches := mapstructUnexpectedTypeRegexp.FindStringSubmatch(message); matches != nil {
+		return i18n.NewError("unexpected_type", matches[1], matches[2])
+	}
+	if matches := mapstructNullRegexp.FindStringSubmatch(message); matches != nil {
+		return i18n.NewError("should_not_be_null", matches[1])
 	}
+	return errors.New(message)
 }
 
 func (f *FormData) getUsedKeysPathFromValidatorPath(path string) string {
@@ -319,7 +380,7 @@ func (f *FormData) getUsedKeysPathFromValidatorPath(path string) string {
 func (f *FormData) checkProvidedFields() {
 	if !f.allowUnknownFields {
 		for _, unusedKey := range f.metadata.Unused {
-			f.fieldErrors[unusedKey] = append(f.fieldErrors[unusedKey], "unexpected field")
+			f.fieldErrors[unusedKey] = append(f.fieldErrors[unusedKey], i18n.NewError("unexpected_field"))
 		}
 	}
 	for _, usedKey := range f.metadata.Keys {
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 )
 
 type NamedStruct struct {
@@ -24,7 +25,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 		definitionStructure interface{}
 		json                string
 		wantErr             string
-		wantFieldErrors     formdata.FieldErrors
+		wantFieldErrors     map[string][]string
 	}{
 		{
 			"simple",
@@ -50,7 +51,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"id":"123"}`,
 			"invalid input data",
-			formdata.FieldErrors{"id": {"expected type 'int32', got unconvertible type 'string'"}},
+			map[string][]string{"id": {"expected type 'int32', got unconvertible type 'string'"}},
 		},
 		{
 			"null value for a not-null field",
@@ -60,7 +61,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"id":null, "name":null}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":   {"should not be null (expected type: int64)"},
 				"name": {"should not be null (expected type: string)"},
 			},
@@ -72,7 +73,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"my_id":"123"}`,
 			"invalid input data",
-			formdata.FieldErrors{"my_id": {"unexpected field"}},
+			map[string][]string{"my_id": {"unexpected field"}},
 		},
 		{
 			"field ignored by json",
@@ -81,7 +82,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"Name":"test"}`,
 			"invalid input data",
-			formdata.FieldErrors{"Name": {"unexpected field"}},
+			map[string][]string{"Name": {"unexpected field"}},
 		},
 		{
 			"decoder error for a field",
@@ -90,7 +91,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"time":"123"}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"time": {"decoding error: parsing time \"123\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"123\" as \"2006\""},
 			},
 		},
@@ -101,7 +102,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"my_id":1, "id":null}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"my_id": {"unexpected field"},
 			},
 		},
@@ -120,7 +121,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"id":null, "struct":{"other_struct":null, "OtherStruct2": null}}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":          {"unexpected field"},
 				"struct.name": {"missing field"},
 			},
@@ -140,7 +141,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"id":null, "struct":{"name1": "my name"}}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":           {"unexpected field"},
 				"struct.name":  {"missing field"},
 				"struct.name2": {"missing field"},
@@ -155,7 +156,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"name": ""}}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"name": {"name must be at least 1 character in length"},
 			},
 		},
@@ -174,7 +175,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"id":null, "struct":{"name":null, "other_struct":{}, "other_struct2":{}}}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":                        {"unexpected field"},
 				"struct.other_struct.name":  {"missing field"},
 				"struct.other_struct2.name": {"missing field"},
@@ -215,7 +216,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":   {"missing field"},
 				"name": {"missing field"},
 			},
@@ -228,7 +229,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"id":0, "name":""}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":   {"id must be 1 or greater"},
 				"name": {"name must be at least 1 character in length"},
 			},
@@ -241,7 +242,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"id":0, "name":""}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":   {"id must be 1 or greater"},
 				"name": {"name must be at least 1 character in length"},
 			},
@@ -254,7 +255,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"id":null, "name":null}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":   {"id must be 1 or greater"},
 				"name": {"name must be at least 1 character in length"},
 			},
@@ -275,7 +276,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"id":1234}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id": {"should be null"},
 			},
 		},
@@ -293,7 +294,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			&NamedStruct{},
 			`{"id":0,"name":""}`,
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":   {"id must be 1 or greater"},
 				"name": {"name must be at least 1 character in length"},
 			},
@@ -305,7 +306,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"field":"value"}`,
 			"invalid input data",
-			formdata.FieldErrors{"": {"field: unsupported type: chan"}},
+			map[string][]string{"": {"field: unsupported type: chan"}},
 		},
 		{
 			"custom error messages",
@@ -315,7 +316,7 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}{},
 			`{"date":"value","duration":"another value"}`,
 			"invalid input data",
-			formdata.FieldErrors{"date": {"should be dd-mm-yyyy"}, "duration": {"invalid duration"}},
+			map[string][]string{"date": {"should be dd-mm-yyyy"}, "duration": {"invalid duration"}},
 		},
 	}
 	for _, tt := range tests {
@@ -334,7 +335,9 @@ func TestFormData_ParseJSONRequestData(t *testing.T) {
 			}
 			if tt.wantFieldErrors != nil {
 				assert.IsType(t, formdata.FieldErrors{}, err)
-				assert.Equal(t, tt.wantFieldErrors, err)
+				if fieldErrors, ok := err.(formdata.FieldErrors); ok {
+					assert.Equal(t, tt.wantFieldErrors, fieldErrors.LocalizeFields(i18n.DefaultLanguage))
+				}
 			}
 		})
 	}
@@ -346,7 +349,7 @@ func TestFormData_ParseMapData(t *testing.T) {
 		definitionStructure interface{}
 		sourceMap           map[string]interface{}
 		wantErr             string
-		wantFieldErrors     formdata.FieldErrors
+		wantFieldErrors     map[string][]string
 	}{
 		{
 			"simple",
@@ -365,7 +368,7 @@ func TestFormData_ParseMapData(t *testing.T) {
 			}{},
 			map[string]interface{}{"id": "123"},
 			"invalid input data",
-			formdata.FieldErrors{"id": {"expected type 'int32', got unconvertible type 'string'"}},
+			map[string][]string{"id": {"expected type 'int32', got unconvertible type 'string'"}},
 		},
 		{
 			"null value for a not-null field",
@@ -375,7 +378,7 @@ func TestFormData_ParseMapData(t *testing.T) {
 			}{},
 			map[string]interface{}{"id": nil, "name": nil},
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":   {"should not be null (expected type: int64)"},
 				"name": {"should not be null (expected type: string)"},
 			},
@@ -387,7 +390,7 @@ func TestFormData_ParseMapData(t *testing.T) {
 			}{},
 			map[string]interface{}{"my_id": "123"},
 			"invalid input data",
-			formdata.FieldErrors{"my_id": {"unexpected field"}},
+			map[string][]string{"my_id": {"unexpected field"}},
 		},
 		{
 			"field ignored by json",
@@ -396,7 +399,7 @@ func TestFormData_ParseMapData(t *testing.T) {
 			}{},
 			map[string]interface{}{"Name": "test"},
 			"invalid input data",
-			formdata.FieldErrors{"Name": {"unexpected field"}},
+			map[string][]string{"Name": {"unexpected field"}},
 		},
 		{
 			"decoder error for a field",
@@ -405,7 +408,7 @@ func TestFormData_ParseMapData(t *testing.T) {
 			}{},
 			map[string]interface{}{"time": "123"},
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"time": {"decoding error: parsing time \"123\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"123\" as \"2006\""},
 			},
 		},
@@ -416,7 +419,7 @@ func TestFormData_ParseMapData(t *testing.T) {
 			}{},
 			map[string]interface{}{"my_id": 1, "id": nil},
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"my_id": {"unexpected field"},
 			},
 		},
@@ -435,7 +438,7 @@ func TestFormData_ParseMapData(t *testing.T) {
 			}{},
 			map[string]interface{}{"id": nil, "struct": map[string]interface{}{"other_struct": nil, "other_struct2": nil}},
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":          {"unexpected field"},
 				"struct.name": {"missing field"},
 			},
@@ -466,7 +469,7 @@ func TestFormData_ParseMapData(t *testing.T) {
 				},
 			},
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id": {"unexpected field"},
 			},
 		},
@@ -488,7 +491,7 @@ func TestFormData_ParseMapData(t *testing.T) {
 			}{},
 			map[string]interface{}{"id": 0, "name": ""},
 			"invalid input data",
-			formdata.FieldErrors{
+			map[string][]string{
 				"id":   []string{"id must be 1 or greater"},
 				"name": []string{"name must be at least 1 character in length"},
 			},
@@ -500,7 +503,7 @@ func TestFormData_ParseMapData(t *testing.T) {
 			}{},
 			map[string]interface{}{"field": "value"},
 			"invalid input data",
-			formdata.FieldErrors{"": {"field: unsupported type: chan"}},
+			map[string][]string{"": {"field: unsupported type: chan"}},
 		},
 	}
 	for _, tt := range tests {
@@ -518,7 +521,9 @@ func TestFormData_ParseMapData(t *testing.T) {
 			}
 			if tt.wantFieldErrors != nil {
 				assert.IsType(t, formdata.FieldErrors{}, err)
-				assert.Equal(t, tt.wantFieldErrors, err)
+				if fieldErrors, ok := err.(formdata.FieldErrors); ok {
+					assert.Equal(t, tt.wantFieldErrors, fieldErrors.LocalizeFields(i18n.DefaultLanguage))
+				}
 			}
 		})
 	}
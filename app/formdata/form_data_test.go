@@ -21,7 +21,7 @@ func TestFormData_IsSet(t *testing.T) {
 }
 
 func TestFormData_IsValid(t *testing.T) {
-	formData := &FormData{fieldErrors: FieldErrors{"fieldWithErrors": []string{"someError"}}}
+	formData := &FormData{fieldErrors: FieldErrors{"fieldWithErrors": []error{errors.New("someError")}}}
 	assert.True(t, formData.IsValid("someField"))
 	assert.False(t, formData.IsValid("fieldWithErrors"))
 }
@@ -72,10 +72,32 @@ func TestFormData_RegisterTranslation_SetsArgumentsForErrorMessages(t *testing.T
 	f.RegisterTranslation("test", "failed for field %[2]s with parameter %[3]s (tag=%[1]s)")
 	err := f.ParseMapData(map[string]interface{}{"id": int64(1)})
 	assert.Equal(t, err, FieldErrors{
-		"ID": []string{"failed for field ID with parameter value (tag=test)"},
+		"ID": []error{errors.New("failed for field ID with parameter value (tag=test)")},
 	})
 }
 
+func TestFormData_LocalizesFieldErrorsOfTheCatalogue(t *testing.T) {
+	f := NewFormData(&struct {
+		ID    int64  `json:"id" validate:"min=1"`
+		Name  string `json:"name" validate:"set"`
+		Type  string `json:"type" validate:"oneof=a b"`
+		Login string `json:"login" validate:"max=1"`
+		Text  string `json:"text" validate:"set"`
+	}{})
+	f.RegisterTranslation("set", "is required")
+	err := f.ParseMapData(map[string]interface{}{"id": int64(0), "type": "c", "login": "ab", "unknown": 1})
+	assert.IsType(t, FieldErrors{}, err)
+	assert.Equal(t, "données invalides", err.(FieldErrors).Localize("fr"))
+	assert.Equal(t, map[string][]string{
+		"id":      {"id doit être supérieur ou égal à 1"},
+		"name":    {"is required"},
+		"type":    {"type doit être l'une des valeurs [a b]"},
+		"login":   {"login must be a maximum of 1 character in length"},
+		"text":    {"is required"},
+		"unknown": {"champ inattendu"},
+	}, err.(FieldErrors).LocalizeFields("fr"))
+}
+
 func TestFormData_ValidatorSkippingUnsetFields(t *testing.T) {
 	f := NewFormData(&struct {
 		ID     *int64 `validate:"test"`
@@ -87,12 +109,13 @@ func TestFormData_ValidatorSkippingUnsetFields(t *testing.T) {
 		return false
 	}))
 	f.RegisterTranslation("test", "failed")
+	failed := []error{errors.New("failed")}
 	err := f.ParseMapData(map[string]interface{}{})
 	assert.Nil(t, err)
 	err = f.ParseMapData(map[string]interface{}{"id": nil, "nested": nil})
-	assert.Equal(t, FieldErrors{"ID": []string{"failed"}, "Nested": []string{"failed"}}, err)
+	assert.Equal(t, FieldErrors{"ID": failed, "Nested": failed}, err)
 	err = f.ParseMapData(map[string]interface{}{"id": nil, "nested": map[string]interface{}{"id": 1}})
-	assert.Equal(t, FieldErrors{"ID": []string{"failed"}, "Nested": []string{"failed"}, "Nested.ID": []string{"failed"}}, err)
+	assert.Equal(t, FieldErrors{"ID": failed, "Nested": failed, "Nested.ID": failed}, err)
 }
 
 func TestFormData_ValidatorSkippingUnchangedFields(t *testing.T) {
@@ -109,6 +132,7 @@ func TestFormData_ValidatorSkippingUnchangedFields(t *testing.T) {
 		return false
 	}))
 	f.RegisterTranslation("test", "failed")
+	failed := []error{errors.New("failed")}
 
 	err := f.ParseMapData(map[string]interface{}{})
 	assert.Nil(t, err)
@@ -116,19 +140,19 @@ func TestFormData_ValidatorSkippingUnchangedFields(t *testing.T) {
 	err = f.ParseMapData(map[string]interface{}{})
 	assert.Nil(t, err)
 	err = f.ParseMapData(map[string]interface{}{"id": nil, "nested": map[string]interface{}{"id": 1}})
-	assert.Equal(t, FieldErrors{"Nested": []string{"failed"}, "Nested.ID": []string{"failed"}}, err)
+	assert.Equal(t, FieldErrors{"Nested": failed, "Nested.ID": failed}, err)
 	i := int64(10)
 	j := int64(20)
 	f.SetOldValues(&testStruct{ID: &i, Nested: &nestedStruct{ID: &j}})
 	err = f.ParseMapData(map[string]interface{}{})
 	assert.Nil(t, err)
 	err = f.ParseMapData(map[string]interface{}{"id": nil, "nested": map[string]interface{}{"id": nil}})
-	assert.Equal(t, FieldErrors{"ID": []string{"failed"}, "Nested": []string{"failed"}, "Nested.ID": []string{"failed"}}, err)
+	assert.Equal(t, FieldErrors{"ID": failed, "Nested": failed, "Nested.ID": failed}, err)
 	err = f.ParseMapData(map[string]interface{}{"id": 10, "nested": map[string]interface{}{"id": 20}})
-	assert.Equal(t, FieldErrors{"Nested": []string{"failed"}}, err)
+	assert.Equal(t, FieldErrors{"Nested": failed}, err)
 	f.SetOldValues(nil)
 	err = f.ParseMapData(map[string]interface{}{"id": 10, "nested": map[string]interface{}{"id": 20}})
-	assert.Equal(t, FieldErrors{"ID": []string{"failed"}, "Nested": []string{"failed"}, "Nested.ID": []string{"failed"}}, err)
+	assert.Equal(t, FieldErrors{"ID": failed, "Nested": failed, "Nested.ID": failed}, err)
 }
 
 func Test_stringToDatabaseTimeUTCHookFunc(t *testing.T) {
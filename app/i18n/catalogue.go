@@ -0,0 +1,204 @@
+package i18n
+
+// catalogue contains the messages of the API in the supported languages by their stable codes.
+// The messages are format strings receiving the arguments of errors (see Error),
+// translations may reorder the arguments with explicit argument indexes (like `%[2]s`).
+var catalogue = map[string]map[string]string{
+	// HTTP statuses
+	"bad_request": {
+		"en": "Bad Request",
+		"fr": "Requête invalide",
+		"de": "Ungültige Anfrage",
+		"ar": "طلب غير صالح",
+	},
+	"unauthorized": {
+		"en": "Unauthorized",
+		"fr": "Non authentifié",
+		"de": "Nicht authentifiziert",
+		"ar": "غير مصرح",
+	},
+	"forbidden": {
+		"en": "Forbidden",
+		"fr": "Accès interdit",
+		"de": "Zugriff verweigert",
+		"ar": "ممنوع",
+	},
+	"not_found": {
+		"en": "Not Found",
+		"fr": "Introuvable",
+		"de": "Nicht gefunden",
+		"ar": "غير موجود",
+	},
+	"request_timeout": {
+		"en": "Request Timeout",
+		"fr": "Délai d'attente dépassé",
+		"de": "Zeitüberschreitung der Anfrage",
+		"ar": "انتهت مهلة الطلب",
+	},
+	"conflict": {
+		"en": "Conflict",
+		"fr": "Conflit",
+		"de": "Konflikt",
+		"ar": "تعارض",
+	},
+	"unprocessable_entity": {
+		"en": "Unprocessable Entity",
+		"fr": "Requête impossible à traiter",
+		"de": "Nicht verarbeitbare Anfrage",
+		"ar": "طلب غير قابل للمعالجة",
+	},
+	"too_many_requests": {
+		"en": "Too Many Requests",
+		"fr": "Trop de requêtes",
+		"de": "Zu viele Anfragen",
+		"ar": "طلبات كثيرة جدًا",
+	},
+	"internal_server_error": {
+		"en": "Internal Server Error",
+		"fr": "Erreur interne du serveur",
+		"de": "Interner Serverfehler",
+		"ar": "خطأ داخلي في الخادم",
+	},
+
+	// Errors
+	"invalid_input_data": {
+		"en": "invalid input data",
+		"fr": "données invalides",
+		"de": "ungültige Eingabedaten",
+		"ar": "بيانات الإدخال غير صالحة",
+	},
+	"invalid_input_json": {
+		"en": "invalid input JSON: %v",
+		"fr": "JSON invalide : %v",
+		"de": "ungültiges JSON: %v",
+		"ar": "JSON غير صالح: %v",
+	},
+	"insufficient_access_rights": {
+		"en": "insufficient access rights",
+		"fr": "droits d'accès insuffisants",
+		"de": "unzureichende Zugriffsrechte",
+		"ar": "صلاحيات الوصول غير كافية",
+	},
+	"wrong_int64_value": {
+		"en": "wrong value for %s (should be int64)",
+		"fr": "valeur incorrecte pour %s (un entier est attendu)",
+		"de": "falscher Wert für %s (eine ganze Zahl wird erwartet)",
+		"ar": "قيمة خاطئة لـ %s (يجب أن تكون عددًا صحيحًا)",
+	},
+	"wrong_boolean_value": {
+		"en": "wrong value for %s (should have a boolean value (0 or 1))",
+		"fr": "valeur incorrecte pour %s (0 ou 1 est attendu)",
+		"de": "falscher Wert für %s (0 oder 1 wird erwartet)",
+		"ar": "قيمة خاطئة لـ %s (يجب أن تكون 0 أو 1)",
+	},
+	"wrong_time_value": {
+		"en": "wrong value for %s (should be time (rfc3339Nano))",
+		"fr": "valeur incorrecte pour %s (une date au format RFC 3339 est attendue)",
+		"de": "falscher Wert für %s (ein Zeitpunkt im Format RFC 3339 wird erwartet)",
+		"ar": "قيمة خاطئة لـ %s (يجب أن تكون وقتًا بتنسيق RFC 3339)",
+	},
+	"wrong_ids_list": {
+		"en": "unable to parse one of the integers given as query args (value: '%s', param: '%s')",
+		"fr": "impossible de lire l'un des entiers du paramètre %[2]s (valeur : '%[1]s')",
+		"de": "eine der ganzen Zahlen im Parameter %[2]s kann nicht gelesen werden (Wert: '%[1]s')",
+		"ar": "تعذرت قراءة أحد الأعداد الصحيحة في المعامل %[2]s (القيمة: '%[1]s')",
+	},
+	"too_many_values": {
+		"en": "no more than %d %s expected",
+		"fr": "pas plus de %d valeurs attendues pour %s",
+		"de": "höchstens %d Werte für %s erwartet",
+		"ar": "لا يُتوقع أكثر من %d قيم لـ %s",
+	},
+	"unallowed_sorting_field": {
+		"en": "unallowed field in sorting parameters: %q",
+		"fr": "champ non autorisé dans les paramètres de tri : %q",
+		"de": "unzulässiges Feld in den Sortierparametern: %q",
+		"ar": "حقل غير مسموح به في معاملات الترتيب: %q",
+	},
+	"no_rights_to_watch_group": {
+		"en": "no rights to watch for watched_group_id",
+		"fr": "pas de droit d'observer le groupe watched_group_id",
+		"de": "keine Berechtigung, die Gruppe watched_group_id zu beobachten",
+		"ar": "لا توجد صلاحية لمراقبة المجموعة watched_group_id",
+	},
+	"invalid_team": {
+		"en": "can't use given as_team_id as a user's team",
+		"fr": "le paramètre as_team_id n'est pas une équipe de l'utilisateur",
+		"de": "der Parameter as_team_id ist kein Team des Benutzers",
+		"ar": "المعامل as_team_id ليس فريقًا للمستخدم",
+	},
+	"both_team_and_watched_group": {
+		"en": "only one of as_team_id and watched_group_id can be given",
+		"fr": "les paramètres as_team_id et watched_group_id ne peuvent pas être donnés ensemble",
+		"de": "die Parameter as_team_id und watched_group_id können nicht zusammen angegeben werden",
+		"ar": "لا يمكن تحديد as_team_id و watched_group_id معًا",
+	},
+
+	// Errors of fields
+	"missing_field": {
+		"en": "missing field",
+		"fr": "champ manquant",
+		"de": "fehlendes Feld",
+		"ar": "حقل مفقود",
+	},
+	"missing_parameter": {
+		"en": "missing %s",
+		"fr": "paramètre %s manquant",
+		"de": "Parameter %s fehlt",
+		"ar": "المعامل %s مفقود",
+	},
+	"unexpected_field": {
+		"en": "unexpected field",
+		"fr": "champ inattendu",
+		"de": "unerwartetes Feld",
+		"ar": "حقل غير متوقع",
+	},
+	"should_be_null": {
+		"en": "should be null",
+		"fr": "doit être null",
+		"de": "muss null sein",
+		"ar": "يجب أن يكون null",
+	},
+	"should_not_be_null": {
+		"en": "should not be null (expected type: %s)",
+		"fr": "ne doit pas être null (type attendu : %s)",
+		"de": "darf nicht null sein (erwarteter Typ: %s)",
+		"ar": "يجب ألا يكون null (النوع المتوقع: %s)",
+	},
+	"unexpected_type": {
+		"en": "expected type '%s', got unconvertible type '%s'",
+		"fr": "type '%s' attendu, type '%s' reçu",
+		"de": "Typ '%s' erwartet, Typ '%s' erhalten",
+		"ar": "النوع المتوقع '%s'، النوع المستلم '%s'",
+	},
+	"invalid_duration": {
+		"en": "invalid duration",
+		"fr": "durée invalide",
+		"de": "ungültige Dauer",
+		"ar": "مدة غير صالحة",
+	},
+	"invalid_dmy_date": {
+		"en": "should be dd-mm-yyyy",
+		"fr": "doit être au format jj-mm-aaaa",
+		"de": "muss im Format TT-MM-JJJJ sein",
+		"ar": "يجب أن يكون بالتنسيق dd-mm-yyyy",
+	},
+	"min_number": {
+		"en": "%s must be %s or greater",
+		"fr": "%s doit être supérieur ou égal à %s",
+		"de": "%s muss größer oder gleich %s sein",
+		"ar": "يجب أن يكون %s أكبر من أو يساوي %s",
+	},
+	"max_number": {
+		"en": "%s must be %s or less",
+		"fr": "%s doit être inférieur ou égal à %s",
+		"de": "%s muss kleiner oder gleich %s sein",
+		"ar": "يجب أن يكون %s أصغر من أو يساوي %s",
+	},
+	"one_of": {
+		"en": "%s must be one of [%s]",
+		"fr": "%s doit être l'une des valeurs [%s]",
+		"de": "%s muss einer der Werte [%s] sein",
+		"ar": "يجب أن يكون %s إحدى القيم [%s]",
+	},
+}
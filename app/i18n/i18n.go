@@ -0,0 +1,109 @@
+// Package i18n provides the localization of messages returned by the API
+// (a catalogue of messages keyed by stable codes, errors carrying the codes, and the negotiation of the language).
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is the language of messages when no supported language is requested.
+// The messages are written in this language in the code.
+const DefaultLanguage = "en"
+
+// SupportedLanguages lists the languages of the catalogue.
+var SupportedLanguages = []string{DefaultLanguage, "fr", "de", "ar"}
+
+// Localize returns the message of the catalogue having the given code in the given language
+// (in the default language if the message is not translated) formatted with the given arguments.
+// The second result is false if there is no such message in the catalogue.
+func Localize(code, lang string, args ...interface{}) (string, bool) {
+	texts, ok := catalogue[code]
+	if !ok {
+		return "", false
+	}
+	text, ok := texts[lang]
+	if !ok {
+		text = texts[DefaultLanguage]
+	}
+	return fmt.Sprintf(text, args...), true
+}
+
+// LocalizableError is an error having a stable code and a message which can be translated.
+type LocalizableError interface {
+	error
+	Code() string
+	Localize(lang string) string
+}
+
+// Error is an error with a message of the catalogue. It carries the stable code of the message
+// and the arguments of the message so that the message can be translated.
+type Error struct {
+	code string
+	args []interface{}
+}
+
+var _ LocalizableError = (*Error)(nil)
+
+// NewError creates an error with the message of the catalogue having the given code.
+// It panics if there is no such message as this can only be caused by bugs in the code.
+func NewError(code string, args ...interface{}) *Error {
+	if _, ok := catalogue[code]; !ok {
+		panic(fmt.Errorf("no message with code %q in the catalogue", code))
+	}
+	return &Error{code: code, args: args}
+}
+
+// Error returns the message of the error in the default language.
+func (e *Error) Error() string {
+	return e.Localize(DefaultLanguage)
+}
+
+// Code returns the stable code of the error.
+func (e *Error) Code() string {
+	return e.code
+}
+
+// Localize returns the message of the error in the given language.
+func (e *Error) Localize(lang string) string {
+	message, _ := Localize(e.code, lang, e.args...)
+	return message
+}
+
+// Negotiate returns the supported language best matching the value of the Accept-Language header.
+// If the header requests no supported language, the first supported language among the fallback ones
+// (like the user's default language) is returned, or the default language if there is none.
+func Negotiate(acceptLanguage string, fallbackLanguages ...string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err == nil {
+		for _, tag := range tags {
+			if supportedLanguage, ok := supportedBaseLanguage(tag); ok {
+				return supportedLanguage
+			}
+		}
+	}
+	for _, fallbackLanguage := range fallbackLanguages {
+		tag, err := language.Parse(fallbackLanguage)
+		if err != nil {
+			continue
+		}
+		if supportedLanguage, ok := supportedBaseLanguage(tag); ok {
+			return supportedLanguage
+		}
+	}
+	return DefaultLanguage
+}
+
+func supportedBaseLanguage(tag language.Tag) (string, bool) {
+	base, confidence := tag.Base()
+	if confidence == language.No {
+		return "", false
+	}
+	for _, supportedLanguage := range SupportedLanguages {
+		if base.String() == supportedLanguage {
+			return supportedLanguage, true
+		}
+	}
+	return "", false
+}
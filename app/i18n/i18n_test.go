@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalize(t *testing.T) {
+	tests := []struct {
+		name             string
+		code             string
+		language         string
+		args             []interface{}
+		wantLocalization string
+		wantOK           bool
+	}{
+		{
+			name: "without arguments", code: "insufficient_access_rights", language: "fr",
+			wantLocalization: "droits d'accès insuffisants", wantOK: true,
+		},
+		{
+			name: "with an argument", code: "wrong_int64_value", language: "de", args: []interface{}{"item_id"},
+			wantLocalization: "falscher Wert für item_id (eine ganze Zahl wird erwartet)", wantOK: true,
+		},
+		{
+			name: "with reordered arguments", code: "wrong_ids_list", language: "fr", args: []interface{}{"a", "ids"},
+			wantLocalization: "impossible de lire l'un des entiers du paramètre ids (valeur : 'a')", wantOK: true,
+		},
+		{
+			name: "the default language", code: "missing_parameter", language: DefaultLanguage, args: []interface{}{"item_id"},
+			wantLocalization: "missing item_id", wantOK: true,
+		},
+		{
+			name: "unsupported language", code: "too_many_values", language: "es", args: []interface{}{10, "ids"},
+			wantLocalization: "no more than 10 ids expected", wantOK: true,
+		},
+		{name: "unknown code", code: "unknown", language: "fr"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			localization, ok := Localize(tt.code, tt.language, tt.args...)
+			assert.Equal(t, tt.wantLocalization, localization)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	err := NewError("unallowed_sorting_field", "name")
+
+	assert.Equal(t, "unallowed_sorting_field", err.Code())
+	assert.EqualError(t, err, `unallowed field in sorting parameters: "name"`)
+	assert.Equal(t, `champ non autorisé dans les paramètres de tri : "name"`, err.Localize("fr"))
+}
+
+func TestNewError_PanicsOnUnknownCode(t *testing.T) {
+	assert.PanicsWithError(t, `no message with code "unknown" in the catalogue`, func() { NewError("unknown") })
+}
+
+func TestCatalogue_HasAllTheSupportedLanguages(t *testing.T) {
+	for code, texts := range catalogue {
+		assert.Len(t, texts, len(SupportedLanguages), "code %q", code)
+		for _, language := range SupportedLanguages {
+			assert.NotEmpty(t, texts[language], "code %q, language %q", code, language)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name              string
+		acceptLanguage    string
+		fallbackLanguages []string
+		want              string
+	}{
+		{name: "exact match", acceptLanguage: "fr", want: "fr"},
+		{name: "regional variant", acceptLanguage: "de-CH", want: "de"},
+		{name: "by quality", acceptLanguage: "es, ar;q=0.5, fr;q=0.7", want: "fr"},
+		{name: "fallback", acceptLanguage: "es", fallbackLanguages: []string{"ar"}, want: "ar"},
+		{name: "empty header", fallbackLanguages: []string{"de"}, want: "de"},
+		{name: "invalid header", acceptLanguage: ";;;", fallbackLanguages: []string{"fr"}, want: "fr"},
+		{name: "unsupported fallback", acceptLanguage: "es", fallbackLanguages: []string{"xx", "it", "fr"}, want: "fr"},
+		{name: "nothing supported", acceptLanguage: "es", fallbackLanguages: []string{"it"}, want: DefaultLanguage},
+		{name: "header goes first", acceptLanguage: "en", fallbackLanguages: []string{"fr"}, want: "en"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Negotiate(tt.acceptLanguage, tt.fallbackLanguages...))
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/go-chi/chi/middleware"
 	"github.com/sirupsen/logrus" //nolint:depguard
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Logger is wrapper around a logger and keeping the logging config so that it can be reused by other loggers.
@@ -105,6 +106,10 @@ func (l *Logger) WithContext(ctx context.Context) *logrus.Entry {
 		entry = entry.WithField("req_id", requestID)
 	}
 
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		entry = entry.WithFields(logrus.Fields{"trace_id": spanContext.TraceID().String(), "span_id": spanContext.SpanID().String()})
+	}
+
 	return entry
 }
 
@@ -13,6 +13,7 @@ import (
 	"github.com/sirupsen/logrus" //nolint:depguard
 	"github.com/spf13/viper"
 	assertlib "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestGlobal(t *testing.T) {
@@ -185,3 +186,20 @@ func TestLogger_Configure_LevelInvalid(t *testing.T) {
 	logger.Configure(conf)
 	assert.Equal(logrus.InfoLevel, logger.logrusLogger.Level)
 }
+
+func TestLogger_WithContext_AddsTraceIDs(t *testing.T) {
+	assert := assertlib.New(t)
+	logger, hook := NewMockLogger()
+
+	logger.WithContext(context.Background()).Info("without span")
+	assert.NotContains(hook.LastEntry().Data, "trace_id")
+	assert.NotContains(hook.LastEntry().Data, "span_id")
+
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	ctx := trace.ContextWithSpanContext(context.Background(),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled}))
+	logger.WithContext(ctx).Info("with span")
+	assert.Equal("0102030405060708090a0b0c0d0e0f10", hook.LastEntry().Data["trace_id"])
+	assert.Equal("0102030405060708", hook.LastEntry().Data["span_id"])
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/tracing"
 )
 
 // A Client is the login module client.
@@ -39,7 +40,7 @@ func (client *Client) GetUserProfile(ctx context.Context, accessToken string) (p
 	mustNotBeError(err)
 	request.Header.Set("Authorization", "Bearer "+accessToken)
 	request = request.WithContext(ctx)
-	response, err := http.DefaultClient.Do(request)
+	response, err := tracing.HTTPClient.Do(request)
 	mustNotBeError(err)
 	body, err := ioutil.ReadAll(io.LimitReader(response.Body, 1<<20)) // 1Mb
 	_ = response.Body.Close()
@@ -181,7 +182,7 @@ func (client *Client) requestAccountsManagerAndDecode(ctx context.Context, urlPa
 	mustNotBeError(err)
 	request = request.WithContext(ctx)
 	request.Header.Add("Content-Type", "application/json")
-	response, err := http.DefaultClient.Do(request)
+	response, err := tracing.HTTPClient.Do(request)
 	mustNotBeError(err)
 	responseBody, err := ioutil.ReadAll(io.LimitReader(response.Body, 1<<20)) // 1Mb
 	_ = response.Body.Close()
@@ -0,0 +1,119 @@
+package lti
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// clientAssertionLifetime is the lifetime of the JWTs the tool authenticates with at the token endpoints of platforms.
+const clientAssertionLifetime = 5 * time.Minute
+
+// Score is a score of a user to be published to a line item of a platform.
+type Score struct {
+	// Subject identifies the user at the platform.
+	Subject      string
+	ScoreGiven   float32
+	ScoreMaximum float32
+	Timestamp    time.Time
+}
+
+// PublishScore publishes the score to the given line item of the platform (AGS).
+func PublishScore(
+	ctx context.Context, config *Config, platform *PlatformConfig, privateKey *rsa.PrivateKey, lineItemURL string, score *Score,
+) error {
+	accessToken, err := requestAccessToken(ctx, config, platform, privateKey, ScopeScore)
+	if err != nil {
+		return fmt.Errorf("can't get an access token of the LTI platform: %w", err)
+	}
+
+	scoresURL, err := scoresURLOfLineItem(lineItemURL)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"userId":           score.Subject,
+		"scoreGiven":       score.ScoreGiven,
+		"scoreMaximum":     score.ScoreMaximum,
+		"activityProgress": "Completed",
+		"gradingProgress":  "FullyGraded",
+		"timestamp":        score.Timestamp.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, scoresURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/vnd.ims.lis.v1.score+json")
+	request.Header.Set("Authorization", "Bearer "+accessToken.AccessToken)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		responseBody, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1<<10)) // 1Kb
+		return fmt.Errorf("can't publish the score to the LTI platform: unexpected status code %d (%s)",
+			response.StatusCode, responseBody)
+	}
+	return nil
+}
+
+// scoresURLOfLineItem returns the URL of the scores service of the line item
+// (the '/scores' path suffix is inserted before the query string).
+func scoresURLOfLineItem(lineItemURL string) (string, error) {
+	parsedURL, err := url.Parse(lineItemURL)
+	if err != nil {
+		return "", fmt.Errorf("wrong URL of the line item: %w", err)
+	}
+	parsedURL.Path += "/scores"
+	return parsedURL.String(), nil
+}
+
+// requestAccessToken requests an access token with the client-credentials grant
+// authenticating with a JWT signed by the tool (as required by the LTI security framework).
+func requestAccessToken(
+	ctx context.Context, config *Config, platform *PlatformConfig, privateKey *rsa.PrivateKey, scopes ...string,
+) (*oauth2.Token, error) {
+	jwtID, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	assertion, err := SignJWT(privateKey, config.KeyID, map[string]interface{}{
+		"iss": platform.ClientID,
+		"sub": platform.ClientID,
+		"aud": platform.AccessTokenURL,
+		"iat": now.Unix(),
+		"exp": now.Add(clientAssertionLifetime).Unix(),
+		"jti": jwtID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	credentialsConfig := &clientcredentials.Config{
+		TokenURL: platform.AccessTokenURL,
+		Scopes:   scopes,
+		EndpointParams: url.Values{
+			"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+			"client_assertion":      {assertion},
+		},
+		AuthStyle: oauth2.AuthStyleInParams,
+	}
+	return credentialsConfig.Token(ctx)
+}
@@ -0,0 +1,83 @@
+package lti
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidc"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidctest"
+)
+
+func TestPublishScore(t *testing.T) {
+	var publishedScore map[string]interface{}
+	var platform *httptest.Server
+	platform = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jwks":
+			_ = json.NewEncoder(w).Encode(KeySet(&oidctest.PrivateKey().PublicKey, "tool"))
+		case "/token":
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal(t, ScopeScore, r.PostForm.Get("scope"))
+			assert.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", r.PostForm.Get("client_assertion_type"))
+			claims, err := oidc.NewKeySet(platform.URL+"/jwks").VerifySignature(r.Context(), r.PostForm.Get("client_assertion"))
+			require.NoError(t, err)
+			assert.Equal(t, "client", claims["iss"])
+			assert.Equal(t, platform.URL+"/token", claims["aud"])
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"access_token": "agstoken", "token_type": "Bearer", "expires_in": 3600}`)
+		case "/lineitems/1/scores":
+			assert.Equal(t, "1", r.URL.Query().Get("type_id"))
+			assert.Equal(t, "Bearer agstoken", r.Header.Get("Authorization"))
+			assert.Equal(t, "application/vnd.ims.lis.v1.score+json", r.Header.Get("Content-Type"))
+			body, _ := ioutil.ReadAll(r.Body)
+			require.NoError(t, json.Unmarshal(body, &publishedScore))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer platform.Close()
+
+	config := &Config{KeyID: "tool"}
+	platformConfig := &PlatformConfig{Issuer: platform.URL, ClientID: "client", AccessTokenURL: platform.URL + "/token"}
+	timestamp := time.Date(2025, 2, 24, 10, 0, 0, 0, time.UTC)
+	err := PublishScore(context.Background(), config, platformConfig, oidctest.PrivateKey(),
+		platform.URL+"/lineitems/1?type_id=1", &Score{Subject: "user-1", ScoreGiven: 75, ScoreMaximum: 100, Timestamp: timestamp})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"userId":           "user-1",
+		"scoreGiven":       float64(75),
+		"scoreMaximum":     float64(100),
+		"activityProgress": "Completed",
+		"gradingProgress":  "FullyGraded",
+		"timestamp":        "2025-02-24T10:00:00Z",
+	}, publishedScore)
+}
+
+func TestPublishScore_FailsOnWrongStatusCode(t *testing.T) {
+	platform := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"access_token": "agstoken", "token_type": "Bearer"}`)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = fmt.Fprint(w, "forbidden")
+	}))
+	defer platform.Close()
+
+	err := PublishScore(context.Background(), &Config{KeyID: "tool"},
+		&PlatformConfig{ClientID: "client", AccessTokenURL: platform.URL + "/token"}, oidctest.PrivateKey(),
+		platform.URL+"/lineitems/1", &Score{Subject: "user-1"})
+	assert.EqualError(t, err, "can't publish the score to the LTI platform: unexpected status code 403 (forbidden)")
+}
@@ -0,0 +1,61 @@
+package lti
+
+import (
+	"crypto/rsa"
+	"strconv"
+	"time"
+)
+
+// deepLinkingResponseLifetime is the lifetime of deep linking responses signed by the tool.
+const deepLinkingResponseLifetime = 5 * time.Minute
+
+// ContentItem is an item selected for a deep linking response.
+type ContentItem struct {
+	ItemID int64
+	Title  string
+}
+
+// DeepLinkingResponse is the response of the tool to a deep linking request of a platform
+// (Data is the opaque value given in the request).
+type DeepLinkingResponse struct {
+	Platform     *PlatformConfig
+	DeploymentID string
+	Data         string
+	Items        []ContentItem
+}
+
+// Sign generates the signed JWT of the deep linking response to be posted to the return URL
+// of the request (as the 'JWT' form parameter). Each item becomes a resource link launching the tool
+// with the 'item_id' custom parameter.
+func (response *DeepLinkingResponse) Sign(config *Config, privateKey *rsa.PrivateKey) (string, error) {
+	contentItems := make([]map[string]interface{}, 0, len(response.Items))
+	for _, item := range response.Items {
+		contentItems = append(contentItems, map[string]interface{}{
+			"type":   "ltiResourceLink",
+			"title":  item.Title,
+			"url":    config.LaunchURL,
+			"custom": map[string]string{"item_id": strconv.FormatInt(item.ItemID, 10)},
+		})
+	}
+
+	nonce, err := randomString()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":             response.Platform.ClientID,
+		"aud":             response.Platform.Issuer,
+		"iat":             now.Unix(),
+		"exp":             now.Add(deepLinkingResponseLifetime).Unix(),
+		"nonce":           nonce,
+		ClaimMessageType:  MessageTypeDeepLinkingResponse,
+		ClaimVersion:      "1.3.0",
+		ClaimDeploymentID: response.DeploymentID,
+		ClaimContentItems: contentItems,
+	}
+	if response.Data != "" {
+		claims[ClaimDeepLinkingData] = response.Data
+	}
+	return SignJWT(privateKey, config.KeyID, claims)
+}
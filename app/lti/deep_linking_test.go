@@ -0,0 +1,46 @@
+package lti
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidc"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidctest"
+)
+
+func TestDeepLinkingResponse_Sign(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(KeySet(&oidctest.PrivateKey().PublicKey, "tool"))
+	}))
+	defer server.Close()
+
+	config := &Config{KeyID: "tool", LaunchURL: "https://backend.example.org/auth/lti/launch"}
+	platform := &PlatformConfig{Issuer: "https://moodle.example.org", ClientID: "client"}
+	response := &DeepLinkingResponse{
+		Platform: platform, DeploymentID: "1", Data: "opaque",
+		Items: []ContentItem{{ItemID: 1234, Title: "Chapter 1"}},
+	}
+	jwt, err := response.Sign(config, oidctest.PrivateKey())
+	require.NoError(t, err)
+
+	claims, err := oidc.NewKeySet(server.URL).VerifySignature(context.Background(), jwt)
+	require.NoError(t, err)
+	assert.Equal(t, "client", claims["iss"])
+	assert.Equal(t, "https://moodle.example.org", claims["aud"])
+	assert.Equal(t, MessageTypeDeepLinkingResponse, claims[ClaimMessageType])
+	assert.Equal(t, "1", claims[ClaimDeploymentID])
+	assert.Equal(t, "opaque", claims[ClaimDeepLinkingData])
+	assert.NotEmpty(t, claims["nonce"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"type":   "ltiResourceLink",
+		"title":  "Chapter 1",
+		"url":    "https://backend.example.org/auth/lti/launch",
+		"custom": map[string]interface{}{"item_id": "1234"},
+	}}, claims[ClaimContentItems])
+}
@@ -0,0 +1,182 @@
+package lti
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidc"
+)
+
+// Launch is a validated LTI 1.3 launch.
+type Launch struct {
+	MessageType  string
+	DeploymentID string
+	// Subject identifies the user at the platform.
+	Subject    string
+	GivenName  string
+	FamilyName string
+	Email      string
+	Roles      []string
+	// ContextID & ContextTitle describe the context (e.g., a course) the tool is launched from (may be empty).
+	ContextID    string
+	ContextTitle string
+	// Custom are the custom parameters of the resource link.
+	Custom map[string]string
+	// LineItemURL is the URL of the line item the scores of the user should be published to (may be empty).
+	LineItemURL string
+	// DeepLinking is given for deep linking requests only.
+	DeepLinking *DeepLinkingSettings
+}
+
+// DeepLinkingSettings are the settings of a deep linking request.
+type DeepLinkingSettings struct {
+	ReturnURL string
+	Data      string
+}
+
+// KeySets are the key sets of the platforms by their URLs. They are kept between launches
+// so that the keys are loaded once and reloaded only when a launch is signed with an unknown key.
+// A nil *KeySets creates a new key set for each launch.
+type KeySets struct {
+	mutex   sync.Mutex
+	keySets map[string]*oidc.KeySet
+}
+
+// NewKeySets creates an empty set of key sets.
+func NewKeySets() *KeySets {
+	return &KeySets{keySets: make(map[string]*oidc.KeySet)}
+}
+
+func (keySets *KeySets) platformKeySet(platform *PlatformConfig) *oidc.KeySet {
+	if keySets == nil {
+		return oidc.NewKeySet(platform.JWKSURL)
+	}
+
+	keySets.mutex.Lock()
+	defer keySets.mutex.Unlock()
+
+	keySet, ok := keySets.keySets[platform.JWKSURL]
+	if !ok {
+		keySet = oidc.NewKeySet(platform.JWKSURL)
+		keySets.keySets[platform.JWKSURL] = keySet
+	}
+	return keySet
+}
+
+// ValidateLaunch checks the signature (with the key set of the platform kept in keySets) and the claims
+// of a launch token posted by the platform (the id_token parameter) and parses the launch.
+func ValidateLaunch(ctx context.Context, keySets *KeySets, platform *PlatformConfig, rawIDToken, nonce string) (*Launch, error) {
+	claims, err := keySets.platformKeySet(platform).VerifySignature(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if err = oidc.ValidateClaims(claims, platform.Issuer, platform.ClientID); err != nil {
+		return nil, err
+	}
+	if claims["nonce"] != nonce {
+		return nil, errors.New("wrong nonce of the launch")
+	}
+	return parseLaunch(claims, platform)
+}
+
+func parseLaunch(claims map[string]interface{}, platform *PlatformConfig) (*Launch, error) {
+	if claims[ClaimVersion] != "1.3.0" {
+		return nil, fmt.Errorf("unsupported LTI version: %v", claims[ClaimVersion])
+	}
+
+	launch := &Launch{
+		MessageType:  stringClaim(claims, ClaimMessageType),
+		DeploymentID: stringClaim(claims, ClaimDeploymentID),
+		Subject:      stringClaim(claims, "sub"),
+		GivenName:    stringClaim(claims, "given_name"),
+		FamilyName:   stringClaim(claims, "family_name"),
+		Email:        stringClaim(claims, "email"),
+		Custom:       map[string]string{},
+	}
+	if !platform.HasDeployment(launch.DeploymentID) {
+		return nil, fmt.Errorf("unknown deployment of the tool: %q", launch.DeploymentID)
+	}
+
+	if roles, ok := claims[ClaimRoles].([]interface{}); ok {
+		for _, role := range roles {
+			if role, ok := role.(string); ok {
+				launch.Roles = append(launch.Roles, role)
+			}
+		}
+	}
+	if context, ok := claims[ClaimContext].(map[string]interface{}); ok {
+		launch.ContextID = stringClaim(context, "id")
+		launch.ContextTitle = stringClaim(context, "title")
+		if launch.ContextTitle == "" {
+			launch.ContextTitle = stringClaim(context, "label")
+		}
+	}
+	if custom, ok := claims[ClaimCustom].(map[string]interface{}); ok {
+		for key, value := range custom {
+			if value, ok := value.(string); ok {
+				launch.Custom[key] = value
+			}
+		}
+	}
+	if endpoint, ok := claims[ClaimAGSEndpoint].(map[string]interface{}); ok {
+		if scopes, ok := endpoint["scope"].([]interface{}); ok {
+			for _, scope := range scopes {
+				if scope == ScopeScore {
+					launch.LineItemURL = stringClaim(endpoint, "lineitem")
+				}
+			}
+		}
+	}
+
+	switch launch.MessageType {
+	case MessageTypeResourceLinkRequest:
+		if resourceLink, ok := claims[ClaimResourceLink].(map[string]interface{}); !ok || stringClaim(resourceLink, "id") == "" {
+			return nil, errors.New("no resource link in the launch")
+		}
+	case MessageTypeDeepLinkingRequest:
+		settings, ok := claims[ClaimDeepLinkingSettings].(map[string]interface{})
+		if !ok || stringClaim(settings, "deep_link_return_url") == "" {
+			return nil, errors.New("no deep linking settings in the launch")
+		}
+		launch.DeepLinking = &DeepLinkingSettings{
+			ReturnURL: stringClaim(settings, "deep_link_return_url"),
+			Data:      stringClaim(settings, "data"),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported message type: %q", launch.MessageType)
+	}
+	return launch, nil
+}
+
+// ItemID returns the ID of the item given in the 'item_id' custom parameter of the launch.
+// It returns false if the parameter is not given.
+func (launch *Launch) ItemID() (int64, bool, error) {
+	value, ok := launch.Custom["item_id"]
+	if !ok {
+		return 0, false, nil
+	}
+	itemID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("wrong value of the item_id custom parameter: %q", value)
+	}
+	return itemID, true, nil
+}
+
+// IsInstructor tells if the user has an instructor or administrator role in the context of the launch.
+func (launch *Launch) IsInstructor() bool {
+	for _, role := range launch.Roles {
+		if strings.HasSuffix(role, "membership#Instructor") || strings.HasSuffix(role, "membership#Administrator") {
+			return true
+		}
+	}
+	return false
+}
+
+func stringClaim(claims map[string]interface{}, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
@@ -0,0 +1,166 @@
+package lti
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidctest"
+)
+
+func launchClaims(issuer string) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":             issuer,
+		"sub":             "user-1",
+		"aud":             "client",
+		"exp":             time.Now().Add(time.Hour).Unix(),
+		"nonce":           "somenonce",
+		"given_name":      "John",
+		"family_name":     "Doe",
+		"email":           "jdoe@example.org",
+		ClaimMessageType:  MessageTypeResourceLinkRequest,
+		ClaimVersion:      "1.3.0",
+		ClaimDeploymentID: "1",
+		ClaimResourceLink: map[string]interface{}{"id": "link-1"},
+		ClaimContext:      map[string]interface{}{"id": "course-1", "label": "MATH101"},
+		ClaimRoles:        []string{"http://purl.imsglobal.org/vocab/lis/v2/membership#Instructor"},
+		ClaimCustom:       map[string]interface{}{"item_id": "1234"},
+		ClaimAGSEndpoint: map[string]interface{}{
+			"scope":    []string{ScopeScore},
+			"lineitem": "https://moodle.example.org/lineitems/1",
+		},
+	}
+}
+
+func TestValidateLaunch(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+	platform := &PlatformConfig{Issuer: server.URL, ClientID: "client", DeploymentIDs: []string{"1"}, JWKSURL: server.URL + "/jwks"}
+
+	launch, err := ValidateLaunch(context.Background(), nil, platform, oidctest.SignIDToken(launchClaims(server.URL)), "somenonce")
+	require.NoError(t, err)
+	assert.Equal(t, &Launch{
+		MessageType:  MessageTypeResourceLinkRequest,
+		DeploymentID: "1",
+		Subject:      "user-1",
+		GivenName:    "John",
+		FamilyName:   "Doe",
+		Email:        "jdoe@example.org",
+		Roles:        []string{"http://purl.imsglobal.org/vocab/lis/v2/membership#Instructor"},
+		ContextID:    "course-1",
+		ContextTitle: "MATH101",
+		Custom:       map[string]string{"item_id": "1234"},
+		LineItemURL:  "https://moodle.example.org/lineitems/1",
+	}, launch)
+	assert.True(t, launch.IsInstructor())
+	itemID, ok, err := launch.ItemID()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1234), itemID)
+}
+
+func TestValidateLaunch_DeepLinkingRequest(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+	platform := &PlatformConfig{Issuer: server.URL, ClientID: "client", DeploymentIDs: []string{"1"}, JWKSURL: server.URL + "/jwks"}
+
+	claims := launchClaims(server.URL)
+	claims[ClaimMessageType] = MessageTypeDeepLinkingRequest
+	delete(claims, ClaimResourceLink)
+	claims[ClaimDeepLinkingSettings] = map[string]interface{}{
+		"deep_link_return_url": "https://moodle.example.org/deeplinking", "data": "opaque",
+	}
+	launch, err := ValidateLaunch(context.Background(), nil, platform, oidctest.SignIDToken(claims), "somenonce")
+	require.NoError(t, err)
+	assert.Equal(t, &DeepLinkingSettings{ReturnURL: "https://moodle.example.org/deeplinking", Data: "opaque"}, launch.DeepLinking)
+}
+
+func TestValidateLaunch_ReusesTheKeySetOfThePlatform(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+	platform := &PlatformConfig{Issuer: server.URL, ClientID: "client", DeploymentIDs: []string{"1"}, JWKSURL: server.URL + "/jwks"}
+	keySets := NewKeySets()
+
+	for i := 0; i < 2; i++ {
+		_, err := ValidateLaunch(context.Background(), keySets, platform, oidctest.SignIDToken(launchClaims(server.URL)), "somenonce")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, server.JWKSRequests())
+
+	// a token signed with an unknown key makes the key set reload
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT","kid":"rotated"}`))
+	rawIDToken := oidctest.SignIDToken(launchClaims(server.URL))
+	rawIDToken = header + rawIDToken[strings.Index(rawIDToken, "."):]
+	_, err := ValidateLaunch(context.Background(), keySets, platform, rawIDToken, "somenonce")
+	assert.EqualError(t, err, `unknown key of the ID token: "rotated"`)
+	assert.Equal(t, 2, server.JWKSRequests())
+
+	// without a set of key sets, the keys are loaded for each launch
+	_, err = ValidateLaunch(context.Background(), nil, platform, oidctest.SignIDToken(launchClaims(server.URL)), "somenonce")
+	require.NoError(t, err)
+	assert.Equal(t, 3, server.JWKSRequests())
+}
+
+func TestValidateLaunch_Errors(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+	platform := &PlatformConfig{Issuer: server.URL, ClientID: "client", DeploymentIDs: []string{"1"}, JWKSURL: server.URL + "/jwks"}
+
+	tests := []struct {
+		name    string
+		changes map[string]interface{}
+		nonce   string
+		wantErr string
+	}{
+		{name: "wrong nonce", nonce: "othernonce", wantErr: "wrong nonce of the launch"},
+		{name: "wrong audience", changes: map[string]interface{}{"aud": "other"}, wantErr: "the ID token is not issued for this client"},
+		{name: "wrong version", changes: map[string]interface{}{ClaimVersion: "1.1"}, wantErr: "unsupported LTI version: 1.1"},
+		{
+			name: "unknown deployment", changes: map[string]interface{}{ClaimDeploymentID: "2"},
+			wantErr: `unknown deployment of the tool: "2"`,
+		},
+		{
+			name: "unsupported message type", changes: map[string]interface{}{ClaimMessageType: "LtiSubmissionReviewRequest"},
+			wantErr: `unsupported message type: "LtiSubmissionReviewRequest"`,
+		},
+		{name: "no resource link", changes: map[string]interface{}{ClaimResourceLink: nil}, wantErr: "no resource link in the launch"},
+		{
+			name:    "no deep linking settings",
+			changes: map[string]interface{}{ClaimMessageType: MessageTypeDeepLinkingRequest},
+			wantErr: "no deep linking settings in the launch",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			claims := launchClaims(server.URL)
+			for key, value := range tt.changes {
+				if value == nil {
+					delete(claims, key)
+				} else {
+					claims[key] = value
+				}
+			}
+			nonce := "somenonce"
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+			_, err := ValidateLaunch(context.Background(), nil, platform, oidctest.SignIDToken(claims), nonce)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLaunch_ItemID(t *testing.T) {
+	_, ok, err := (&Launch{Custom: map[string]string{}}).ItemID()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = (&Launch{Custom: map[string]string{"item_id": "abc"}}).ItemID()
+	assert.EqualError(t, err, `wrong value of the item_id custom parameter: "abc"`)
+}
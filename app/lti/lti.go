@@ -0,0 +1,110 @@
+// Package lti implements the tool side of LTI 1.3: the validation of launches signed by platforms,
+// deep linking responses, and the publication of scores with the Assignment and Grade Services (AGS).
+package lti
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Claims of LTI 1.3 messages.
+const (
+	ClaimMessageType         = "https://purl.imsglobal.org/spec/lti/claim/message_type"
+	ClaimVersion             = "https://purl.imsglobal.org/spec/lti/claim/version"
+	ClaimDeploymentID        = "https://purl.imsglobal.org/spec/lti/claim/deployment_id"
+	ClaimTargetLinkURI       = "https://purl.imsglobal.org/spec/lti/claim/target_link_uri"
+	ClaimResourceLink        = "https://purl.imsglobal.org/spec/lti/claim/resource_link"
+	ClaimContext             = "https://purl.imsglobal.org/spec/lti/claim/context"
+	ClaimRoles               = "https://purl.imsglobal.org/spec/lti/claim/roles"
+	ClaimCustom              = "https://purl.imsglobal.org/spec/lti/claim/custom"
+	ClaimDeepLinkingSettings = "https://purl.imsglobal.org/spec/lti-dl/claim/deep_linking_settings"
+	ClaimContentItems        = "https://purl.imsglobal.org/spec/lti-dl/claim/content_items"
+	ClaimDeepLinkingData     = "https://purl.imsglobal.org/spec/lti-dl/claim/data"
+	ClaimAGSEndpoint         = "https://purl.imsglobal.org/spec/lti-ags/claim/endpoint"
+)
+
+// Message types of LTI 1.3 launches.
+const (
+	MessageTypeResourceLinkRequest = "LtiResourceLinkRequest"
+	MessageTypeDeepLinkingRequest  = "LtiDeepLinkingRequest"
+	MessageTypeDeepLinkingResponse = "LtiDeepLinkingResponse"
+)
+
+// ScopeScore is the AGS scope allowing the tool to publish scores.
+const ScopeScore = "https://purl.imsglobal.org/spec/lti-ags/scope/score"
+
+// Config is the configuration of the backend as an LTI 1.3 tool.
+type Config struct {
+	// KeyID is the identifier of the key of the tool in its key set.
+	KeyID string
+	// LaunchURL is the URL of the launch service of the tool registered at the platforms (the redirect URI).
+	LaunchURL string
+	// FrontendURL is the URL of the frontend users are redirected to after a launch.
+	FrontendURL string
+	// Platforms are the LTI platforms the tool is registered at.
+	Platforms []PlatformConfig
+}
+
+// PlatformConfig is the registration of the tool at an LTI platform.
+type PlatformConfig struct {
+	Issuer   string
+	ClientID string
+	// DeploymentIDs are the identifiers of the deployments of the tool accepted from the platform.
+	DeploymentIDs []string
+	// AuthLoginURL is the OpenID Connect authentication endpoint of the platform.
+	AuthLoginURL string
+	// AccessTokenURL is the OAuth2 token endpoint of the platform (used for AGS).
+	AccessTokenURL string
+	// JWKSURL is the URL of the key set the platform signs its messages with.
+	JWKSURL string
+}
+
+// Validate checks that the configuration of the tool is complete.
+func (config *Config) Validate() error {
+	if config.LaunchURL == "" {
+		return errors.New("no launch URL is configured for LTI")
+	}
+	if config.FrontendURL == "" {
+		return errors.New("no frontend URL is configured for LTI")
+	}
+	for index := range config.Platforms {
+		platform := &config.Platforms[index]
+		if platform.Issuer == "" || platform.ClientID == "" || platform.AuthLoginURL == "" || platform.JWKSURL == "" {
+			return fmt.Errorf("the LTI platform #%d should have an issuer, a client ID, an auth login URL, and a JWKS URL", index+1)
+		}
+	}
+	return nil
+}
+
+// Platform returns the configuration of the platform with the given issuer and client ID.
+// The client ID may be omitted if the tool is registered only once at the platform.
+// It returns nil if there is no such platform.
+func (config *Config) Platform(issuer, clientID string) *PlatformConfig {
+	var found *PlatformConfig
+	for index := range config.Platforms {
+		platform := &config.Platforms[index]
+		if platform.Issuer != issuer {
+			continue
+		}
+		if platform.ClientID == clientID {
+			return platform
+		}
+		if clientID == "" {
+			if found != nil {
+				return nil // ambiguous
+			}
+			found = platform
+		}
+	}
+	return found
+}
+
+// HasDeployment tells if the given deployment of the tool is accepted from the platform.
+func (platform *PlatformConfig) HasDeployment(deploymentID string) bool {
+	for _, id := range platform.DeploymentIDs {
+		if id == deploymentID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package lti
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Platform(t *testing.T) {
+	config := &Config{Platforms: []PlatformConfig{
+		{Issuer: "https://moodle.example.org", ClientID: "client1"},
+		{Issuer: "https://moodle.example.org", ClientID: "client2"},
+		{Issuer: "https://canvas.example.org", ClientID: "client3"},
+	}}
+
+	assert.Equal(t, &config.Platforms[1], config.Platform("https://moodle.example.org", "client2"))
+	assert.Equal(t, &config.Platforms[2], config.Platform("https://canvas.example.org", ""))
+	assert.Nil(t, config.Platform("https://moodle.example.org", ""))
+	assert.Nil(t, config.Platform("https://moodle.example.org", "client3"))
+	assert.Nil(t, config.Platform("https://unknown.example.org", "client1"))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	config := &Config{
+		LaunchURL: "https://backend.example.org/auth/lti/launch", FrontendURL: "https://app.example.org",
+		Platforms: []PlatformConfig{{
+			Issuer: "https://moodle.example.org", ClientID: "client", AuthLoginURL: "https://moodle.example.org/auth",
+			JWKSURL: "https://moodle.example.org/certs",
+		}},
+	}
+	assert.NoError(t, config.Validate())
+
+	config.Platforms[0].JWKSURL = ""
+	assert.EqualError(t, config.Validate(),
+		"the LTI platform #1 should have an issuer, a client ID, an auth login URL, and a JWKS URL")
+
+	assert.EqualError(t, (&Config{}).Validate(), "no launch URL is configured for LTI")
+	assert.EqualError(t, (&Config{LaunchURL: config.LaunchURL}).Validate(), "no frontend URL is configured for LTI")
+}
+
+func TestPlatformConfig_HasDeployment(t *testing.T) {
+	platform := &PlatformConfig{DeploymentIDs: []string{"1", "2"}}
+	assert.True(t, platform.HasDeployment("2"))
+	assert.False(t, platform.HasDeployment("3"))
+}
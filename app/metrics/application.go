@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DefaultDurationBuckets are the default upper bounds (in seconds) of the buckets of duration histograms.
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PropagationDurationBuckets are the upper bounds (in seconds) of the buckets of the durations of propagation steps.
+var PropagationDurationBuckets = []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// The metrics of the application.
+var (
+	HTTPRequests = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "algorea_http_requests_total",
+		Help: "Number of processed HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+	HTTPRequestDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "algorea_http_request_duration_seconds",
+		Help:    "Duration of processing of HTTP requests by method and route pattern.",
+		Buckets: DefaultDurationBuckets,
+	}, []string{"method", "route"})
+
+	DBQueries = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "algorea_db_queries_total",
+		Help: "Number of SQL queries by operation (exec or query) and status (ok or error).",
+	}, []string{"operation", "status"})
+	DBQueryDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "algorea_db_query_duration_seconds",
+		Help:    "Duration of SQL queries by operation (exec or query).",
+		Buckets: DefaultDurationBuckets,
+	}, []string{"operation"})
+	DBTransactionRetries = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "algorea_db_transaction_retries_total",
+		Help: "Number of retried transactions by reason (deadlock or lock_wait_timeout).",
+	}, []string{"reason"})
+
+	PropagationStepDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "algorea_propagation_step_duration_seconds",
+		Help:    "Duration of propagation steps.",
+		Buckets: PropagationDurationBuckets,
+	}, []string{"step"})
+)
+
+// unmatchedRoute is the route label of requests not matching any route.
+const unmatchedRoute = "unmatched"
+
+// Middleware counts the HTTP requests and measures their durations by route pattern.
+// It should go before the recoverer so that panics are counted as internal errors.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+		wrappedWriter := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(wrappedWriter, r)
+
+		route := unmatchedRoute
+		if routeContext, ok := r.Context().Value(chi.RouteCtxKey).(*chi.Context); ok {
+			if pattern := routeContext.RoutePattern(); pattern != "" && pattern != "/*" {
+				route = pattern
+			}
+		}
+		status := wrappedWriter.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		HTTPRequests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		HTTPRequestDuration.WithLabelValues(r.Method, route).Observe(time.Since(startTime).Seconds())
+	})
+}
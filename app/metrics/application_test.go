@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/metrics/metricstest"
+)
+
+func TestMiddleware(t *testing.T) {
+	subRouter := chi.NewRouter()
+	subRouter.Get("/items/{item_id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	subRouter.Post("/answers", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	router := chi.NewRouter()
+	router.Use(Middleware)
+	router.Mount("/", subRouter)
+
+	initialForbidden := testutil.ToFloat64(HTTPRequests.WithLabelValues("GET", "/items/{item_id}", "403"))
+	initialOK := testutil.ToFloat64(HTTPRequests.WithLabelValues("POST", "/answers", "200"))
+	initialUnmatched := testutil.ToFloat64(HTTPRequests.WithLabelValues("GET", "unmatched", "404"))
+	initialDurations := metricstest.HistogramCount(HTTPRequestDuration.WithLabelValues("GET", "/items/{item_id}"))
+
+	for _, request := range []*http.Request{
+		httptest.NewRequest("GET", "/items/12", http.NoBody),
+		httptest.NewRequest("GET", "/items/34", http.NoBody),
+		httptest.NewRequest("POST", "/answers", http.NoBody),
+		httptest.NewRequest("GET", "/unknown", http.NoBody),
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	assert.Equal(t, initialForbidden+2, testutil.ToFloat64(HTTPRequests.WithLabelValues("GET", "/items/{item_id}", "403")))
+	assert.Equal(t, initialOK+1, testutil.ToFloat64(HTTPRequests.WithLabelValues("POST", "/answers", "200")))
+	assert.Equal(t, initialUnmatched+1, testutil.ToFloat64(HTTPRequests.WithLabelValues("GET", "unmatched", "404")))
+	assert.Equal(t, initialDurations+2, metricstest.HistogramCount(HTTPRequestDuration.WithLabelValues("GET", "/items/{item_id}")))
+}
@@ -0,0 +1,48 @@
+// Package metrics collects metrics of the application and exposes them in the Prometheus exposition format.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry is the registry of the metrics of the application.
+var Registry = prometheus.NewRegistry()
+
+// PropagationBacklogTables are the propagation tables whose sizes are exposed as backlogs.
+var PropagationBacklogTables = []string{"results_propagate", "permissions_propagate"}
+
+var propagationBacklogDesc = prometheus.NewDesc("algorea_propagation_backlog",
+	"Number of rows waiting in the propagation tables (results_propagate, permissions_propagate) when metrics are scraped.",
+	[]string{"table"}, nil)
+
+type propagationBacklogCollector struct {
+	countRows func(table string) (int64, error)
+}
+
+// NewPropagationBacklogCollector creates a collector of the sizes of the propagation tables.
+// The rows of each table are counted with countRows on each scrape, the tables failing to be counted are skipped.
+func NewPropagationBacklogCollector(countRows func(table string) (int64, error)) prometheus.Collector {
+	return &propagationBacklogCollector{countRows: countRows}
+}
+
+func (c *propagationBacklogCollector) Describe(descriptions chan<- *prometheus.Desc) {
+	descriptions <- propagationBacklogDesc
+}
+
+func (c *propagationBacklogCollector) Collect(metrics chan<- prometheus.Metric) {
+	for _, table := range PropagationBacklogTables {
+		if count, err := c.countRows(table); err == nil {
+			metrics <- prometheus.MustNewConstMetric(propagationBacklogDesc, prometheus.GaugeValue, float64(count), table)
+		}
+	}
+}
+
+// Handler serves the metrics of the application along with the metrics of the given collectors.
+func Handler(collectors ...prometheus.Collector) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors...)
+	return promhttp.HandlerFor(prometheus.Gatherers{Registry, registry}, promhttp.HandlerOpts{})
+}
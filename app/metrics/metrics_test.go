@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	HTTPRequests.WithLabelValues("GET", "/items/{item_id}", "200").Inc()
+	var countedTables []string
+	handler := Handler(NewPropagationBacklogCollector(func(table string) (int64, error) {
+		countedTables = append(countedTables, table)
+		if table == "permissions_propagate" {
+			return 0, errors.New("error")
+		}
+		return 12, nil
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "# TYPE algorea_http_requests_total counter\n")
+	assert.Contains(t, recorder.Body.String(), "# HELP algorea_propagation_backlog Number of rows waiting in the propagation tables "+
+		"(results_propagate, permissions_propagate) when metrics are scraped.\n"+
+		"# TYPE algorea_propagation_backlog gauge\n"+
+		"algorea_propagation_backlog{table=\"results_propagate\"} 12\n")
+	assert.NotContains(t, recorder.Body.String(), "permissions_propagate\"}")
+	assert.Equal(t, []string{"results_propagate", "permissions_propagate"}, countedTables)
+}
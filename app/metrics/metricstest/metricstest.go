@@ -0,0 +1,19 @@
+//go:build !prod
+
+// Package metricstest provides helpers to check metrics in tests.
+package metricstest
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// HistogramCount returns the number of observations of the given histogram
+// (as returned by the WithLabelValues method of a histogram vector).
+func HistogramCount(histogram prometheus.Observer) uint64 {
+	var metric dto.Metric
+	if err := histogram.(prometheus.Metric).Write(&metric); err != nil {
+		panic(err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,173 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
+)
+
+type messageTexts struct {
+	subject string
+	body    string
+}
+
+// texts contains the templates of the messages keyed by the type of notifications and the language.
+// The templates are executed with the payload of a notification (and `status_label` for thread notifications).
+var texts = map[string]map[string]messageTexts{
+	database.EmailNotificationTypeInvitationCreated: {
+		"en": {
+			subject: `Invitation to join the group "{{.group_name}}"`,
+			body: "You have been invited to join the group \"{{.group_name}}\".\n\n" +
+				"Log in to the platform to accept or reject the invitation.",
+		},
+		"fr": {
+			subject: `Invitation à rejoindre le groupe « {{.group_name}} »`,
+			body: "Vous avez été invité(e) à rejoindre le groupe « {{.group_name}} ».\n\n" +
+				"Connectez-vous à la plateforme pour accepter ou refuser l'invitation.",
+		},
+		"de": {
+			subject: `Einladung in die Gruppe „{{.group_name}}“`,
+			body: "Sie wurden eingeladen, der Gruppe „{{.group_name}}“ beizutreten.\n\n" +
+				"Melden Sie sich auf der Plattform an, um die Einladung anzunehmen oder abzulehnen.",
+		},
+		"ar": {
+			subject: `دعوة للانضمام إلى المجموعة "{{.group_name}}"`,
+			body: "تمت دعوتك للانضمام إلى المجموعة \"{{.group_name}}\".\n\n" +
+				"سجّل الدخول إلى المنصة لقبول الدعوة أو رفضها.",
+		},
+	},
+	database.EmailNotificationTypeJoinRequestAccepted: {
+		"en": {
+			subject: `Your request to join "{{.group_name}}" has been accepted`,
+			body:    "Your request to join the group \"{{.group_name}}\" has been accepted. You are now a member of the group.",
+		},
+		"fr": {
+			subject: `Votre demande pour rejoindre « {{.group_name}} » a été acceptée`,
+			body: "Votre demande pour rejoindre le groupe « {{.group_name}} » a été acceptée. " +
+				"Vous êtes désormais membre du groupe.",
+		},
+		"de": {
+			subject: `Ihre Beitrittsanfrage für „{{.group_name}}“ wurde angenommen`,
+			body: "Ihre Anfrage, der Gruppe „{{.group_name}}“ beizutreten, wurde angenommen. " +
+				"Sie sind jetzt Mitglied der Gruppe.",
+		},
+		"ar": {
+			subject: `تم قبول طلب انضمامك إلى "{{.group_name}}"`,
+			body:    "تم قبول طلبك للانضمام إلى المجموعة \"{{.group_name}}\". أنت الآن عضو في المجموعة.",
+		},
+	},
+	database.EmailNotificationTypeThreadStatusChanged: {
+		"en": {
+			subject: `Help thread on "{{.item_title}}": {{.status_label}}`,
+			body:    "The status of the help thread on \"{{.item_title}}\" has been changed to \"{{.status_label}}\".",
+		},
+		"fr": {
+			subject: `Fil d'aide sur « {{.item_title}} » : {{.status_label}}`,
+			body:    "Le statut du fil d'aide sur « {{.item_title}} » est désormais « {{.status_label}} ».",
+		},
+		"de": {
+			subject: `Hilfe-Thread zu „{{.item_title}}“: {{.status_label}}`,
+			body:    "Der Status des Hilfe-Threads zu „{{.item_title}}“ wurde in „{{.status_label}}“ geändert.",
+		},
+		"ar": {
+			subject: `سلسلة المساعدة حول "{{.item_title}}": {{.status_label}}`,
+			body:    "تم تغيير حالة سلسلة المساعدة حول \"{{.item_title}}\" إلى \"{{.status_label}}\".",
+		},
+	},
+	database.EmailNotificationTypeMembershipExpiring: {
+		"en": {
+			subject: `Your membership in "{{.group_name}}" expires soon`,
+			body:    "Your membership in the group \"{{.group_name}}\" expires on {{.expires_at}} (UTC).",
+		},
+		"fr": {
+			subject: `Votre adhésion à « {{.group_name}} » expire bientôt`,
+			body:    "Votre adhésion au groupe « {{.group_name}} » expire le {{.expires_at}} (UTC).",
+		},
+		"de": {
+			subject: `Ihre Mitgliedschaft in „{{.group_name}}“ läuft bald ab`,
+			body:    "Ihre Mitgliedschaft in der Gruppe „{{.group_name}}“ läuft am {{.expires_at}} (UTC) ab.",
+		},
+		"ar": {
+			subject: `عضويتك في "{{.group_name}}" ستنتهي قريبًا`,
+			body:    "ستنتهي عضويتك في المجموعة \"{{.group_name}}\" في {{.expires_at}} (UTC).",
+		},
+	},
+}
+
+// footers are appended to the bodies of all the messages.
+var footers = map[string]string{
+	"en": "You can stop receiving this kind of email in your profile settings.",
+	"fr": "Vous pouvez désactiver ce type d'e-mails dans les paramètres de votre profil.",
+	"de": "Sie können diese Art von E-Mails in Ihren Profileinstellungen abbestellen.",
+	"ar": "يمكنك إيقاف تلقي هذا النوع من الرسائل من إعدادات ملفك الشخصي.",
+}
+
+var threadStatusLabels = map[string]map[string]string{
+	"waiting_for_participant": {
+		"en": "waiting for the participant", "fr": "en attente du participant",
+		"de": "wartet auf den Teilnehmer", "ar": "في انتظار المشارك",
+	},
+	"waiting_for_trainer": {
+		"en": "waiting for a trainer", "fr": "en attente d'un tuteur",
+		"de": "wartet auf einen Betreuer", "ar": "في انتظار مدرب",
+	},
+	"closed": {
+		"en": "closed", "fr": "fermé", "de": "geschlossen", "ar": "مغلقة",
+	},
+}
+
+type compiledTexts struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+var compiledTemplates = compileTemplates()
+
+func compileTemplates() map[string]map[string]compiledTexts {
+	result := make(map[string]map[string]compiledTexts, len(texts))
+	for notificationType, textsByLanguage := range texts {
+		result[notificationType] = make(map[string]compiledTexts, len(textsByLanguage))
+		for language, messageTexts := range textsByLanguage {
+			name := notificationType + "." + language
+			result[notificationType][language] = compiledTexts{
+				subject: template.Must(template.New(name + ".subject").Option("missingkey=error").Parse(messageTexts.subject)),
+				body: template.Must(template.New(name + ".body").Option("missingkey=error").
+					Parse(messageTexts.body + "\n\n-- \n" + footers[language] + "\n")),
+			}
+		}
+	}
+	return result
+}
+
+// Render renders the subject & the body of a notification of the given type in the supported language
+// best matching the given one (the default language of the recipient).
+func Render(notificationType, language string, payload map[string]string) (subject, body string, err error) {
+	templatesByLanguage, ok := compiledTemplates[notificationType]
+	if !ok {
+		return "", "", fmt.Errorf("unknown type of notifications: %q", notificationType)
+	}
+	language = i18n.Negotiate("", language)
+
+	data := make(map[string]string, len(payload)+1)
+	for key, value := range payload {
+		data[key] = value
+	}
+	if status, ok := payload["status"]; ok {
+		data["status_label"] = status
+		if label, ok := threadStatusLabels[status][language]; ok {
+			data["status_label"] = label
+		}
+	}
+
+	var subjectBuilder, bodyBuilder strings.Builder
+	if err = templatesByLanguage[language].subject.Execute(&subjectBuilder, data); err != nil {
+		return "", "", err
+	}
+	if err = templatesByLanguage[language].body.Execute(&bodyBuilder, data); err != nil {
+		return "", "", err
+	}
+	return subjectBuilder.String(), bodyBuilder.String(), nil
+}
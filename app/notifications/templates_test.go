@@ -0,0 +1,107 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
+)
+
+func TestTexts_AllTypesAreTranslatedIntoAllSupportedLanguages(t *testing.T) {
+	for _, notificationType := range database.EmailNotificationTypes() {
+		require.Contains(t, texts, notificationType)
+		for _, language := range i18n.SupportedLanguages {
+			assert.Contains(t, texts[notificationType], language, "%s is not translated into %s", notificationType, language)
+			assert.Contains(t, footers, language)
+		}
+	}
+	for status, labels := range threadStatusLabels {
+		for _, language := range i18n.SupportedLanguages {
+			assert.Contains(t, labels, language, "the status %s is not translated into %s", status, language)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name             string
+		notificationType string
+		language         string
+		payload          map[string]string
+		wantSubject      string
+		wantBody         string
+	}{
+		{
+			name:             "invitation",
+			notificationType: database.EmailNotificationTypeInvitationCreated,
+			language:         "en",
+			payload:          map[string]string{"group_id": "1", "group_name": "Class A"},
+			wantSubject:      `Invitation to join the group "Class A"`,
+			wantBody: "You have been invited to join the group \"Class A\".\n\n" +
+				"Log in to the platform to accept or reject the invitation.\n\n" +
+				"-- \nYou can stop receiving this kind of email in your profile settings.\n",
+		},
+		{
+			name:             "thread status in French",
+			notificationType: database.EmailNotificationTypeThreadStatusChanged,
+			language:         "fr",
+			payload:          map[string]string{"item_id": "1", "participant_id": "2", "item_title": "Boucles", "status": "closed"},
+			wantSubject:      "Fil d'aide sur « Boucles » : fermé",
+			wantBody: "Le statut du fil d'aide sur « Boucles » est désormais « fermé ».\n\n" +
+				"-- \nVous pouvez désactiver ce type d'e-mails dans les paramètres de votre profil.\n",
+		},
+		{
+			name:             "unknown thread status",
+			notificationType: database.EmailNotificationTypeThreadStatusChanged,
+			language:         "de",
+			payload:          map[string]string{"item_title": "Schleifen", "status": "unknown"},
+			wantSubject:      "Hilfe-Thread zu „Schleifen“: unknown",
+			wantBody: "Der Status des Hilfe-Threads zu „Schleifen“ wurde in „unknown“ geändert.\n\n" +
+				"-- \nSie können diese Art von E-Mails in Ihren Profileinstellungen abbestellen.\n",
+		},
+		{
+			name:             "unsupported language",
+			notificationType: database.EmailNotificationTypeMembershipExpiring,
+			language:         "sl",
+			payload:          map[string]string{"group_id": "1", "group_name": "Club", "expires_at": "2025-03-01 10:00"},
+			wantSubject:      `Your membership in "Club" expires soon`,
+			wantBody: "Your membership in the group \"Club\" expires on 2025-03-01 10:00 (UTC).\n\n" +
+				"-- \nYou can stop receiving this kind of email in your profile settings.\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			subject, body, err := Render(tt.notificationType, tt.language, tt.payload)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSubject, subject)
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestRender_AllTemplatesCanBeRendered(t *testing.T) {
+	payload := map[string]string{
+		"group_id": "1", "group_name": "Group", "item_id": "2", "participant_id": "3", "item_title": "Item",
+		"status": "waiting_for_trainer", "expires_at": "2025-03-01 10:00",
+	}
+	for _, notificationType := range database.EmailNotificationTypes() {
+		for _, language := range i18n.SupportedLanguages {
+			subject, body, err := Render(notificationType, language, payload)
+			require.NoError(t, err, "%s in %s", notificationType, language)
+			assert.NotEmpty(t, subject)
+			assert.NotContains(t, body, "{{")
+		}
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	_, _, err := Render("unknown", "en", nil)
+	assert.EqualError(t, err, `unknown type of notifications: "unknown"`)
+
+	_, _, err = Render(database.EmailNotificationTypeInvitationCreated, "en", map[string]string{})
+	assert.Error(t, err)
+}
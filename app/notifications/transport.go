@@ -0,0 +1,192 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+)
+
+// Message is an email message in plain text.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Transport sends email messages.
+type Transport interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// NewTransport creates the transport configured by the `email` part of the configuration:
+//   - "smtp" sends the messages to the SMTP server `smtpHost`:`smtpPort` (587 by default),
+//     using STARTTLS if supported by the server and authenticating if `smtpUsername` is given,
+//   - "file" appends the messages to the file `filePath`,
+//   - "log" (default) only logs the messages.
+func NewTransport(config *viper.Viper) (Transport, error) {
+	config.SetDefault("transport", "log")
+	config.SetDefault("from", "noreply@localhost")
+	config.SetDefault("smtpPort", 587)
+	config.SetDefault("smtpTimeout", 10*time.Second)
+
+	from := config.GetString("from")
+	switch transport := config.GetString("transport"); transport {
+	case "smtp":
+		if config.GetString("smtpHost") == "" {
+			return nil, fmt.Errorf("'smtpHost' should be set for the 'smtp' email transport")
+		}
+		return &SMTPTransport{
+			Host:     config.GetString("smtpHost"),
+			Port:     config.GetInt("smtpPort"),
+			Username: config.GetString("smtpUsername"),
+			Password: config.GetString("smtpPassword"),
+			From:     from,
+			Timeout:  config.GetDuration("smtpTimeout"),
+		}, nil
+	case "file":
+		if config.GetString("filePath") == "" {
+			return nil, fmt.Errorf("'filePath' should be set for the 'file' email transport")
+		}
+		return &FileTransport{Path: config.GetString("filePath"), From: from}, nil
+	case "log":
+		return &LogTransport{From: from}, nil
+	default:
+		return nil, fmt.Errorf("unknown email transport: %q", transport)
+	}
+}
+
+// SMTPTransport sends messages to an SMTP server.
+type SMTPTransport struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// Send sends the message to the SMTP server.
+func (t *SMTPTransport) Send(ctx context.Context, message *Message) error {
+	address := net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+	conn, err := (&net.Dialer{Timeout: t.Timeout}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(t.Timeout))
+
+	client, err := smtp.NewClient(conn, t.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: t.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if t.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", t.Username, t.Password, t.Host)); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(addressOf(t.From)); err != nil {
+		return err
+	}
+	if err = client.Rcpt(message.To); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(formatMessage(t.From, message, time.Now())); err != nil {
+		_ = writer.Close()
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// FileTransport appends messages to a file (for testing).
+type FileTransport struct {
+	Path string
+	From string
+
+	mutex sync.Mutex
+}
+
+// Send appends the message to the file followed by an empty line.
+func (t *FileTransport) Send(_ context.Context, message *Message) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	file, err := os.OpenFile(t.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(formatMessage(t.From, message, time.Now()), "\r\n"...)); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LogTransport only logs messages (for testing).
+type LogTransport struct {
+	From string
+}
+
+// Send logs the message.
+func (t *LogTransport) Send(ctx context.Context, message *Message) error {
+	logging.SharedLogger.WithContext(ctx).
+		WithField("from", t.From).
+		WithField("to", message.To).
+		WithField("subject", message.Subject).
+		Infof("Email message:\n%s", message.Body)
+	return nil
+}
+
+// formatMessage formats the message as an RFC 5322 message with a quoted-printable UTF-8 body.
+func formatMessage(from string, message *Message, date time.Time) []byte {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "From: %s\r\n", from)
+	fmt.Fprintf(&buffer, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buffer, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buffer, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	buffer.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buffer.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	writer := quotedprintable.NewWriter(&buffer)
+	_, _ = writer.Write([]byte(strings.ReplaceAll(message.Body, "\n", "\r\n")))
+	_ = writer.Close()
+	buffer.WriteString("\r\n")
+	return buffer.Bytes()
+}
+
+// addressOf returns the email address of a "Name <address>" string (or the string itself if it cannot be parsed).
+func addressOf(from string) string {
+	if address, err := mail.ParseAddress(from); err == nil {
+		return address.Address
+	}
+	return from
+}
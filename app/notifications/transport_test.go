@@ -0,0 +1,207 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransport(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    Transport
+		wantErr string
+	}{
+		{name: "log by default", values: map[string]interface{}{}, want: &LogTransport{From: "noreply@localhost"}},
+		{
+			name:   "smtp",
+			values: map[string]interface{}{"transport": "smtp", "from": "a@example.org", "smtpHost": "mail", "smtpUsername": "user"},
+			want: &SMTPTransport{
+				Host: "mail", Port: 587, Username: "user", From: "a@example.org", Timeout: 10 * time.Second,
+			},
+		},
+		{
+			name:   "file",
+			values: map[string]interface{}{"transport": "file", "filePath": "/tmp/emails"},
+			want:   &FileTransport{Path: "/tmp/emails", From: "noreply@localhost"},
+		},
+		{
+			name:    "smtp without host",
+			values:  map[string]interface{}{"transport": "smtp"},
+			wantErr: "'smtpHost' should be set for the 'smtp' email transport",
+		},
+		{
+			name:    "file without path",
+			values:  map[string]interface{}{"transport": "file"},
+			wantErr: "'filePath' should be set for the 'file' email transport",
+		},
+		{
+			name:    "unknown transport",
+			values:  map[string]interface{}{"transport": "pigeon"},
+			wantErr: `unknown email transport: "pigeon"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			config := viper.New()
+			for key, value := range tt.values {
+				config.Set(key, value)
+			}
+			transport, err := NewTransport(config)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, transport)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, transport)
+		})
+	}
+}
+
+func Test_formatMessage(t *testing.T) {
+	message := formatMessage("Algorea <noreply@example.org>", &Message{
+		To:      "jane@example.org",
+		Subject: "Invitation à rejoindre",
+		Body:    "Première ligne\nSeconde ligne",
+	}, time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "From: Algorea <noreply@example.org>\r\n"+
+		"To: jane@example.org\r\n"+
+		"Subject: =?utf-8?q?Invitation_=C3=A0_rejoindre?=\r\n"+
+		"Date: Sat, 01 Mar 2025 10:00:00 +0000\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=utf-8\r\n"+
+		"Content-Transfer-Encoding: quoted-printable\r\n\r\n"+
+		"Premi=C3=A8re ligne\r\nSeconde ligne\r\n", string(message))
+}
+
+func Test_addressOf(t *testing.T) {
+	assert.Equal(t, "noreply@example.org", addressOf("Algorea <noreply@example.org>"))
+	assert.Equal(t, "noreply@example.org", addressOf("noreply@example.org"))
+	assert.Equal(t, "not an address", addressOf("not an address"))
+}
+
+func TestFileTransport_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "emails.eml")
+	transport := &FileTransport{Path: path, From: "noreply@example.org"}
+
+	require.NoError(t, transport.Send(context.Background(), &Message{To: "a@example.org", Subject: "First", Body: "1"}))
+	require.NoError(t, transport.Send(context.Background(), &Message{To: "b@example.org", Subject: "Second", Body: "2"}))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(content), "From: noreply@example.org\r\n"))
+	assert.Contains(t, string(content), "To: a@example.org\r\nSubject: First\r\n")
+	assert.Contains(t, string(content), "To: b@example.org\r\nSubject: Second\r\n")
+	assert.True(t, strings.HasSuffix(string(content), "\r\n\r\n2\r\n\r\n"))
+}
+
+func TestFileTransport_Send_FailsWhenFileCannotBeOpened(t *testing.T) {
+	transport := &FileTransport{Path: filepath.Join(t.TempDir(), "missing", "emails.eml")}
+	assert.Error(t, transport.Send(context.Background(), &Message{To: "a@example.org"}))
+}
+
+func TestLogTransport_Send(t *testing.T) {
+	assert.NoError(t, (&LogTransport{}).Send(context.Background(), &Message{To: "a@example.org"}))
+}
+
+// runFakeSMTPServer accepts one SMTP session without extensions and returns the received commands & data.
+func runFakeSMTPServer(t *testing.T, rejectRecipient bool) (port int, received chan []string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	received = make(chan []string, 1)
+	go func() {
+		var lines []string
+		defer func() { received <- lines }()
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+			switch {
+			case inData:
+				if line == "." {
+					inData = false
+					reply("250 OK")
+				}
+			case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(line, "RCPT") && rejectRecipient:
+				reply("550 No such user")
+			case strings.HasPrefix(line, "DATA"):
+				inData = true
+				reply("354 Go ahead")
+			case strings.HasPrefix(line, "QUIT"):
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port, received
+}
+
+func TestSMTPTransport_Send(t *testing.T) {
+	port, received := runFakeSMTPServer(t, false)
+	transport := &SMTPTransport{Host: "127.0.0.1", Port: port, From: "Algorea <noreply@example.org>", Timeout: 5 * time.Second}
+
+	require.NoError(t, transport.Send(context.Background(), &Message{To: "jane@example.org", Subject: "Hello", Body: "Hi Jane"}))
+
+	lines := <-received
+	assert.Contains(t, lines, "MAIL FROM:<noreply@example.org>")
+	assert.Contains(t, lines, "RCPT TO:<jane@example.org>")
+	assert.Contains(t, lines, "Subject: Hello")
+	assert.Contains(t, lines, "Hi Jane")
+	assert.Equal(t, "QUIT", lines[len(lines)-1])
+}
+
+func TestSMTPTransport_Send_FailsWhenRecipientIsRejected(t *testing.T) {
+	port, received := runFakeSMTPServer(t, true)
+	transport := &SMTPTransport{Host: "127.0.0.1", Port: port, From: "noreply@example.org", Timeout: 5 * time.Second}
+
+	err := transport.Send(context.Background(), &Message{To: "unknown@example.org", Subject: "Hello", Body: "Hi"})
+	assert.ErrorContains(t, err, "No such user")
+	<-received
+}
+
+func TestSMTPTransport_Send_FailsWhenServerIsUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+
+	transport := &SMTPTransport{Host: "127.0.0.1", Port: port, From: "noreply@example.org", Timeout: time.Second}
+	err = transport.Send(context.Background(), &Message{To: "a@example.org"})
+	assert.ErrorContains(t, err, strconv.Itoa(port))
+}
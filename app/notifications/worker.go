@@ -0,0 +1,149 @@
+// Package notifications provides a worker sending the email notifications queued in `email_notifications`
+// (localized according to the default language of the recipients) through a pluggable transport.
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/pollingworker"
+)
+
+const maxErrorLength = 1000
+
+// Config is the configuration of the notification worker.
+type Config struct {
+	// PollInterval is the time to wait before checking the queue again when there is nothing to do.
+	PollInterval time.Duration
+	// BatchSize is the maximum number of notifications attempted on each poll.
+	BatchSize int
+	// MinBackoff is the delay before retrying a notification which has failed once.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay before retrying a failed notification.
+	MaxBackoff time.Duration
+	// MaxAttempts is the number of failed attempts after which a notification is given up.
+	MaxAttempts int
+	// ClaimDuration is the time during which a notification being sent by a worker cannot be sent by other workers.
+	ClaimDuration time.Duration
+}
+
+// DefaultConfig returns the default configuration of the notification worker.
+func DefaultConfig() Config {
+	return Config{
+		PollInterval:  5 * time.Second,
+		BatchSize:     100,
+		MinBackoff:    time.Minute,
+		MaxBackoff:    6 * time.Hour,
+		MaxAttempts:   10,
+		ClaimDuration: 5 * time.Minute,
+	}
+}
+
+// Worker sends the notifications queued in `email_notifications`.
+type Worker struct {
+	db        *database.DB
+	transport Transport
+	config    Config
+}
+
+// New creates a new notification worker sending the messages through the given transport.
+func New(db *database.DB, transport Transport, config Config) *Worker {
+	return &Worker{db: db, transport: transport, config: config}
+}
+
+// Run sends the queued notifications until the context is canceled.
+// Errors are logged and the processing is retried on the next poll.
+func (w *Worker) Run(ctx context.Context) {
+	pollingworker.Run(ctx, "Notification", w.config.PollInterval, w.RunOnce)
+}
+
+// RunOnce attempts a batch of due notifications (skipping the ones claimed concurrently by other workers).
+// It returns true if at least one notification has been processed.
+func (w *Worker) RunOnce(ctx context.Context) (processed bool, err error) {
+	store := database.NewDataStoreWithContext(ctx, w.db)
+	notifications, err := store.EmailNotifications().GetDue(w.config.BatchSize)
+	if err != nil {
+		return false, err
+	}
+
+	for index := range notifications {
+		if ctx.Err() != nil {
+			break
+		}
+		claimed, claimErr := store.EmailNotifications().Claim(notifications[index].ID, w.config.ClaimDuration)
+		if claimErr != nil {
+			return processed, claimErr
+		}
+		if !claimed {
+			continue
+		}
+		processed = true
+		if err = w.process(ctx, store, &notifications[index]); err != nil {
+			return processed, err
+		}
+	}
+	return processed, nil
+}
+
+func (w *Worker) process(ctx context.Context, store *database.DataStore, notification *database.EmailNotification) error {
+	logEntry := logging.SharedLogger.WithContext(ctx).
+		WithField("notification_id", notification.ID).
+		WithField("user_id", notification.UserID).
+		WithField("type", notification.Type)
+
+	if notification.Email == "" {
+		logEntry.Debug("Email notification canceled as the user has no email or has opted out")
+		return store.EmailNotifications().MarkAsCanceled(notification.ID)
+	}
+
+	message, err := buildMessage(notification)
+	if err != nil {
+		// retrying would not help
+		logEntry.Errorf("Cannot render the email notification: %v", err)
+		return store.EmailNotifications().MarkAsFailedAttempt(notification.ID, truncateError(err), 0, true)
+	}
+
+	sendErr := w.transport.Send(ctx, message)
+	if ctx.Err() != nil {
+		return nil // the worker is being stopped, the notification will be attempted again when the claim expires
+	}
+
+	if sendErr != nil {
+		attemptsCount := notification.AttemptsCount + 1
+		giveUp := attemptsCount >= w.config.MaxAttempts
+		retryIn := pollingworker.Backoff(notification.AttemptsCount, w.config.MinBackoff, w.config.MaxBackoff)
+		logEntry = logEntry.WithField("attempts_count", attemptsCount)
+		if giveUp {
+			logEntry.Warnf("Email notification failed, giving up: %v", sendErr)
+		} else {
+			logEntry.Infof("Email notification failed, retrying in %v: %v", retryIn, sendErr)
+		}
+		return store.EmailNotifications().MarkAsFailedAttempt(notification.ID, truncateError(sendErr), retryIn, giveUp)
+	}
+
+	logEntry.Debug("Email notification sent")
+	return store.EmailNotifications().MarkAsSent(notification.ID)
+}
+
+func buildMessage(notification *database.EmailNotification) (*Message, error) {
+	var payload map[string]string
+	if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+		return nil, err
+	}
+	subject, body, err := Render(notification.Type, notification.DefaultLanguage, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{To: notification.Email, Subject: subject, Body: body}, nil
+}
+
+func truncateError(err error) error {
+	if message := err.Error(); len(message) > maxErrorLength {
+		return errors.New(message[:maxErrorLength])
+	}
+	return err
+}
@@ -0,0 +1,49 @@
+package notifications
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	assert.Equal(t, Config{
+		PollInterval:  5 * time.Second,
+		BatchSize:     100,
+		MinBackoff:    time.Minute,
+		MaxBackoff:    6 * time.Hour,
+		MaxAttempts:   10,
+		ClaimDuration: 5 * time.Minute,
+	}, DefaultConfig())
+}
+
+func Test_buildMessage(t *testing.T) {
+	message, err := buildMessage(&database.EmailNotification{
+		ID:              1,
+		UserID:          2,
+		Type:            database.EmailNotificationTypeJoinRequestAccepted,
+		Payload:         `{"group_id": "3", "group_name": "Club"}`,
+		Email:           "jane@example.org",
+		DefaultLanguage: "fr",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.org", message.To)
+	assert.Equal(t, "Votre demande pour rejoindre « Club » a été acceptée", message.Subject)
+	assert.True(t, strings.HasPrefix(message.Body, "Votre demande pour rejoindre le groupe « Club » a été acceptée."))
+}
+
+func Test_buildMessage_FailsOnInvalidPayload(t *testing.T) {
+	_, err := buildMessage(&database.EmailNotification{Type: database.EmailNotificationTypeJoinRequestAccepted, Payload: `[]`})
+	assert.Error(t, err)
+}
+
+func Test_truncateError(t *testing.T) {
+	assert.EqualError(t, truncateError(errors.New("short")), "short")
+	assert.Len(t, truncateError(errors.New(strings.Repeat("a", 2000))).Error(), maxErrorLength)
+}
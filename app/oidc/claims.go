@@ -0,0 +1,93 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultClaimsMapping maps columns of `users` to the standard claims of ID tokens.
+// It is used when the configuration of the provider doesn't define a mapping.
+var DefaultClaimsMapping = map[string]string{
+	"login":            "preferred_username",
+	"email":            "email",
+	"email_verified":   "email_verified",
+	"first_name":       "given_name",
+	"last_name":        "family_name",
+	"default_language": "locale",
+	"time_zone":        "zoneinfo",
+}
+
+// mappableUserColumns are columns of `users` which can be filled from claims.
+var mappableUserColumns = map[string]bool{
+	"login":            true,
+	"email":            true,
+	"email_verified":   true,
+	"first_name":       true,
+	"last_name":        true,
+	"student_id":       true,
+	"country_code":     true,
+	"default_language": true,
+	"time_zone":        true,
+	"address":          true,
+	"zipcode":          true,
+	"city":             true,
+	"web_site":         true,
+}
+
+// ValidateClaimsMapping checks that the given mapping only fills the columns of `users` allowed to be filled from claims
+// and that it fills `login`.
+func ValidateClaimsMapping(mapping map[string]string) error {
+	for column := range mapping {
+		if !mappableUserColumns[column] {
+			return fmt.Errorf("the column %q of users cannot be filled from claims", column)
+		}
+	}
+	if mapping["login"] == "" {
+		return errors.New("no claim is mapped to the login")
+	}
+	return nil
+}
+
+// ConvertClaims converts the claims of an ID token into a user profile (values of columns of `users`)
+// using the given mapping (columns of `users` => claims).
+// Columns whose claims are missing get empty values except for `default_language` which is nil then.
+func ConvertClaims(claims map[string]interface{}, mapping map[string]string) (map[string]interface{}, error) {
+	profile := make(map[string]interface{}, len(mapping))
+	for column, claim := range mapping {
+		value := claims[claim]
+		if number, ok := value.(json.Number); ok {
+			value = number.String()
+		}
+		switch column {
+		case "email_verified":
+			profile[column] = value == true || value == "true"
+		case "default_language":
+			language, _ := value.(string)
+			language = strings.ToLower(strings.SplitN(strings.Replace(language, "_", "-", 1), "-", 2)[0])
+			if language == "" {
+				profile[column] = nil
+			} else {
+				profile[column] = language
+			}
+		case "country_code":
+			countryCode, _ := value.(string)
+			profile[column] = strings.ToLower(countryCode)
+		default:
+			if value == nil {
+				profile[column] = nil
+				continue
+			}
+			stringValue, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("the claim %q is not a string", claim)
+			}
+			profile[column] = stringValue
+		}
+	}
+	if login, ok := profile["login"].(string); !ok || login == "" {
+		return nil, fmt.Errorf("no %q claim for the login", mapping["login"])
+	}
+	return profile, nil
+}
@@ -0,0 +1,74 @@
+package oidc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertClaims(t *testing.T) {
+	profile, err := ConvertClaims(map[string]interface{}{
+		"sub":                "user-1",
+		"preferred_username": "jdoe",
+		"email":              "jdoe@example.org",
+		"email_verified":     true,
+		"given_name":         "John",
+		"locale":             "fr_FR",
+		"zoneinfo":           "Europe/Paris",
+		"school_id":          json.Number("1234"),
+		"country":            "FR",
+	}, map[string]string{
+		"login":            "preferred_username",
+		"email":            "email",
+		"email_verified":   "email_verified",
+		"first_name":       "given_name",
+		"last_name":        "family_name",
+		"default_language": "locale",
+		"time_zone":        "zoneinfo",
+		"student_id":       "school_id",
+		"country_code":     "country",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"login":            "jdoe",
+		"email":            "jdoe@example.org",
+		"email_verified":   true,
+		"first_name":       "John",
+		"last_name":        nil,
+		"default_language": "fr",
+		"time_zone":        "Europe/Paris",
+		"student_id":       "1234",
+		"country_code":     "fr",
+	}, profile)
+}
+
+func TestConvertClaims_MissingClaims(t *testing.T) {
+	profile, err := ConvertClaims(map[string]interface{}{"preferred_username": "jdoe"}, DefaultClaimsMapping)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"login":            "jdoe",
+		"email":            nil,
+		"email_verified":   false,
+		"first_name":       nil,
+		"last_name":        nil,
+		"default_language": nil,
+		"time_zone":        nil,
+	}, profile)
+}
+
+func TestConvertClaims_Errors(t *testing.T) {
+	_, err := ConvertClaims(map[string]interface{}{"sub": "user-1"}, DefaultClaimsMapping)
+	assert.EqualError(t, err, `no "preferred_username" claim for the login`)
+
+	_, err = ConvertClaims(map[string]interface{}{"preferred_username": "jdoe", "given_name": []interface{}{"John"}},
+		DefaultClaimsMapping)
+	assert.EqualError(t, err, `the claim "given_name" is not a string`)
+}
+
+func TestValidateClaimsMapping(t *testing.T) {
+	assert.NoError(t, ValidateClaimsMapping(DefaultClaimsMapping))
+	assert.EqualError(t, ValidateClaimsMapping(map[string]string{"login": "sub", "group_id": "group"}),
+		`the column "group_id" of users cannot be filled from claims`)
+	assert.EqualError(t, ValidateClaimsMapping(map[string]string{"email": "email"}), "no claim is mapped to the login")
+}
@@ -0,0 +1,239 @@
+package oidc
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// clockSkew is the tolerated difference between the clocks of the provider and of the backend.
+const clockSkew = time.Minute
+
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// IDTokenFromToken extracts the ID token from the response of the token endpoint.
+func IDTokenFromToken(token *oauth2.Token) (string, error) {
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return "", errors.New("no id_token in the token response")
+	}
+	return rawIDToken, nil
+}
+
+// VerifyIDToken checks the signature (RS256 only) and the claims (iss, aud, azp, exp) of the given ID token
+// and returns its claims.
+func (client *Client) VerifyIDToken(ctx context.Context, rawIDToken string) (map[string]interface{}, error) {
+	keySet, err := client.keySet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := keySet.VerifySignature(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if err = ValidateClaims(claims, client.config.Issuer, client.config.ClientID); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (client *Client) keySet(ctx context.Context) (*KeySet, error) {
+	metadata, err := client.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if client.keys == nil {
+		client.keys = NewKeySet(metadata.JWKSURI)
+	}
+	return client.keys, nil
+}
+
+// ValidateClaims checks the standard claims (iss, aud, azp, exp, sub) of an ID token
+// issued by the given issuer for the given client.
+func ValidateClaims(claims map[string]interface{}, issuer, clientID string) error {
+	if claims["iss"] != issuer {
+		return errors.New("wrong issuer of the ID token")
+	}
+
+	var audience []interface{}
+	switch aud := claims["aud"].(type) {
+	case string:
+		audience = []interface{}{aud}
+	case []interface{}:
+		audience = aud
+	}
+	audienceMatches := false
+	for _, aud := range audience {
+		if aud == clientID {
+			audienceMatches = true
+		}
+	}
+	if !audienceMatches {
+		return errors.New("the ID token is not issued for this client")
+	}
+	if azp, ok := claims["azp"]; (ok || len(audience) > 1) && azp != clientID {
+		return errors.New("the ID token is not issued for this client")
+	}
+
+	expiresAt, ok := claims["exp"].(json.Number)
+	if !ok {
+		return errors.New("no expiration time in the ID token")
+	}
+	expiresAtUnix, err := expiresAt.Int64()
+	if err != nil || time.Unix(expiresAtUnix, 0).Add(clockSkew).Before(time.Now()) {
+		return errors.New("the ID token has expired")
+	}
+
+	if subject, ok := claims["sub"].(string); !ok || subject == "" {
+		return errors.New("no subject in the ID token")
+	}
+	return nil
+}
+
+// KeySet is the set of public keys (JWKS) an identity provider signs its ID tokens with.
+// The keys are loaded on first use and reloaded when a token is signed with an unknown key.
+type KeySet struct {
+	url string
+
+	mutex sync.Mutex
+	keys  *jsonWebKeySet
+}
+
+// NewKeySet creates a key set loaded from the given URL.
+func NewKeySet(jwksURL string) *KeySet {
+	return &KeySet{url: jwksURL}
+}
+
+// VerifySignature checks the signature (RS256 only) of the given ID token and returns its claims.
+// The claims are not validated.
+func (keySet *KeySet) VerifySignature(ctx context.Context, rawIDToken string) (map[string]interface{}, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed ID token")
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed ID token header: %w", err)
+	}
+	if header.Algorithm != "RS256" {
+		return nil, fmt.Errorf("unsupported signing algorithm of the ID token: %q", header.Algorithm)
+	}
+
+	publicKey, err := keySet.publicKey(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed ID token signature")
+	}
+	hashed := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signature); err != nil {
+		return nil, errors.New("invalid signature of the ID token")
+	}
+
+	var claims map[string]interface{}
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed ID token payload: %w", err)
+	}
+	return claims, nil
+}
+
+func (keySet *KeySet) publicKey(ctx context.Context, keyID string) (*rsa.PublicKey, error) {
+	keys, err := keySet.load(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	key := findKey(keys, keyID)
+	if key == nil {
+		// the provider may have rotated its keys
+		if keys, err = keySet.load(ctx, true); err != nil {
+			return nil, err
+		}
+		if key = findKey(keys, keyID); key == nil {
+			return nil, fmt.Errorf("unknown key of the ID token: %q", keyID)
+		}
+	}
+
+	modulus, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, errors.New("malformed key of the identity provider")
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil || len(exponent) == 0 || len(exponent) > 4 {
+		return nil, errors.New("malformed key of the identity provider")
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exponent).Int64()),
+	}, nil
+}
+
+func (keySet *KeySet) load(ctx context.Context, reload bool) (*jsonWebKeySet, error) {
+	keySet.mutex.Lock()
+	defer keySet.mutex.Unlock()
+
+	if keySet.keys != nil && !reload {
+		return keySet.keys, nil
+	}
+	var keys jsonWebKeySet
+	if err := getJSON(ctx, keySet.url, &keys); err != nil {
+		return nil, fmt.Errorf("can't load the keys of the identity provider: %w", err)
+	}
+	keySet.keys = &keys
+	return keySet.keys, nil
+}
+
+func findKey(keys *jsonWebKeySet, keyID string) *jsonWebKey {
+	for index := range keys.Keys {
+		key := &keys.Keys[index]
+		if key.KeyType != "RSA" || key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if keyID == "" || key.KeyID == keyID {
+			return key
+		}
+	}
+	return nil
+}
+
+func decodeSegment(segment string, result interface{}) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(decoded))
+	decoder.UseNumber()
+	return decoder.Decode(result)
+}
@@ -0,0 +1,97 @@
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidctest"
+)
+
+func TestClient_VerifyIDToken_Errors(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+
+	withClaims := func(changes map[string]interface{}) string {
+		claims := validClaims(server.URL)
+		for key, value := range changes {
+			if value == nil {
+				delete(claims, key)
+			} else {
+				claims[key] = value
+			}
+		}
+		return oidctest.SignIDToken(claims)
+	}
+	validToken := withClaims(nil)
+	parts := strings.Split(validToken, ".")
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{name: "malformed", token: "abc.def", wantErr: "malformed ID token"},
+		{
+			name:    "unsupported algorithm",
+			token:   base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + ".",
+			wantErr: `unsupported signing algorithm of the ID token: "none"`,
+		},
+		{
+			name: "unknown key",
+			token: base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"unknown"}`)) + "." +
+				parts[1] + "." + parts[2],
+			wantErr: `unknown key of the ID token: "unknown"`,
+		},
+		{
+			name:    "wrong signature",
+			token:   parts[0] + "." + strings.Split(withClaims(map[string]interface{}{"sub": "user-2"}), ".")[1] + "." + parts[2],
+			wantErr: "invalid signature of the ID token",
+		},
+		{name: "wrong issuer", token: withClaims(map[string]interface{}{"iss": "https://other.example.org"}), wantErr: "wrong issuer of the ID token"},
+		{name: "wrong audience", token: withClaims(map[string]interface{}{"aud": "other"}), wantErr: "the ID token is not issued for this client"},
+		{
+			name:    "several audiences without azp",
+			token:   withClaims(map[string]interface{}{"aud": []string{"client", "other"}}),
+			wantErr: "the ID token is not issued for this client",
+		},
+		{
+			name:    "wrong azp",
+			token:   withClaims(map[string]interface{}{"aud": []string{"client", "other"}, "azp": "other"}),
+			wantErr: "the ID token is not issued for this client",
+		},
+		{name: "no exp", token: withClaims(map[string]interface{}{"exp": nil}), wantErr: "no expiration time in the ID token"},
+		{
+			name:    "expired",
+			token:   withClaims(map[string]interface{}{"exp": time.Now().Add(-2 * time.Minute).Unix()}),
+			wantErr: "the ID token has expired",
+		},
+		{name: "no subject", token: withClaims(map[string]interface{}{"sub": ""}), wantErr: "no subject in the ID token"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(&Config{Issuer: server.URL, ClientID: "client"})
+			_, err := client.VerifyIDToken(context.Background(), tt.token)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestClient_VerifyIDToken_AcceptsSeveralAudiencesWithAzp(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+
+	claims := validClaims(server.URL)
+	claims["aud"] = []string{"other", "client"}
+	claims["azp"] = "client"
+	verifiedClaims, err := NewClient(&Config{Issuer: server.URL, ClientID: "client"}).
+		VerifyIDToken(context.Background(), oidctest.SignIDToken(claims))
+	require.NoError(t, err)
+	assert.Equal(t, "client", verifiedClaims["azp"])
+}
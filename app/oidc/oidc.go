@@ -0,0 +1,154 @@
+// Package oidc provides a client of OpenID Connect identity providers:
+// the discovery of the provider, the authorization-code flow, the validation of ID tokens,
+// and the conversion of their claims into user profiles.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/tracing"
+)
+
+// RequestTimeout is the timeout of the requests to identity providers
+// (so that a slow provider doesn't block the requests of users for long).
+const RequestTimeout = 10 * time.Second
+
+// httpClient makes the requests to identity providers.
+var httpClient = &http.Client{Timeout: RequestTimeout, Transport: &tracing.Transport{}}
+
+// Config is the configuration of an OpenID Connect identity provider.
+type Config struct {
+	// Issuer is the URL of the provider (the discovery document is loaded from {Issuer}/.well-known/openid-configuration).
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// Scopes requested by the client ("openid" is always requested).
+	Scopes []string
+	// ClaimsMapping maps columns of `users` to claims of ID tokens (see DefaultClaimsMapping).
+	ClaimsMapping map[string]string
+}
+
+// ProviderMetadata is the part of the discovery document of a provider used by the client.
+type ProviderMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Client is a client of an OpenID Connect identity provider.
+// The discovery document and the keys of the provider are loaded on first use.
+type Client struct {
+	config *Config
+
+	mutex    sync.Mutex
+	metadata *ProviderMetadata
+	keys     *KeySet
+}
+
+// NewClient creates a client of the identity provider with the given configuration.
+func NewClient(config *Config) *Client {
+	return &Client{config: config}
+}
+
+// Issuer returns the issuer of the identity provider.
+func (client *Client) Issuer() string {
+	return client.config.Issuer
+}
+
+// Discover loads the discovery document of the provider.
+func (client *Client) Discover(ctx context.Context) (*ProviderMetadata, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if client.metadata != nil {
+		return client.metadata, nil
+	}
+
+	var metadata ProviderMetadata
+	if err := getJSON(ctx, strings.TrimSuffix(client.config.Issuer, "/")+"/.well-known/openid-configuration", &metadata); err != nil {
+		return nil, fmt.Errorf("can't load the discovery document: %w", err)
+	}
+	if metadata.Issuer != client.config.Issuer {
+		return nil, fmt.Errorf("the issuer of the discovery document (%q) doesn't match the configured issuer", metadata.Issuer)
+	}
+	if metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("the discovery document has no token endpoint or no jwks_uri")
+	}
+	client.metadata = &metadata
+	return client.metadata, nil
+}
+
+// OAuth2Config returns the OAuth2 configuration of the provider.
+func (client *Client) OAuth2Config(ctx context.Context) (*oauth2.Config, error) {
+	metadata, err := client.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scopes := []string{"openid"}
+	for _, scope := range client.config.Scopes {
+		if scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return &oauth2.Config{
+		ClientID:     client.config.ClientID,
+		ClientSecret: client.config.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  metadata.AuthorizationEndpoint,
+			TokenURL: metadata.TokenEndpoint,
+		},
+		Scopes: scopes,
+	}, nil
+}
+
+// Exchange converts an authorization code into tokens. The response should contain an ID token.
+func (client *Client) Exchange(ctx context.Context, code string, options ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	oauthConfig, err := client.OAuth2Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return oauthConfig.Exchange(context.WithValue(ctx, oauth2.HTTPClient, httpClient), code, options...)
+}
+
+// Refresh gets new tokens for the given refresh token.
+func (client *Client) Refresh(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+	oauthConfig, err := client.OAuth2Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the old token is invalid since its AccessToken is empty, so the lib will refresh it
+	return oauthConfig.TokenSource(context.WithValue(ctx, oauth2.HTTPClient, httpClient), &oauth2.Token{RefreshToken: refreshToken}).Token()
+}
+
+func getJSON(ctx context.Context, url string, result interface{}) error {
+	request, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Accept", "application/json")
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, 1<<20)) // 1Mb
+	_ = response.Body.Close()
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", response.StatusCode)
+	}
+	return json.Unmarshal(body, result)
+}
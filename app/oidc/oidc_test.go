@@ -0,0 +1,111 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidctest"
+)
+
+func validClaims(issuer string) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":                issuer,
+		"sub":                "user-1",
+		"aud":                "client",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"iat":                time.Now().Unix(),
+		"preferred_username": "jdoe",
+	}
+}
+
+func TestClient_Discover(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+
+	client := NewClient(&Config{Issuer: server.URL})
+	metadata, err := client.Discover(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &ProviderMetadata{
+		Issuer:                server.URL,
+		AuthorizationEndpoint: server.URL + "/authorize",
+		TokenEndpoint:         server.URL + "/token",
+		JWKSURI:               server.URL + "/jwks",
+	}, metadata)
+}
+
+func TestClient_Discover_ChecksIssuer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(oidctest.DiscoveryDocument("https://other.example.org")))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(&Config{Issuer: server.URL}).Discover(context.Background())
+	assert.EqualError(t, err, `the issuer of the discovery document ("https://other.example.org") doesn't match the configured issuer`)
+}
+
+func TestClient_Discover_FailsOnWrongStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := NewClient(&Config{Issuer: server.URL}).Discover(context.Background())
+	assert.EqualError(t, err, "can't load the discovery document: unexpected status code 404")
+}
+
+func TestClient_OAuth2Config(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+
+	oauthConfig, err := NewClient(&Config{
+		Issuer: server.URL, ClientID: "client", ClientSecret: "secret", Scopes: []string{"profile", "openid", "email"},
+	}).OAuth2Config(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &oauth2.Config{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Endpoint:     oauth2.Endpoint{AuthURL: server.URL + "/authorize", TokenURL: server.URL + "/token"},
+		Scopes:       []string{"openid", "profile", "email"},
+	}, oauthConfig)
+}
+
+func TestClient_ExchangeAndVerifyIDToken(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+	claims := validClaims(server.URL)
+	server.SetTokenResponse("somecode", oidctest.TokenResponse("accesstoken", "refreshtoken", 3600, claims))
+
+	client := NewClient(&Config{Issuer: server.URL, ClientID: "client", ClientSecret: "secret"})
+	token, err := client.Exchange(context.Background(), "somecode", oauth2.SetAuthURLParam("code_verifier", "verifier"))
+	require.NoError(t, err)
+	assert.Equal(t, "accesstoken", token.AccessToken)
+	assert.Equal(t, "refreshtoken", token.RefreshToken)
+
+	rawIDToken, err := IDTokenFromToken(token)
+	require.NoError(t, err)
+	verifiedClaims, err := client.VerifyIDToken(context.Background(), rawIDToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", verifiedClaims["sub"])
+	assert.Equal(t, "jdoe", verifiedClaims["preferred_username"])
+}
+
+func TestClient_Refresh(t *testing.T) {
+	server := oidctest.NewServer()
+	defer server.Close()
+	server.SetTokenResponse("refreshtoken", oidctest.TokenResponse("newaccesstoken", "", 3600, validClaims(server.URL)))
+
+	token, err := NewClient(&Config{Issuer: server.URL, ClientID: "client"}).Refresh(context.Background(), "refreshtoken")
+	require.NoError(t, err)
+	assert.Equal(t, "newaccesstoken", token.AccessToken)
+	assert.Equal(t, "refreshtoken", token.RefreshToken)
+}
+
+func TestIDTokenFromToken_NoIDToken(t *testing.T) {
+	_, err := IDTokenFromToken(&oauth2.Token{AccessToken: "accesstoken"})
+	assert.EqualError(t, err, "no id_token in the token response")
+}
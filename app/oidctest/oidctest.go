@@ -0,0 +1,175 @@
+//go:build !prod
+
+// Package oidctest provides a mock OpenID Connect identity provider to be used in tests.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// KeyID is the ID of the key signing ID tokens.
+const KeyID = "oidctest"
+
+var (
+	privateKey     *rsa.PrivateKey
+	privateKeyOnce sync.Once
+)
+
+// PrivateKey returns the key signing ID tokens (generated once).
+func PrivateKey() *rsa.PrivateKey {
+	privateKeyOnce.Do(func() {
+		var err error
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return privateKey
+}
+
+// DiscoveryDocument returns the discovery document of a provider with the given issuer
+// whose endpoints are {issuer}/authorize, {issuer}/token & {issuer}/jwks.
+func DiscoveryDocument(issuer string) string {
+	document, _ := json.Marshal(map[string]interface{}{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"token_endpoint":                        issuer + "/token",
+		"jwks_uri":                              issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+	return string(document)
+}
+
+// JWKS returns the JSON Web Key Set containing the public key signing ID tokens.
+func JWKS() string {
+	publicKey := PrivateKey().PublicKey
+	keySet, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": KeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		}},
+	})
+	return string(keySet)
+}
+
+// SignIDToken returns an ID token with the given claims signed with PrivateKey().
+func SignIDToken(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": KeyID})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		panic(err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hashed := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, PrivateKey(), crypto.SHA256, hashed[:])
+	if err != nil {
+		panic(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// TokenResponse returns the response of the token endpoint containing an ID token with the given claims.
+func TokenResponse(accessToken, refreshToken string, expiresIn int, claims map[string]interface{}) string {
+	response := map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   expiresIn,
+		"id_token":     SignIDToken(claims),
+	}
+	if refreshToken != "" {
+		response["refresh_token"] = refreshToken
+	}
+	result, _ := json.Marshal(response)
+	return string(result)
+}
+
+// Server is a mock identity provider. Its issuer is the URL of the server.
+type Server struct {
+	*httptest.Server
+
+	mutex             sync.Mutex
+	responses         map[string]string
+	discoveryRequests int
+	jwksRequests      int
+}
+
+// NewServer starts a mock identity provider.
+func NewServer() *Server {
+	server := &Server{responses: make(map[string]string)}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	return server
+}
+
+// SetTokenResponse sets the response of the token endpoint for the given grant
+// (an authorization code or a refresh token).
+func (server *Server) SetTokenResponse(grant, response string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.responses[grant] = response
+}
+
+// JWKSRequests returns the number of requests to the key set of the identity provider.
+func (server *Server) JWKSRequests() int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.jwksRequests
+}
+
+// DiscoveryRequests returns the number of requests to the discovery document of the identity provider.
+func (server *Server) DiscoveryRequests() int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.discoveryRequests
+}
+
+func (server *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		server.mutex.Lock()
+		server.discoveryRequests++
+		server.mutex.Unlock()
+		_, _ = fmt.Fprint(w, DiscoveryDocument(server.URL))
+	case "/jwks":
+		server.mutex.Lock()
+		server.jwksRequests++
+		server.mutex.Unlock()
+		_, _ = fmt.Fprint(w, JWKS())
+	case "/token":
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		grant := r.PostForm.Get("code")
+		if r.PostForm.Get("grant_type") == "refresh_token" {
+			grant = r.PostForm.Get("refresh_token")
+		}
+		server.mutex.Lock()
+		response, ok := server.responses[grant]
+		server.mutex.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"error": "invalid_grant"}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, response)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
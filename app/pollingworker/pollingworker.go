@@ -0,0 +1,62 @@
+// Package pollingworker provides the loop shared by the workers polling a queue stored in the database.
+package pollingworker
+
+import (
+	"context"
+	"time"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+)
+
+// Run calls runOnce until the context is canceled.
+// runOnce is called again immediately if it has processed something successfully,
+// otherwise Run waits for pollInterval before calling it again. Errors are logged.
+// The name of the worker (like "Propagation") is used in the log messages.
+func Run(ctx context.Context, name string, pollInterval time.Duration,
+	runOnce func(ctx context.Context) (processed bool, err error),
+) {
+	logging.SharedLogger.WithContext(ctx).Infof("%s worker started", name)
+	for {
+		processed, err := runOnce(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			logging.SharedLogger.WithContext(ctx).Errorf("%s worker error: %v", name, err)
+		}
+
+		if processed && err == nil {
+			continue
+		}
+		if !Sleep(ctx, pollInterval) {
+			break
+		}
+	}
+	logging.SharedLogger.WithContext(ctx).Infof("%s worker stopped", name)
+}
+
+// Backoff returns the delay before retrying a task which has already failed failedAttempts times.
+// The delay doubles with each failed attempt, starting from minDelay and capped at maxDelay.
+func Backoff(failedAttempts int, minDelay, maxDelay time.Duration) time.Duration {
+	delay := minDelay
+	for i := 0; i < failedAttempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// Sleep waits for the given duration. It returns false if the context has been canceled meanwhile.
+func Sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
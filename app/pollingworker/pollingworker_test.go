@@ -0,0 +1,69 @@
+package pollingworker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	Run(ctx, "Test", time.Millisecond, func(context.Context) (bool, error) {
+		calls++
+		switch calls {
+		case 1:
+			return true, nil
+		case 2:
+			return true, errors.New("some error")
+		case 3:
+			return false, nil
+		default:
+			cancel()
+			return false, nil
+		}
+	})
+	assert.Equal(t, 4, calls)
+}
+
+func TestRun_StopsWhenContextIsCanceledDuringSleep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	Run(ctx, "Test", time.Hour, func(context.Context) (bool, error) {
+		calls++
+		cancel()
+		return true, errors.New("some error")
+	})
+	assert.Equal(t, 1, calls)
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		failedAttempts int
+		want           time.Duration
+	}{
+		{failedAttempts: 0, want: 5 * time.Second},
+		{failedAttempts: 1, want: 10 * time.Second},
+		{failedAttempts: 2, want: 20 * time.Second},
+		{failedAttempts: 3, want: 40 * time.Second},
+		{failedAttempts: 4, want: time.Minute},
+		{failedAttempts: 1000, want: time.Minute},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Backoff(tt.failedAttempts, 5*time.Second, time.Minute), "failedAttempts = %d", tt.failedAttempts)
+	}
+}
+
+func TestSleep(t *testing.T) {
+	assert.True(t, Sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, Sleep(ctx, time.Hour))
+}
@@ -0,0 +1,60 @@
+package propagationworker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+// stepTimer measures the time spent in each propagation step.
+// A step lasts until the next step starts or until the timer is stopped.
+type stepTimer struct {
+	mutex           sync.Mutex
+	now             func() time.Time
+	currentStep     database.PropagationStep
+	currentStepFrom time.Time
+	running         bool
+	stepDurations   map[database.PropagationStep]time.Duration
+}
+
+func newStepTimer() *stepTimer {
+	return &stepTimer{now: time.Now, stepDurations: make(map[database.PropagationStep]time.Duration)}
+}
+
+func (t *stepTimer) startStep(step database.PropagationStep) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	t.finishCurrentStep(now)
+	t.currentStep = step
+	t.currentStepFrom = now
+	t.running = true
+}
+
+func (t *stepTimer) stop() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.finishCurrentStep(t.now())
+	t.running = false
+}
+
+func (t *stepTimer) finishCurrentStep(now time.Time) {
+	if t.running {
+		t.stepDurations[t.currentStep] += now.Sub(t.currentStepFrom)
+	}
+}
+
+// durations returns the accumulated durations of the steps (as strings, for logging).
+func (t *stepTimer) durations() map[string]string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	result := make(map[string]string, len(t.stepDurations))
+	for step, duration := range t.stepDurations {
+		result[string(step)] = duration.String()
+	}
+	return result
+}
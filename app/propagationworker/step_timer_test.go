@@ -0,0 +1,37 @@
+package propagationworker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+func TestStepTimer(t *testing.T) {
+	currentTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	timer := newStepTimer()
+	timer.now = func() time.Time { return currentTime }
+
+	timer.startStep(database.PropagationStepResultsNamedLockAcquire)
+	currentTime = currentTime.Add(time.Second)
+	timer.startStep(database.PropagationStepResultsInsideNamedLockMain)
+	currentTime = currentTime.Add(3 * time.Second)
+	timer.startStep(database.PropagationStepResultsNamedLockAcquire)
+	currentTime = currentTime.Add(2 * time.Second)
+	timer.stop()
+	currentTime = currentTime.Add(time.Hour)
+	timer.stop()
+
+	assert.Equal(t, map[string]string{
+		string(database.PropagationStepResultsNamedLockAcquire):    "3s",
+		string(database.PropagationStepResultsInsideNamedLockMain): "3s",
+	}, timer.durations())
+}
+
+func TestStepTimer_NoSteps(t *testing.T) {
+	timer := newStepTimer()
+	timer.stop()
+	assert.Empty(t, timer.durations())
+}
@@ -0,0 +1,135 @@
+// Package propagationworker provides a worker processing the durable propagation queue.
+package propagationworker
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/pollingworker"
+)
+
+const (
+	// NamedLockName is the name of the lock preventing propagations from being run concurrently
+	// by the worker and the `propagation` command.
+	NamedLockName = "propagation_command"
+	// NamedLockTimeout is the maximum time to wait for the propagation lock.
+	NamedLockTimeout = 600 * time.Second
+)
+
+// Config is the configuration of the propagation worker.
+type Config struct {
+	// PollInterval is the time to wait before checking the queue again when there is nothing to do.
+	PollInterval time.Duration
+	// MinBackoff is the delay before retrying a propagation which has failed once.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay before retrying a failed propagation.
+	MaxBackoff time.Duration
+}
+
+// DefaultConfig returns the default configuration of the propagation worker.
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		MinBackoff:   5 * time.Second,
+		MaxBackoff:   10 * time.Minute,
+	}
+}
+
+// Worker runs the propagations requested through the propagation queue.
+type Worker struct {
+	db     *database.DB
+	config Config
+}
+
+// New creates a new propagation worker.
+func New(db *database.DB, config Config) *Worker {
+	return &Worker{db: db, config: config}
+}
+
+// Run processes the queue until the context is canceled.
+// Errors are logged and the processing is retried on the next poll.
+func (w *Worker) Run(ctx context.Context) {
+	pollingworker.Run(ctx, "Propagation", w.config.PollInterval, w.RunOnce)
+}
+
+// RunOnce runs all the due propagations of the queue once.
+// It returns true if at least one propagation has been run (successfully or not).
+func (w *Worker) RunOnce(ctx context.Context) (processed bool, err error) {
+	err = database.NewDataStoreWithContext(ctx, w.db).
+		WithNamedLock(NamedLockName, NamedLockTimeout, func(store *database.DataStore) error {
+			entries, getErr := store.PropagationQueue().GetDue()
+			if getErr != nil {
+				return getErr
+			}
+
+			for index := range entries {
+				processed = true
+				if processErr := w.process(ctx, store, &entries[index]); processErr != nil {
+					return processErr
+				}
+			}
+			return nil
+		})
+	return processed, err
+}
+
+func (w *Worker) process(ctx context.Context, store *database.DataStore, entry *database.PropagationQueueEntry) error {
+	timer := newStepTimer()
+	previousHook := database.GetBeforePropagationStepHook()
+	database.SetBeforePropagationStepHook(func(step database.PropagationStep) {
+		timer.startStep(step)
+		previousHook(step)
+	})
+	startTime := time.Now()
+	runErr := runPropagation(store, entry.Type)
+	duration := time.Since(startTime)
+	timer.stop()
+	database.SetBeforePropagationStepHook(previousHook)
+
+	logEntry := logging.SharedLogger.WithContext(ctx).
+		WithField("type", entry.Type).
+		WithField("requests_count", entry.RequestsCount).
+		WithField("duration", duration.String()).
+		WithField("step_durations", timer.durations())
+
+	if runErr != nil {
+		retryIn := pollingworker.Backoff(entry.FailedAttempts, w.config.MinBackoff, w.config.MaxBackoff)
+		logEntry.WithField("failed_attempts", entry.FailedAttempts+1).
+			Warnf("Propagation failed, retrying in %v: %v", retryIn, runErr)
+		return store.PropagationQueue().MarkAsFailed(entry, runErr, retryIn)
+	}
+
+	logEntry.Info("Propagation done")
+	return store.PropagationQueue().MarkAsDone(entry)
+}
+
+func runPropagation(store *database.DataStore, propagationType string) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			switch e := p.(type) {
+			case runtime.Error:
+				panic(e)
+			case error:
+				err = e
+			default:
+				err = fmt.Errorf("%v", p)
+			}
+		}
+	}()
+
+	return store.InTransaction(func(store *database.DataStore) error {
+		switch propagationType {
+		case database.PropagationTypePermissions:
+			store.SchedulePermissionsPropagation()
+		case database.PropagationTypeResults:
+			store.ScheduleResultsPropagation()
+		default:
+			return fmt.Errorf("unknown propagation type: %q", propagationType)
+		}
+		return nil
+	})
+}
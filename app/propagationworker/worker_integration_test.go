@@ -0,0 +1,64 @@
+//go:build !unit
+
+package propagationworker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/propagationworker"
+	"github.com/France-ioi/AlgoreaBackend/v2/golang"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+func TestWorker_RunOnce(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		propagation_queue:
+			- {type: permissions, requests_count: 3, failed_attempts: 2, next_attempt_at: "2000-01-01 00:00:00"}
+			- {type: results, requests_count: 1, next_attempt_at: "2000-01-01 00:00:00"}`)
+	defer func() { _ = db.Close() }()
+
+	calledSteps := golang.NewSet[database.PropagationStep]()
+	previousHook := database.GetBeforePropagationStepHook()
+	database.SetBeforePropagationStepHook(func(step database.PropagationStep) { calledSteps.Add(step) })
+	defer database.SetBeforePropagationStepHook(previousHook)
+
+	worker := propagationworker.New(db, propagationworker.DefaultConfig())
+	processed, err := worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.True(t, processed)
+	assert.True(t, calledSteps.Contains(database.PropagationStepAccessMain))
+	assert.True(t, calledSteps.Contains(database.PropagationStepResultsNamedLockAcquire))
+
+	found, err := database.NewDataStore(db).PropagationQueue().HasRows()
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	processed, err = worker.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestWorker_RunOnce_SkipsPostponedEntries(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		propagation_queue:
+			- {type: results, failed_attempts: 1, next_attempt_at: "9999-12-31 23:59:59"}`)
+	defer func() { _ = db.Close() }()
+
+	processed, err := propagationworker.New(db, propagationworker.DefaultConfig()).RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.False(t, processed)
+
+	found, err := database.NewDataStore(db).PropagationQueue().HasRows()
+	require.NoError(t, err)
+	assert.True(t, found)
+}
@@ -0,0 +1,12 @@
+package propagationworker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	assert.Equal(t, Config{PollInterval: time.Second, MinBackoff: 5 * time.Second, MaxBackoff: 10 * time.Minute}, DefaultConfig())
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+// dbStoreSweepInterval is the minimal interval between removals of full buckets from a database store
+// (by the same process).
+const dbStoreSweepInterval = time.Minute
+
+// DBStore keeps token buckets in the `rate_limit_buckets` table so that they are shared by all the processes.
+type DBStore struct {
+	db *database.DB
+
+	sweepMutex sync.Mutex
+	lastSweep  time.Time
+}
+
+// NewDBStore creates a store keeping token buckets in the given database.
+func NewDBStore(db *database.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+// Take takes a token from the bucket with the given key according to the policy.
+func (s *DBStore) Take(ctx context.Context, key string, policy *Policy, now time.Time) (result Result, err error) {
+	store := database.NewDataStoreWithContext(ctx, s.db)
+	if s.shouldSweep(now) {
+		if err = store.RateLimitBuckets().Where("full_at <= ?", (*database.Time)(&now)).Delete().Error(); err != nil {
+			return Result{}, err
+		}
+	}
+
+	err = store.InTransaction(func(store *database.DataStore) error {
+		var stored struct {
+			Tokens    float64
+			UpdatedAt database.Time
+		}
+		var previous *bucket
+		err := store.RateLimitBuckets().WithExclusiveWriteLock().Where("bucket_key = ?", key).
+			Select("tokens, updated_at").Take(&stored).Error()
+		if !gorm.IsRecordNotFoundError(err) {
+			if err != nil {
+				return err
+			}
+			previous = &bucket{tokens: stored.Tokens, updatedAt: time.Time(stored.UpdatedAt)}
+		}
+
+		var current bucket
+		current, result = policy.take(previous, now)
+		fullAt := now.Add(result.Reset)
+		return store.RateLimitBuckets().InsertOrUpdateMap(map[string]interface{}{
+			"bucket_key": key,
+			"tokens":     current.tokens,
+			"updated_at": (*database.Time)(&current.updatedAt),
+			"full_at":    (*database.Time)(&fullAt),
+		}, []string{"tokens", "updated_at", "full_at"})
+	})
+	return result, err
+}
+
+func (s *DBStore) shouldSweep(now time.Time) bool {
+	s.sweepMutex.Lock()
+	defer s.sweepMutex.Unlock()
+
+	if now.Sub(s.lastSweep) < dbStoreSweepInterval {
+		return false
+	}
+	s.lastSweep = now
+	return true
+}
@@ -0,0 +1,60 @@
+//go:build !unit
+
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/ratelimit"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
+	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
+)
+
+func TestDBStore_Take(t *testing.T) {
+	testoutput.SuppressIfPasses(t)
+
+	db := testhelpers.SetupDBWithFixtureString(`
+		rate_limit_buckets:
+			- {bucket_key: "answers:ip:1.1.1.1", tokens: 0, updated_at: "2019-12-31 23:00:00", full_at: "2019-12-31 23:01:00"}`)
+	defer func() { _ = db.Close() }()
+
+	store := ratelimit.NewDBStore(db)
+	policy := &ratelimit.Policy{Limit: 2, Period: time.Minute, Key: ratelimit.KeyIP}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, expected := range []ratelimit.Result{
+		{Allowed: true, Remaining: 1, Reset: 30 * time.Second},
+		{Allowed: true, Remaining: 0, Reset: 59 * time.Second},
+		{Allowed: false, Remaining: 0, Reset: 58 * time.Second, RetryAfter: 28 * time.Second},
+	} {
+		result, err := store.Take(context.Background(), "group:ip:2.2.2.2", policy, now)
+		require.NoError(t, err)
+		result.Reset = result.Reset.Round(time.Millisecond)
+		result.RetryAfter = result.RetryAfter.Round(time.Millisecond)
+		assert.Equal(t, expected, result)
+		now = now.Add(time.Second)
+	}
+
+	type bucketRow struct {
+		BucketKey string
+		Tokens    float64
+		UpdatedAt string
+		FullAt    string
+	}
+	var buckets []bucketRow
+	require.NoError(t, database.NewDataStore(db).RateLimitBuckets().
+		Select("bucket_key, tokens, CAST(updated_at AS CHAR) AS updated_at, CAST(full_at AS CHAR) AS full_at").
+		Scan(&buckets).Error())
+	// the full bucket has been removed
+	require.Len(t, buckets, 1)
+	assert.Equal(t, "group:ip:2.2.2.2", buckets[0].BucketKey)
+	assert.InDelta(t, 1.0/15, buckets[0].Tokens, 1e-9)
+	assert.Equal(t, "2020-01-01 00:00:02.000000", buckets[0].UpdatedAt)
+	assert.Equal(t, "2020-01-01 00:01:00", buckets[0].FullAt[:19])
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryStoreSweepInterval is the minimal interval between removals of full buckets from a memory store.
+const memoryStoreSweepInterval = time.Minute
+
+type memoryBucket struct {
+	bucket
+	fullAt time.Time
+}
+
+// MemoryStore keeps token buckets in the memory of the process.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*memoryBucket)}
+}
+
+// Take takes a token from the bucket with the given key according to the policy.
+func (s *MemoryStore) Take(_ context.Context, key string, policy *Policy, now time.Time) (Result, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sweep(now)
+
+	var previous *bucket
+	if stored, ok := s.buckets[key]; ok {
+		previous = &stored.bucket
+	}
+	current, result := policy.take(previous, now)
+	s.buckets[key] = &memoryBucket{bucket: current, fullAt: now.Add(result.Reset)}
+	return result, nil
+}
+
+// sweep removes the buckets which are full again (they are the same as missing ones).
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryStoreSweepInterval {
+		return
+	}
+	for key, stored := range s.buckets {
+		if !stored.fullAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,158 @@
+// Package ratelimit limits the rate of requests with token buckets.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+// KeyType is the type of the key identifying the bucket of a request.
+type KeyType string
+
+const (
+	// KeyUser means that each user has its own bucket (the IP address is used for requests without a user).
+	// The routes authenticated by task tokens use the user of the task token.
+	KeyUser KeyType = "user"
+	// KeySession means that each session has its own bucket (the IP address is used for requests without a session).
+	// The routes authenticated by task tokens have no session, so they use the user of the task token instead.
+	KeySession KeyType = "session"
+	// KeyIP means that each IP address has its own bucket.
+	KeyIP KeyType = "ip"
+)
+
+// Policy is a token bucket policy: a bucket contains at most Limit tokens,
+// it is refilled with Limit tokens per Period, and each request takes one token.
+type Policy struct {
+	Limit  int
+	Period time.Duration
+	Key    KeyType
+}
+
+func (p *Policy) validate() error {
+	if p.Limit <= 0 {
+		return errors.New("the limit should be positive")
+	}
+	if p.Period <= 0 {
+		return errors.New("the period should be positive")
+	}
+	switch p.Key {
+	case KeyUser, KeySession, KeyIP:
+		return nil
+	default:
+		return fmt.Errorf("unknown key: %q", p.Key)
+	}
+}
+
+// Result is the state of a bucket after taking a token from it.
+type Result struct {
+	// Allowed is true if a token has been taken (the request can be processed).
+	Allowed bool
+	// Remaining is the number of whole tokens left in the bucket.
+	Remaining int
+	// Reset is the time remaining until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time remaining until a token is available (zero if Allowed).
+	RetryAfter time.Duration
+}
+
+// bucket is the state of a token bucket at some moment.
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// take refills the bucket (or creates a full one) up to the given moment and takes a token from it if possible.
+func (p *Policy) take(previous *bucket, now time.Time) (bucket, Result) {
+	tokensPerNanosecond := float64(p.Limit) / float64(p.Period)
+	current := bucket{tokens: float64(p.Limit), updatedAt: now}
+	if previous != nil {
+		elapsed := now.Sub(previous.updatedAt)
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		current.tokens = math.Min(float64(p.Limit), previous.tokens+float64(elapsed)*tokensPerNanosecond)
+	}
+
+	var result Result
+	if current.tokens >= 1 {
+		current.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - current.tokens) / tokensPerNanosecond))
+	}
+	result.Remaining = int(math.Floor(current.tokens))
+	result.Reset = current.fullIn(p)
+	return current, result
+}
+
+// fullIn returns the time remaining until the bucket is full again.
+func (b *bucket) fullIn(p *Policy) time.Duration {
+	return time.Duration(math.Ceil((float64(p.Limit) - b.tokens) * float64(p.Period) / float64(p.Limit)))
+}
+
+// Store keeps the states of token buckets.
+type Store interface {
+	// Take takes a token from the bucket with the given key according to the policy.
+	Take(ctx context.Context, key string, policy *Policy, now time.Time) (Result, error)
+}
+
+// Limiter limits the rate of requests of route groups, each route group having its own policy.
+type Limiter struct {
+	store    Store
+	policies map[string]*Policy
+	now      func() time.Time
+}
+
+// NewLimiter creates a limiter configured in the server config:
+//   - 'rateLimitsStore' is the store of the buckets: 'memory' (the default, buckets are kept by each process)
+//     or 'database' (buckets are shared by all the processes using the database),
+//   - 'rateLimits' maps route groups to their policies ('limit', 'period' & 'key'),
+//     the requests of route groups without a policy are not limited.
+func NewLimiter(serverConfig *viper.Viper, db *database.DB) (*Limiter, error) {
+	var policies map[string]*Policy
+	if err := serverConfig.UnmarshalKey("rateLimits", &policies); err != nil {
+		return nil, err
+	}
+	for routeGroup, policy := range policies {
+		if err := policy.validate(); err != nil {
+			return nil, fmt.Errorf("wrong policy of %q: %w", routeGroup, err)
+		}
+	}
+
+	var store Store
+	switch storeType := serverConfig.GetString("rateLimitsStore"); storeType {
+	case "", "memory":
+		store = NewMemoryStore()
+	case "database":
+		store = NewDBStore(db)
+	default:
+		return nil, fmt.Errorf("unknown rate limits store: %q", storeType)
+	}
+	return &Limiter{store: store, policies: policies, now: time.Now}, nil
+}
+
+// Policy returns the policy of the given route group (nil if the requests of the route group are not limited).
+func (l *Limiter) Policy(routeGroup string) *Policy {
+	return l.policies[strings.ToLower(routeGroup)]
+}
+
+// Take takes a token from the bucket of the given route group and key (as returned by keyFunc for the key type of the policy).
+// The requests of route groups without a policy are always allowed (a nil policy is returned).
+func (l *Limiter) Take(
+	ctx context.Context, routeGroup string, keyFunc func(keyType KeyType) string,
+) (*Policy, Result, error) {
+	policy := l.Policy(routeGroup)
+	if policy == nil {
+		return nil, Result{Allowed: true}, nil
+	}
+	result, err := l.store.Take(ctx, strings.ToLower(routeGroup)+":"+keyFunc(policy.Key), policy, l.now())
+	return policy, result, err
+}
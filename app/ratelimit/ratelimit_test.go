@@ -0,0 +1,164 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_take(t *testing.T) {
+	policy := &Policy{Limit: 3, Period: time.Minute, Key: KeyIP}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	state, result := policy.take(nil, now)
+	assert.Equal(t, bucket{tokens: 2, updatedAt: now}, state)
+	assert.Equal(t, Result{Allowed: true, Remaining: 2, Reset: 20 * time.Second}, result)
+
+	state, result = policy.take(&state, now)
+	assert.Equal(t, Result{Allowed: true, Remaining: 1, Reset: 40 * time.Second}, result)
+	state, result = policy.take(&state, now)
+	assert.Equal(t, Result{Allowed: true, Remaining: 0, Reset: time.Minute}, result)
+
+	state, result = policy.take(&state, now.Add(5*time.Second))
+	assert.Equal(t, 0.25, state.tokens)
+	assert.Equal(t, Result{Allowed: false, Remaining: 0, Reset: 55 * time.Second, RetryAfter: 15 * time.Second}, result)
+
+	// one token is refilled every 20 seconds
+	state, result = policy.take(&state, now.Add(25*time.Second))
+	assert.Equal(t, Result{Allowed: true, Remaining: 0, Reset: 55 * time.Second}, result)
+
+	// the bucket is never filled over the limit
+	state, result = policy.take(&state, now.Add(time.Hour))
+	assert.Equal(t, Result{Allowed: true, Remaining: 2, Reset: 20 * time.Second}, result)
+
+	// the time going backwards refills nothing
+	_, result = policy.take(&state, now)
+	assert.Equal(t, Result{Allowed: true, Remaining: 1, Reset: 40 * time.Second}, result)
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	store := NewMemoryStore()
+	policy := &Policy{Limit: 1, Period: time.Minute, Key: KeyIP}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	result, err := store.Take(context.Background(), "group:ip:1.1.1.1", policy, now)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	result, err = store.Take(context.Background(), "group:ip:1.1.1.1", policy, now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, Result{Allowed: false, Reset: 30 * time.Second, RetryAfter: 30 * time.Second}, result)
+
+	result, err = store.Take(context.Background(), "group:ip:2.2.2.2", policy, now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Len(t, store.buckets, 2)
+
+	// the full buckets are removed
+	result, err = store.Take(context.Background(), "group:ip:3.3.3.3", policy, now.Add(100*time.Second))
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Len(t, store.buckets, 1)
+	assert.Contains(t, store.buckets, "group:ip:3.3.3.3")
+}
+
+func TestNewLimiter(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+	require.NoError(t, config.ReadConfig(strings.NewReader(`
+rateLimits:
+  code-check: {limit: 10, period: 1m, key: user}
+  temp-user: {limit: 2, period: 1h, key: ip}
+`)))
+
+	limiter, err := NewLimiter(config, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryStore{}, limiter.store)
+	assert.Equal(t, &Policy{Limit: 10, Period: time.Minute, Key: KeyUser}, limiter.Policy("code-check"))
+	assert.Equal(t, &Policy{Limit: 2, Period: time.Hour, Key: KeyIP}, limiter.Policy("Temp-User"))
+	assert.Nil(t, limiter.Policy("answers"))
+
+	config.Set("rateLimitsStore", "database")
+	limiter, err = NewLimiter(config, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &DBStore{}, limiter.store)
+}
+
+func TestNewLimiter_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:          "unknown store",
+			config:        map[string]interface{}{"rateLimitsStore": "redis"},
+			expectedError: `unknown rate limits store: "redis"`,
+		},
+		{
+			name: "wrong limit",
+			config: map[string]interface{}{"rateLimits": map[string]interface{}{
+				"answers": map[string]interface{}{"period": "1m", "key": "ip"},
+			}},
+			expectedError: `wrong policy of "answers": the limit should be positive`,
+		},
+		{
+			name: "wrong period",
+			config: map[string]interface{}{"rateLimits": map[string]interface{}{
+				"answers": map[string]interface{}{"limit": 1, "key": "ip"},
+			}},
+			expectedError: `wrong policy of "answers": the period should be positive`,
+		},
+		{
+			name: "unknown key",
+			config: map[string]interface{}{"rateLimits": map[string]interface{}{
+				"answers": map[string]interface{}{"limit": 1, "period": "1m", "key": "login"},
+			}},
+			expectedError: `wrong policy of "answers": unknown key: "login"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			config := viper.New()
+			require.NoError(t, config.MergeConfigMap(tt.config))
+			limiter, err := NewLimiter(config, nil)
+			assert.Nil(t, limiter)
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestLimiter_Take(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := &Limiter{
+		store:    NewMemoryStore(),
+		policies: map[string]*Policy{"code-check": {Limit: 1, Period: time.Minute, Key: KeySession}},
+		now:      func() time.Time { return now },
+	}
+
+	var requestedKeyTypes []KeyType
+	keyFunc := func(keyType KeyType) string {
+		requestedKeyTypes = append(requestedKeyTypes, keyType)
+		return "session:1"
+	}
+	policy, result, err := limiter.Take(context.Background(), "code-check", keyFunc)
+	require.NoError(t, err)
+	assert.Equal(t, limiter.policies["code-check"], policy)
+	assert.True(t, result.Allowed)
+	_, result, err = limiter.Take(context.Background(), "code-check", keyFunc)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, []KeyType{KeySession, KeySession}, requestedKeyTypes)
+	assert.Contains(t, limiter.store.(*MemoryStore).buckets, "code-check:session:1")
+
+	policy, result, err = limiter.Take(context.Background(), "answers", keyFunc)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+	assert.True(t, result.Allowed)
+	assert.Len(t, requestedKeyTypes, 2)
+}
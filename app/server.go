@@ -10,9 +10,11 @@ import (
 	"time"
 )
 
-// Server provides an http.Server.
+// Server provides an http.Server
+// (along with another http.Server serving the metrics on a separate port if metrics are enabled).
 type Server struct {
 	*http.Server
+	metricsServer *http.Server
 }
 
 // NewServer creates and configures an APIServer serving all application routes.
@@ -22,6 +24,7 @@ func NewServer(app *Application) (*Server, error) {
 	serverConfig.SetDefault("port", 8080)
 	serverConfig.SetDefault("readTimeout", 60)
 	serverConfig.SetDefault("writeTimeout", 60)
+	serverConfig.SetDefault("metricsPort", 9464)
 
 	srv := http.Server{
 		Addr:         fmt.Sprintf(":%d", serverConfig.GetInt("Port")),
@@ -30,7 +33,16 @@ func NewServer(app *Application) (*Server, error) {
 		Handler:      app.HTTPHandler,
 	}
 
-	return &Server{&srv}, nil
+	server := &Server{Server: &srv}
+	if app.MetricsHandler != nil {
+		server.metricsServer = &http.Server{
+			Addr:         fmt.Sprintf(":%d", serverConfig.GetInt("MetricsPort")),
+			ReadTimeout:  srv.ReadTimeout,
+			WriteTimeout: srv.WriteTimeout,
+			Handler:      app.MetricsHandler,
+		}
+	}
+	return server, nil
 }
 
 // Start runs ListenAndServe on the http.Server with graceful shutdown.
@@ -49,19 +61,35 @@ func (srv *Server) Start() chan error {
 		}
 	}()
 	log.Printf("Listening on %s\n", srv.Addr)
+	metricsServerErrChannel := make(chan error, 1)
+	if srv.metricsServer != nil {
+		go func() {
+			if err := srv.metricsServer.ListenAndServe(); err != http.ErrServerClosed {
+				metricsServerErrChannel <- err
+			}
+		}()
+		log.Printf("Serving metrics on %s\n", srv.metricsServer.Addr)
+	}
 
 	// dealing with termination
 	go func() {
 		select {
 		case err := <-serverErrChannel:
+			srv.shutdownMetricsServer()
 			if err != nil {
 				doneChannel <- fmt.Errorf("server returned an error: %v", err)
 			} else {
 				doneChannel <- nil
 			}
+		case err := <-metricsServerErrChannel:
+			log.Println("Shutting down server... Reason: the metrics server returned an error")
+			_ = srv.Shutdown(context.Background())
+			<-serverErrChannel
+			doneChannel <- fmt.Errorf("metrics server returned an error: %v", err)
 		case sig := <-quit:
 			log.Println("Shutting down server... Reason:", sig)
 			shutdownErr := srv.Shutdown(context.Background())
+			srv.shutdownMetricsServer()
 			if serverErr := <-serverErrChannel; serverErr != nil {
 				doneChannel <- fmt.Errorf("server returned an error: %v", serverErr)
 			} else if shutdownErr != nil {
@@ -77,3 +105,9 @@ func (srv *Server) Start() chan error {
 	}()
 	return doneChannel
 }
+
+func (srv *Server) shutdownMetricsServer() {
+	if srv.metricsServer != nil {
+		_ = srv.metricsServer.Shutdown(context.Background())
+	}
+}
@@ -40,6 +40,63 @@ func TestServer_Start(t *testing.T) {
 	}
 }
 
+func TestServer_Start_ServesMetricsOnSeparatePort(t *testing.T) {
+	app, err := New()
+	assert.NoError(t, err)
+	app.Config.Set("server.metricsEnabled", true)
+	app.Config.Set("server.metricsPort", 8089)
+	assert.NoError(t, app.Reset(app.Config))
+	srv, err := NewServer(app)
+	assert.NoError(t, err)
+	assert.Equal(t, ":8089", srv.metricsServer.Addr)
+
+	doneChannel := srv.Start()
+	defer close(doneChannel)
+
+	var response *http.Response
+	assert.Eventually(t, func() bool {
+		response, err = http.Get("http://127.0.0.1:8089/metrics") //nolint:noctx
+		return err == nil
+	}, 3*time.Second, 10*time.Millisecond)
+	if response != nil {
+		_ = response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+
+	err = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+	assert.NoError(t, err)
+
+	select {
+	case err = <-doneChannel:
+		assert.NoError(t, err)
+	case <-time.After(3 * time.Second):
+		assert.Fail(t, "Timeout on waiting for server to stop")
+	}
+	_, err = http.Get("http://127.0.0.1:8089/metrics") //nolint:noctx
+	assert.Error(t, err)
+}
+
+func TestServer_Start_HandlesMetricsListenerError(t *testing.T) {
+	app, err := New()
+	assert.NoError(t, err)
+	app.Config.Set("server.metricsEnabled", true)
+	app.Config.Set("server.metricsPort", -1)
+	assert.NoError(t, app.Reset(app.Config))
+	srv, err := NewServer(app)
+	assert.NoError(t, err)
+
+	doneChannel := srv.Start()
+	defer close(doneChannel)
+
+	select {
+	case err = <-doneChannel:
+		assert.Equal(t, errors.New("metrics server returned an error: listen tcp: address -1: invalid port"), err)
+	case <-time.After(3 * time.Second):
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+		assert.Fail(t, "Timeout on waiting for server to stop")
+	}
+}
+
 func TestServer_Start_HandlesListenerError(t *testing.T) {
 	app, err := New()
 	assert.NoError(t, err)
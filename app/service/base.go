@@ -9,16 +9,21 @@ import (
 	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/domain"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/lti"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/ratelimit"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/token"
 )
 
 // Base is the common service context data.
 type Base struct {
-	store        *database.DataStore
-	ServerConfig *viper.Viper
-	AuthConfig   *viper.Viper
-	DomainConfig []domain.ConfigItem
-	TokenConfig  *token.Config
+	store             *database.DataStore
+	ServerConfig      *viper.Viper
+	AuthConfig        *viper.Viper
+	DomainConfig      []domain.ConfigItem
+	TokenConfig       *token.Config
+	RateLimiter       *ratelimit.Limiter
+	IdentityProviders *auth.IdentityProviders
+	LTIKeySets        *lti.KeySets
 }
 
 // SetGlobalStore sets the global store shared by all the request (should be called only once on start).
@@ -48,3 +53,9 @@ func (srv *Base) GetStore(r *http.Request) *database.DataStore {
 func (srv *Base) GetPropagationEndpoint() string {
 	return srv.ServerConfig.GetString("propagation_endpoint")
 }
+
+// IsPropagationQueueEnabled returns true if the propagation should be enqueued
+// to be run by the propagation worker (the 'propagation_queue' config flag).
+func (srv *Base) IsPropagationQueueEnabled() bool {
+	return srv.ServerConfig.GetBool("propagation_queue")
+}
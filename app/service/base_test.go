@@ -89,3 +89,43 @@ func TestBase_GetPropagationEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestBase_IsPropagationQueueEnabled(t *testing.T) {
+	tests := []struct {
+		name         string
+		ServerConfig func() *viper.Viper
+		want         bool
+	}{
+		{
+			name:         "should be false if no config",
+			ServerConfig: viper.New,
+			want:         false,
+		},
+		{
+			name: "should be false if the propagation queue is disabled",
+			ServerConfig: func() *viper.Viper {
+				config := viper.New()
+				config.Set("propagation_queue", false)
+				return config
+			},
+			want: false,
+		},
+		{
+			name: "should be true if the propagation queue is enabled",
+			ServerConfig: func() *viper.Viper {
+				config := viper.New()
+				config.Set("propagation_queue", true)
+				return config
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &Base{
+				ServerConfig: tt.ServerConfig(),
+			}
+			assert.Equalf(t, tt.want, srv.IsPropagationQueueEnabled(), "IsPropagationQueueEnabled()")
+		})
+	}
+}
@@ -4,15 +4,19 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/go-chi/render"
 
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 )
 
 // ErrorResponse is an extension of the response for returning errors.
 type ErrorResponse[T any] struct {
 	Response[T]
+	ErrorCode string      `json:"error_code"`           // stable code of the error (of the HTTP status if the error has no code)
 	ErrorText string      `json:"error_text,omitempty"` // application-level error message, for debugging
 	Errors    interface{} `json:"errors,omitempty"`     // form errors
 }
@@ -28,31 +32,55 @@ type APIError struct {
 var NoError = APIError{0, nil}
 
 // InsufficientAccessRightsError is an APIError to be returned when the has no access rights to perform an action.
-var InsufficientAccessRightsError = ErrForbidden(errors.New("insufficient access rights"))
-
-func (e APIError) httpResponse() render.Renderer {
+var InsufficientAccessRightsError = ErrForbidden(i18n.NewError("insufficient_access_rights"))
+
+// httpResponse generates the error response with messages localized according to the request language
+// (see requestLanguage()). Only the errors implementing i18n.LocalizableError have stable codes and translations.
+func (e APIError) httpResponse(r *http.Request) render.Renderer {
+	language := requestLanguage(r)
+	statusText := http.StatusText(e.HTTPStatusCode)
+	errorCode := strings.ReplaceAll(strings.ToLower(statusText), " ", "_")
+	message, ok := i18n.Localize(errorCode, language)
+	if !ok {
+		message = statusText
+	}
 	response := Response[*struct{}]{
 		HTTPStatusCode: e.HTTPStatusCode,
 		Success:        false,
-		Message:        http.StatusText(e.HTTPStatusCode),
+		Message:        message,
 	}
-	result := ErrorResponse[*struct{}]{Response: response}
+	result := ErrorResponse[*struct{}]{Response: response, ErrorCode: errorCode}
 	if e.Error == nil {
 		return &result
 	}
 
+	result.ErrorText = e.Error.Error() //nolint FIXME: should be disabled in prod
+	var localizableError i18n.LocalizableError
+	if errors.As(e.Error, &localizableError) {
+		result.ErrorCode = localizableError.Code()
+		result.ErrorText = localizableError.Localize(language)
+	}
 	if fieldErrors, ok := e.Error.(formdata.FieldErrors); ok {
-		result.Errors = fieldErrors
+		result.Errors = fieldErrors.LocalizeFields(language)
 	}
-
-	result.ErrorText = e.Error.Error() //nolint FIXME: should be disabled in prod
 	if len(result.ErrorText) > 0 {
-		result.ErrorText = strings.ToUpper(result.ErrorText[0:1]) + result.ErrorText[1:]
+		firstRune, size := utf8.DecodeRuneInString(result.ErrorText)
+		result.ErrorText = strings.ToUpper(string(firstRune)) + result.ErrorText[size:]
 	}
 
 	return &result
 }
 
+// requestLanguage returns the language of the request negotiated from the Accept-Language header
+// with a fallback to the default language of the authenticated user.
+func requestLanguage(r *http.Request) string {
+	var fallbackLanguages []string
+	if auth.IsUserInContext(r.Context()) {
+		fallbackLanguages = append(fallbackLanguages, auth.UserFromContext(r.Context()).DefaultLanguage)
+	}
+	return i18n.Negotiate(r.Header.Get("Accept-Language"), fallbackLanguages...)
+}
+
 // ErrInvalidRequest is for errors caused by invalid request input
 // It results in a 400 Invalid request response.
 func ErrInvalidRequest(err error) APIError {
@@ -89,6 +117,12 @@ func ErrUnprocessableEntity(err error) APIError {
 	return APIError{http.StatusUnprocessableEntity, err}
 }
 
+// ErrTooManyRequests is for errors caused by exceeding the rate limit of requests
+// It results in a 429 Too Many Requests.
+func ErrTooManyRequests(err error) APIError {
+	return APIError{http.StatusTooManyRequests, err}
+}
+
 // ErrUnexpected is for internal errors (not supposed to fail) not directly caused by the user input
 // It results in a 500 Internal Server Error response.
 func ErrUnexpected(err error) APIError {
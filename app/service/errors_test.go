@@ -3,6 +3,7 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	assertlib "github.com/stretchr/testify/assert"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/formdata"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/servicetest"
 )
@@ -36,21 +38,23 @@ func responseForHandler(appHandler service.AppHandler) *httptest.ResponseRecorde
 func TestNoErrorWithAPIError(t *testing.T) {
 	assert := assertlib.New(t)
 	recorder := responseForError(service.APIError{HTTPStatusCode: http.StatusConflict, Error: nil})
-	assert.Equal(`{"success":false,"message":"Conflict"}`+"\n", recorder.Body.String())
+	assert.Equal(`{"success":false,"message":"Conflict","error_code":"conflict"}`+"\n", recorder.Body.String())
 	assert.Equal(http.StatusConflict, recorder.Code)
 }
 
 func TestInvalidRequest(t *testing.T) {
 	assert := assertlib.New(t)
 	recorder := responseForError(service.ErrInvalidRequest(errors.New("sample invalid req")))
-	assert.Equal(`{"success":false,"message":"Bad Request","error_text":"Sample invalid req"}`+"\n", recorder.Body.String())
+	assert.Equal(`{"success":false,"message":"Bad Request",`+
+		`"error_code":"bad_request","error_text":"Sample invalid req"}`+"\n", recorder.Body.String())
 	assert.Equal(http.StatusBadRequest, recorder.Code)
 }
 
 func TestUnprocessableEntityRequest(t *testing.T) {
 	assert := assertlib.New(t)
 	recorder := responseForError(service.ErrUnprocessableEntity(errors.New("some error")))
-	assert.Equal(`{"success":false,"message":"Unprocessable Entity","error_text":"Some error"}`+"\n", recorder.Body.String())
+	assert.Equal(`{"success":false,"message":"Unprocessable Entity",`+
+		`"error_code":"unprocessable_entity","error_text":"Some error"}`+"\n", recorder.Body.String())
 	assert.Equal(http.StatusUnprocessableEntity, recorder.Code)
 }
 
@@ -58,13 +62,14 @@ func TestInvalidRequest_WithFormErrors(t *testing.T) {
 	assert := assertlib.New(t)
 
 	formErrors := make(formdata.FieldErrors)
-	formErrors["name"] = []string{"is required"}
-	formErrors["phone"] = []string{"is required", "must be a phone number"}
+	formErrors["name"] = []error{errors.New("is required")}
+	formErrors["phone"] = []error{errors.New("is required"), errors.New("must be a phone number")}
 
 	recorder := responseForError(service.ErrInvalidRequest(formErrors))
 	assert.JSONEq(`{
 			"success":false,
 			"message":"Bad Request",
+			"error_code":"invalid_input_data",
 			"error_text":"Invalid input data",
 			"errors": {
 				"name": ["is required"],
@@ -77,41 +82,124 @@ func TestInvalidRequest_WithFormErrors(t *testing.T) {
 	assert.Equal(http.StatusBadRequest, recorder.Code)
 }
 
+func TestInvalidRequest_LocalizesMessages(t *testing.T) {
+	assert := assertlib.New(t)
+
+	formErrors := make(formdata.FieldErrors)
+	formErrors["score"] = []error{i18n.NewError("missing_field"), i18n.NewError("min_number", "score", "0")}
+	formErrors["name"] = []error{errors.New("missing field")}
+
+	req, _ := http.NewRequest("GET", "/dummy", http.NoBody)
+	req.Header.Set("Accept-Language", "de-CH, fr;q=0.8")
+	recorder := httptest.NewRecorder()
+	service.AppHandler(func(http.ResponseWriter, *http.Request) service.APIError {
+		return service.ErrInvalidRequest(formErrors)
+	}).ServeHTTP(recorder, req)
+
+	assert.JSONEq(`{
+			"success":false,
+			"message":"Ungültige Anfrage",
+			"error_code":"invalid_input_data",
+			"error_text":"Ungültige Eingabedaten",
+			"errors": {
+				"score": ["fehlendes Feld", "score muss größer oder gleich 0 sein"],
+				"name": ["missing field"]
+			}
+	}`, recorder.Body.String())
+	assert.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func TestForbidden_LocalizesErrorsOfTheCatalogueOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+		wantText string
+	}{
+		{
+			name:     "error of the catalogue",
+			err:      i18n.NewError("wrong_int64_value", "item_id"),
+			wantCode: "wrong_int64_value",
+			wantText: "Valeur incorrecte pour item_id (un entier est attendu)",
+		},
+		{
+			name:     "wrapped error of the catalogue",
+			err:      fmt.Errorf("wrapped: %w", i18n.NewError("insufficient_access_rights")),
+			wantCode: "insufficient_access_rights",
+			wantText: "Droits d'accès insuffisants",
+		},
+		{
+			name:     "plain error with the text of the catalogue",
+			err:      errors.New("insufficient access rights"),
+			wantCode: "forbidden",
+			wantText: "Insufficient access rights",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/dummy", http.NoBody)
+			req.Header.Set("Accept-Language", "fr")
+			recorder := httptest.NewRecorder()
+			service.AppHandler(func(http.ResponseWriter, *http.Request) service.APIError {
+				return service.ErrForbidden(tt.err)
+			}).ServeHTTP(recorder, req)
+
+			assertlib.JSONEq(t, `{
+				"success":false,
+				"message":"Accès interdit",
+				"error_code":"`+tt.wantCode+`",
+				"error_text":"`+tt.wantText+`"
+			}`, recorder.Body.String())
+		})
+	}
+}
+
 func TestForbidden(t *testing.T) {
 	assert := assertlib.New(t)
 	recorder := responseForError(service.ErrForbidden(errors.New("sample forbidden resp")))
-	assert.Equal(`{"success":false,"message":"Forbidden","error_text":"Sample forbidden resp"}`+"\n", recorder.Body.String())
+	assert.Equal(`{"success":false,"message":"Forbidden",`+
+		`"error_code":"forbidden","error_text":"Sample forbidden resp"}`+"\n", recorder.Body.String())
 	assert.Equal(http.StatusForbidden, recorder.Code)
 }
 
 func TestUnexpected(t *testing.T) {
 	assert := assertlib.New(t)
 	recorder := responseForError(service.ErrUnexpected(errors.New("unexp err")))
-	assert.Equal(`{"success":false,"message":"Internal Server Error","error_text":"Unexp err"}`+"\n", recorder.Body.String())
+	assert.Equal(`{"success":false,"message":"Internal Server Error",`+
+		`"error_code":"internal_server_error","error_text":"Unexp err"}`+"\n", recorder.Body.String())
 	assert.Equal(http.StatusInternalServerError, recorder.Code)
 }
 
 func TestNotFound(t *testing.T) {
 	assert := assertlib.New(t)
 	recorder := responseForError(service.ErrNotFound(errors.New("some error")))
-	assert.Equal(`{"success":false,"message":"Not Found","error_text":"Some error"}`+"\n", recorder.Body.String())
+	assert.Equal(`{"success":false,"message":"Not Found","error_code":"not_found","error_text":"Some error"}`+"\n", recorder.Body.String())
 	assert.Equal(http.StatusNotFound, recorder.Code)
 }
 
 func TestRequestTimeout(t *testing.T) {
 	assert := assertlib.New(t)
 	recorder := responseForError(service.ErrRequestTimeout())
-	assert.Equal(`{"success":false,"message":"Request Timeout"}`+"\n", recorder.Body.String())
+	assert.Equal(`{"success":false,"message":"Request Timeout","error_code":"request_timeout"}`+"\n", recorder.Body.String())
 	assert.Equal(http.StatusRequestTimeout, recorder.Code)
 }
 
 func TestConflict(t *testing.T) {
 	assert := assertlib.New(t)
 	recorder := responseForError(service.ErrConflict(errors.New("conflict error")))
-	assert.Equal(`{"success":false,"message":"Conflict","error_text":"Conflict error"}`+"\n", recorder.Body.String())
+	assert.Equal(`{"success":false,"message":"Conflict","error_code":"conflict","error_text":"Conflict error"}`+"\n", recorder.Body.String())
 	assert.Equal(http.StatusConflict, recorder.Code)
 }
 
+func TestTooManyRequests(t *testing.T) {
+	assert := assertlib.New(t)
+	recorder := responseForError(service.ErrTooManyRequests(errors.New("too many requests")))
+	assert.Equal(`{"success":false,"message":"Too Many Requests",`+
+		`"error_code":"too_many_requests","error_text":"Too many requests"}`+"\n", recorder.Body.String())
+	assert.Equal(http.StatusTooManyRequests, recorder.Code)
+}
+
 func TestRendersErrUnexpectedOnPanicWithError(t *testing.T) {
 	assert := assertlib.New(t)
 	handler, hook, restoreFunc := servicetest.WithLoggingMiddleware(
@@ -121,7 +209,7 @@ func TestRendersErrUnexpectedOnPanicWithError(t *testing.T) {
 	defer restoreFunc()
 
 	recorder := responseForHTTPHandler(handler)
-	assert.Equal(`{"success":false,"message":"Internal Server Error","error_text":"Some error"}`+"\n",
+	assert.Equal(`{"success":false,"message":"Internal Server Error","error_code":"internal_server_error","error_text":"Some error"}`+"\n",
 		recorder.Body.String())
 	assert.Equal(http.StatusInternalServerError, recorder.Code)
 	assert.Contains(hook.GetAllLogs(), "unexpected error: some error")
@@ -136,7 +224,8 @@ func TestRendersRecoveredAPIErrorOnPanicWithAPIError(t *testing.T) {
 	defer restoreFunc()
 
 	recorder := responseForHTTPHandler(handler)
-	assert.Equal(`{"success":false,"message":"Forbidden","error_text":"Insufficient access rights"}`+"\n",
+	assert.Equal(`{"success":false,"message":"Forbidden",`+
+		`"error_code":"insufficient_access_rights","error_text":"Insufficient access rights"}`+"\n",
 		recorder.Body.String())
 	assert.Equal(http.StatusForbidden, recorder.Code)
 	assert.NotContains(strings.ToLower(hook.GetAllLogs()), "error")
@@ -152,7 +241,8 @@ func TestRendersErrUnexpectedOnPanicWithSomeValue(t *testing.T) {
 	defer restoreFunc()
 
 	recorder := responseForHTTPHandler(handler)
-	assert.Equal(`{"success":false,"message":"Internal Server Error","error_text":"Unknown error: `+expectedMessage+`"}`+"\n",
+	assert.Equal(`{"success":false,"message":"Internal Server Error",`+
+		`"error_code":"internal_server_error","error_text":"Unknown error: `+expectedMessage+`"}`+"\n",
 		recorder.Body.String())
 	assert.Equal(http.StatusInternalServerError, recorder.Code)
 	assert.Contains(hook.GetAllLogs(), "unexpected error: unknown error: some error")
@@ -166,7 +256,7 @@ func TestRendersErrRequestTimeoutOnPanicContextDeadlineExceeded(t *testing.T) {
 	defer restoreFunc()
 
 	recorder := responseForHTTPHandler(handler)
-	assert.Equal(`{"success":false,"message":"Request Timeout"}`+"\n", recorder.Body.String())
+	assert.Equal(`{"success":false,"message":"Request Timeout","error_code":"request_timeout"}`+"\n", recorder.Body.String())
 	assert.Equal(http.StatusRequestTimeout, recorder.Code)
 }
 
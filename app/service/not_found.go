@@ -8,5 +8,5 @@ import (
 
 // NotFound is a basic HTTP handler which generates a 404 error.
 func NotFound(w http.ResponseWriter, r *http.Request) {
-	_ = render.Render(w, r, ErrNotFound(nil).httpResponse()) // nolint, never fails
+	_ = render.Render(w, r, ErrNotFound(nil).httpResponse(r)) // nolint, never fails
 }
@@ -15,6 +15,6 @@ func TestNotFound(t *testing.T) {
 
 	NotFound(recorder, req)
 
-	assert.Equal(`{"success":false,"message":"Not Found"}`+"\n", recorder.Body.String())
+	assert.Equal(`{"success":false,"message":"Not Found","error_code":"not_found"}`+"\n", recorder.Body.String())
 	assert.Equal(http.StatusNotFound, recorder.Code)
 }
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/go-chi/chi"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 )
 
 // ResolveURLQueryGetInt64SliceField extracts from the query parameter of the request a list of integer separated by commas (',')
@@ -28,7 +30,7 @@ func ResolveURLQueryGetInt64SliceField(req *http.Request, paramName string) ([]i
 	for _, idStr := range idsStr {
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse one of the integers given as query args (value: '%s', param: '%s')", idStr, paramName)
+			return nil, i18n.NewError("wrong_ids_list", idStr, paramName)
 		}
 		ids = append(ids, id)
 	}
@@ -43,7 +45,7 @@ func ResolveURLQueryGetInt64Field(httpReq *http.Request, name string) (int64, er
 	strValue := httpReq.URL.Query().Get(name)
 	int64Value, err := strconv.ParseInt(strValue, 10, 64)
 	if err != nil {
-		return 0, fmt.Errorf("wrong value for %s (should be int64)", name)
+		return 0, i18n.NewError("wrong_int64_value", name)
 	}
 	return int64Value, nil
 }
@@ -117,7 +119,7 @@ func ResolveURLQueryGetTimeField(httpReq *http.Request, name string) (time.Time,
 	}
 	result, err := time.Parse(time.RFC3339Nano, httpReq.URL.Query().Get(name))
 	if err != nil {
-		return time.Time{}, fmt.Errorf("wrong value for %s (should be time (rfc3339Nano))", name)
+		return time.Time{}, i18n.NewError("wrong_time_value", name)
 	}
 	return result, nil
 }
@@ -135,7 +137,7 @@ func ResolveURLQueryGetBoolField(httpReq *http.Request, name string) (bool, erro
 	if strValue == "1" {
 		return true, nil
 	}
-	return false, fmt.Errorf("wrong value for %s (should have a boolean value (0 or 1))", name)
+	return false, i18n.NewError("wrong_boolean_value", name)
 }
 
 // ResolveURLQueryGetBoolFieldWithDefault extracts a get-parameter of type bool (0 or 1) from the query.
@@ -153,18 +155,18 @@ func ResolveURLQueryGetBoolFieldWithDefault(httpReq *http.Request, name string,
 		return true, nil
 	}
 
-	return false, fmt.Errorf("wrong value for %s (should have a boolean value (0 or 1))", name)
+	return false, i18n.NewError("wrong_boolean_value", name)
 }
 
 // ResolveURLQueryPathInt64Field extracts a path element of type int64 from the query.
 func ResolveURLQueryPathInt64Field(httpReq *http.Request, name string) (int64, error) {
 	strValue := chi.URLParam(httpReq, name)
 	if strValue == "" {
-		return 0, fmt.Errorf("missing %s", name)
+		return 0, i18n.NewError("missing_parameter", name)
 	}
 	int64Value, err := strconv.ParseInt(strValue, 10, 64)
 	if err != nil {
-		return 0, fmt.Errorf("wrong value for %s (should be int64)", name)
+		return 0, i18n.NewError("wrong_int64_value", name)
 	}
 	return int64Value, nil
 }
@@ -176,7 +178,7 @@ func URLQueryPathHasField(httpReq *http.Request, name string) bool {
 
 func checkQueryGetFieldIsNotMissing(httpReq *http.Request, name string) error {
 	if !URLQueryPathHasField(httpReq, name) {
-		return fmt.Errorf("missing %s", name)
+		return i18n.NewError("missing_parameter", name)
 	}
 
 	return nil
@@ -194,7 +196,7 @@ func ResolveURLQueryPathInt64SliceField(req *http.Request, paramName string) ([]
 	for _, idStr := range idsStr {
 		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse one of the integers given as query args (value: '%s', param: '%s')", idStr, paramName)
+			return nil, i18n.NewError("wrong_ids_list", idStr, paramName)
 		}
 		ids = append(ids, id)
 	}
@@ -209,7 +211,7 @@ func ResolveURLQueryPathInt64SliceFieldWithLimit(r *http.Request, paramName stri
 		return nil, err
 	}
 	if len(ids) > limit {
-		return nil, fmt.Errorf("no more than %d %s expected", limit, paramName)
+		return nil, i18n.NewError("too_many_values", limit, paramName)
 	}
 	return ids, nil
 }
@@ -221,7 +223,7 @@ func ResolveJSONBodyIntoMap(r *http.Request) (map[string]interface{}, APIError)
 	defer func() { _, _ = io.Copy(io.Discard, r.Body) }()
 	err := json.NewDecoder(r.Body).Decode(&rawRequestData)
 	if err != nil {
-		return nil, ErrInvalidRequest(fmt.Errorf("invalid input JSON: %v", err))
+		return nil, ErrInvalidRequest(i18n.NewError("invalid_input_json", err))
 	}
 	return rawRequestData, NoError
 }
@@ -2,7 +2,7 @@ package service
 
 import (
 	"errors"
-	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -14,6 +14,8 @@ import (
 	"bou.ke/monkey"
 	"github.com/go-chi/chi"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 )
 
 func TestResolveURLQueryGetInt64SliceField(t *testing.T) {
@@ -613,7 +615,7 @@ func TestResolveURLQueryPathInt64SliceFieldWithLimit(t *testing.T) {
 			limit:      2,
 			mockResult: []int64{1, 2, 3},
 			want:       nil,
-			wantErr:    fmt.Errorf("no more than %d %s expected", 2, expectedParamName),
+			wantErr:    i18n.NewError("too_many_values", 2, expectedParamName),
 		},
 		{
 			name:       "error",
@@ -651,7 +653,7 @@ func TestResolveJSONBodyIntoMap(t *testing.T) {
 			name:             "empty body",
 			body:             "",
 			expectedMap:      nil,
-			expectedAPIError: ErrInvalidRequest(errors.New("invalid input JSON: EOF")),
+			expectedAPIError: ErrInvalidRequest(i18n.NewError("invalid_input_json", io.EOF)),
 		},
 		{
 			name:             "empty json",
@@ -669,7 +671,7 @@ func TestResolveJSONBodyIntoMap(t *testing.T) {
 			name:             "invalid json",
 			body:             `{"key":"value"`,
 			expectedMap:      nil,
-			expectedAPIError: ErrInvalidRequest(errors.New("invalid input JSON: unexpected EOF")),
+			expectedAPIError: ErrInvalidRequest(i18n.NewError("invalid_input_json", io.ErrUnexpectedEOF)),
 		},
 	}
 	for _, testCase := range testCases {
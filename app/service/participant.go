@@ -2,13 +2,13 @@ package service
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/go-chi/render"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 )
 
 type participantMiddlewareKey int
@@ -31,7 +31,7 @@ func ParticipantMiddleware(srv GetStorer) func(next http.Handler) http.Handler {
 			participantID, apiError := GetParticipantIDFromRequest(r, user, srv.GetStore(r))
 			if apiError != NoError {
 				w.Header().Set("Content-Type", "application/json; charset=utf-8")
-				_ = render.Render(w, r, apiError.httpResponse())
+				_ = render.Render(w, r, apiError.httpResponse(r))
 				return
 			}
 
@@ -62,7 +62,7 @@ func GetParticipantIDFromRequest(httpReq *http.Request, user *database.User, sto
 		found, err = store.Groups().TeamGroupForUser(groupID, user).HasRows()
 		MustNotBeError(err)
 		if !found {
-			return 0, ErrForbidden(errors.New("can't use given as_team_id as a user's team"))
+			return 0, ErrForbidden(i18n.NewError("invalid_team"))
 		}
 	}
 	return groupID, NoError
@@ -3,7 +3,6 @@
 package service_test
 
 import (
-	"fmt"
 	"net/http"
 	"net/url"
 	"testing"
@@ -11,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
 	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
@@ -42,17 +42,17 @@ func TestGetParticipantIDFromRequest(t *testing.T) {
 		{
 			name:          "no team",
 			query:         "as_team_id=404",
-			expectedError: service.ErrForbidden(fmt.Errorf("can't use given as_team_id as a user's team")),
+			expectedError: service.ErrForbidden(i18n.NewError("invalid_team")),
 		},
 		{
 			name:          "as_team_id is not a team",
 			query:         "param&as_team_id=1",
-			expectedError: service.ErrForbidden(fmt.Errorf("can't use given as_team_id as a user's team")),
+			expectedError: service.ErrForbidden(i18n.NewError("invalid_team")),
 		},
 		{
 			name:          "the current user is not a member of as_team_id",
 			query:         "as_team_id=2",
-			expectedError: service.ErrForbidden(fmt.Errorf("can't use given as_team_id as a user's team")),
+			expectedError: service.ErrForbidden(i18n.NewError("invalid_team")),
 		},
 		{
 			name:           "okay",
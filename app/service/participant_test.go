@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"errors"
-	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -17,6 +16,7 @@ import (
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 )
 
 func TestGetParticipantIDFromRequest_NoAsTeamID(t *testing.T) {
@@ -35,7 +35,7 @@ func TestGetParticipantIDFromRequest_InvalidAsTeamID(t *testing.T) {
 	participantID, apiError := GetParticipantIDFromRequest(
 		&http.Request{URL: &url.URL{RawQuery: "as_team_id=abc"}}, &database.User{GroupID: 123}, database.NewDataStore(db))
 	assert.Equal(t, int64(0), participantID)
-	assert.Equal(t, ErrInvalidRequest(fmt.Errorf("wrong value for as_team_id (should be int64)")), apiError)
+	assert.Equal(t, ErrInvalidRequest(i18n.NewError("wrong_int64_value", "as_team_id")), apiError)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -71,7 +71,7 @@ func TestParticipantMiddleware(t *testing.T) {
 			apiError:                 ErrForbidden(errors.New("some error")),
 			expectedServiceWasCalled: false,
 			expectedStatusCode:       403,
-			expectedBody:             `{"success":false,"message":"Forbidden","error_text":"Some error"}`,
+			expectedBody:             `{"success":false,"message":"Forbidden","error_code":"forbidden","error_text":"Some error"}`,
 		},
 	}
 	for _, tt := range tests {
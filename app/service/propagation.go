@@ -1,12 +1,16 @@
 package service
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/tracing"
 )
 
 // PropagationEndpointTimeout is the timeout for the propagation endpoint.
@@ -19,11 +23,17 @@ func SchedulePropagation(store *database.DataStore, endpoint string, types []str
 	if endpoint != "" {
 		// Async.
 		client := http.Client{
-			Timeout: PropagationEndpointTimeout,
+			Timeout:   PropagationEndpointTimeout,
+			Transport: &tracing.Transport{},
 		}
 
+		// the call should not be canceled with the request, only the span is passed
+		requestContext := trace.ContextWithSpan(context.Background(), trace.SpanFromContext(store.GetContext()))
+		request, err := http.NewRequestWithContext(requestContext, http.MethodGet, endpoint+"?types="+strings.Join(types, ","), http.NoBody)
+		MustNotBeError(err)
+
 		callTime := time.Now()
-		response, err := client.Get(endpoint + "?types=" + strings.Join(types, ","))
+		response, err := client.Do(request)
 		logging.SharedLogger.WithContext(store.GetContext()).
 			Infof("Propagation endpoint called: %v, types=%v, duration=%v", endpoint, types, time.Since(callTime))
 
@@ -61,3 +71,15 @@ func SchedulePropagation(store *database.DataStore, endpoint string, types []str
 		}
 	}
 }
+
+// SchedulePropagation schedules propagation of the given types.
+// If the propagation queue is enabled in the config, the propagation is enqueued to be run by the propagation worker
+// (together with the current transaction if any). Otherwise, the propagation endpoint from the config is used.
+func (srv *Base) SchedulePropagation(store *database.DataStore, types []string) {
+	if srv.IsPropagationQueueEnabled() {
+		MustNotBeError(store.PropagationQueue().Enqueue(types))
+		return
+	}
+
+	SchedulePropagation(store, srv.GetPropagationEndpoint(), types)
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/ratelimit"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/token"
+)
+
+// RateLimitMiddleware limits the rate of requests with the policy of the given route group (see ratelimit.NewLimiter).
+// The requests are keyed by the user (so the middleware should go after auth.UserMiddleware to use it), by the session,
+// or by the IP address. The state of the bucket is sent in the RateLimit-* headers
+// and the requests over the limit get the 'too many requests' error with the Retry-After header.
+func (srv *Base) RateLimitMiddleware(routeGroup string) func(next http.Handler) http.Handler {
+	return srv.rateLimitMiddleware(routeGroup, authenticatedRateLimitKey)
+}
+
+// TaskTokenRateLimitMiddleware is RateLimitMiddleware for services identifying the user by the 'task_token'
+// of the JSON body instead of auth.UserMiddleware. The requests are keyed by the user of the (verified) task token
+// for both the 'user' and 'session' keys as there is no session. The requests without a valid task token
+// are keyed by the IP address (the services reject them anyway).
+func (srv *Base) TaskTokenRateLimitMiddleware(routeGroup string) func(next http.Handler) http.Handler {
+	return srv.rateLimitMiddleware(routeGroup, srv.taskTokenRateLimitKey)
+}
+
+// rateLimitKeyFunc returns the key of the bucket of a request for the 'user' and 'session' key types
+// (false if the request has no user or session, so that the request is keyed by the IP address).
+type rateLimitKeyFunc func(r *http.Request, keyType ratelimit.KeyType) (string, bool)
+
+func (srv *Base) rateLimitMiddleware(routeGroup string, keyFunc rateLimitKeyFunc) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return AppHandler(func(w http.ResponseWriter, r *http.Request) APIError {
+			if srv.RateLimiter == nil {
+				next.ServeHTTP(w, r)
+				return NoError
+			}
+
+			policy, result, err := srv.RateLimiter.Take(r.Context(), routeGroup, func(keyType ratelimit.KeyType) string {
+				return rateLimitKey(r, keyType, keyFunc)
+			})
+			MustNotBeError(err)
+			if policy == nil {
+				next.ServeHTTP(w, r)
+				return NoError
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(policy.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(result.Reset), 10))
+			w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, ceilSeconds(policy.Period)))
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.FormatInt(ceilSeconds(result.RetryAfter), 10))
+				return ErrTooManyRequests(errors.New("too many requests, retry later"))
+			}
+
+			next.ServeHTTP(w, r)
+			return NoError
+		})
+	}
+}
+
+func rateLimitKey(r *http.Request, keyType ratelimit.KeyType, keyFunc rateLimitKeyFunc) string {
+	if keyType != ratelimit.KeyIP {
+		if key, ok := keyFunc(r, keyType); ok {
+			return key
+		}
+	}
+
+	// the remote address is set to the real IP address of the client by middleware.RealIP
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return "ip:" + ip
+}
+
+func authenticatedRateLimitKey(r *http.Request, keyType ratelimit.KeyType) (string, bool) {
+	ctx := r.Context()
+	if !auth.IsUserInContext(ctx) {
+		return "", false
+	}
+	if keyType == ratelimit.KeySession {
+		sessionID := auth.SessionIDFromContext(ctx)
+		return "session:" + strconv.FormatInt(sessionID, 10), sessionID != 0
+	}
+	return "user:" + strconv.FormatInt(auth.UserFromContext(ctx).GroupID, 10), true
+}
+
+// taskTokenRateLimitKey keys the request by the user of the task token of the JSON body
+// (the body is restored for the next handlers).
+func (srv *Base) taskTokenRateLimitKey(r *http.Request, _ ratelimit.KeyType) (string, bool) {
+	body, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return "", false
+	}
+
+	var requestData struct {
+		TaskToken *string `json:"task_token"`
+	}
+	if json.Unmarshal(body, &requestData) != nil || requestData.TaskToken == nil || srv.TokenConfig == nil {
+		return "", false
+	}
+	taskToken := token.Task{PublicKey: srv.TokenConfig.PublicKey}
+	if taskToken.UnmarshalString(*requestData.TaskToken) != nil {
+		return "", false
+	}
+	return "user:" + strconv.FormatInt(taskToken.Converted.UserID, 10), true
+}
+
+func ceilSeconds(duration time.Duration) int64 {
+	return int64(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/ratelimit"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/token"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/tokentest"
+)
+
+func TestBase_RateLimitMiddleware(t *testing.T) {
+	config := viper.New()
+	require.NoError(t, config.MergeConfigMap(map[string]interface{}{"rateLimits": map[string]interface{}{
+		"code-check": map[string]interface{}{"limit": 2, "period": "1m", "key": "user"},
+	}}))
+	limiter, err := ratelimit.NewLimiter(config, nil)
+	require.NoError(t, err)
+	srv := &Base{RateLimiter: limiter}
+
+	var calls int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	})
+	limitedHandler := auth.MockUserMiddleware(&database.User{GroupID: 42})(srv.RateLimitMiddleware("code-check")(handler))
+
+	for _, expectedRemaining := range []string{"1", "0"} {
+		recorder := httptest.NewRecorder()
+		limitedHandler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", http.NoBody))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "2", recorder.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, expectedRemaining, recorder.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2;w=60", recorder.Header().Get("RateLimit-Policy"))
+		assert.Empty(t, recorder.Header().Get("Retry-After"))
+	}
+
+	recorder := httptest.NewRecorder()
+	limitedHandler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", http.NoBody))
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", recorder.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "30", recorder.Header().Get("Retry-After"))
+	assert.Equal(t, `{"success":false,"message":"Too Many Requests",`+
+		`"error_code":"too_many_requests","error_text":"Too many requests, retry later"}`+"\n",
+		recorder.Body.String())
+	assert.Equal(t, 2, calls)
+
+	// another user has another bucket
+	recorder = httptest.NewRecorder()
+	auth.MockUserMiddleware(&database.User{GroupID: 43})(srv.RateLimitMiddleware("code-check")(handler)).
+		ServeHTTP(recorder, httptest.NewRequest("GET", "/", http.NoBody))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "1", recorder.Header().Get("RateLimit-Remaining"))
+
+	// the requests of route groups without a policy are not limited
+	recorder = httptest.NewRecorder()
+	srv.RateLimitMiddleware("answers")(handler).ServeHTTP(recorder, httptest.NewRequest("GET", "/", http.NoBody))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, 4, calls)
+}
+
+func TestBase_RateLimitMiddleware_WithoutLimiter(t *testing.T) {
+	var called bool
+	recorder := httptest.NewRecorder()
+	(&Base{}).RateLimitMiddleware("code-check")(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true })).
+		ServeHTTP(recorder, httptest.NewRequest("GET", "/", http.NoBody))
+	assert.True(t, called)
+	assert.Empty(t, recorder.Header().Get("RateLimit-Limit"))
+}
+
+func Test_rateLimitKey(t *testing.T) {
+	request := httptest.NewRequest("GET", "/", http.NoBody)
+	request.RemoteAddr = "10.0.0.1:1234"
+	assert.Equal(t, "ip:10.0.0.1", rateLimitKey(request, ratelimit.KeyIP, authenticatedRateLimitKey))
+	assert.Equal(t, "ip:10.0.0.1", rateLimitKey(request, ratelimit.KeyUser, authenticatedRateLimitKey))
+	assert.Equal(t, "ip:10.0.0.1", rateLimitKey(request, ratelimit.KeySession, authenticatedRateLimitKey))
+
+	// the remote address is only an IP address when set by middleware.RealIP
+	request.RemoteAddr = "2001:db8::1"
+	assert.Equal(t, "ip:2001:db8::1", rateLimitKey(request, ratelimit.KeyIP, authenticatedRateLimitKey))
+
+	auth.MockUserMiddleware(&database.User{GroupID: 42})(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "user:42", rateLimitKey(r, ratelimit.KeyUser, authenticatedRateLimitKey))
+		assert.Equal(t, "session:1", rateLimitKey(r, ratelimit.KeySession, authenticatedRateLimitKey))
+		assert.Equal(t, "ip:2001:db8::1", rateLimitKey(r, ratelimit.KeyIP, authenticatedRateLimitKey))
+	})).ServeHTTP(httptest.NewRecorder(), request)
+}
+
+func TestBase_taskTokenRateLimitKey(t *testing.T) {
+	taskToken, err := (&token.Task{
+		UserID: "42", AttemptID: "42/0", LocalItemID: "50", PlatformName: "test",
+		PrivateKey: tokentest.AlgoreaPlatformPrivateKeyParsed,
+	}).Sign(tokentest.AlgoreaPlatformPrivateKeyParsed)
+	require.NoError(t, err)
+	otherPlatformToken, err := (&token.Task{
+		UserID: "42", AttemptID: "42/0", LocalItemID: "50", PlatformName: "test",
+		PrivateKey: tokentest.TaskPlatformPrivateKeyParsed,
+	}).Sign(tokentest.TaskPlatformPrivateKeyParsed)
+	require.NoError(t, err)
+	srv := &Base{TokenConfig: &token.Config{PublicKey: tokentest.AlgoreaPlatformPublicKeyParsed}}
+
+	for _, test := range []struct {
+		name        string
+		body        string
+		expectedKey string
+	}{
+		{name: "valid task token", body: `{"task_token":"` + taskToken + `","answer":"print(1)"}`, expectedKey: "user:42"},
+		{name: "task token signed by another key", body: `{"task_token":"` + otherPlatformToken + `"}`, expectedKey: "ip:10.0.0.1"},
+		{name: "no task token", body: `{"answer":"print(1)"}`, expectedKey: "ip:10.0.0.1"},
+		{name: "invalid json", body: `{"task_token":`, expectedKey: "ip:10.0.0.1"},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/", strings.NewReader(test.body))
+			request.RemoteAddr = "10.0.0.1:1234"
+			assert.Equal(t, test.expectedKey, rateLimitKey(request, ratelimit.KeyUser, srv.taskTokenRateLimitKey))
+
+			// the body is restored for the next handlers
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			assert.Equal(t, test.body, string(body))
+		})
+	}
+
+	// the session key falls back to the user of the task token as there is no session
+	request := httptest.NewRequest("POST", "/", strings.NewReader(`{"task_token":"`+taskToken+`"}`))
+	assert.Equal(t, "user:42", rateLimitKey(request, ratelimit.KeySession, srv.taskTokenRateLimitKey))
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 )
 
 // FieldType represents a type of tie-breaker field's value.
@@ -197,7 +198,7 @@ func validateSortingField(
 		return fmt.Errorf("a field cannot be a sorting parameter more than once: %q", fieldName)
 	}
 	if _, ok := configuredFields[fieldName]; !ok {
-		return fmt.Errorf("unallowed field in sorting parameters: %q", fieldName)
+		return i18n.NewError("unallowed_sorting_field", fieldName)
 	}
 	if !configuredFields[fieldName].Nullable && sorting.nullPlacement != 0 {
 		return fmt.Errorf("'null last' sorting cannot be applied to a non-nullable field: %q", fieldName)
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 )
 
 func TestApplySorting(t *testing.T) {
@@ -104,7 +105,7 @@ func TestApplySorting(t *testing.T) {
 					TieBreakers:  SortingAndPagingTieBreakers{"id": FieldTypeInt64},
 				},
 			},
-			wantAPIError: ErrInvalidRequest(errors.New(`unallowed field in sorting parameters: "class"`)),
+			wantAPIError: ErrInvalidRequest(i18n.NewError("unallowed_sorting_field", "class")),
 		},
 		{
 			name: "'null last' for a non-nullable field",
@@ -355,7 +356,7 @@ func TestApplySorting(t *testing.T) {
 					TieBreakers:  SortingAndPagingTieBreakers{"id": FieldTypeInt64},
 				},
 			},
-			wantAPIError: ErrInvalidRequest(errors.New(`wrong value for from.id (should be int64)`)),
+			wantAPIError: ErrInvalidRequest(i18n.NewError("wrong_int64_value", "from.id")),
 		},
 		{
 			name: "one of the 'from.*' fields is skipped",
@@ -1,8 +1,9 @@
 package service
 
 import (
-	"errors"
 	"net/http"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 )
 
 // ResolveWatchedGroupID returns the watched_group_id parameter (if given) and checks if the current user has rights
@@ -20,7 +21,7 @@ func (srv *Base) ResolveWatchedGroupID(httpReq *http.Request) (watchedGroupID in
 
 	found := srv.GetUser(httpReq).CanWatchGroupMembers(srv.GetStore(httpReq), watchedGroupID)
 	if !found {
-		return 0, false, ErrForbidden(errors.New("no rights to watch for watched_group_id"))
+		return 0, false, ErrForbidden(i18n.NewError("no_rights_to_watch_group"))
 	}
 
 	return watchedGroupID, true, NoError
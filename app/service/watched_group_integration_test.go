@@ -3,7 +3,6 @@
 package service_test
 
 import (
-	"errors"
 	"net/http"
 	"reflect"
 	"testing"
@@ -12,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers"
 	"github.com/France-ioi/AlgoreaBackend/v2/testhelpers/testoutput"
@@ -42,7 +42,7 @@ func TestBase_ResolveWatchedGroupID(t *testing.T) {
 		func(*service.Base, *http.Request) *database.User { return &database.User{GroupID: 4} })
 	defer patch.Unpatch()
 
-	forbiddenError := service.ErrForbidden(errors.New("no rights to watch for watched_group_id"))
+	forbiddenError := service.ErrForbidden(i18n.NewError("no_rights_to_watch_group"))
 
 	tests := []struct {
 		name                    string
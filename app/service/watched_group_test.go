@@ -1,11 +1,12 @@
 package service
 
 import (
-	"errors"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/i18n"
 )
 
 func TestBase_ResolveWatchedGroupID(t *testing.T) {
@@ -22,7 +23,7 @@ func TestBase_ResolveWatchedGroupID(t *testing.T) {
 			url:                     "/dummy?watched_group_id=abc",
 			wantWatchedGroupID:      0,
 			wantWatchedGroupIDIsSet: false,
-			wantAPIError:            ErrInvalidRequest(errors.New("wrong value for watched_group_id (should be int64)")),
+			wantAPIError:            ErrInvalidRequest(i18n.NewError("wrong_int64_value", "watched_group_id")),
 		},
 	}
 
@@ -0,0 +1,108 @@
+// Package similarity computes the similarity of texts (like answers to tasks)
+// using token-based fingerprinting with the winnowing algorithm.
+package similarity
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// KGramLength is the number of consecutive tokens hashed together.
+	KGramLength = 5
+	// WindowSize is the number of consecutive k-gram hashes among which the minimal one is selected.
+	// Any common sequence of at least KGramLength + WindowSize - 1 tokens is guaranteed to be detected.
+	WindowSize = 4
+)
+
+// Fingerprint is a set of selected hashes of k-grams of a text.
+type Fingerprint map[uint64]struct{}
+
+// Tokenize splits a text into tokens: identifiers & numbers (lower-cased) and single punctuation characters.
+// White spaces are ignored.
+func Tokenize(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			current.WriteRune(unicode.ToLower(r))
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			flush()
+			tokens = append(tokens, string(r))
+		}
+	}
+	flush()
+	return tokens
+}
+
+// Compute returns the fingerprint of a text.
+func Compute(text string) Fingerprint {
+	tokens := Tokenize(text)
+	fingerprint := make(Fingerprint)
+	if len(tokens) == 0 {
+		return fingerprint
+	}
+	if len(tokens) < KGramLength {
+		fingerprint[hashKGram(tokens)] = struct{}{}
+		return fingerprint
+	}
+
+	hashes := make([]uint64, 0, len(tokens)-KGramLength+1)
+	for index := 0; index+KGramLength <= len(tokens); index++ {
+		hashes = append(hashes, hashKGram(tokens[index:index+KGramLength]))
+	}
+	if len(hashes) <= WindowSize {
+		fingerprint[minHash(hashes)] = struct{}{}
+		return fingerprint
+	}
+	for index := 0; index+WindowSize <= len(hashes); index++ {
+		fingerprint[minHash(hashes[index:index+WindowSize])] = struct{}{}
+	}
+	return fingerprint
+}
+
+// Compare returns the similarity of two fingerprints (the Jaccard index of the sets of hashes) in the [0, 1] range.
+func Compare(fingerprint1, fingerprint2 Fingerprint) float64 {
+	if len(fingerprint1) == 0 || len(fingerprint2) == 0 {
+		return 0
+	}
+	if len(fingerprint1) > len(fingerprint2) {
+		fingerprint1, fingerprint2 = fingerprint2, fingerprint1
+	}
+	var commonCount int
+	for hash := range fingerprint1 {
+		if _, ok := fingerprint2[hash]; ok {
+			commonCount++
+		}
+	}
+	return float64(commonCount) / float64(len(fingerprint1)+len(fingerprint2)-commonCount)
+}
+
+func hashKGram(tokens []string) uint64 {
+	hash := fnv.New64a()
+	for _, token := range tokens {
+		_, _ = hash.Write([]byte(token))
+		_, _ = hash.Write([]byte{0})
+	}
+	return hash.Sum64()
+}
+
+func minHash(hashes []uint64) uint64 {
+	result := hashes[0]
+	for _, hash := range hashes[1:] {
+		if hash < result {
+			result = hash
+		}
+	}
+	return result
+}
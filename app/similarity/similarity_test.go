@@ -0,0 +1,46 @@
+package similarity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t,
+		[]string{"for", "i", "in", "range", "(", "10", ")", ":", "print", "(", "my_var", "+", "i", ")"},
+		Tokenize("for i in range(10):\n\tPrint(My_Var + i)"))
+	assert.Empty(t, Tokenize(" \n\t "))
+}
+
+func TestCompute(t *testing.T) {
+	assert.Empty(t, Compute(""))
+	assert.Len(t, Compute("a b"), 1)
+	assert.Len(t, Compute("a b c d e f"), 1)
+	assert.Equal(t, Compute("x = 1\ny = 2\nprint(x + y)"), Compute("X = 1   y = 2 print( x+y )"))
+}
+
+func TestCompare(t *testing.T) {
+	program := `
+		n = int(input())
+		total = 0
+		for i in range(n):
+			total += int(input())
+		print(total)`
+	reformattedProgram := `
+		N = int(input()); TOTAL = 0
+		for i in range(N): TOTAL += int(input())
+		print(TOTAL)`
+	otherProgram := `
+		def fibonacci(k):
+			return k if k < 2 else fibonacci(k - 1) + fibonacci(k - 2)
+		print(fibonacci(int(input())))`
+
+	assert.Equal(t, 1.0, Compare(Compute(program), Compute(program)))
+	assert.Greater(t, Compare(Compute(program), Compute(reformattedProgram)), 0.5)
+	assert.Less(t, Compare(Compute(program), Compute(otherProgram)), 0.2)
+	assert.Greater(t, Compare(Compute(program), Compute(program+"\nprint(n)")), 0.5)
+	assert.Equal(t, 0.0, Compare(Compute(program), Compute("")))
+	assert.Equal(t, 0.0, Compare(Compute(""), Compute("")))
+	assert.Equal(t, Compare(Compute(program), Compute(otherProgram)), Compare(Compute(otherProgram), Compute(program)))
+}
@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware creates a span for each HTTP request (continuing the trace given in the request headers if any).
+// The span is named after the chi route pattern of the request.
+// It should go before the logger so that the trace IDs get into the logs of the request.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := StartSpan(ctx, "HTTP "+r.Method, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("http.request.method", r.Method), attribute.String("url.path", r.URL.Path)))
+		defer span.End()
+
+		wrappedWriter := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(wrappedWriter, r.WithContext(ctx))
+
+		if routeContext, ok := r.Context().Value(chi.RouteCtxKey).(*chi.Context); ok {
+			if route := routeContext.RoutePattern(); route != "" && route != "/*" {
+				span.SetName(r.Method + " " + route)
+				span.SetAttributes(attribute.String("http.route", route))
+			}
+		}
+		status := wrappedWriter.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// Transport is an http.RoundTripper creating a span for each outgoing request
+// and passing the trace context to the called service in the request headers.
+type Transport struct {
+	// Base is the transport making the requests (http.DefaultTransport if nil).
+	Base http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction in a span.
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, span := StartSpan(request.Context(), "HTTP "+request.Method, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("http.request.method", request.Method),
+			attribute.String("server.address", request.URL.Host), attribute.String("url.path", request.URL.Path)))
+	defer span.End()
+
+	request = request.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	response, err := base.RoundTrip(request)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return response, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+	if response.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(response.StatusCode))
+	}
+	return response, nil
+}
+
+var _ http.RoundTripper = &Transport{}
+
+// HTTPClient is an HTTP client tracing its requests (see Transport).
+var HTTPClient = &http.Client{Transport: &Transport{}}
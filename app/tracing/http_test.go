@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddleware(t *testing.T) {
+	exporter := setInMemoryExporter(t)
+
+	var spanContextInHandler trace.SpanContext
+	router := chi.NewRouter()
+	router.Use(Middleware)
+	router.Get("/items/{item_id}", func(w http.ResponseWriter, r *http.Request) {
+		spanContextInHandler = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	request := httptest.NewRequest("GET", "/items/12", http.NoBody)
+	request.Header.Set("traceparent", "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/unknown", http.NoBody))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "GET /items/{item_id}", spans[0].Name)
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "0102030405060708", spans[0].Parent.SpanID().String())
+	assert.Equal(t, spans[0].SpanContext, spanContextInHandler)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Contains(t, spans[0].Attributes, attribute.String("http.route", "/items/{item_id}"))
+	assert.Contains(t, spans[0].Attributes, attribute.Int("http.response.status_code", http.StatusInternalServerError))
+
+	assert.Equal(t, "HTTP POST", spans[1].Name)
+	assert.False(t, spans[1].Parent.IsValid())
+	assert.Equal(t, codes.Unset, spans[1].Status.Code)
+	assert.Contains(t, spans[1].Attributes, attribute.Int("http.response.status_code", http.StatusNotFound))
+}
+
+func TestTransport(t *testing.T) {
+	exporter := setInMemoryExporter(t)
+
+	var receivedTraceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedTraceParent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	ctx, parentSpan := StartSpan(httptest.NewRequest("GET", "/", http.NoBody).Context(), "parent")
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/propagate?types=results", http.NoBody)
+	require.NoError(t, err)
+	response, err := HTTPClient.Do(request)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	parentSpan.End()
+
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	assert.Empty(t, request.Header.Get("traceparent"), "the original request should not be modified")
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "HTTP GET", spans[0].Name)
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+	assert.Equal(t, parentSpan.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	assert.Contains(t, spans[0].Attributes, attribute.String("url.path", "/propagate"))
+	assert.Contains(t, spans[0].Attributes, attribute.Int("http.response.status_code", http.StatusAccepted))
+	assert.Equal(t, "00-"+spans[0].SpanContext.TraceID().String()+"-"+spans[0].SpanContext.SpanID().String()+"-01",
+		receivedTraceParent)
+}
+
+func TestTransport_Error(t *testing.T) {
+	exporter := setInMemoryExporter(t)
+
+	client := &http.Client{Transport: &Transport{Base: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, assert.AnError
+	})}}
+	_, err := client.Get("http://example.com/")
+	assert.ErrorIs(t, err, assert.AnError)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) { return f(request) }
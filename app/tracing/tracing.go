@@ -0,0 +1,135 @@
+// Package tracing traces the processing of requests (HTTP handlers, transactions, named locks,
+// propagation steps, outgoing HTTP calls) with OpenTelemetry.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/version"
+)
+
+const instrumentationName = "github.com/France-ioi/AlgoreaBackend/v2"
+
+const (
+	exporterNone = "none"
+	exporterNoop = "noop"
+	exporterOTLP = "otlp"
+)
+
+var (
+	providerMutex sync.Mutex
+	provider      *sdktrace.TracerProvider
+)
+
+// Configure sets up the global tracer provider with the exporter given in the logging configuration (tracingExporter):
+//   - "none" (default): the tracing is disabled,
+//   - "otlp": the spans are exported with the OTLP/HTTP protocol to tracingEndpoint ("host:port", "localhost:4318" by default)
+//     at tracingURLPath ("/v1/traces" by default), without TLS if tracingInsecure is true,
+//   - "noop": the spans are created (so the trace IDs get into the logs) but not exported (for tests).
+//
+// Only the tracingSampleRatio part of root spans is sampled. The previously configured tracer provider is shut down.
+func Configure(config *viper.Viper) error {
+	config.SetDefault("tracingExporter", exporterNone)
+	config.SetDefault("tracingServiceName", "algorea-backend")
+	config.SetDefault("tracingSampleRatio", 1.0)
+
+	var spanProcessor sdktrace.SpanProcessor
+	switch exporter := config.GetString("tracingExporter"); exporter {
+	case exporterNone:
+	case exporterNoop:
+		spanProcessor = sdktrace.NewSimpleSpanProcessor(tracetest.NewNoopExporter())
+	case exporterOTLP:
+		var options []otlptracehttp.Option
+		if endpoint := config.GetString("tracingEndpoint"); endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if urlPath := config.GetString("tracingURLPath"); urlPath != "" {
+			options = append(options, otlptracehttp.WithURLPath(urlPath))
+		}
+		if config.GetBool("tracingInsecure") {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		otlpExporter, err := otlptracehttp.New(context.Background(), options...)
+		if err != nil {
+			return fmt.Errorf("cannot create the OTLP exporter: %w", err)
+		}
+		spanProcessor = sdktrace.NewBatchSpanProcessor(otlpExporter)
+	default:
+		return fmt.Errorf("unknown tracing exporter: %q", exporter)
+	}
+
+	var newProvider *sdktrace.TracerProvider
+	if spanProcessor != nil {
+		newProvider = sdktrace.NewTracerProvider(
+			sdktrace.WithSpanProcessor(spanProcessor),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.GetFloat64("tracingSampleRatio")))),
+			sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+				semconv.ServiceName(config.GetString("tracingServiceName")), semconv.ServiceVersion(version.Version))),
+		)
+		otel.SetTracerProvider(newProvider)
+	} else {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	}
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	providerMutex.Lock()
+	previousProvider := provider
+	provider = newProvider
+	providerMutex.Unlock()
+
+	if previousProvider != nil {
+		_ = previousProvider.Shutdown(context.Background())
+	}
+	return nil
+}
+
+// Shutdown exports the remaining spans and shuts down the configured tracer provider (if any).
+func Shutdown(ctx context.Context) error {
+	providerMutex.Lock()
+	previousProvider := provider
+	provider = nil
+	providerMutex.Unlock()
+
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	if previousProvider == nil {
+		return nil
+	}
+	return previousProvider.Shutdown(ctx)
+}
+
+// Enabled returns true if a tracer provider is configured (see Configure).
+func Enabled() bool {
+	providerMutex.Lock()
+	defer providerMutex.Unlock()
+
+	return provider != nil
+}
+
+// StartSpan starts a span with the given name as a child of the span of the given context (if any).
+// The returned context contains the new span.
+func StartSpan(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, options...)
+}
+
+// EndSpan ends the given span marking it as failed if the given error is not nil.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setInMemoryExporter sets a global tracer provider exporting the spans synchronously into the returned exporter.
+func setInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { require.NoError(t, Shutdown(context.Background())) })
+	return exporter
+}
+
+func TestConfigure(t *testing.T) {
+	defer func() { require.NoError(t, Shutdown(context.Background())) }()
+
+	for _, test := range []struct {
+		exporter        string
+		expectedEnabled bool
+	}{
+		{exporter: "", expectedEnabled: false},
+		{exporter: "none", expectedEnabled: false},
+		{exporter: "noop", expectedEnabled: true},
+		{exporter: "otlp", expectedEnabled: true},
+	} {
+		test := test
+		t.Run(test.exporter, func(t *testing.T) {
+			config := viper.New()
+			if test.exporter != "" {
+				config.Set("tracingExporter", test.exporter)
+			}
+			require.NoError(t, Configure(config))
+			assert.Equal(t, test.expectedEnabled, Enabled())
+
+			_, span := StartSpan(context.Background(), "span")
+			defer span.End()
+			assert.Equal(t, test.expectedEnabled, span.SpanContext().IsValid())
+		})
+	}
+}
+
+func TestConfigure_UnknownExporter(t *testing.T) {
+	config := viper.New()
+	config.Set("tracingExporter", "zipkin")
+	assert.EqualError(t, Configure(config), `unknown tracing exporter: "zipkin"`)
+}
+
+func TestConfigure_SampleRatio(t *testing.T) {
+	defer func() { require.NoError(t, Shutdown(context.Background())) }()
+
+	config := viper.New()
+	config.Set("tracingExporter", "noop")
+	config.Set("tracingSampleRatio", 0)
+	require.NoError(t, Configure(config))
+
+	_, span := StartSpan(context.Background(), "span")
+	defer span.End()
+	assert.True(t, span.SpanContext().IsValid())
+	assert.False(t, span.SpanContext().IsSampled())
+}
+
+func TestEndSpan(t *testing.T) {
+	exporter := setInMemoryExporter(t)
+
+	_, span := StartSpan(context.Background(), "successful")
+	EndSpan(span, nil)
+	_, span = StartSpan(context.Background(), "failed")
+	EndSpan(span, errors.New("some error"))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "successful", spans[0].Name)
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	assert.Empty(t, spans[0].Events)
+	assert.Equal(t, "failed", spans[1].Name)
+	assert.Equal(t, sdktrace.Status{Code: codes.Error, Description: "some error"}, spans[1].Status)
+	require.Len(t, spans[1].Events, 1)
+	assert.Equal(t, "exception", spans[1].Events[0].Name)
+}
+
+func TestStartSpan_CreatesChildSpans(t *testing.T) {
+	exporter := setInMemoryExporter(t)
+
+	ctx, parentSpan := StartSpan(context.Background(), "parent")
+	_, childSpan := StartSpan(ctx, "child", trace.WithSpanKind(trace.SpanKindClient))
+	childSpan.End()
+	parentSpan.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+	assert.Equal(t, parentSpan.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, parentSpan.SpanContext().TraceID(), spans[0].SpanContext.TraceID())
+}
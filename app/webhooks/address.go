@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+)
+
+// hostLookupTimeout is the maximum duration of the resolution of the host of a webhook URL at registration.
+const hostLookupTimeout = 5 * time.Second
+
+// ErrForbiddenAddress is returned when a webhook URL points to a loopback, private or link-local address.
+var ErrForbiddenAddress = errors.New("loopback, private and link-local addresses are not allowed")
+
+// IsForbiddenIP returns true if webhooks should not be delivered to the IP address
+// (i.e. if it is an unspecified, loopback, private or link-local address),
+// so that webhooks cannot be used to reach the internal network of the server.
+func IsForbiddenIP(ip net.IP) bool {
+	return ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast()
+}
+
+// CheckHost returns ErrForbiddenAddress if the host (a domain name or an IP address)
+// resolves to a forbidden address (see IsForbiddenIP).
+// Hosts which cannot be resolved are accepted as the addresses are checked again on each delivery.
+func CheckHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if IsForbiddenIP(ip) {
+			return ErrForbiddenAddress
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hostLookupTimeout)
+	defer cancel()
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil //nolint:nilerr the addresses are checked when connecting
+	}
+	for _, address := range addresses {
+		if IsForbiddenIP(address.IP) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+// checkDialedAddress is the Control function of the dialer of the worker rejecting connections to forbidden addresses.
+// As it is called with resolved addresses, it also protects against hosts resolving differently at registration
+// and at delivery.
+func checkDialedAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || IsForbiddenIP(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
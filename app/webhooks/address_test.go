@@ -0,0 +1,52 @@
+package webhooks
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsForbiddenIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "93.184.216.34", want: false},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", want: false},
+		{ip: "0.0.0.0", want: true},
+		{ip: "127.0.0.1", want: true},
+		{ip: "127.1.2.3", want: true},
+		{ip: "10.0.0.1", want: true},
+		{ip: "172.16.5.4", want: true},
+		{ip: "192.168.1.1", want: true},
+		{ip: "169.254.169.254", want: true},
+		{ip: "::", want: true},
+		{ip: "::1", want: true},
+		{ip: "fd00::1", want: true},
+		{ip: "fe80::1", want: true},
+		{ip: "::ffff:127.0.0.1", want: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsForbiddenIP(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	assert.NoError(t, CheckHost(context.Background(), "93.184.216.34"))
+	assert.ErrorIs(t, CheckHost(context.Background(), "10.1.2.3"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckHost(context.Background(), "::1"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckHost(context.Background(), "localhost"), ErrForbiddenAddress)
+	assert.NoError(t, CheckHost(context.Background(), "unknown.invalid"))
+}
+
+func Test_checkDialedAddress(t *testing.T) {
+	assert.NoError(t, checkDialedAddress("tcp4", "93.184.216.34:443", nil))
+	assert.ErrorIs(t, checkDialedAddress("tcp4", "127.0.0.1:443", nil), ErrForbiddenAddress)
+	assert.ErrorIs(t, checkDialedAddress("tcp6", "[fe80::1]:443", nil), ErrForbiddenAddress)
+	assert.Error(t, checkDialedAddress("tcp4", "127.0.0.1", nil))
+}
@@ -0,0 +1,228 @@
+// Package webhooks provides a worker delivering the queued webhook events.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/logging"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/pollingworker"
+)
+
+const (
+	// EventHeader is the name of the header containing the type of the delivered event.
+	EventHeader = "X-Algorea-Event"
+	// DeliveryHeader is the name of the header containing the id of the delivery.
+	DeliveryHeader = "X-Algorea-Delivery"
+	// SignatureHeader is the name of the header containing the signature of the delivery
+	// in the form `t=<unix timestamp>,v1=<hex-encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook's secret>`.
+	SignatureHeader = "X-Algorea-Signature"
+
+	maxErrorLength            = 1000
+	maxDiscardedResponseBytes = 64 << 10
+)
+
+// Config is the configuration of the webhook delivery worker.
+type Config struct {
+	// PollInterval is the time to wait before checking the queue again when there is nothing to do.
+	PollInterval time.Duration
+	// BatchSize is the maximum number of deliveries attempted on each poll.
+	BatchSize int
+	// Timeout is the maximum duration of an HTTP request to a webhook.
+	Timeout time.Duration
+	// MinBackoff is the delay before retrying a delivery which has failed once.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay before retrying a failed delivery.
+	MaxBackoff time.Duration
+	// MaxAttempts is the number of failed attempts after which a delivery is given up.
+	MaxAttempts int
+	// ClaimDuration is the time during which a delivery being attempted by a worker cannot be attempted by other workers.
+	// It should be greater than Timeout.
+	ClaimDuration time.Duration
+	// AllowPrivateAddresses allows deliveries to loopback, private and link-local addresses (for local testing only).
+	AllowPrivateAddresses bool
+}
+
+// DefaultConfig returns the default configuration of the webhook delivery worker.
+func DefaultConfig() Config {
+	return Config{
+		PollInterval:  time.Second,
+		BatchSize:     100,
+		Timeout:       10 * time.Second,
+		MinBackoff:    10 * time.Second,
+		MaxBackoff:    time.Hour,
+		MaxAttempts:   10,
+		ClaimDuration: time.Minute,
+	}
+}
+
+// Worker delivers the events queued in `webhook_deliveries`.
+type Worker struct {
+	db     *database.DB
+	config Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// New creates a new webhook delivery worker.
+func New(db *database.DB, config Config) *Worker {
+	return &Worker{
+		db:     db,
+		config: config,
+		client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: newTransport(config.AllowPrivateAddresses),
+			// a redirection would allow to bypass the check of the URL done at registration
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		now: time.Now,
+	}
+}
+
+// newTransport creates the transport of the worker's client which refuses to connect
+// to loopback, private and link-local addresses unless allowPrivateAddresses is true.
+func newTransport(allowPrivateAddresses bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivateAddresses {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: checkDialedAddress}
+		transport.DialContext = dialer.DialContext
+		transport.Proxy = nil // the proxy would connect to the webhook's address without our check
+	}
+	return transport
+}
+
+// Run delivers the queued events until the context is canceled.
+// Errors are logged and the processing is retried on the next poll.
+func (w *Worker) Run(ctx context.Context) {
+	pollingworker.Run(ctx, "Webhook", w.config.PollInterval, w.RunOnce)
+}
+
+// RunOnce attempts a batch of due deliveries (skipping the ones claimed concurrently by other workers).
+// It returns true if at least one delivery has been attempted (successfully or not).
+func (w *Worker) RunOnce(ctx context.Context) (processed bool, err error) {
+	store := database.NewDataStoreWithContext(ctx, w.db)
+	deliveries, err := store.WebhookDeliveries().GetDue(w.config.BatchSize)
+	if err != nil {
+		return false, err
+	}
+
+	for index := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+		claimed, claimErr := store.WebhookDeliveries().Claim(deliveries[index].ID, w.config.ClaimDuration)
+		if claimErr != nil {
+			return processed, claimErr
+		}
+		if !claimed {
+			continue
+		}
+		processed = true
+		if err = w.process(ctx, store, &deliveries[index]); err != nil {
+			return processed, err
+		}
+	}
+	return processed, nil
+}
+
+func (w *Worker) process(ctx context.Context, store *database.DataStore, delivery *database.WebhookDelivery) error {
+	responseCode, deliverErr := w.deliver(ctx, delivery)
+	if ctx.Err() != nil {
+		return nil // the worker is being stopped, the delivery will be attempted again when the claim expires
+	}
+
+	logEntry := logging.SharedLogger.WithContext(ctx).
+		WithField("delivery_id", delivery.ID).
+		WithField("webhook_id", delivery.WebhookID).
+		WithField("event", delivery.EventType).
+		WithField("response_code", responseCode)
+
+	if deliverErr != nil {
+		attemptsCount := delivery.AttemptsCount + 1
+		giveUp := attemptsCount >= w.config.MaxAttempts
+		retryIn := pollingworker.Backoff(delivery.AttemptsCount, w.config.MinBackoff, w.config.MaxBackoff)
+		logEntry = logEntry.WithField("attempts_count", attemptsCount)
+		if giveUp {
+			logEntry.Warnf("Webhook delivery failed, giving up: %v", deliverErr)
+		} else {
+			logEntry.Infof("Webhook delivery failed, retrying in %v: %v", retryIn, deliverErr)
+		}
+		return store.WebhookDeliveries().MarkAsFailedAttempt(delivery.ID, responseCode, deliverErr, retryIn, giveUp)
+	}
+
+	logEntry.Debug("Webhook delivered")
+	return store.WebhookDeliveries().MarkAsDelivered(delivery.ID, responseCode)
+}
+
+type envelope struct {
+	DeliveryID int64           `json:"delivery_id,string"`
+	Event      string          `json:"event"`
+	CreatedAt  database.Time   `json:"created_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// deliver posts the delivery to the webhook's URL.
+// It returns the status code of the response (0 if there is no response)
+// and an error if the delivery has failed (including when the status code is not 2xx).
+func (w *Worker) deliver(ctx context.Context, delivery *database.WebhookDelivery) (responseCode int, err error) {
+	body, err := json.Marshal(&envelope{
+		DeliveryID: delivery.ID,
+		Event:      delivery.EventType,
+		CreatedAt:  delivery.CreatedAt,
+		Data:       json.RawMessage(delivery.Payload),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Algorea-Webhooks")
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set(SignatureHeader, Sign(delivery.Secret, w.now(), body))
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return 0, truncateError(err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	// reading the body allows the connection to be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxDiscardedResponseBytes))
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return response.StatusCode, fmt.Errorf("unexpected status code: %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// Sign returns the value of the signature header for the given body sent at the given time.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unixTimestamp := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(unixTimestamp + "."))
+	_, _ = mac.Write(body)
+	return "t=" + unixTimestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func truncateError(err error) error {
+	if message := err.Error(); len(message) > maxErrorLength {
+		return errors.New(message[:maxErrorLength])
+	}
+	return err
+}
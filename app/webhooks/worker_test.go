@@ -0,0 +1,129 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"grade_saved"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, _ = mac.Write([]byte("1700000000." + string(body)))
+
+	assert.Equal(t, "t=1700000000,v1="+hex.EncodeToString(mac.Sum(nil)), Sign("secret", time.Unix(1700000000, 0), body))
+	assert.NotEqual(t, Sign("secret", time.Unix(1700000000, 0), body), Sign("other secret", time.Unix(1700000000, 0), body))
+	assert.NotEqual(t, Sign("secret", time.Unix(1700000000, 0), body), Sign("secret", time.Unix(1700000001, 0), body))
+}
+
+func TestDefaultConfig(t *testing.T) {
+	assert.Equal(t, Config{
+		PollInterval:  time.Second,
+		BatchSize:     100,
+		Timeout:       10 * time.Second,
+		MinBackoff:    10 * time.Second,
+		MaxBackoff:    time.Hour,
+		MaxAttempts:   10,
+		ClaimDuration: time.Minute,
+	}, DefaultConfig())
+}
+
+// testConfig returns the default configuration allowing deliveries to the test servers listening on the loopback.
+func testConfig() Config {
+	config := DefaultConfig()
+	config.AllowPrivateAddresses = true
+	return config
+}
+
+func testDelivery(url string) *database.WebhookDelivery {
+	return &database.WebhookDelivery{
+		ID:        12,
+		WebhookID: 34,
+		EventType: "item_unlocked",
+		Payload:   `{"participant_id": "1", "attempt_id": null, "item_id": "2"}`,
+		CreatedAt: database.Time(time.Date(2025, 2, 18, 10, 0, 0, 0, time.UTC)),
+		URL:       url,
+		Secret:    "secret",
+	}
+}
+
+func TestWorker_deliver(t *testing.T) {
+	var receivedRequest *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedRequest = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	worker := New(nil, testConfig())
+	now := time.Unix(1739872800, 0)
+	worker.now = func() time.Time { return now }
+
+	responseCode, err := worker.deliver(context.Background(), testDelivery(server.URL+"/hook"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, responseCode)
+
+	require.NotNil(t, receivedRequest)
+	assert.Equal(t, http.MethodPost, receivedRequest.Method)
+	assert.Equal(t, "/hook", receivedRequest.URL.Path)
+	assert.Equal(t, "application/json", receivedRequest.Header.Get("Content-Type"))
+	assert.Equal(t, "item_unlocked", receivedRequest.Header.Get(EventHeader))
+	assert.Equal(t, "12", receivedRequest.Header.Get(DeliveryHeader))
+	assert.Equal(t, Sign("secret", now, receivedBody), receivedRequest.Header.Get(SignatureHeader))
+	assert.JSONEq(t, `{
+		"delivery_id": "12", "event": "item_unlocked", "created_at": "2025-02-18T10:00:00Z",
+		"data": {"participant_id": "1", "attempt_id": null, "item_id": "2"}
+	}`, string(receivedBody))
+}
+
+func TestWorker_deliver_FailsOnNon2xxStatusCodes(t *testing.T) {
+	for _, statusCode := range []int{http.StatusFound, http.StatusBadRequest, http.StatusInternalServerError} {
+		statusCode := statusCode
+		t.Run(http.StatusText(statusCode), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Location", "https://example.org/")
+				w.WriteHeader(statusCode)
+			}))
+			defer server.Close()
+
+			responseCode, err := New(nil, testConfig()).deliver(context.Background(), testDelivery(server.URL))
+			assert.Equal(t, statusCode, responseCode)
+			assert.EqualError(t, err, fmt.Sprintf("unexpected status code: %d", statusCode))
+		})
+	}
+}
+
+func TestWorker_deliver_FailsWhenServerIsUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	responseCode, err := New(nil, testConfig()).deliver(context.Background(), testDelivery(url))
+	assert.Zero(t, responseCode)
+	assert.Error(t, err)
+}
+
+func TestWorker_deliver_RefusesForbiddenAddresses(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }))
+	defer server.Close()
+
+	responseCode, err := New(nil, DefaultConfig()).deliver(context.Background(), testDelivery(server.URL))
+	assert.Zero(t, responseCode)
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.False(t, called)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	_ "github.com/go-sql-driver/mysql" // use to force database/sql to use mysql
+	"github.com/spf13/cobra"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/appenv"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+func init() { //nolint:gochecknoinits
+	var itemID, groupID int64
+	var minSimilarity float64
+
+	computeAnswersSimilarityCmd := &cobra.Command{
+		Use:   "compute-answers-similarity [environment]",
+		Short: "compute the similarity of submissions for a task",
+		Long: `computes the pairwise similarity of the latest submissions for a task of participants of a group
+(listed by GET /items/{item_id}/similarity)`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if itemID <= 0 || groupID <= 0 {
+				fmt.Println("item-id and group-id must be positive")
+				os.Exit(1)
+			}
+			if minSimilarity < 0 || minSimilarity > 1 {
+				fmt.Println("min-similarity must be between 0 and 1")
+				os.Exit(1)
+			}
+
+			// if arg given, replace the env
+			if len(args) > 0 {
+				appenv.SetEnv(args[0])
+			}
+
+			appenv.SetDefaultEnv("dev")
+
+			var application *app.Application
+			var err error
+			application, err = app.New()
+			defer func() {
+				if application != nil && application.Database != nil {
+					_ = application.Database.Close()
+				}
+			}()
+			if err != nil {
+				return err
+			}
+
+			var pairsCount int
+			err = database.NewDataStore(application.Database).InTransaction(func(store *database.DataStore) error {
+				var err error
+				pairsCount, err = store.AnswerSimilarities().ComputeForItemAndGroup(itemID, groupID, minSimilarity)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("cannot compute the similarity of answers: %v", err)
+			}
+
+			fmt.Printf("%d pairs of similar answers stored\n", pairsCount)
+
+			return nil
+		},
+	}
+
+	computeAnswersSimilarityCmd.Flags().Int64Var(&itemID, "item-id", 0, "id of the task")
+	computeAnswersSimilarityCmd.Flags().Int64Var(&groupID, "group-id", 0, "id of the group whose participants' submissions are compared")
+	computeAnswersSimilarityCmd.Flags().Float64Var(&minSimilarity, "min-similarity", database.DefaultMinStoredAnswerSimilarity,
+		"minimal similarity of the stored pairs of submissions")
+
+	rootCmd.AddCommand(computeAnswersSimilarityCmd)
+}
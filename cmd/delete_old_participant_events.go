@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql" // use to force database/sql to use mysql
+	"github.com/spf13/cobra"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/appenv"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+func init() { //nolint:gochecknoinits
+	var age time.Duration
+
+	deleteOldParticipantEventsCmd := &cobra.Command{
+		Use:   "delete-old-participant-events [environment]",
+		Short: "delete old events of participants",
+		Long:  `deletes events (streamed by GET /current-user/events) which are too old to be useful for reconnecting clients`,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if age <= 0 {
+				fmt.Println("age must be positive")
+				os.Exit(1)
+			}
+
+			// if arg given, replace the env
+			if len(args) > 0 {
+				appenv.SetEnv(args[0])
+			}
+
+			appenv.SetDefaultEnv("dev")
+
+			var application *app.Application
+			var err error
+			application, err = app.New()
+			defer func() {
+				if application != nil && application.Database != nil {
+					_ = application.Database.Close()
+				}
+			}()
+			if err != nil {
+				return err
+			}
+
+			deletedCount, err := database.NewDataStore(application.Database).ParticipantEvents().DeleteOlderThan(age)
+			if err != nil {
+				return fmt.Errorf("cannot delete old participant events: %v", err)
+			}
+
+			fmt.Printf("%d events deleted\n", deletedCount)
+
+			return nil
+		},
+	}
+
+	deleteOldParticipantEventsCmd.Flags().DurationVar(&age, "age", time.Hour,
+		"minimal age of the events to delete")
+
+	rootCmd.AddCommand(deleteOldParticipantEventsCmd)
+}
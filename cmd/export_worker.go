@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	_ "github.com/go-sql-driver/mysql" // use to force database/sql to use mysql
+	"github.com/spf13/cobra"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/appenv"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/exports"
+)
+
+func init() { //nolint:gochecknoinits
+	config := exports.DefaultConfig()
+
+	exportWorkerCmd := &cobra.Command{
+		Use:   "export-worker [environment]",
+		Short: "run the export worker",
+		Long: `runs the export jobs requested through the API until interrupted,
+storing their artifacts in the configured exports storage and deleting the expired ones`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+
+			// Set the environment.
+			if len(args) > 0 {
+				appenv.SetEnv(args[0])
+			}
+
+			if config.PollInterval <= 0 || config.ArtifactTTL <= 0 || config.MaxDuration <= 0 {
+				return fmt.Errorf("invalid intervals: poll-interval, artifact-ttl and max-duration should be positive")
+			}
+
+			var application *app.Application
+			application, err = app.New()
+			defer func() {
+				if application != nil && application.Database != nil {
+					_ = application.Database.Close()
+				}
+			}()
+			if err != nil {
+				return err
+			}
+
+			serverConfig := app.ServerConfig(application.Config)
+			storage, err := exports.NewStorage(serverConfig)
+			if err != nil {
+				return err
+			}
+			rootPath := serverConfig.GetString("rootPath")
+			if rootPath == "" {
+				rootPath = "/"
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			fmt.Println("Export worker started.")
+			exports.New(application.Database, application.HTTPHandler, rootPath, storage, config).Run(ctx)
+			fmt.Println("Export worker stopped.")
+
+			return nil
+		},
+	}
+
+	exportWorkerCmd.Flags().DurationVar(&config.PollInterval, "poll-interval", config.PollInterval,
+		"time to wait before checking for pending jobs again when there are none")
+	exportWorkerCmd.Flags().DurationVar(&config.ArtifactTTL, "artifact-ttl", config.ArtifactTTL,
+		"time during which the artifact of an export can be downloaded")
+	exportWorkerCmd.Flags().DurationVar(&config.MaxDuration, "max-duration", config.MaxDuration,
+		"maximum duration of an export (longer exports are considered interrupted)")
+	rootCmd.AddCommand(exportWorkerCmd)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	_ "github.com/go-sql-driver/mysql" // use to force database/sql to use mysql
+	"github.com/spf13/cobra"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/appenv"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+func init() { //nolint:gochecknoinits
+	var (
+		itemID     int64
+		outputPath string
+	)
+
+	itemsExportCmd := &cobra.Command{
+		Use:   "items-export [environment]",
+		Short: "export an item with its descendants",
+		Long: `writes a JSON archive of an item and all its descendants (as GET /items/{item_id}/export does)
+which can be imported with the items-import command or with POST /items/import`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if itemID == 0 {
+				fmt.Println("item-id is required")
+				os.Exit(1)
+			}
+
+			// if arg given, replace the env
+			if len(args) > 0 {
+				appenv.SetEnv(args[0])
+			}
+
+			appenv.SetDefaultEnv("dev")
+
+			var application *app.Application
+			var err error
+			application, err = app.New()
+			defer func() {
+				if application != nil && application.Database != nil {
+					_ = application.Database.Close()
+				}
+			}()
+			if err != nil {
+				return err
+			}
+
+			archive, err := database.NewDataStore(application.Database).Items().ExportSubtree(itemID)
+			if err != nil {
+				return fmt.Errorf("cannot export the item: %v", err)
+			}
+
+			output := os.Stdout
+			if outputPath != "" {
+				if output, err = os.Create(outputPath); err != nil {
+					return err
+				}
+				defer func() { _ = output.Close() }()
+			}
+			encoder := json.NewEncoder(output)
+			encoder.SetIndent("", "  ")
+			if err = encoder.Encode(archive); err != nil {
+				return fmt.Errorf("cannot write the archive: %v", err)
+			}
+
+			if outputPath != "" {
+				fmt.Printf("%d items exported\n", len(archive.Items))
+			}
+
+			return nil
+		},
+	}
+
+	itemsExportCmd.Flags().Int64Var(&itemID, "item-id", 0, "id of the item to export with its descendants")
+	itemsExportCmd.Flags().StringVarP(&outputPath, "output", "o", "", "file to write the archive to (stdout by default)")
+
+	rootCmd.AddCommand(itemsExportCmd)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	_ "github.com/go-sql-driver/mysql" // use to force database/sql to use mysql
+	"github.com/spf13/cobra"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/appenv"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+func init() { //nolint:gochecknoinits
+	var (
+		inputPath  string
+		ownerLogin string
+		options    database.ItemArchiveImportOptions
+	)
+
+	itemsImportCmd := &cobra.Command{
+		Use:   "items-import [environment]",
+		Short: "import items from an archive",
+		Long: `recreates the items of an archive produced by the items-export command or by GET /items/{item_id}/export
+under the given parent item with new ids (as POST /items/import does)`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if inputPath == "" || ownerLogin == "" || options.ParentItemID == 0 {
+				fmt.Println("input, owner-login and parent-item-id are required")
+				os.Exit(1)
+			}
+			switch options.OnTextIDConflict {
+			case database.ItemArchiveTextIDConflictFail, database.ItemArchiveTextIDConflictClear, database.ItemArchiveTextIDConflictRename:
+			default:
+				fmt.Println("on-text-id-conflict should be one of fail, clear, rename")
+				os.Exit(1)
+			}
+
+			archiveJSON, err := os.ReadFile(inputPath)
+			if err != nil {
+				return err
+			}
+			var archive database.ItemArchive
+			if err = json.Unmarshal(archiveJSON, &archive); err != nil {
+				return fmt.Errorf("cannot read the archive: %v", err)
+			}
+
+			// if arg given, replace the env
+			if len(args) > 0 {
+				appenv.SetEnv(args[0])
+			}
+
+			appenv.SetDefaultEnv("dev")
+
+			var application *app.Application
+			application, err = app.New()
+			defer func() {
+				if application != nil && application.Database != nil {
+					_ = application.Database.Close()
+				}
+			}()
+			if err != nil {
+				return err
+			}
+
+			var report *database.ItemArchiveImportReport
+			err = database.NewDataStore(application.Database).InTransaction(func(store *database.DataStore) error {
+				if err = store.Users().Where("login = ?", ownerLogin).PluckFirst("group_id", &options.OwnerGroupID).Error(); err != nil {
+					return fmt.Errorf("cannot find the owner: %v", err)
+				}
+				report, err = store.Items().ImportArchive(&archive, &options)
+				return err
+			})
+			if report != nil {
+				printItemsImportReport(report, options.DryRun)
+			}
+			if err != nil {
+				return fmt.Errorf("cannot import the items: %v", err)
+			}
+
+			fmt.Println("DONE")
+
+			return nil
+		},
+	}
+
+	itemsImportCmd.Flags().StringVarP(&inputPath, "input", "i", "", "file containing the archive")
+	itemsImportCmd.Flags().StringVar(&ownerLogin, "owner-login", "",
+		"login of the user becoming the owner of the imported items")
+	itemsImportCmd.Flags().Int64Var(&options.ParentItemID, "parent-item-id", 0,
+		"id of the item the imported root item becomes a child of")
+	itemsImportCmd.Flags().StringVar(&options.OnTextIDConflict, "on-text-id-conflict", database.ItemArchiveTextIDConflictFail,
+		"what to do when a text_id is already used (fail, clear, rename)")
+	itemsImportCmd.Flags().BoolVar(&options.DryRun, "dry-run", false, "only check the archive without importing anything")
+
+	rootCmd.AddCommand(itemsImportCmd)
+}
+
+func printItemsImportReport(report *database.ItemArchiveImportReport, dryRun bool) {
+	fmt.Printf("%d items, %d relations, %d dependencies\n", report.ItemsCount, report.RelationsCount, report.DependenciesCount)
+	for _, conflict := range report.TextIDConflicts {
+		if conflict.NewTextID != nil {
+			fmt.Printf("text_id %q of item %d is already used, renamed to %q\n", conflict.TextID, conflict.ItemID, *conflict.NewTextID)
+			continue
+		}
+		fmt.Printf("text_id %q of item %d is already used\n", conflict.TextID, conflict.ItemID)
+	}
+	if dryRun {
+		return
+	}
+	for oldItemID, newItemID := range report.IDMapping {
+		fmt.Printf("item %d imported as %d\n", oldItemID, newItemID)
+	}
+}
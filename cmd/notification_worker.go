@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	_ "github.com/go-sql-driver/mysql" // use to force database/sql to use mysql
+	"github.com/spf13/cobra"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/appenv"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/notifications"
+)
+
+func init() { //nolint:gochecknoinits
+	config := notifications.DefaultConfig()
+
+	notificationWorkerCmd := &cobra.Command{
+		Use:   "notification-worker [environment]",
+		Short: "run the email notification worker",
+		Long: `sends the queued email notifications through the transport of the "email" configuration until interrupted,
+retrying failed notifications with an exponential backoff`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+
+			// Set the environment.
+			if len(args) > 0 {
+				appenv.SetEnv(args[0])
+			}
+
+			if config.PollInterval <= 0 || config.MinBackoff <= 0 || config.ClaimDuration <= 0 || config.MaxBackoff < config.MinBackoff {
+				return fmt.Errorf("invalid intervals: poll-interval, min-backoff and claim-duration should be positive, " +
+					"max-backoff should not be less than min-backoff")
+			}
+			if config.BatchSize <= 0 || config.MaxAttempts <= 0 {
+				return fmt.Errorf("batch-size and max-attempts should be positive")
+			}
+
+			var application *app.Application
+			application, err = app.New()
+			defer func() {
+				if application != nil && application.Database != nil {
+					_ = application.Database.Close()
+				}
+			}()
+			if err != nil {
+				return err
+			}
+
+			transport, err := notifications.NewTransport(app.EmailConfig(application.Config))
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			fmt.Println("Notification worker started.")
+			notifications.New(application.Database, transport, config).Run(ctx)
+			fmt.Println("Notification worker stopped.")
+
+			return nil
+		},
+	}
+
+	notificationWorkerCmd.Flags().DurationVar(&config.PollInterval, "poll-interval", config.PollInterval,
+		"time to wait before checking the queue again when it is empty")
+	notificationWorkerCmd.Flags().IntVar(&config.BatchSize, "batch-size", config.BatchSize,
+		"maximum number of notifications attempted on each poll")
+	notificationWorkerCmd.Flags().DurationVar(&config.MinBackoff, "min-backoff", config.MinBackoff,
+		"delay before retrying a notification which has failed once")
+	notificationWorkerCmd.Flags().DurationVar(&config.MaxBackoff, "max-backoff", config.MaxBackoff,
+		"maximum delay before retrying a failed notification")
+	notificationWorkerCmd.Flags().IntVar(&config.MaxAttempts, "max-attempts", config.MaxAttempts,
+		"number of failed attempts after which a notification is given up")
+	notificationWorkerCmd.Flags().DurationVar(&config.ClaimDuration, "claim-duration", config.ClaimDuration,
+		"time during which a notification being sent by a worker cannot be sent by other workers")
+	rootCmd.AddCommand(notificationWorkerCmd)
+}
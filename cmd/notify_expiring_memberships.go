@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql" // use to force database/sql to use mysql
+	"github.com/spf13/cobra"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/appenv"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+)
+
+func init() { //nolint:gochecknoinits
+	var within time.Duration
+
+	notifyExpiringMembershipsCmd := &cobra.Command{
+		Use:   "notify-expiring-memberships [environment]",
+		Short: "enqueue email notifications of memberships about to expire",
+		Long: `enqueues email notifications for the members of groups whose membership expires soon
+(each member is notified once for an expiration time), to be run periodically, e.g., every hour`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// if arg given, replace the env
+			if len(args) > 0 {
+				appenv.SetEnv(args[0])
+			}
+
+			appenv.SetDefaultEnv("dev")
+
+			if within <= 0 {
+				return fmt.Errorf("within should be positive")
+			}
+
+			var application *app.Application
+			var err error
+			application, err = app.New()
+			defer func() {
+				if application != nil && application.Database != nil {
+					_ = application.Database.Close()
+				}
+			}()
+			if err != nil {
+				return err
+			}
+
+			enqueuedCount, err := database.NewDataStore(application.Database).EmailNotifications().EnqueueForExpiringMemberships(within)
+			if err != nil {
+				return fmt.Errorf("cannot enqueue notifications: %v", err)
+			}
+
+			fmt.Printf("%d notifications of expiring memberships enqueued\n", enqueuedCount)
+
+			return nil
+		},
+	}
+
+	notifyExpiringMembershipsCmd.Flags().DurationVar(&within, "within", 3*24*time.Hour,
+		"members are notified when their membership expires within this duration")
+	rootCmd.AddCommand(notifyExpiringMembershipsCmd)
+}
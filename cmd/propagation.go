@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
 	_ "github.com/go-sql-driver/mysql" // use to force database/sql to use mysql
 	"github.com/spf13/cobra"
@@ -10,11 +9,7 @@ import (
 	"github.com/France-ioi/AlgoreaBackend/v2/app"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/appenv"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
-)
-
-const (
-	propagationCommandLockName    = "propagation_command"
-	propagationCommandLockTimeout = 600 * time.Second
+	"github.com/France-ioi/AlgoreaBackend/v2/app/propagationworker"
 )
 
 func init() { //nolint:gochecknoinits
@@ -45,7 +40,7 @@ func init() { //nolint:gochecknoinits
 			// Propagation.
 			// We use a lock because we don't want this process to be called concurrently.
 			err = database.NewDataStore(application.Database).
-				WithNamedLock(propagationCommandLockName, propagationCommandLockTimeout, func(s *database.DataStore) error {
+				WithNamedLock(propagationworker.NamedLockName, propagationworker.NamedLockTimeout, func(s *database.DataStore) error {
 					return s.InTransaction(func(store *database.DataStore) error {
 						store.SchedulePermissionsPropagation()
 						store.ScheduleResultsPropagation()
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	_ "github.com/go-sql-driver/mysql" // use to force database/sql to use mysql
+	"github.com/spf13/cobra"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/appenv"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/propagationworker"
+)
+
+func init() { //nolint:gochecknoinits
+	config := propagationworker.DefaultConfig()
+
+	propagationWorkerCmd := &cobra.Command{
+		Use:   "propagation-worker [environment]",
+		Short: "run the propagation worker",
+		Long: `processes the propagation queue (filled by the services when 'server.propagation_queue' is enabled)
+until interrupted, retrying failed propagations with an exponential backoff`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+
+			// Set the environment.
+			if len(args) > 0 {
+				appenv.SetEnv(args[0])
+			}
+
+			if config.PollInterval <= 0 || config.MinBackoff <= 0 || config.MaxBackoff < config.MinBackoff {
+				return fmt.Errorf("invalid intervals: poll-interval and min-backoff should be positive, " +
+					"max-backoff should not be less than min-backoff")
+			}
+
+			var application *app.Application
+			application, err = app.New()
+			defer func() {
+				if application != nil && application.Database != nil {
+					_ = application.Database.Close()
+				}
+			}()
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			fmt.Println("Propagation worker started.")
+			propagationworker.New(application.Database, config).Run(ctx)
+			fmt.Println("Propagation worker stopped.")
+
+			return nil
+		},
+	}
+
+	propagationWorkerCmd.Flags().DurationVar(&config.PollInterval, "poll-interval", config.PollInterval,
+		"time to wait before checking the queue again when it is empty")
+	propagationWorkerCmd.Flags().DurationVar(&config.MinBackoff, "min-backoff", config.MinBackoff,
+		"delay before retrying a propagation which has failed once")
+	propagationWorkerCmd.Flags().DurationVar(&config.MaxBackoff, "max-backoff", config.MaxBackoff,
+		"maximum delay before retrying a failed propagation")
+	rootCmd.AddCommand(propagationWorkerCmd)
+}
@@ -2,6 +2,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/database/configdb"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/domain"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/tracing"
 )
 
 func init() { //nolint:gochecknoinits
@@ -41,6 +43,7 @@ func init() { //nolint:gochecknoinits
 				if application != nil && application.Database != nil {
 					_ = application.Database.Close()
 				}
+				_ = tracing.Shutdown(context.Background())
 			}()
 			if err != nil {
 				return err
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+
+	_ "github.com/go-sql-driver/mysql" // use to force database/sql to use mysql
+	"github.com/spf13/cobra"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/appenv"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/database"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/propagationworker"
+)
+
+func init() { //nolint:gochecknoinits
+	updatePermissionsWindowsCmd := &cobra.Command{
+		Use:   "update-permissions-windows [environment]",
+		Short: "propagate permissions whose can_view window has opened or closed",
+		Long: `marks the permissions whose can_view_from/can_view_until window has opened or closed since the last run
+for propagation and runs the permissions propagation (to be run periodically, e.g., every minute)`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// if arg given, replace the env
+			if len(args) > 0 {
+				appenv.SetEnv(args[0])
+			}
+
+			appenv.SetDefaultEnv("dev")
+
+			var application *app.Application
+			var err error
+			application, err = app.New()
+			defer func() {
+				if application != nil && application.Database != nil {
+					_ = application.Database.Close()
+				}
+			}()
+			if err != nil {
+				return err
+			}
+
+			// We use the lock of the propagation because we don't want the propagation to be run concurrently.
+			var updatedCount int64
+			err = database.NewDataStore(application.Database).
+				WithNamedLock(propagationworker.NamedLockName, propagationworker.NamedLockTimeout, func(s *database.DataStore) error {
+					return s.InTransaction(func(store *database.DataStore) error {
+						var err error
+						updatedCount, err = store.PermissionsGranted().UpdateCanViewWindowsStates()
+						return err
+					})
+				})
+			if err != nil {
+				return fmt.Errorf("cannot update permissions windows: %v", err)
+			}
+
+			fmt.Printf("%d permissions windows opened or closed\n", updatedCount)
+
+			return nil
+		},
+	}
+
+	rootCmd.AddCommand(updatePermissionsWindowsCmd)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	_ "github.com/go-sql-driver/mysql" // use to force database/sql to use mysql
+	"github.com/spf13/cobra"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/appenv"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/webhooks"
+)
+
+func init() { //nolint:gochecknoinits
+	config := webhooks.DefaultConfig()
+
+	webhookWorkerCmd := &cobra.Command{
+		Use:   "webhook-worker [environment]",
+		Short: "run the webhook delivery worker",
+		Long: `delivers the queued webhook events until interrupted,
+retrying failed deliveries with an exponential backoff`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+
+			// Set the environment.
+			if len(args) > 0 {
+				appenv.SetEnv(args[0])
+			}
+
+			if config.PollInterval <= 0 || config.Timeout <= 0 || config.MinBackoff <= 0 || config.MaxBackoff < config.MinBackoff {
+				return fmt.Errorf("invalid intervals: poll-interval, timeout and min-backoff should be positive, " +
+					"max-backoff should not be less than min-backoff")
+			}
+			if config.BatchSize <= 0 || config.MaxAttempts <= 0 {
+				return fmt.Errorf("batch-size and max-attempts should be positive")
+			}
+			if config.ClaimDuration <= config.Timeout {
+				return fmt.Errorf("claim-duration should be greater than timeout")
+			}
+
+			var application *app.Application
+			application, err = app.New()
+			defer func() {
+				if application != nil && application.Database != nil {
+					_ = application.Database.Close()
+				}
+			}()
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			fmt.Println("Webhook worker started.")
+			webhooks.New(application.Database, config).Run(ctx)
+			fmt.Println("Webhook worker stopped.")
+
+			return nil
+		},
+	}
+
+	webhookWorkerCmd.Flags().DurationVar(&config.PollInterval, "poll-interval", config.PollInterval,
+		"time to wait before checking the queue again when it is empty")
+	webhookWorkerCmd.Flags().IntVar(&config.BatchSize, "batch-size", config.BatchSize,
+		"maximum number of deliveries attempted on each poll")
+	webhookWorkerCmd.Flags().DurationVar(&config.Timeout, "timeout", config.Timeout,
+		"maximum duration of a request to a webhook")
+	webhookWorkerCmd.Flags().DurationVar(&config.MinBackoff, "min-backoff", config.MinBackoff,
+		"delay before retrying a delivery which has failed once")
+	webhookWorkerCmd.Flags().DurationVar(&config.MaxBackoff, "max-backoff", config.MaxBackoff,
+		"maximum delay before retrying a failed delivery")
+	webhookWorkerCmd.Flags().IntVar(&config.MaxAttempts, "max-attempts", config.MaxAttempts,
+		"number of failed attempts after which a delivery is given up")
+	webhookWorkerCmd.Flags().DurationVar(&config.ClaimDuration, "claim-duration", config.ClaimDuration,
+		"time during which a delivery being attempted by a worker cannot be attempted by other workers")
+	webhookWorkerCmd.Flags().BoolVar(&config.AllowPrivateAddresses, "allow-private-addresses", config.AllowPrivateAddresses,
+		"allow deliveries to loopback, private and link-local addresses (for local testing only)")
+	rootCmd.AddCommand(webhookWorkerCmd)
+}
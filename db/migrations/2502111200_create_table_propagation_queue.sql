@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE `propagation_queue` (
+  `type` ENUM('permissions', 'results') NOT NULL COMMENT 'Type of the propagation to run',
+  `requests_count` INT UNSIGNED NOT NULL DEFAULT 1
+    COMMENT 'Number of (coalesced) requests for this type of propagation since the last successful run',
+  `requested_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT 'Time of the latest request',
+  `failed_attempts` INT UNSIGNED NOT NULL DEFAULT 0 COMMENT 'Number of failed runs since the last successful run',
+  `next_attempt_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3)
+    COMMENT 'The propagation should not be run before this time (used to retry failed runs with a backoff)',
+  `last_error` TEXT DEFAULT NULL COMMENT 'Error of the latest failed run',
+  PRIMARY KEY (`type`),
+  INDEX `next_attempt_at` (`next_attempt_at`)
+)
+  COMMENT='Queue of propagations requested by services, processed asynchronously by the propagation worker'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `propagation_queue`;
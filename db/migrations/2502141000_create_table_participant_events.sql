@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE `participant_events` (
+  `id` BIGINT(20) NOT NULL AUTO_INCREMENT,
+  `type` ENUM('grade_saved', 'result_changed', 'item_unlocked') NOT NULL COMMENT 'Type of the event',
+  `participant_id` BIGINT(20) NOT NULL COMMENT 'Participant (user or team) the event relates to',
+  `attempt_id` BIGINT(20) DEFAULT NULL COMMENT 'Attempt of the participant (NULL for item unlocking)',
+  `item_id` BIGINT(20) NOT NULL COMMENT 'Item the event relates to',
+  `created_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  PRIMARY KEY (`id`),
+  INDEX `created_at` (`created_at`)
+)
+  COMMENT='Recent changes of results and item unlocks, streamed to users through server-sent events'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `participant_events`;
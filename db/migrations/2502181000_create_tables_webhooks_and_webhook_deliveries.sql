@@ -0,0 +1,49 @@
+-- +migrate Up
+CREATE TABLE `webhooks` (
+  `id` BIGINT(20) NOT NULL,
+  `group_id` BIGINT(20) NOT NULL COMMENT 'Only events of descendants of this group (or of the group itself) are delivered',
+  `item_id` BIGINT(20) DEFAULT NULL
+    COMMENT 'If set, only events on descendants of this item (or on the item itself) are delivered (not for membership changes)',
+  `url` VARCHAR(2048) NOT NULL COMMENT 'URL the events are posted to',
+  `secret` VARCHAR(64) NOT NULL COMMENT 'Secret used to sign the deliveries (HMAC-SHA256)',
+  `event_types` SET('grade_saved', 'result_validated', 'item_unlocked', 'group_membership_changed') NOT NULL
+    COMMENT 'Types of events delivered to the URL',
+  `creator_id` BIGINT(20) DEFAULT NULL COMMENT 'User who registered the webhook',
+  `created_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_webhooks_group_id_groups_id` FOREIGN KEY (`group_id`) REFERENCES `groups`(`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_webhooks_item_id_items_id` FOREIGN KEY (`item_id`) REFERENCES `items`(`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_webhooks_creator_id_users_group_id` FOREIGN KEY (`creator_id`) REFERENCES `users`(`group_id`) ON DELETE SET NULL
+)
+  COMMENT='HTTP callbacks registered by managers to be notified of events happening in their groups'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+CREATE TABLE `webhook_deliveries` (
+  `id` BIGINT(20) NOT NULL AUTO_INCREMENT,
+  `webhook_id` BIGINT(20) NOT NULL,
+  `event_type` ENUM('grade_saved', 'result_validated', 'item_unlocked', 'group_membership_changed') NOT NULL,
+  `payload` JSON NOT NULL COMMENT 'Data of the event',
+  `created_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT 'Time of the event',
+  `status` ENUM('pending', 'delivered', 'failed') NOT NULL DEFAULT 'pending'
+    COMMENT '"failed" means that the delivery has been given up after too many attempts',
+  `attempts_count` INT UNSIGNED NOT NULL DEFAULT 0,
+  `next_attempt_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT 'The delivery should not be attempted before this time',
+  `last_attempt_at` DATETIME(3) DEFAULT NULL,
+  `last_response_code` SMALLINT UNSIGNED DEFAULT NULL COMMENT 'HTTP status code of the response to the latest attempt',
+  `last_error` TEXT DEFAULT NULL COMMENT 'Error of the latest failed attempt',
+  PRIMARY KEY (`id`),
+  INDEX `status_next_attempt_at` (`status`, `next_attempt_at`),
+  INDEX `webhook_id_created_at` (`webhook_id`, `created_at`),
+  CONSTRAINT `fk_webhook_deliveries_webhook_id_webhooks_id`
+    FOREIGN KEY (`webhook_id`) REFERENCES `webhooks`(`id`) ON DELETE CASCADE
+)
+  COMMENT='Queue and log of webhook deliveries'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `webhook_deliveries`;
+DROP TABLE `webhooks`;
@@ -0,0 +1,30 @@
+-- +migrate Up
+CREATE TABLE `export_jobs` (
+  `id` BIGINT(20) NOT NULL,
+  `user_id` BIGINT(20) NOT NULL COMMENT 'User who requested the export (the export is run on behalf of this user)',
+  `type` ENUM('group_progress', 'user_progress', 'team_progress', 'full_dump', 'activity_log') NOT NULL,
+  `parameters` JSON NOT NULL COMMENT 'Parameters of the export (as given on creation)',
+  `domain` VARCHAR(255) NOT NULL COMMENT 'Domain the export has been requested from',
+  `status` ENUM('pending', 'running', 'succeeded', 'failed', 'expired') NOT NULL DEFAULT 'pending'
+    COMMENT '"expired" means that the artifact has been deleted',
+  `created_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  `started_at` DATETIME(3) DEFAULT NULL,
+  `finished_at` DATETIME(3) DEFAULT NULL,
+  `error` TEXT DEFAULT NULL COMMENT 'Why the export has failed',
+  `artifact_key` VARCHAR(255) DEFAULT NULL COMMENT 'Key of the artifact in the export storage',
+  `artifact_file_name` VARCHAR(255) DEFAULT NULL,
+  `artifact_content_type` VARCHAR(255) DEFAULT NULL,
+  `artifact_size` BIGINT(20) DEFAULT NULL COMMENT 'Size of the artifact in bytes',
+  `expires_at` DATETIME(3) DEFAULT NULL COMMENT 'The artifact gets deleted after this time',
+  PRIMARY KEY (`id`),
+  INDEX `status_created_at` (`status`, `created_at`),
+  INDEX `status_expires_at` (`status`, `expires_at`),
+  CONSTRAINT `fk_export_jobs_user_id_users_group_id` FOREIGN KEY (`user_id`) REFERENCES `users`(`group_id`) ON DELETE CASCADE
+)
+  COMMENT='Exports requested by users, run asynchronously by the export worker'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `export_jobs`;
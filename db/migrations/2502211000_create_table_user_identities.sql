@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE `user_identities` (
+  `issuer` VARCHAR(255) NOT NULL COMMENT 'Issuer of the OpenID Connect identity provider',
+  `subject` VARCHAR(255) NOT NULL COMMENT 'Identifier of the user at the identity provider (the "sub" claim)',
+  `user_id` BIGINT(20) NOT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`issuer`, `subject`),
+  INDEX `user_id` (`user_id`),
+  CONSTRAINT `fk_user_identities_user_id_users_group_id` FOREIGN KEY (`user_id`) REFERENCES `users`(`group_id`) ON DELETE CASCADE
+)
+  COMMENT='Links users to their accounts at external OpenID Connect identity providers'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `user_identities`;
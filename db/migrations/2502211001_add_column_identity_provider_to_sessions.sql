@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE `sessions`
+  ADD COLUMN `identity_provider` ENUM('login_module', 'oidc') NOT NULL DEFAULT 'login_module'
+    COMMENT 'The identity provider which has issued the refresh token' AFTER `refresh_token`;
+
+-- +migrate Down
+ALTER TABLE `sessions` DROP COLUMN `identity_provider`;
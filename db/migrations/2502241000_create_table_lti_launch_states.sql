@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE `lti_launch_states` (
+  `state` VARCHAR(32) NOT NULL COMMENT 'Value of the "state" parameter sent to the LTI platform on login initiation',
+  `nonce` VARCHAR(32) NOT NULL COMMENT 'Value of the "nonce" parameter the launch token should contain',
+  `issuer` VARCHAR(255) NOT NULL COMMENT 'Issuer of the LTI platform',
+  `client_id` VARCHAR(255) NOT NULL COMMENT 'Client ID of the tool at the LTI platform',
+  `expires_at` DATETIME NOT NULL,
+  PRIMARY KEY (`state`),
+  INDEX `expires_at` (`expires_at`)
+)
+  COMMENT='States of LTI 1.3 launches initiated by platforms, waiting for the launch'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `lti_launch_states`;
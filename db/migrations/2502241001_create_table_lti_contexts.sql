@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE `lti_contexts` (
+  `issuer` VARCHAR(255) NOT NULL COMMENT 'Issuer of the LTI platform',
+  `deployment_id` VARCHAR(255) NOT NULL COMMENT 'Identifier of the deployment of the tool at the LTI platform',
+  `context_id` VARCHAR(255) NOT NULL COMMENT 'Identifier of the context (e.g., a course) at the LTI platform',
+  `group_id` BIGINT(20) NOT NULL COMMENT 'Group the users launching the tool from the context join',
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`issuer`, `deployment_id`, `context_id`),
+  INDEX `group_id` (`group_id`),
+  CONSTRAINT `fk_lti_contexts_group_id_groups_id` FOREIGN KEY (`group_id`) REFERENCES `groups`(`id`) ON DELETE CASCADE
+)
+  COMMENT='Groups corresponding to contexts (courses) of LTI platforms'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `lti_contexts`;
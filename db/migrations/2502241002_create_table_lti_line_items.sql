@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE `lti_line_items` (
+  `participant_id` BIGINT(20) NOT NULL,
+  `item_id` BIGINT(20) NOT NULL,
+  `issuer` VARCHAR(255) NOT NULL COMMENT 'Issuer of the LTI platform',
+  `client_id` VARCHAR(255) NOT NULL COMMENT 'Client ID of the tool at the LTI platform',
+  `subject` VARCHAR(255) NOT NULL COMMENT 'Identifier of the participant at the LTI platform (the "sub" claim)',
+  `line_item_url` VARCHAR(2048) NOT NULL COMMENT 'URL of the line item (of the gradebook) the scores are published to',
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`participant_id`, `item_id`, `issuer`, `client_id`),
+  INDEX `item_id` (`item_id`),
+  CONSTRAINT `fk_lti_line_items_participant_id_groups_id` FOREIGN KEY (`participant_id`) REFERENCES `groups`(`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_lti_line_items_item_id_items_id` FOREIGN KEY (`item_id`) REFERENCES `items`(`id`) ON DELETE CASCADE
+)
+  COMMENT='Line items of LTI platforms (Assignment and Grade Services) the scores of participants on items are published to'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `lti_line_items`;
@@ -0,0 +1,22 @@
+-- +migrate Up
+CREATE TABLE `lti_deep_linking_requests` (
+  `id` BIGINT(20) NOT NULL,
+  `user_id` BIGINT(20) NOT NULL COMMENT 'User who has launched the deep linking request',
+  `issuer` VARCHAR(255) NOT NULL COMMENT 'Issuer of the LTI platform',
+  `client_id` VARCHAR(255) NOT NULL COMMENT 'Client ID of the tool at the LTI platform',
+  `deployment_id` VARCHAR(255) NOT NULL COMMENT 'Identifier of the deployment of the tool at the LTI platform',
+  `return_url` VARCHAR(2048) NOT NULL COMMENT 'URL of the platform the deep linking response should be posted to',
+  `data` TEXT NULL COMMENT 'Opaque value of the platform to be returned in the deep linking response',
+  `expires_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `user_id` (`user_id`),
+  INDEX `expires_at` (`expires_at`),
+  CONSTRAINT `fk_lti_deep_linking_requests_user_id_users_group_id` FOREIGN KEY (`user_id`) REFERENCES `users`(`group_id`) ON DELETE CASCADE
+)
+  COMMENT='LTI 1.3 deep linking requests (selections of items by instructors) waiting for a response'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `lti_deep_linking_requests`;
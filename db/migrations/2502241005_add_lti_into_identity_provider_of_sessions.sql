@@ -0,0 +1,10 @@
+-- +migrate Up
+ALTER TABLE `sessions`
+  MODIFY `identity_provider` ENUM('login_module', 'oidc', 'lti') NOT NULL DEFAULT 'login_module'
+    COMMENT 'The identity provider which has issued the refresh token (or which has launched the session for LTI)';
+
+-- +migrate Down
+DELETE FROM `sessions` WHERE `identity_provider` = 'lti';
+ALTER TABLE `sessions`
+  MODIFY `identity_provider` ENUM('login_module', 'oidc') NOT NULL DEFAULT 'login_module'
+    COMMENT 'The identity provider which has issued the refresh token';
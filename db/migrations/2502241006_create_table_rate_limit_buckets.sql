@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE `rate_limit_buckets` (
+  `bucket_key` VARCHAR(255) NOT NULL COMMENT 'Route group, type of the key and key (user id, session id or IP address) of the bucket',
+  `tokens` DOUBLE NOT NULL COMMENT 'Number of tokens left in the bucket at `updated_at`',
+  `updated_at` DATETIME(6) NOT NULL,
+  `full_at` DATETIME(6) NOT NULL COMMENT 'Time at which the bucket is full again (it can be deleted after that)',
+  PRIMARY KEY (`bucket_key`),
+  INDEX `full_at` (`full_at`)
+)
+  COMMENT='Token buckets limiting the rate of requests (when the rate limits are stored in the database)'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `rate_limit_buckets`;
@@ -0,0 +1,8 @@
+-- +migrate Up
+ALTER TABLE `items`
+  ADD COLUMN `ranking_freeze_duration` TIME DEFAULT NULL
+    COMMENT 'Duration before the end of participations during which the changes of results are hidden in the ranking for non-managers'
+    AFTER `duration`;
+
+-- +migrate Down
+ALTER TABLE `items` DROP COLUMN `ranking_freeze_duration`;
@@ -0,0 +1,22 @@
+-- +migrate Up
+CREATE TABLE `item_revisions` (
+  `item_id` BIGINT(20) NOT NULL,
+  `revision` INT(11) NOT NULL COMMENT 'Number of the revision, starting from 1 for each item',
+  `author_id` BIGINT(20) DEFAULT NULL COMMENT 'User who made the change',
+  `created_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  `snapshot` JSON NOT NULL
+    COMMENT 'Properties of the item, its strings (by language tag) and its children relations (by child item id) after the change',
+  `diff` JSON NOT NULL COMMENT 'Changed values ({"path": {"old": ..., "new": ...}}) compared to the previous revision',
+  PRIMARY KEY (`item_id`, `revision`),
+  CONSTRAINT `fk_item_revisions_item_id_items_id`
+    FOREIGN KEY (`item_id`) REFERENCES `items`(`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_item_revisions_author_id_users_group_id`
+    FOREIGN KEY (`author_id`) REFERENCES `users`(`group_id`) ON DELETE SET NULL
+)
+  COMMENT='Immutable history of changes of items, their strings and their children'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `item_revisions`;
@@ -0,0 +1,53 @@
+-- +migrate Up
+ALTER TABLE `permissions_granted`
+  ADD COLUMN `can_view_from` DATETIME NOT NULL DEFAULT '1000-01-01 00:00:00'
+    COMMENT 'Time from which `can_view` is granted (before this time, the row gives no `can_view` permission)'
+    AFTER `can_enter_until`,
+  ADD COLUMN `can_view_until` DATETIME NOT NULL DEFAULT '9999-12-31 23:59:59'
+    COMMENT 'Time until which `can_view` is granted (after this time, the row gives no `can_view` permission)'
+    AFTER `can_view_from`,
+  ADD COLUMN `can_view_window_is_open` TINYINT(1) NOT NULL DEFAULT 1
+    COMMENT 'Whether NOW() was between `can_view_from` and `can_view_until` when the row was last checked by the permissions windows scheduler'
+    AFTER `can_view_until`;
+
+DROP TRIGGER `after_update_permissions_granted`;
+-- +migrate StatementBegin
+CREATE TRIGGER `after_update_permissions_granted` AFTER UPDATE ON `permissions_granted` FOR EACH ROW BEGIN
+    IF NOT (NEW.`can_view` <=> OLD.`can_view` AND NEW.`can_grant_view` <=> OLD.`can_grant_view` AND
+            NEW.`can_watch` <=> OLD.`can_watch` AND NEW.`can_edit` <=> OLD.`can_edit` AND
+            NEW.`is_owner` <=> OLD.`is_owner` AND NEW.`can_view_from` <=> OLD.`can_view_from` AND
+            NEW.`can_view_until` <=> OLD.`can_view_until` AND
+            NEW.`can_view_window_is_open` <=> OLD.`can_view_window_is_open`) THEN
+      IF @synchronous_propagations_connection_id > 0 THEN
+        REPLACE INTO `permissions_propagate_sync` (`connection_id`, `group_id`, `item_id`, `propagate_to`)
+          VALUE (@synchronous_propagations_connection_id, NEW.`group_id`, NEW.`item_id`, 'self');
+      ELSE
+        REPLACE INTO `permissions_propagate` (`group_id`, `item_id`, `propagate_to`)
+          VALUE (NEW.`group_id`, NEW.`item_id`, 'self');
+      END IF;
+    END IF;
+END
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TRIGGER `after_update_permissions_granted`;
+-- +migrate StatementBegin
+CREATE TRIGGER `after_update_permissions_granted` AFTER UPDATE ON `permissions_granted` FOR EACH ROW BEGIN
+    IF NOT (NEW.`can_view` <=> OLD.`can_view` AND NEW.`can_grant_view` <=> OLD.`can_grant_view` AND
+            NEW.`can_watch` <=> OLD.`can_watch` AND NEW.`can_edit` <=> OLD.`can_edit` AND
+            NEW.`is_owner` <=> OLD.`is_owner`) THEN
+      IF @synchronous_propagations_connection_id > 0 THEN
+        REPLACE INTO `permissions_propagate_sync` (`connection_id`, `group_id`, `item_id`, `propagate_to`)
+          VALUE (@synchronous_propagations_connection_id, NEW.`group_id`, NEW.`item_id`, 'self');
+      ELSE
+        REPLACE INTO `permissions_propagate` (`group_id`, `item_id`, `propagate_to`)
+          VALUE (NEW.`group_id`, NEW.`item_id`, 'self');
+      END IF;
+    END IF;
+END
+-- +migrate StatementEnd
+
+ALTER TABLE `permissions_granted`
+  DROP COLUMN `can_view_window_is_open`,
+  DROP COLUMN `can_view_until`,
+  DROP COLUMN `can_view_from`;
@@ -0,0 +1,16 @@
+-- +migrate Up
+ALTER TABLE `gradings`
+  ADD COLUMN `grader_id` BIGINT(20) DEFAULT NULL
+    COMMENT 'The user who graded the answer manually (NULL if the answer has been graded by a grader)'
+    AFTER `graded_at`,
+  ADD COLUMN `feedback` MEDIUMTEXT DEFAULT NULL
+    COMMENT 'A comment of the user who graded the answer manually'
+    AFTER `grader_id`,
+  ADD CONSTRAINT `fk_gradings_grader_id_users_group_id`
+    FOREIGN KEY (`grader_id`) REFERENCES `users`(`group_id`) ON DELETE SET NULL;
+
+-- +migrate Down
+ALTER TABLE `gradings`
+  DROP FOREIGN KEY `fk_gradings_grader_id_users_group_id`,
+  DROP COLUMN `feedback`,
+  DROP COLUMN `grader_id`;
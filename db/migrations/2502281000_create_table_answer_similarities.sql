@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE `answer_similarities` (
+  `answer1_id` BIGINT(20) NOT NULL COMMENT 'The submission with the smaller id',
+  `answer2_id` BIGINT(20) NOT NULL COMMENT 'The submission with the greater id',
+  `item_id` BIGINT(20) NOT NULL,
+  `similarity` FLOAT NOT NULL COMMENT 'Similarity of the answers (from 0 to 1)',
+  `computed_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`answer1_id`, `answer2_id`),
+  KEY `item_id_similarity` (`item_id`, `similarity`),
+  CONSTRAINT `fk_answer_similarities_answer1_id_answers_id`
+    FOREIGN KEY (`answer1_id`) REFERENCES `answers`(`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_answer_similarities_answer2_id_answers_id`
+    FOREIGN KEY (`answer2_id`) REFERENCES `answers`(`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_answer_similarities_item_id_items_id`
+    FOREIGN KEY (`item_id`) REFERENCES `items`(`id`) ON DELETE CASCADE,
+  CONSTRAINT `cs_answer_similarities_answer1_id_less_than_answer2_id` CHECK (`answer1_id` < `answer2_id`)
+)
+  COMMENT='Similarity of pairs of latest submissions of participants for a task (computed by the similarity analysis)'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `answer_similarities`;
@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE `email_notifications` (
+  `id` BIGINT(20) NOT NULL AUTO_INCREMENT,
+  `user_id` BIGINT(20) NOT NULL COMMENT 'Recipient',
+  `type` ENUM('invitation_created', 'join_request_accepted', 'thread_status_changed', 'membership_expiring') NOT NULL,
+  `payload` JSON NOT NULL COMMENT 'Values used to render the message',
+  `deduplication_key` VARCHAR(255) DEFAULT NULL COMMENT 'If set, a notification with the same key is not enqueued twice',
+  `created_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  `status` ENUM('pending', 'sent', 'failed', 'canceled') NOT NULL DEFAULT 'pending'
+    COMMENT '"failed" means that the sending has been given up after too many attempts, "canceled" means that the user has no email or has opted out before the sending',
+  `attempts_count` INT UNSIGNED NOT NULL DEFAULT 0,
+  `next_attempt_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT 'The sending should not be attempted before this time',
+  `last_attempt_at` DATETIME(3) DEFAULT NULL,
+  `last_error` TEXT DEFAULT NULL COMMENT 'Error of the latest failed attempt',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `deduplication_key` (`deduplication_key`),
+  INDEX `status_next_attempt_at` (`status`, `next_attempt_at`),
+  INDEX `user_id_created_at` (`user_id`, `created_at`),
+  CONSTRAINT `fk_email_notifications_user_id_users_group_id`
+    FOREIGN KEY (`user_id`) REFERENCES `users`(`group_id`) ON DELETE CASCADE
+)
+  COMMENT='Outbox of email notifications sent by the `notification-worker` command'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `email_notifications`;
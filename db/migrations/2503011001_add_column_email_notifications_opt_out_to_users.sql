@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE `users`
+  ADD COLUMN `email_notifications_opt_out`
+    SET('invitation_created', 'join_request_accepted', 'thread_status_changed', 'membership_expiring') NOT NULL DEFAULT ''
+    COMMENT 'Types of email notifications the user does not want to receive'
+    AFTER `notifications_read_at`;
+
+-- +migrate Down
+ALTER TABLE `users` DROP COLUMN `email_notifications_opt_out`;
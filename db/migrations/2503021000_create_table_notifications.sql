@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE `notifications` (
+  `id` BIGINT(20) NOT NULL AUTO_INCREMENT,
+  `user_id` BIGINT(20) NOT NULL COMMENT 'Recipient',
+  `type` ENUM('invitation_created', 'join_request_accepted', 'join_request_refused', 'removed', 'thread_updated', 'answer_graded')
+    NOT NULL,
+  `payload` JSON NOT NULL COMMENT 'Identifiers (and names) of the objects the notification is about',
+  `deduplication_key` VARCHAR(255) DEFAULT NULL
+    COMMENT 'If set, a new notification with the same key for the same user replaces the previous one',
+  `created_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  `read_at` DATETIME(3) DEFAULT NULL COMMENT 'When the user marked the notification as read',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `user_id_deduplication_key` (`user_id`, `deduplication_key`),
+  INDEX `user_id_created_at_id` (`user_id`, `created_at`, `id`),
+  CONSTRAINT `fk_notifications_user_id_users_group_id`
+    FOREIGN KEY (`user_id`) REFERENCES `users`(`group_id`) ON DELETE CASCADE
+)
+  COMMENT='In-app notifications of users listed by GET /current-user/notifications'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate Down
+DROP TABLE `notifications`;
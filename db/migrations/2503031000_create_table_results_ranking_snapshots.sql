@@ -0,0 +1,34 @@
+-- +migrate Up
+CREATE TABLE `results_ranking_snapshots` (
+  `participant_id` BIGINT(20) NOT NULL,
+  `attempt_id` BIGINT(20) NOT NULL,
+  `item_id` BIGINT(20) NOT NULL,
+  `score_computed` FLOAT NOT NULL COMMENT 'Score of the result when the ranking freeze started',
+  `score_obtained_at` DATETIME DEFAULT NULL COMMENT 'Time when the score was obtained (as of the start of the ranking freeze)',
+  PRIMARY KEY (`participant_id`, `attempt_id`, `item_id`),
+  CONSTRAINT `fk_results_ranking_snapshots_to_results`
+    FOREIGN KEY (`participant_id`, `attempt_id`, `item_id`)
+      REFERENCES `results`(`participant_id`, `attempt_id`, `item_id`) ON DELETE CASCADE
+)
+  COMMENT='Results of contest participations as of the start of the ranking freeze (stored on the first change during the freeze)'
+  COLLATE='utf8_general_ci'
+  ENGINE=InnoDB
+;
+
+-- +migrate StatementBegin
+CREATE TRIGGER `after_update_results` AFTER UPDATE ON `results` FOR EACH ROW BEGIN
+  IF NOT (NEW.score_computed <=> OLD.score_computed AND NEW.score_obtained_at <=> OLD.score_obtained_at) THEN
+    INSERT IGNORE INTO `results_ranking_snapshots` (`participant_id`, `attempt_id`, `item_id`, `score_computed`, `score_obtained_at`)
+    SELECT OLD.participant_id, OLD.attempt_id, OLD.item_id, OLD.score_computed, OLD.score_obtained_at
+    FROM `attempts`
+    JOIN `items` ON `items`.`id` = `attempts`.`root_item_id`
+    WHERE `attempts`.`participant_id` = OLD.participant_id AND `attempts`.`id` = OLD.attempt_id AND
+      `attempts`.`root_item_id` = OLD.item_id AND `items`.`ranking_freeze_duration` IS NOT NULL AND
+      NOW() >= `attempts`.`allows_submissions_until` - INTERVAL TIME_TO_SEC(`items`.`ranking_freeze_duration`) SECOND;
+  END IF;
+END;
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TRIGGER `after_update_results`;
+DROP TABLE `results_ranking_snapshots`;
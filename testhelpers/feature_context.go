@@ -42,6 +42,7 @@ func InitializeScenario(s *godog.ScenarioContext) {
 	s.Step(`^the generated group codes are ("[^"]*"(?:\s*,\s*"[^"]*")*)$`, ctx.TheGeneratedGroupCodesAre)
 	s.Step(`^the generated auth key is "([^"]*)"$`, ctx.TheGeneratedAuthKeyIs)
 	s.Step(`^the application config is:$`, ctx.TheApplicationConfigIs)
+	s.Step(`^the exports storage has the artifact "([^"]*)" with the following content:$`, ctx.TheExportsStorageHasTheArtifact)
 	s.Step(`^the context variable "([^"]*)" is "([^"]*)"$`, ctx.TheContextVariableIs)
 
 	ctx.registerFeaturesForGroups(s)
@@ -97,6 +98,7 @@ func InitializeScenario(s *godog.ScenarioContext) {
 	s.Step(`^the response headers? "([^"]*)" should be:`, ctx.TheResponseHeadersShouldBe)
 	s.Step(`^the response should be "([^"]*)"$`, ctx.TheResponseShouldBe)
 	s.Step(`^the response error message should contain "(.*)"$`, ctx.TheResponseErrorMessageShouldContain)
+	s.Step(`^the response body should contain "(.*)"$`, ctx.TheResponseBodyShouldContain)
 
 	s.Step(`^the response should be a JSON array with (\d+) entr(?:ies|y)$`, ctx.ItShouldBeAJSONArrayWithEntries)
 	s.Step(`^the response at ([^ ]+) should be "([^"]*)"$`, ctx.TheResponseAtShouldBeTheValue)
@@ -141,6 +143,17 @@ func InitializeScenario(s *godog.ScenarioContext) {
 		`^the login module "lti_result/send" endpoint for user id "([^"]*)", `+
 			`content id "([^"]*)", score "([^"]*)" returns (\d+) with encoded body:$`,
 		ctx.TheLoginModuleLTIResultSendEndpointForUserIDContentIDScoreReturns)
+	s.Step(`^the OIDC provider "token" endpoint for code "([^"]*)" returns the refresh token "([^"]*)" and an ID token with claims:$`,
+		ctx.TheOIDCProviderTokenEndpointForCodeReturns)
+	s.Step(
+		`^the OIDC provider "token" endpoint for refresh token "([^"]*)" returns the refresh token "([^"]*)" and an ID token with claims:$`,
+		ctx.TheOIDCProviderTokenEndpointForRefreshTokenReturns)
+	s.Step(`^"([^"]+)" is an LTI launch signed by the platform "([^"]+)" with the following claims:$`,
+		ctx.LTILaunchIsSignedByThePlatform)
+	s.Step(`^the LTI platform "([^"]+)" issues the access token "([^"]+)" for publishing scores$`,
+		ctx.TheLTIPlatformIssuesAccessTokenForScores)
+	s.Step(`^the LTI line item "([^"]+)" receives with the access token "([^"]+)" and responds (\d+) to the score:$`,
+		ctx.TheLTILineItemReceivesTheScoreAndResponds)
 
 	s.After(func(contextCtx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
 		tearDownErr := ctx.ScenarioTeardown(sc, err)
@@ -0,0 +1,115 @@
+//go:build !prod
+
+package testhelpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/thingful/httpmock"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/lti"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidctest"
+)
+
+func (ctx *TestContext) ltiPlatform(issuer string) (*lti.PlatformConfig, error) {
+	config, err := auth.GetLTIConfig(ctx.appAuthConfig())
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, fmt.Errorf("LTI is not configured")
+	}
+	platform := config.Platform(issuer, "")
+	if platform == nil {
+		return nil, fmt.Errorf("no LTI platform with issuer %q is configured", issuer)
+	}
+	return platform, nil
+}
+
+// LTILaunchIsSignedByThePlatform generates a launch token of the LTI platform with the given issuer
+// and sets it in a global template variable. The key set of the platform is mocked.
+// The 'iss', 'aud', 'iat', 'exp' and version claims are set by default.
+func (ctx *TestContext) LTILaunchIsSignedByThePlatform(varName, issuer string, claims *godog.DocString) error {
+	platform, err := ctx.ltiPlatform(issuer)
+	if err != nil {
+		return err
+	}
+
+	preprocessedClaims, err := ctx.preprocessString(claims.Content)
+	if err != nil {
+		return err
+	}
+	var launchClaims map[string]interface{}
+	if err = json.Unmarshal([]byte(preprocessedClaims), &launchClaims); err != nil {
+		return err
+	}
+	defaultClaims := map[string]interface{}{
+		"iss":            platform.Issuer,
+		"aud":            platform.ClientID,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		lti.ClaimVersion: "1.3.0",
+	}
+	for claim, value := range defaultClaims {
+		if _, ok := launchClaims[claim]; !ok {
+			launchClaims[claim] = value
+		}
+	}
+
+	// the key set is loaded once by the application (created for each scenario), so its stub is registered only once per scenario
+	if !ctx.ltiPlatformKeysMocked[platform.JWKSURL] {
+		httpmock.Activate(httpmock.WithAllowedHosts("127.0.0.1"))
+		httpmock.RegisterStubRequests(
+			httpmock.NewStubRequest("GET", platform.JWKSURL, httpmock.NewStringResponder(200, oidctest.JWKS())))
+		ctx.ltiPlatformKeysMocked[platform.JWKSURL] = true
+	}
+
+	ctx.templateSet.AddGlobal(varName, oidctest.SignIDToken(launchClaims))
+	return nil
+}
+
+// TheLTIPlatformIssuesAccessTokenForScores mocks the token endpoint of the LTI platform with the given issuer
+// so that it issues the given access token for publishing scores.
+func (ctx *TestContext) TheLTIPlatformIssuesAccessTokenForScores(issuer, accessToken string) error {
+	platform, err := ctx.ltiPlatform(issuer)
+	if err != nil {
+		return err
+	}
+
+	httpmock.Activate(httpmock.WithAllowedHosts("127.0.0.1"))
+	httpmock.RegisterStubRequests(httpmock.NewStubRequest("POST", platform.AccessTokenURL,
+		httpmock.NewStringResponder(200, fmt.Sprintf(
+			`{"access_token": %q, "token_type": "Bearer", "expires_in": 3600, "scope": %q}`, accessToken, lti.ScopeScore))))
+	return nil
+}
+
+// TheLTILineItemReceivesTheScoreAndResponds mocks the scores service of the given LTI line item
+// expecting the given score (as JSON) authorized with the given access token.
+func (ctx *TestContext) TheLTILineItemReceivesTheScoreAndResponds(
+	lineItemURL, accessToken string, statusCode int, score *godog.DocString,
+) error {
+	preprocessedScore, err := ctx.preprocessString(score.Content)
+	if err != nil {
+		return err
+	}
+	var compactedScore bytes.Buffer
+	if err = json.Compact(&compactedScore, []byte(preprocessedScore)); err != nil {
+		return err
+	}
+
+	httpmock.Activate(httpmock.WithAllowedHosts("127.0.0.1"))
+	httpmock.RegisterStubRequests(httpmock.NewStubRequest("POST", lineItemURL+"/scores",
+		httpmock.NewStringResponder(statusCode, ""),
+		httpmock.WithHeader(&http.Header{
+			"Content-Type":  []string{"application/vnd.ims.lis.v1.score+json"},
+			"Authorization": []string{"Bearer " + accessToken},
+		}),
+		httpmock.WithBody(&compactedScore)))
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/France-ioi/AlgoreaBackend/v2/app"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/api/groups"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/auth"
+	"github.com/France-ioi/AlgoreaBackend/v2/app/exports"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/service"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/token"
 	"github.com/France-ioi/AlgoreaBackend/v2/app/tokentest"
@@ -194,7 +195,10 @@ func (ctx *TestContext) TheApplicationConfigIs(yamlConfig *godog.DocString) erro
 		return err
 	}
 
-	// Only 'domain' and 'auth' changes are currently supported
+	// Only 'domain', 'auth' and 'server' changes are currently supported
+	if config.IsSet("server") {
+		ctx.application.ReplaceServerConfig(config)
+	}
 	if config.IsSet("auth") {
 		ctx.application.ReplaceAuthConfig(config)
 	}
@@ -205,6 +209,24 @@ func (ctx *TestContext) TheApplicationConfigIs(yamlConfig *godog.DocString) erro
 	return nil
 }
 
+// TheExportsStorageHasTheArtifact stores an artifact with the given key and content
+// in the exports storage configured in the app configuration.
+func (ctx *TestContext) TheExportsStorageHasTheArtifact(key string, content *godog.DocString) error {
+	storage, err := exports.NewStorage(app.ServerConfig(ctx.application.Config))
+	if err != nil {
+		return err
+	}
+	writer, err := storage.Create(context.Background(), key)
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write([]byte(content.Content)); err != nil {
+		_ = writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
 // TheContextVariableIs sets a context variable in the request http.Request as the provided value.
 // Can be retrieved from the request with r.Context().Value(service.APIServiceContextVariableName("variableName")).
 func (ctx *TestContext) TheContextVariableIs(variableName, value string) error {
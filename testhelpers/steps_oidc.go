@@ -0,0 +1,97 @@
+//go:build !prod
+
+package testhelpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/thingful/httpmock"
+
+	"github.com/France-ioi/AlgoreaBackend/v2/app/oidctest"
+)
+
+// TheOIDCProviderTokenEndpointForCodeReturns mocks the token endpoint of the OpenID Connect identity provider
+// (together with its discovery document and its keys) called with the provided code.
+// The provider responds with the given refresh token and an ID token containing the given claims.
+func (ctx *TestContext) TheOIDCProviderTokenEndpointForCodeReturns(
+	code, refreshToken string,
+	claims *godog.DocString,
+) error {
+	preprocessedCode, err := ctx.preprocessString(code)
+	if err != nil {
+		return err
+	}
+	return ctx.mockOIDCProviderTokenEndpoint(url.Values{
+		"grant_type": {"authorization_code"},
+		"code":       {preprocessedCode},
+	}, refreshToken, claims)
+}
+
+// TheOIDCProviderTokenEndpointForRefreshTokenReturns mocks the token endpoint of the OpenID Connect identity provider
+// (together with its discovery document and its keys) called with the provided refresh token.
+// The provider responds with the given new refresh token and an ID token containing the given claims.
+func (ctx *TestContext) TheOIDCProviderTokenEndpointForRefreshTokenReturns(
+	refreshToken, newRefreshToken string,
+	claims *godog.DocString,
+) error {
+	preprocessedRefreshToken, err := ctx.preprocessString(refreshToken)
+	if err != nil {
+		return err
+	}
+	return ctx.mockOIDCProviderTokenEndpoint(url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {preprocessedRefreshToken},
+	}, newRefreshToken, claims)
+}
+
+func (ctx *TestContext) mockOIDCProviderTokenEndpoint(
+	params url.Values, refreshToken string, claims *godog.DocString,
+) error {
+	httpmock.Activate(httpmock.WithAllowedHosts("127.0.0.1"))
+
+	preprocessedRefreshToken, err := ctx.preprocessString(refreshToken)
+	if err != nil {
+		return err
+	}
+	preprocessedClaims, err := ctx.preprocessString(claims.Content)
+	if err != nil {
+		return err
+	}
+	var idTokenClaims map[string]interface{}
+	if err = json.Unmarshal([]byte(preprocessedClaims), &idTokenClaims); err != nil {
+		return err
+	}
+
+	issuer := ctx.appAuthConfig().GetString("oidc.issuer")
+	defaultClaims := map[string]interface{}{
+		"iss": issuer,
+		"aud": ctx.appAuthConfig().GetString("oidc.clientID"),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for claim, value := range defaultClaims {
+		if _, ok := idTokenClaims[claim]; !ok {
+			idTokenClaims[claim] = value
+		}
+	}
+
+	// the discovery document and the keys are loaded once by the application (created for each scenario),
+	// so their stubs are registered only once per scenario
+	if !ctx.oidcProviderMocked {
+		httpmock.RegisterStubRequests(
+			httpmock.NewStubRequest("GET", issuer+"/.well-known/openid-configuration",
+				httpmock.NewStringResponder(200, oidctest.DiscoveryDocument(issuer))),
+			httpmock.NewStubRequest("GET", issuer+"/jwks", httpmock.NewStringResponder(200, oidctest.JWKS())),
+		)
+		ctx.oidcProviderMocked = true
+	}
+
+	responder := httpmock.NewStringResponder(200,
+		oidctest.TokenResponse("oidcaccesstoken", preprocessedRefreshToken, 3600, idTokenClaims))
+	httpmock.RegisterStubRequests(httpmock.NewStubRequest("POST", issuer+"/token", responder,
+		httpmock.WithBody(bytes.NewBufferString(params.Encode()))))
+	return nil
+}
@@ -68,16 +68,17 @@ func (ctx *TestContext) iSendrequestGeneric(method, path, reqBody string) error
 	})
 	defer database.SetOnForcefulRetryOfTransactionHook(func() {})
 
-	var headers map[string][]string
+	headers := make(map[string][]string, len(ctx.requestHeaders)+2)
+	for key := range ctx.requestHeaders {
+		headers[key] = make([]string, len(ctx.requestHeaders[key]))
+		copy(headers[key], ctx.requestHeaders[key])
+	}
 	if ctx.userID != 0 {
-		headers = make(map[string][]string, len(ctx.requestHeaders)+1)
-		for key := range ctx.requestHeaders {
-			headers[key] = make([]string, len(ctx.requestHeaders[key]))
-			copy(headers[key], ctx.requestHeaders[key])
-		}
 		headers["Authorization"] = []string{"Bearer " + testAccessToken}
-	} else {
-		headers = ctx.requestHeaders
+	}
+	// error messages are expected in English unless the scenario negotiates another language explicitly
+	if _, ok := headers["Accept-Language"]; !ok {
+		headers["Accept-Language"] = []string{"en"}
 	}
 
 	reqBody, err = ctx.preprocessString(reqBody)
@@ -492,6 +492,14 @@ func (ctx *TestContext) TheResponseErrorMessageShouldContain(s string) (err erro
 	return nil
 }
 
+// TheResponseBodyShouldContain checks that the response body contains the given string.
+func (ctx *TestContext) TheResponseBodyShouldContain(s string) error {
+	if !strings.Contains(ctx.lastResponseBody, s) {
+		return fmt.Errorf("cannot find expected `%s` in the response body: `%s`", s, ctx.lastResponseBody)
+	}
+	return nil
+}
+
 // TheResponseShouldBe checks that the response status of the response is of the given kind.
 func (ctx *TestContext) TheResponseShouldBe(kind string) error {
 	var expectedCode int
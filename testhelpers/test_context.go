@@ -52,6 +52,8 @@ type TestContext struct {
 	previousRandSource              interface{}
 	previousGeneratedGroupCodeIndex int
 	generatedGroupCodeIndex         int
+	oidcProviderMocked              bool
+	ltiPlatformKeysMocked           map[string]bool
 }
 
 const (
@@ -75,6 +77,8 @@ func (ctx *TestContext) SetupTestContext(sc *godog.Scenario) {
 	ctx.dbTableData = make(map[string]*godog.Table)
 	ctx.templateSet = ctx.constructTemplateSet()
 	ctx.needPopulateDatabase = false
+	ctx.oidcProviderMocked = false
+	ctx.ltiPlatformKeysMocked = map[string]bool{}
 
 	ctx.initReferences(sc)
 